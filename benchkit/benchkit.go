@@ -0,0 +1,19 @@
+// Package benchkit - Tüm lab'ların (mongo-perf-lab, io-vs-cpu-demo, c_go_nodejs_c#) ortak ölçüm kütüphanesi
+// Her deney süreyi, belleği, gecikme dağılımını ve ilerlemeyi aynı şekilde ölçer ve
+// aynı metrik tanımlarıyla raporlar. Böylece "p99" veya "ops/sn" her lab'da aynı anlama gelir.
+//
+// İçerik:
+//   - timing.go: Percentile, Summary (min/ortalama/p50/p95/p99/max)
+//   - stats.go: Tekrarlanan turların dağılımı (ortalama/medyan/p95/std, Describe)
+//   - memory.go: Bellek ölçümü (allocation, heap, GC) ve tepe heap örnekleyici
+//   - histogram.go: Sabit bellekli gecikme histogramı (milyonlarca ölçüm için)
+//   - progress.go: Uzun işlemler için ilerleme/ETA çıktısı
+//   - report.go: Ortak sonuç formatı (Result) ve JSON Lines çıktısı
+//   - hostinfo.go: Makine parmak izi (CPU, RAM, disk, OS, Go sürümü) - her sonuca eklenir
+//   - timeline.go: Aralık başına throughput (zaman çizelgesi)
+//
+// Sadece standart kütüphaneyi kullanır; lab'lar go.mod'da replace ile bağlar:
+//
+//	require benchkit v0.0.0
+//	replace benchkit => ../../benchkit
+package benchkit
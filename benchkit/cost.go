@@ -0,0 +1,137 @@
+package benchkit
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// CostModel - Ölçülen metrikleri paraya ve enerjiye çeviren basit maliyet modeli
+// "read_v3, read_v1'den 400 ms hızlı" yerine "milyon sorgu başına $0.80 yerine $0.12" diyebilmek için.
+// Bu bir TAHMİNDİR: Fiyatlar liste fiyatlarıdır, bölge/sözleşmeye göre değişir; önemli olan
+// aynı model altında versiyonların birbirine oranıdır.
+type CostModel struct {
+	Name              string  `yaml:"name" json:"name"`
+	InstanceHourlyUSD float64 `yaml:"instanceHourlyUSD" json:"instanceHourlyUSD"` // Veritabanı instance'ının saatlik ücreti
+	VCPUs             int     `yaml:"vcpus" json:"vcpus"`                         // Instance'ın vCPU sayısı
+	EgressPerGBUSD    float64 `yaml:"egressPerGBUSD" json:"egressPerGBUSD"`       // Sunucudan çıkan veri ücreti ($/GB)
+	WattsPerCore      float64 `yaml:"wattsPerCore" json:"wattsPerCore"`           // Meşgul bir çekirdeğin ortalama güç tüketimi
+}
+
+// CostPresets - Hazır modeller (AWS üzerindeki Atlas liste fiyatlarına yakın değerler)
+// Güncel fiyatlar için Atlas fiyatlandırma sayfasına bakın; manifest'te alan bazında ezilebilir
+var CostPresets = map[string]CostModel{
+	"atlas-m10": {Name: "atlas-m10", InstanceHourlyUSD: 0.08, VCPUs: 2, EgressPerGBUSD: 0.09, WattsPerCore: 10},
+	"atlas-m30": {Name: "atlas-m30", InstanceHourlyUSD: 0.54, VCPUs: 2, EgressPerGBUSD: 0.09, WattsPerCore: 10},
+	"atlas-m40": {Name: "atlas-m40", InstanceHourlyUSD: 1.04, VCPUs: 4, EgressPerGBUSD: 0.09, WattsPerCore: 10},
+	"atlas-m50": {Name: "atlas-m50", InstanceHourlyUSD: 2.00, VCPUs: 8, EgressPerGBUSD: 0.09, WattsPerCore: 10},
+	"local":     {Name: "local", InstanceHourlyUSD: 0, VCPUs: 1, EgressPerGBUSD: 0, WattsPerCore: 10},
+}
+
+// ResolveCostModel - Preset'i alır, override'daki sıfır olmayan alanlarla ezer
+// preset boşsa sadece override kullanılır
+func ResolveCostModel(preset string, override CostModel) (CostModel, error) {
+	var m CostModel
+	if preset != "" {
+		p, ok := CostPresets[preset]
+		if !ok {
+			names := make([]string, 0, len(CostPresets))
+			for name := range CostPresets {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			return CostModel{}, fmt.Errorf("bilinmeyen maliyet preset'i %q (%s)", preset, strings.Join(names, ", "))
+		}
+		m = p
+	}
+	if override.Name != "" {
+		m.Name = override.Name
+	}
+	if override.InstanceHourlyUSD > 0 {
+		m.InstanceHourlyUSD = override.InstanceHourlyUSD
+	}
+	if override.VCPUs > 0 {
+		m.VCPUs = override.VCPUs
+	}
+	if override.EgressPerGBUSD > 0 {
+		m.EgressPerGBUSD = override.EgressPerGBUSD
+	}
+	if override.WattsPerCore > 0 {
+		m.WattsPerCore = override.WattsPerCore
+	}
+	if m.VCPUs <= 0 {
+		m.VCPUs = 1
+	}
+	if m.Name == "" {
+		m.Name = "custom"
+	}
+	return m, nil
+}
+
+// CostInputs - Maliyet hesabına giren ölçümler
+type CostInputs struct {
+	Ops        int64         // İşlem sayısı (okunan doküman, istek...)
+	Duration   time.Duration // Duvar saati süresi
+	ClientCPU  time.Duration // Client process'in harcadığı CPU (CPUTime farkı)
+	ServerTime time.Duration // Sunucunun işle meşgul olduğu süre (ör: komut süreleri toplamı - üst sınır)
+	Bytes      int64         // Sunucudan client'a taşınan veri
+}
+
+// CostEstimate - Modelin ürettiği tahmin
+type CostEstimate struct {
+	Model                 string  `json:"model"`
+	Ops                   int64   `json:"ops"`
+	ClientCPUSeconds      float64 `json:"clientCpuSeconds"`
+	ServerSeconds         float64 `json:"serverSeconds"`
+	BytesTransferred      int64   `json:"bytesTransferred"`
+	BytesPerOp            float64 `json:"bytesPerOp"`
+	ServerUSD             float64 `json:"serverUSD"`        // Sunucu meşguliyetinin instance ücretindeki payı
+	EgressUSD             float64 `json:"egressUSD"`        // Taşınan verinin ücreti
+	DedicatedUSD          float64 `json:"dedicatedUSD"`     // Instance sadece bu işe ayrılsaydı duvar saati boyunca ödenecek ücret
+	PerMillionOpsUSD      float64 `json:"perMillionOpsUSD"` // (ServerUSD + EgressUSD) / ops × 1M
+	EnergyWh              float64 `json:"energyWh"`         // (client CPU + sunucu süresi) × watt/çekirdek
+	EnergyPerMillionOpsWh float64 `json:"energyPerMillionOpsWh"`
+}
+
+// Estimate - Ölçümleri maliyet ve enerji tahminine çevirir
+// Sunucu süresinin tek çekirdek kullandığı varsayılır: payı = süre / (vCPU × 1 saat) × saatlik ücret
+func (m CostModel) Estimate(in CostInputs) CostEstimate {
+	vcpus := m.VCPUs
+	if vcpus <= 0 {
+		vcpus = 1
+	}
+	e := CostEstimate{
+		Model:            m.Name,
+		Ops:              in.Ops,
+		ClientCPUSeconds: in.ClientCPU.Seconds(),
+		ServerSeconds:    in.ServerTime.Seconds(),
+		BytesTransferred: in.Bytes,
+	}
+	e.ServerUSD = e.ServerSeconds / (float64(vcpus) * 3600) * m.InstanceHourlyUSD
+	e.EgressUSD = float64(in.Bytes) / 1e9 * m.EgressPerGBUSD
+	e.DedicatedUSD = in.Duration.Hours() * m.InstanceHourlyUSD
+	e.EnergyWh = (e.ClientCPUSeconds + e.ServerSeconds) * m.WattsPerCore / 3600
+
+	if in.Ops > 0 {
+		perOp := 1e6 / float64(in.Ops)
+		e.BytesPerOp = float64(in.Bytes) / float64(in.Ops)
+		e.PerMillionOpsUSD = (e.ServerUSD + e.EgressUSD) * perOp
+		e.EnergyPerMillionOpsWh = e.EnergyWh * perOp
+	}
+	return e
+}
+
+// WriteText - Tahmini "MALİYET TAHMİNİ" bölümü olarak yazar
+func (e CostEstimate) WriteText(printf Printf) {
+	printf("\n=== MALİYET TAHMİNİ (%s) ===\n", e.Model)
+	printf("  🧮 Client CPU: %.3f CPU-sn, sunucu: %.3f sn\n", e.ClientCPUSeconds, e.ServerSeconds)
+	printf("  📡 Taşınan veri: %.2f MB (%.0f byte/işlem)\n", float64(e.BytesTransferred)/(1024*1024), e.BytesPerOp)
+	printf("  💰 Bu çalıştırma: $%.6f (sunucu $%.6f + veri $%.6f), ayrılmış instance: $%.6f\n",
+		e.ServerUSD+e.EgressUSD, e.ServerUSD, e.EgressUSD, e.DedicatedUSD)
+	if e.Ops > 0 {
+		printf("  💵 Milyon işlem başına: $%.4f\n", e.PerMillionOpsUSD)
+		printf("  ⚡ Enerji: %.4f Wh (milyon işlem başına %.4f Wh)\n", e.EnergyWh, e.EnergyPerMillionOpsWh)
+	}
+	printf("  ℹ️  Liste fiyatlarıyla kaba tahmin - mutlak değerden çok versiyonlar arası oran anlamlıdır\n")
+}
@@ -0,0 +1,10 @@
+//go:build !unix
+
+package benchkit
+
+import "time"
+
+// CPUTime - Bu platformda desteklenmiyor, her zaman 0 döner (CPU-saniye raporlanmaz)
+func CPUTime() time.Duration {
+	return 0
+}
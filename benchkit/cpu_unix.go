@@ -0,0 +1,18 @@
+//go:build unix
+
+package benchkit
+
+import (
+	"syscall"
+	"time"
+)
+
+// CPUTime - Process'in şu ana kadar harcadığı toplam CPU süresi (user + system, tüm thread'ler)
+// Ölçüm başı ve sonu arasındaki fark "CPU-saniye"dir: 4 çekirdeği 1 sn meşgul eden iş 4 CPU-sn harcar
+func CPUTime() time.Duration {
+	var ru syscall.Rusage
+	if err := syscall.Getrusage(syscall.RUSAGE_SELF, &ru); err != nil {
+		return 0
+	}
+	return time.Duration(ru.Utime.Nano() + ru.Stime.Nano())
+}
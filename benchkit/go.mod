@@ -0,0 +1,3 @@
+module benchkit
+
+go 1.22
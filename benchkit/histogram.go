@@ -0,0 +1,161 @@
+package benchkit
+
+import (
+	"math/bits"
+	"time"
+)
+
+// Histogram - Sabit bellekli, log-lineer gecikme histogramı
+// Milyonlarca ölçümü []time.Duration'da tutup sıralamak yerine değerler kovalara (bucket) sayılır.
+// Her ikinin kuvveti aralığı 32 eşit kovaya bölünür: göreli hata %3'ün altında kalır,
+// bellek ölçüm sayısından bağımsızdır (en fazla ~1900 kova).
+//
+// Eşzamanlı kullanım için güvenli değildir: Her goroutine kendi histogramını tutar,
+// sonunda Merge ile birleştirilir (kilit çekişmesi ölçülen gecikmeye karışmasın).
+type Histogram struct {
+	counts []uint64
+	count  int64
+	sum    time.Duration
+	min    time.Duration
+	max    time.Duration
+}
+
+// subBucketBits - Her ikinin kuvveti aralığındaki kova sayısı = 2^subBucketBits
+const (
+	subBucketBits  = 5
+	subBucketCount = 1 << subBucketBits
+)
+
+// NewHistogram - Boş histogram oluşturur
+func NewHistogram() *Histogram {
+	return &Histogram{}
+}
+
+// bucketIndex - Değerin (ns) kova indeksi
+// 0-31 ns birebir, sonrası: üst bit konumu (üs) × 32 + sonraki 5 bit (mantis)
+func bucketIndex(v int64) int {
+	if v < subBucketCount {
+		return int(v)
+	}
+	shift := bits.Len64(uint64(v)) - subBucketBits - 1
+	return subBucketCount + shift*subBucketCount + int(v>>uint(shift)) - subBucketCount
+}
+
+// bucketUpper - Kovadaki en büyük değer (ns)
+func bucketUpper(idx int) int64 {
+	if idx < subBucketCount {
+		return int64(idx)
+	}
+	shift := (idx - subBucketCount) / subBucketCount
+	mantissa := int64((idx-subBucketCount)%subBucketCount + subBucketCount)
+	return (mantissa+1)<<uint(shift) - 1
+}
+
+// Record - Bir ölçüm ekler (negatif süreler 0 sayılır)
+func (h *Histogram) Record(d time.Duration) {
+	if d < 0 {
+		d = 0
+	}
+	idx := bucketIndex(int64(d))
+	if idx >= len(h.counts) {
+		grown := make([]uint64, idx+1)
+		copy(grown, h.counts)
+		h.counts = grown
+	}
+	h.counts[idx]++
+
+	if h.count == 0 || d < h.min {
+		h.min = d
+	}
+	if d > h.max {
+		h.max = d
+	}
+	h.count++
+	h.sum += d
+}
+
+// Merge - Başka bir histogramın ölçümlerini ekler
+func (h *Histogram) Merge(other *Histogram) {
+	if other == nil || other.count == 0 {
+		return
+	}
+	if len(other.counts) > len(h.counts) {
+		grown := make([]uint64, len(other.counts))
+		copy(grown, h.counts)
+		h.counts = grown
+	}
+	for i, c := range other.counts {
+		h.counts[i] += c
+	}
+	if h.count == 0 || other.min < h.min {
+		h.min = other.min
+	}
+	if other.max > h.max {
+		h.max = other.max
+	}
+	h.count += other.count
+	h.sum += other.sum
+}
+
+// Count - Toplam ölçüm sayısı
+func (h *Histogram) Count() int64 {
+	return h.count
+}
+
+// Percentile - Yüzdelik değeri döndürür (Percentile ile aynı nearest-rank tanımı)
+// Sonuç, değerin düştüğü kovanın üst sınırıdır; gerçek max'ı aşmaz
+func (h *Histogram) Percentile(p float64) time.Duration {
+	if h.count == 0 {
+		return 0
+	}
+	// Sıralı listedeki indeks (0 tabanlı) - Percentile fonksiyonuyla aynı
+	rank := int64(float64(h.count-1)*p/100) + 1
+	var seen int64
+	for i, c := range h.counts {
+		seen += int64(c)
+		if seen >= rank {
+			v := time.Duration(bucketUpper(i))
+			if v > h.max {
+				v = h.max
+			}
+			if v < h.min {
+				v = h.min
+			}
+			return v
+		}
+	}
+	return h.max
+}
+
+// Summary - Histogramın özeti (Summarize ile aynı alanlar)
+func (h *Histogram) Summary() Summary {
+	if h.count == 0 {
+		return Summary{}
+	}
+	return Summary{
+		Count: h.count,
+		Min:   h.min,
+		Mean:  h.sum / time.Duration(h.count),
+		P50:   h.Percentile(50),
+		P95:   h.Percentile(95),
+		P99:   h.Percentile(99),
+		Max:   h.max,
+	}
+}
+
+// Bucket - Dolu bir kova (CDF ve grafik çizimi için)
+type Bucket struct {
+	Upper time.Duration // Kovadaki en büyük değer
+	Count uint64
+}
+
+// Buckets - Dolu kovaları küçükten büyüğe döndürür
+func (h *Histogram) Buckets() []Bucket {
+	var out []Bucket
+	for i, c := range h.counts {
+		if c > 0 {
+			out = append(out, Bucket{Upper: time.Duration(bucketUpper(i)), Count: c})
+		}
+	}
+	return out
+}
@@ -0,0 +1,220 @@
+package benchkit
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// HostInfo - Ölçümün yapıldığı makinenin donanım/yazılım parmak izi
+// Farklı makinelerde alınmış iki sonuç, bu bilgi olmadan karşılaştırılamaz:
+// "read_v3 2 kat hızlandı" aslında "laptop yerine sunucuda çalıştı" olabilir.
+// Okunamayan alanlar boş kalır (ör: VM'lerde frekans yöneticisi yoktur).
+type HostInfo struct {
+	Hostname  string  `json:"hostname"`
+	OS        string  `json:"os"`               // Dağıtım/sürüm (ör: "Ubuntu 22.04.4 LTS")
+	Kernel    string  `json:"kernel,omitempty"` // Çekirdek sürümü
+	Arch      string  `json:"arch"`
+	CPUModel  string  `json:"cpuModel"`
+	Cores     int     `json:"cores"`                // Mantıksal çekirdek sayısı (runtime.NumCPU)
+	CPUMHz    float64 `json:"cpuMHz,omitempty"`     // Maksimum (yoksa anlık) frekans
+	Governor  string  `json:"governor,omitempty"`   // Frekans yöneticisi: powersave, performance...
+	MemoryMB  int64   `json:"memoryMB"`             // Toplam RAM
+	Disks     []Disk  `json:"disks,omitempty"`      // Fiziksel/sanal blok cihazları
+	GoVersion string  `json:"goVersion"`            // Ölçümü yapan binary'nin Go sürümü
+	GoMaxProc int     `json:"gomaxprocs,omitempty"` // Çalışma anındaki GOMAXPROCS
+}
+
+// Disk - Blok cihazı ve tipi
+type Disk struct {
+	Name string `json:"name"`
+	Type string `json:"type"` // nvme, ssd, hdd, virtual
+}
+
+var (
+	hostOnce sync.Once
+	hostInfo HostInfo
+)
+
+// CollectHostInfo - Makine bilgisini toplar (process başına bir kez, sonra önbellekten)
+func CollectHostInfo() HostInfo {
+	hostOnce.Do(func() {
+		hostInfo = collectHostInfo()
+	})
+	return hostInfo
+}
+
+func collectHostInfo() HostInfo {
+	h := HostInfo{
+		OS:        runtime.GOOS,
+		Arch:      runtime.GOARCH,
+		Cores:     runtime.NumCPU(),
+		GoVersion: runtime.Version(),
+		GoMaxProc: runtime.GOMAXPROCS(0),
+	}
+	h.Hostname, _ = os.Hostname()
+
+	switch runtime.GOOS {
+	case "linux":
+		collectLinux(&h)
+	case "darwin":
+		collectDarwin(&h)
+	}
+	return h
+}
+
+func collectLinux(h *HostInfo) {
+	if name := osReleaseName(); name != "" {
+		h.OS = name
+	}
+	h.Kernel = readTrimmed("/proc/sys/kernel/osrelease")
+
+	// /proc/cpuinfo: Tüm çekirdekler aynı modeli raporlar, ilk kayıt yeterli
+	if f, err := os.Open("/proc/cpuinfo"); err == nil {
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			key, value, ok := strings.Cut(scanner.Text(), ":")
+			if !ok {
+				continue
+			}
+			key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+			switch {
+			case key == "model name" && h.CPUModel == "":
+				h.CPUModel = value
+			case key == "cpu MHz" && h.CPUMHz == 0:
+				h.CPUMHz, _ = strconv.ParseFloat(value, 64)
+			}
+		}
+		f.Close()
+	}
+
+	// cpufreq varsa anlık frekans yerine maksimum frekansı kullan (anlık değer yüke göre oynar)
+	cpufreq := "/sys/devices/system/cpu/cpu0/cpufreq"
+	if khz, err := strconv.ParseFloat(readTrimmed(filepath.Join(cpufreq, "cpuinfo_max_freq")), 64); err == nil && khz > 0 {
+		h.CPUMHz = khz / 1000
+	}
+	h.Governor = readTrimmed(filepath.Join(cpufreq, "scaling_governor"))
+
+	if f, err := os.Open("/proc/meminfo"); err == nil {
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			fields := strings.Fields(scanner.Text())
+			if len(fields) >= 2 && fields[0] == "MemTotal:" {
+				kb, _ := strconv.ParseInt(fields[1], 10, 64)
+				h.MemoryMB = kb / 1024
+				break
+			}
+		}
+		f.Close()
+	}
+
+	// Blok cihazları: loop, ram, zram, device-mapper ve optik sürücüler sayılmaz
+	entries, _ := os.ReadDir("/sys/block")
+	for _, e := range entries {
+		name := e.Name()
+		if hasAnyPrefix(name, "loop", "ram", "zram", "dm-", "sr", "md") {
+			continue
+		}
+		disk := Disk{Name: name}
+		switch {
+		case strings.HasPrefix(name, "nvme"):
+			disk.Type = "nvme"
+		case hasAnyPrefix(name, "vd", "xvd"):
+			disk.Type = "virtual"
+		case readTrimmed(filepath.Join("/sys/block", name, "queue/rotational")) == "0":
+			disk.Type = "ssd"
+		default:
+			disk.Type = "hdd"
+		}
+		h.Disks = append(h.Disks, disk)
+	}
+}
+
+func collectDarwin(h *HostInfo) {
+	if v := sysctl("kern.osproductversion"); v != "" {
+		h.OS = "macOS " + v
+	}
+	h.Kernel = sysctl("kern.osrelease")
+	h.CPUModel = sysctl("machdep.cpu.brand_string")
+	if hz, err := strconv.ParseFloat(sysctl("hw.cpufrequency_max"), 64); err == nil {
+		h.CPUMHz = hz / 1e6 // Apple Silicon'da yok
+	}
+	if bytes, err := strconv.ParseInt(sysctl("hw.memsize"), 10, 64); err == nil {
+		h.MemoryMB = bytes / (1024 * 1024)
+	}
+}
+
+// String - Tek satırlık özet (text raporların başlığı için)
+func (h HostInfo) String() string {
+	cpu := h.CPUModel
+	if cpu == "" {
+		cpu = "bilinmeyen CPU"
+	}
+	cpu += fmt.Sprintf(" (%d çekirdek", h.Cores)
+	if h.CPUMHz > 0 {
+		cpu += fmt.Sprintf(", %.0f MHz", h.CPUMHz)
+	}
+	if h.Governor != "" {
+		cpu += ", " + h.Governor
+	}
+	cpu += ")"
+
+	parts := []string{cpu, fmt.Sprintf("%.1f GB RAM", float64(h.MemoryMB)/1024)}
+	if len(h.Disks) > 0 {
+		var disks []string
+		for _, d := range h.Disks {
+			disks = append(disks, d.Name+":"+d.Type)
+		}
+		parts = append(parts, strings.Join(disks, ","))
+	}
+	osName := h.OS
+	if h.Kernel != "" {
+		osName += " (" + h.Kernel + ")"
+	}
+	parts = append(parts, osName+" "+h.Arch, h.GoVersion)
+	return strings.Join(parts, " · ")
+}
+
+func osReleaseName() string {
+	data, err := os.ReadFile("/etc/os-release")
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if value, ok := strings.CutPrefix(line, "PRETTY_NAME="); ok {
+			return strings.Trim(value, `"`)
+		}
+	}
+	return ""
+}
+
+func readTrimmed(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+func sysctl(name string) string {
+	out, err := exec.Command("sysctl", "-n", name).Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func hasAnyPrefix(s string, prefixes ...string) bool {
+	for _, p := range prefixes {
+		if strings.HasPrefix(s, p) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,140 @@
+package benchkit
+
+import (
+	"runtime"
+	"runtime/metrics"
+	"sync"
+	"time"
+)
+
+// MemSnapshot - runtime.MemStats'ın ölçüm için gerekli kısmı
+type MemSnapshot struct {
+	TotalAlloc uint64        // Program başından beri ayrılan toplam byte (sadece artar)
+	HeapAlloc  uint64        // Şu anda heap'te canlı + henüz toplanmamış byte
+	NumGC      uint32        // Tamamlanan GC döngüsü sayısı
+	PauseTotal time.Duration // Toplam GC duraklama süresi
+}
+
+// ReadMem - Anlık bellek durumunu okur
+// gc true ise önce runtime.GC() çağrılır: ölçüm başında önceki işlerin çöpü temizlenir
+func ReadMem(gc bool) MemSnapshot {
+	if gc {
+		runtime.GC()
+	}
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	return MemSnapshot{
+		TotalAlloc: m.TotalAlloc,
+		HeapAlloc:  m.HeapAlloc,
+		NumGC:      m.NumGC,
+		PauseTotal: time.Duration(m.PauseTotalNs),
+	}
+}
+
+// MemUsage - İki snapshot arasındaki bellek kullanımı
+// Allocated, TotalAlloc farkıdır: Ölçüm sırasında GC çalışsa bile negatif olmaz
+// (Alloc farkı GC sonrası eksiye düşer ve uint64'te taşar)
+type MemUsage struct {
+	Allocated uint64 `json:"allocatedBytes"` // Ölçüm boyunca ayrılan toplam byte
+	HeapDelta int64  `json:"heapDeltaBytes"` // Heap'in net değişimi (negatif olabilir)
+	PeakHeap  uint64 `json:"peakHeapBytes,omitempty"`
+	// PeakGrowth - Heap zirvesinin başlangıca göre büyümesi (PeakHeap - başlangıç HeapAlloc, hep >= 0)
+	PeakGrowth int64         `json:"peakGrowthBytes,omitempty"`
+	NumGC      uint32        `json:"numGC"`
+	GCPause    time.Duration `json:"gcPauseNs"`
+}
+
+// MemDelta - before → after arasındaki kullanımı hesaplar
+func MemDelta(before, after MemSnapshot) MemUsage {
+	return MemUsage{
+		Allocated: after.TotalAlloc - before.TotalAlloc,
+		HeapDelta: int64(after.HeapAlloc) - int64(before.HeapAlloc),
+		NumGC:     after.NumGC - before.NumGC,
+		GCPause:   after.PauseTotal - before.PauseTotal,
+	}
+}
+
+// AllocatedMB - Ayrılan toplam belleği MB olarak döndürür
+func (u MemUsage) AllocatedMB() float64 {
+	return float64(u.Allocated) / (1024 * 1024)
+}
+
+// PeakSampler - Ölçüm boyunca heap'in tepe değerini arka planda örnekler
+// Başlangıç/bitiş snapshot'ları arada oluşup GC ile temizlenen tepeyi göremez
+type PeakSampler struct {
+	mu   sync.Mutex
+	peak uint64
+	stop chan struct{}
+	done chan struct{}
+}
+
+// StartPeakSampler - interval aralıkla heap'i örneklemeye başlar
+// Örnekler runtime/metrics ile okunur: ReadMemStats'ın aksine dünyayı durdurmaz, ms mertebesi aralıklar uygundur
+func StartPeakSampler(interval time.Duration) *PeakSampler {
+	s := &PeakSampler{stop: make(chan struct{}), done: make(chan struct{})}
+	s.sample()
+	go func() {
+		defer close(s.done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				s.sample()
+			case <-s.stop:
+				return
+			}
+		}
+	}()
+	return s
+}
+
+func (s *PeakSampler) sample() {
+	// heap/objects, MemStats.HeapAlloc ile aynı değerdir
+	sample := []metrics.Sample{{Name: "/memory/classes/heap/objects:bytes"}}
+	metrics.Read(sample)
+	if sample[0].Value.Kind() != metrics.KindUint64 {
+		return
+	}
+	heap := sample[0].Value.Uint64()
+	s.mu.Lock()
+	if heap > s.peak {
+		s.peak = heap
+	}
+	s.mu.Unlock()
+}
+
+// Stop - Örneklemeyi durdurur ve tepe heap değerini döndürür
+func (s *PeakSampler) Stop() uint64 {
+	close(s.stop)
+	<-s.done
+	s.sample()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.peak
+}
+
+// memProbeInterval - MemProbe'un heap zirvesini örnekleme aralığı
+const memProbeInterval = 5 * time.Millisecond
+
+// MemProbe - Ölçülen bölümün bellek muhasebesi: ReadMem + PeakSampler + MemDelta tek yerde
+type MemProbe struct {
+	before MemSnapshot
+	peak   *PeakSampler
+}
+
+// StartMemProbe - GC çalıştırır, başlangıç değerlerini alır ve heap örneklemesini başlatır
+func StartMemProbe() *MemProbe {
+	before := ReadMem(true)
+	return &MemProbe{before: before, peak: StartPeakSampler(memProbeInterval)}
+}
+
+// Stop - Örneklemeyi durdurur; başlangıca göre farkları, heap zirvesini ve zirve büyümesini döndürür
+func (p *MemProbe) Stop() MemUsage {
+	usage := MemDelta(p.before, ReadMem(false))
+	usage.PeakHeap = p.peak.Stop()
+	if usage.PeakHeap > p.before.HeapAlloc {
+		usage.PeakGrowth = int64(usage.PeakHeap - p.before.HeapAlloc)
+	}
+	return usage
+}
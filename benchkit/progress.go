@@ -0,0 +1,69 @@
+package benchkit
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Printf - fmt.Printf ve Logger.Printf ile uyumlu yazma fonksiyonu
+type Printf func(format string, args ...any) (int, error)
+
+// Progress - Uzun süren işlemlerin ilerleme çıktısı
+// Her `every` işlemde bir satır yazar: tamamlanan/toplam, hız ve tahmini kalan süre.
+// Add goroutine'lerden eşzamanlı çağrılabilir.
+type Progress struct {
+	total  int64
+	every  int64
+	done   int64
+	start  time.Time
+	printf Printf
+}
+
+// NewProgress - İlerleme sayacı oluşturur ve süreyi başlatır
+// Parametreler:
+//   - total: Toplam iş miktarı (bilinmiyorsa 0 - ETA yazılmaz)
+//   - every: Kaç işte bir satır yazılacağı
+//   - printf: Çıktı fonksiyonu (fmt.Printf veya logger.Printf)
+func NewProgress(total, every int64, printf Printf) *Progress {
+	if every <= 0 {
+		every = 1
+	}
+	return &Progress{total: total, every: every, start: time.Now(), printf: printf}
+}
+
+// Add - n iş tamamlandı; bir `every` sınırı geçildiyse ilerleme satırını yazar
+// Toplam tamamlanan iş sayısını döndürür
+func (p *Progress) Add(n int64) int64 {
+	done := atomic.AddInt64(&p.done, n)
+	if done/p.every == (done-n)/p.every {
+		return done
+	}
+
+	rate := p.Rate()
+	if p.total > 0 && rate > 0 {
+		eta := time.Duration(float64(p.total-done)/rate) * time.Second
+		p.printf("  ✅ İlerleme: %d/%d (%.1f/sn, Kalan: ~%v)\n", done, p.total, rate, eta)
+	} else {
+		p.printf("  ✅ İlerleme: %d (%.1f/sn)\n", done, rate)
+	}
+	return done
+}
+
+// Done - Tamamlanan iş sayısı
+func (p *Progress) Done() int64 {
+	return atomic.LoadInt64(&p.done)
+}
+
+// Elapsed - Başlangıçtan beri geçen süre
+func (p *Progress) Elapsed() time.Duration {
+	return time.Since(p.start)
+}
+
+// Rate - Başlangıçtan beri ortalama hız (iş/sn)
+func (p *Progress) Rate() float64 {
+	elapsed := time.Since(p.start).Seconds()
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(p.Done()) / elapsed
+}
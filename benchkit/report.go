@@ -0,0 +1,161 @@
+package benchkit
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+)
+
+// Result - Tüm lab'ların ortak sonuç formatı
+// Metrik tanımları:
+//   - durationMs: Ölçülen bölümün duvar saati süresi (warm-up hariç)
+//   - ops: Ölçülen işlem sayısı (okunan doküman, HTTP isteği, döngü iterasyonu...)
+//   - opsPerSec: ops / süre (saniye)
+//   - latencyMs: Tek işlem gecikmesi dağılımı (Percentile tanımıyla), ms
+//   - memory: TotalAlloc farkı ile ölçülen allocation ve GC bilgisi
+//   - errors: Başarısız işlem sayısı
+//   - host: Ölçümün yapıldığı makine (farklı makinelerin sonuçlarını ayırt etmek için)
+//   - histogram: Gecikme dağılımının dolu kovaları (yüzdelikler yeniden hesaplanabilsin, CDF çizilebilsin)
+//   - timeline: Aralık başına throughput (bkz. Timeline)
+//
+// benchmark ve variant, mongo-perf-lab'ın metrik kayıtlarıyla (MetricsRecord) aynı anlamdadır:
+// İkisi birlikte bir ölçüm serisini tanımlar; karşılaştırma araçları farklı çalıştırmaların
+// kayıtlarını bu anahtarla eşleştirir. durationMs, errors ve host alanları da ortaktır.
+type Result struct {
+	Lab        string            `json:"lab"`               // mongo-perf-lab, io-vs-cpu-demo, cross-language
+	Benchmark  string            `json:"benchmark"`         // Lab içindeki benchmark adı
+	Variant    string            `json:"variant,omitempty"` // Parametre kombinasyonunun kısa adı (ör: "http/cpu?iterations=1000/c16")
+	Params     map[string]string `json:"params,omitempty"`
+	StartedAt  time.Time         `json:"startedAt"`
+	DurationMs float64           `json:"durationMs"`
+	Ops        int64             `json:"ops"`
+	OpsPerSec  float64           `json:"opsPerSec"`
+	Latency    *LatencyMs        `json:"latencyMs,omitempty"`
+	Memory     *MemUsage         `json:"memory,omitempty"`
+	Errors     int64             `json:"errors"`
+	Host       *HostInfo         `json:"host,omitempty"`
+	Histogram  []HistogramBucket `json:"histogram,omitempty"`
+	Timeline   []TimelinePoint   `json:"timeline,omitempty"`
+	PerOp      *OpCost           `json:"perOp,omitempty"`
+}
+
+// OpCost - İşlem başına kaynak maliyeti (ör: sunucunun istek başına harcadığı CPU ve bellek)
+type OpCost struct {
+	Ops        int64   `json:"ops"`   // Maliyetin bölündüğü işlem sayısı (Result.Ops'tan az olabilir)
+	CPUMs      float64 `json:"cpuMs"` // İşlem başına CPU süresi (user + system)
+	AllocBytes float64 `json:"allocBytes"`
+	Allocs     float64 `json:"allocs"` // İşlem başına heap ayırma sayısı
+}
+
+// HistogramBucket - Histogram kovasının JSON karşılığı
+type HistogramBucket struct {
+	LeMs  float64 `json:"leMs"` // Kovadaki en büyük değer (ms)
+	Count uint64  `json:"count"`
+}
+
+// HistogramMs - Histogramın dolu kovalarını JSON formatına çevirir
+func HistogramMs(h *Histogram) []HistogramBucket {
+	buckets := h.Buckets()
+	out := make([]HistogramBucket, len(buckets))
+	for i, b := range buckets {
+		out[i] = HistogramBucket{LeMs: Millis(b.Upper), Count: b.Count}
+	}
+	return out
+}
+
+// LatencyMs - Summary'nin JSON karşılığı (tüm değerler milisaniye)
+type LatencyMs struct {
+	Count int64   `json:"count"`
+	Min   float64 `json:"min"`
+	Mean  float64 `json:"mean"`
+	P50   float64 `json:"p50"`
+	P95   float64 `json:"p95"`
+	P99   float64 `json:"p99"`
+	Max   float64 `json:"max"`
+}
+
+// Millis - Summary'yi milisaniye cinsinden JSON formatına çevirir
+func (s Summary) Millis() *LatencyMs {
+	return &LatencyMs{
+		Count: s.Count,
+		Min:   Millis(s.Min),
+		Mean:  Millis(s.Mean),
+		P50:   Millis(s.P50),
+		P95:   Millis(s.P95),
+		P99:   Millis(s.P99),
+		Max:   Millis(s.Max),
+	}
+}
+
+// NewResult - Süre ve işlem sayısından sonuç oluşturur (opsPerSec hesaplanır, host eklenir)
+func NewResult(lab, benchmark string, started time.Time, duration time.Duration, ops int64) Result {
+	host := CollectHostInfo()
+	r := Result{
+		Lab:        lab,
+		Benchmark:  benchmark,
+		StartedAt:  started,
+		DurationMs: Millis(duration),
+		Ops:        ops,
+		Host:       &host,
+	}
+	if duration > 0 {
+		r.OpsPerSec = float64(ops) / duration.Seconds()
+	}
+	return r
+}
+
+// WriteText - Sonucu insan-okunabilir olarak yazar
+// Tüm lab'ların text çıktısında aynı satırlar aynı sırayla görünür
+func (r Result) WriteText(w io.Writer) {
+	name := r.Benchmark
+	if r.Variant != "" {
+		name += "/" + r.Variant
+	}
+	fmt.Fprintf(w, "\n=== %s / %s ===\n", r.Lab, name)
+	if r.Host != nil {
+		fmt.Fprintf(w, "  🖥️  %s\n", r.Host)
+	}
+	if len(r.Params) > 0 {
+		keys := make([]string, 0, len(r.Params))
+		for k := range r.Params {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			fmt.Fprintf(w, "  %-12s %s\n", k+":", r.Params[k])
+		}
+	}
+	fmt.Fprintf(w, "  ⏱️  Süre:      %.2f ms\n", r.DurationMs)
+	fmt.Fprintf(w, "  📦 İşlem:     %d (%.1f/sn)\n", r.Ops, r.OpsPerSec)
+	if l := r.Latency; l != nil && l.Count > 0 {
+		fmt.Fprintf(w, "  📊 Gecikme:   p50 %.3f ms, p95 %.3f ms, p99 %.3f ms, max %.3f ms\n", l.P50, l.P95, l.P99, l.Max)
+	}
+	if m := r.Memory; m != nil {
+		fmt.Fprintf(w, "  💾 Bellek:    %.2f MB ayrıldı, %d GC (%v duraklama)\n", m.AllocatedMB(), m.NumGC, m.GCPause)
+	}
+	if c := r.PerOp; c != nil && c.Ops > 0 {
+		fmt.Fprintf(w, "  🧮 İşlem başı: %.3f ms CPU, %.1f KB, %.0f ayırma (%d işlemden)\n", c.CPUMs, c.AllocBytes/1024, c.Allocs, c.Ops)
+	}
+	if r.Errors > 0 {
+		fmt.Fprintf(w, "  ❌ Hata:      %d\n", r.Errors)
+	}
+}
+
+// AppendJSONL - Değeri dosyaya JSON satırı (JSON Lines) olarak ekler
+// Dosya yoksa oluşturulur; her çağrı dosyayı açıp kapatır, ayrı process'ler aynı dosyaya ekleyebilir
+func AppendJSONL(path string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = file.Write(append(data, '\n'))
+	return err
+}
@@ -0,0 +1,64 @@
+package benchkit
+
+import (
+	"math"
+	"sort"
+)
+
+// Distribution - Tekrarlanan ölçümlerin (tur başına bir değer) özeti
+// Summary gecikme dağılımı içindir (binlerce işlem); Distribution ise birkaç turun
+// süre/bellek gibi değerlerinin ne kadar tutarlı olduğunu gösterir
+type Distribution struct {
+	N      int     `json:"n"`
+	Mean   float64 `json:"mean"`
+	Median float64 `json:"median"`
+	P95    float64 `json:"p95"` // Percentile ile aynı tanım (nearest-rank, alt indeks)
+	Min    float64 `json:"min"`
+	Max    float64 `json:"max"`
+	StdDev float64 `json:"stddev"` // Örneklem standart sapması (n-1)
+}
+
+// Describe - Değer listesinin dağılımını çıkarır
+// Liste kopyalanıp sıralanır, çağıranın dilimi değişmez
+func Describe(values []float64) Distribution {
+	if len(values) == 0 {
+		return Distribution{}
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	n := len(sorted)
+	var sum float64
+	for _, v := range sorted {
+		sum += v
+	}
+	d := Distribution{
+		N:    n,
+		Mean: sum / float64(n),
+		P95:  sorted[int(float64(n-1)*95/100)],
+		Min:  sorted[0],
+		Max:  sorted[n-1],
+	}
+	if n%2 == 1 {
+		d.Median = sorted[n/2]
+	} else {
+		d.Median = (sorted[n/2-1] + sorted[n/2]) / 2
+	}
+	if n > 1 {
+		var sq float64
+		for _, v := range sorted {
+			sq += (v - d.Mean) * (v - d.Mean)
+		}
+		d.StdDev = math.Sqrt(sq / float64(n-1))
+	}
+	return d
+}
+
+// CV - Varyasyon katsayısı (stddev / ortalama, %): Ölçümün gürültü seviyesi
+// Ortalama 0 ise 0 döner
+func (d Distribution) CV() float64 {
+	if d.Mean == 0 {
+		return 0
+	}
+	return d.StdDev / d.Mean * 100
+}
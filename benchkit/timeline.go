@@ -0,0 +1,95 @@
+package benchkit
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// TimelinePoint - Zaman çizelgesinde bir aralığın throughput'u
+// Ortalama ops/sn tek sayıda ısınmayı, duraklamaları (GC, lease bekleme) ve yük altında
+// zamanla düşen performansı gizler; aralık başına değerler bunları görünür kılar
+type TimelinePoint struct {
+	AtMs      float64 `json:"atMs"` // Ölçüm başından aralığın sonuna kadar geçen süre
+	Ops       int64   `json:"ops"`  // Aralıkta tamamlanan başarılı işlem
+	Errors    int64   `json:"errors"`
+	OpsPerSec float64 `json:"opsPerSec"`
+}
+
+// Timeline - Eşzamanlı sayaçlardan belirli aralıklarla örnek alan kayıtçı
+// Add birçok goroutine'den kilitsiz çağrılabilir; örnekleme ayrı goroutine'de yapılır
+type Timeline struct {
+	ops    atomic.Int64
+	errors atomic.Int64
+
+	start  time.Time
+	stop   chan struct{}
+	done   chan struct{}
+	mu     sync.Mutex
+	points []TimelinePoint
+
+	lastOps, lastErrors int64
+	lastAt              time.Time
+}
+
+// StartTimeline - interval aralıklarla örneklemeye başlar
+func StartTimeline(interval time.Duration) *Timeline {
+	now := time.Now()
+	t := &Timeline{start: now, lastAt: now, stop: make(chan struct{}), done: make(chan struct{})}
+	go func() {
+		defer close(t.done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-t.stop:
+				return
+			case now := <-ticker.C:
+				t.sample(now)
+			}
+		}
+	}()
+	return t
+}
+
+// Add - Tamamlanan bir işlemi sayar (ok = false: hata)
+func (t *Timeline) Add(ok bool) {
+	if ok {
+		t.ops.Add(1)
+	} else {
+		t.errors.Add(1)
+	}
+}
+
+func (t *Timeline) sample(now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	ops, errors := t.ops.Load(), t.errors.Load()
+	point := TimelinePoint{
+		AtMs:   Millis(now.Sub(t.start)),
+		Ops:    ops - t.lastOps,
+		Errors: errors - t.lastErrors,
+	}
+	if elapsed := now.Sub(t.lastAt); elapsed > 0 {
+		point.OpsPerSec = float64(point.Ops) / elapsed.Seconds()
+	}
+	t.points = append(t.points, point)
+	t.lastOps, t.lastErrors, t.lastAt = ops, errors, now
+}
+
+// Points - Şu ana kadarki aralıklar (kopya); örnekleme sürer
+func (t *Timeline) Points() []TimelinePoint {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]TimelinePoint(nil), t.points...)
+}
+
+// Stop - Örneklemeyi bitirir; son (yarım) aralık da eklenir
+func (t *Timeline) Stop() []TimelinePoint {
+	close(t.stop)
+	<-t.done
+	if now := time.Now(); now.Sub(t.lastAt) > time.Millisecond {
+		t.sample(now)
+	}
+	return t.Points()
+}
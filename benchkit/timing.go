@@ -0,0 +1,58 @@
+package benchkit
+
+import (
+	"sort"
+	"time"
+)
+
+// Percentile - Sıralı süre listesinde yüzdelik değeri döndürür (nearest-rank, alt indeks)
+// Liste boşsa 0 döner. p=100 maksimum değerdir.
+func Percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	idx := int(float64(len(sorted)-1) * p / 100)
+	return sorted[idx]
+}
+
+// Summary - Gecikme dağılımının özeti
+// Tüm lab'lar aynı alanları raporlar: "p99" her yerde aynı tanımla hesaplanır (Percentile)
+// JSON çıktısında milisaniye olarak LatencyMs kullanılır
+type Summary struct {
+	Count int64
+	Min   time.Duration
+	Mean  time.Duration
+	P50   time.Duration
+	P95   time.Duration
+	P99   time.Duration
+	Max   time.Duration
+}
+
+// Summarize - Süre listesinin özetini çıkarır
+// Liste kopyalanıp sıralanır, çağıranın dilimi değişmez
+func Summarize(durations []time.Duration) Summary {
+	if len(durations) == 0 {
+		return Summary{}
+	}
+	sorted := append([]time.Duration(nil), durations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	var total time.Duration
+	for _, d := range sorted {
+		total += d
+	}
+	return Summary{
+		Count: int64(len(sorted)),
+		Min:   sorted[0],
+		Mean:  total / time.Duration(len(sorted)),
+		P50:   Percentile(sorted, 50),
+		P95:   Percentile(sorted, 95),
+		P99:   Percentile(sorted, 99),
+		Max:   sorted[len(sorted)-1],
+	}
+}
+
+// Millis - Süreyi milisaniye cinsinden float olarak döndürür (raporlardaki tüm süreler ms)
+func Millis(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,229 @@
+package main
+
+import (
+	"fmt"
+	"hash/fnv"
+	"html"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// chart.go - Tablolardan SVG grafikler (harici servis veya kütüphane gerekmez)
+// Çubuk grafik tablonun sıralama ölçüsünü (ns/op veya ops/sn) gösterir; gecikme CDF'i
+// kayıtlardaki histogramdan (benchkit.HistogramMs) çizilir. X ekseni logaritmiktir: Kuyruk
+// gecikmeleri medyandan kat kat büyük olabilir, doğrusal eksende medyanlar üst üste biner.
+// Grafikler HTML rapora gömülür; -charts ile ayrı .svg dosyaları da yazılır.
+
+const (
+	chartWidth     = 640.0
+	barRowHeight   = 22.0
+	barLabelWidth  = 110.0
+	barValueWidth  = 90.0
+	cdfHeight      = 280.0
+	cdfMarginLeft  = 48.0
+	cdfMarginRight = 110.0 // Açıklama (legend) alanı
+	cdfMarginTop   = 12.0
+	cdfMarginBot   = 34.0
+	chartFont      = `font-family="system-ui, sans-serif" font-size="12"`
+)
+
+// languageColors - Dillerin bilinen renkleri (her grafikte aynı dil aynı renk)
+var languageColors = map[string]string{
+	"go":     "#00add8",
+	"node":   "#68a063",
+	"csharp": "#9b4f96",
+	"c":      "#555555",
+}
+
+// languageColor - Bilinmeyen diller için addan türetilen sabit renk
+func languageColor(language string) string {
+	if c, ok := languageColors[language]; ok {
+		return c
+	}
+	h := fnv.New32a()
+	h.Write([]byte(language))
+	v := h.Sum32()
+	return fmt.Sprintf("rgb(%d,%d,%d)", 40+v%150, 40+(v>>8)%150, 40+(v>>16)%150)
+}
+
+// barChart - Tablonun sıralama ölçüsüyle yatay çubuk grafik (satırlar tablodaki sırada)
+func barChart(t Table) string {
+	value := func(r Row) float64 { return r.Result.OpsPerSec }
+	caption := "ops/sn (yüksek daha iyi)"
+	if t.ByNsPerOp {
+		value = func(r Row) float64 { return r.NsPerOp }
+		caption = "ns/op (düşük daha iyi)"
+	}
+	maxValue := 0.0
+	for _, r := range t.Rows {
+		maxValue = max(maxValue, value(r))
+	}
+	height := 20 + float64(len(t.Rows))*barRowHeight + 8
+	barArea := chartWidth - barLabelWidth - barValueWidth
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%.0f" height="%.0f" %s>`+"\n", chartWidth, height, chartFont)
+	fmt.Fprintf(&b, `<text x="%.0f" y="14" fill="#555">%s</text>`+"\n", barLabelWidth, html.EscapeString(caption))
+	for i, r := range t.Rows {
+		y := 20 + float64(i)*barRowHeight
+		width := 0.0
+		if maxValue > 0 {
+			width = value(r) / maxValue * barArea
+		}
+		fmt.Fprintf(&b, `<text x="%.0f" y="%.1f" text-anchor="end">%s</text>`+"\n", barLabelWidth-8, y+barRowHeight/2+4, html.EscapeString(r.Language))
+		fmt.Fprintf(&b, `<rect x="%.0f" y="%.1f" width="%.2f" height="%.0f" fill="%s" rx="2"><title>%s: %s</title></rect>`+"\n",
+			barLabelWidth, y+3, width, barRowHeight-6, languageColor(r.Language), html.EscapeString(r.Language), formatChartValue(value(r)))
+		fmt.Fprintf(&b, `<text x="%.2f" y="%.1f">%s</text>`+"\n", barLabelWidth+width+6, y+barRowHeight/2+4, formatChartValue(value(r)))
+	}
+	b.WriteString("</svg>\n")
+	return b.String()
+}
+
+// formatChartValue - Çubuğun yanındaki kısa değer (1234567 → 1.23M)
+func formatChartValue(v float64) string {
+	switch {
+	case v >= 1e9:
+		return fmt.Sprintf("%.2fG", v/1e9)
+	case v >= 1e6:
+		return fmt.Sprintf("%.2fM", v/1e6)
+	case v >= 1e4:
+		return fmt.Sprintf("%.1fk", v/1e3)
+	case v >= 10:
+		return fmt.Sprintf("%.0f", v)
+	default:
+		return fmt.Sprintf("%.3g", v)
+	}
+}
+
+// cdfChart - Dillerin gecikme dağılımları (kümülatif, log ölçekli ms ekseni)
+// Histogramı olmayan tablo için boş döner
+func cdfChart(t Table) string {
+	lo, hi := math.Inf(1), 0.0
+	for _, r := range t.Rows {
+		for _, bucket := range r.Result.Histogram {
+			if bucket.Count > 0 && bucket.LeMs > 0 {
+				lo, hi = min(lo, bucket.LeMs), max(hi, bucket.LeMs)
+			}
+		}
+	}
+	if hi == 0 {
+		return ""
+	}
+	// Eksen tam onluk kuvvetlerde başlar ve biter
+	minExp, maxExp := math.Floor(math.Log10(lo)), math.Ceil(math.Log10(hi))
+	if maxExp == minExp {
+		maxExp++
+	}
+	plotWidth := chartWidth - cdfMarginLeft - cdfMarginRight
+	plotHeight := cdfHeight - cdfMarginTop - cdfMarginBot
+	x := func(ms float64) float64 {
+		return cdfMarginLeft + (math.Log10(ms)-minExp)/(maxExp-minExp)*plotWidth
+	}
+	y := func(fraction float64) float64 {
+		return cdfMarginTop + (1-fraction)*plotHeight
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%.0f" height="%.0f" %s>`+"\n", chartWidth, cdfHeight, chartFont)
+	// Izgara: Onluk kuvvetler ve 2, 5 katları (x), çeyrekler, p90 ve p99 (y)
+	for exp := minExp; exp < maxExp; exp++ {
+		for _, m := range []float64{1, 2, 5} {
+			ms := m * math.Pow(10, exp)
+			stroke := "#eee"
+			if m == 1 {
+				stroke = "#ccc"
+				fmt.Fprintf(&b, `<text x="%.1f" y="%.0f" text-anchor="middle" fill="#555">%s</text>`+"\n",
+					x(ms), cdfHeight-cdfMarginBot+16, formatMs(ms))
+			}
+			fmt.Fprintf(&b, `<line x1="%.1f" y1="%.0f" x2="%.1f" y2="%.0f" stroke="%s"/>`+"\n", x(ms), y(1), x(ms), y(0), stroke)
+		}
+	}
+	fmt.Fprintf(&b, `<text x="%.1f" y="%.0f" text-anchor="middle" fill="#555">%s</text>`+"\n",
+		x(math.Pow(10, maxExp)), cdfHeight-cdfMarginBot+16, formatMs(math.Pow(10, maxExp)))
+	for _, p := range []float64{0, 0.25, 0.5, 0.75, 0.9, 0.99} {
+		fmt.Fprintf(&b, `<line x1="%.0f" y1="%.1f" x2="%.0f" y2="%.1f" stroke="#ddd"/>`+"\n", cdfMarginLeft, y(p), cdfMarginLeft+plotWidth, y(p))
+		fmt.Fprintf(&b, `<text x="%.0f" y="%.1f" text-anchor="end" fill="#555">p%g</text>`+"\n", cdfMarginLeft-6, y(p)+4, p*100)
+	}
+	fmt.Fprintf(&b, `<text x="%.0f" y="%.0f" text-anchor="middle" fill="#555">gecikme (ms, log)</text>`+"\n",
+		cdfMarginLeft+plotWidth/2, cdfHeight-4)
+
+	legend := 0
+	for _, r := range t.Rows {
+		var total uint64
+		for _, bucket := range r.Result.Histogram {
+			total += bucket.Count
+		}
+		if total == 0 {
+			continue
+		}
+		// Basamaklı çizgi: Kova sınırına kadar önceki oran, sınırda yeni oran
+		var points strings.Builder
+		var cumulative uint64
+		prev := 0.0
+		for _, bucket := range r.Result.Histogram {
+			if bucket.LeMs <= 0 {
+				cumulative += bucket.Count // 0 ms kovası log eksende çizilemez, oranı sonrakine eklenir
+				continue
+			}
+			if points.Len() == 0 {
+				fmt.Fprintf(&points, "%.1f,%.1f ", x(bucket.LeMs), y(0))
+			}
+			fraction := float64(cumulative+bucket.Count) / float64(total)
+			fmt.Fprintf(&points, "%.1f,%.1f %.1f,%.1f ", x(bucket.LeMs), y(prev), x(bucket.LeMs), y(fraction))
+			cumulative += bucket.Count
+			prev = fraction
+		}
+		color := languageColor(r.Language)
+		fmt.Fprintf(&b, `<polyline points="%s" fill="none" stroke="%s" stroke-width="2"><title>%s</title></polyline>`+"\n",
+			strings.TrimSpace(points.String()), color, html.EscapeString(r.Language))
+		ly := cdfMarginTop + 8 + float64(legend)*18
+		fmt.Fprintf(&b, `<rect x="%.0f" y="%.0f" width="12" height="12" fill="%s"/><text x="%.0f" y="%.0f">%s</text>`+"\n",
+			chartWidth-cdfMarginRight+12, ly-10, color, chartWidth-cdfMarginRight+30, ly, html.EscapeString(r.Language))
+		legend++
+	}
+	b.WriteString("</svg>\n")
+	return b.String()
+}
+
+// formatMs - Eksen etiketi (0.01, 0.1, 1, 10, 100, 1000 ...)
+func formatMs(ms float64) string {
+	return strings.TrimSuffix(strings.TrimRight(fmt.Sprintf("%.3f", ms), "0"), ".")
+}
+
+// chartFiles - Tablonun .svg dosyalarını dir'e yazar; yazılan yolları döndürür (CDF yoksa tek dosya)
+func chartFiles(dir string, t Table) ([]string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	base := filepath.Join(dir, slug(t.Title()))
+	svgs := []string{barChart(t), cdfChart(t)}
+	var paths []string
+	for i, suffix := range []string{"-bar.svg", "-cdf.svg"} {
+		if svgs[i] == "" {
+			continue
+		}
+		if err := os.WriteFile(base+suffix, []byte(svgs[i]), 0o644); err != nil {
+			return nil, err
+		}
+		paths = append(paths, base+suffix)
+	}
+	return paths, nil
+}
+
+// slug - Dosya adına uygun başlık ("gc / gogc=100" → "gc-gogc-100")
+func slug(s string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(s) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+			dash = false
+		} else if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	return strings.TrimSuffix(b.String(), "-")
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+)
+
+// aggregate - Dillerin sonuç dosyalarını birleştirip karşılaştırma tabloları üretir
+// Her dilin runner'ı result.schema.json'a uyan kayıtları JSON Lines olarak yazar (Go araçları
+// benchkit.Result ile zaten uyar). Kayıtlar benchmark ve durumla (variant'ın dilden sonraki
+// kısmı: "node/fib-recursive" → fib-recursive) gruplanır; her grupta her dilin en son kaydı
+// bir satırdır. Satırlar params.nsPerOp varsa ona (düşük iyi), yoksa opsPerSec'e (yüksek iyi)
+// göre sıralanır ve en iyiye göre yüzde verilir. params.checksum diğer dillerin çoğunluğundan
+// farklıysa işaretlenir, farklı makinelerin sonuçları aynı tabloya düşerse uyarı yazılır.
+//
+// Şemaya uymayan satırlar (ör: diğer lab'ların kayıtları) atlanır ve sayısı raporlanır.
+//
+// HTML raporda her tablonun altında çubuk grafik ve (kayıtlarda histogram varsa) gecikme CDF'i
+// bulunur (bkz. chart.go). -charts ile grafikler ayrı .svg dosyaları olarak da yazılır; Markdown
+// çıktısı bu dosyalara resim bağlantısı verir.
+//
+// KULLANIM (c_go_nodejs_c# klasöründe):
+//
+//	go run ./aggregate results.jsonl node-results.jsonl csharp-results.jsonl
+//	go run ./aggregate -format html -o report.html *.jsonl
+//	go run ./aggregate -charts charts -o report.md *.jsonl
+func main() {
+	format := flag.String("format", "markdown", "Çıktı biçimi: markdown veya html")
+	output := flag.String("o", "", "Çıktı dosyası (boş: standart çıktı)")
+	verbose := flag.Bool("v", false, "Atlanan satırları tek tek yaz")
+	chartDir := flag.String("charts", "", "Grafiklerin .svg olarak yazılacağı klasör (boş: yazılmaz)")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Kullanım: aggregate [flag'ler] sonuç.jsonl...\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *format != "markdown" && *format != "html" {
+		fmt.Fprintf(os.Stderr, "❌ -format: bilinmeyen biçim %q (markdown, html)\n", *format)
+		os.Exit(2)
+	}
+
+	results, problems, err := readResults(flag.Args())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		os.Exit(1)
+	}
+	if len(problems) > 0 {
+		fmt.Fprintf(os.Stderr, "⚠️  %d satır atlandı (şemaya uymuyor)\n", len(problems))
+		if *verbose {
+			for _, p := range problems {
+				fmt.Fprintf(os.Stderr, "   %s\n", p)
+			}
+		}
+	}
+	if len(results) == 0 {
+		fmt.Fprintln(os.Stderr, "❌ Karşılaştırılacak kayıt yok")
+		os.Exit(1)
+	}
+	tables := buildTables(results)
+
+	charts := map[string][]string{}
+	if *chartDir != "" {
+		for _, t := range tables {
+			if charts[t.Title()], err = chartFiles(*chartDir, t); err != nil {
+				fmt.Fprintf(os.Stderr, "❌ -charts: %v\n", err)
+				os.Exit(1)
+			}
+		}
+	}
+
+	out := os.Stdout
+	if *output != "" {
+		if out, err = os.Create(*output); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+	}
+	w := bufio.NewWriter(out)
+	now := time.Now()
+	if *format == "html" {
+		err = writeHTML(w, tables, now)
+	} else {
+		writeMarkdown(w, tables, now, charts)
+	}
+	if err == nil {
+		err = w.Flush()
+	}
+	if *output != "" {
+		if closeErr := out.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		os.Exit(1)
+	}
+	if *output != "" {
+		fmt.Fprintf(os.Stderr, "📄 %d kayıttan %d tablo %s dosyasına yazıldı\n", len(results), len(tables), *output)
+	}
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// columns - Tablonun başlıkları ve satır hücreleri (Markdown ve HTML aynı hücreleri yazar)
+func columns(t Table) ([]string, [][]string) {
+	hasChecksum, hasFootprint := false, false
+	for _, r := range t.Rows {
+		hasChecksum = hasChecksum || r.Checksum != ""
+		hasFootprint = hasFootprint || r.Result.Params["startupMs"] != ""
+	}
+	header := []string{"#", "dil", "ops/sn"}
+	if t.ByNsPerOp {
+		header = append(header, "ns/op")
+	}
+	header = append(header, "p50 (ms)", "p95 (ms)", "p99 (ms)", "göreli", "hata")
+	if hasFootprint {
+		header = append(header, "başlangıç (ms)", "RSS boşta (MB)", "RSS tepe (MB)")
+	}
+	if hasChecksum {
+		header = append(header, "checksum")
+	}
+
+	var rows [][]string
+	for i, r := range t.Rows {
+		language := r.Language
+		if runtime := r.Result.Params["runtime"]; runtime != "" {
+			language += " (" + runtime + ")"
+		}
+		cells := []string{strconv.Itoa(i + 1), language, formatFloat(r.Result.OpsPerSec, 1)}
+		if t.ByNsPerOp {
+			cells = append(cells, formatFloat(r.NsPerOp, 4))
+		}
+		if l := r.Result.Latency; l != nil && l.Count > 0 {
+			cells = append(cells, formatFloat(l.P50, 3), formatFloat(l.P95, 3), formatFloat(l.P99, 3))
+		} else {
+			cells = append(cells, "-", "-", "-")
+		}
+		cells = append(cells, fmt.Sprintf("%.1f%%", r.Relative), strconv.FormatInt(r.Result.Errors, 10))
+		if hasFootprint {
+			for _, key := range []string{"startupMs", "rssIdleMB", "rssPeakMB"} {
+				cells = append(cells, valueOr(r.Result.Params[key], "-"))
+			}
+		}
+		if hasChecksum {
+			checksum := r.Checksum
+			if r.Mismatch {
+				checksum += " ✗"
+			}
+			cells = append(cells, checksum)
+		}
+		rows = append(rows, cells)
+	}
+	return header, rows
+}
+
+// valueOr - Boşsa yerine geçen değer
+func valueOr(v, fallback string) string {
+	if v == "" {
+		return fallback
+	}
+	return v
+}
+
+// formatFloat - Binlik ayraçsız, sabit ondalıklı sayı
+func formatFloat(v float64, decimals int) string {
+	return strconv.FormatFloat(v, 'f', decimals, 64)
+}
+
+// notes - Tablonun altındaki uyarılar
+func notes(t Table) []string {
+	var out []string
+	if t.ByNsPerOp {
+		out = append(out, "Sıralama ns/op'a göre (düşük daha iyi)")
+	} else {
+		out = append(out, "Sıralama ops/sn'ye göre (yüksek daha iyi)")
+	}
+	for _, r := range t.Rows {
+		if r.Mismatch {
+			out = append(out, fmt.Sprintf("⚠️ %s checksum'u diğer dillerden farklı: Aynı işi yapmamış olabilir", r.Language))
+		}
+	}
+	if len(t.Hosts) > 1 {
+		out = append(out, fmt.Sprintf("⚠️ Sonuçlar %d farklı makineden: %s", len(t.Hosts), strings.Join(t.Hosts, " | ")))
+	}
+	return out
+}
+
+// writeMarkdown - Her tablo için bir başlık, GitHub tablosu ve notlar
+// charts verildiyse (-charts) tablonun .svg dosyaları resim olarak eklenir
+func writeMarkdown(w io.Writer, tables []Table, generated time.Time, charts map[string][]string) {
+	fmt.Fprintf(w, "# Diller arası karşılaştırma\n\n")
+	fmt.Fprintf(w, "%s tarihinde %d tablo üretildi.\n", generated.Format("2006-01-02 15:04"), len(tables))
+	for _, t := range tables {
+		header, rows := columns(t)
+		fmt.Fprintf(w, "\n## %s\n\n", t.Title())
+		fmt.Fprintf(w, "| %s |\n", strings.Join(header, " | "))
+		fmt.Fprintf(w, "|%s\n", strings.Repeat("---|", len(header)))
+		for _, cells := range rows {
+			for i := range cells {
+				cells[i] = strings.ReplaceAll(cells[i], "|", "\\|")
+			}
+			fmt.Fprintf(w, "| %s |\n", strings.Join(cells, " | "))
+		}
+		fmt.Fprintln(w)
+		for _, note := range notes(t) {
+			fmt.Fprintf(w, "- %s\n", note)
+		}
+		if len(t.Hosts) == 1 {
+			fmt.Fprintf(w, "- 🖥️ %s\n", t.Hosts[0])
+		}
+		for _, path := range charts[t.Title()] {
+			fmt.Fprintf(w, "\n![%s](%s)\n", t.Title(), filepath.ToSlash(path))
+		}
+	}
+}
+
+// htmlPage - Harici kaynak gerektirmeyen tek dosyalık rapor
+var htmlPage = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="tr">
+<head>
+<meta charset="utf-8">
+<title>Diller arası karşılaştırma</title>
+<style>
+body { font-family: system-ui, sans-serif; margin: 2rem; color: #222; }
+table { border-collapse: collapse; margin: 0.5rem 0; }
+th, td { border: 1px solid #ccc; padding: 0.3rem 0.6rem; text-align: right; }
+th { background: #f3f3f3; }
+td:nth-child(2) { text-align: left; }
+tr:nth-child(2) td { font-weight: bold; } /* En iyi sonuç */
+.notes { color: #555; font-size: 0.9rem; }
+.charts { display: flex; flex-wrap: wrap; gap: 1rem; align-items: flex-start; }
+</style>
+</head>
+<body>
+<h1>Diller arası karşılaştırma</h1>
+<p>{{.Generated}} tarihinde {{len .Tables}} tablo üretildi.</p>
+{{range .Tables}}
+<h2>{{.Title}}</h2>
+<table>
+<tr>{{range .Header}}<th>{{.}}</th>{{end}}</tr>
+{{range .Rows}}<tr>{{range .}}<td>{{.}}</td>{{end}}</tr>
+{{end}}</table>
+<ul class="notes">{{range .Notes}}<li>{{.}}</li>{{end}}</ul>
+<div class="charts">{{.Bar}}{{.CDF}}</div>
+{{end}}
+</body>
+</html>
+`))
+
+// writeHTML - Markdown'la aynı tablolar ve grafikleri (SVG gömülü), tarayıcıda açılabilir tek dosya
+func writeHTML(w io.Writer, tables []Table, generated time.Time) error {
+	type htmlTable struct {
+		Title  string
+		Header []string
+		Rows   [][]string
+		Notes  []string
+		Bar    template.HTML // chart.go kullanıcı verisini (dil adları) kaçışlayarak yazar
+		CDF    template.HTML
+	}
+	data := struct {
+		Generated string
+		Tables    []htmlTable
+	}{Generated: generated.Format("2006-01-02 15:04")}
+	for _, t := range tables {
+		header, rows := columns(t)
+		tableNotes := notes(t)
+		if len(t.Hosts) == 1 {
+			tableNotes = append(tableNotes, "🖥️ "+t.Hosts[0])
+		}
+		data.Tables = append(data.Tables, htmlTable{Title: t.Title(), Header: header, Rows: rows, Notes: tableNotes,
+			Bar: template.HTML(barChart(t)), CDF: template.HTML(cdfChart(t))})
+	}
+	return htmlPage.Execute(w, data)
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"benchkit"
+)
+
+// readResults - JSON Lines dosyalarındaki kayıtlar; şemaya uymayan satırlar satır numarasıyla raporlanır
+func readResults(paths []string) ([]benchkit.Result, []string, error) {
+	var results []benchkit.Result
+	var problems []string
+	for _, path := range paths {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		scanner := bufio.NewScanner(file)
+		scanner.Buffer(make([]byte, 1<<20), 16<<20) // Histogram ve zaman çizelgesi satırı uzatır
+		for line := 1; scanner.Scan(); line++ {
+			text := strings.TrimSpace(scanner.Text())
+			if text == "" {
+				continue
+			}
+			var r benchkit.Result
+			if err := json.Unmarshal([]byte(text), &r); err != nil {
+				problems = append(problems, fmt.Sprintf("%s:%d: %v", path, line, err))
+				continue
+			}
+			if err := validate(r); err != nil {
+				problems = append(problems, fmt.Sprintf("%s:%d: %v", path, line, err))
+				continue
+			}
+			results = append(results, r)
+		}
+		err = scanner.Err()
+		file.Close()
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	return results, problems, nil
+}
+
+// validate - result.schema.json'daki zorunlu alanlar (diğer lab'ların kayıtları da burada elenir)
+func validate(r benchkit.Result) error {
+	switch {
+	case r.Lab != "cross-language":
+		return fmt.Errorf("lab %q, cross-language bekleniyor", r.Lab)
+	case r.Benchmark == "" || r.Variant == "":
+		return fmt.Errorf("benchmark ve variant gerekli")
+	case r.Params["language"] == "":
+		return fmt.Errorf("params.language gerekli")
+	case r.StartedAt.IsZero():
+		return fmt.Errorf("startedAt gerekli")
+	case r.Ops < 0 || r.OpsPerSec < 0 || r.DurationMs < 0:
+		return fmt.Errorf("ops, opsPerSec ve durationMs negatif olamaz")
+	}
+	return nil
+}
+
+// Row - Tablodaki bir dilin sonucu
+type Row struct {
+	Language string
+	Result   benchkit.Result
+	NsPerOp  float64 // params.nsPerOp (yoksa 0)
+	Relative float64 // En iyiye göre yüzde (100 = en iyi)
+	Checksum string
+	Mismatch bool // Checksum çoğunluktan farklı
+}
+
+// Table - Aynı benchmark ve durumun dillere göre sıralı sonuçları
+type Table struct {
+	Benchmark string
+	Case      string // variant'ın dilden sonraki kısmı (ping'de boş)
+	ByNsPerOp bool   // Sıralama ns/op'a göre (düşük iyi); değilse opsPerSec'e göre (yüksek iyi)
+	Rows      []Row
+	Hosts     []string // Farklı makineler (bkz. machine; birden fazlaysa tablo uyarı taşır)
+}
+
+// Title - Tablo başlığı
+func (t Table) Title() string {
+	if t.Case == "" {
+		return t.Benchmark
+	}
+	return t.Benchmark + " / " + t.Case
+}
+
+// buildTables - Kayıtları (benchmark, durum) ile gruplar; her dilin en son kaydı kullanılır
+func buildTables(results []benchkit.Result) []Table {
+	type key struct{ benchmark, caseName string }
+	latest := map[key]map[string]benchkit.Result{}
+	for _, r := range results {
+		language := r.Params["language"]
+		caseName := strings.TrimPrefix(strings.TrimPrefix(r.Variant, language), "/")
+		k := key{r.Benchmark, caseName}
+		if latest[k] == nil {
+			latest[k] = map[string]benchkit.Result{}
+		}
+		if prev, ok := latest[k][language]; !ok || r.StartedAt.After(prev.StartedAt) {
+			latest[k][language] = r
+		}
+	}
+
+	var tables []Table
+	for k, byLanguage := range latest {
+		t := Table{Benchmark: k.benchmark, Case: k.caseName, ByNsPerOp: true}
+		hosts := map[string]bool{}
+		checksums := map[string]int{}
+		for language, r := range byLanguage {
+			row := Row{Language: language, Result: r, Checksum: r.Params["checksum"]}
+			if v, err := strconv.ParseFloat(r.Params["nsPerOp"], 64); err == nil && v > 0 {
+				row.NsPerOp = v
+			} else {
+				t.ByNsPerOp = false
+			}
+			if r.Host != nil {
+				hosts[machine(*r.Host)] = true
+			}
+			if row.Checksum != "" {
+				checksums[row.Checksum]++
+			}
+			t.Rows = append(t.Rows, row)
+		}
+		for host := range hosts {
+			t.Hosts = append(t.Hosts, host)
+		}
+		sort.Strings(t.Hosts)
+		rank(&t, majority(checksums))
+		tables = append(tables, t)
+	}
+	sort.Slice(tables, func(i, j int) bool {
+		if tables[i].Benchmark != tables[j].Benchmark {
+			return tables[i].Benchmark < tables[j].Benchmark
+		}
+		return tables[i].Case < tables[j].Case
+	})
+	return tables
+}
+
+// machine - Makinenin kimliği: Çalışma ortamı sürümleri (goVersion, gomaxprocs) dile göre
+// değişir, aynı makinede ölçülen dillerin sonuçları ayrı makine sayılmamalı
+func machine(h benchkit.HostInfo) string {
+	return fmt.Sprintf("%s: %s, %d çekirdek, %.1f GB RAM, %s %s", h.Hostname, h.CPUModel, h.Cores,
+		float64(h.MemoryMB)/1024, h.OS, h.Arch)
+}
+
+// rank - Satırları sıralar, göreli değeri ve checksum uyuşmazlığını işaretler
+func rank(t *Table, expected string) {
+	better := func(a, b Row) bool {
+		if t.ByNsPerOp {
+			return a.NsPerOp < b.NsPerOp
+		}
+		return a.Result.OpsPerSec > b.Result.OpsPerSec
+	}
+	sort.SliceStable(t.Rows, func(i, j int) bool {
+		if better(t.Rows[i], t.Rows[j]) != better(t.Rows[j], t.Rows[i]) {
+			return better(t.Rows[i], t.Rows[j])
+		}
+		return t.Rows[i].Language < t.Rows[j].Language
+	})
+	best := t.Rows[0]
+	for i := range t.Rows {
+		row := &t.Rows[i]
+		switch {
+		case t.ByNsPerOp:
+			row.Relative = best.NsPerOp / row.NsPerOp * 100
+		case best.Result.OpsPerSec > 0:
+			row.Relative = row.Result.OpsPerSec / best.Result.OpsPerSec * 100
+		}
+		row.Mismatch = row.Checksum != "" && expected != "" && row.Checksum != expected
+	}
+}
+
+// majority - En çok dilin ürettiği checksum (eşitlikte alfabetik ilk)
+func majority(counts map[string]int) string {
+	var best string
+	for checksum, n := range counts {
+		if n > counts[best] || (n == counts[best] && checksum < best) {
+			best = checksum
+		}
+	}
+	return best
+}
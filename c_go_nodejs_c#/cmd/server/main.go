@@ -0,0 +1,154 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// GET /ping?delay=10ms&jitter=5ms&size=4096
+//
+//	delay   Simüle edilen I/O beklemesi (varsayılan 10ms)
+//	jitter  Beklemeye eklenen ±jitter'lık düzgün dağılımlı sapma (varsayılan 0, sonuç 0'ın altına inmez)
+//	size    Yanıt gövdesinin boyutu (bayt, varsayılan 4: "pong"); "pong" tekrarlanarak doldurulur
+//
+// Parametresiz istek eski davranışla aynıdır; farklı çalışma noktalarında (kısa/uzun bekleme,
+// küçük/büyük yanıt) diller arası karşılaştırma yapılabilir.
+
+// maxPingSize - size üst sınırı (tek istek sunucuyu bellek dışına çıkarmasın)
+const maxPingSize = 16 << 20
+
+// pongChunk - Yanıt bu bloktan parça parça yazılır (büyük yanıtlar için istek başına ayırma yok)
+var pongChunk = func() []byte {
+	b := make([]byte, 64<<10)
+	for i := range b {
+		b[i] = "pong"[i%4]
+	}
+	return b
+}()
+
+func handler(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/ping" {
+		delay, jitter, size, err := pingParams(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if jitter > 0 {
+			delay += time.Duration(rand.Int63n(int64(2*jitter)+1)) - jitter
+		}
+		time.Sleep(max(delay, 0)) // I/O simülasyonu
+		w.Header().Set("Content-Length", strconv.Itoa(size))
+		for size > 0 {
+			n := min(size, len(pongChunk))
+			if _, err := w.Write(pongChunk[:n]); err != nil {
+				return
+			}
+			size -= n
+		}
+	}
+}
+
+// pingParams - delay, jitter ve size parametreleri (verilmeyenler varsayılan)
+func pingParams(r *http.Request) (delay, jitter time.Duration, size int, err error) {
+	q := r.URL.Query()
+	delay, size = 10*time.Millisecond, 4
+	if v := q.Get("delay"); v != "" {
+		if delay, err = time.ParseDuration(v); err != nil || delay < 0 {
+			return 0, 0, 0, fmt.Errorf("delay: negatif olmayan süre bekleniyor (ör: 10ms)")
+		}
+	}
+	if v := q.Get("jitter"); v != "" {
+		if jitter, err = time.ParseDuration(v); err != nil || jitter < 0 {
+			return 0, 0, 0, fmt.Errorf("jitter: negatif olmayan süre bekleniyor (ör: 5ms)")
+		}
+	}
+	if v := q.Get("size"); v != "" {
+		if size, err = strconv.Atoi(v); err != nil || size < 0 || size > maxPingSize {
+			return 0, 0, 0, fmt.Errorf("size: 0 ile %d arasında bayt sayısı bekleniyor", maxPingSize)
+		}
+	}
+	return delay, jitter, size, nil
+}
+
+// GET /json?n=100
+//
+// n iç içe nesne (1-10000, varsayılan 100) her istekte yeniden oluşturulup serileştirilir;
+// bekleme yoktur, ölçülen şey nesne oluşturma + JSON serileştirme + yazmadır. Diğer dillerin
+// sunucuları (server.js, server.cs, server.c) aynı alan sırasıyla bayt bayt aynı gövdeyi üretir;
+// orchestrator gövdenin özetini checksum olarak kaydeder, farklı üreten dil tabloda işaretlenir:
+//
+//	{"count":N,"items":[{"id":i,"name":"item-i","price":i+0.5,"active":i%2==0,
+//	  "tags":["tag-<i%5>","tag-<(i+1)%5>"],
+//	  "owner":{"id":i%10,"name":"user-<i%10>","email":"user-<i%10>@example.com"},
+//	  "scores":[i%7,i%11,i%13]}, ...]}
+
+// maxJSONItems - n üst sınırı
+const maxJSONItems = 10000
+
+type jsonOwner struct {
+	ID    int    `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+type jsonItem struct {
+	ID     int       `json:"id"`
+	Name   string    `json:"name"`
+	Price  float64   `json:"price"`
+	Active bool      `json:"active"`
+	Tags   []string  `json:"tags"`
+	Owner  jsonOwner `json:"owner"`
+	Scores []int     `json:"scores"`
+}
+
+type jsonResponse struct {
+	Count int        `json:"count"`
+	Items []jsonItem `json:"items"`
+}
+
+func jsonHandler(w http.ResponseWriter, r *http.Request) {
+	n := 100
+	if v := r.URL.Query().Get("n"); v != "" {
+		var err error
+		if n, err = strconv.Atoi(v); err != nil || n < 1 || n > maxJSONItems {
+			http.Error(w, fmt.Sprintf("n: 1 ile %d arasında sayı bekleniyor", maxJSONItems), http.StatusBadRequest)
+			return
+		}
+	}
+	resp := jsonResponse{Count: n, Items: make([]jsonItem, n)}
+	for i := range resp.Items {
+		owner := i % 10
+		resp.Items[i] = jsonItem{
+			ID:     i,
+			Name:   "item-" + strconv.Itoa(i),
+			Price:  float64(i) + 0.5,
+			Active: i%2 == 0,
+			Tags:   []string{"tag-" + strconv.Itoa(i%5), "tag-" + strconv.Itoa((i+1)%5)},
+			Owner: jsonOwner{
+				ID:    owner,
+				Name:  "user-" + strconv.Itoa(owner),
+				Email: "user-" + strconv.Itoa(owner) + "@example.com",
+			},
+			Scores: []int{i % 7, i % 11, i % 13},
+		}
+	}
+	body, err := json.Marshal(resp)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	w.Write(body)
+}
+
+func main() {
+	http.HandleFunc("/ping", handler)
+	http.HandleFunc("/json", jsonHandler)
+	fmt.Println("Go server running on :3001")
+	http.ListenAndServe(":3001", nil)
+}
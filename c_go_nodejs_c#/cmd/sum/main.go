@@ -0,0 +1,148 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+	"strconv"
+	"sync"
+	"time"
+
+	"benchkit"
+)
+
+// cmd/sum - 1'den n'e kadar toplamın diller arası ölçümü
+// Tek çalıştırma derleyici ısınmasını, CPU frekans geçişlerini ve gürültüyü ayıramaz. Bu yüzden:
+//   - Önce -warmup kez ölçülmeden çalıştırılır, sonra -iterations kez ölçülür
+//   - Her çalıştırmanın sonucu n(n+1)/2 ile doğrulanır (yanlış sonuç hızlı olsa da geçersizdir)
+//   - ns/op eleman başınadır: Çalıştırmaların medyanı / n
+//   - parallel: Aralık -workers goroutine'e parçalanır, kısmi toplamlar en sonda birleştirilir
+//
+// Sonuçlar ortak formatta (benchkit.Result, benchmark "sum", variant "go/serial" ve
+// "go/parallel-8") yazılır; diğer dillerin ölçümleri aynı dosyaya eklenip birleştirilebilir.
+// params.nsPerOp dillerin karşılaştırıldığı değerdir, gecikme alanları çalıştırma sürelerini özetler.
+//
+//	go run ./cmd/sum
+//	go run ./cmd/sum -n 1000000000 -iterations 20 -workers 8 -json results.jsonl
+func main() {
+	n := flag.Int64("n", 100_000_000, "Toplanacak son sayı")
+	iterations := flag.Int("iterations", 10, "Ölçülen çalıştırma sayısı")
+	warmup := flag.Int("warmup", 3, "Ölçülmeyen ısınma çalıştırması")
+	workers := flag.Int("workers", runtime.NumCPU(), "parallel varyantının goroutine sayısı")
+	variants := flag.String("variant", "all", "serial, parallel veya all")
+	jsonPath := flag.String("json", "", "Sonuçların ekleneceği JSON Lines dosyası (benchkit formatı)")
+	flag.Parse()
+
+	if *n <= 0 || *iterations <= 0 || *warmup < 0 || *workers <= 0 {
+		fmt.Println("❌ n, iterations ve workers pozitif, warmup negatif olmayan sayı olmalı")
+		os.Exit(2)
+	}
+
+	type variant struct {
+		name string
+		run  func(n int64) int64
+	}
+	var selected []variant
+	if *variants == "all" || *variants == "serial" {
+		selected = append(selected, variant{"go/serial", sumSerial})
+	}
+	if *variants == "all" || *variants == "parallel" {
+		w := *workers
+		selected = append(selected, variant{fmt.Sprintf("go/parallel-%d", w), func(n int64) int64 { return sumParallel(n, w) }})
+	}
+	if len(selected) == 0 {
+		fmt.Printf("❌ bilinmeyen varyant %q (serial, parallel, all)\n", *variants)
+		os.Exit(2)
+	}
+
+	expected := *n * (*n + 1) / 2
+	host := benchkit.CollectHostInfo()
+	fmt.Printf("🚀 n=%d, %d ısınma + %d ölçüm, beklenen toplam %d\n", *n, *warmup, *iterations, expected)
+
+	for _, v := range selected {
+		for i := 0; i < *warmup; i++ {
+			v.run(*n)
+		}
+
+		latency := benchkit.NewHistogram()
+		var failed int64
+		start := time.Now()
+		for i := 0; i < *iterations; i++ {
+			runStart := time.Now()
+			sum := v.run(*n)
+			latency.Record(time.Since(runStart))
+			if sum != expected {
+				failed++
+				fmt.Printf("  ❌ %s: yanlış sonuç %d (beklenen %d)\n", v.name, sum, expected)
+			}
+		}
+		elapsed := time.Since(start)
+
+		summary := latency.Summary()
+		nsPerOp := float64(summary.P50.Nanoseconds()) / float64(*n)
+		result := benchkit.NewResult("cross-language", "sum", start, elapsed, *n*int64(*iterations))
+		result.Variant = v.name
+		result.Params = map[string]string{"language": "go", "n": strconv.FormatInt(*n, 10),
+			"iterations": strconv.Itoa(*iterations), "warmup": strconv.Itoa(*warmup),
+			"nsPerOp": strconv.FormatFloat(nsPerOp, 'f', 4, 64)}
+		if v.name != "go/serial" {
+			result.Params["workers"] = strconv.Itoa(*workers)
+		}
+		result.Latency = summary.Millis()
+		result.Histogram = benchkit.HistogramMs(latency)
+		result.Errors = failed
+		result.Host = &host
+		result.WriteText(os.Stdout)
+
+		if *jsonPath != "" {
+			if err := benchkit.AppendJSONL(*jsonPath, result); err != nil {
+				fmt.Printf("⚠️  Sonuç yazılamadı: %v\n", err)
+			}
+		}
+	}
+	if *jsonPath != "" {
+		fmt.Printf("\n📄 %d sonuç %s dosyasına eklendi\n", len(selected), *jsonPath)
+	}
+}
+
+// sumSerial - Tek döngü
+func sumSerial(n int64) int64 {
+	var sum int64
+	for i := int64(1); i <= n; i++ {
+		sum += i
+	}
+	return sum
+}
+
+// sumParallel - [1, n] aralığı workers parçaya bölünür, her parça kendi goroutine'inde toplanır
+// Kısmi toplamlar ayrı yerel değişkenlerde tutulur: Paylaşılan dizide yan yana yazmak aynı
+// cache line'ı çekirdekler arasında gidip getirir (false sharing)
+func sumParallel(n int64, workers int) int64 {
+	partials := make([]int64, workers)
+	chunk := (n + int64(workers) - 1) / int64(workers)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		from := int64(w)*chunk + 1
+		to := min(from+chunk-1, n)
+		if from > to {
+			break
+		}
+		wg.Add(1)
+		go func(w int, from, to int64) {
+			defer wg.Done()
+			var sum int64
+			for i := from; i <= to; i++ {
+				sum += i
+			}
+			partials[w] = sum
+		}(w, from, to)
+	}
+	wg.Wait()
+
+	var sum int64
+	for _, p := range partials {
+		sum += p
+	}
+	return sum
+}
@@ -0,0 +1,300 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"math/bits"
+	"strconv"
+)
+
+// Benchmark - Diller arası ortak CPU benchmark'ı
+// Girdi Setup'ta bir kez üretilir; Reset ve Verify ölçülmez, yalnızca Run ölçülür
+type Benchmark interface {
+	Name() string
+	Params() map[string]string // Benchmark'a özel flag değerleri (sonuca eklenir)
+	Setup()
+	Reset()                      // Her Run'dan önce (ör: sıralanacak diziyi baştan kopyalar)
+	Run()                        // Ölçülen iş
+	Verify() (string, error)     // Son Run'ın sonucunu doğrular, diller arası karşılaştırılacak checksum'u döner
+	Ops() (n int64, unit string) // ns/op'un paydası
+}
+
+// registry - Çalıştırılabilir benchmark'lar (-bench ile seçilir)
+// Flag'ler burada tanımlanır: Her benchmark kendi parametresini taşır (ör: -fib-n, -sort-n)
+var registry = []Benchmark{
+	&fibRecursive{n: flag.Int("fib-n", 35, "fib-recursive: n")},
+	&fibIterative{n: flag.Int64("fib-iter-n", 100_000_000, "fib-iterative: n (sonuç mod 2^64)")},
+	&quicksort{n: flag.Int("sort-n", 10_000_000, "quicksort: dizi uzunluğu")},
+	&matrix{n: flag.Int("matrix-n", 512, "matrix: n×n float64 matris çarpımı")},
+	&sha256Bench{mb: flag.Int("sha256-mb", 1024, "sha256: özetlenecek veri (MB)")},
+}
+
+// xorshift32 - Girdi üreteci (Marsaglia); 32 bit işlemlerle JS'te (>>> ve Math.imul gerekmez),
+// C#'ta ve C'de aynı diziyi üretir. Tohum 2463534242
+type xorshift32 uint32
+
+func (x *xorshift32) next() uint32 {
+	v := uint32(*x)
+	v ^= v << 13
+	v ^= v >> 17
+	v ^= v << 5
+	*x = xorshift32(v)
+	return v
+}
+
+const seed xorshift32 = 2463534242
+
+// fibRecursive - fib(n) = fib(n-1) + fib(n-2): Fonksiyon çağrısı ve yığın maliyeti
+// Op bir çağrıdır: fib(n) için 2·fib(n+1) - 1 çağrı yapılır
+type fibRecursive struct {
+	n      *int
+	result int64
+}
+
+func (b *fibRecursive) Name() string              { return "fib-recursive" }
+func (b *fibRecursive) Params() map[string]string { return map[string]string{"n": strconv.Itoa(*b.n)} }
+func (b *fibRecursive) Setup()                    {}
+func (b *fibRecursive) Reset()                    {}
+func (b *fibRecursive) Run()                      { b.result = fib(*b.n) }
+
+func fib(n int) int64 {
+	if n < 2 {
+		return int64(n)
+	}
+	return fib(n-1) + fib(n-2)
+}
+
+func (b *fibRecursive) Verify() (string, error) {
+	expected := fibDoubling(uint64(*b.n))
+	if uint64(b.result) != expected {
+		return "", fmt.Errorf("fib(%d) = %d, beklenen %d", *b.n, b.result, expected)
+	}
+	return strconv.FormatInt(b.result, 10), nil
+}
+
+func (b *fibRecursive) Ops() (int64, string) {
+	return 2*int64(fibDoubling(uint64(*b.n+1))) - 1, "çağrı"
+}
+
+// fibIterative - a, b = b, a+b döngüsü, uint64 taşmasıyla (mod 2^64): Sıkı döngü ve toplama
+type fibIterative struct {
+	n      *int64
+	result uint64
+}
+
+func (b *fibIterative) Name() string { return "fib-iterative" }
+func (b *fibIterative) Params() map[string]string {
+	return map[string]string{"n": strconv.FormatInt(*b.n, 10)}
+}
+func (b *fibIterative) Setup() {}
+func (b *fibIterative) Reset() {}
+
+func (b *fibIterative) Run() {
+	var x, y uint64 = 0, 1
+	for i := int64(0); i < *b.n; i++ {
+		x, y = y, x+y
+	}
+	b.result = x
+}
+
+func (b *fibIterative) Verify() (string, error) {
+	if expected := fibDoubling(uint64(*b.n)); b.result != expected {
+		return "", fmt.Errorf("fib(%d) mod 2^64 = %d, beklenen %d", *b.n, b.result, expected)
+	}
+	return strconv.FormatUint(b.result, 10), nil
+}
+
+func (b *fibIterative) Ops() (int64, string) { return *b.n, "adım" }
+
+// fibDoubling - fib(n) mod 2^64, O(log n): fib(2k) = fib(k)·(2·fib(k+1) - fib(k)),
+// fib(2k+1) = fib(k)² + fib(k+1)². Doğrulama için ölçülen yöntemlerden bağımsız bir yol
+func fibDoubling(n uint64) uint64 {
+	var a, b uint64 = 0, 1 // fib(k), fib(k+1)
+	for i := bits.Len64(n) - 1; i >= 0; i-- {
+		c := a * (2*b - a)
+		d := a*a + b*b
+		a, b = c, d
+		if n>>uint(i)&1 == 1 {
+			a, b = b, a+b
+		}
+	}
+	return a
+}
+
+// quicksort - xorshift32 dizisinin elle yazılmış quicksort'u (Hoare bölmesi, ortadaki pivot)
+// Kütüphane sıralaması kullanılmaz: Diller farklı algoritmalar kullanır (pdqsort, TimSort, introsort)
+type quicksort struct {
+	n        *int
+	input    []int32
+	data     []int32
+	inputSum int64
+}
+
+func (b *quicksort) Name() string              { return "quicksort" }
+func (b *quicksort) Params() map[string]string { return map[string]string{"n": strconv.Itoa(*b.n)} }
+
+func (b *quicksort) Setup() {
+	rng := seed
+	b.input = make([]int32, *b.n)
+	b.inputSum = 0
+	for i := range b.input {
+		b.input[i] = int32(rng.next() >> 1) // 0 ile 2^31-1 arası: İşaretli 32 bit her dilde aynı
+		b.inputSum += int64(b.input[i])
+	}
+	b.data = make([]int32, *b.n)
+}
+
+func (b *quicksort) Reset() { copy(b.data, b.input) }
+func (b *quicksort) Run()   { sortInts(b.data, 0, len(b.data)-1) }
+
+func sortInts(a []int32, lo, hi int) {
+	for lo < hi {
+		pivot := a[lo+(hi-lo)/2]
+		i, j := lo, hi
+		for i <= j {
+			for a[i] < pivot {
+				i++
+			}
+			for a[j] > pivot {
+				j--
+			}
+			if i <= j {
+				a[i], a[j] = a[j], a[i]
+				i++
+				j--
+			}
+		}
+		// Küçük taraf özyinelemeyle, büyük taraf döngüyle: Yığın derinliği O(log n) kalır
+		if j-lo < hi-i {
+			sortInts(a, lo, j)
+			lo = i
+		} else {
+			sortInts(a, i, hi)
+			hi = j
+		}
+	}
+}
+
+func (b *quicksort) Verify() (string, error) {
+	var sum int64
+	for i, v := range b.data {
+		if i > 0 && b.data[i-1] > v {
+			return "", fmt.Errorf("sıralı değil: [%d]=%d > [%d]=%d", i-1, b.data[i-1], i, v)
+		}
+		sum += int64(v)
+	}
+	if sum != b.inputSum {
+		return "", fmt.Errorf("elemanlar değişti: toplam %d, beklenen %d", sum, b.inputSum)
+	}
+	n := len(b.data)
+	return fmt.Sprintf("%d/%d/%d", b.data[0], b.data[n/2], b.data[n-1]), nil
+}
+
+func (b *quicksort) Ops() (int64, string) { return int64(*b.n), "eleman" }
+
+// matrix - n×n float64 matris çarpımı (düz i-k-j döngüsü, satır öncelikli düz diziler)
+// A[i][j] = (i·j) mod 7 + 1, B[i][j] = (i+j) mod 5 + 1: Tüm ara değerler tam sayıdır, toplam
+// her dilde bit bit aynı çıkar
+type matrix struct {
+	n       *int
+	a, b, c []float64
+}
+
+func (m *matrix) Name() string              { return "matrix" }
+func (m *matrix) Params() map[string]string { return map[string]string{"n": strconv.Itoa(*m.n)} }
+
+func (m *matrix) Setup() {
+	n := *m.n
+	m.a, m.b, m.c = make([]float64, n*n), make([]float64, n*n), make([]float64, n*n)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			m.a[i*n+j] = float64(i*j%7 + 1)
+			m.b[i*n+j] = float64((i+j)%5 + 1)
+		}
+	}
+}
+
+func (m *matrix) Reset() { clear(m.c) }
+
+func (m *matrix) Run() {
+	n := *m.n
+	for i := 0; i < n; i++ {
+		row := m.c[i*n : (i+1)*n]
+		for k := 0; k < n; k++ {
+			aik := m.a[i*n+k]
+			bk := m.b[k*n : (k+1)*n]
+			for j := range row {
+				row[j] += aik * bk[j]
+			}
+		}
+	}
+}
+
+// Verify - Σ C = Σ_k (A'nın k. sütun toplamı)·(B'nin k. satır toplamı): Çarpım yapmadan O(n²)
+func (m *matrix) Verify() (string, error) {
+	n := *m.n
+	var sum, expected float64
+	for _, v := range m.c {
+		sum += v
+	}
+	for k := 0; k < n; k++ {
+		var col, row float64
+		for i := 0; i < n; i++ {
+			col += m.a[i*n+k]
+			row += m.b[k*n+i]
+		}
+		expected += col * row
+	}
+	if sum != expected {
+		return "", fmt.Errorf("ΣC = %g, beklenen %g", sum, expected)
+	}
+	return strconv.FormatFloat(sum, 'f', 0, 64), nil
+}
+
+func (m *matrix) Ops() (int64, string) { n := int64(*m.n); return n * n * n, "çarp-topla" }
+
+// sha256Bench - 1 MB'lık xorshift32 bloğunun mb kez akış halinde özetlenmesi
+// Blok, üretecin ardışık değerlerinin little-endian baytlarıdır
+type sha256Bench struct {
+	mb        *int
+	block     []byte
+	digest    string
+	reference string // İlk çalıştırmanın özeti: Sonrakiler aynı olmalı
+}
+
+func (b *sha256Bench) Name() string              { return "sha256" }
+func (b *sha256Bench) Params() map[string]string { return map[string]string{"mb": strconv.Itoa(*b.mb)} }
+
+func (b *sha256Bench) Setup() {
+	rng := seed
+	b.block = make([]byte, 1<<20)
+	for i := 0; i < len(b.block); i += 4 {
+		binary.LittleEndian.PutUint32(b.block[i:], rng.next())
+	}
+	b.reference = ""
+}
+
+func (b *sha256Bench) Reset() {}
+
+func (b *sha256Bench) Run() {
+	h := sha256.New()
+	for i := 0; i < *b.mb; i++ {
+		h.Write(b.block)
+	}
+	b.digest = hex.EncodeToString(h.Sum(nil))
+}
+
+func (b *sha256Bench) Verify() (string, error) {
+	if b.reference == "" {
+		b.reference = b.digest
+	}
+	if b.digest != b.reference {
+		return "", fmt.Errorf("özet çalıştırmalar arasında değişti: %s, ilk %s", b.digest, b.reference)
+	}
+	return b.digest, nil
+}
+
+func (b *sha256Bench) Ops() (int64, string) { return int64(*b.mb) << 20, "bayt" }
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"benchkit"
+)
+
+// compute - Diller arası CPU benchmark paketi
+// cmd/sum'ın tek döngüsü derleyicinin en kolay optimize ettiği iştir; gerçek farklar çağrı
+// maliyetinde, dizi erişiminde, kayan noktada ve kütüphanelerde ortaya çıkar:
+//
+//	fib-recursive  Özyinelemeli fib(35): Fonksiyon çağrısı (op = çağrı)
+//	fib-iterative  Döngüyle fib(1e8) mod 2^64: Sıkı döngü (op = adım)
+//	quicksort      10M int32'nin elle yazılmış quicksort'u: Dallanma ve bellek erişimi (op = eleman)
+//	matrix         512×512 float64 çarpımı: Kayan nokta ve önbellek (op = çarp-topla)
+//	sha256         1 GB'ın SHA-256 özeti: Standart kütüphanenin kripto kodu (op = bayt)
+//
+// Algoritmalar ve girdiler (xorshift32, tohum 2463534242) diğer dillerin birebir tekrarlayacağı
+// şekilde tanımlıdır (bkz. benchmarks.go); her benchmark sonucunu doğrular ve diller arasında
+// aynı çıkması gereken bir checksum üretir. Ölçüm cmd/sum ile aynıdır: -warmup ısınma, -iterations
+// ölçüm, ns/op medyan çalıştırma / op. Sonuçlar benchmark "compute", variant "go/<ad>" olarak
+// ortak formatta yazılır; params.checksum farklı çıkan dil aynı işi yapmamıştır.
+//
+// KULLANIM (c_go_nodejs_c# klasöründe):
+//
+//	go run ./compute
+//	go run ./compute -bench fib-recursive,matrix -matrix-n 1024 -iterations 10 -json results.jsonl
+func main() {
+	names := flag.String("bench", "all", "Çalıştırılacak benchmark'lar (virgülle ayrılmış veya all)")
+	iterations := flag.Int("iterations", 5, "Ölçülen çalıştırma sayısı")
+	warmup := flag.Int("warmup", 1, "Ölçülmeyen ısınma çalıştırması")
+	jsonPath := flag.String("json", "", "Sonuçların ekleneceği JSON Lines dosyası (benchkit formatı)")
+	flag.Parse()
+
+	if *iterations <= 0 || *warmup < 0 {
+		fmt.Println("❌ iterations pozitif, warmup negatif olmayan sayı olmalı")
+		os.Exit(2)
+	}
+	selected, err := selectBenchmarks(*names)
+	if err != nil {
+		fmt.Printf("❌ -bench: %v\n", err)
+		os.Exit(2)
+	}
+
+	host := benchkit.CollectHostInfo()
+	fmt.Printf("🚀 %d benchmark, %d ısınma + %d ölçüm\n", len(selected), *warmup, *iterations)
+
+	type row struct {
+		name, unit, checksum string
+		nsPerOp              float64
+		median               time.Duration
+		failed               int64
+	}
+	var rows []row
+	for _, b := range selected {
+		fmt.Printf("\n▶️  %s\n", b.Name())
+		b.Setup()
+		for i := 0; i < *warmup; i++ {
+			b.Reset()
+			b.Run()
+		}
+
+		latency := benchkit.NewHistogram()
+		var failed int64
+		var checksum string
+		var measured time.Duration
+		started := time.Now()
+		for i := 0; i < *iterations; i++ {
+			b.Reset()
+			runStart := time.Now()
+			b.Run()
+			elapsed := time.Since(runStart)
+			latency.Record(elapsed)
+			measured += elapsed
+
+			sum, err := b.Verify()
+			if err != nil {
+				failed++
+				fmt.Printf("  ❌ %v\n", err)
+				continue
+			}
+			checksum = sum
+		}
+
+		ops, unit := b.Ops()
+		summary := latency.Summary()
+		nsPerOp := float64(summary.P50.Nanoseconds()) / float64(ops)
+		// Süre yalnızca Run'ların toplamıdır: Reset ve Verify throughput'a karışmaz
+		result := benchkit.NewResult("cross-language", "compute", started, measured, ops*int64(*iterations))
+		result.Variant = "go/" + b.Name()
+		result.Params = map[string]string{"language": "go", "iterations": strconv.Itoa(*iterations),
+			"warmup": strconv.Itoa(*warmup), "unit": unit, "checksum": checksum,
+			"nsPerOp": strconv.FormatFloat(nsPerOp, 'f', 4, 64)}
+		for k, v := range b.Params() {
+			result.Params[k] = v
+		}
+		result.Latency = summary.Millis()
+		result.Histogram = benchkit.HistogramMs(latency)
+		result.Errors = failed
+		result.Host = &host
+		fmt.Printf("  📊 medyan %v, %.4f ns/%s, checksum %s\n", summary.P50.Round(time.Microsecond), nsPerOp, unit, checksum)
+		rows = append(rows, row{name: b.Name(), unit: unit, checksum: checksum, nsPerOp: nsPerOp, median: summary.P50, failed: failed})
+
+		if *jsonPath != "" {
+			if err := benchkit.AppendJSONL(*jsonPath, result); err != nil {
+				fmt.Printf("⚠️  Sonuç yazılamadı: %v\n", err)
+			}
+		}
+	}
+
+	fmt.Printf("\n=== COMPUTE (go) ===\n")
+	fmt.Printf("  🖥️  %s\n", host)
+	fmt.Printf("  %-14s %12s %14s %-11s %6s  %s\n", "benchmark", "medyan", "ns/op", "op", "hata", "checksum")
+	for _, r := range rows {
+		fmt.Printf("  %-14s %12v %14.4f %-11s %6d  %s\n", r.name, r.median.Round(time.Microsecond), r.nsPerOp, r.unit, r.failed, r.checksum)
+	}
+	if *jsonPath != "" {
+		fmt.Printf("\n📄 %d sonuç %s dosyasına eklendi\n", len(rows), *jsonPath)
+	}
+}
+
+// selectBenchmarks - "all" veya virgülle ayrılmış adlar (registry sırasıyla)
+func selectBenchmarks(names string) ([]Benchmark, error) {
+	if names == "all" {
+		return registry, nil
+	}
+	wanted := map[string]bool{}
+	for _, name := range strings.Split(names, ",") {
+		wanted[strings.TrimSpace(name)] = true
+	}
+	var selected []Benchmark
+	for _, b := range registry {
+		if wanted[b.Name()] {
+			selected = append(selected, b)
+			delete(wanted, b.Name())
+		}
+	}
+	if len(wanted) > 0 {
+		all := make([]string, len(registry))
+		for i, b := range registry {
+			all[i] = b.Name()
+		}
+		for name := range wanted {
+			return nil, fmt.Errorf("bilinmeyen benchmark %q (%s)", name, strings.Join(all, ", "))
+		}
+	}
+	return selected, nil
+}
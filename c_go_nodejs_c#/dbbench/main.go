@@ -0,0 +1,191 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"benchkit"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/version"
+)
+
+// dbbench - perfdb.orders üzerinde dillerin MongoDB sürücülerinin karşılaştırması
+// mongo-perf-lab'ın ürettiği orders koleksiyonu (generator.go) aynı yük altında her dilin
+// resmi sürücüsüyle sorgulanır. Sunucu ve veri aynı olduğundan fark sürücüdedir: Bağlantı
+// havuzu, BSON kodlama/çözme ve dilin çalışma ortamı. Her işlemde toplam süre ile sürücünün
+// komut olaylarındaki gidiş-dönüş süresi (sunucu + ağ) ayrı tutulur; aradaki fark "sürücü
+// payı" olarak raporlanır.
+//
+// Diğer dillerin karşılığı PROTOCOL.md'ye uymalıdır: Aynı _id havuzu, aynı sorgu sırası,
+// aynı havuz boyutu, dokümanların dilin genel doküman tipine tam çözülmesi ve aynı doğrulama
+// geçişi. Doğrulama geçişinin sonucu checksum olarak yazılır; aggregate farklı checksum'ı
+// (farklı veri veya farklı sorgu) işaretler.
+//
+// Sonuçlar -json ile ortak formatta (benchmark "db", variant "go/point-c64") yazılır.
+//
+// KULLANIM (c_go_nodejs_c# klasöründe; önce mongo-perf-lab generator'ı ile veri üretin):
+//
+//	go run ./dbbench
+//	go run ./dbbench -workloads point,batch -c 1,16,64 -d 20s -json results.jsonl
+//	DBBENCH_PASSWORD=... go run ./dbbench -uri mongodb://staging:27017 -user perflab
+func main() {
+	uri := flag.String("uri", "mongodb://localhost:27017", "Bağlantı adresi (şifre içermemeli, bkz. -user)")
+	user := flag.String("user", "", "Kullanıcı adı (şifre DBBENCH_PASSWORD ortam değişkeninden okunur)")
+	database := flag.String("db", "perfdb", "Veritabanı")
+	collection := flag.String("collection", "orders", "Koleksiyon")
+	names := flag.String("workloads", "point,range,batch", "İş yükleri (virgülle ayrılmış)")
+	levelsFlag := flag.String("c", "1,16,64", "Eşzamanlılık seviyeleri (havuz boyutu = eşzamanlılık)")
+	duration := flag.Duration("d", 10*time.Second, "İş yükü ve seviye başına ölçüm süresi")
+	warmup := flag.Duration("warmup", 2*time.Second, "Her ölçümden önce ısınma süresi")
+	poolSize := flag.Int("ids", 10000, "_id havuzunun boyutu")
+	jsonPath := flag.String("json", "", "Sonuçların ekleneceği JSON Lines dosyası (benchkit formatı)")
+	flag.Parse()
+
+	var selected []Workload
+	for _, name := range strings.Split(*names, ",") {
+		i := slices.IndexFunc(workloads, func(w Workload) bool { return w.Name == name })
+		if i < 0 {
+			fmt.Printf("❌ -workloads: bilinmeyen iş yükü %q (point, range, batch)\n", name)
+			os.Exit(2)
+		}
+		selected = append(selected, workloads[i])
+	}
+	var levels []int
+	for _, item := range strings.Split(*levelsFlag, ",") {
+		c, err := strconv.Atoi(strings.TrimSpace(item))
+		if err != nil || c <= 0 {
+			fmt.Printf("❌ -c: %q pozitif tam sayı değil\n", item)
+			os.Exit(2)
+		}
+		levels = append(levels, c)
+	}
+
+	host := benchkit.CollectHostInfo()
+	fmt.Printf("🖥️  %s\n", host)
+	runtimeInfo := fmt.Sprintf("%s, mongo-go-driver %s", runtime.Version(), version.Driver)
+	fmt.Printf("🔌 %s (%s.%s), %s\n", *uri, *database, *collection, runtimeInfo)
+
+	timer := &commandTimer{}
+	var runs []Run
+	checksums := map[string]string{}
+	for _, c := range levels {
+		// Havuz boyutu eşzamanlılıktır ve ölçümden önce doldurulur (PROTOCOL.md): Bağlantı
+		// kurulumu ölçüme karışmaz, her dil aynı sayıda bağlantıyla çalışır
+		client, err := connect(*uri, *user, c, timer)
+		if err != nil {
+			fmt.Printf("❌ Bağlantı: %v\n", err)
+			os.Exit(1)
+		}
+		col := client.Database(*database).Collection(*collection)
+		ctx := context.Background()
+		ids, err := loadIDPool(ctx, col, *poolSize)
+		if err != nil || len(ids) == 0 {
+			fmt.Printf("❌ _id havuzu alınamadı (koleksiyon boş mu? önce generator): %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("\n⚙️  c=%d (havuz %d bağlantı, %d _id)\n", c, c, len(ids))
+		for _, wl := range selected {
+			if _, ok := checksums[wl.Name]; !ok {
+				total, docs, err := wl.Verify(ctx, col, ids)
+				if err != nil {
+					fmt.Printf("❌ %s doğrulama: %v\n", wl.Name, err)
+					os.Exit(1)
+				}
+				checksums[wl.Name] = fmt.Sprintf("docs=%d,total=%d", docs, total)
+			}
+			if *warmup > 0 {
+				runWorkload(col, ids, wl, c, *warmup, timer)
+			}
+			run := runWorkload(col, ids, wl, c, *duration, timer)
+			runs = append(runs, run)
+
+			s := run.Latency.Summary()
+			fmt.Printf("   %-6s %9.0f ops/sn  p50 %-10v p99 %-10v komut %-10v sürücü payı %-10v hata %d\n", wl.Name, run.OpsPerSec(),
+				s.P50.Round(time.Microsecond), s.P99.Round(time.Microsecond), run.CommandMean().Round(time.Microsecond),
+				run.DriverOverhead().Round(time.Microsecond), run.Failed)
+
+			if *jsonPath != "" {
+				r := benchkit.NewResult("cross-language", "db", run.Started, run.Duration, run.Ops-run.Failed)
+				r.Variant = fmt.Sprintf("go/%s-c%d", wl.Name, c)
+				r.Params = map[string]string{
+					"language":         "go",
+					"runtime":          runtimeInfo,
+					"workload":         wl.Name,
+					"concurrency":      strconv.Itoa(c),
+					"collection":       *database + "." + *collection,
+					"idPool":           strconv.Itoa(len(ids)),
+					"checksum":         checksums[wl.Name],
+					"docs":             strconv.FormatInt(run.Docs, 10),
+					"commandMeanUs":    strconv.FormatFloat(float64(run.CommandMean())/1e3, 'f', 1, 64),
+					"driverOverheadUs": strconv.FormatFloat(float64(run.DriverOverhead())/1e3, 'f', 1, 64),
+				}
+				r.Latency = s.Millis()
+				r.Histogram = benchkit.HistogramMs(run.Latency)
+				r.Errors = run.Failed
+				r.Host = &host
+				if err := benchkit.AppendJSONL(*jsonPath, r); err != nil {
+					fmt.Printf("   ⚠️  Sonuç yazılamadı: %v\n", err)
+				}
+			}
+		}
+		client.Disconnect(ctx)
+	}
+
+	fmt.Printf("\n=== ÖZET (go, %s) ===\n", runtimeInfo)
+	fmt.Printf("  %-8s %6s %10s %10s %10s %12s %12s %8s\n", "iş yükü", "c", "ops/sn", "p50", "p99", "komut ort.", "sürücü payı", "hata")
+	for _, wl := range selected {
+		for _, run := range runs {
+			if run.Workload != wl.Name {
+				continue
+			}
+			s := run.Latency.Summary()
+			fmt.Printf("  %-8s %6d %10.0f %10v %10v %12v %12v %8d\n", run.Workload, run.Concurrency, run.OpsPerSec(),
+				s.P50.Round(time.Microsecond), s.P99.Round(time.Microsecond), run.CommandMean().Round(time.Microsecond),
+				run.DriverOverhead().Round(time.Microsecond), run.Failed)
+		}
+		fmt.Printf("  %-8s checksum %s\n", "", checksums[wl.Name])
+	}
+	if *jsonPath != "" {
+		fmt.Printf("\n📄 %d sonuç %s dosyasına eklendi\n", len(runs), *jsonPath)
+	}
+}
+
+// connect - PROTOCOL.md'deki istemci ayarları: Havuz = eşzamanlılık (min = max), sıkıştırma yok,
+// primary'den okuma. Havuz, her bağlantıya bir ping gönderilerek doldurulur
+func connect(uri, user string, poolSize int, timer *commandTimer) (*mongo.Client, error) {
+	opts := options.Client().ApplyURI(uri).
+		SetMaxPoolSize(uint64(poolSize)).
+		SetMinPoolSize(uint64(poolSize)).
+		SetCompressors(nil).
+		SetMonitor(timer.monitor())
+	if user != "" {
+		opts.SetAuth(options.Credential{Username: user, Password: os.Getenv("DBBENCH_PASSWORD")})
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	client, err := mongo.Connect(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	// Eşzamanlı ping'ler havuzdaki her bağlantıyı en az bir kez kullanır (kurulum + handshake)
+	errs := make(chan error, poolSize)
+	for i := 0; i < poolSize; i++ {
+		go func() { errs <- client.Ping(ctx, nil) }()
+	}
+	for i := 0; i < poolSize; i++ {
+		if err := <-errs; err != nil {
+			client.Disconnect(context.Background())
+			return nil, err
+		}
+	}
+	return client, nil
+}
@@ -0,0 +1,295 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"benchkit"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/event"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// workload.go - PROTOCOL.md'deki iş yükleri
+// Her iş yükü bir işlemin (op) nasıl yapıldığını ve w. worker'ın j. işleminin hangi
+// parametreyle yapılacağını tanımlar. Sıra deterministiktir: Aynı eşzamanlılıkta her dil
+// aynı sorguları aynı dağılımla gönderir.
+
+// orderStatuses - orders.status değerleri (mongo-perf-lab/app/orders.go)
+var orderStatuses = []string{"PAID", "CANCELLED", "PENDING"}
+
+// rangeLimit, batchSize - range ve batch iş yüklerinin sabitleri (PROTOCOL.md)
+const (
+	rangeLimit = 20
+	batchSize  = 50
+)
+
+// Workload - Tek iş yükü
+type Workload struct {
+	Name        string
+	Description string
+	// Op - w. worker'ın j. işlemi; çözülen doküman sayısını döndürür
+	Op func(ctx context.Context, col *mongo.Collection, ids []any, w, j, workers int) (int, error)
+	// Verify - Doğrulama geçişi: PROTOCOL.md'deki sabit sorgulardaki total toplamı ve doküman sayısı
+	Verify func(ctx context.Context, col *mongo.Collection, ids []any) (total int64, docs int, err error)
+}
+
+// workloads - Ölçülebilir iş yükleri (PROTOCOL.md ile aynı sırada)
+var workloads = []Workload{
+	{
+		Name:        "point",
+		Description: "findOne({_id}) - tek doküman",
+		Op: func(ctx context.Context, col *mongo.Collection, ids []any, w, j, workers int) (int, error) {
+			_, err := findOne(ctx, col, ids[(w+j*workers)%len(ids)])
+			return 1, err
+		},
+		Verify: func(ctx context.Context, col *mongo.Collection, ids []any) (int64, int, error) {
+			var total int64
+			n := min(100, len(ids))
+			for i := 0; i < n; i++ {
+				doc, err := findOne(ctx, col, ids[i])
+				if err != nil {
+					return 0, 0, err
+				}
+				total += intField(doc, "total")
+			}
+			return total, n, nil
+		},
+	},
+	{
+		Name:        "range",
+		Description: fmt.Sprintf("find({status}).sort({createdAt: -1}).limit(%d)", rangeLimit),
+		Op: func(ctx context.Context, col *mongo.Collection, ids []any, w, j, workers int) (int, error) {
+			docs, err := findRange(ctx, col, orderStatuses[(w+j)%len(orderStatuses)])
+			return len(docs), err
+		},
+		Verify: func(ctx context.Context, col *mongo.Collection, ids []any) (int64, int, error) {
+			var total int64
+			count := 0
+			for _, status := range orderStatuses {
+				docs, err := findRange(ctx, col, status)
+				if err != nil {
+					return 0, 0, err
+				}
+				for _, doc := range docs {
+					total += intField(doc, "total")
+				}
+				count += len(docs)
+			}
+			return total, count, nil
+		},
+	},
+	{
+		Name:        "batch",
+		Description: fmt.Sprintf("find({_id: {$in: %d id}})", batchSize),
+		Op: func(ctx context.Context, col *mongo.Collection, ids []any, w, j, workers int) (int, error) {
+			start := ((w + j*workers) * batchSize) % len(ids)
+			docs, err := findBatch(ctx, col, batchIDs(ids, start))
+			return len(docs), err
+		},
+		Verify: func(ctx context.Context, col *mongo.Collection, ids []any) (int64, int, error) {
+			docs, err := findBatch(ctx, col, batchIDs(ids, 0))
+			if err != nil {
+				return 0, 0, err
+			}
+			var total int64
+			for _, doc := range docs {
+				total += intField(doc, "total")
+			}
+			return total, len(docs), nil
+		},
+	},
+}
+
+// findOne - Doküman genel tipe (bson.M) çözülür: Diğer dillerde JS nesnesi, BsonDocument
+func findOne(ctx context.Context, col *mongo.Collection, id any) (bson.M, error) {
+	var doc bson.M
+	err := col.FindOne(ctx, bson.M{"_id": id}).Decode(&doc)
+	return doc, err
+}
+
+func findRange(ctx context.Context, col *mongo.Collection, status string) ([]bson.M, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}}).SetLimit(rangeLimit)
+	return findAll(ctx, col, bson.M{"status": status}, opts)
+}
+
+func findBatch(ctx context.Context, col *mongo.Collection, ids []any) ([]bson.M, error) {
+	return findAll(ctx, col, bson.M{"_id": bson.M{"$in": ids}}, options.Find())
+}
+
+func findAll(ctx context.Context, col *mongo.Collection, filter bson.M, opts *options.FindOptions) ([]bson.M, error) {
+	cursor, err := col.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	var docs []bson.M
+	err = cursor.All(ctx, &docs)
+	return docs, err
+}
+
+// batchIDs - Havuzun start'tan başlayan batchSize'lık dilimi (sonda başa sarar)
+func batchIDs(ids []any, start int) []any {
+	out := make([]any, min(batchSize, len(ids)))
+	for i := range out {
+		out[i] = ids[(start+i)%len(ids)]
+	}
+	return out
+}
+
+// intField - Sayısal alanın değeri (int32, int64 veya double saklanmış olabilir; yoksa 0)
+func intField(doc bson.M, key string) int64 {
+	switch v := doc[key].(type) {
+	case int32:
+		return int64(v)
+	case int64:
+		return v
+	case float64:
+		return int64(v)
+	}
+	return 0
+}
+
+// loadIDPool - _id'ye göre sıralı ilk n _id (her dil aynı havuzu alır; $sample rastgeledir)
+func loadIDPool(ctx context.Context, col *mongo.Collection, n int) ([]any, error) {
+	opts := options.Find().SetProjection(bson.M{"_id": 1}).SetSort(bson.M{"_id": 1}).SetLimit(int64(n))
+	docs, err := findAll(ctx, col, bson.M{}, opts)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]any, len(docs))
+	for i, doc := range docs {
+		ids[i] = doc["_id"]
+	}
+	return ids, nil
+}
+
+// commandTimer - Sürücünün komut olaylarından gidiş-dönüş süreleri
+// CommandSucceededEvent.Duration komutun gönderilmesinden yanıtın okunmasına kadardır
+// (sunucu + ağ). İşlemin toplam süresinden farkı sürücünün payıdır: Havuzdan bağlantı alma,
+// sorgunun BSON'a kodlanması ve yanıtın dile özgü dokümanlara çözülmesi
+type commandTimer struct {
+	nanos    atomic.Int64
+	commands atomic.Int64
+}
+
+func (t *commandTimer) monitor() *event.CommandMonitor {
+	record := func(d time.Duration) {
+		t.nanos.Add(int64(d))
+		t.commands.Add(1)
+	}
+	return &event.CommandMonitor{
+		Succeeded: func(_ context.Context, e *event.CommandSucceededEvent) { record(e.Duration) },
+		Failed:    func(_ context.Context, e *event.CommandFailedEvent) { record(e.Duration) },
+	}
+}
+
+func (t *commandTimer) reset() {
+	t.nanos.Store(0)
+	t.commands.Store(0)
+}
+
+// Run - Bir iş yükünün bir eşzamanlılıktaki sonucu
+type Run struct {
+	Workload    string
+	Concurrency int
+	Started     time.Time
+	Duration    time.Duration
+	Ops         int64
+	Failed      int64
+	Docs        int64               // Çözülen doküman sayısı
+	Latency     *benchkit.Histogram // Başarılı işlemler
+	OpTime      time.Duration       // Başarılı ve başarısız tüm işlemlerin toplam süresi
+	CommandTime time.Duration       // Aynı süredeki komutların toplam gidiş-dönüşü (bkz. commandTimer)
+	Commands    int64
+}
+
+// OpsPerSec - Başarılı işlem / saniye
+func (r Run) OpsPerSec() float64 {
+	if r.Duration <= 0 {
+		return 0
+	}
+	return float64(r.Ops-r.Failed) / r.Duration.Seconds()
+}
+
+// DriverOverhead - İşlem başına sürücü payı (toplam süre - komut gidiş-dönüşü)
+func (r Run) DriverOverhead() time.Duration {
+	if r.Ops == 0 {
+		return 0
+	}
+	return max(0, r.OpTime-r.CommandTime) / time.Duration(r.Ops)
+}
+
+// CommandMean - Komut başına ortalama gidiş-dönüş (sunucu + ağ)
+func (r Run) CommandMean() time.Duration {
+	if r.Commands == 0 {
+		return 0
+	}
+	return r.CommandTime / time.Duration(r.Commands)
+}
+
+// runWorkload - concurrency worker ile duration boyunca kapalı döngü
+func runWorkload(col *mongo.Collection, ids []any, wl Workload, concurrency int, duration time.Duration, timer *commandTimer) Run {
+	ctx, cancel := context.WithTimeout(context.Background(), duration)
+	defer cancel()
+
+	type workerResult struct {
+		latency *benchkit.Histogram
+		ops     int64
+		failed  int64
+		docs    int64
+		opTime  time.Duration
+	}
+	results := make([]workerResult, concurrency)
+
+	timer.reset()
+	start := time.Now()
+	var wg sync.WaitGroup
+	for w := range results {
+		wg.Add(1)
+		go func(w int, r *workerResult) {
+			defer wg.Done()
+			r.latency = benchkit.NewHistogram()
+			for j := 0; ctx.Err() == nil; j++ {
+				opStart := time.Now()
+				docs, err := wl.Op(ctx, col, ids, w, j, concurrency)
+				elapsed := time.Since(opStart)
+				// Süre dolduğu için iptal edilen son işlem sayılmaz
+				if ctx.Err() != nil && errors.Is(err, context.DeadlineExceeded) {
+					break
+				}
+				r.ops++
+				r.opTime += elapsed
+				if err != nil {
+					r.failed++
+					continue
+				}
+				r.docs += int64(docs)
+				r.latency.Record(elapsed)
+			}
+		}(w, &results[w])
+	}
+	wg.Wait()
+
+	run := Run{
+		Workload:    wl.Name,
+		Concurrency: concurrency,
+		Started:     start,
+		Duration:    time.Since(start),
+		Latency:     benchkit.NewHistogram(),
+		CommandTime: time.Duration(timer.nanos.Load()),
+		Commands:    timer.commands.Load(),
+	}
+	for _, r := range results {
+		run.Latency.Merge(r.latency)
+		run.Ops += r.ops
+		run.Failed += r.failed
+		run.Docs += r.docs
+		run.OpTime += r.opTime
+	}
+	return run
+}
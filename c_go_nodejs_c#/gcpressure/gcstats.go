@@ -0,0 +1,97 @@
+package main
+
+import (
+	"math"
+	"runtime/metrics"
+	"time"
+)
+
+// gcStats - runtime/metrics'ten GC duraklama histogramı ve CPU sınıfları
+// MemStats.PauseNs son 256 duraklamayı tutar; milyonlarca nesnelik bir çalıştırmada eskileri
+// kaybolur. Histogram tüm duraklamaları sayar ama çözünürlüğü kova genişliğiyle sınırlıdır
+type gcStats struct {
+	pauses   *metrics.Float64Histogram
+	gcCPU    float64 // GC'nin harcadığı CPU-saniye (arka plan işçileri + yardımlar + duraklamalar)
+	totalCPU float64
+}
+
+var gcMetrics = []string{
+	"/sched/pauses/total/gc:seconds",
+	"/cpu/classes/gc/total:cpu-seconds",
+	"/cpu/classes/total:cpu-seconds",
+}
+
+func readGCStats() gcStats {
+	samples := make([]metrics.Sample, len(gcMetrics))
+	for i, name := range gcMetrics {
+		samples[i].Name = name
+	}
+	metrics.Read(samples)
+
+	var s gcStats
+	if samples[0].Value.Kind() == metrics.KindFloat64Histogram {
+		s.pauses = samples[0].Value.Float64Histogram()
+	}
+	if samples[1].Value.Kind() == metrics.KindFloat64 {
+		s.gcCPU = samples[1].Value.Float64()
+	}
+	if samples[2].Value.Kind() == metrics.KindFloat64 {
+		s.totalCPU = samples[2].Value.Float64()
+	}
+	return s
+}
+
+// cpuPercentSince - İki okuma arasında CPU'nun yüzde kaçını GC harcadı
+// runtime/metrics'in CPU sınıfları tahmindir ve GC döngüsü sonunda güncellenir
+func (s gcStats) cpuPercentSince(before gcStats) float64 {
+	total := s.totalCPU - before.totalCPU
+	if total <= 0 {
+		return 0
+	}
+	return (s.gcCPU - before.gcCPU) / total * 100
+}
+
+// pauseSummary - Duraklama dağılımının özeti (kova üst sınırları)
+type pauseSummary struct {
+	count         uint64
+	p50, p99, max time.Duration
+}
+
+// pausesSince - before'dan sonraki duraklamaların yüzdelikleri
+func (s gcStats) pausesSince(before gcStats) pauseSummary {
+	if s.pauses == nil || before.pauses == nil {
+		return pauseSummary{}
+	}
+	counts := make([]uint64, len(s.pauses.Counts))
+	var sum pauseSummary
+	for i, c := range s.pauses.Counts {
+		counts[i] = c - before.pauses.Counts[i]
+		sum.count += counts[i]
+	}
+	if sum.count == 0 {
+		return sum
+	}
+	upper := func(i int) time.Duration {
+		// Buckets[i+1] kovanın üst sınırıdır; son kova +Inf ise alt sınır kullanılır
+		bound := s.pauses.Buckets[i+1]
+		if math.IsInf(bound, 1) {
+			bound = s.pauses.Buckets[i]
+		}
+		return time.Duration(bound * float64(time.Second))
+	}
+	var seen uint64
+	for i, c := range counts {
+		if c == 0 {
+			continue
+		}
+		seen += c
+		if sum.p50 == 0 && seen*100 >= sum.count*50 {
+			sum.p50 = upper(i)
+		}
+		if sum.p99 == 0 && seen*100 >= sum.count*99 {
+			sum.p99 = upper(i)
+		}
+		sum.max = upper(i)
+	}
+	return sum
+}
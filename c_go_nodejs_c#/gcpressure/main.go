@@ -0,0 +1,198 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"time"
+
+	"benchkit"
+)
+
+// gcpressure - Diller arası çöp toplama (GC) karşılaştırması
+// Çok sayıda küçük, kısa ömürlü nesne ve bunların arasında yaşayan daha uzun ömürlü bir küme:
+// Web sunucularının tipik bellek profili. Her dilde aynı iş yapılır:
+//
+//	for batch := 0; batch < objects/batch; batch++ {
+//	    batch büyüklüğünde nesne üret: {id, name string, tags []string (4), attrs map[string]int (8)}
+//	    nesneleri id → nesne map'ine koy, map'teki attrs değerlerini topla (checksum)
+//	    map'i son live batch'lik pencereye ekle, en eskisini bırak
+//	}
+//
+// Pencere (-live) yaşayan heap'i belirler: Nesneler ya hemen (genç nesil) ya da live batch sonra
+// (yaşlı nesil) ölür. Ölçülenler:
+//   - Throughput: Nesne/sn ve batch süresi dağılımı (GC duraklamaları ve yardımları p99'a yansır)
+//   - GC: Döngü sayısı, duraklama dağılımı (runtime/metrics /sched/pauses/total/gc, kova
+//     çözünürlüğünde), GC'nin CPU payı, tepe heap
+//
+// Node (--trace-gc, perf_hooks 'gc' girdileri) ve C# (GC.CollectionCount, GC.GetTotalPauseDuration)
+// aynı parametrelerle çalıştırıldığında checksum aynı çıkmalıdır. Sonuçlar benchmark "gc",
+// variant "go/gogc=100" olarak ortak formatta yazılır.
+//
+// KULLANIM (c_go_nodejs_c# klasöründe):
+//
+//	go run ./gcpressure
+//	go run ./gcpressure -objects 20000000 -live 500 -gogc 200 -json results.jsonl
+//	go run ./gcpressure -gogc off -memlimit 512MiB
+func main() {
+	objects := flag.Int("objects", 10_000_000, "Üretilecek toplam nesne")
+	batch := flag.Int("batch", 1000, "Batch başına nesne (bir map)")
+	live := flag.Int("live", 100, "Yaşatılan son batch sayısı (yaşayan heap ≈ live × batch nesne)")
+	gogc := flag.String("gogc", "100", "GOGC: yüzde veya off")
+	memLimit := flag.String("memlimit", "", "GOMEMLIMIT (ör: 512MiB; boş: sınırsız)")
+	jsonPath := flag.String("json", "", "Sonuçların ekleneceği JSON Lines dosyası (benchkit formatı)")
+	flag.Parse()
+
+	if *objects <= 0 || *batch <= 0 || *live < 0 {
+		fmt.Println("❌ objects ve batch pozitif, live negatif olmayan sayı olmalı")
+		os.Exit(2)
+	}
+	if err := configureGC(*gogc, *memLimit); err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(2)
+	}
+	batches := *objects / *batch
+
+	host := benchkit.CollectHostInfo()
+	fmt.Printf("🚀 %d nesne (%d batch × %d), %d batch yaşatılır, GOGC=%s\n", batches**batch, batches, *batch, *live, *gogc)
+
+	memBefore := benchkit.ReadMem(true)
+	gcBefore := readGCStats()
+	peak := benchkit.StartPeakSampler(50 * time.Millisecond)
+	latency := benchkit.NewHistogram()
+	window := make([]map[int]*object, 0, *live+1)
+	var checksum int64
+
+	start := time.Now()
+	for b := 0; b < batches; b++ {
+		batchStart := time.Now()
+		m := make(map[int]*object, *batch)
+		for i := 0; i < *batch; i++ {
+			id := b**batch + i
+			m[id] = newObject(id)
+		}
+		for _, o := range m {
+			for _, v := range o.attrs {
+				checksum += int64(v)
+			}
+		}
+		window = append(window, m)
+		if len(window) > *live {
+			window[0] = nil
+			window = window[1:]
+		}
+		latency.Record(time.Since(batchStart))
+	}
+	elapsed := time.Since(start)
+
+	peakHeap := peak.Stop()
+	gcAfter := readGCStats()
+	memUsage := benchkit.MemDelta(memBefore, benchkit.ReadMem(false))
+	memUsage.PeakHeap = peakHeap
+	pauses := gcAfter.pausesSince(gcBefore)
+	runtime.KeepAlive(window)
+
+	total := int64(batches * *batch)
+	result := benchkit.NewResult("cross-language", "gc", start, elapsed, total)
+	result.Variant = "go/gogc=" + *gogc
+	result.Params = map[string]string{"language": "go", "objects": strconv.FormatInt(total, 10),
+		"batch": strconv.Itoa(*batch), "live": strconv.Itoa(*live), "gogc": *gogc,
+		"checksum":     strconv.FormatInt(checksum, 10),
+		"gcCpuPercent": strconv.FormatFloat(gcAfter.cpuPercentSince(gcBefore), 'f', 2, 64),
+		"pauseP50Ms":   strconv.FormatFloat(benchkit.Millis(pauses.p50), 'f', 3, 64),
+		"pauseP99Ms":   strconv.FormatFloat(benchkit.Millis(pauses.p99), 'f', 3, 64),
+		"pauseMaxMs":   strconv.FormatFloat(benchkit.Millis(pauses.max), 'f', 3, 64),
+	}
+	if *memLimit != "" {
+		result.Params["memlimit"] = *memLimit
+	}
+	result.Latency = latency.Summary().Millis()
+	result.Histogram = benchkit.HistogramMs(latency)
+	result.Memory = &memUsage
+	result.Host = &host
+	result.WriteText(os.Stdout)
+	fmt.Printf("  🗑️  GC:        %d döngü, %d duraklama: p50 %v, p99 %v, max %v (toplam %v)\n", memUsage.NumGC, pauses.count,
+		pauses.p50, pauses.p99, pauses.max, memUsage.GCPause)
+	fmt.Printf("  🧮 GC CPU:    %%%.2f, tepe heap %.1f MB, checksum %d\n", gcAfter.cpuPercentSince(gcBefore),
+		float64(peakHeap)/(1024*1024), checksum)
+
+	if *jsonPath != "" {
+		if err := benchkit.AppendJSONL(*jsonPath, result); err != nil {
+			fmt.Printf("⚠️  Sonuç yazılamadı: %v\n", err)
+			return
+		}
+		fmt.Printf("\n📄 Sonuç %s dosyasına eklendi\n", *jsonPath)
+	}
+}
+
+// object - Tipik bir kayıt: Birkaç string, küçük bir dilim ve küçük bir map
+type object struct {
+	id    int
+	name  string
+	tags  []string
+	attrs map[string]int
+}
+
+// attrKeys - Her nesnede aynı 8 anahtar (string'ler paylaşılır, yalnızca map'ler ayrılır)
+var attrKeys = [8]string{"a", "b", "c", "d", "e", "f", "g", "h"}
+
+// newObject - id'den deterministik nesne: attrs[k] = (id + k'nın sırası) % 100
+func newObject(id int) *object {
+	o := &object{
+		id:    id,
+		name:  "order-" + strconv.Itoa(id),
+		tags:  make([]string, 4),
+		attrs: make(map[string]int, len(attrKeys)),
+	}
+	for i := range o.tags {
+		o.tags[i] = "t" + strconv.Itoa((id+i)%16)
+	}
+	for i, k := range attrKeys {
+		o.attrs[k] = (id + i) % 100
+	}
+	return o
+}
+
+// configureGC - GOGC ve GOMEMLIMIT'i flag'lerden uygular (ortam değişkenleriyle aynı etki)
+func configureGC(gogc, memLimit string) error {
+	if gogc == "off" {
+		debug.SetGCPercent(-1)
+	} else {
+		percent, err := strconv.Atoi(gogc)
+		if err != nil || percent <= 0 {
+			return fmt.Errorf("-gogc: pozitif yüzde veya off olmalı")
+		}
+		debug.SetGCPercent(percent)
+	}
+	if memLimit != "" {
+		limit, err := parseBytes(memLimit)
+		if err != nil {
+			return fmt.Errorf("-memlimit: %v", err)
+		}
+		debug.SetMemoryLimit(limit)
+	}
+	return nil
+}
+
+// parseBytes - "512MiB", "2GiB", "1048576" (GOMEMLIMIT'in biçimi)
+func parseBytes(s string) (int64, error) {
+	units := []struct {
+		suffix string
+		scale  int64
+	}{{"GiB", 1 << 30}, {"MiB", 1 << 20}, {"KiB", 1 << 10}, {"B", 1}}
+	scale := int64(1)
+	for _, u := range units {
+		if len(s) > len(u.suffix) && s[len(s)-len(u.suffix):] == u.suffix {
+			s, scale = s[:len(s)-len(u.suffix)], u.scale
+			break
+		}
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("geçersiz boyut (ör: 512MiB)")
+	}
+	return n * scale, nil
+}
@@ -0,0 +1,205 @@
+// Package load - /ping sunucularına kapalı döngü HTTP yükü
+// orchestrator (sunucuları kendisi başlatır) ve loadtest (çalışan sunuculara kademeli yük)
+// aynı ölçüm döngüsünü ve istemci ayarlarını kullanır; böylece iki aracın sayıları karşılaştırılabilir.
+package load
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"time"
+
+	"benchkit"
+)
+
+// ClientConfig - Bağlantı yeniden kullanımını belirleyen istemci ayarları
+// Diller arası farkın bir kısmı sunucunun değil bağlantı davranışının farkıdır: Keep-alive
+// kapalıyken her istek bir TCP el sıkışması ve sunucuda yeni bir goroutine/thread/soket demektir.
+// Karşılaştırmada bu ayarlar sabit tutulur ve sonuçla birlikte yazılır.
+type ClientConfig struct {
+	KeepAlive bool // false: Her istek yeni bağlantı (Connection: close)
+	Conns     int  // Açık bağlantı üst sınırı (0: worker başına bir bağlantı)
+	Timeout   time.Duration
+}
+
+// Label - Sonuç tablolarında ve variant'ta kullanılan kısa ad (ör: keepalive, keepalive-conns8, close)
+func (c ClientConfig) Label() string {
+	if !c.KeepAlive {
+		return "close"
+	}
+	if c.Conns > 0 {
+		return fmt.Sprintf("keepalive-conns%d", c.Conns)
+	}
+	return "keepalive"
+}
+
+// Level - Bir eşzamanlılık seviyesinin sonucu
+type Level struct {
+	Concurrency int
+	Started     time.Time
+	Duration    time.Duration
+	Requests    int64
+	Failed      int64
+	NewConns    int64               // Yeniden kullanılmayan bağlantılar (her biri bir TCP el sıkışması)
+	Errors      map[string]int64    // Hata türüne göre sayılar
+	Latency     *benchkit.Histogram // Başarılı istekler
+}
+
+// RPS - Başarılı istek / saniye
+func (l Level) RPS() float64 {
+	if l.Duration <= 0 {
+		return 0
+	}
+	return float64(l.Requests-l.Failed) / l.Duration.Seconds()
+}
+
+// ReuseRatio - Mevcut bir bağlantıyla gönderilen isteklerin oranı (0-1)
+func (l Level) ReuseRatio() float64 {
+	if l.Requests == 0 {
+		return 0
+	}
+	return max(0, 1-float64(l.NewConns)/float64(l.Requests))
+}
+
+// newClient - Ayara göre HTTP istemcisi
+// Varsayılan transport host başına 2 boşta bağlantı tutar; fazlası her istekte yeniden bağlanır
+// ve ölçüme TCP handshake karışır. Bu yüzden boşta bağlantı sınırı en az eşzamanlılık kadardır.
+// Conns eşzamanlılıktan küçükse worker'lar bağlantı bekler; bekleme gecikmeye dahildir
+func newClient(cfg ClientConfig, concurrency int) *http.Client {
+	maxIdle := max(concurrency, cfg.Conns)
+	return &http.Client{
+		Timeout: cfg.Timeout,
+		Transport: &http.Transport{
+			MaxIdleConns:        maxIdle,
+			MaxIdleConnsPerHost: maxIdle,
+			MaxConnsPerHost:     cfg.Conns,
+			DisableKeepAlives:   !cfg.KeepAlive,
+		},
+	}
+}
+
+// RunLevel - concurrency worker ile duration boyunca kapalı döngü GET url
+// Her sunucu aynı istemci ayarlarıyla ölçülür; yeni bağlantılar httptrace ile sayılır
+func RunLevel(url string, concurrency int, duration time.Duration, cfg ClientConfig) Level {
+	client := newClient(cfg, concurrency)
+	defer client.CloseIdleConnections()
+
+	ctx, cancel := context.WithTimeout(context.Background(), duration)
+	defer cancel()
+
+	type workerResult struct {
+		latency  *benchkit.Histogram
+		requests int64
+		failed   int64
+		newConns int64
+		errors   map[string]int64
+	}
+	results := make([]workerResult, concurrency)
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := range results {
+		wg.Add(1)
+		go func(w *workerResult) {
+			defer wg.Done()
+			w.latency = benchkit.NewHistogram()
+			w.errors = map[string]int64{}
+			traced := httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+				GotConn: func(info httptrace.GotConnInfo) {
+					if !info.Reused {
+						w.newConns++
+					}
+				},
+			})
+			for ctx.Err() == nil {
+				reqStart := time.Now()
+				err := get(traced, client, url)
+				// Süre dolduğu için iptal edilen son istek sayılmaz
+				if ctx.Err() != nil && errors.Is(err, context.DeadlineExceeded) {
+					break
+				}
+				w.requests++
+				if err != nil {
+					w.failed++
+					w.errors[errorKind(err)]++
+				} else {
+					w.latency.Record(time.Since(reqStart))
+				}
+			}
+		}(&results[i])
+	}
+	wg.Wait()
+
+	level := Level{
+		Concurrency: concurrency,
+		Started:     start,
+		Duration:    time.Since(start),
+		Errors:      map[string]int64{},
+		Latency:     benchkit.NewHistogram(),
+	}
+	for _, w := range results {
+		level.Latency.Merge(w.latency)
+		level.Requests += w.requests
+		level.Failed += w.failed
+		level.NewConns += w.newConns
+		for kind, n := range w.errors {
+			level.Errors[kind] += n
+		}
+	}
+	return level
+}
+
+// get - GET url; 200 dışı yanıt hatadır, gövde bağlantı yeniden kullanılsın diye okunur
+func get(ctx context.Context, client *http.Client, url string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if _, err := io.Copy(io.Discard, resp.Body); err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return statusError{resp.StatusCode}
+	}
+	return nil
+}
+
+// statusError - 200 dışı yanıt
+type statusError struct {
+	code int
+}
+
+func (e statusError) Error() string {
+	return fmt.Sprintf("http %d", e.code)
+}
+
+// errorKind - Hatanın raporda gruplanacağı kısa adı
+// Keep-alive kapalıyken yüksek hızda istemcinin geçici portları TIME_WAIT'te tükenebilir;
+// bu "connect" hatası olarak görünür, sunucunun hatası değildir
+func errorKind(err error) string {
+	var status statusError
+	var netErr net.Error
+	var opErr *net.OpError
+	switch {
+	case errors.As(err, &status):
+		return status.Error()
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return "timeout"
+	case errors.As(err, &opErr) && opErr.Op == "dial":
+		return "connect"
+	case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		return "eof"
+	default:
+		return "other"
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"os"
+
+	"benchkit"
+)
+
+// writeHgrm - Histogramı HdrHistogram'ın yüzdelik dağılım (.hgrm) biçiminde yazar (değerler ms)
+// Dosya HdrHistogram'ın çizim aracıyla (hdrhistogram.github.io/HdrHistogram/plotFiles.html)
+// diğer araçların çıktılarıyla aynı grafikte açılabilir. Her dolu kova bir satırdır; değer
+// kovanın üst sınırıdır (göreli hata %3'ün altında, bkz. benchkit.Histogram)
+func writeHgrm(path string, h *benchkit.Histogram) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(file)
+	fmt.Fprintf(w, "%12s %14s %10s %14s\n\n", "Value", "Percentile", "TotalCount", "1/(1-Percentile)")
+
+	buckets := h.Buckets()
+	total := h.Count()
+	var cumulative int64
+	var sum, sumSquares float64
+	for _, b := range buckets {
+		ms := float64(b.Upper) / 1e6
+		cumulative += int64(b.Count)
+		sum += ms * float64(b.Count)
+		sumSquares += ms * ms * float64(b.Count)
+		percentile := float64(cumulative) / float64(total)
+		if cumulative == total {
+			fmt.Fprintf(w, "%12.3f %2.12f %10d\n", ms, 1.0, cumulative)
+			continue
+		}
+		fmt.Fprintf(w, "%12.3f %2.12f %10d %14.2f\n", ms, percentile, cumulative, 1/(1-percentile))
+	}
+
+	var mean, stdDev float64
+	if total > 0 {
+		mean = sum / float64(total)
+		stdDev = math.Sqrt(max(0, sumSquares/float64(total)-mean*mean))
+	}
+	s := h.Summary()
+	fmt.Fprintf(w, "#[Mean    = %12.3f, StdDeviation   = %12.3f]\n", mean, stdDev)
+	fmt.Fprintf(w, "#[Max     = %12.3f, Total count    = %12d]\n", float64(s.Max)/1e6, total)
+	fmt.Fprintf(w, "#[Buckets = %12d, SubBuckets     = %12d]\n", len(buckets), 32)
+
+	if err := w.Flush(); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,302 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"benchkit"
+	"crosslang/load"
+)
+
+// loadtest - Çalışan /ping sunucularına kademeli (eşzamanlılık rampası) yük
+// orchestrator sunucuları tek bir eşzamanlılıkta karşılaştırır; "Go vs Node vs C# HTTP
+// throughput" sayısının ne kadarının sunucudan, ne kadarının bağlantı davranışından geldiği
+// orada görünmez. loadtest her sunucuyu her eşzamanlılık seviyesinde ve her bağlantı
+// modunda aynı istemciyle ölçer:
+//
+//	keepalive  Bağlantılar yeniden kullanılır (worker başına bir bağlantı veya -conns kadar)
+//	close      Her istek yeni TCP bağlantısı: Sunucunun accept/bağlantı kurulum maliyeti ölçülür
+//
+// Her ölçümde yeni açılan bağlantılar sayılır (httptrace): Keep-alive'da bu sayı eşzamanlılık
+// kadar kalmalıdır; fazlası sunucunun bağlantıları kapattığını gösterir (ör: istek sayısı
+// sınırı, Connection: close) ve RPS farkı sunucunun değil bağlantı kurulumunun farkıdır.
+//
+// Gecikmeler sabit bellekli log-lineer histogramda (HDR benzeri, göreli hata <%3) tutulur;
+// -hgrm ile her ölçüm HdrHistogram .hgrm dosyası olarak da yazılır. Bir sunucunun hata oranı
+// -max-errors'ı geçerse o moddaki daha yüksek seviyeleri atlanır (doymuş sayılır).
+//
+// Sunucular önceden başlatılmış olmalıdır (ör: go run ./cmd/server, node server.js).
+//
+// KULLANIM (c_go_nodejs_c# klasöründe):
+//
+//	go run ./loadtest
+//	go run ./loadtest -targets go=http://127.0.0.1:3001,node=http://127.0.0.1:3000 -c 1,16,128,512 -mode both
+//	go run ./loadtest -mode keepalive -conns 8 -c 64 -json results.jsonl -hgrm hgrm/
+func main() {
+	targetsFlag := flag.String("targets", "go=http://127.0.0.1:3001,node=http://127.0.0.1:3000,csharp=http://127.0.0.1:3002,c=http://127.0.0.1:3003",
+		"Ölçülecek sunucular (ad=URL, virgülle ayrılmış)")
+	path := flag.String("path", "/ping", "Yüklenecek yol (ör: /ping?delay=0&size=4096)")
+	levelsFlag := flag.String("c", "1,8,64,256", "Eşzamanlılık seviyeleri (virgülle ayrılmış, sırayla)")
+	mode := flag.String("mode", "both", "Bağlantı modu: keepalive, close veya both")
+	conns := flag.Int("conns", 0, "Keep-alive'da açık bağlantı üst sınırı (0: worker başına bir bağlantı)")
+	duration := flag.Duration("d", 5*time.Second, "Seviye başına ölçüm süresi")
+	warmup := flag.Duration("warmup", time.Second, "Her ölçümden önce ısınma süresi")
+	timeout := flag.Duration("timeout", 10*time.Second, "İstek zaman aşımı")
+	maxErrors := flag.Float64("max-errors", 0.05, "Bu hata oranı aşılınca sunucunun üst seviyeleri atlanır (0-1)")
+	jsonPath := flag.String("json", "", "Sonuçların ekleneceği JSON Lines dosyası (benchkit formatı)")
+	hgrmDir := flag.String("hgrm", "", "Her ölçümün .hgrm dosyasının yazılacağı klasör (boş: yazılmaz)")
+	flag.Parse()
+
+	targets, err := parseTargets(*targetsFlag)
+	if err != nil {
+		fmt.Printf("❌ -targets: %v\n", err)
+		os.Exit(2)
+	}
+	levels, err := parseLevels(*levelsFlag)
+	if err != nil {
+		fmt.Printf("❌ -c: %v\n", err)
+		os.Exit(2)
+	}
+	var clients []load.ClientConfig
+	switch *mode {
+	case "keepalive":
+		clients = []load.ClientConfig{{KeepAlive: true, Conns: *conns}}
+	case "close":
+		clients = []load.ClientConfig{{KeepAlive: false}}
+	case "both":
+		clients = []load.ClientConfig{{KeepAlive: true, Conns: *conns}, {KeepAlive: false}}
+	default:
+		fmt.Printf("❌ -mode: bilinmeyen mod %q (keepalive, close, both)\n", *mode)
+		os.Exit(2)
+	}
+	if *conns < 0 {
+		fmt.Println("❌ -conns negatif olamaz")
+		os.Exit(2)
+	}
+	if *hgrmDir != "" {
+		if err := os.MkdirAll(*hgrmDir, 0o755); err != nil {
+			fmt.Printf("❌ -hgrm: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	// Yanıt vermeyen sunucu tüm seviyelerde zaman aşımı beklemesin
+	var live []target
+	for _, t := range targets {
+		if err := probe(t.URL + *path); err != nil {
+			fmt.Printf("⚠️  %s atlandı: %v\n", t.Name, err)
+			continue
+		}
+		live = append(live, t)
+	}
+	if len(live) == 0 {
+		fmt.Println("❌ Yanıt veren sunucu yok (sunucular başlatıldı mı?)")
+		os.Exit(1)
+	}
+
+	host := benchkit.CollectHostInfo()
+	fmt.Printf("🖥️  %s\n", host)
+	fmt.Printf("🚀 %d sunucu, GET %s, c=%s, %d mod, ölçüm başına %v (+%v ısınma)\n",
+		len(live), *path, *levelsFlag, len(clients), *duration, *warmup)
+
+	var measured []measurement
+	for _, client := range clients {
+		client.Timeout = *timeout
+		saturated := map[string]bool{}
+		fmt.Printf("\n🔌 %s\n", client.Label())
+		// Seviye dışta, sunucu içte: Aynı seviyedeki ölçümler zamanca yakın kalır
+		for _, c := range levels {
+			for _, t := range live {
+				if saturated[t.Name] {
+					continue
+				}
+				url := t.URL + *path
+				if *warmup > 0 {
+					load.RunLevel(url, c, *warmup, client)
+				}
+				level := load.RunLevel(url, c, *duration, client)
+				m := measurement{target: t, client: client, level: level}
+				measured = append(measured, m)
+
+				s := level.Latency.Summary()
+				fmt.Printf("   %-8s c=%-5d %9.0f RPS  p50 %-10v p99 %-10v p99.9 %-10v yeni bağlantı %-7d hata %d%s\n",
+					t.Name, c, level.RPS(), s.P50.Round(time.Microsecond), s.P99.Round(time.Microsecond),
+					level.Latency.Percentile(99.9).Round(time.Microsecond), level.NewConns, level.Failed, formatErrors(level.Errors))
+
+				if *jsonPath != "" {
+					if err := benchkit.AppendJSONL(*jsonPath, m.result(*path, host)); err != nil {
+						fmt.Printf("   ⚠️  Sonuç yazılamadı: %v\n", err)
+					}
+				}
+				if *hgrmDir != "" {
+					name := filepath.Join(*hgrmDir, fmt.Sprintf("%s-%s-c%d.hgrm", t.Name, client.Label(), c))
+					if err := writeHgrm(name, level.Latency); err != nil {
+						fmt.Printf("   ⚠️  %v\n", err)
+					}
+				}
+				if level.Requests > 0 && float64(level.Failed)/float64(level.Requests) > *maxErrors {
+					fmt.Printf("   ⚠️  %s hata oranı %%%.1f: Üst seviyeler atlanıyor\n", t.Name, float64(level.Failed)/float64(level.Requests)*100)
+					saturated[t.Name] = true
+				}
+			}
+		}
+	}
+
+	printSummary(measured, clients, live, levels)
+	if *jsonPath != "" {
+		fmt.Printf("\n📄 %d sonuç %s dosyasına eklendi\n", len(measured), *jsonPath)
+	}
+}
+
+// target - Ölçülecek sunucu
+type target struct {
+	Name string
+	URL  string
+}
+
+// measurement - Bir sunucunun bir modda bir seviyedeki sonucu
+type measurement struct {
+	target target
+	client load.ClientConfig
+	level  load.Level
+}
+
+// result - Ortak sonuç formatı; variant "<dil>/<mod>-c<N>" olduğundan aggregate aynı mod ve
+// seviyedeki dilleri tek tabloda karşılaştırır
+func (m measurement) result(path string, host benchkit.HostInfo) benchkit.Result {
+	l := m.level
+	r := benchkit.NewResult("cross-language", "http", l.Started, l.Duration, l.Requests-l.Failed)
+	r.Variant = fmt.Sprintf("%s/%s-c%d", m.target.Name, m.client.Label(), l.Concurrency)
+	r.Params = map[string]string{
+		"language":    m.target.Name,
+		"url":         m.target.URL + path,
+		"concurrency": strconv.Itoa(l.Concurrency),
+		"client":      m.client.Label(),
+		"keepAlive":   strconv.FormatBool(m.client.KeepAlive),
+		"conns":       strconv.Itoa(m.client.Conns),
+		"newConns":    strconv.FormatInt(l.NewConns, 10),
+		"reuse":       strconv.FormatFloat(l.ReuseRatio(), 'f', 4, 64),
+		"p999Ms":      strconv.FormatFloat(benchkit.Millis(l.Latency.Percentile(99.9)), 'f', 3, 64),
+	}
+	r.Latency = l.Latency.Summary().Millis()
+	r.Histogram = benchkit.HistogramMs(l.Latency)
+	r.Errors = l.Failed
+	r.Host = &host
+	return r
+}
+
+// printSummary - Her mod için sunucu × seviye tablosu; iki mod da ölçüldüyse keep-alive kazancı
+func printSummary(measured []measurement, clients []load.ClientConfig, targets []target, levels []int) {
+	for _, client := range clients {
+		fmt.Printf("\n=== %s ===\n", client.Label())
+		fmt.Printf("  %-8s %6s %10s %10s %10s %10s %10s %10s %8s %8s\n",
+			"sunucu", "c", "RPS", "p50", "p99", "p99.9", "max", "yeni bağl.", "reuse", "hata")
+		for _, m := range measured {
+			if m.client.Label() != client.Label() {
+				continue
+			}
+			l := m.level
+			s := l.Latency.Summary()
+			fmt.Printf("  %-8s %6d %10.0f %10v %10v %10v %10v %10d %7.1f%% %8d\n", m.target.Name, l.Concurrency, l.RPS(),
+				s.P50.Round(time.Microsecond), s.P99.Round(time.Microsecond), l.Latency.Percentile(99.9).Round(time.Microsecond),
+				s.Max.Round(time.Microsecond), l.NewConns, l.ReuseRatio()*100, l.Failed)
+		}
+	}
+	if len(clients) < 2 {
+		return
+	}
+
+	// Keep-alive RPS'i / close RPS'i: Bağlantı kurulumunun sunucuya maliyeti
+	rps := map[string]map[int]map[bool]float64{}
+	for _, m := range measured {
+		if rps[m.target.Name] == nil {
+			rps[m.target.Name] = map[int]map[bool]float64{}
+		}
+		if rps[m.target.Name][m.level.Concurrency] == nil {
+			rps[m.target.Name][m.level.Concurrency] = map[bool]float64{}
+		}
+		rps[m.target.Name][m.level.Concurrency][m.client.KeepAlive] = m.level.RPS()
+	}
+	fmt.Printf("\n=== KEEP-ALIVE KAZANCI (keepalive RPS / close RPS) ===\n")
+	fmt.Printf("  %-8s", "sunucu")
+	for _, c := range levels {
+		fmt.Printf(" %9s", "c="+strconv.Itoa(c))
+	}
+	fmt.Println()
+	for _, t := range targets {
+		fmt.Printf("  %-8s", t.Name)
+		for _, c := range levels {
+			byMode := rps[t.Name][c]
+			if byMode[false] > 0 && byMode[true] > 0 {
+				fmt.Printf(" %8.2fx", byMode[true]/byMode[false])
+			} else {
+				fmt.Printf(" %9s", "-")
+			}
+		}
+		fmt.Println()
+	}
+}
+
+// formatErrors - Hata türleri (ör: " (connect 12, timeout 3)"); hata yoksa boş
+func formatErrors(errs map[string]int64) string {
+	if len(errs) == 0 {
+		return ""
+	}
+	kinds := make([]string, 0, len(errs))
+	for kind := range errs {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	parts := make([]string, len(kinds))
+	for i, kind := range kinds {
+		parts[i] = fmt.Sprintf("%s %d", kind, errs[kind])
+	}
+	return " (" + strings.Join(parts, ", ") + ")"
+}
+
+// parseTargets - "go=http://127.0.0.1:3001,node=..." listesi
+func parseTargets(s string) ([]target, error) {
+	var out []target
+	for _, item := range strings.Split(s, ",") {
+		name, url, ok := strings.Cut(strings.TrimSpace(item), "=")
+		if !ok || name == "" || url == "" {
+			return nil, fmt.Errorf("%q: ad=URL bekleniyor", item)
+		}
+		out = append(out, target{Name: name, URL: strings.TrimSuffix(url, "/")})
+	}
+	return out, nil
+}
+
+// parseLevels - "1,8,64,256" listesi
+func parseLevels(s string) ([]int, error) {
+	var out []int
+	for _, item := range strings.Split(s, ",") {
+		c, err := strconv.Atoi(strings.TrimSpace(item))
+		if err != nil || c <= 0 {
+			return nil, fmt.Errorf("%q: pozitif tam sayı bekleniyor", item)
+		}
+		out = append(out, c)
+	}
+	return out, nil
+}
+
+// probe - Tek GET: Sunucu ayakta ve 200 dönüyor mu
+func probe(url string) error {
+	client := &http.Client{Timeout: 2 * time.Second}
+	resp, err := client.Get(url)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("http %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+// Footprint - Sunucunun başlangıç süresi ve bellek kullanımı
+// RSS process grubunun toplamıdır: Node ve .NET'te çalışma ortamının (JIT, GC heap'leri)
+// kendisi de dahildir, karşılaştırılan şey kullanıcının ödediği bellektir
+type Footprint struct {
+	Startup  time.Duration // Median başlangıç süresi (bkz. -starts)
+	Starts   int
+	IdleRSS  int64 // Hazır olduktan ve -idle beklendikten sonra (bayt)
+	LoadRSS  int64 // Ölçüm boyunca ortalama
+	PeakRSS  int64 // Ölçüm boyunca en yüksek
+	Measured bool  // RSS okunabildi mi (yalnızca Linux)
+}
+
+// PerConn - Yük altındaki ek belleğin eşzamanlı bağlantı başına payı (bayt)
+// Bağlantı başına thread açan sunucuda thread yığını, goroutine'de birkaç KB'lık yığın görünür
+func (f Footprint) PerConn(concurrency int) int64 {
+	if concurrency <= 0 || f.PeakRSS < f.IdleRSS {
+		return 0
+	}
+	return (f.PeakRSS - f.IdleRSS) / int64(concurrency)
+}
+
+// rssSampler - Ölçüm boyunca process grubunun RSS'ini arka planda örnekler
+// Başlangıç/bitiş okumaları aradaki tepeyi (ör: yükün başındaki bağlantı patlaması) göremez
+type rssSampler struct {
+	pgid  int
+	mu    sync.Mutex
+	peak  int64
+	sum   int64
+	count int64
+	stop  chan struct{}
+	done  chan struct{}
+}
+
+// startRSSSampler - interval aralıkla RSS örneklemeye başlar
+func startRSSSampler(pgid int, interval time.Duration) *rssSampler {
+	s := &rssSampler{pgid: pgid, stop: make(chan struct{}), done: make(chan struct{})}
+	s.sample()
+	go func() {
+		defer close(s.done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				s.sample()
+			case <-s.stop:
+				return
+			}
+		}
+	}()
+	return s
+}
+
+func (s *rssSampler) sample() {
+	rss, err := groupRSS(s.pgid)
+	if err != nil {
+		return
+	}
+	s.mu.Lock()
+	s.peak = max(s.peak, rss)
+	s.sum += rss
+	s.count++
+	s.mu.Unlock()
+}
+
+// Stop - Örneklemeyi durdurur; ortalama ve tepe RSS'i döndürür (örnek yoksa ok false)
+func (s *rssSampler) Stop() (mean, peak int64, ok bool) {
+	close(s.stop)
+	<-s.done
+	s.sample()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.count == 0 {
+		return 0, 0, false
+	}
+	return s.sum / s.count, s.peak, true
+}
+
+// startRepeated - Sunucuyu derler ve n kez başlatır; ilk n-1 başlatma yalnızca başlangıç süresi
+// için yapılıp kapatılır, sonuncusu çalışır halde döner. Tek başlatma gürültülüdür (disk
+// önbelleği, JIT önbelleği): İlki soğuk, sonrakiler sıcak başlangıçtır
+func startRepeated(spec ServerSpec, path string, ready time.Duration, n int) (*Server, []time.Duration, error) {
+	if err := BuildServer(spec); err != nil {
+		return nil, nil, err
+	}
+	var startups []time.Duration
+	for i := 0; ; i++ {
+		server, err := StartServer(spec, path, ready)
+		if err != nil {
+			return nil, nil, err
+		}
+		startups = append(startups, server.Startup)
+		if i == n-1 {
+			return server, startups, nil
+		}
+		server.Stop()
+	}
+}
+
+// median - Sürelerin ortancası (çift sayıda ise küçük olan)
+func median(durations []time.Duration) time.Duration {
+	sorted := slices.Clone(durations)
+	slices.Sort(sorted)
+	return sorted[(len(sorted)-1)/2]
+}
+
+// mb - Bayt → MB
+func mb(bytes int64) float64 {
+	return float64(bytes) / (1024 * 1024)
+}
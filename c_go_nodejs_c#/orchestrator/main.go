@@ -0,0 +1,270 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"benchkit"
+	"crosslang/load"
+)
+
+// orchestrator - Go, Node.js, C# ve C /ping sunucularının otomatik karşılaştırması
+// Her sunucu sırayla derlenip başlatılır, /ping 200 dönene kadar beklenir, aynı yük
+// profiliyle (eşzamanlılık, süre, ısınma, istemci ayarları: bkz. load.ClientConfig) ölçülür
+// ve kapatılır. Sunucular aynı anda çalışmaz: CPU'yu paylaşmaları sıralamayı bozar. Sonunda
+// RPS'e göre sıralı tek tablo yazılır; -json ile her sunucu ortak sonuç formatında
+// (benchkit.Result) eklenir. Kademeli yük ve keep-alive karşılaştırması için bkz. loadtest.
+//
+// Tüm sunucular /ping'de 10 ms bekler: Fark, beklemenin etrafındaki yüktür (HTTP ayrıştırma,
+// zamanlayıcı, bağlantı başına goroutine/thread/callback). Yüksek eşzamanlılıkta bağlantı
+// başına thread açan C sunucusu ile event loop'lu Node arasındaki fark belirginleşir.
+// /json?n=100 ise beklemesizdir: Her istekte 100 iç içe nesne oluşturulup serileştirilir
+// (bkz. cmd/server), gerçek servislerde dilleri asıl ayıran iş budur. Her yolun yanıt gövdesinin
+// özeti checksum olarak kaydedilir; diğerlerinden farklı gövde dönen sunucu işaretlenir.
+//
+// Throughput'un yanında ayak izi de ölçülür:
+//
+//	başlangıç  Derlenmiş sunucunun başlatılmasından ilk 200 yanıtına kadar (-starts başlatmanın median'ı)
+//	RSS        Process grubunun toplamı (Linux): -idle sonra boşta, ölçüm boyunca ortalama ve tepe
+//
+// Sunucular -config ile JSON olarak verilebilir (varsayılanlar için bkz. server.go):
+//
+//	[{"name": "go", "build": "go build -o server_go ./cmd/server", "command": "exec ./server_go", "port": 3001},
+//	 {"name": "node", "command": "exec node server.js", "port": 3000}]
+//
+// Araç kurulu değilse (ör: dotnet) o sunucu atlanır ve raporda belirtilir.
+//
+// KULLANIM (c_go_nodejs_c# klasöründe):
+//
+//	go run ./orchestrator
+//	go run ./orchestrator -c 256 -d 20s -only go,node
+//	go run ./orchestrator -path "/json?n=1000" -only go,c
+//	go run ./orchestrator -config servers.json -json results.jsonl
+func main() {
+	configPath := flag.String("config", "", "Sunucu listesi (JSON; boş: go, node, csharp, c)")
+	only := flag.String("only", "", "Yalnızca bu sunucular (virgülle ayrılmış adlar)")
+	dir := flag.String("dir", ".", "Komutların çalışma dizini (config'te dir verilmeyenler için)")
+	pathList := flag.String("path", "/ping,/json?n=100", "Yüklenecek yollar (virgülle ayrılmış; her biri ayrı sıralama)")
+	concurrency := flag.Int("c", 64, "Eşzamanlı istek sayısı")
+	duration := flag.Duration("d", 10*time.Second, "Sunucu ve yol başına ölçüm süresi")
+	warmup := flag.Duration("warmup", 2*time.Second, "Ölçümden önce ısınma süresi (JIT, bağlantılar)")
+	timeout := flag.Duration("timeout", 10*time.Second, "İstek zaman aşımı")
+	keepAlive := flag.Bool("keepalive", true, "Bağlantıları yeniden kullan (false: her istek yeni bağlantı)")
+	conns := flag.Int("conns", 0, "Açık bağlantı üst sınırı (0: eşzamanlı istek başına bir bağlantı)")
+	ready := flag.Duration("ready", time.Minute, "Sunucunun hazır olması için beklenecek en uzun süre (derleme hariç)")
+	starts := flag.Int("starts", 3, "Başlangıç süresi için başlatma sayısı (median raporlanır; sonuncusu ölçülür)")
+	idle := flag.Duration("idle", 2*time.Second, "Hazır olduktan sonra boşta RSS okunmadan önce beklenecek süre")
+	jsonPath := flag.String("json", "", "Sonuçların ekleneceği JSON Lines dosyası (benchkit formatı)")
+	flag.Parse()
+
+	specs, err := loadServers(*configPath)
+	if err != nil {
+		fmt.Printf("❌ -config: %v\n", err)
+		os.Exit(2)
+	}
+	if *only != "" {
+		names := strings.Split(*only, ",")
+		specs = slices.DeleteFunc(slices.Clone(specs), func(s ServerSpec) bool { return !slices.Contains(names, s.Name) })
+		if len(specs) == 0 {
+			fmt.Printf("❌ -only: %q ile eşleşen sunucu yok\n", *only)
+			os.Exit(2)
+		}
+	}
+	if *concurrency <= 0 {
+		fmt.Println("❌ -c pozitif olmalı")
+		os.Exit(2)
+	}
+	if *conns < 0 {
+		fmt.Println("❌ -conns negatif olamaz")
+		os.Exit(2)
+	}
+	if *starts <= 0 {
+		fmt.Println("❌ -starts pozitif olmalı")
+		os.Exit(2)
+	}
+	paths := strings.Split(*pathList, ",")
+	client := load.ClientConfig{KeepAlive: *keepAlive, Conns: *conns, Timeout: *timeout}
+
+	host := benchkit.CollectHostInfo()
+	fmt.Printf("🖥️  %s\n", host)
+	fmt.Printf("🚀 %d sunucu, GET %s, c=%d, %s, yol başına %v (+%v ısınma)\n", len(specs), *pathList, *concurrency, client.Label(), *duration, *warmup)
+
+	type run struct {
+		spec     ServerSpec
+		path     string
+		level    load.Level
+		checksum string // Yanıt gövdesinin özeti: Aynı işi yapan sunucular aynı gövdeyi döner
+	}
+	var runs []run
+	footprints := map[string]Footprint{}
+	skipped := map[string]string{}
+	for _, spec := range specs {
+		if spec.Dir == "" {
+			spec.Dir = *dir
+		}
+		fmt.Printf("\n▶️  %s: %s\n", spec.Name, spec.Command)
+		if spec.Build != "" {
+			fmt.Printf("   🔨 %s\n", spec.Build)
+		}
+		server, startups, err := startRepeated(spec, paths[0], *ready, *starts)
+		if err != nil {
+			fmt.Printf("   ⚠️  Atlandı: %v\n", err)
+			skipped[spec.Name] = err.Error()
+			continue
+		}
+		fp := Footprint{Startup: median(startups), Starts: len(startups)}
+		fmt.Printf("   ✅ Hazır: başlangıç %v (median, %d başlatma)\n", fp.Startup.Round(100*time.Microsecond), fp.Starts)
+
+		time.Sleep(*idle)
+		if rss, err := groupRSS(server.Pid()); err == nil {
+			fp.IdleRSS, fp.Measured = rss, true
+		}
+
+		// RSS tüm yolların yükü boyunca örneklenir: Tepe, sunucunun en pahalı işindeki bellektir
+		sampler := startRSSSampler(server.Pid(), 100*time.Millisecond)
+		for _, path := range paths {
+			url := server.URL + path
+			checksum, err := server.Digest(path)
+			if err != nil {
+				fmt.Printf("   ⚠️  %s: %v\n", path, err)
+			}
+			if *warmup > 0 {
+				load.RunLevel(url, *concurrency, *warmup, client)
+			}
+			level := load.RunLevel(url, *concurrency, *duration, client)
+			runs = append(runs, run{spec: spec, path: path, level: level, checksum: checksum})
+
+			s := level.Latency.Summary()
+			fmt.Printf("   📊 %s: %.0f RPS, p50 %v, p99 %v, yeni bağlantı %d, hata %d\n", path, level.RPS(), s.P50.Round(time.Microsecond),
+				s.P99.Round(time.Microsecond), level.NewConns, level.Failed)
+		}
+		mean, peak, ok := sampler.Stop()
+		server.Stop()
+		if ok && fp.Measured {
+			fp.LoadRSS, fp.PeakRSS = mean, peak
+			fmt.Printf("   🧠 RSS boşta %.1f MB, yük altında ort. %.1f MB, tepe %.1f MB\n", mb(fp.IdleRSS), mb(fp.LoadRSS), mb(fp.PeakRSS))
+		} else {
+			fp.Measured = false
+		}
+		footprints[spec.Name] = fp
+	}
+
+	if len(runs) == 0 {
+		fmt.Println("\n❌ Hiçbir sunucu ölçülemedi")
+		os.Exit(1)
+	}
+
+	var ranking []string // İlk yolun RPS sırasıyla sunucular (ayak izi tablosu da bu sırada)
+	for _, path := range paths {
+		var ranked []run
+		checksums := map[string]int{}
+		for _, r := range runs {
+			if r.path == path {
+				ranked = append(ranked, r)
+				if r.checksum != "" {
+					checksums[r.checksum]++
+				}
+			}
+		}
+		sort.SliceStable(ranked, func(i, j int) bool { return ranked[i].level.RPS() > ranked[j].level.RPS() })
+		best := ranked[0].level.RPS()
+		expected := majority(checksums)
+
+		fmt.Printf("\n=== SIRALAMA (GET %s, c=%d, %s, %v) ===\n", path, *concurrency, client.Label(), *duration)
+		fmt.Printf("  %-4s %-10s %10s %8s %10s %10s %10s %10s %8s\n", "#", "sunucu", "RPS", "göreli", "p50", "p95", "p99", "max", "hata")
+		for i, r := range ranked {
+			s := r.level.Latency.Summary()
+			relative := 0.0
+			if best > 0 {
+				relative = r.level.RPS() / best * 100
+			}
+			fmt.Printf("  %-4d %-10s %10.0f %7.1f%% %10v %10v %10v %10v %8d\n", i+1, r.spec.Name, r.level.RPS(), relative,
+				s.P50.Round(time.Microsecond), s.P95.Round(time.Microsecond), s.P99.Round(time.Microsecond),
+				s.Max.Round(time.Microsecond), r.level.Failed)
+			if path == paths[0] {
+				ranking = append(ranking, r.spec.Name)
+			}
+		}
+		for _, r := range ranked {
+			if r.checksum != "" && r.checksum != expected {
+				fmt.Printf("  ⚠️  %s yanıtı diğer sunuculardan farklı (özet %s, çoğunluk %s): Aynı işi yapmıyor olabilir\n", r.spec.Name, r.checksum, expected)
+			}
+		}
+
+		if *jsonPath != "" {
+			for _, r := range ranked {
+				fp := footprints[r.spec.Name]
+				result := runResult(r.spec, r.path, r.level, fp, client, *concurrency)
+				if r.checksum != "" {
+					result.Params["checksum"] = r.checksum
+				}
+				result.Host = &host
+				if err := benchkit.AppendJSONL(*jsonPath, result); err != nil {
+					fmt.Printf("  ⚠️  Sonuç yazılamadı: %v\n", err)
+				}
+			}
+		}
+	}
+
+	// Ayak izi: Aynı sıra (RPS), throughput'un bedeli yan yana görünsün
+	fmt.Printf("\n=== AYAK İZİ ===\n")
+	fmt.Printf("  %-4s %-10s %12s %12s %12s %12s %14s\n", "#", "sunucu", "başlangıç", "RSS boşta", "RSS yük ort.", "RSS tepe", "KB/bağlantı")
+	for i, name := range ranking {
+		fp := footprints[name]
+		if !fp.Measured {
+			fmt.Printf("  %-4d %-10s %12v %12s %12s %12s %14s\n", i+1, name, fp.Startup.Round(100*time.Microsecond), "-", "-", "-", "-")
+			continue
+		}
+		fmt.Printf("  %-4d %-10s %12v %9.1f MB %9.1f MB %9.1f MB %14.1f\n", i+1, name, fp.Startup.Round(100*time.Microsecond),
+			mb(fp.IdleRSS), mb(fp.LoadRSS), mb(fp.PeakRSS), float64(fp.PerConn(*concurrency))/1024)
+	}
+
+	for _, spec := range specs {
+		if reason, ok := skipped[spec.Name]; ok {
+			fmt.Printf("  ⚠️  %s ölçülmedi: %s\n", spec.Name, reason)
+		}
+	}
+	if *jsonPath != "" {
+		fmt.Printf("\n📄 %d sonuç %s dosyasına eklendi\n", len(runs), *jsonPath)
+	}
+}
+
+// runResult - Ortak sonuç formatı: benchmark yolun adı (ping, json), variant dil ve varsa
+// sorgu ("go/n=100"); aggregate aynı yol ve sorgudaki dilleri tek tabloda karşılaştırır
+func runResult(spec ServerSpec, path string, level load.Level, fp Footprint, client load.ClientConfig, concurrency int) benchkit.Result {
+	name, query, _ := strings.Cut(path, "?")
+	r := benchkit.NewResult("cross-language", strings.Trim(name, "/"), level.Started, level.Duration, level.Requests-level.Failed)
+	r.Variant = spec.Name
+	if query != "" {
+		r.Variant += "/" + query
+	}
+	r.Params = map[string]string{"language": spec.Name, "command": spec.Command, "path": path,
+		"concurrency": strconv.Itoa(concurrency), "client": client.Label(),
+		"newConns": strconv.FormatInt(level.NewConns, 10), "startupMs": strconv.FormatFloat(benchkit.Millis(fp.Startup), 'f', 1, 64)}
+	if fp.Measured {
+		r.Params["rssIdleMB"] = strconv.FormatFloat(mb(fp.IdleRSS), 'f', 1, 64)
+		r.Params["rssLoadMB"] = strconv.FormatFloat(mb(fp.LoadRSS), 'f', 1, 64)
+		r.Params["rssPeakMB"] = strconv.FormatFloat(mb(fp.PeakRSS), 'f', 1, 64)
+	}
+	r.Latency = level.Latency.Summary().Millis()
+	r.Histogram = benchkit.HistogramMs(level.Latency)
+	r.Errors = level.Failed
+	return r
+}
+
+// majority - En çok sunucunun döndüğü özet (eşitlikte alfabetik ilk)
+func majority(counts map[string]int) string {
+	var best string
+	for checksum, n := range counts {
+		if n > counts[best] || (n == counts[best] && checksum < best) {
+			best = checksum
+		}
+	}
+	return best
+}
@@ -0,0 +1,51 @@
+//go:build linux
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// groupRSS - Process grubundaki tüm process'lerin RSS toplamı (bayt)
+// /proc/<pid>/stat'ın 5. alanı process grubu, 24. alanı RSS'tir (sayfa). Komut kabuk üzerinden
+// başlatıldığından sunucu, exec kullanılmadıysa kabuğun alt process'idir; grup ikisini de kapsar
+func groupRSS(pgid int) (int64, error) {
+	paths, err := filepath.Glob("/proc/[0-9]*/stat")
+	if err != nil {
+		return 0, err
+	}
+	pageSize := int64(os.Getpagesize())
+	var total int64
+	found := false
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue // Process bu arada çıkmış olabilir
+		}
+		// Komut adı boşluk ve parantez içerebilir: Alanlar son ')'dan sonra başlar (3. alan)
+		end := bytes.LastIndexByte(data, ')')
+		if end < 0 {
+			continue
+		}
+		fields := bytes.Fields(data[end+1:])
+		if len(fields) < 22 {
+			continue
+		}
+		if group, err := strconv.Atoi(string(fields[2])); err != nil || group != pgid {
+			continue
+		}
+		pages, err := strconv.ParseInt(string(fields[21]), 10, 64)
+		if err != nil {
+			continue
+		}
+		total += pages * pageSize
+		found = true
+	}
+	if !found {
+		return 0, os.ErrNotExist
+	}
+	return total, nil
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+
+package main
+
+import "errors"
+
+// groupRSS - RSS yalnızca Linux'ta (/proc) okunur; diğer platformlarda ayak izi raporlanmaz
+func groupRSS(pgid int) (int64, error) {
+	return 0, errors.ErrUnsupported
+}
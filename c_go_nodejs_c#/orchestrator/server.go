@@ -0,0 +1,170 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"time"
+)
+
+// ServerSpec - Karşılaştırılacak bir sunucunun nasıl başlatılacağı (-config ile JSON olarak verilir)
+type ServerSpec struct {
+	Name    string `json:"name"`
+	Build   string `json:"build,omitempty"` // Başlatmadan önce bir kez çalıştırılır (derleme; başlangıç süresine dahil değil)
+	Command string `json:"command"`         // Kabukta çalıştırılır; exec ile başlarsa sunucu kabuğun yerini alır
+	Port    int    `json:"port"`
+	Dir     string `json:"dir,omitempty"` // Komutun çalışma dizini (boş: -dir)
+}
+
+// defaultServers - Klasördeki /ping sunucuları: Hepsi 10 ms bekleyip "pong" döner
+// Derleme ayrı adımdır: Başlangıç süresi yalnızca derlenmiş sunucunun açılışını ölçer
+var defaultServers = []ServerSpec{
+	{Name: "go", Build: "go build -o server_go ./cmd/server", Command: "exec ./server_go", Port: 3001},
+	{Name: "node", Command: "exec node server.js", Port: 3000},
+	{Name: "csharp", Build: "dotnet build -c Release -o bin/server server.csproj >&2", Command: "exec dotnet bin/server/server.dll", Port: 3002},
+	{Name: "c", Build: "gcc -O2 -pthread -o server_c server.c", Command: "exec ./server_c", Port: 3003},
+}
+
+// loadServers - -config dosyasındaki sunucu listesi (boş: defaultServers)
+func loadServers(path string) ([]ServerSpec, error) {
+	if path == "" {
+		return defaultServers, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var specs []ServerSpec
+	if err := json.Unmarshal(data, &specs); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	for _, s := range specs {
+		if s.Name == "" || s.Command == "" || s.Port <= 0 {
+			return nil, fmt.Errorf("%s: her sunucu için name, command ve port gerekli", path)
+		}
+	}
+	return specs, nil
+}
+
+// BuildServer - Derleme adımını çalıştırır (Build boşsa bir şey yapmaz)
+func BuildServer(spec ServerSpec) error {
+	if spec.Build == "" {
+		return nil
+	}
+	cmd := shellCommand(spec.Build)
+	cmd.Dir = spec.Dir
+	cmd.Stdout = os.Stderr // Derleme çıktısı raporu bölmesin
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("derleme: %v", err)
+	}
+	return nil
+}
+
+// Server - Çalışan sunucu process'i
+type Server struct {
+	Spec    ServerSpec
+	URL     string        // http://127.0.0.1:port
+	Startup time.Duration // Process başlatılmasından ilk 200 yanıtına kadar geçen süre
+	cmd     *exec.Cmd
+	exit    chan error
+}
+
+// readyPoll - Hazır olma yoklama aralığı; başlangıç süresinin çözünürlüğüdür
+// Port açılmadan yapılan yoklama "connection refused" ile hemen döner, sunucuya yük olmaz
+const readyPoll = 5 * time.Millisecond
+
+// StartServer - Komutu başlatır ve url (ör: /ping) 200 dönene kadar bekler
+// Port zaten yanıt veriyorsa başlatılmaz: Başka bir process'i ölçmek sonucu sessizce bozar
+func StartServer(spec ServerSpec, path string, ready time.Duration) (*Server, error) {
+	s := &Server{Spec: spec, URL: fmt.Sprintf("http://127.0.0.1:%d", spec.Port), exit: make(chan error, 1)}
+	if s.probe(path) == nil {
+		return nil, fmt.Errorf("port %d zaten kullanımda (önce oradaki sunucuyu kapatın)", spec.Port)
+	}
+
+	s.cmd = shellCommand(spec.Command)
+	s.cmd.Dir = spec.Dir
+	s.cmd.Stderr = os.Stderr // Hatalar görünsün; stdout'taki başlangıç satırları raporu bölmesin
+	started := time.Now()
+	if err := s.cmd.Start(); err != nil {
+		return nil, err
+	}
+	go func() { s.exit <- s.cmd.Wait() }()
+
+	deadline := started.Add(ready)
+	for time.Now().Before(deadline) {
+		select {
+		case err := <-s.exit:
+			return nil, fmt.Errorf("başlarken çıktı: %v", err)
+		default:
+		}
+		if s.probe(path) == nil {
+			s.Startup = time.Since(started)
+			return s, nil
+		}
+		time.Sleep(readyPoll)
+	}
+	s.Stop()
+	return nil, fmt.Errorf("%v içinde hazır olmadı (%s%s)", ready, s.URL, path)
+}
+
+// Pid - Sunucunun process grubu (unix'te kabuk ve alt process'leri aynı gruptadır)
+func (s *Server) Pid() int {
+	return s.cmd.Process.Pid
+}
+
+// probe - Tek GET; 200 değilse hata
+func (s *Server) probe(path string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL+path, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("http %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Stop - Process grubunu sonlandırır (derleyici/dotnet gibi ara process'ler dahil); 5 saniyede
+// çıkmazsa öldürür
+func (s *Server) Stop() {
+	terminate(s.cmd)
+	select {
+	case <-s.exit:
+	case <-time.After(5 * time.Second):
+		kill(s.cmd)
+		<-s.exit
+	}
+}
+
+// Digest - Tek GET yanıtının gövdesinin kısa SHA-256 özeti (16 hex)
+// Aynı işi yapan sunucuların gövdesi bayt bayt aynıdır (ör: /json'da alan sırası ve sayı biçimi)
+func (s *Server) Digest(path string) (string, error) {
+	resp, err := http.Get(s.URL + path)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("http %d", resp.StatusCode)
+	}
+	h := sha256.New()
+	if _, err := io.Copy(h, resp.Body); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)[:8]), nil
+}
@@ -0,0 +1,167 @@
+#include <netinet/in.h>
+#include <netinet/tcp.h>
+#include <pthread.h>
+#include <stdarg.h>
+#include <stdio.h>
+#include <stdlib.h>
+#include <string.h>
+#include <sys/socket.h>
+#include <time.h>
+#include <unistd.h>
+
+// Bağlantı başına bir thread: Go'nun goroutine'i ve Node'un event loop'u yerine klasik model
+// Keep-alive desteklenir (yük testi bağlantıları yeniden kullanır); istek gövdesi beklenmez
+
+static const char PONG[] = "HTTP/1.1 200 OK\r\nContent-Type: text/plain\r\nContent-Length: 4\r\n\r\npong";
+static const char NOT_FOUND[] = "HTTP/1.1 404 Not Found\r\nContent-Length: 0\r\n\r\n";
+static const char BAD_REQUEST[] = "HTTP/1.1 400 Bad Request\r\nContent-Length: 0\r\n\r\n";
+
+#define MAX_JSON_ITEMS 10000
+
+// GET /json?n=100 - cmd/server'daki jsonHandler'ın karşılığı (aynı alan sırası, aynı gövde)
+// Serileştirici kütüphanesi yok: Nesne yapısı sabit olduğundan alanlar doğrudan yazılır
+// (karakter kaçışı gerekmez). Bu, C'nin gerçek bir uygulamadaki maliyetinin alt sınırıdır
+struct buffer {
+    char *data;
+    size_t len, cap;
+};
+
+static void append(struct buffer *b, const char *fmt, ...) __attribute__((format(printf, 2, 3)));
+
+static void append(struct buffer *b, const char *fmt, ...) {
+    for (;;) {
+        va_list ap;
+        va_start(ap, fmt);
+        int n = vsnprintf(b->data + b->len, b->cap - b->len, fmt, ap);
+        va_end(ap);
+        if (n >= 0 && (size_t)n < b->cap - b->len) {
+            b->len += n;
+            return;
+        }
+        b->cap = b->cap * 2 + n;
+        b->data = realloc(b->data, b->cap);
+    }
+}
+
+// parse_n - İstek satırındaki n parametresi (yoksa 100, geçersizse -1)
+static int parse_n(const char *request) {
+    const char *line_end = strstr(request, "\r\n");
+    const char *q = strstr(request, "?n=");
+    if (q == NULL) {
+        q = strstr(request, "&n=");
+    }
+    if (q == NULL || q > line_end) {
+        return 100;
+    }
+    char *end;
+    long n = strtol(q + 3, &end, 10);
+    if (end == q + 3 || (*end != ' ' && *end != '&') || n < 1 || n > MAX_JSON_ITEMS) {
+        return -1;
+    }
+    return (int)n;
+}
+
+static void write_json(int fd, int n, struct buffer *b) {
+    b->len = 0;
+    append(b, "{\"count\":%d,\"items\":[", n);
+    for (int i = 0; i < n; i++) {
+        int owner = i % 10;
+        append(b,
+               "%s{\"id\":%d,\"name\":\"item-%d\",\"price\":%d.5,\"active\":%s,"
+               "\"tags\":[\"tag-%d\",\"tag-%d\"],"
+               "\"owner\":{\"id\":%d,\"name\":\"user-%d\",\"email\":\"user-%d@example.com\"},"
+               "\"scores\":[%d,%d,%d]}",
+               i > 0 ? "," : "", i, i, i, i % 2 == 0 ? "true" : "false", i % 5, (i + 1) % 5,
+               owner, owner, owner, i % 7, i % 11, i % 13);
+    }
+    append(b, "]}");
+
+    char header[128];
+    int header_len = snprintf(header, sizeof(header),
+                              "HTTP/1.1 200 OK\r\nContent-Type: application/json\r\nContent-Length: %zu\r\n\r\n", b->len);
+    write(fd, header, header_len);
+    for (size_t off = 0; off < b->len;) {
+        ssize_t w = write(fd, b->data + off, b->len - off);
+        if (w <= 0) {
+            return;
+        }
+        off += w;
+    }
+}
+
+static void *handle(void *arg) {
+    int fd = (int)(long)arg;
+    char buf[8192];
+    size_t len = 0;
+    struct buffer body = {malloc(64 << 10), 0, 64 << 10}; // Bağlantı boyunca yeniden kullanılır
+
+    for (;;) {
+        ssize_t n = read(fd, buf + len, sizeof(buf) - len - 1);
+        if (n <= 0) {
+            break;
+        }
+        len += n;
+        buf[len] = '\0';
+
+        // Tampondaki tüm tam istekler (pipelining) sırayla yanıtlanır
+        char *end;
+        while ((end = strstr(buf, "\r\n\r\n")) != NULL) {
+            if (strncmp(buf, "GET /ping", 9) == 0) {
+                struct timespec ts = {0, 10 * 1000 * 1000}; // I/O simülasyonu
+                nanosleep(&ts, NULL);
+                write(fd, PONG, sizeof(PONG) - 1);
+            } else if (strncmp(buf, "GET /json", 9) == 0 && (buf[9] == ' ' || buf[9] == '?')) {
+                int n = parse_n(buf);
+                if (n < 0) {
+                    write(fd, BAD_REQUEST, sizeof(BAD_REQUEST) - 1);
+                } else {
+                    write_json(fd, n, &body);
+                }
+            } else {
+                write(fd, NOT_FOUND, sizeof(NOT_FOUND) - 1);
+            }
+            size_t used = end + 4 - buf;
+            memmove(buf, buf + used, len - used + 1);
+            len -= used;
+        }
+        if (len == sizeof(buf) - 1) {
+            break; // Başlık tampona sığmadı
+        }
+    }
+    free(body.data);
+    close(fd);
+    return NULL;
+}
+
+int main() {
+    int server = socket(AF_INET, SOCK_STREAM, 0);
+    int one = 1;
+    setsockopt(server, SOL_SOCKET, SO_REUSEADDR, &one, sizeof(one));
+
+    struct sockaddr_in addr = {0};
+    addr.sin_family = AF_INET;
+    addr.sin_addr.s_addr = htonl(INADDR_ANY);
+    addr.sin_port = htons(3003);
+    if (bind(server, (struct sockaddr *)&addr, sizeof(addr)) < 0 || listen(server, 1024) < 0) {
+        perror("listen");
+        return 1;
+    }
+    printf("C server running on :3003\n");
+    fflush(stdout);
+
+    for (;;) {
+        int fd = accept(server, NULL, NULL);
+        if (fd < 0) {
+            continue;
+        }
+        // Go ve Node da TCP_NODELAY açar: Başlık ve gövdenin ayrı write'ları Nagle + gecikmeli
+        // ACK yüzünden ~40 ms beklemesin
+        setsockopt(fd, IPPROTO_TCP, TCP_NODELAY, &one, sizeof(one));
+        pthread_t thread;
+        if (pthread_create(&thread, NULL, handle, (void *)(long)fd) != 0) {
+            close(fd);
+            continue;
+        }
+        pthread_detach(thread);
+    }
+}
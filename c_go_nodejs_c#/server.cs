@@ -0,0 +1,91 @@
+using System;
+using System.Net;
+using System.Text;
+using System.Text.Json;
+using System.Text.Json.Serialization;
+using System.Threading.Tasks;
+
+// GET /json?n=100 - cmd/server'daki jsonHandler'ın karşılığı (aynı alan sırası, aynı gövde)
+record Owner(
+    [property: JsonPropertyName("id")] int Id,
+    [property: JsonPropertyName("name")] string Name,
+    [property: JsonPropertyName("email")] string Email);
+
+record Item(
+    [property: JsonPropertyName("id")] int Id,
+    [property: JsonPropertyName("name")] string Name,
+    [property: JsonPropertyName("price")] double Price,
+    [property: JsonPropertyName("active")] bool Active,
+    [property: JsonPropertyName("tags")] string[] Tags,
+    [property: JsonPropertyName("owner")] Owner Owner,
+    [property: JsonPropertyName("scores")] int[] Scores);
+
+record JsonResponse(
+    [property: JsonPropertyName("count")] int Count,
+    [property: JsonPropertyName("items")] Item[] Items);
+
+class Server
+{
+    const int MaxJsonItems = 10000;
+
+    static byte[] JsonBody(int n)
+    {
+        var items = new Item[n];
+        for (int i = 0; i < n; i++)
+        {
+            int owner = i % 10;
+            items[i] = new Item(i, "item-" + i, i + 0.5, i % 2 == 0,
+                new[] { "tag-" + (i % 5), "tag-" + ((i + 1) % 5) },
+                new Owner(owner, "user-" + owner, "user-" + owner + "@example.com"),
+                new[] { i % 7, i % 11, i % 13 });
+        }
+        return JsonSerializer.SerializeToUtf8Bytes(new JsonResponse(n, items));
+    }
+
+    static async Task Main(string[] args)
+    {
+        var listener = new HttpListener();
+        listener.Prefixes.Add("http://+:3002/");
+        listener.Start();
+        Console.WriteLine("C# server running on :3002");
+
+        var pong = Encoding.UTF8.GetBytes("pong");
+        while (true)
+        {
+            var context = await listener.GetContextAsync();
+            _ = Task.Run(async () =>
+            {
+                var response = context.Response;
+                var path = context.Request.Url.AbsolutePath;
+                if (path == "/ping")
+                {
+                    await Task.Delay(10); // I/O simülasyonu
+                    response.ContentType = "text/plain";
+                    response.ContentLength64 = pong.Length;
+                    await response.OutputStream.WriteAsync(pong, 0, pong.Length);
+                }
+                else if (path == "/json")
+                {
+                    var raw = context.Request.QueryString["n"];
+                    int n = 100;
+                    if (raw != null && (!int.TryParse(raw, out n) || n < 1 || n > MaxJsonItems))
+                    {
+                        response.StatusCode = 400;
+                    }
+                    else
+                    {
+                        var body = JsonBody(n);
+                        response.ContentType = "application/json";
+                        response.ContentLength64 = body.Length;
+                        await response.OutputStream.WriteAsync(body, 0, body.Length);
+                    }
+                }
+                else
+                {
+                    response.StatusCode = 404;
+                }
+                response.Close();
+            });
+        }
+    }
+}
@@ -1,12 +1,47 @@
 const http = require("http");
 
+const MAX_JSON_ITEMS = 10000;
+
+// GET /json?n=100 - cmd/server'daki jsonHandler'ın karşılığı (aynı alan sırası, aynı gövde)
+function jsonBody(n) {
+  const items = new Array(n);
+  for (let i = 0; i < n; i++) {
+    const owner = i % 10;
+    items[i] = {
+      id: i,
+      name: "item-" + i,
+      price: i + 0.5,
+      active: i % 2 === 0,
+      tags: ["tag-" + (i % 5), "tag-" + ((i + 1) % 5)],
+      owner: { id: owner, name: "user-" + owner, email: "user-" + owner + "@example.com" },
+      scores: [i % 7, i % 11, i % 13],
+    };
+  }
+  return JSON.stringify({ count: n, items });
+}
+
 const server = http.createServer((req, res) => {
+  const [path, query] = req.url.split("?", 2);
   if (req.url === "/ping") {
     // I/O simülasyonu
     setTimeout(() => {
       res.writeHead(200, { "Content-Type": "text/plain" });
       res.end("pong");
     }, 10);
+  } else if (path === "/json") {
+    const raw = new URLSearchParams(query || "").get("n");
+    const n = raw === null ? 100 : Number(raw);
+    if (!Number.isInteger(n) || n < 1 || n > MAX_JSON_ITEMS) {
+      res.writeHead(400, { "Content-Type": "text/plain" });
+      res.end(`n: 1 ile ${MAX_JSON_ITEMS} arasında sayı bekleniyor`);
+      return;
+    }
+    const body = jsonBody(n);
+    res.writeHead(200, { "Content-Type": "application/json", "Content-Length": Buffer.byteLength(body) });
+    res.end(body);
+  } else {
+    res.writeHead(404);
+    res.end();
   }
 });
 
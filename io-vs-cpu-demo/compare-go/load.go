@@ -0,0 +1,195 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"benchkit"
+)
+
+// Request - Bir işin uçtan uca yapılması (servis: tek GET, worker: POST + sonuç sorgulama)
+type Request func(ctx context.Context, client *http.Client) error
+
+// Level - Tek eşzamanlılık seviyesinin istemci tarafı ölçümü
+type Level struct {
+	Concurrency int
+	Started     time.Time
+	Duration    time.Duration
+	Requests    int64
+	Failed      int64
+	Errors      map[string]int64
+	Latency     *benchkit.Histogram // Tüm istekler (hatalar dahil)
+	OKLatency   *benchkit.Histogram // Yalnızca başarılı istekler (goodput'un gecikmesi)
+}
+
+// RPS - Başarılı iş / saniye
+func (l Level) RPS() float64 {
+	if l.Duration <= 0 {
+		return 0
+	}
+	return float64(l.Requests-l.Failed) / l.Duration.Seconds()
+}
+
+// RunLevel - concurrency worker ile duration boyunca kapalı döngü yük (bkz. loadgen-go/level.go)
+func RunLevel(request Request, concurrency int, duration, timeout time.Duration) Level {
+	client := &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			MaxIdleConns:        concurrency,
+			MaxIdleConnsPerHost: concurrency,
+		},
+	}
+	defer client.CloseIdleConnections()
+
+	ctx, cancel := context.WithTimeout(context.Background(), duration)
+	defer cancel()
+
+	type workerResult struct {
+		latency  *benchkit.Histogram
+		ok       *benchkit.Histogram
+		requests int64
+		failed   int64
+		errors   map[string]int64
+	}
+	results := make([]workerResult, concurrency)
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := range results {
+		wg.Add(1)
+		go func(w *workerResult) {
+			defer wg.Done()
+			w.latency = benchkit.NewHistogram()
+			w.ok = benchkit.NewHistogram()
+			w.errors = map[string]int64{}
+			for ctx.Err() == nil {
+				reqStart := time.Now()
+				err := request(ctx, client)
+				if ctx.Err() != nil && errors.Is(err, context.DeadlineExceeded) {
+					break
+				}
+				elapsed := time.Since(reqStart)
+				w.latency.Record(elapsed)
+				w.requests++
+				if err != nil {
+					w.failed++
+					w.errors[errorKind(err)]++
+				} else {
+					w.ok.Record(elapsed)
+				}
+			}
+		}(&results[i])
+	}
+	wg.Wait()
+
+	level := Level{
+		Concurrency: concurrency,
+		Started:     start,
+		Duration:    time.Since(start),
+		Errors:      map[string]int64{},
+		Latency:     benchkit.NewHistogram(),
+		OKLatency:   benchkit.NewHistogram(),
+	}
+	for _, w := range results {
+		level.Latency.Merge(w.latency)
+		level.OKLatency.Merge(w.ok)
+		level.Requests += w.requests
+		level.Failed += w.failed
+		for kind, n := range w.errors {
+			level.Errors[kind] += n
+		}
+	}
+	return level
+}
+
+// serviceRequest - Senkron servis: GET url, yanıt işin sonucudur
+func serviceRequest(url string) Request {
+	return func(ctx context.Context, client *http.Client) error {
+		return do(ctx, client, http.MethodGet, url, nil)
+	}
+}
+
+// errJobFailed - İş sunucuda failed durumuna geçti
+var errJobFailed = errors.New("iş başarısız")
+
+// workerRequest - Asenkron worker: POST url ile iş gönderilir, bitene kadar GET /job/{id} sorgulanır
+// Gecikme, iş gönderiminden sonucun görüldüğü ana kadardır (kuyrukta bekleme dahil)
+func workerRequest(base, url string, poll time.Duration) Request {
+	return func(ctx context.Context, client *http.Client) error {
+		var job struct {
+			ID     string `json:"id"`
+			Status string `json:"status"`
+		}
+		if err := do(ctx, client, http.MethodPost, url, &job); err != nil {
+			return err
+		}
+		for job.Status != "done" {
+			if job.Status == "failed" {
+				return errJobFailed
+			}
+			timer := time.NewTimer(poll)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return ctx.Err()
+			case <-timer.C:
+			}
+			if err := do(ctx, client, http.MethodGet, base+"/job/"+job.ID, &job); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// statusError - 2xx dışı yanıt
+type statusError struct {
+	code int
+}
+
+func (e statusError) Error() string {
+	return fmt.Sprintf("http %d", e.code)
+}
+
+// do - İstek gönderir; out nil değilse gövde JSON olarak çözülür, değilse sonuna kadar okunur
+func do(ctx context.Context, client *http.Client, method, url string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	defer io.Copy(io.Discard, resp.Body) // Bağlantı yeniden kullanılabilsin
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return statusError{code: resp.StatusCode}
+	}
+	if out != nil {
+		return json.NewDecoder(resp.Body).Decode(out)
+	}
+	return nil
+}
+
+// errorKind - Hatanın rapordaki türü
+func errorKind(err error) string {
+	var status statusError
+	var netErr interface{ Timeout() bool }
+	switch {
+	case errors.As(err, &status):
+		return status.Error()
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return "timeout"
+	case errors.Is(err, errJobFailed):
+		return errJobFailed.Error()
+	default:
+		return "bağlantı"
+	}
+}
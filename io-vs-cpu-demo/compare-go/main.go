@@ -0,0 +1,279 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"benchkit"
+)
+
+// compare-go - Servis ve worker mimarisinin otomatik karşılaştırması
+// Aynı işi (CPU + I/O) iki şekilde sunan iki server-go process'i başlatılır ve aynı yük
+// profiliyle sırayla ölçülür:
+//   - service: -job-mode direct, GET /mixed: İş HTTP isteğinin içinde yapılır (eski service-go)
+//   - worker:  -job-mode pool, POST /job + GET /job/{id}: İş sabit sayıda worker'a devredilir
+//     (eski worker-go); gecikme gönderimden sonucun görüldüğü ana kadardır
+//
+// Her seviyede istemci tarafında RPS ve gecikme, sunucu tarafında /metrics'ten CPU kullanımı
+// (process_cpu_seconds_total) ve en yüksek goroutine sayısı ölçülür; sonuçlar yan yana yazılır
+// ve -json ile benchkit formatında eklenir (sunucu metrikleri params içinde).
+// Beklenen: Servis modunda goroutine sayısı eşzamanlılıkla büyür, worker modunda -workers'ta
+// sabitlenir ve fazlası kuyrukta bekler; CPU ağırlıklı işte RPS'ler benzer kalır.
+//
+// -overload verilirse servis/worker yerine pool modunun aşırı yük stratejileri karşılaştırılır
+// (server-go -overload, bkz. server-go/overload.go): Her strateji ayrı sunucuda, senkron GET /job
+// ile ve -reject-status 429 ile ölçülür. Tabloda goodput (başarılı iş/sn), başarılı isteklerin
+// p50/p99'u, tüm isteklerin p99'u ve hata oranı yan yana yazılır: reject hızlı hata verir,
+// block gecikmeyi büyütür, drop-oldest ve codel taze işleri hızlı tutar.
+//
+// KULLANIM (io-vs-cpu-demo klasöründe; server-go otomatik derlenir):
+//
+//	go run ./compare-go
+//	go run ./compare-go -cpu 20000000 -io 10ms -c 1,8,32,128 -d 10s -workers 4 -json results.jsonl
+//	go run ./compare-go -overload reject,block,drop-oldest,codel:5ms,100ms -workers 4 -queue 32 -c 16,64,256
+func main() {
+	bin := flag.String("server", "", "server-go binary'si (boş: ./server-go geçici dizine derlenir)")
+	port := flag.Int("port", 4100, "İlk sunucunun portu (service), worker bir sonrakini kullanır")
+	cpu := flag.Int64("cpu", 5_000_000, "İş başına CPU iterasyonu")
+	ioDelay := flag.Duration("io", 50*time.Millisecond, "İş başına I/O beklemesi")
+	levels := flag.String("c", "1,4,16,64", "Eşzamanlılık seviyeleri (virgülle ayrılmış)")
+	duration := flag.Duration("d", 5*time.Second, "Her seviyenin ölçüm süresi")
+	warmup := flag.Duration("warmup", time.Second, "Her seviyeden önce ölçülmeyen ısınma süresi")
+	timeout := flag.Duration("timeout", 30*time.Second, "İstek zaman aşımı")
+	workers := flag.Int("workers", 8, "Worker modunda worker sayısı")
+	queue := flag.Int("queue", 1024, "Worker modunda kuyruk boyutu (dolarsa 503 hata sayılır)")
+	poll := flag.Duration("poll", 10*time.Millisecond, "Worker modunda iş durumu sorgulama aralığı")
+	jsonPath := flag.String("json", "", "Sonuçların ekleneceği JSON Lines dosyası (benchkit formatı)")
+	overload := flag.String("overload", "", "Servis/worker yerine karşılaştırılacak aşırı yük stratejileri (ör: reject,block,drop-oldest,codel)")
+	flag.Parse()
+
+	concurrency, err := parseLevels(*levels)
+	if err != nil {
+		fmt.Printf("❌ -c: %v\n", err)
+		os.Exit(2)
+	}
+	if *bin == "" {
+		dir, err := os.MkdirTemp("", "compare-go")
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+		defer os.RemoveAll(dir)
+		fmt.Println("🔨 server-go derleniyor...")
+		if *bin, err = buildServer(dir); err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	query := url.Values{"cpu": {strconv.FormatInt(*cpu, 10)}, "io": {ioDelay.String()}}.Encode()
+	modes := []mode{
+		{
+			name: "service",
+			args: []string{"-job-mode", "direct"},
+			request: func(base string) Request {
+				return serviceRequest(base + "/mixed?" + query)
+			},
+		},
+		{
+			name: "worker",
+			args: []string{"-job-mode", "pool", "-workers", strconv.Itoa(*workers), "-queue", strconv.Itoa(*queue)},
+			request: func(base string) Request {
+				return workerRequest(base, base+"/job?"+query, *poll)
+			},
+		},
+	}
+
+	if *overload != "" {
+		modes = overloadModes(*overload, *workers, *queue, query)
+	}
+
+	fmt.Printf("🖥️  %s\n", benchkit.CollectHostInfo())
+	fmt.Printf("🚀 İş: cpu=%d, io=%v · %d seviye, seviye başına %v (+%v ısınma)\n", *cpu, *ioDelay, len(concurrency), *duration, *warmup)
+
+	results := make([][]measurement, len(modes))
+	for i, m := range modes {
+		server, err := StartServer(m.name, *bin, *port+i, m.args...)
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("\n=== %s (%s %s) ===\n", m.name, server.URL, strings.Join(m.args, " "))
+		request := m.request(server.URL)
+		for _, c := range concurrency {
+			if *warmup > 0 {
+				RunLevel(request, c, *warmup, *timeout)
+			}
+			// /metrics okunamazsa ölçüm yine yapılır, sunucu tarafı değerler 0 kalır
+			sampler, err := StartSampler(server, 200*time.Millisecond)
+			level := RunLevel(request, c, *duration, *timeout)
+			var usage ServerUsage
+			if err == nil {
+				usage, err = sampler.Stop()
+			}
+			if err != nil {
+				fmt.Printf("⚠️  /metrics okunamadı: %v\n", err)
+			}
+			s := level.Latency.Summary()
+			fmt.Printf("  c=%-4d %8.1f RPS  p50 %-10v p99 %-10v CPU %%%-5.0f goroutine %d%s\n",
+				c, level.RPS(), round(s.P50), round(s.P99), usage.CPUPercent, usage.PeakGoroutines, errorSummary(level))
+			results[i] = append(results[i], measurement{level: level, usage: usage})
+
+			if *jsonPath != "" {
+				r := benchkit.NewResult("io-vs-cpu-demo", "compare", level.Started, level.Duration, level.Requests-level.Failed)
+				r.Variant = fmt.Sprintf("%s/c%d", m.name, c)
+				r.Params = map[string]string{
+					"mode":           m.name,
+					"concurrency":    strconv.Itoa(c),
+					"cpu":            strconv.FormatInt(*cpu, 10),
+					"io":             ioDelay.String(),
+					"cpuPercent":     strconv.FormatFloat(usage.CPUPercent, 'f', 1, 64),
+					"peakGoroutines": strconv.Itoa(usage.PeakGoroutines),
+				}
+				if m.name != "service" {
+					r.Params["workers"] = strconv.Itoa(*workers)
+				}
+				if *overload != "" {
+					r.Benchmark = "overload"
+					r.Params["queue"] = strconv.Itoa(*queue)
+					r.Params["okP99Ms"] = strconv.FormatFloat(benchkit.Millis(level.OKLatency.Summary().P99), 'f', 3, 64)
+				}
+				r.Latency = s.Millis()
+				r.Histogram = benchkit.HistogramMs(level.Latency)
+				r.Errors = level.Failed
+				if err := benchkit.AppendJSONL(*jsonPath, r); err != nil {
+					fmt.Printf("⚠️  Sonuç yazılamadı: %v\n", err)
+				}
+			}
+		}
+		server.Stop()
+	}
+	if *overload != "" {
+		printOverload(modes, concurrency, results)
+		return
+	}
+	printComparison(modes, concurrency, results)
+}
+
+// overloadModes - Virgülle ayrılmış strateji listesinden modlar
+// Süre olan parçalar önceki stratejinin parametresidir: codel:5ms,100ms tek stratejidir
+func overloadModes(list string, workers, queue int, query string) []mode {
+	var strategies []string
+	for _, part := range strings.Split(list, ",") {
+		part = strings.TrimSpace(part)
+		if _, err := time.ParseDuration(part); err == nil && len(strategies) > 0 {
+			strategies[len(strategies)-1] += "," + part
+			continue
+		}
+		strategies = append(strategies, part)
+	}
+	modes := make([]mode, len(strategies))
+	for i, strategy := range strategies {
+		modes[i] = mode{
+			name: strategy,
+			args: []string{"-job-mode", "pool", "-workers", strconv.Itoa(workers), "-queue", strconv.Itoa(queue),
+				"-reject-status", "429", "-overload", strategy},
+			request: func(base string) Request {
+				return serviceRequest(base + "/job?" + query)
+			},
+		}
+	}
+	return modes
+}
+
+// printOverload - Seviye başına stratejileri alt alta yazar
+func printOverload(modes []mode, concurrency []int, results [][]measurement) {
+	fmt.Println("\n=== Aşırı yük stratejileri ===")
+	fmt.Printf("  %6s %-18s %10s %10s %10s %10s %8s\n", "c", "strateji", "goodput", "ok p50", "ok p99", "tümü p99", "hata")
+	for i, c := range concurrency {
+		for j, m := range modes {
+			if i >= len(results[j]) {
+				continue
+			}
+			level := results[j][i].level
+			ok, all := level.OKLatency.Summary(), level.Latency.Summary()
+			errorRate := 0.0
+			if level.Requests > 0 {
+				errorRate = float64(level.Failed) / float64(level.Requests) * 100
+			}
+			fmt.Printf("  %6d %-18s %10.1f %10v %10v %10v %7.1f%%\n",
+				c, m.name, level.RPS(), round(ok.P50), round(ok.P99), round(all.P99), errorRate)
+		}
+	}
+}
+
+// mode - Karşılaştırılan bir mimari
+type mode struct {
+	name    string
+	args    []string                  // server-go argümanları (-addr hariç)
+	request func(base string) Request // Sunucu adresinden tek iş isteği
+}
+
+// measurement - Bir seviyenin istemci ve sunucu tarafı ölçümü
+type measurement struct {
+	level Level
+	usage ServerUsage
+}
+
+// printComparison - Seviye başına modları yan yana yazar
+func printComparison(modes []mode, concurrency []int, results [][]measurement) {
+	fmt.Println("\n=== Karşılaştırma ===")
+	fmt.Printf("  %6s", "c")
+	for _, m := range modes {
+		fmt.Printf(" | %-8s %8s %10s %10s %6s %6s", m.name, "RPS", "p50", "p99", "CPU%", "gor.")
+	}
+	fmt.Println()
+	for i, c := range concurrency {
+		fmt.Printf("  %6d", c)
+		for j := range modes {
+			if i >= len(results[j]) {
+				fmt.Printf(" | %-8s %8s %10s %10s %6s %6s", "", "-", "-", "-", "-", "-")
+				continue
+			}
+			r := results[j][i]
+			s := r.level.Latency.Summary()
+			fmt.Printf(" | %-8s %8.1f %10v %10v %6.0f %6d", "", r.level.RPS(), round(s.P50), round(s.P99), r.usage.CPUPercent, r.usage.PeakGoroutines)
+		}
+		fmt.Println()
+	}
+}
+
+// errorSummary - " · hata: http 503=12, timeout=3" (hata yoksa boş)
+func errorSummary(level Level) string {
+	if len(level.Errors) == 0 {
+		return ""
+	}
+	kinds := make([]string, 0, len(level.Errors))
+	for kind, n := range level.Errors {
+		kinds = append(kinds, fmt.Sprintf("%s=%d", kind, n))
+	}
+	sort.Strings(kinds)
+	return " · hata: " + strings.Join(kinds, ", ")
+}
+
+// parseLevels - "1,2,4" listesini pozitif sayılara çevirir
+func parseLevels(s string) ([]int, error) {
+	var levels []int
+	for _, part := range strings.Split(s, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("geçersiz seviye %q", part)
+		}
+		levels = append(levels, n)
+	}
+	return levels, nil
+}
+
+// round - Tabloda okunabilir süre (µs hassasiyeti)
+func round(d time.Duration) time.Duration {
+	if d >= time.Millisecond {
+		return d.Round(10 * time.Microsecond)
+	}
+	return d.Round(time.Microsecond)
+}
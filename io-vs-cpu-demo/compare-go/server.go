@@ -0,0 +1,162 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// Server - Karşılaştırma için başlatılan server-go process'i
+type Server struct {
+	Name string
+	URL  string
+	cmd  *exec.Cmd
+	exit chan error
+}
+
+// buildServer - server-go'yu geçici dizine derler, binary yolunu döner
+// Ölçüm sırasında `go run`ın derleyici process'leri CPU'yu paylaşmasın diye önceden derlenir
+func buildServer(dir string) (string, error) {
+	bin := filepath.Join(dir, "server-go")
+	cmd := exec.Command("go", "build", "-o", bin, "./server-go")
+	cmd.Stdout, cmd.Stderr = os.Stderr, os.Stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("server-go derlenemedi (io-vs-cpu-demo klasöründe çalıştırın): %w", err)
+	}
+	return bin, nil
+}
+
+// StartServer - Binary'yi verilen portta argümanlarla başlatır ve hazır olmasını bekler
+func StartServer(name, bin string, port int, args ...string) (*Server, error) {
+	addr := fmt.Sprintf("127.0.0.1:%d", port)
+	cmd := exec.Command(bin, append([]string{"-addr", addr}, args...)...)
+	cmd.Stderr = os.Stderr // Sunucunun stdout'u (başlangıç/kapanış satırları) raporu bölmesin
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	s := &Server{Name: name, URL: "http://" + addr, cmd: cmd, exit: make(chan error, 1)}
+	go func() { s.exit <- cmd.Wait() }()
+
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		select {
+		case err := <-s.exit:
+			return nil, fmt.Errorf("%s başlarken çıktı: %v", name, err)
+		default:
+		}
+		if _, err := s.Scrape(); err == nil {
+			return s, nil
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	s.Stop()
+	return nil, fmt.Errorf("%s 10 saniyede hazır olmadı (%s)", name, s.URL)
+}
+
+// Stop - SIGTERM ile kapatır (sunucu işleri boşaltır); 10 saniyede çıkmazsa öldürür
+func (s *Server) Stop() {
+	s.cmd.Process.Signal(syscall.SIGTERM)
+	select {
+	case <-s.exit:
+	case <-time.After(10 * time.Second):
+		s.cmd.Process.Kill()
+		<-s.exit
+	}
+}
+
+// Scrape - GET /metrics'teki etiketsiz metrikler (ad → değer)
+// process_cpu_seconds_total ve go_goroutines yeterli; etiketli satırlar atlanır
+func (s *Server) Scrape() (map[string]float64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL+"/metrics", nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("/metrics: http %d", resp.StatusCode)
+	}
+	metrics := map[string]float64{}
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		name, value, ok := strings.Cut(scanner.Text(), " ")
+		if !ok || strings.HasPrefix(name, "#") || strings.Contains(name, "{") {
+			continue
+		}
+		if v, err := strconv.ParseFloat(value, 64); err == nil {
+			metrics[name] = v
+		}
+	}
+	return metrics, scanner.Err()
+}
+
+// Sampler - Ölçüm boyunca sunucu metriklerini izler
+type Sampler struct {
+	server   *Server
+	before   map[string]float64
+	started  time.Time
+	peak     float64 // En yüksek go_goroutines
+	stop     chan struct{}
+	finished chan struct{}
+}
+
+// StartSampler - CPU sayacını okur ve interval aralıklarla goroutine sayısını örneklemeye başlar
+func StartSampler(server *Server, interval time.Duration) (*Sampler, error) {
+	before, err := server.Scrape()
+	if err != nil {
+		return nil, err
+	}
+	s := &Sampler{server: server, before: before, started: time.Now(), peak: before["go_goroutines"],
+		stop: make(chan struct{}), finished: make(chan struct{})}
+	go func() {
+		defer close(s.finished)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-s.stop:
+				return
+			case <-ticker.C:
+				if m, err := server.Scrape(); err == nil {
+					s.peak = max(s.peak, m["go_goroutines"])
+				}
+			}
+		}
+	}()
+	return s, nil
+}
+
+// ServerUsage - Ölçüm boyunca sunucu tarafı kullanım
+type ServerUsage struct {
+	CPUPercent     float64 // CPU-saniye / duvar saati × 100 (çok çekirdekte 100'ü aşar)
+	PeakGoroutines int
+}
+
+// Stop - Örneklemeyi bitirir ve kullanımı hesaplar
+func (s *Sampler) Stop() (ServerUsage, error) {
+	close(s.stop)
+	<-s.finished
+	after, err := s.server.Scrape()
+	if err != nil {
+		return ServerUsage{}, err
+	}
+	elapsed := time.Since(s.started).Seconds()
+	usage := ServerUsage{PeakGoroutines: int(max(s.peak, after["go_goroutines"]))}
+	if elapsed > 0 {
+		usage.CPUPercent = (after["process_cpu_seconds_total"] - s.before["process_cpu_seconds_total"]) / elapsed * 100
+	}
+	return usage, nil
+}
@@ -1,8 +1,58 @@
 const express = require('express');
 const axios = require('axios');
+const client = require('prom-client');
 
 const app = express();
 const PORT = 3000;
+// /cpu, /io, /mixed ve /job tek Go sunucusunda (server-go)
+const SERVER_GO = process.env.SERVER_GO_URL || 'http://server-go:4000';
+
+
+// Prometheus metrikleri (GET /metrics): server-go ile aynı adlar, iki sunucu aynı panelde izlenir
+// Node'da goroutine yok: Event loop gecikmesi ve aktif handle sayısı varsayılan metriklerden gelir
+client.collectDefaultMetrics();
+const httpRequests = new client.Counter({
+    name: 'http_requests_total',
+    help: 'Biten HTTP istekleri',
+    labelNames: ['endpoint', 'method', 'code'],
+});
+const httpInFlight = new client.Gauge({
+    name: 'http_requests_in_flight',
+    help: 'İşlenmekte olan HTTP istekleri',
+    labelNames: ['endpoint'],
+});
+const httpDuration = new client.Histogram({
+    name: 'http_request_duration_seconds',
+    help: 'Handler süresi',
+    labelNames: ['endpoint'],
+    buckets: [0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60],
+});
+
+// Etiket, route tanımıdır (/job/:id): İş ID'leri ve bilinmeyen yollar etiket sayısını patlatmaz
+const ROUTES = ['/ping', '/cpu', '/io', '/mixed', '/job'];
+function endpointLabel(req) {
+    if (req.path.startsWith('/job/')) return '/job/:id';
+    return ROUTES.includes(req.path) ? req.path : 'unmatched';
+}
+
+app.use((req, res, next) => {
+    if (req.path === '/metrics') return next();
+    const endpoint = endpointLabel(req);
+    httpInFlight.inc({ endpoint });
+    const end = httpDuration.startTimer({ endpoint });
+    // close, istemci bağlantıyı koparsa da tetiklenir: Eşzamanlı istek sayısı takılı kalmaz
+    res.on('close', () => {
+        httpInFlight.dec({ endpoint });
+        httpRequests.inc({ endpoint, method: req.method, code: res.statusCode });
+        end();
+    });
+    next();
+});
+
+app.get('/metrics', async (req, res) => {
+    res.set('Content-Type', client.register.contentType);
+    res.send(await client.register.metrics());
+});
 
 
 // I/O ağırlıklı endpoint
@@ -11,18 +61,31 @@ app.get('/ping', async (req, res) => {
 });
 
 
-//Cpu isi go servisine gönderilir
+//Cpu isi go servisine gönderilir (iterations parametresi aynen iletilir)
 app.get('/cpu', async (req, res) => {
-    const response = await axios.get("http://service-go:4000/cpu");
+    const response = await axios.get(`${SERVER_GO}/cpu`, { params: req.query });
     res.send(response.data);
 });
 
+// I/O ve karışık işler de parametreleriyle iletilir
+app.get(['/io', '/mixed'], async (req, res) => {
+    const response = await axios.get(`${SERVER_GO}${req.path}`, { params: req.query });
+    res.send(response.data);
+});
 
-//Asyn job (worker)
+
+//Asyn job (worker): iş kuyruğa alınır, ID hemen döner - sonuç /job/:id ile sorgulanır
 
 app.get('/job', async (req, res) => {
-    await axios.get('http://worker-go:5000/job');
-    res.send('Job sent to worker');
+    const response = await axios.post(`${SERVER_GO}/job`, null, { params: req.query });
+    res.status(202).json(response.data);
+});
+
+app.get('/job/:id', async (req, res) => {
+    const response = await axios.get(`${SERVER_GO}/job/${encodeURIComponent(req.params.id)}`, {
+        validateStatus: () => true,
+    });
+    res.status(response.status).send(response.data);
 });
 
 
@@ -0,0 +1,31 @@
+module iovscpu
+
+go 1.22
+
+require (
+	benchkit v0.0.0
+	github.com/felixge/fgprof v0.9.5
+	github.com/google/pprof v0.0.0-20240727154555-813a5fbdbec8
+	go.mongodb.org/mongo-driver v1.17.6
+	google.golang.org/grpc v1.67.0
+	google.golang.org/protobuf v1.34.2
+)
+
+require (
+	github.com/golang/snappy v0.0.4 // indirect
+	github.com/klauspost/compress v1.16.7 // indirect
+	github.com/montanaflynn/stats v0.7.1 // indirect
+	github.com/xdg-go/pbkdf2 v1.0.0 // indirect
+	github.com/xdg-go/scram v1.1.2 // indirect
+	github.com/xdg-go/stringprep v1.0.4 // indirect
+	github.com/youmark/pkcs8 v0.0.0-20240726163527-a2c0da244d78 // indirect
+	golang.org/x/crypto v0.26.0 // indirect
+	golang.org/x/net v0.28.0 // indirect
+	golang.org/x/sync v0.8.0 // indirect
+	golang.org/x/sys v0.24.0 // indirect
+	golang.org/x/text v0.17.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20240814211410-ddb44dafa142 // indirect
+)
+
+// Tüm lab'ların ortak ölçüm kütüphanesi (repo kökündeki benchkit modülü)
+replace benchkit => ../benchkit
@@ -0,0 +1,314 @@
+// Package ws - server-go ve loadgen-go'nun ortak minimal WebSocket (RFC 6455) uygulaması
+// Kütüphane yerine demo'nun ihtiyacı kadarı (bkz. server-go/queue_redis.go'daki RESP istemcisi):
+//   - El sıkışma: Accept (sunucu, http.Hijacker ile) ve Dial (istemci, ws:// ve wss://)
+//   - Metin/binary mesajlar, parçalı (fragmented) mesajların birleştirilmesi
+//   - Ping'e otomatik pong, close el sıkışması
+//
+// Uzantılar (permessage-deflate) ve alt protokoller desteklenmez.
+package ws
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// Frame opcode'ları
+const (
+	OpContinuation = 0x0
+	OpText         = 0x1
+	OpBinary       = 0x2
+	OpClose        = 0x8
+	OpPing         = 0x9
+	OpPong         = 0xA
+)
+
+// MaxMessageSize - Kabul edilen en büyük mesaj (bayt); aşan mesaj bağlantıyı kapatır
+const MaxMessageSize = 16 << 20
+
+// acceptGUID - Sec-WebSocket-Accept hesabındaki sabit (RFC 6455 §1.3)
+const acceptGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// ErrClosed - Karşı taraf close frame'i gönderdi
+var ErrClosed = errors.New("websocket kapandı")
+
+// Conn - Kurulmuş WebSocket bağlantısı
+// ReadMessage tek goroutine'den çağrılmalıdır; WriteMessage eşzamanlı çağrılabilir
+type Conn struct {
+	conn   net.Conn
+	br     *bufio.Reader
+	client bool // İstemci giden frame'leri maskeler (RFC 6455 §5.3)
+
+	writeMu sync.Mutex
+}
+
+// Accept - HTTP isteğini WebSocket'e yükseltir (101 Switching Protocols)
+// Hata durumunda yanıt yazılmıştır
+func Accept(w http.ResponseWriter, r *http.Request) (*Conn, error) {
+	if !headerContains(r.Header, "Connection", "upgrade") || !headerContains(r.Header, "Upgrade", "websocket") {
+		http.Error(w, "websocket yükseltmesi bekleniyor", http.StatusUpgradeRequired)
+		return nil, errors.New("Upgrade: websocket başlığı yok")
+	}
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if key == "" || r.Header.Get("Sec-WebSocket-Version") != "13" {
+		http.Error(w, "Sec-WebSocket-Key ve Sec-WebSocket-Version: 13 gerekli", http.StatusBadRequest)
+		return nil, errors.New("geçersiz websocket el sıkışması")
+	}
+	// Middleware'ler writer'ı sarabilir; ResponseController Unwrap zincirini izler
+	conn, rw, err := http.NewResponseController(w).Hijack()
+	if err != nil {
+		http.Error(w, "bağlantı devralınamadı", http.StatusInternalServerError)
+		return nil, err
+	}
+	response := "HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\nConnection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + acceptKey(key) + "\r\n\r\n"
+	if _, err := rw.WriteString(response); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if err := rw.Flush(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return &Conn{conn: conn, br: rw.Reader}, nil
+}
+
+// Dial - ws:// veya wss:// adresine bağlanır; tlsConfig yalnızca wss için kullanılır
+func Dial(ctx context.Context, rawURL string, tlsConfig *tls.Config) (*Conn, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	host := u.Host
+	if u.Port() == "" {
+		host = net.JoinHostPort(u.Hostname(), map[string]string{"ws": "80", "wss": "443"}[u.Scheme])
+	}
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", host)
+	if err != nil {
+		return nil, err
+	}
+	switch u.Scheme {
+	case "ws":
+	case "wss":
+		cfg := tlsConfig.Clone()
+		if cfg == nil {
+			cfg = &tls.Config{}
+		}
+		if cfg.ServerName == "" {
+			cfg.ServerName = u.Hostname()
+		}
+		tlsConn := tls.Client(conn, cfg)
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			conn.Close()
+			return nil, err
+		}
+		conn = tlsConn
+	default:
+		conn.Close()
+		return nil, fmt.Errorf("desteklenmeyen şema %q (ws, wss)", u.Scheme)
+	}
+
+	// El sıkışma ctx'e bağlı: Süre dolarsa bağlantı kapatılır ve okuma hata döner
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	nonce := make([]byte, 16)
+	rand.Read(nonce)
+	key := base64.StdEncoding.EncodeToString(nonce)
+	request := "GET " + u.RequestURI() + " HTTP/1.1\r\nHost: " + u.Host + "\r\n" +
+		"Upgrade: websocket\r\nConnection: Upgrade\r\n" +
+		"Sec-WebSocket-Key: " + key + "\r\nSec-WebSocket-Version: 13\r\n\r\n"
+	if _, err := io.WriteString(conn, request); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	br := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(br, nil)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		conn.Close()
+		return nil, fmt.Errorf("websocket el sıkışması: http %d", resp.StatusCode)
+	}
+	if resp.Header.Get("Sec-WebSocket-Accept") != acceptKey(key) {
+		conn.Close()
+		return nil, errors.New("websocket el sıkışması: geçersiz Sec-WebSocket-Accept")
+	}
+	if !stop() {
+		return nil, ctx.Err() // AfterFunc bağlantıyı kapatmış
+	}
+	return &Conn{conn: conn, br: br, client: true}, nil
+}
+
+// ReadMessage - Sıradaki veri mesajı (OpText veya OpBinary)
+// Ping'lere pong ile cevap verilir; close frame'inde karşılık verilip ErrClosed döner
+func (c *Conn) ReadMessage() (byte, []byte, error) {
+	var (
+		opcode  byte
+		message []byte
+	)
+	for {
+		fin, op, payload, err := c.readFrame()
+		if err != nil {
+			return 0, nil, err
+		}
+		switch op {
+		case OpPing:
+			if err := c.WriteMessage(OpPong, payload); err != nil {
+				return 0, nil, err
+			}
+			continue
+		case OpPong:
+			continue
+		case OpClose:
+			c.WriteMessage(OpClose, payload) // Close el sıkışması: Kodu geri gönder
+			return 0, nil, ErrClosed
+		case OpContinuation:
+			if opcode == 0 {
+				return 0, nil, errors.New("websocket: beklenmeyen continuation frame")
+			}
+		default:
+			if opcode != 0 {
+				return 0, nil, errors.New("websocket: parçalı mesajın ortasında yeni mesaj")
+			}
+			opcode = op
+		}
+		if len(message)+len(payload) > MaxMessageSize {
+			return 0, nil, fmt.Errorf("websocket: mesaj %d bayttan büyük", MaxMessageSize)
+		}
+		message = append(message, payload...)
+		if fin {
+			return opcode, message, nil
+		}
+	}
+}
+
+// readFrame - Tek frame'i okur (maskeyi çözer)
+func (c *Conn) readFrame() (fin bool, opcode byte, payload []byte, err error) {
+	var header [2]byte
+	if _, err = io.ReadFull(c.br, header[:]); err != nil {
+		return
+	}
+	fin = header[0]&0x80 != 0
+	opcode = header[0] & 0x0F
+	masked := header[1]&0x80 != 0
+	length := uint64(header[1] & 0x7F)
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err = io.ReadFull(c.br, ext[:]); err != nil {
+			return
+		}
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err = io.ReadFull(c.br, ext[:]); err != nil {
+			return
+		}
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+	if length > MaxMessageSize {
+		err = fmt.Errorf("websocket: frame %d bayttan büyük", MaxMessageSize)
+		return
+	}
+	var mask [4]byte
+	if masked {
+		if _, err = io.ReadFull(c.br, mask[:]); err != nil {
+			return
+		}
+	}
+	payload = make([]byte, length)
+	if _, err = io.ReadFull(c.br, payload); err != nil {
+		return
+	}
+	if masked {
+		for i := range payload {
+			payload[i] ^= mask[i%4]
+		}
+	}
+	return
+}
+
+// WriteMessage - Tek frame'lik mesaj gönderir (istemcide maskelenir)
+func (c *Conn) WriteMessage(opcode byte, data []byte) error {
+	frame := make([]byte, 0, 14+len(data))
+	frame = append(frame, 0x80|opcode) // FIN
+	maskBit := byte(0)
+	if c.client {
+		maskBit = 0x80
+	}
+	switch n := len(data); {
+	case n < 126:
+		frame = append(frame, maskBit|byte(n))
+	case n <= 0xFFFF:
+		frame = append(frame, maskBit|126)
+		frame = binary.BigEndian.AppendUint16(frame, uint16(n))
+	default:
+		frame = append(frame, maskBit|127)
+		frame = binary.BigEndian.AppendUint64(frame, uint64(n))
+	}
+	if c.client {
+		var mask [4]byte
+		rand.Read(mask[:])
+		frame = append(frame, mask[:]...)
+		start := len(frame)
+		frame = append(frame, data...)
+		for i := range data {
+			frame[start+i] ^= mask[i%4]
+		}
+	} else {
+		frame = append(frame, data...)
+	}
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	_, err := c.conn.Write(frame)
+	return err
+}
+
+// CloseGracefully - Close frame'i gönderir (1000 normal kapanış) ve bağlantıyı kapatır
+// Karşı tarafın cevabı beklenmez: Demo'da kapanışın hızlı olması yeterli
+func (c *Conn) CloseGracefully() error {
+	c.WriteMessage(OpClose, []byte{0x03, 0xE8})
+	return c.conn.Close()
+}
+
+// Close - Bağlantıyı hemen kapatır
+func (c *Conn) Close() error {
+	return c.conn.Close()
+}
+
+// acceptKey - Sec-WebSocket-Accept = base64(SHA1(key + GUID))
+func acceptKey(key string) string {
+	sum := sha1.Sum([]byte(key + acceptGUID))
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// headerContains - Virgülle ayrılmış başlık değerlerinde (büyük/küçük harf duyarsız) token arar
+func headerContains(h http.Header, name, token string) bool {
+	for _, value := range h.Values(name) {
+		for _, part := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(part), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"crypto/tls"
+	"net/http"
+	"time"
+)
+
+// client.go - İstemci protokol ayarları (-keepalive, -max-idle, -http2, -insecure)
+// server-go'daki aynı adlı bayraklarla birlikte kullanılır (bkz. server-go/protocol.go):
+// Keep-alive kapalıyken her istek yeni bağlantı açar; HTTP/2'de tüm worker'lar birkaç bağlantıyı
+// paylaşır. Her seviyede açılan yeni bağlantı sayısı ve anlaşılan protokol raporlanır.
+
+// ClientConfig - Yük istemcisinin bağlantı davranışı
+type ClientConfig struct {
+	KeepAlive bool
+	MaxIdle   int  // Host başına boşta tutulan bağlantı (0 = eşzamanlılık kadar)
+	HTTP2     bool // https hedeflerde ALPN ile HTTP/2 dene
+	Insecure  bool // Sertifika doğrulamasını atla (server-go -tls self-signed sertifika kullanır)
+}
+
+// newClient - Ayara göre HTTP istemcisi
+// Varsayılan transport host başına 2 boşta bağlantı tutar; fazlası her istekte yeniden bağlanır
+// ve ölçüme TCP handshake karışır. Bu yüzden MaxIdle varsayılanı eşzamanlılıktır
+func newClient(cfg ClientConfig, concurrency int, timeout time.Duration) *http.Client {
+	maxIdle := cfg.MaxIdle
+	if maxIdle <= 0 {
+		maxIdle = concurrency
+	}
+	transport := &http.Transport{
+		MaxIdleConns:        maxIdle,
+		MaxIdleConnsPerHost: maxIdle,
+		DisableKeepAlives:   !cfg.KeepAlive,
+		// Özel TLSClientConfig verildiğinde HTTP/2 ancak açıkça istenirse denenir
+		ForceAttemptHTTP2: cfg.HTTP2,
+		TLSClientConfig:   &tls.Config{InsecureSkipVerify: cfg.Insecure},
+	}
+	if !cfg.HTTP2 {
+		transport.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+	}
+	return &http.Client{Timeout: timeout, Transport: transport}
+}
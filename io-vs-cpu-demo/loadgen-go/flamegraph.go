@@ -0,0 +1,142 @@
+package main
+
+import (
+	"fmt"
+	"hash/fnv"
+	"html"
+	"io"
+	"sort"
+
+	"github.com/google/pprof/profile"
+)
+
+// flamegraph.go - CPU profilinden flamegraph SVG'si
+// Her çerçeve bir fonksiyondur; genişliği, o fonksiyonun (ve çağırdıklarının) CPU süresine
+// oranıdır. Kök en altta, yaprak fonksiyonlar üstte. Kardeşler ada göre sıralanır (zaman
+// sırası değildir). Graphviz gibi harici bir araç gerekmez: SVG doğrudan yazılır.
+//   - /cpu yükünde tek bir geniş cpuHeavyTask kulesi beklenir
+//   - /io yükünde CPU'nun çoğu ağ/zamanlayıcı (netpoll, runtime) fonksiyonlarındadır
+
+const (
+	flameWidth       = 1200.0
+	flameFrameHeight = 16.0
+	flameTitleHeight = 24.0
+	flameMinWidth    = 0.5 // Bundan dar çerçeveler çizilmez (SVG boyutu için)
+	flameCharWidth   = 7.0 // 12px font için yaklaşık karakter genişliği
+)
+
+// flameNode - Çağrı ağacında bir çerçeve
+type flameNode struct {
+	name     string
+	value    int64
+	children map[string]*flameNode
+}
+
+func (n *flameNode) child(name string) *flameNode {
+	c, ok := n.children[name]
+	if !ok {
+		c = &flameNode{name: name, children: map[string]*flameNode{}}
+		n.children[name] = c
+	}
+	return c
+}
+
+func (n *flameNode) depth() int {
+	d := 0
+	for _, c := range n.children {
+		d = max(d, c.depth())
+	}
+	return d + 1
+}
+
+// buildFlameTree - Örnekleri kökten yaprağa çağrı ağacına toplar
+// Değer olarak "cpu" örnek tipi (nanosaniye) kullanılır, yoksa son tip
+func buildFlameTree(prof *profile.Profile) *flameNode {
+	index := len(prof.SampleType) - 1
+	for i, st := range prof.SampleType {
+		if st.Type == "cpu" {
+			index = i
+		}
+	}
+	root := &flameNode{name: "all", children: map[string]*flameNode{}}
+	for _, s := range prof.Sample {
+		v := s.Value[index]
+		root.value += v
+		node := root
+		// Location[0] yapraktır; bir Location'daki satırların sonuncusu, öncekilerin inline
+		// edildiği çağıran fonksiyondur
+		for i := len(s.Location) - 1; i >= 0; i-- {
+			lines := s.Location[i].Line
+			for j := len(lines) - 1; j >= 0; j-- {
+				name := "?"
+				if lines[j].Function != nil {
+					name = lines[j].Function.Name
+				}
+				node = node.child(name)
+				node.value += v
+			}
+		}
+	}
+	return root
+}
+
+// WriteFlamegraph - Profilin flamegraph'ını SVG olarak yazar
+func WriteFlamegraph(w io.Writer, prof *profile.Profile, title string) error {
+	root := buildFlameTree(prof)
+	height := flameTitleHeight + float64(root.depth())*flameFrameHeight
+	if _, err := fmt.Fprintf(w, `<svg xmlns="http://www.w3.org/2000/svg" width="%.0f" height="%.0f" font-family="monospace" font-size="12">
+<rect width="100%%" height="100%%" fill="#f8f8f8"/>
+<text x="%.0f" y="16" text-anchor="middle" font-size="14">%s</text>
+`, flameWidth, height, flameWidth/2, html.EscapeString(title)); err != nil {
+		return err
+	}
+	if root.value > 0 {
+		if err := writeFlameNode(w, root, root.value, 0, height-flameFrameHeight); err != nil {
+			return err
+		}
+	}
+	_, err := io.WriteString(w, "</svg>\n")
+	return err
+}
+
+// writeFlameNode - Çerçeveyi ve çocuklarını (bir üst satıra) çizer
+func writeFlameNode(w io.Writer, n *flameNode, total int64, x, y float64) error {
+	width := float64(n.value) / float64(total) * flameWidth
+	if width < flameMinWidth {
+		return nil
+	}
+	label := n.name
+	if maxChars := int((width - 6) / flameCharWidth); len(label) > maxChars {
+		label = ""
+		if maxChars > 2 {
+			label = n.name[:maxChars-2] + ".."
+		}
+	}
+	if _, err := fmt.Fprintf(w, `<g><title>%s (%.2f%%)</title><rect x="%.2f" y="%.0f" width="%.2f" height="%.0f" fill="%s" rx="2"/><text x="%.2f" y="%.0f">%s</text></g>
+`, html.EscapeString(n.name), float64(n.value)/float64(total)*100, x, y, width, flameFrameHeight-1,
+		flameColor(n.name), x+3, y+flameFrameHeight-4, html.EscapeString(label)); err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(n.children))
+	for name := range n.children {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		c := n.children[name]
+		if err := writeFlameNode(w, c, total, x, y-flameFrameHeight); err != nil {
+			return err
+		}
+		x += float64(c.value) / float64(total) * flameWidth
+	}
+	return nil
+}
+
+// flameColor - Ada göre sabit sıcak renk (aynı fonksiyon her grafikte aynı renkte)
+func flameColor(name string) string {
+	h := fnv.New32a()
+	h.Write([]byte(name))
+	v := h.Sum32()
+	return fmt.Sprintf("rgb(%d,%d,%d)", 205+v%50, 80+(v>>8)%130, 40+(v>>16)%50)
+}
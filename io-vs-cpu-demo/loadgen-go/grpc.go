@@ -0,0 +1,190 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"benchkit"
+	"iovscpu/internal/workloadpb"
+)
+
+// grpc.go - grpc:// ve grpcs:// hedefleri (server-go -grpc-addr, bkz. server-go/grpc.go)
+// URL, REST endpoint'iyle aynı parametreleri taşır; REST ve gRPC aynı komutla karşılaştırılır:
+//
+//	grpc://localhost:4001/cpu?task=sha256&iterations=1000&payload=65536
+//	grpc://localhost:4001/io?delay=10ms&payload=64
+//
+// Tüm worker'lar tek bağlantıyı paylaşır (HTTP/2 çoklama, gRPC'nin olağan kullanımı).
+// payload=N: İstek N bayt taşır, sunucu aynı boyutta yanıt döner; REST'te loadgen aynı
+// boyutta POST gövdesi gönderir.
+
+// grpcCall - Hedef URL'den hazırlanan tek çağrı
+type grpcCall func(ctx context.Context, client workloadpb.WorkloadClient) error
+
+// parseGRPCTarget - URL'yi adres ve çağrıya çevirir
+func parseGRPCTarget(target string) (string, grpcCall, error) {
+	u, err := url.Parse(target)
+	if err != nil {
+		return "", nil, err
+	}
+	query := u.Query()
+	intParam := func(name string) (int64, error) {
+		if query.Get(name) == "" {
+			return 0, nil
+		}
+		n, err := strconv.ParseInt(query.Get(name), 10, 64)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("%s: negatif olmayan bir sayı olmalı", name)
+		}
+		return n, nil
+	}
+	payloadSize, err := intParam("payload")
+	if err != nil {
+		return "", nil, err
+	}
+	payload := make([]byte, payloadSize)
+
+	switch u.Path {
+	case "/cpu":
+		iterations, err := intParam("iterations")
+		if err != nil {
+			return "", nil, err
+		}
+		req := &workloadpb.CPURequest{Task: query.Get("task"), Iterations: iterations, Payload: payload}
+		return u.Host, func(ctx context.Context, client workloadpb.WorkloadClient) error {
+			_, err := client.CPU(ctx, req)
+			return err
+		}, nil
+	case "/io":
+		size, err := intParam("size")
+		if err != nil {
+			return "", nil, err
+		}
+		req := &workloadpb.IORequest{Task: query.Get("task"), Size: size, Payload: payload}
+		if value := query.Get("delay"); value != "" {
+			delay, err := time.ParseDuration(value)
+			if err != nil {
+				return "", nil, fmt.Errorf("delay: %v", err)
+			}
+			req.Delay = durationpb.New(delay)
+		}
+		return u.Host, func(ctx context.Context, client workloadpb.WorkloadClient) error {
+			_, err := client.IO(ctx, req)
+			return err
+		}, nil
+	}
+	return "", nil, fmt.Errorf("gRPC karşılığı olmayan yol %q (/cpu, /io)", u.Path)
+}
+
+// RunGRPCLevel - concurrency worker ile duration boyunca gRPC çağrısı (kapalı döngü)
+// timeout çağrı başına deadline'dır: Sunucuda iş kesilir (REST'teki ?timeout= gibi)
+func RunGRPCLevel(target string, concurrency int, duration, timeout time.Duration, cfg ClientConfig, timeline *benchkit.Timeline) LevelResult {
+	level := LevelResult{
+		Concurrency: concurrency,
+		Started:     time.Now(),
+		Errors:      map[string]int64{},
+		Latency:     benchkit.NewHistogram(),
+		Proto:       "grpc",
+	}
+	addr, call, err := parseGRPCTarget(target)
+	if err == nil {
+		var conn *grpc.ClientConn
+		if conn, err = dialGRPC(target, addr, cfg); err == nil {
+			defer conn.Close()
+			level.NewConns = 1
+			runGRPCWorkers(&level, workloadpb.NewWorkloadClient(conn), call, duration, timeout, timeline)
+			return level
+		}
+	}
+	// Hedef kullanılamaz: Tek hata olarak raporlanır
+	level.Requests, level.Failed = 1, 1
+	timeline.Add(false)
+	level.Errors[err.Error()]++
+	level.Duration = time.Since(level.Started)
+	return level
+}
+
+// dialGRPC - grpc:// şifresiz, grpcs:// TLS (-insecure ile doğrulamasız)
+func dialGRPC(target, addr string, cfg ClientConfig) (*grpc.ClientConn, error) {
+	creds := insecure.NewCredentials()
+	if u, _ := url.Parse(target); u.Scheme == "grpcs" {
+		creds = credentials.NewTLS(&tls.Config{InsecureSkipVerify: cfg.Insecure})
+	}
+	return grpc.NewClient(addr, grpc.WithTransportCredentials(creds))
+}
+
+func runGRPCWorkers(level *LevelResult, client workloadpb.WorkloadClient, call grpcCall, duration, timeout time.Duration, timeline *benchkit.Timeline) {
+	ctx, cancel := context.WithTimeout(context.Background(), duration)
+	defer cancel()
+
+	type workerResult struct {
+		latency  *benchkit.Histogram
+		requests int64
+		failed   int64
+		errors   map[string]int64
+	}
+	results := make([]workerResult, level.Concurrency)
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := range results {
+		wg.Add(1)
+		go func(w *workerResult) {
+			defer wg.Done()
+			w.latency = benchkit.NewHistogram()
+			w.errors = map[string]int64{}
+			for ctx.Err() == nil {
+				reqStart := time.Now()
+				callCtx, callCancel := context.WithTimeout(ctx, timeout)
+				err := call(callCtx, client)
+				callCancel()
+				// Süre dolduğu için iptal edilen son çağrı sayılmaz
+				if ctx.Err() != nil && err != nil {
+					break
+				}
+				w.latency.Record(time.Since(reqStart))
+				w.requests++
+				timeline.Add(err == nil)
+				if err != nil {
+					w.failed++
+					w.errors[grpcErrorKind(err)]++
+				}
+			}
+		}(&results[i])
+	}
+	wg.Wait()
+
+	level.Started = start
+	level.Duration = time.Since(start)
+	for _, w := range results {
+		level.Latency.Merge(w.latency)
+		level.Requests += w.requests
+		level.Failed += w.failed
+		for kind, n := range w.errors {
+			level.Errors[kind] += n
+		}
+	}
+}
+
+// grpcErrorKind - "grpc DeadlineExceeded" gibi
+func grpcErrorKind(err error) string {
+	if s, ok := status.FromError(err); ok {
+		return "grpc " + s.Code().String()
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return "timeout"
+	}
+	return errorKind(err)
+}
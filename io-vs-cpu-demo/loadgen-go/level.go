@@ -0,0 +1,218 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	neturl "net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"benchkit"
+)
+
+// LevelResult - Tek bir eşzamanlılık seviyesinin ölçümü
+type LevelResult struct {
+	Concurrency int
+	Started     time.Time
+	Duration    time.Duration
+	Requests    int64
+	Failed      int64
+	Errors      map[string]int64 // Hata türü ("timeout", "http 503"...) → sayı
+	Latency     *benchkit.Histogram
+	NewConns    int64  // Açılan yeni bağlantı (keep-alive kapalıyken ≈ istek sayısı)
+	PeakConns   int64  // WebSocket: Aynı anda açık en fazla bağlantı
+	Proto       string // Anlaşılan protokol (HTTP/1.1, HTTP/2.0)
+	Timeline    []benchkit.TimelinePoint
+}
+
+// RPS - Başarılı istek / saniye
+func (l LevelResult) RPS() float64 {
+	if l.Duration <= 0 {
+		return 0
+	}
+	return float64(l.Requests-l.Failed) / l.Duration.Seconds()
+}
+
+// ErrorRate - Başarısız istek oranı (0-1)
+func (l LevelResult) ErrorRate() float64 {
+	if l.Requests == 0 {
+		return 0
+	}
+	return float64(l.Failed) / float64(l.Requests)
+}
+
+// Result - Ortak sonuç formatı (diğer lab'larla aynı alanlar)
+// variant hedefin şema+yol+sorgusu ve eşzamanlılıktır (ör: "http/cpu?iterations=1000/c16"):
+// Host dahil edilmez, farklı makinelerdeki aynı ölçüm aynı seride karşılaştırılır
+func (l LevelResult) Result(url string) benchkit.Result {
+	r := benchkit.NewResult("io-vs-cpu-demo", "loadgen", l.Started, l.Duration, l.Requests-l.Failed)
+	r.Variant = fmt.Sprintf("%s/c%d", targetVariant(url), l.Concurrency)
+	r.Params = map[string]string{"url": url, "concurrency": strconv.Itoa(l.Concurrency),
+		"proto": l.Proto, "newConns": strconv.FormatInt(l.NewConns, 10)}
+	if l.PeakConns > 0 {
+		r.Params["peakConns"] = strconv.FormatInt(l.PeakConns, 10)
+	}
+	r.Latency = l.Latency.Summary().Millis()
+	r.Histogram = benchkit.HistogramMs(l.Latency)
+	r.Timeline = l.Timeline
+	r.Errors = l.Failed
+	return r
+}
+
+// targetVariant - "http://localhost:4000/cpu?iterations=1000" → "http/cpu?iterations=1000"
+func targetVariant(target string) string {
+	u, err := neturl.Parse(target)
+	if err != nil {
+		return target
+	}
+	return u.Scheme + u.RequestURI()
+}
+
+// RunLevel - URL'yi concurrency worker ile duration boyunca yükler
+// Her worker kendi histogramını tutar (benchkit.Histogram eşzamanlı kullanıma kapalı), sonunda birleştirilir.
+// Gecikme, başarılı ve başarısız tüm istekler için kaydedilir: Zaman aşımına uğrayan istekler
+// de kullanıcının beklediği süredir. Tamamlanan her istek timeline'a da sayılır.
+func RunLevel(url string, concurrency int, duration, timeout time.Duration, cfg ClientConfig, timeline *benchkit.Timeline) LevelResult {
+	client := newClient(cfg, concurrency, timeout)
+	defer client.CloseIdleConnections()
+	body := requestBody(url)
+
+	ctx, cancel := context.WithTimeout(context.Background(), duration)
+	defer cancel()
+
+	type workerResult struct {
+		latency  *benchkit.Histogram
+		requests int64
+		failed   int64
+		errors   map[string]int64
+		newConns int64
+		proto    string
+	}
+	results := make([]workerResult, concurrency)
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := range results {
+		wg.Add(1)
+		go func(w *workerResult) {
+			defer wg.Done()
+			w.latency = benchkit.NewHistogram()
+			w.errors = map[string]int64{}
+			// Yeniden kullanılmayan her bağlantı bir TCP (ve TLS) el sıkışmasıdır
+			traced := httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+				GotConn: func(info httptrace.GotConnInfo) {
+					if !info.Reused {
+						w.newConns++
+					}
+				},
+			})
+			for ctx.Err() == nil {
+				reqStart := time.Now()
+				proto, err := doRequest(traced, client, url, body)
+				if proto != "" {
+					w.proto = proto
+				}
+				// Süre dolduğu için iptal edilen son istek sayılmaz
+				if ctx.Err() != nil && errors.Is(err, context.DeadlineExceeded) {
+					break
+				}
+				w.latency.Record(time.Since(reqStart))
+				w.requests++
+				timeline.Add(err == nil)
+				if err != nil {
+					w.failed++
+					w.errors[errorKind(err)]++
+				}
+			}
+		}(&results[i])
+	}
+	wg.Wait()
+
+	level := LevelResult{
+		Concurrency: concurrency,
+		Started:     start,
+		Duration:    time.Since(start),
+		Errors:      map[string]int64{},
+		Latency:     benchkit.NewHistogram(),
+	}
+	for _, w := range results {
+		level.Latency.Merge(w.latency)
+		level.Requests += w.requests
+		level.Failed += w.failed
+		level.NewConns += w.newConns
+		if w.proto != "" {
+			level.Proto = w.proto
+		}
+		for kind, n := range w.errors {
+			level.Errors[kind] += n
+		}
+	}
+	return level
+}
+
+// statusError - 2xx dışı yanıt
+type statusError struct {
+	code int
+}
+
+func (e statusError) Error() string {
+	return fmt.Sprintf("http %d", e.code)
+}
+
+// requestBody - URL'de ?payload=N varsa N baytlık istek gövdesi (gRPC'deki payload'ın karşılığı)
+func requestBody(target string) []byte {
+	u, err := neturl.Parse(target)
+	if err != nil {
+		return nil
+	}
+	n, err := strconv.Atoi(u.Query().Get("payload"))
+	if err != nil || n <= 0 {
+		return nil
+	}
+	return make([]byte, n)
+}
+
+// doRequest - Tek istek; gövde sonuna kadar okunur (bağlantı yeniden kullanılabilsin)
+// body varsa POST ile gönderilir. Yanıt alındıysa protokolü (resp.Proto) de döner
+func doRequest(ctx context.Context, client *http.Client, url string, body []byte) (string, error) {
+	method, reader := http.MethodGet, io.Reader(nil)
+	if body != nil {
+		method, reader = http.MethodPost, bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, reader)
+	if err != nil {
+		return "", err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if _, err := io.Copy(io.Discard, resp.Body); err != nil {
+		return resp.Proto, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.Proto, statusError{code: resp.StatusCode}
+	}
+	return resp.Proto, nil
+}
+
+// errorKind - Hatanın rapordaki türü
+func errorKind(err error) string {
+	var status statusError
+	var netErr interface{ Timeout() bool }
+	switch {
+	case errors.As(err, &status):
+		return status.Error()
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return "timeout"
+	default:
+		return "bağlantı"
+	}
+}
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"benchkit"
+)
+
+// loadgen-go - Demo endpoint'leri için yük testi istemcisi
+// Her URL, verilen her eşzamanlılık seviyesinde belirli bir süre boyunca sürekli istekle yüklenir
+// (kapalı döngü: her worker yanıtı alınca bir sonraki isteği gönderir). Her seviye için
+// RPS, p50/p95/p99, hata oranı, açılan yeni bağlantı sayısı ve protokol raporlanır; seviyeler
+// arka arkaya bir throughput eğrisi verir:
+//   - /cpu: RPS çekirdek sayısına kadar artar, sonra sabitlenir ve gecikme eşzamanlılıkla büyür
+//   - /io:  RPS eşzamanlılıkla neredeyse doğrusal artar, gecikme sabit kalır
+//   - ws:// hedefler: Her worker bir WebSocket bağlantısı tutar, RPS mesaj/saniyedir (bkz. websocket.go)
+//   - grpc:// hedefler: /cpu ve /io'nun gRPC karşılığı; ?payload=N ile REST'e karşı (bkz. grpc.go)
+//
+// -json ile her seviye ortak sonuç formatında (benchkit.Result) eklenir: variant (ör: "http/io/c16"),
+// gecikme histogramı ve -timeline aralıklarında throughput. server-go -json aynı dosyaya sunucu
+// tarafını yazar; mongo-perf-lab kayıtlarıyla aynı araçlarla karşılaştırılır.
+//
+// KULLANIM (io-vs-cpu-demo klasöründe, sunucu çalışırken):
+//
+//	go run ./loadgen-go -url "http://localhost:4000/cpu?iterations=10000000"
+//	go run ./loadgen-go -url "http://localhost:4000/cpu?iterations=10000000,http://localhost:4000/io?delay=50ms" -c 1,2,4,8,16,32,64 -d 10s
+//	go run ./loadgen-go -url http://localhost:4000/io -c 100 -d 30s -json results.jsonl
+//	go run ./loadgen-go -url "http://localhost:4000/cpu?iterations=10000000" -c 1,8 -profile-dir results
+//	go run ./loadgen-go -url "http://localhost:4000/io?delay=50ms" -c 16 -profile-dir results -profile-mode both
+//	go run ./loadgen-go -url "https://localhost:4000/io?delay=10ms" -insecure -keepalive=false
+//	go run ./loadgen-go -url ws://localhost:4000/ws -c 10,100,1000 -ws-size 1024
+//	go run ./loadgen-go -url "http://localhost:4000/io?delay=5ms&payload=65536,grpc://localhost:4001/io?delay=5ms&payload=65536"
+func main() {
+	urls := flag.String("url", "http://localhost:4000/cpu", "Yüklenecek URL'ler (virgülle ayrılmış)")
+	levels := flag.String("c", "1,2,4,8,16,32", "Eşzamanlılık seviyeleri (virgülle ayrılmış)")
+	duration := flag.Duration("d", 10*time.Second, "Her seviyenin ölçüm süresi")
+	warmup := flag.Duration("warmup", time.Second, "Her seviyeden önce ölçülmeyen ısınma süresi")
+	timeout := flag.Duration("timeout", 30*time.Second, "İstek zaman aşımı")
+	jsonPath := flag.String("json", "", "Sonuçların ekleneceği JSON Lines dosyası (benchkit formatı)")
+	timelineInterval := flag.Duration("timeline", time.Second, "-json sonuçlarındaki zaman çizelgesinin aralığı (throughput/aralık)")
+	keepAlive := flag.Bool("keepalive", true, "HTTP keep-alive (false: her istek yeni bağlantı açar)")
+	maxIdle := flag.Int("max-idle", 0, "Host başına boşta tutulan bağlantı (0 = eşzamanlılık kadar)")
+	http2 := flag.Bool("http2", true, "https hedeflerde HTTP/2 (false: yalnızca HTTP/1.1)")
+	insecure := flag.Bool("insecure", false, "TLS sertifika doğrulamasını atla (server-go -tls self-signed)")
+	wsSize := flag.Int("ws-size", 64, "ws:// hedeflerde mesaj boyutu (bayt)")
+	wsInterval := flag.Duration("ws-interval", 0, "ws:// hedeflerde yankıdan sonra bir sonraki mesaja kadar bekleme")
+	profileDir := flag.String("profile-dir", "", "Her seviyede sunucunun profilini (-profile-mode) ve flamegraph SVG'sini bu dizine yaz (bkz. profile.go)")
+	profileMode := flag.String("profile-mode", "cpu", "-profile-dir ile alınan profil: cpu, wall (fgprof, I/O beklemesi dahil) veya both")
+	flag.Parse()
+
+	concurrency, err := parseLevels(*levels)
+	if err != nil {
+		fmt.Printf("❌ -c: %v\n", err)
+		os.Exit(2)
+	}
+	profileKinds, err := ParseProfileModes(*profileMode)
+	if err != nil {
+		fmt.Printf("❌ -profile-mode: %v\n", err)
+		os.Exit(2)
+	}
+	targets := strings.Split(*urls, ",")
+	client := ClientConfig{KeepAlive: *keepAlive, MaxIdle: *maxIdle, HTTP2: *http2, Insecure: *insecure}
+	wsCfg := WSConfig{Size: *wsSize, Interval: *wsInterval}
+
+	fmt.Printf("🖥️  %s\n", benchkit.CollectHostInfo())
+	fmt.Printf("🚀 %d URL × %d seviye, seviye başına %v (+%v ısınma)\n", len(targets), len(concurrency), *duration, *warmup)
+
+	for _, target := range targets {
+		target = strings.TrimSpace(target)
+		fmt.Printf("\n=== %s ===\n", target)
+		fmt.Printf("  %6s %10s %10s %10s %10s %10s %8s %8s %9s\n", "c", "RPS", "p50", "p95", "p99", "max", "hata", "bağl.", "protokol")
+
+		run := func(c int, d time.Duration) LevelResult {
+			timeline := benchkit.StartTimeline(*timelineInterval)
+			var level LevelResult
+			switch {
+			case strings.HasPrefix(target, "ws://") || strings.HasPrefix(target, "wss://"):
+				level = RunWSLevel(target, c, d, *timeout, client, wsCfg, timeline)
+			case strings.HasPrefix(target, "grpc://") || strings.HasPrefix(target, "grpcs://"):
+				level = RunGRPCLevel(target, c, d, *timeout, client, timeline)
+			default:
+				level = RunLevel(target, c, d, *timeout, client, timeline)
+			}
+			level.Timeline = timeline.Stop()
+			return level
+		}
+
+		var curve []LevelResult
+		for _, c := range concurrency {
+			if *warmup > 0 {
+				run(c, *warmup)
+			}
+			var captures []*ProfileCapture
+			if *profileDir != "" {
+				for _, kind := range profileKinds {
+					captures = append(captures, StartProfile(target, kind, *duration, client))
+				}
+			}
+			level := run(c, *duration)
+			curve = append(curve, level)
+
+			s := level.Latency.Summary()
+			fmt.Printf("  %6d %10.1f %10v %10v %10v %10v %7.2f%% %8d %9s\n",
+				c, level.RPS(), round(s.P50), round(s.P95), round(s.P99), round(s.Max), level.ErrorRate()*100, level.NewConns, level.Proto)
+			if level.PeakConns > 0 {
+				fmt.Printf("         🔌 açık bağlantı (en fazla): %d\n", level.PeakConns)
+			}
+
+			if *jsonPath != "" {
+				if err := benchkit.AppendJSONL(*jsonPath, level.Result(target)); err != nil {
+					fmt.Printf("⚠️  Sonuç yazılamadı: %v\n", err)
+				}
+			}
+			for _, capture := range captures {
+				saveCapture(*profileDir, target, c, capture)
+			}
+		}
+		printCurve(curve)
+		printErrors(curve)
+	}
+}
+
+// saveCapture - Profil bitene kadar bekler ve dosyalara yazar (hata ölçümü durdurmaz)
+func saveCapture(dir, target string, concurrency int, capture *ProfileCapture) {
+	data, prof, err := capture.Wait()
+	if err == nil {
+		var svg string
+		if svg, err = SaveProfile(dir, target, capture.Kind, concurrency, data, prof); err == nil {
+			fmt.Printf("         🔥 %s\n", svg)
+			return
+		}
+	}
+	fmt.Printf("⚠️  %s profili alınamadı: %v\n", capture.Kind, err)
+}
+
+// parseLevels - "1,2,4" listesini pozitif sayılara çevirir
+func parseLevels(s string) ([]int, error) {
+	var levels []int
+	for _, part := range strings.Split(s, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("geçersiz seviye %q", part)
+		}
+		levels = append(levels, n)
+	}
+	return levels, nil
+}
+
+// printCurve - RPS'in eşzamanlılıkla değişimi (en yüksek RPS = 40 karakter)
+// Verim: RPS artışının eşzamanlılık artışına oranı (ilk seviyeye göre) - %100 doğrusal ölçekleme
+func printCurve(curve []LevelResult) {
+	if len(curve) == 0 {
+		return
+	}
+	peak := 0.0
+	for _, l := range curve {
+		peak = max(peak, l.RPS())
+	}
+	if peak == 0 {
+		return
+	}
+	base := curve[0]
+	fmt.Println("\n  Throughput eğrisi:")
+	for _, l := range curve {
+		efficiency := 0.0
+		if base.RPS() > 0 {
+			efficiency = l.RPS() / base.RPS() / (float64(l.Concurrency) / float64(base.Concurrency)) * 100
+		}
+		fmt.Printf("  %6d %-40s %8.1f RPS  verim %%%.0f\n",
+			l.Concurrency, strings.Repeat("█", int(l.RPS()/peak*40)), l.RPS(), efficiency)
+	}
+}
+
+// printErrors - Hata türlerinin dağılımı (hata yoksa hiçbir şey yazmaz)
+func printErrors(curve []LevelResult) {
+	total := map[string]int64{}
+	for _, l := range curve {
+		for kind, n := range l.Errors {
+			total[kind] += n
+		}
+	}
+	if len(total) == 0 {
+		return
+	}
+	kinds := make([]string, 0, len(total))
+	for kind := range total {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	fmt.Println("\n  Hatalar:")
+	for _, kind := range kinds {
+		fmt.Printf("     - %s: %d\n", kind, total[kind])
+	}
+}
+
+// round - Tabloda okunabilir süre (µs hassasiyeti)
+func round(d time.Duration) time.Duration {
+	if d >= time.Millisecond {
+		return d.Round(10 * time.Microsecond)
+	}
+	return d.Round(time.Microsecond)
+}
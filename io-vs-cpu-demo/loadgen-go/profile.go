@@ -0,0 +1,144 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/google/pprof/profile"
+)
+
+// profile.go - Yük sırasında sunucunun CPU veya wall-clock profili (-profile-dir, -profile-mode)
+// Her seviye ölçülürken sunucunun profil endpoint'i aynı süre için çağrılır:
+// Profil, yalnızca o eşzamanlılıktaki yükü içerir. Ham profil (.pb.gz, go tool pprof ile
+// açılır) ve flamegraph SVG'si (tarayıcıda açılır, bkz. flamegraph.go) dizine yazılır.
+// Sunucu -pprof=false ile çalışıyorsa ya da pprof yoksa (gateway-node) uyarı verilip geçilir.
+//
+// CPU profili (/debug/pprof/profile) yalnızca CPU'da çalışan goroutine'leri örnekler: /io gibi
+// ağ veya Mongo getMore bekleyen istekler profilde neredeyse hiç görünmez. wall modu sunucunun
+// /debug/fgprof endpoint'ini kullanır; fgprof çalışan ve bekleyen tüm goroutine'leri örnekler,
+// flamegraph zamanın nerede beklenerek geçtiğini de gösterir. İkisi aynı anda alınabilir (both).
+
+// profilePaths - Profil türüne göre sunucu endpoint'i
+var profilePaths = map[string]string{
+	"cpu":  "/debug/pprof/profile",
+	"wall": "/debug/fgprof",
+}
+
+// ParseProfileModes - "cpu", "wall" veya "both" → alınacak profil türleri
+func ParseProfileModes(mode string) ([]string, error) {
+	switch mode {
+	case "cpu", "wall":
+		return []string{mode}, nil
+	case "both":
+		return []string{"cpu", "wall"}, nil
+	}
+	return nil, fmt.Errorf("geçersiz profil modu %q (cpu, wall, both)", mode)
+}
+
+// ProfileCapture - Arka planda süren profil isteği
+type ProfileCapture struct {
+	Kind string // cpu, wall
+	done chan struct{}
+	data []byte
+	err  error
+}
+
+// StartProfile - target'ın sunucusundan duration boyunca kind (cpu, wall) profili ister
+// pprof ve fgprof saniye çözünürlüğündedir: Süre aşağı yuvarlanır (en az 1s)
+func StartProfile(target, kind string, duration time.Duration, cfg ClientConfig) *ProfileCapture {
+	c := &ProfileCapture{Kind: kind, done: make(chan struct{})}
+	go func() {
+		defer close(c.done)
+		c.data, c.err = fetchProfile(target, profilePaths[kind], max(1, int(duration/time.Second)), cfg)
+	}()
+	return c
+}
+
+// Wait - Profili bekler ve ayrıştırır
+func (c *ProfileCapture) Wait() ([]byte, *profile.Profile, error) {
+	<-c.done
+	if c.err != nil {
+		return nil, nil, c.err
+	}
+	prof, err := profile.Parse(bytes.NewReader(c.data))
+	if err != nil {
+		return nil, nil, fmt.Errorf("profil ayrıştırılamadı: %v", err)
+	}
+	return c.data, prof, nil
+}
+
+func fetchProfile(target, path string, seconds int, cfg ClientConfig) ([]byte, error) {
+	u, err := url.Parse(target)
+	if err != nil {
+		return nil, err
+	}
+	scheme := map[string]string{"ws": "http", "wss": "https"}[u.Scheme]
+	if scheme == "" {
+		scheme = u.Scheme
+	}
+	profileURL := fmt.Sprintf("%s://%s%s?seconds=%d", scheme, u.Host, path, seconds)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(seconds)*time.Second+30*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, profileURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	// Yükle aynı protokol ayarları: https hedefte -insecure profil isteğine de uygulanır
+	resp, err := newClient(cfg, 1, 0).Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: http %d", profileURL, resp.StatusCode)
+	}
+	return data, nil
+}
+
+// unsafeFileChars - Dosya adında kullanılmayacak karakterler
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// SaveProfile - Ham profili ve flamegraph'ı dir'e yazar, SVG'nin yolunu döner
+// Dosya adı: {host}_{yol}_{sorgu}-c{eşzamanlılık}, wall-clock profilinde sonuna -wall eklenir
+func SaveProfile(dir, target, kind string, concurrency int, data []byte, prof *profile.Profile) (string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	name := target
+	if u, err := url.Parse(target); err == nil {
+		name = u.Host + u.Path + "_" + u.RawQuery
+	}
+	base := filepath.Join(dir, fmt.Sprintf("%s-c%d", unsafeFileChars.ReplaceAllString(name, "_"), concurrency))
+	title := fmt.Sprintf("%s (c=%d)", target, concurrency)
+	if kind == "wall" {
+		base += "-wall"
+		title = fmt.Sprintf("%s (c=%d, wall-clock)", target, concurrency)
+	}
+
+	if err := os.WriteFile(base+".pb.gz", data, 0o644); err != nil {
+		return "", err
+	}
+	svg := base + ".svg"
+	f, err := os.Create(svg)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	if err := WriteFlamegraph(f, prof, title); err != nil {
+		return "", err
+	}
+	return svg, f.Close()
+}
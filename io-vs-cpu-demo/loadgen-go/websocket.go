@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"benchkit"
+	"iovscpu/internal/ws"
+)
+
+// websocket.go - ws:// ve wss:// hedefleri (server-go GET /ws echo)
+// Her worker bir bağlantı açar ve seviye boyunca tutar; mesaj gönderir, yankıyı bekler
+// (kapalı döngü, -ws-interval ile mesajlar arasında bekleme). Gecikme mesaj gidiş-dönüş
+// süresidir; RPS mesaj/saniyedir. Bağlanamayan worker hata sayılır ve durur: Yüksek
+// eşzamanlılıkta "açık bağlantı (en fazla)" sunucunun/OS'un bağlantı sınırını gösterir
+// (ulimit -n, sunucu belleği).
+
+// WSConfig - WebSocket yük ayarları
+type WSConfig struct {
+	Size     int           // Mesaj boyutu (bayt)
+	Interval time.Duration // Yankıdan sonra bir sonraki mesaja kadar bekleme (0 = hemen)
+}
+
+// RunWSLevel - concurrency bağlantıyla duration boyunca mesaj gidiş-dönüşü ölçer
+func RunWSLevel(url string, concurrency int, duration, timeout time.Duration, cfg ClientConfig, wsCfg WSConfig, timeline *benchkit.Timeline) LevelResult {
+	ctx, cancel := context.WithTimeout(context.Background(), duration)
+	defer cancel()
+
+	type workerResult struct {
+		latency  *benchkit.Histogram
+		requests int64
+		failed   int64
+		errors   map[string]int64
+	}
+	results := make([]workerResult, concurrency)
+	tlsConfig := &tls.Config{InsecureSkipVerify: cfg.Insecure}
+	var open, peak, opened atomic.Int64
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := range results {
+		wg.Add(1)
+		go func(w *workerResult) {
+			defer wg.Done()
+			w.latency = benchkit.NewHistogram()
+			w.errors = map[string]int64{}
+
+			dialCtx, dialCancel := context.WithTimeout(ctx, timeout)
+			conn, err := ws.Dial(dialCtx, url, tlsConfig)
+			dialCancel()
+			if err != nil {
+				if ctx.Err() == nil {
+					w.requests++
+					w.failed++
+					w.errors[wsErrorKind(err)]++
+					timeline.Add(false)
+				}
+				return
+			}
+			opened.Add(1)
+			current := open.Add(1)
+			for p := peak.Load(); current > p && !peak.CompareAndSwap(p, current); p = peak.Load() {
+			}
+			defer open.Add(-1)
+			defer conn.CloseGracefully()
+			// Seviye bittiğinde bekleyen okuma bağlantı kapatılarak kesilir
+			stop := context.AfterFunc(ctx, func() { conn.Close() })
+			defer stop()
+
+			message := make([]byte, wsCfg.Size)
+			rand.Read(message)
+			for ctx.Err() == nil {
+				reqStart := time.Now()
+				err := conn.WriteMessage(ws.OpBinary, message)
+				if err == nil {
+					var echo []byte
+					if _, echo, err = conn.ReadMessage(); err == nil && len(echo) != len(message) {
+						err = fmt.Errorf("yankı %d bayt, beklenen %d", len(echo), len(message))
+					}
+				}
+				if ctx.Err() != nil {
+					break // Süre dolduğu için kesilen son mesaj sayılmaz
+				}
+				w.latency.Record(time.Since(reqStart))
+				w.requests++
+				timeline.Add(err == nil)
+				if err != nil {
+					w.failed++
+					w.errors[wsErrorKind(err)]++
+					return // Bağlantı kullanılamaz
+				}
+				if wsCfg.Interval > 0 {
+					select {
+					case <-ctx.Done():
+					case <-time.After(wsCfg.Interval):
+					}
+				}
+			}
+		}(&results[i])
+	}
+	wg.Wait()
+
+	level := LevelResult{
+		Concurrency: concurrency,
+		Started:     start,
+		Duration:    time.Since(start),
+		Errors:      map[string]int64{},
+		Latency:     benchkit.NewHistogram(),
+		NewConns:    opened.Load(),
+		PeakConns:   peak.Load(),
+		Proto:       "websocket",
+	}
+	for _, w := range results {
+		level.Latency.Merge(w.latency)
+		level.Requests += w.requests
+		level.Failed += w.failed
+		for kind, n := range w.errors {
+			level.Errors[kind] += n
+		}
+	}
+	return level
+}
+
+// wsErrorKind - WebSocket hatasının rapordaki türü
+func wsErrorKind(err error) string {
+	switch {
+	case errors.Is(err, ws.ErrClosed):
+		return "ws kapatıldı"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	default:
+		return errorKind(err)
+	}
+}
@@ -0,0 +1,233 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"benchkit"
+)
+
+// autoscale.go - Pool modunda worker sayısını yüke göre değiştiren denetleyici (-autoscale)
+// Sabit -workers ya boşta bekleyen kapasite (fazla) ya da kuyrukta biriken iş (az) demektir.
+// Denetleyici her -autoscale interval'inde havuzun durumuna bakar:
+//   - Büyütme: Worker başına kuyruktaki iş depth'i aşıyorsa ya da pencerede işe başlayanların
+//     kuyruk beklemesi (p95) wait hedefini aşıyorsa worker sayısı up ile çarpılır (en az +1)
+//   - Küçültme: Kuyruk boşsa, worker'ların yarısından azı meşgulse ve son değişiklikten beri
+//     cooldown geçtiyse worker'ların dörtte biri (en az 1) bırakılır
+//
+// Büyütme hemen, küçültme gecikmeli yapılır: Yük kısa süre düşüp geri geldiğinde havuzun
+// salınması (flapping) önlenir. Kararlar konsola yazılır, havuz büyüklüğünün zaman çizelgesi
+// GET /job/stats'ta, anlık değerler /metrics'te (pool_workers, pool_scale_events_total) görünür.
+// Politikayı denemek için yükü basamaklı artırıp azaltın:
+//
+//	go run ./server-go -job-mode pool -workers 2 -queue 256 -autoscale "min=2,max=64,wait=50ms,cooldown=5s"
+//	go run ./loadgen-go -url "http://localhost:4000/job?io=50ms" -c 4,32,128,32,4 -d 15s
+
+// autoscaleHistory - Zaman çizelgesinde tutulan en fazla nokta (1s aralıkla 10 dakika)
+const autoscaleHistory = 600
+
+// AutoscaleConfig - Ölçekleme politikası
+type AutoscaleConfig struct {
+	Min      int
+	Max      int
+	Interval time.Duration
+	Wait     time.Duration // Kuyruk beklemesi (p95) hedefi
+	Depth    float64       // Worker başına kuyrukta bekleyen iş hedefi
+	Cooldown time.Duration // Küçültmeden önce son değişiklikten beri geçmesi gereken süre
+	Up       float64       // Büyütme çarpanı
+}
+
+// defaultAutoscale - "-autoscale on" ve verilmeyen anahtarlar
+var defaultAutoscale = AutoscaleConfig{Min: 1, Max: 64, Interval: time.Second, Wait: 100 * time.Millisecond,
+	Depth: 1, Cooldown: 10 * time.Second, Up: 2}
+
+// ParseAutoscale - "min=2,max=64,wait=50ms,depth=1,interval=1s,cooldown=10s,up=2" veya "on"
+func ParseAutoscale(s string) (AutoscaleConfig, error) {
+	cfg := defaultAutoscale
+	if s == "on" {
+		return cfg, nil
+	}
+	for _, part := range strings.Split(s, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return cfg, fmt.Errorf("geçersiz ayar %q (ör: max=64)", part)
+		}
+		var err error
+		switch key {
+		case "min":
+			cfg.Min, err = strconv.Atoi(value)
+		case "max":
+			cfg.Max, err = strconv.Atoi(value)
+		case "interval":
+			cfg.Interval, err = time.ParseDuration(value)
+		case "wait":
+			cfg.Wait, err = time.ParseDuration(value)
+		case "cooldown":
+			cfg.Cooldown, err = time.ParseDuration(value)
+		case "depth":
+			cfg.Depth, err = strconv.ParseFloat(value, 64)
+		case "up":
+			cfg.Up, err = strconv.ParseFloat(value, 64)
+		default:
+			return cfg, fmt.Errorf("bilinmeyen ayar %q (min, max, interval, wait, depth, cooldown, up)", key)
+		}
+		if err != nil {
+			return cfg, fmt.Errorf("%s: %v", key, err)
+		}
+	}
+	switch {
+	case cfg.Min < 1 || cfg.Max < cfg.Min:
+		return cfg, fmt.Errorf("1 <= min <= max olmalı")
+	case cfg.Interval <= 0 || cfg.Wait <= 0 || cfg.Cooldown < 0:
+		return cfg, fmt.Errorf("interval ve wait pozitif, cooldown negatif olmayan bir süre olmalı")
+	case cfg.Depth < 0 || cfg.Up <= 1:
+		return cfg, fmt.Errorf("depth negatif olmamalı, up 1'den büyük olmalı")
+	}
+	return cfg, nil
+}
+
+// AutoscalePoint - Zaman çizelgesinde bir karar anı
+type AutoscalePoint struct {
+	AtMs       float64 `json:"atMs"` // Denetleyici başladıktan sonra
+	Workers    int     `json:"workers"`
+	Busy       int     `json:"busy"`
+	QueueDepth int     `json:"queueDepth"`
+	WaitP95Ms  float64 `json:"waitP95Ms"`
+	JobsPerSec float64 `json:"jobsPerSec"`
+	Action     string  `json:"action,omitempty"` // "up 8→16", "down 16→12"
+}
+
+// Autoscaler - JobPool'un worker sayısını AutoscaleConfig'e göre değiştirir
+type Autoscaler struct {
+	pool  *JobPool
+	cfg   AutoscaleConfig
+	start time.Time
+
+	mu            sync.Mutex
+	timeline      []AutoscalePoint
+	ups, downs    int64
+	lastChange    time.Time
+	lastCompleted int64
+}
+
+// StartAutoscaler - Havuzu cfg.Min ile cfg.Max arasına getirir ve denetleyiciyi başlatır
+func StartAutoscaler(pool *JobPool, cfg AutoscaleConfig) *Autoscaler {
+	now := time.Now()
+	a := &Autoscaler{pool: pool, cfg: cfg, start: now, lastChange: now}
+	if workers := pool.Stats().Workers; workers < cfg.Min || workers > cfg.Max {
+		pool.Resize(min(max(workers, cfg.Min), cfg.Max))
+	}
+	go func() {
+		ticker := time.NewTicker(cfg.Interval)
+		defer ticker.Stop()
+		for now := range ticker.C {
+			a.tick(now)
+		}
+	}()
+	return a
+}
+
+// tick - Tek karar: Pencereyi okur, gerekirse havuzu yeniden boyutlandırır
+func (a *Autoscaler) tick(now time.Time) {
+	w := a.pool.TakeWindow()
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	point := AutoscalePoint{
+		AtMs:       benchkit.Millis(now.Sub(a.start)),
+		Workers:    w.Workers,
+		Busy:       w.Busy,
+		QueueDepth: w.Depth,
+		WaitP95Ms:  benchkit.Millis(w.WaitP95),
+		JobsPerSec: float64(w.Completed-a.lastCompleted) / a.cfg.Interval.Seconds(),
+	}
+	a.lastCompleted = w.Completed
+
+	target := a.decide(w, now)
+	if target != w.Workers {
+		direction := "up"
+		if target < w.Workers {
+			direction = "down"
+			a.downs++
+			fmt.Printf("📉 worker %d → %d (meşgul %d, kuyruk boş)\n", w.Workers, target, w.Busy)
+		} else {
+			a.ups++
+			fmt.Printf("📈 worker %d → %d (kuyruk %d, bekleme p95 %v)\n", w.Workers, target, w.Depth, w.WaitP95.Round(time.Millisecond))
+		}
+		point.Action = fmt.Sprintf("%s %d→%d", direction, w.Workers, target)
+		a.pool.Resize(target)
+		a.lastChange = now
+	}
+
+	a.timeline = append(a.timeline, point)
+	if len(a.timeline) > autoscaleHistory {
+		a.timeline = a.timeline[len(a.timeline)-autoscaleHistory:]
+	}
+}
+
+// decide - Politikanın önerdiği worker sayısı
+func (a *Autoscaler) decide(w poolWindow, now time.Time) int {
+	cfg := a.cfg
+	pressure := float64(w.Depth) > cfg.Depth*float64(w.Workers) || (w.Started > 0 && w.WaitP95 > cfg.Wait)
+	if pressure {
+		grown := int(math.Ceil(float64(w.Workers) * cfg.Up))
+		return min(cfg.Max, max(w.Workers+1, grown))
+	}
+	idle := w.Depth == 0 && float64(w.Busy) < 0.5*float64(w.Workers)
+	if idle && now.Sub(a.lastChange) >= cfg.Cooldown {
+		// Meşgul worker'lar %70 kullanımda kalacak kadar worker bırakılır
+		shrunk := max(w.Workers-max(1, w.Workers/4), int(math.Ceil(float64(w.Busy)/0.7)))
+		return max(cfg.Min, min(w.Workers, shrunk))
+	}
+	return w.Workers
+}
+
+// AutoscaleStats - /job/stats'taki autoscale bölümü
+type AutoscaleStats struct {
+	Min        int              `json:"min"`
+	Max        int              `json:"max"`
+	IntervalMs float64          `json:"intervalMs"`
+	WaitMs     float64          `json:"waitTargetMs"`
+	Depth      float64          `json:"depthTarget"`
+	CooldownMs float64          `json:"cooldownMs"`
+	Up         float64          `json:"upFactor"`
+	ScaleUps   int64            `json:"scaleUps"`
+	ScaleDowns int64            `json:"scaleDowns"`
+	Timeline   []AutoscalePoint `json:"timeline"`
+}
+
+// Stats - Politika, karar sayıları ve zaman çizelgesi (kopya)
+func (a *Autoscaler) Stats() AutoscaleStats {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return AutoscaleStats{
+		Min:        a.cfg.Min,
+		Max:        a.cfg.Max,
+		IntervalMs: benchkit.Millis(a.cfg.Interval),
+		WaitMs:     benchkit.Millis(a.cfg.Wait),
+		Depth:      a.cfg.Depth,
+		CooldownMs: benchkit.Millis(a.cfg.Cooldown),
+		Up:         a.cfg.Up,
+		ScaleUps:   a.ups,
+		ScaleDowns: a.downs,
+		Timeline:   append([]AutoscalePoint(nil), a.timeline...),
+	}
+}
+
+// WritePrometheus - pool_* metrikleri (HTTPMetrics.AddCollector ile bağlanır)
+func (a *Autoscaler) WritePrometheus(b *strings.Builder) {
+	pool := a.pool.Stats()
+	a.mu.Lock()
+	ups, downs := a.ups, a.downs
+	a.mu.Unlock()
+	fmt.Fprintf(b, "# HELP pool_workers Hedef worker sayısı\n# TYPE pool_workers gauge\npool_workers %d\n", pool.Workers)
+	fmt.Fprintf(b, "# HELP pool_workers_busy İş çalıştıran worker\n# TYPE pool_workers_busy gauge\npool_workers_busy %d\n", pool.Busy)
+	fmt.Fprintf(b, "# HELP pool_queue_depth Kuyrukta bekleyen iş\n# TYPE pool_queue_depth gauge\npool_queue_depth %d\n", pool.QueueDepth)
+	b.WriteString("# HELP pool_scale_events_total Ölçekleme kararları\n# TYPE pool_scale_events_total counter\n")
+	fmt.Fprintf(b, "pool_scale_events_total{direction=\"up\"} %d\n", ups)
+	fmt.Fprintf(b, "pool_scale_events_total{direction=\"down\"} %d\n", downs)
+}
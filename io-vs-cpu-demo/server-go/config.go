@@ -0,0 +1,136 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+// config.go - GET/PUT /config: Sunucuyu yeniden başlatmadan iş miktarını ve kapasiteyi değiştirme
+// Bir parametre taraması (ör: worker sayısı 1..64 × gecikme 10ms..1s) her noktada sunucuyu
+// yeniden başlatırsa ısınma (bağlantı havuzları, önbellekler, GC hedefi) her
+// seferinde baştan ödenir. Harness taramayı tek sunucuda PUT /config ile yürütür:
+//
+//	curl -X PUT localhost:4000/config -d '{"iterations": 1000000, "delay": "50ms"}'
+//	curl -X PUT localhost:4000/config -d '{"limits": {"/cpu": 4}, "workers": 16}'
+//
+// Alanların hepsi isteğe bağlıdır; verilmeyenler değişmez. Önce tüm alanlar doğrulanır, biri
+// geçersizse hiçbiri uygulanmaz (400). Yanıt, güncel yapılandırmadır (GET ile aynı).
+//   - iterations, delay: Parametresiz isteklerin varsayılanları (-iterations, -delay)
+//   - limits: Endpoint başına eşzamanlılık sınırı (POST /limits'in toplu hali, 0 = sınırsız)
+//   - workers: Pool modunda worker sayısı; -autoscale açıkken ya da diğer modlarda 409
+
+// configUpdate - PUT /config gövdesi
+type configUpdate struct {
+	Iterations *int64         `json:"iterations,omitempty"`
+	Delay      *string        `json:"delay,omitempty"`
+	Limits     map[string]int `json:"limits,omitempty"`
+	Workers    *int           `json:"workers,omitempty"`
+}
+
+// configView - GET /config yanıtı
+type configView struct {
+	Iterations int64          `json:"iterations"`
+	Delay      string         `json:"delay"`
+	Limits     map[string]int `json:"limits"`
+	JobMode    string         `json:"jobMode"`
+	Workers    int            `json:"workers,omitempty"` // Pool modunda
+	Autoscale  bool           `json:"autoscale,omitempty"`
+}
+
+// configHandler - Değiştirilebilen bileşenler (pool ve autoscaler nil olabilir)
+type configHandler struct {
+	defaults   *workloadDefaults
+	limiter    *Limiter
+	pool       *JobPool
+	autoscaler *Autoscaler
+	jobMode    string
+}
+
+// ServeHTTP - GET /config, PUT /config
+func (h *configHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodPut {
+		var update configUpdate
+		decoder := json.NewDecoder(r.Body)
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&update); err != nil {
+			http.Error(w, fmt.Sprintf("geçersiz JSON: %v", err), http.StatusBadRequest)
+			return
+		}
+		if status, err := h.apply(update); err != nil {
+			http.Error(w, err.Error(), status)
+			return
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(h.view())
+}
+
+// apply - Güncellemeyi doğrular ve uygular; hata durumunda HTTP kodu da döner
+func (h *configHandler) apply(update configUpdate) (int, error) {
+	iterations, delay := h.defaults.Iterations(), h.defaults.Delay()
+	if update.Iterations != nil {
+		if *update.Iterations < 0 || *update.Iterations > maxIterations {
+			return http.StatusBadRequest, fmt.Errorf("iterations: 0 ile %d arasında olmalı", int64(maxIterations))
+		}
+		iterations = *update.Iterations
+	}
+	if update.Delay != nil {
+		d, err := time.ParseDuration(*update.Delay)
+		if err != nil || d < 0 || d > maxDelay {
+			return http.StatusBadRequest, fmt.Errorf("delay: 0 ile %v arasında bir süre olmalı (ör: 50ms)", maxDelay)
+		}
+		delay = d
+	}
+	for name, limit := range update.Limits {
+		if !slices.Contains(limitedEndpoints, name) || limit < 0 {
+			return http.StatusBadRequest, fmt.Errorf("limits: %q için negatif olmayan sınır (%v)", name, limitedEndpoints)
+		}
+	}
+	if update.Workers != nil {
+		switch {
+		case h.pool == nil:
+			return http.StatusConflict, fmt.Errorf("workers: yalnızca pool modunda değişir (-job-mode %s)", h.jobMode)
+		case h.autoscaler != nil:
+			return http.StatusConflict, fmt.Errorf("workers: -autoscale açıkken worker sayısını denetleyici belirler")
+		case *update.Workers <= 0:
+			return http.StatusBadRequest, fmt.Errorf("workers: pozitif olmalı")
+		}
+	}
+
+	var changes []string
+	if update.Iterations != nil || update.Delay != nil {
+		h.defaults.Set(iterations, delay)
+		changes = append(changes, fmt.Sprintf("iterations=%d delay=%v", iterations, delay))
+	}
+	for name, limit := range update.Limits {
+		h.limiter.SetLimit(name, limit)
+		changes = append(changes, fmt.Sprintf("limit %s=%d", name, limit))
+	}
+	if update.Workers != nil {
+		h.pool.Resize(*update.Workers)
+		changes = append(changes, fmt.Sprintf("workers=%d", *update.Workers))
+	}
+	if len(changes) > 0 {
+		fmt.Printf("⚙️  config: %s\n", strings.Join(changes, ", "))
+	}
+	return http.StatusOK, nil
+}
+
+// view - Güncel yapılandırma
+func (h *configHandler) view() configView {
+	v := configView{
+		Iterations: h.defaults.Iterations(),
+		Delay:      h.defaults.Delay().String(),
+		Limits:     h.limiter.Limits(),
+		JobMode:    h.jobMode,
+		Autoscale:  h.autoscaler != nil,
+	}
+	if h.pool != nil {
+		v.Workers = h.pool.Stats().Workers
+	}
+	return v
+}
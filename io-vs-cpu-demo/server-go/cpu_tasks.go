@@ -0,0 +1,286 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// cpu_tasks.go - Seçilebilir CPU iş yükleri (?task=)
+// Toplama döngüsü yalnızca ALU'yu meşgul eder: Bellek, önbellek ve ayırma (allocation) baskısı
+// yoktur. Gerçek CPU-bound işler farklı davranır - matris çarpımı önbelleğe, JSON ve regexp
+// ayırmaya ve GC'ye, sieve bellek bant genişliğine dayanır. Aynı eşzamanlılıkta farklı görevler
+// farklı ölçeklenir (GC, bellek bant genişliği paylaşılır).
+//
+// Her görevin tek bir boyut parametresi (n) vardır; anlamı göreve göre değişir. Varsayılan n
+// değerleri, tek çekirdekte aynı mertebede süreye (~20-50ms) denk gelecek şekilde seçilmiştir;
+// GET /cpu/tasks?calibrate=100ms bu makinede hedef süreye denk gelen n'leri ölçer.
+
+// cpuTask - Kayıtlı bir CPU iş yükü
+type cpuTask struct {
+	Name    string `json:"name"`
+	Param   string `json:"param"`   // n'nin anlamı
+	Default int64  `json:"default"` // Varsayılan n (~20-50ms)
+	Max     int64  `json:"max"`     // Tek istekte izin verilen en büyük n
+	run     func(ctx context.Context, n int64) (int64, error)
+}
+
+// cpuTasks - Görev kaydı; ?task= verilmezse "sum"
+// sum'ın varsayılanı yerine -iterations kullanılır (workloadDefaults.taskDefault, PUT /config ile değişir)
+var cpuTasks = map[string]*cpuTask{
+	"sum":    {Name: "sum", Param: "iterasyon", Default: 50_000_000, Max: maxIterations, run: cpuHeavyTask},
+	"sieve":  {Name: "sieve", Param: "üst sınır (asal sayılar)", Default: 5_000_000, Max: 500_000_000, run: sieveTask},
+	"matrix": {Name: "matrix", Param: "boyut (n×n float64)", Default: 250, Max: 3_000, run: matrixTask},
+	"sha256": {Name: "sha256", Param: "1 KiB blok sayısı (zincirleme)", Default: 40_000, Max: 100_000_000, run: sha256Task},
+	"json":   {Name: "json", Param: "kayıt sayısı (marshal + unmarshal)", Default: 20_000, Max: 10_000_000, run: jsonTask},
+	"regexp": {Name: "regexp", Param: "log satırı sayısı", Default: 50_000, Max: 50_000_000, run: regexpTask},
+}
+
+// defaultCPUTask - ?task= verilmediğinde kullanılan görev
+const defaultCPUTask = "sum"
+
+// lookupCPUTask - Görevi adıyla bulur
+func lookupCPUTask(name string) (*cpuTask, error) {
+	if name == "" {
+		name = defaultCPUTask
+	}
+	task, ok := cpuTasks[name]
+	if !ok {
+		return nil, fmt.Errorf("task: bilinmeyen görev %q (%v)", name, cpuTaskNames())
+	}
+	return task, nil
+}
+
+func cpuTaskNames() []string {
+	names := make([]string, 0, len(cpuTasks))
+	for name := range cpuTasks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// runCPUTask - Görevi çalıştırır; n = 0 ise CPU işi yapılmaz
+// Görevler ctx'i düzenli aralıklarla kontrol eder: İptalde yarım sonuçla ctx.Err() döner
+func runCPUTask(ctx context.Context, name string, n int64) (int64, error) {
+	if n == 0 {
+		return 0, nil
+	}
+	task, err := lookupCPUTask(name)
+	if err != nil {
+		// Parametreler istekte doğrulanır; buraya yalnızca eski sürümün kuyruğa yazdığı işler düşer
+		task = cpuTasks[defaultCPUTask]
+	}
+	return task.run(ctx, n)
+}
+
+// cpuTaskParams - ?task= ve boyut parametresi
+// Boyut verilmezse görevin varsayılanı kullanılır; optional ise (/mixed, /job) görev de
+// seçilmemişse CPU işi yapılmaz (n = 0)
+func cpuTaskParams(r *http.Request, defaults *workloadDefaults, sizeName string, optional bool) (string, int64, error) {
+	query := r.URL.Query()
+	task, err := lookupCPUTask(query.Get("task"))
+	if err != nil {
+		return "", 0, err
+	}
+	def := defaults.taskDefault(task)
+	if optional && query.Get("task") == "" {
+		def = 0
+	}
+	n, err := iterationsParam(r, sizeName, def)
+	if err != nil {
+		return "", 0, err
+	}
+	if n > task.Max {
+		return "", 0, fmt.Errorf("%s: %s görevi için en fazla %d", sizeName, task.Name, task.Max)
+	}
+	return task.Name, n, nil
+}
+
+// sieveTask - Eratosthenes kalburu: n'e kadar asal sayısı
+func sieveTask(ctx context.Context, n int64) (int64, error) {
+	composite := make([]bool, n+1)
+	var count int64
+	for i := int64(2); i <= n; i++ {
+		if i%ctxCheckEvery == 0 && ctx.Err() != nil {
+			return count, ctx.Err()
+		}
+		if composite[i] {
+			continue
+		}
+		count++
+		for j := i * i; j <= n; j += i {
+			composite[j] = true
+		}
+	}
+	return count, nil
+}
+
+// matrixTask - İki n×n matrisin çarpımı (i-k-j sırası, önbellek dostu); sonuç izinin tam kısmı
+// ctx her satırda kontrol edilir (bir satır n² çarpma)
+func matrixTask(ctx context.Context, n int64) (int64, error) {
+	size := int(n)
+	a := make([]float64, size*size)
+	b := make([]float64, size*size)
+	c := make([]float64, size*size)
+	for i := range a {
+		a[i] = float64(i%7) + 0.5
+		b[i] = float64(i%5) - 1.5
+	}
+	for i := 0; i < size; i++ {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+		for k := 0; k < size; k++ {
+			aik := a[i*size+k]
+			for j := 0; j < size; j++ {
+				c[i*size+j] += aik * b[k*size+j]
+			}
+		}
+	}
+	var trace float64
+	for i := 0; i < size; i++ {
+		trace += c[i*size+i]
+	}
+	return int64(trace), nil
+}
+
+// sha256Task - 1 KiB bloğu n kez zincirleme hash'ler (her tur öncekinin özetini içerir)
+func sha256Task(ctx context.Context, n int64) (int64, error) {
+	block := make([]byte, 1024)
+	var sum [sha256.Size]byte
+	for i := int64(0); i < n; i++ {
+		if i%1024 == 0 && ctx.Err() != nil {
+			return 0, ctx.Err()
+		}
+		copy(block, sum[:])
+		sum = sha256.Sum256(block)
+	}
+	return int64(binary.BigEndian.Uint64(sum[:8]) >> 1), nil
+}
+
+// jsonRecord - JSON görevinin kaydı (tipik bir API yanıt öğesi)
+type jsonRecord struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email"`
+	Tags      []string  `json:"tags"`
+	Score     float64   `json:"score"`
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// jsonTask - n kaydı marshal edip geri unmarshal eder; sonuç JSON boyutu (bayt)
+// Marshal ve Unmarshal kesilemez: ctx yalnızca aşamalar arasında kontrol edilir
+func jsonTask(ctx context.Context, n int64) (int64, error) {
+	records := make([]jsonRecord, n)
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := range records {
+		id := int64(i)
+		records[i] = jsonRecord{
+			ID:        id,
+			Name:      "user-" + strconv.FormatInt(id, 10),
+			Email:     "user" + strconv.FormatInt(id, 10) + "@example.com",
+			Tags:      []string{"a", "b", "c"},
+			Score:     float64(id%1000) / 10,
+			Active:    id%2 == 0,
+			CreatedAt: created.Add(time.Duration(id) * time.Second),
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	data, err := json.Marshal(records)
+	if err != nil {
+		return 0, err
+	}
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	var decoded []jsonRecord
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return 0, err
+	}
+	return int64(len(data)), nil
+}
+
+// logLinePattern - regexp görevinin deseni: Zaman aşımı hataları ve süreleri
+var logLinePattern = regexp.MustCompile(`^(\d{4}-\d{2}-\d{2})T[\d:]+ (ERROR|WARN) \[(\w+)\] .*timeout after (\d+)ms$`)
+
+// regexpTask - n log satırını üretip desenle eşleştirir; sonuç eşleşen satır sayısı
+func regexpTask(ctx context.Context, n int64) (int64, error) {
+	levels := []string{"INFO", "WARN", "ERROR", "DEBUG"}
+	var matches int64
+	for i := int64(0); i < n; i++ {
+		if i%1024 == 0 && ctx.Err() != nil {
+			return matches, ctx.Err()
+		}
+		line := fmt.Sprintf("2024-03-%02dT12:%02d:%02d %s [svc%d] request %d finished: timeout after %dms",
+			i%28+1, i%60, i%60, levels[i%4], i%16, i, i%5000)
+		if logLinePattern.MatchString(line) {
+			matches++
+		}
+	}
+	return matches, nil
+}
+
+// calibrateCPUTask - Bu makinede target süreye denk gelen n (tek goroutine)
+// n ikiye katlanarak aşılır, sonra aralık ikiye bölünerek daraltılır
+func calibrateCPUTask(task *cpuTask, target time.Duration) (int64, time.Duration) {
+	measure := func(n int64) time.Duration {
+		start := time.Now()
+		task.run(context.Background(), n)
+		return time.Since(start)
+	}
+	lo, hi := int64(0), int64(1)
+	elapsed := measure(hi)
+	for elapsed < target && hi < task.Max {
+		lo, hi = hi, min(hi*2, task.Max)
+		elapsed = measure(hi)
+	}
+	for i := 0; i < 6 && hi-lo > 1; i++ {
+		mid := lo + (hi-lo)/2
+		if d := measure(mid); d < target {
+			lo = mid
+		} else {
+			hi, elapsed = mid, d
+		}
+	}
+	return hi, elapsed
+}
+
+// cpuTasksHandler - GET /cpu/tasks[?calibrate=100ms]
+// calibrate verilirse her görev ölçülür: İstek saniyeler sürebilir ve ölçüm sırasında CPU meşguldür
+func cpuTasksHandler(defaults *workloadDefaults) http.HandlerFunc {
+	type taskInfo struct {
+		*cpuTask
+		Default      int64   `json:"default"` // sum için güncel -iterations
+		CalibratedN  int64   `json:"calibratedN,omitempty"`
+		CalibratedMs float64 `json:"calibratedMs,omitempty"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		target, err := delayParam(r, "calibrate", 0)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		var tasks []taskInfo
+		for _, name := range cpuTaskNames() {
+			info := taskInfo{cpuTask: cpuTasks[name], Default: defaults.taskDefault(cpuTasks[name])}
+			if target > 0 {
+				n, elapsed := calibrateCPUTask(info.cpuTask, target)
+				info.CalibratedN, info.CalibratedMs = n, float64(elapsed)/float64(time.Millisecond)
+			}
+			tasks = append(tasks, info)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(tasks)
+	}
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"iovscpu/internal/workloadpb"
+)
+
+// grpc.go - /cpu ve /io'nun gRPC karşılığı (-grpc-addr, bkz. internal/workloadpb/workload.proto)
+// Aynı görevler (cpu_tasks.go, io_tasks.go) aynı sınırlarla çalışır; fark yalnızca protokoldür:
+// HTTP/2 üzerinde protobuf, tek bağlantıda çoklu istek. loadgen-go grpc:// hedefleriyle REST'e
+// karşı ölçer:
+//
+//	go run ./loadgen-go -url "http://localhost:4000/cpu?iterations=100000&payload=65536,grpc://localhost:4001/cpu?iterations=100000&payload=65536"
+//
+// Hata eşlemesi REST ile aynı mantıkta (bkz. timeout.go): Zaman aşımı DEADLINE_EXCEEDED (504),
+// iptal CANCELLED (499), geçersiz parametre INVALID_ARGUMENT (400), I/O hedefi hatası
+// UNAVAILABLE (502). İstekler /metrics'te method="GRPC" ile sayılır.
+
+// workloadServer - workloadpb.WorkloadServer
+type workloadServer struct {
+	workloadpb.UnimplementedWorkloadServer
+	defaults *workloadDefaults
+}
+
+// CPU - GET /cpu karşılığı
+func (s *workloadServer) CPU(ctx context.Context, req *workloadpb.CPURequest) (*workloadpb.CPUReply, error) {
+	task, err := lookupCPUTask(req.GetTask())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	n := req.GetIterations()
+	if n == 0 {
+		n = s.defaults.taskDefault(task)
+	}
+	if n < 0 || n > task.Max {
+		return nil, status.Errorf(codes.InvalidArgument, "iterations: %s görevi için 0 ile %d arasında", task.Name, task.Max)
+	}
+	start := time.Now()
+	result, err := runCPUTask(ctx, task.Name, n)
+	if err != nil {
+		return nil, workStatus(fmt.Sprintf("CPU (task=%s)", task.Name), err)
+	}
+	return &workloadpb.CPUReply{
+		Result:     result,
+		Task:       task.Name,
+		Iterations: n,
+		Elapsed:    durationpb.New(time.Since(start)),
+		Payload:    make([]byte, len(req.GetPayload())),
+	}, nil
+}
+
+// IO - GET /io karşılığı
+func (s *workloadServer) IO(ctx context.Context, req *workloadpb.IORequest) (*workloadpb.IOReply, error) {
+	task, err := lookupIOTask(req.GetTask())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	n := req.GetSize()
+	if n == 0 {
+		n = task.Default
+	}
+	if n < 0 || n > task.Max {
+		return nil, status.Errorf(codes.InvalidArgument, "size: %s görevi için 0 ile %d arasında", task.Name, task.Max)
+	}
+	delay := s.defaults.Delay()
+	if req.GetDelay() != nil {
+		delay = req.GetDelay().AsDuration()
+	}
+	if delay < 0 || delay > maxDelay {
+		return nil, status.Errorf(codes.InvalidArgument, "delay: 0 ile %v arasında", maxDelay)
+	}
+	start := time.Now()
+	result, err := runIOTask(ctx, task.Name, n, delay)
+	if err != nil {
+		return nil, workStatus(fmt.Sprintf("IO (task=%s)", task.Name), err)
+	}
+	return &workloadpb.IOReply{
+		Result:  result,
+		Task:    task.Name,
+		Elapsed: durationpb.New(time.Since(start)),
+		Payload: make([]byte, len(req.GetPayload())),
+	}, nil
+}
+
+// workStatus - İş hatasını gRPC durumuna çevirir (writeWorkError'ın karşılığı)
+func workStatus(what string, err error) error {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Errorf(codes.DeadlineExceeded, "%s: zaman aşımı", what)
+	case errors.Is(err, context.Canceled):
+		return status.Errorf(codes.Canceled, "%s: iptal edildi", what)
+	default:
+		return status.Errorf(codes.Unavailable, "%s failed: %v", what, err)
+	}
+}
+
+// metricsInterceptor - gRPC çağrılarını HTTPMetrics'e kaydeder
+// Etiket tam metod adıdır (/iovscpu.v1.Workload/CPU); code, gRPC durum kodunun HTTP karşılığı
+func metricsInterceptor(metrics *HTTPMetrics) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		done := metrics.Start(info.FullMethod)
+		resp, err := handler(ctx, req)
+		done("GRPC", grpcHTTPStatus(status.Code(err)))
+		return resp, err
+	}
+}
+
+// grpcHTTPStatus - Metriklerde REST ile aynı kodlar görünsün
+func grpcHTTPStatus(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return 200
+	case codes.InvalidArgument:
+		return 400
+	case codes.DeadlineExceeded:
+		return 504
+	case codes.Canceled:
+		return statusClientClosedRequest
+	case codes.Unavailable:
+		return 502
+	case codes.Unimplemented:
+		return 404
+	default:
+		return 500
+	}
+}
+
+// startGRPC - gRPC sunucusunu addr'de başlatır
+func startGRPC(addr string, defaults *workloadDefaults, metrics *HTTPMetrics) (*grpc.Server, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	server := grpc.NewServer(grpc.UnaryInterceptor(metricsInterceptor(metrics)))
+	workloadpb.RegisterWorkloadServer(server, &workloadServer{defaults: defaults})
+	go server.Serve(listener)
+	return server, nil
+}
+
+// stopGRPC - Süren çağrıları timeout kadar bekler, sonra bağlantıları keser
+func stopGRPC(server *grpc.Server, timeout time.Duration) {
+	done := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(timeout):
+		server.Stop()
+	}
+}
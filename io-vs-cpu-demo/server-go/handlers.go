@@ -0,0 +1,255 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// Parametre sınırları - tek bir istek sunucuyu dakikalarca meşgul etmesin
+const (
+	maxIterations = 10_000_000_000
+	maxDelay      = time.Minute
+	maxPayload    = 16 << 20
+)
+
+// workloadDefaults - Parametre verilmeyen isteklerin iş miktarı
+// Tüm handler'lar aynı değeri paylaşır; PUT /config çalışırken değiştirir (bkz. config.go)
+type workloadDefaults struct {
+	iterations atomic.Int64
+	delay      atomic.Int64 // time.Duration
+}
+
+// newWorkloadDefaults - -iterations / -delay ile başlangıç değerleri
+func newWorkloadDefaults(iterations int64, delay time.Duration) *workloadDefaults {
+	d := &workloadDefaults{}
+	d.Set(iterations, delay)
+	return d
+}
+
+// Iterations - sum görevinin (ve parametresiz /cpu'nun) varsayılan iterasyonu
+func (d *workloadDefaults) Iterations() int64 {
+	return d.iterations.Load()
+}
+
+// Delay - /io ve /job'un varsayılan gecikmesi
+func (d *workloadDefaults) Delay() time.Duration {
+	return time.Duration(d.delay.Load())
+}
+
+// Set - İki değeri birlikte değiştirir
+func (d *workloadDefaults) Set(iterations int64, delay time.Duration) {
+	d.iterations.Store(iterations)
+	d.delay.Store(int64(delay))
+}
+
+// taskDefault - Görevin boyut verilmediğinde kullanılan n'i: sum için -iterations
+func (d *workloadDefaults) taskDefault(task *cpuTask) int64 {
+	if task.Name == defaultCPUTask {
+		return d.Iterations()
+	}
+	return task.Default
+}
+
+// cpuHeavyTask - CPU-bound iş: 0..iterations toplamı
+// Döngü boyunca goroutine CPU'yu bırakmaz; aynı anda çalışabilen istek sayısı çekirdek sayısıyla sınırlıdır.
+// Go, çalışan bir döngüyü dışarıdan durduramaz: İptal, ctx'in her ctxCheckEvery iterasyonda
+// kontrol edilmesiyle fark edilir (bkz. timeout.go)
+func cpuHeavyTask(ctx context.Context, iterations int64) (int64, error) {
+	var sum int64 = 0
+	for i := int64(0); i <= iterations; i++ {
+		if i%ctxCheckEvery == 0 && ctx.Err() != nil {
+			return sum, ctx.Err()
+		}
+		sum += i
+	}
+	return sum, nil
+}
+
+// ioTask - I/O-bound iş simülasyonu: Veritabanı/ağ beklemesi
+// Bekleyen goroutine park edilir; binlerce istek aynı anda beklerken CPU boşta kalır.
+// ctx iptal edilirse bekleme hemen biter
+func ioTask(ctx context.Context, delay time.Duration) error {
+	return sleepCtx(ctx, delay)
+}
+
+// cpuHandler - GET /cpu?task=T&iterations=N[&payload=N]
+// task verilmezse sum; iterations, görevin boyut parametresidir (bkz. cpu_tasks.go)
+func cpuHandler(defaults *workloadDefaults) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		task, iterations, err := cpuTaskParams(r, defaults, "iterations", false)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		payload, err := readPayload(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		start := time.Now()
+		result, err := runCPUTask(r.Context(), task, iterations)
+		if err != nil {
+			writeWorkError(w, fmt.Sprintf("CPU (task=%s)", task), err)
+			return
+		}
+		fmt.Fprintf(w, "CPU result: %d (task=%s, iterations=%d, %v)\n", result, task, iterations, time.Since(start))
+		w.Write(make([]byte, payload))
+	}
+}
+
+// ioHandler - GET /io?delay=D veya /io?task=T&size=N [&payload=N]
+// task verilmezse sleep; diğer görevlerde size, görevin boyut parametresidir (bkz. io_tasks.go)
+// Gerçek I/O hatası (dosya, upstream, Mongo) 502 döner
+func ioHandler(defaults *workloadDefaults) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		task, size, err := ioTaskParams(r, "task", "size")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		delay, err := delayParam(r, "delay", defaults.Delay())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		payload, err := readPayload(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		start := time.Now()
+		result, err := runIOTask(r.Context(), task, size, delay)
+		if err != nil {
+			writeWorkError(w, fmt.Sprintf("IO (task=%s)", task), err)
+			return
+		}
+		if task == defaultIOTask {
+			fmt.Fprintf(w, "IO done (delay=%v, %v)\n", delay, time.Since(start))
+		} else {
+			fmt.Fprintf(w, "IO done (task=%s, size=%d, result=%d, %v)\n", task, size, result, time.Since(start))
+		}
+		w.Write(make([]byte, payload))
+	}
+}
+
+// mixedHandler - GET /mixed?cpu=N&io=D[&task=T][&iotask=T&iosize=N]
+// Parametrelerden biri verilmezse o kısım atlanır (0); task verilip cpu verilmezse görevin varsayılanı.
+// iotask verilirse bekleme yerine o I/O görevi çalışır (bkz. io_tasks.go)
+func mixedHandler(defaults *workloadDefaults) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		task, iterations, err := cpuTaskParams(r, defaults, "cpu", true)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		ioName, ioSize, err := ioTaskParams(r, "iotask", "iosize")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		delay, err := delayParam(r, "io", 0)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		start := time.Now()
+		result, err := runCPUTask(r.Context(), task, iterations)
+		if err != nil {
+			writeWorkError(w, fmt.Sprintf("CPU (task=%s)", task), err)
+			return
+		}
+		cpuTime := time.Since(start)
+		if _, err := runIOTask(r.Context(), ioName, ioSize, delay); err != nil {
+			writeWorkError(w, fmt.Sprintf("IO (iotask=%s)", ioName), err)
+			return
+		}
+		fmt.Fprintf(w, "Mixed result: %d (task=%s, cpu=%d %v, iotask=%s, io=%v, toplam %v)\n",
+			result, task, iterations, cpuTime, ioName, time.Since(start)-cpuTime, time.Since(start))
+	}
+}
+
+// jobHandler - GET /job?cpu=N&io=D[&task=T]: Eski worker-go işi, istek başına bir goroutine (direct mod)
+// Parametre verilmezse varsayılan gecikmeyle I/O
+func jobHandler(defaults *workloadDefaults) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		spec, err := jobParams(r, defaults)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		fmt.Println("Worker job started")
+		_, err = spec.run(r.Context()) // burada cpu / I/O işi yapıyoruz
+		fmt.Println("Worker job finished")
+		if err != nil {
+			writeWorkError(w, "Job", err)
+			return
+		}
+
+		w.Write([]byte("Ok"))
+	}
+}
+
+// jobParams - /job parametreleri: task, cpu, iotask, iosize ve io; CPU ve I/O işi yoksa varsayılan gecikme
+func jobParams(r *http.Request, defaults *workloadDefaults) (jobSpec, error) {
+	task, iterations, err := cpuTaskParams(r, defaults, "cpu", true)
+	if err != nil {
+		return jobSpec{}, err
+	}
+	ioName, ioSize, err := ioTaskParams(r, "iotask", "iosize")
+	if err != nil {
+		return jobSpec{}, err
+	}
+	delay, err := delayParam(r, "io", 0)
+	if err != nil {
+		return jobSpec{}, err
+	}
+	if iterations == 0 && delay == 0 && ioName == defaultIOTask {
+		delay = defaults.Delay()
+	}
+	return jobSpec{Task: task, Iterations: iterations, IOTask: ioName, IOSize: ioSize, Delay: delay}, nil
+}
+
+// iterationsParam - Sorgu parametresini iterasyon sayısı olarak okur (yoksa def)
+func iterationsParam(r *http.Request, name string, def int64) (int64, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return def, nil
+	}
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || n < 0 || n > maxIterations {
+		return 0, fmt.Errorf("%s: 0 ile %d arasında bir sayı olmalı", name, int64(maxIterations))
+	}
+	return n, nil
+}
+
+// readPayload - ?payload=N: İstek gövdesini (POST) okuyup atar, yanıta eklenecek bayt sayısını döner
+// gRPC'deki payload alanının karşılığı (bkz. grpc.go): loadgen-go N baytlık gövde gönderir,
+// yanıt metin satırından sonra N bayt taşır
+func readPayload(r *http.Request) (int64, error) {
+	n, err := iterationsParam(r, "payload", 0)
+	if err != nil || n > maxPayload {
+		return 0, fmt.Errorf("payload: 0 ile %d arasında bir bayt sayısı olmalı", maxPayload)
+	}
+	if _, err := io.Copy(io.Discard, io.LimitReader(r.Body, maxPayload)); err != nil {
+		return 0, fmt.Errorf("istek gövdesi okunamadı: %v", err)
+	}
+	return n, nil
+}
+
+// delayParam - Sorgu parametresini süre olarak okur: "2s", "150ms" (yoksa def)
+func delayParam(r *http.Request, name string, def time.Duration) (time.Duration, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return def, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 || d > maxDelay {
+		return 0, fmt.Errorf("%s: 0 ile %v arasında bir süre olmalı (ör: 2s, 150ms)", name, maxDelay)
+	}
+	return d, nil
+}
@@ -0,0 +1,235 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"os"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// io_tasks.go - Seçilebilir I/O iş yükleri (/io?task=, /mixed ve /job'da ?iotask=)
+// time.Sleep yalnızca beklemeyi taklit eder: Goroutine park edilir ama çekirdek, ağ yığını ya
+// da disk hiç çalışmaz. Gerçek I/O'da bekleme süresine sistem çağrıları, sayfa önbelleği,
+// bağlantı havuzu ve (Mongo'da) BSON çözme maliyeti eklenir:
+//   - sleep: Bekleme simülasyonu (varsayılan, delay / io parametresi)
+//   - file:  n baytlık geçici dosya yazılır, fsync edilir, geri okunur ve silinir (-io-dir)
+//   - http:  -upstream adresine n ardışık GET (yanıt gövdesi sonuna kadar okunur)
+//   - mongo: -mongo-uri'deki perfdb.orders'ta rastgele bir status için find, n doküman okunur
+//   - mongo-point, mongo-scan, mongo-agg: Aynı koleksiyonda parametreli sorgular (bkz. mongo_queries.go)
+//
+// file'ın okuma kısmı büyük ihtimalle sayfa önbelleğinden gelir; fsync diske yazmayı zorlar.
+
+// ioWorkload - Kayıtlı bir I/O iş yükü
+type ioWorkload struct {
+	Name    string `json:"name"`
+	Param   string `json:"param"`   // n'nin anlamı
+	Default int64  `json:"default"` // Varsayılan n
+	Max     int64  `json:"max"`     // Tek istekte izin verilen en büyük n
+	run     func(ctx context.Context, n int64) (int64, error)
+}
+
+// defaultIOTask - Görev seçilmediğinde kullanılan I/O işi
+const defaultIOTask = "sleep"
+
+// ioTasks - Görev kaydı; http ve mongo* görevleri configureIOTasks ile bağlanır
+var ioTasks = map[string]*ioWorkload{
+	"sleep": {Name: "sleep", Param: "kullanılmaz (delay / io süresi)"},
+	"file":  {Name: "file", Param: "dosya boyutu (bayt)", Default: 1 << 20, Max: 1 << 30},
+	"http":  {Name: "http", Param: "ardışık istek sayısı", Default: 1, Max: 100, run: notConfigured("-upstream")},
+	"mongo": {Name: "mongo", Param: "okunacak doküman sayısı", Default: 100, Max: 100_000, run: notConfigured("-mongo-uri")},
+
+	"mongo-point": {Name: "mongo-point", Param: "ardışık _id okuması", Default: 1, Max: 1000, run: notConfigured("-mongo-uri")},
+	"mongo-scan":  {Name: "mongo-scan", Param: "en fazla okunacak doküman", Default: 100, Max: 100_000, run: notConfigured("-mongo-uri")},
+	"mongo-agg":   {Name: "mongo-agg", Param: "gruplanan pencere (saat)", Default: 24, Max: 1000, run: notConfigured("-mongo-uri")},
+}
+
+// ioConfig - Gerçek I/O görevlerinin hedefleri
+type ioConfig struct {
+	Dir      string // file görevinin geçici dosya dizini
+	Upstream string // http görevinin adresi (boşsa http kapalı)
+	MongoURI string // mongo görevinin sunucusu (boşsa mongo kapalı)
+	MongoDB  string // perfdb
+}
+
+func notConfigured(flagName string) func(context.Context, int64) (int64, error) {
+	return func(context.Context, int64) (int64, error) {
+		return 0, fmt.Errorf("görev yapılandırılmamış: sunucuyu %s ile başlatın", flagName)
+	}
+}
+
+// configureIOTasks - Görevleri hedeflerine bağlar; Mongo istemcisini döner (Close için, yoksa nil)
+// mongo.Connect bağlantı açmaz: Sunucu erişilemezse hata ilk mongo isteğinde döner
+func configureIOTasks(cfg ioConfig) (*mongo.Client, error) {
+	ioTasks["file"].run = func(ctx context.Context, n int64) (int64, error) {
+		return fileTask(ctx, cfg.Dir, n)
+	}
+	if cfg.Upstream != "" {
+		// Ayrı istemci: Her ardışık çağrı bağlantıyı yeniden kullanır (keep-alive)
+		client := &http.Client{Timeout: 30 * time.Second}
+		ioTasks["http"].run = func(ctx context.Context, n int64) (int64, error) {
+			return httpTask(ctx, client, cfg.Upstream, n)
+		}
+	}
+	if cfg.MongoURI == "" {
+		return nil, nil
+	}
+	client, err := mongo.Connect(context.Background(), options.Client().ApplyURI(cfg.MongoURI))
+	if err != nil {
+		return nil, err
+	}
+	configureMongoQueries(client.Database(cfg.MongoDB).Collection("orders"))
+	return client, nil
+}
+
+// lookupIOTask - Görevi adıyla bulur
+func lookupIOTask(name string) (*ioWorkload, error) {
+	if name == "" {
+		name = defaultIOTask
+	}
+	task, ok := ioTasks[name]
+	if !ok {
+		names := make([]string, 0, len(ioTasks))
+		for name := range ioTasks {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("bilinmeyen I/O görevi %q (%v)", name, names)
+	}
+	return task, nil
+}
+
+// ioTaskParams - I/O görevi ve boyutu (boyut verilmezse görevin varsayılanı)
+// sleep'in süresi ayrıca delayParam ile okunur
+func ioTaskParams(r *http.Request, taskName, sizeName string) (string, int64, error) {
+	task, err := lookupIOTask(r.URL.Query().Get(taskName))
+	if err != nil {
+		return "", 0, fmt.Errorf("%s: %v", taskName, err)
+	}
+	if task.Name == defaultIOTask {
+		return task.Name, 0, nil
+	}
+	n, err := iterationsParam(r, sizeName, task.Default)
+	if err != nil {
+		return "", 0, err
+	}
+	if n > task.Max {
+		return "", 0, fmt.Errorf("%s: %s görevi için en fazla %d", sizeName, task.Name, task.Max)
+	}
+	return task.Name, n, nil
+}
+
+// runIOTask - I/O işini çalıştırır; sleep için delay kadar bekler
+// Dönen değer göreve göre okunan bayt veya doküman sayısıdır
+func runIOTask(ctx context.Context, name string, n int64, delay time.Duration) (int64, error) {
+	if name == "" || name == defaultIOTask {
+		return 0, ioTask(ctx, delay)
+	}
+	task, err := lookupIOTask(name)
+	if err != nil {
+		return 0, err
+	}
+	return task.run(ctx, n)
+}
+
+// fileTask - n baytı geçici dosyaya yazar, fsync eder, geri okur ve siler
+// ctx her 64 KiB'da kontrol edilir; iptalde dosya yine silinir
+func fileTask(ctx context.Context, dir string, n int64) (int64, error) {
+	f, err := os.CreateTemp(dir, "iovscpu-*.bin")
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	chunk := make([]byte, 64<<10)
+	for i := range chunk {
+		chunk[i] = byte(i)
+	}
+	for written := int64(0); written < n; {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+		m := min(int64(len(chunk)), n-written)
+		if _, err := f.Write(chunk[:m]); err != nil {
+			return 0, err
+		}
+		written += m
+	}
+	if err := f.Sync(); err != nil {
+		return 0, err
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return 0, err
+	}
+	var read int64
+	for {
+		if err := ctx.Err(); err != nil {
+			return read, err
+		}
+		m, err := f.Read(chunk)
+		read += int64(m)
+		if err == io.EOF {
+			return read, nil
+		}
+		if err != nil {
+			return read, err
+		}
+	}
+}
+
+// httpTask - upstream'e n ardışık GET; sonuç toplam gövde boyutu
+func httpTask(ctx context.Context, client *http.Client, upstream string, n int64) (int64, error) {
+	var total int64
+	for i := int64(0); i < n; i++ {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, upstream, nil)
+		if err != nil {
+			return total, err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return total, err
+		}
+		read, err := io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		total += read
+		if err != nil {
+			return total, err
+		}
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			return total, fmt.Errorf("upstream: http %d", resp.StatusCode)
+		}
+	}
+	return total, nil
+}
+
+// orderStatuses - perfdb.orders'taki status değerleri (mongo-perf-lab generator ile aynı)
+var orderStatuses = []string{"PAID", "CANCELLED", "PENDING"}
+
+// mongoTask - Rastgele bir status'taki ilk n siparişi okur; sonuç okunan doküman sayısı
+// status indeksi varsa (mongo-perf-lab create_index) sorgu IXSCAN ile çalışır
+func mongoTask(ctx context.Context, orders *mongo.Collection, n int64) (int64, error) {
+	status := orderStatuses[rand.Intn(len(orderStatuses))]
+	cursor, err := orders.Find(ctx, bson.D{{Key: "status", Value: status}},
+		options.Find().SetLimit(n).SetBatchSize(int32(min(n, 1000))))
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+	var count int64
+	for cursor.Next(ctx) {
+		var doc bson.Raw
+		if err := cursor.Decode(&doc); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, cursor.Err()
+}
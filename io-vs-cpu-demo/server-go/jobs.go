@@ -0,0 +1,275 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// jobs.go - Asenkron iş API'si
+// POST /job işi kabul eder ve beklemeden iş ID'si döner (202 Accepted); istemci sonucu
+// GET /job/{id} ile sorgular. HTTP isteği işin süresine bağlı kalmaz: Uzun işler gateway/istemci
+// zaman aşımlarına takılmaz ve bağlantı hemen serbest kalır (async offloading).
+//
+// İşler bellekteki JobStore'da tutulur; biten işler TTL sonunda silinir. Direct ve pool
+// modlarında sunucu yeniden başlarsa işler kaybolur. Queue modunda iş kalıcı arka uçtadır
+// (bkz. queue.go), JobStore yalnızca bu process'in gördüğü durumları tutar.
+
+// JobStatus - İşin durumu
+type JobStatus string
+
+const (
+	JobQueued  JobStatus = "queued"
+	JobRunning JobStatus = "running"
+	JobDone    JobStatus = "done"
+	JobFailed  JobStatus = "failed" // I/O görevi hata verdi ya da queue modunda deneme sınırı aşıldı
+)
+
+// JobRecord - Bir asenkron işin durumu ve sonucu (GET /job/{id} yanıtı)
+type JobRecord struct {
+	ID         string     `json:"id"`
+	Status     JobStatus  `json:"status"`
+	Task       string     `json:"task"`
+	Iterations int64      `json:"iterations"`
+	IOTask     string     `json:"ioTask"`
+	IOSize     int64      `json:"ioSize,omitempty"`
+	DelayMs    float64    `json:"delayMs"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	StartedAt  *time.Time `json:"startedAt,omitempty"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+	Result     *int64     `json:"result,omitempty"`
+}
+
+// JobStore - Bellekteki iş deposu
+type JobStore struct {
+	ttl  time.Duration
+	mu   sync.Mutex
+	jobs map[string]*JobRecord
+}
+
+// NewJobStore - Depoyu oluşturur ve TTL temizliğini başlatır
+// ttl: Biten bir işin sonucu ne kadar süre sorgulanabilir
+func NewJobStore(ttl time.Duration) *JobStore {
+	s := &JobStore{ttl: ttl, jobs: map[string]*JobRecord{}}
+	go s.cleanup(max(time.Second, ttl/2))
+	return s
+}
+
+// Create - Yeni iş kaydı (queued)
+func (s *JobStore) Create(spec jobSpec) JobRecord {
+	job := &JobRecord{
+		ID:         newJobID(),
+		Status:     JobQueued,
+		Task:       spec.Task,
+		Iterations: spec.Iterations,
+		IOTask:     spec.IOTask,
+		IOSize:     spec.IOSize,
+		DelayMs:    float64(spec.Delay) / float64(time.Millisecond),
+		CreatedAt:  time.Now(),
+	}
+	s.mu.Lock()
+	s.jobs[job.ID] = job
+	s.mu.Unlock()
+	return *job
+}
+
+// Get - İşin anlık kopyası (yoksa veya süresi dolduysa false)
+func (s *JobStore) Get(id string) (JobRecord, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job, ok := s.jobs[id]
+	if !ok {
+		return JobRecord{}, false
+	}
+	return *job, true
+}
+
+// Start - İşi running olarak işaretler
+func (s *JobStore) Start(id string) {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if job, ok := s.jobs[id]; ok {
+		job.Status, job.StartedAt = JobRunning, &now
+	}
+}
+
+// Finish - İşi sonucuyla done olarak işaretler
+func (s *JobStore) Finish(id string, result int64) {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if job, ok := s.jobs[id]; ok {
+		job.Status, job.FinishedAt, job.Result = JobDone, &now, &result
+	}
+}
+
+// Ensure - Kuyruktan alınan işin kaydı yoksa oluşturur
+// İş başka bir process'te (ör: çöken sunucu) kuyruğa yazılmış olabilir
+func (s *JobStore) Ensure(job QueuedJob) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.jobs[job.ID]; ok {
+		return
+	}
+	s.jobs[job.ID] = &JobRecord{
+		ID:         job.ID,
+		Status:     JobQueued,
+		Task:       job.Spec.Task,
+		Iterations: job.Spec.Iterations,
+		IOTask:     job.Spec.IOTask,
+		IOSize:     job.Spec.IOSize,
+		DelayMs:    float64(job.Spec.Delay) / float64(time.Millisecond),
+		CreatedAt:  job.EnqueuedAt,
+	}
+}
+
+// Fail - İşi failed olarak işaretler (TTL temizliği için bitmiş sayılır)
+func (s *JobStore) Fail(id string) {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if job, ok := s.jobs[id]; ok {
+		job.Status, job.FinishedAt = JobFailed, &now
+	}
+}
+
+// Delete - Kaydı siler (ör: pool işi kabul etmediyse)
+func (s *JobStore) Delete(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.jobs, id)
+}
+
+// Unfinished - Verilen durumlardaki işlerin ID'leri (kapanışta boşaltılacak işler)
+func (s *JobStore) Unfinished(statuses ...JobStatus) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var ids []string
+	for id, job := range s.jobs {
+		for _, status := range statuses {
+			if job.Status == status {
+				ids = append(ids, id)
+				break
+			}
+		}
+	}
+	return ids
+}
+
+// CountUnfinished - ids içinde henüz bitmemiş (queued/running) iş sayısı
+// TTL ile silinen işler bitmiş sayılır
+func (s *JobStore) CountUnfinished(ids []string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := 0
+	for _, id := range ids {
+		if job, ok := s.jobs[id]; ok && (job.Status == JobQueued || job.Status == JobRunning) {
+			n++
+		}
+	}
+	return n
+}
+
+// Counts - Durum başına iş sayısı (/job/stats için)
+func (s *JobStore) Counts() map[JobStatus]int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	counts := map[JobStatus]int{}
+	for _, job := range s.jobs {
+		counts[job.Status]++
+	}
+	return counts
+}
+
+// cleanup - TTL'i dolan biten işleri periyodik olarak siler
+// Bekleyen/çalışan işler silinmez: Sonucu henüz üretilmedi
+func (s *JobStore) cleanup(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		cutoff := time.Now().Add(-s.ttl)
+		s.mu.Lock()
+		for id, job := range s.jobs {
+			if job.FinishedAt != nil && job.FinishedAt.Before(cutoff) {
+				delete(s.jobs, id)
+			}
+		}
+		s.mu.Unlock()
+	}
+}
+
+// newJobID - Rastgele 16 karakterlik ID
+func newJobID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// submitJobHandler - POST /job?cpu=N&io=D
+// Direct modda iş kendi goroutine'inde, pool modunda kuyrukta çalışır (kuyruk doluysa -overload'a göre)
+func submitJobHandler(defaults *workloadDefaults, store *JobStore, pool *JobPool, rejectStatus int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		spec, err := jobParams(r, defaults)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		job := store.Create(spec)
+
+		if pool != nil {
+			queued, err := pool.Submit(r.Context(), context.Background(), spec, func() { store.Start(job.ID) })
+			if err != nil {
+				store.Delete(job.ID)
+				if errors.Is(err, errQueueFull) {
+					rejectJob(w, pool, rejectStatus)
+				} else {
+					writeWorkError(w, "Kuyruk bekleme", err)
+				}
+				return
+			}
+			go func() {
+				result := <-queued.done
+				if result.Err != nil {
+					store.Fail(job.ID)
+					return
+				}
+				store.Finish(job.ID, result.Result)
+			}()
+		} else {
+			go func() {
+				store.Start(job.ID)
+				// İsteğin context'i yanıtla birlikte biter; iş ondan bağımsız çalışır
+				result, err := spec.run(context.Background())
+				if err != nil {
+					store.Fail(job.ID)
+					return
+				}
+				store.Finish(job.ID, result)
+			}()
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Location", "/job/"+job.ID)
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(job)
+	}
+}
+
+// jobStatusHandler - GET /job/{id}
+func jobStatusHandler(store *JobStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		job, ok := store.Get(r.PathValue("id"))
+		if !ok {
+			http.Error(w, "iş bulunamadı (hiç olmadı veya TTL doldu)", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(job)
+	}
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// latency.go - Yapay gecikme enjeksiyonu (-inject veya ?inject=)
+// Handler'dan önce dağılımdan çekilen süre kadar beklenir. Kuyruk gecikmesinin (tail latency)
+// istemci yüzdeliklerine etkisini dış araç olmadan incelemek için:
+//
+//	fixed:50ms              Her istek 50ms
+//	uniform:10ms-100ms      10ms ile 100ms arası eşit olasılıkla
+//	normal:50ms,10ms        Ortalama 50ms, standart sapma 10ms (negatifler 0'a kırpılır)
+//	pareto:5ms,1.5          Ölçek 5ms, şekil 1.5: Çoğu istek hızlı, az sayıda istek çok yavaş
+//
+// Pareto'da şekil parametresi küçüldükçe kuyruk kalınlaşır (α ≤ 2'de varyans sonsuzdur):
+// p50 neredeyse değişmezken p99 katlanarak büyür. Çekilen süreler maxDelay ile sınırlanır.
+// Bekleme ctx'e bağlıdır: Zaman aşımı enjekte edilen gecikmeyi de keser (504).
+
+// LatencyDist - Gecikme dağılımı
+type LatencyDist struct {
+	Kind  string        // fixed, uniform, normal, pareto
+	A, B  time.Duration // fixed: A; uniform: [A, B]; normal: ortalama A, sapma B; pareto: ölçek A
+	Shape float64       // pareto: α
+}
+
+// ParseLatencyDist - "tür:parametreler" biçimini çözer; boş metin = enjeksiyon yok (nil)
+func ParseLatencyDist(spec string) (*LatencyDist, error) {
+	if spec == "" || spec == "none" {
+		return nil, nil
+	}
+	kind, params, _ := strings.Cut(spec, ":")
+	d := &LatencyDist{Kind: kind}
+	var err error
+	switch kind {
+	case "fixed":
+		d.A, err = time.ParseDuration(params)
+	case "uniform":
+		lo, hi, ok := strings.Cut(params, "-")
+		if !ok {
+			return nil, fmt.Errorf("uniform:min-max bekleniyor (ör: uniform:10ms-100ms)")
+		}
+		if d.A, err = time.ParseDuration(lo); err == nil {
+			d.B, err = time.ParseDuration(hi)
+		}
+		if err == nil && d.B < d.A {
+			err = fmt.Errorf("uniform: max, min'den küçük")
+		}
+	case "normal":
+		mean, stddev, ok := strings.Cut(params, ",")
+		if !ok {
+			return nil, fmt.Errorf("normal:ortalama,sapma bekleniyor (ör: normal:50ms,10ms)")
+		}
+		if d.A, err = time.ParseDuration(mean); err == nil {
+			d.B, err = time.ParseDuration(stddev)
+		}
+	case "pareto":
+		scale, shape, ok := strings.Cut(params, ",")
+		if !ok {
+			return nil, fmt.Errorf("pareto:ölçek,şekil bekleniyor (ör: pareto:5ms,1.5)")
+		}
+		if d.A, err = time.ParseDuration(scale); err == nil {
+			d.Shape, err = strconv.ParseFloat(shape, 64)
+		}
+		if err == nil && d.Shape <= 0 {
+			err = fmt.Errorf("pareto: şekil pozitif olmalı")
+		}
+	default:
+		return nil, fmt.Errorf("bilinmeyen dağılım %q (fixed, uniform, normal, pareto)", kind)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", spec, err)
+	}
+	if d.A < 0 || d.B < 0 {
+		return nil, fmt.Errorf("%s: süreler negatif olamaz", spec)
+	}
+	return d, nil
+}
+
+// Sample - Dağılımdan bir süre çeker (0 ile maxDelay arasında)
+func (d *LatencyDist) Sample() time.Duration {
+	var v float64
+	switch d.Kind {
+	case "fixed":
+		v = float64(d.A)
+	case "uniform":
+		v = float64(d.A) + rand.Float64()*float64(d.B-d.A)
+	case "normal":
+		v = float64(d.A) + rand.NormFloat64()*float64(d.B)
+	case "pareto":
+		// Ters dönüşüm: xm / U^(1/α), U ∈ (0, 1]
+		v = float64(d.A) / math.Pow(1-rand.Float64(), 1/d.Shape)
+	}
+	return time.Duration(min(max(v, 0), float64(maxDelay)))
+}
+
+// String - Spesifikasyon biçiminde
+func (d *LatencyDist) String() string {
+	switch d.Kind {
+	case "uniform":
+		return fmt.Sprintf("uniform:%v-%v", d.A, d.B)
+	case "normal":
+		return fmt.Sprintf("normal:%v,%v", d.A, d.B)
+	case "pareto":
+		return fmt.Sprintf("pareto:%v,%g", d.A, d.Shape)
+	}
+	return fmt.Sprintf("fixed:%v", d.A)
+}
+
+// withLatency - Handler'dan önce gecikme enjekte eder
+// ?inject= istek başına dağılımı değiştirir ("none" kapatır). Enjekte edilen süre
+// X-Injected-Delay başlığında döner: İstemci, gecikmenin ne kadarının yapay olduğunu görür
+func withLatency(def *LatencyDist, h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		dist := def
+		if spec := r.URL.Query().Get("inject"); spec != "" {
+			var err error
+			if dist, err = ParseLatencyDist(spec); err != nil {
+				http.Error(w, "inject: "+err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+		if dist != nil {
+			delay := dist.Sample()
+			w.Header().Set("X-Injected-Delay", delay.String())
+			if err := sleepCtx(r.Context(), delay); err != nil {
+				writeWorkError(w, "Gecikme enjeksiyonu", err)
+				return
+			}
+		}
+		h(w, r)
+	}
+}
+
+// sleepCtx - delay kadar bekler; ctx biterse hemen döner
+func sleepCtx(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,352 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"benchkit"
+)
+
+// limiter.go - Endpoint başına eşzamanlılık sınırı (semafor) ve sıra metrikleri
+// Sınır doluyken gelen istek bir slot boşalana kadar FIFO sırada bekler; bekleme süresi
+// kaydedilir. Zaman aşımı (-timeout) beklemeyi de kapsar: Sırada süresi dolan istek 504 alır.
+//
+// Little Yasası: Sistemdeki ortalama iş sayısı L = λ × W (λ: geliş hızı, W: ortalama süre).
+// Sıra için: Ortalama bekleyen = kabul hızı × ortalama bekleme. GET /limits her endpoint için
+// λ×W tahminini, zamana göre ağırlıklı gerçek ortalama sıra uzunluğuyla yan yana gösterir;
+// loadgen eşzamanlılığı artırıldıkça sınırın üstündeki istekler sırada birikir ve bekleme
+// eşzamanlılıkla doğrusal büyür.
+//
+// Sınır çalışma sırasında değişir (sunucu yeniden başlamaz):
+//
+//	GET  /limits                         Sınırlar ve sıra metrikleri (JSON)
+//	POST /limits?endpoint=/cpu&limit=4   Sınırı değiştirir (0 = sınırsız); metrikler sıfırlanır
+
+// limitedEndpoints - Sınır konabilen (semafordan geçen) endpoint'ler
+var limitedEndpoints = []string{"/cpu", "/io", "/mixed", "/job"}
+
+// Semaphore - Boyutu değiştirilebilen FIFO semafor
+type Semaphore struct {
+	mu      sync.Mutex
+	limit   int // 0 = sınırsız
+	active  int
+	waiters []chan struct{}
+
+	// Metrikler (son sıfırlamadan beri)
+	since      time.Time
+	admitted   int64
+	abandoned  int64 // Slot beklerken ctx'i biten istekler
+	wait       *benchkit.Histogram
+	waitBucket []int64 // latencyBuckets'a karşılık gelen sayılar (Prometheus için)
+	waitSum    float64
+	queueArea  float64   // ∫ bekleyen sayısı dt (saniye): Zamana göre ağırlıklı ortalama için
+	lastChange time.Time // queueArea'nın en son güncellendiği an
+}
+
+// NewSemaphore - limit eşzamanlı slotlu semafor (0 = sınırsız)
+func NewSemaphore(limit int) *Semaphore {
+	now := time.Now()
+	return &Semaphore{limit: limit, since: now, lastChange: now, wait: benchkit.NewHistogram(),
+		waitBucket: make([]int64, len(latencyBuckets))}
+}
+
+// accumulate - Bekleyen sayısının zaman integralini günceller (mu tutulurken)
+func (s *Semaphore) accumulate(now time.Time) {
+	s.queueArea += float64(len(s.waiters)) * now.Sub(s.lastChange).Seconds()
+	s.lastChange = now
+}
+
+func (s *Semaphore) free() bool {
+	return s.limit == 0 || s.active < s.limit
+}
+
+// Acquire - Slot alır; slot yoksa sırada bekler. ctx biterse sıradan çıkıp hatasını döner
+func (s *Semaphore) Acquire(ctx context.Context) error {
+	start := time.Now()
+	s.mu.Lock()
+	if len(s.waiters) == 0 && s.free() {
+		s.active++
+		s.record(0)
+		s.mu.Unlock()
+		return nil
+	}
+	ch := make(chan struct{})
+	s.accumulate(start)
+	s.waiters = append(s.waiters, ch)
+	s.mu.Unlock()
+
+	select {
+	case <-ch:
+		// Slot Release/SetLimit tarafından devredildi (active zaten artırıldı)
+		s.mu.Lock()
+		s.record(time.Since(start))
+		s.mu.Unlock()
+		return nil
+	case <-ctx.Done():
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		for i, w := range s.waiters {
+			if w == ch {
+				s.accumulate(time.Now())
+				s.waiters = append(s.waiters[:i], s.waiters[i+1:]...)
+				s.abandoned++
+				return ctx.Err()
+			}
+		}
+		// Aynı anda slot devredildi: Slot alınmış sayılır, geri bırakılır
+		s.active--
+		s.handOff()
+		s.abandoned++
+		return ctx.Err()
+	}
+}
+
+// record - Kabul edilen isteğin bekleme süresini kaydeder (mu tutulurken)
+func (s *Semaphore) record(wait time.Duration) {
+	s.admitted++
+	s.wait.Record(wait)
+	seconds := wait.Seconds()
+	s.waitSum += seconds
+	if i := sort.SearchFloat64s(latencyBuckets, seconds); i < len(latencyBuckets) {
+		s.waitBucket[i]++
+	}
+}
+
+// Release - Slotu bırakır; sırada bekleyen varsa slot ona devredilir
+func (s *Semaphore) Release() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.active--
+	s.handOff()
+}
+
+// handOff - Boş slot oldukça sıradakileri uyandırır (mu tutulurken)
+func (s *Semaphore) handOff() {
+	if len(s.waiters) > 0 && s.free() {
+		s.accumulate(time.Now())
+	}
+	for len(s.waiters) > 0 && s.free() {
+		s.active++
+		close(s.waiters[0])
+		s.waiters = s.waiters[1:]
+	}
+}
+
+// SetLimit - Sınırı değiştirir ve metrikleri sıfırlar (yeni sınırın ölçümü temiz başlasın)
+// Sınır küçülürse fazla çalışan istekler kesilmez; bitene kadar yeni istek alınmaz
+func (s *Semaphore) SetLimit(limit int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.limit = limit
+	now := time.Now()
+	s.since, s.lastChange = now, now
+	s.admitted, s.abandoned, s.waitSum, s.queueArea = 0, 0, 0, 0
+	s.wait = benchkit.NewHistogram()
+	s.waitBucket = make([]int64, len(latencyBuckets))
+	s.handOff()
+}
+
+// LimitStats - GET /limits içinde bir endpoint
+type LimitStats struct {
+	Limit     int                 `json:"limit"` // 0 = sınırsız
+	Active    int                 `json:"active"`
+	Waiting   int                 `json:"waiting"`
+	Admitted  int64               `json:"admitted"`
+	Abandoned int64               `json:"abandoned"`
+	WaitMs    *benchkit.LatencyMs `json:"waitMs"`
+	// Little Yasası: Tahmin (kabul hızı × ortalama bekleme) ve ölçülen ortalama sıra uzunluğu
+	ArrivalRate       float64 `json:"arrivalRate"` // Kabul/saniye
+	PredictedQueueLen float64 `json:"predictedQueueLen"`
+	ObservedQueueLen  float64 `json:"observedQueueLen"`
+	WindowSec         float64 `json:"windowSec"`
+}
+
+// Stats - Son sıfırlamadan beri metrikler
+func (s *Semaphore) Stats() LimitStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	s.accumulate(now)
+	window := now.Sub(s.since).Seconds()
+	stats := LimitStats{
+		Limit:     s.limit,
+		Active:    s.active,
+		Waiting:   len(s.waiters),
+		Admitted:  s.admitted,
+		Abandoned: s.abandoned,
+		WaitMs:    s.wait.Summary().Millis(),
+		WindowSec: window,
+	}
+	if window > 0 {
+		stats.ArrivalRate = float64(s.admitted) / window
+		stats.ObservedQueueLen = s.queueArea / window
+	}
+	if s.admitted > 0 {
+		stats.PredictedQueueLen = stats.ArrivalRate * s.waitSum / float64(s.admitted)
+	}
+	return stats
+}
+
+// Limiter - Endpoint adı → semafor
+type Limiter struct {
+	mu   sync.Mutex
+	sems map[string]*Semaphore
+}
+
+// NewLimiter - Başlangıç sınırları ("/cpu=4,/io=100" biçiminden, bkz. ParseLimits)
+func NewLimiter(limits map[string]int) *Limiter {
+	l := &Limiter{sems: map[string]*Semaphore{}}
+	for name, limit := range limits {
+		l.sems[name] = NewSemaphore(limit)
+	}
+	return l
+}
+
+// ParseLimits - "/cpu=4,/io=100" → endpoint → sınır
+func ParseLimits(s string) (map[string]int, error) {
+	limits := map[string]int{}
+	if s == "" {
+		return limits, nil
+	}
+	for _, part := range strings.Split(s, ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		n, err := strconv.Atoi(value)
+		if !ok || err != nil || n < 0 {
+			return nil, fmt.Errorf("geçersiz sınır %q (ör: /cpu=4)", part)
+		}
+		limits[name] = n
+	}
+	return limits, nil
+}
+
+func (l *Limiter) semaphore(name string) *Semaphore {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	s, ok := l.sems[name]
+	if !ok {
+		s = NewSemaphore(0)
+		l.sems[name] = s
+	}
+	return s
+}
+
+// Wrap - Handler'ı endpoint'in semaforuyla sarar
+// Sınırsız endpoint'ler de semafordan geçer: Sınır sonradan konabilir ve aktif sayısı doğru kalır
+func (l *Limiter) Wrap(name string, h http.HandlerFunc) http.HandlerFunc {
+	sem := l.semaphore(name)
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := sem.Acquire(r.Context()); err != nil {
+			writeWorkError(w, "Sıra bekleme ("+name+")", err)
+			return
+		}
+		defer sem.Release()
+		h(w, r)
+	}
+}
+
+// SetLimit - Endpoint'in sınırını değiştirir (0 = sınırsız); bilinmeyen endpoint hata döner
+func (l *Limiter) SetLimit(name string, limit int) error {
+	l.mu.Lock()
+	sem, ok := l.sems[name]
+	l.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("endpoint: bilinmeyen %q (%v)", name, l.names())
+	}
+	sem.SetLimit(limit)
+	return nil
+}
+
+// Limits - Endpoint → güncel sınır
+func (l *Limiter) Limits() map[string]int {
+	limits := map[string]int{}
+	for _, name := range l.names() {
+		s := l.semaphore(name)
+		s.mu.Lock()
+		limits[name] = s.limit
+		s.mu.Unlock()
+	}
+	return limits
+}
+
+// names - Sıralı endpoint adları
+func (l *Limiter) names() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	names := make([]string, 0, len(l.sems))
+	for name := range l.sems {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ServeHTTP - GET /limits (JSON) ve POST /limits?endpoint=E&limit=N
+func (l *Limiter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodPost {
+		name := r.URL.Query().Get("endpoint")
+		limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+		if err != nil || limit < 0 {
+			http.Error(w, "limit: negatif olmayan bir sayı olmalı (0 = sınırsız)", http.StatusBadRequest)
+			return
+		}
+		if err := l.SetLimit(name, limit); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	stats := map[string]LimitStats{}
+	for _, name := range l.names() {
+		stats[name] = l.semaphore(name).Stats()
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(stats)
+}
+
+// WritePrometheus - /metrics'e eklenen sınır ve sıra metrikleri
+func (l *Limiter) WritePrometheus(b *strings.Builder) {
+	names := l.names()
+	type snapshot struct {
+		limit, active, waiting int
+		buckets                []int64
+		count                  int64
+		sum                    float64
+	}
+	snaps := make([]snapshot, len(names))
+	for i, name := range names {
+		s := l.semaphore(name)
+		s.mu.Lock()
+		snaps[i] = snapshot{s.limit, s.active, len(s.waiters), append([]int64(nil), s.waitBucket...), s.admitted, s.waitSum}
+		s.mu.Unlock()
+	}
+
+	b.WriteString("# HELP concurrency_limit Eşzamanlılık sınırı (0 = sınırsız)\n# TYPE concurrency_limit gauge\n")
+	for i, name := range names {
+		fmt.Fprintf(b, "concurrency_limit{endpoint=%q} %d\n", name, snaps[i].limit)
+	}
+	b.WriteString("# HELP concurrency_active Slot tutan istekler\n# TYPE concurrency_active gauge\n")
+	for i, name := range names {
+		fmt.Fprintf(b, "concurrency_active{endpoint=%q} %d\n", name, snaps[i].active)
+	}
+	b.WriteString("# HELP concurrency_waiting Slot bekleyen istekler\n# TYPE concurrency_waiting gauge\n")
+	for i, name := range names {
+		fmt.Fprintf(b, "concurrency_waiting{endpoint=%q} %d\n", name, snaps[i].waiting)
+	}
+	b.WriteString("# HELP concurrency_wait_seconds Slot bekleme süresi (son sınır değişikliğinden beri)\n# TYPE concurrency_wait_seconds histogram\n")
+	for i, name := range names {
+		var cumulative int64
+		for j, le := range latencyBuckets {
+			cumulative += snaps[i].buckets[j]
+			fmt.Fprintf(b, "concurrency_wait_seconds_bucket{endpoint=%q,le=\"%g\"} %d\n", name, le, cumulative)
+		}
+		fmt.Fprintf(b, "concurrency_wait_seconds_bucket{endpoint=%q,le=\"+Inf\"} %d\n", name, snaps[i].count)
+		fmt.Fprintf(b, "concurrency_wait_seconds_sum{endpoint=%q} %g\n", name, snaps[i].sum)
+		fmt.Fprintf(b, "concurrency_wait_seconds_count{endpoint=%q} %d\n", name, snaps[i].count)
+	}
+}
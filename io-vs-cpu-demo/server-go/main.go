@@ -0,0 +1,356 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/pprof"
+	"os"
+	"os/signal"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/felixge/fgprof"
+	"google.golang.org/grpc"
+
+	"benchkit"
+)
+
+// server-go - CPU-bound ve I/O-bound işleri karşılaştıran demo sunucusu
+// Eski service-go (/cpu) ve worker-go (/job) tek sunucuda birleşti: İş miktarı sabitleri
+// değiştirip yeniden derlemek yerine istek parametresiyle ayarlanır.
+//
+// Endpoint'ler:
+//
+//	GET /cpu?iterations=50000000       CPU-bound: Toplama döngüsü, goroutine CPU'yu meşgul eder
+//	GET /cpu?task=matrix&iterations=N  Diğer CPU görevleri: sieve, matrix, sha256, json, regexp
+//	GET /cpu/tasks[?calibrate=100ms]   Görevler, varsayılan boyutları ve bu makinedeki kalibrasyon
+//	GET /io?delay=2s                   I/O-bound: Bekleme, goroutine park edilir ve CPU boşta kalır
+//	GET /io?task=file&size=1048576     Gerçek I/O: file, http (-upstream), mongo (-mongo-uri), bkz. io_tasks.go
+//	GET /job?iotask=mongo-point        Veritabanı işi: orders'ta nokta okuma, mongo-scan (filtreli tarama)
+//	                                   veya mongo-agg (aggregate); sorgu başına süre /metrics'te (bkz. mongo_queries.go)
+//	GET /mixed?cpu=10000000&io=500ms   Önce CPU işi, sonra bekleme (tipik bir API isteği)
+//	                                   (iotask=T&iosize=N ile bekleme yerine gerçek I/O; /job'da da)
+//	GET /job?cpu=N&io=D                Eski worker işi, senkron (parametresiz: varsayılan gecikmeyle I/O)
+//	POST /job?cpu=N&io=D               Asenkron iş: Hemen iş ID'si döner (202), bkz. jobs.go
+//	GET /job/{id}                      Asenkron işin durumu/sonucu
+//	GET /job/stats                     İş sayıları, pool/queue modunda kuyruk metrikleri (JSON)
+//	GET/POST /limits                   Eşzamanlılık sınırları ve slot bekleme metrikleri; POST
+//	                                   ?endpoint=/cpu&limit=4 ile çalışırken değiştirilir (bkz. limiter.go)
+//	GET/PUT /config                    Varsayılan iterations/delay, sınırlar ve pool worker sayısı;
+//	                                   PUT ile yeniden başlatmadan değişir (bkz. config.go)
+//	gRPC Workload/CPU, Workload/IO      /cpu ve /io'nun gRPC karşılığı (-grpc-addr, bkz. grpc.go);
+//	                                   ?payload=N ile REST'te de istek/yanıt N bayt taşır
+//	GET /ws[?delay=10ms]               WebSocket echo; loadgen-go ws:// ile mesaj gidiş-dönüşü ölçer
+//	GET /metrics                       İstek sayıları, eşzamanlı istekler, gecikme histogramları,
+//	                                   goroutine sayısı, istek başına CPU ve heap maliyeti
+//	                                   (Prometheus formatı, bkz. metrics.go ve request_cost.go)
+//	GET /debug/pprof/...               net/http/pprof (-pprof=false ile kapatılır); loadgen-go
+//	                                   -profile-dir ile yük sırasında CPU profili alır
+//	GET /debug/fgprof?seconds=N        fgprof wall-clock profili (-pprof ile birlikte): Bekleyen
+//	                                   goroutine'ler de örneklenir, I/O ve Mongo getMore beklemesi
+//	                                   CPU profilinin aksine görünür (loadgen-go -profile-mode wall)
+//
+// -json results.jsonl ile kapanışta endpoint başına gecikme histogramı, zaman çizelgesi ve makine
+// bilgisi ortak sonuç formatında (benchkit.Result) dosyaya eklenir (bkz. results.go).
+//
+// Parametre verilmezse -iterations / -delay varsayılanları kullanılır. -timeout veya ?timeout=500ms
+// ile iş süresi sınırlanır: Süre dolunca CPU ve I/O işi kesilir ve 504 döner (bkz. timeout.go).
+// -inject veya ?inject=pareto:5ms,1.5 ile işten önce dağılımdan çekilen yapay gecikme eklenir
+// (bkz. latency.go). -limit /cpu=4 ile endpoint'in eşzamanlı istek sayısı sınırlanır; fazlası
+// sırada bekler ve bekleme süresi ölçülür (Little Yasası, bkz. limiter.go).
+// -keepalive, -tls ve -http2 protokolün etkisini ölçmek içindir (bkz. protocol.go).
+//
+// /job üç modda çalışır (-job-mode):
+//   - direct: Her istek kendi goroutine'inde işlenir (sınırsız eşzamanlılık)
+//   - pool: -workers worker + -queue boyutunda kuyruk; kuyruk doluysa -reject-status (429/503)
+//     ve Retry-After ile hemen reddedilir (bkz. pool.go). -autoscale ile worker sayısı kuyruk
+//     derinliği ve bekleme süresine göre değişir (bkz. autoscale.go). -overload ile kuyruk
+//     dolunca reddetmek yerine bekletme, en eskiyi atma veya CoDel seçilir (bkz. overload.go)
+//   - queue: POST /job işi -queue-uri arka ucuna yazar (memory, redis://, mongodb://), -workers
+//     worker oradan lease ile alır; en az bir kez teslim, çöken worker'ın işi tekrar verilir
+//     (bkz. queue.go). GET /job (senkron) direct moddaki gibi çalışır
+//
+// KULLANIM (io-vs-cpu-demo klasöründe):
+//
+//	go run ./server-go
+//	go run ./server-go -addr :5000 -iterations 10000000 -delay 500ms
+//	go run ./server-go -job-mode pool -workers 8 -queue 32
+//	go run ./server-go -job-mode pool -workers 2 -queue 256 -autoscale min=2,max=64,wait=50ms
+//	go run ./server-go -job-mode pool -workers 8 -queue 256 -overload codel:5ms,100ms
+//	go run ./server-go -upstream http://localhost:3000/ping -mongo-uri mongodb://localhost:27017
+//	go run ./server-go -inject pareto:5ms,1.5
+//	go run ./server-go -limit /cpu=4,/io=100
+//	go run ./server-go -tls -http2=false -keepalive=false
+//	go run ./server-go -job-mode queue -queue-uri redis://localhost:6379/0 -crash-prob 0.1
+//	go run ./server-go -job-mode queue -queue-uri mongodb://localhost:27017/iovscpu -lease 10s
+//
+// -scaling ile sunucu açılmaz: cpuHeavyTask ve ioTask farklı GOMAXPROCS ve worker sayılarıyla
+// ölçülür, ölçekleme verimi tablosu yazılır (bkz. scaling.go). İş miktarı -iterations / -delay:
+//
+//	go run ./server-go -scaling -iterations 5000000 -delay 10ms
+//	go run ./server-go -scaling -scaling-procs 1,2,4,8 -scaling-window 5s
+func main() {
+	addr := flag.String("addr", ":4000", "Dinlenecek adres")
+	iterations := flag.Int64("iterations", 50_000_000, "/cpu için varsayılan iterasyon sayısı")
+	delay := flag.Duration("delay", 2*time.Second, "/io ve /job için varsayılan gecikme")
+	jobMode := flag.String("job-mode", "direct", "/job modu: direct (istek başına goroutine), pool veya queue")
+	workers := flag.Int("workers", 8, "Pool/queue modunda worker sayısı")
+	queueSize := flag.Int("queue", 32, "Pool modunda kuyruk boyutu")
+	overload := flag.String("overload", "reject", "Pool kuyruğu dolunca: reject, block, drop-oldest, codel:5ms,100ms (bkz. overload.go)")
+	autoscale := flag.String("autoscale", "", "Pool modunda worker sayısını yüke göre değiştir: on veya min=2,max=64,wait=100ms,depth=1,cooldown=10s (bkz. autoscale.go)")
+	rejectStatus := flag.Int("reject-status", http.StatusServiceUnavailable, "Pool kuyruğu doluyken dönülen kod (429 veya 503)")
+	jobTTL := flag.Duration("job-ttl", 5*time.Minute, "Biten asenkron işlerin sonucunun saklanma süresi")
+	queueURI := flag.String("queue-uri", "memory", "Queue modunda arka uç: memory, redis://host:6379/0, mongodb://host:27017/iovscpu")
+	lease := flag.Duration("lease", 30*time.Second, "Queue modunda lease süresi: Ack edilmeyen iş bu süre sonunda tekrar verilir")
+	maxAttempts := flag.Int("max-attempts", 5, "Queue modunda bir işin en fazla teslim sayısı (aşılırsa failed)")
+	crashProb := flag.Float64("crash-prob", 0, "Queue modunda worker'ın işi Ack etmeden bırakma olasılığı (0-1, çökme simülasyonu)")
+	enablePprof := flag.Bool("pprof", true, "/debug/pprof ve /debug/fgprof endpoint'lerini aç")
+	scaling := flag.Bool("scaling", false, "Sunucu yerine GOMAXPROCS/paralellik ölçekleme deneyini çalıştır")
+	scalingProcs := flag.String("scaling-procs", "", "Denenecek GOMAXPROCS değerleri (virgülle ayrılmış; varsayılan 1, NumCPU/2, NumCPU)")
+	scalingWindow := flag.Duration("scaling-window", 2*time.Second, "Ölçekleme deneyinde her hücrenin ölçüm süresi")
+	timeout := flag.Duration("timeout", 0, "/cpu, /io, /mixed ve GET /job için istek zaman aşımı (0 = yok; ?timeout= ile istek başına)")
+	inject := flag.String("inject", "", "İş endpoint'lerine yapay gecikme: fixed:50ms, uniform:10ms-100ms, normal:50ms,10ms, pareto:5ms,1.5 (bkz. latency.go)")
+	limit := flag.String("limit", "", "Endpoint başına eşzamanlılık sınırı: /cpu=4,/io=100 (POST /limits ile çalışırken değişir)")
+	keepAlive := flag.Bool("keepalive", true, "HTTP keep-alive (false: her yanıttan sonra bağlantı kapanır)")
+	idleTimeout := flag.Duration("idle-timeout", 0, "Boştaki keep-alive bağlantılarının kapatılma süresi (0 = sınırsız)")
+	useTLS := flag.Bool("tls", false, "HTTPS (-tls-cert/-tls-key yoksa self-signed sertifika üretilir)")
+	tlsCert := flag.String("tls-cert", "", "TLS sertifika dosyası (PEM)")
+	tlsKey := flag.String("tls-key", "", "TLS anahtar dosyası (PEM)")
+	http2 := flag.Bool("http2", true, "TLS'te HTTP/2 (false: yalnızca HTTP/1.1)")
+	grpcAddr := flag.String("grpc-addr", ":4001", "gRPC sunucusunun adresi (boş = kapalı, bkz. grpc.go)")
+	jsonPath := flag.String("json", "", "Kapanışta endpoint sonuçlarının ekleneceği JSON Lines dosyası (benchkit formatı)")
+	timelineInterval := flag.Duration("timeline", time.Second, "-json sonuçlarındaki zaman çizelgesinin aralığı")
+	shutdownTimeout := flag.Duration("shutdown-timeout", 30*time.Second, "SIGTERM'de süren istek ve işlerin en fazla bekleneceği süre (bkz. shutdown.go)")
+	ioDir := flag.String("io-dir", os.TempDir(), "file I/O görevinin geçici dosya dizini")
+	upstream := flag.String("upstream", "", "http I/O görevinin çağıracağı adres (ör: http://gateway-node:3000/ping)")
+	mongoURI := flag.String("mongo-uri", "", "mongo* I/O görevlerinin sunucusu (ör: mongodb://localhost:27017)")
+	mongoDB := flag.String("mongo-db", "perfdb", "mongo* I/O görevlerinin veritabanı (orders koleksiyonu okunur)")
+	flag.Parse()
+
+	defaults := newWorkloadDefaults(*iterations, *delay)
+
+	if *scaling {
+		procs, err := parseProcs(*scalingProcs)
+		if err != nil {
+			log.Fatalf("-scaling-procs: %v", err)
+		}
+		RunScaling(defaults, procs, *scalingWindow)
+		return
+	}
+
+	injectDist, err := ParseLatencyDist(*inject)
+	if err != nil {
+		log.Fatalf("-inject: %v", err)
+	}
+
+	mongoClient, err := configureIOTasks(ioConfig{Dir: *ioDir, Upstream: *upstream, MongoURI: *mongoURI, MongoDB: *mongoDB})
+	if err != nil {
+		log.Fatalf("-mongo-uri: %v", err)
+	}
+	if mongoClient != nil {
+		defer mongoClient.Disconnect(context.Background())
+	}
+
+	job := jobHandler(defaults)
+	store := NewJobStore(*jobTTL)
+	var submit http.HandlerFunc
+	var pool *JobPool
+	var autoscaler *Autoscaler
+	var runner *QueueRunner
+	switch *jobMode {
+	case "direct":
+	case "pool":
+		if *workers <= 0 || *queueSize < 0 {
+			log.Fatal("-workers pozitif, -queue negatif olmayan bir sayı olmalı")
+		}
+		if *rejectStatus != http.StatusTooManyRequests && *rejectStatus != http.StatusServiceUnavailable {
+			log.Fatal("-reject-status 429 veya 503 olmalı")
+		}
+		policy, err := ParseOverload(*overload)
+		if err != nil {
+			log.Fatalf("-overload: %v", err)
+		}
+		if policy.Strategy == "drop-oldest" && *queueSize == 0 {
+			log.Fatal("-overload drop-oldest için -queue pozitif olmalı (atılacak bekleyen iş yok)")
+		}
+		pool = NewJobPool(*workers, *queueSize, policy)
+		job = poolJobHandler(defaults, pool, *rejectStatus)
+		if *autoscale != "" {
+			cfg, err := ParseAutoscale(*autoscale)
+			if err != nil {
+				log.Fatalf("-autoscale: %v", err)
+			}
+			autoscaler = StartAutoscaler(pool, cfg)
+		}
+	case "queue":
+		if *workers <= 0 || *lease <= 0 || *maxAttempts <= 0 {
+			log.Fatal("-workers, -lease ve -max-attempts pozitif olmalı")
+		}
+		if *crashProb < 0 || *crashProb >= 1 {
+			log.Fatal("-crash-prob 0 ile 1 arasında olmalı (1 hariç)")
+		}
+		queue, err := OpenJobQueue(context.Background(), *queueURI)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer queue.Close()
+		runner = StartQueueRunner(queue, redactQueueURI(*queueURI), store, *workers, *lease, *maxAttempts, *crashProb)
+		submit = queueSubmitHandler(defaults, store, runner)
+	default:
+		log.Fatalf("bilinmeyen -job-mode: %s (direct, pool, queue)", *jobMode)
+	}
+	if submit == nil {
+		submit = submitJobHandler(defaults, store, pool, *rejectStatus)
+	}
+
+	limits, err := ParseLimits(*limit)
+	if err != nil {
+		log.Fatalf("-limit: %v", err)
+	}
+	for name := range limits {
+		if !slices.Contains(limitedEndpoints, name) {
+			log.Fatalf("-limit: bilinmeyen endpoint %q (%v)", name, limitedEndpoints)
+		}
+	}
+	limiter := NewLimiter(limits)
+
+	mux := http.NewServeMux()
+	metrics := NewHTTPMetrics()
+	if *jsonPath != "" {
+		metrics.RecordResults(*timelineInterval)
+	}
+	metrics.StartCostAttribution()
+	metrics.AddCollector(limiter.WritePrometheus)
+	wsHub := NewWSHub()
+	metrics.AddCollector(wsHub.WritePrometheus)
+	metrics.AddCollector(mongoQueries.WritePrometheus)
+	if autoscaler != nil {
+		metrics.AddCollector(autoscaler.WritePrometheus)
+	}
+	// İş endpoint'leri: Zaman aşımı slot beklemesini ve enjekte edilen gecikmeyi de kapsar
+	work := func(name string, h http.HandlerFunc) http.HandlerFunc {
+		return withTimeout(*timeout, limiter.Wrap(name, withLatency(injectDist, h)))
+	}
+	metrics.Handle(mux, "/cpu", work("/cpu", cpuHandler(defaults)))
+	metrics.Handle(mux, "/io", work("/io", ioHandler(defaults)))
+	metrics.Handle(mux, "GET /cpu/tasks", cpuTasksHandler(defaults))
+	metrics.Handle(mux, "/mixed", work("/mixed", mixedHandler(defaults)))
+	drainer := &drainState{}
+	metrics.Handle(mux, "GET /job", drainer.rejectWhileDraining(work("/job", job)))
+	metrics.Handle(mux, "POST /job", drainer.rejectWhileDraining(submit))
+	metrics.Handle(mux, "GET /job/{id}", jobStatusHandler(store))
+	mux.Handle("GET /metrics", metrics)
+	mux.Handle("/limits", limiter)
+	mux.Handle("/config", &configHandler{defaults: defaults, limiter: limiter, pool: pool, autoscaler: autoscaler, jobMode: *jobMode})
+	// Ölçülmez: Bağlantı ömrü istek süresi değildir; metrikleri ws_* (bkz. websocket.go)
+	mux.Handle("GET /ws", wsHub)
+	if *enablePprof {
+		// Ölçülmez: Profil isteği saniyelerce sürer ve /metrics gecikmelerini bozar
+		mux.HandleFunc("/debug/pprof/", pprof.Index)
+		mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+		mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+		mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+		mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+		mux.Handle("/debug/fgprof", fgprof.Handler())
+	}
+	mux.HandleFunc("GET /job/stats", func(w http.ResponseWriter, r *http.Request) {
+		stats := struct {
+			Mode      string            `json:"mode"`
+			Jobs      map[JobStatus]int `json:"jobs"` // Depodaki asenkron işler
+			Pool      *JobPoolStats     `json:"pool,omitempty"`
+			Autoscale *AutoscaleStats   `json:"autoscale,omitempty"`
+			Queue     *QueueStats       `json:"queue,omitempty"`
+		}{Mode: *jobMode, Jobs: store.Counts()}
+		if pool != nil {
+			poolStats := pool.Stats()
+			stats.Pool = &poolStats
+		}
+		if autoscaler != nil {
+			autoscaleStats := autoscaler.Stats()
+			stats.Autoscale = &autoscaleStats
+		}
+		if runner != nil {
+			queueStats := runner.Stats(r.Context())
+			stats.Queue = &queueStats
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(stats)
+	})
+
+	if (*tlsCert == "") != (*tlsKey == "") {
+		log.Fatal("-tls-cert ve -tls-key birlikte verilmeli")
+	}
+	protocol := protocolConfig{KeepAlive: *keepAlive, IdleTimeout: *idleTimeout, TLS: *useTLS || *tlsCert != "",
+		CertFile: *tlsCert, KeyFile: *tlsKey, HTTP2: *http2}
+	server := &http.Server{Addr: *addr, Handler: mux}
+	if err := protocol.apply(server); err != nil {
+		log.Fatalf("TLS: %v", err)
+	}
+	server.RegisterOnShutdown(wsHub.CloseAll)
+	var grpcServer *grpc.Server
+	if *grpcAddr != "" {
+		if grpcServer, err = startGRPC(*grpcAddr, defaults, metrics); err != nil {
+			log.Fatalf("gRPC: %v", err)
+		}
+		fmt.Printf("gRPC server running on %s\n", *grpcAddr)
+	}
+	serveErr := make(chan error, 1)
+	go func() { serveErr <- protocol.listen(server) }()
+	fmt.Printf("Go server running on %s (iterations=%d, delay=%v, job-mode=%s, %s)\n", *addr, *iterations, *delay, *jobMode, protocol)
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGTERM, os.Interrupt)
+	select {
+	case err := <-serveErr:
+		log.Fatal(err)
+	case sig := <-signals:
+		fmt.Printf("\n%v alındı\n", sig)
+	}
+	// İkinci sinyal beklemeden çıkar
+	signal.Stop(signals)
+	durable := runner != nil && !strings.HasPrefix(*queueURI, "memory")
+	// gRPC çağrıları HTTP ile aynı anda ve aynı süre içinde boşaltılır
+	grpcStopped := make(chan struct{})
+	go func() {
+		if grpcServer != nil {
+			stopGRPC(grpcServer, *shutdownTimeout)
+		}
+		close(grpcStopped)
+	}()
+	report := drain(server, drainer, store, metrics, runner, durable, *shutdownTimeout)
+	<-grpcStopped
+	fmt.Printf("✅ Kapandı: %s\n", report)
+	for _, line := range metrics.CostSummary() {
+		fmt.Printf("🧮 %s\n", line)
+	}
+
+	if *jsonPath != "" {
+		params := map[string]string{"jobMode": *jobMode, "gomaxprocs": strconv.Itoa(runtime.GOMAXPROCS(0)),
+			"proto": protocol.String()}
+		if *limit != "" {
+			params["limit"] = *limit
+		}
+		if *inject != "" {
+			params["inject"] = *inject
+		}
+		if *timeout > 0 {
+			params["timeout"] = timeout.String()
+		}
+		results := metrics.Results(params)
+		for _, r := range results {
+			if err := benchkit.AppendJSONL(*jsonPath, r); err != nil {
+				fmt.Printf("⚠️  Sonuç yazılamadı: %v\n", err)
+				return
+			}
+		}
+		fmt.Printf("📄 %d endpoint sonucu %s dosyasına eklendi\n", len(results), *jsonPath)
+	}
+}
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"benchkit"
+)
+
+// metrics.go - GET /metrics (Prometheus metin formatı)
+// Yük testi sonuçlarını sunucu tarafındaki doygunlukla karşılaştırmak için: İstemci gecikmesi
+// artarken sunucuda eşzamanlı istek ve goroutine sayısı da artıyorsa darboğaz sunucudadır.
+// gateway-node aynı metrik adlarını kullanır, iki sunucu aynı panelde gösterilebilir.
+//
+// İstemci kütüphanesi yerine birkaç metriklik minimal bir kayıt tutulur (bkz. queue_redis.go'daki
+// RESP istemcisi): Metin formatı basittir ve demo bağımlılık gerektirmez.
+//
+//	http_requests_total{endpoint,method,code}           Biten istek sayısı
+//	http_requests_in_flight{endpoint}                   İşlenmekte olan istek sayısı
+//	http_request_duration_seconds{endpoint} (histogram) Handler süresi
+//	go_goroutines, go_threads, go_gomaxprocs            Çalışma zamanı
+//	process_cpu_seconds_total                           Process'in harcadığı CPU süresi (user + system)
+//	endpoint_cpu_seconds_total{endpoint} ve benzerleri  İstek başına CPU ve heap maliyeti (bkz. request_cost.go)
+//
+// Diğer bileşenler AddCollector ile kendi metriklerini ekler (ör: limiter.go'daki sıra metrikleri).
+
+// latencyBuckets - Histogram üst sınırları (saniye)
+// /io varsayılanı 2s, büyük /cpu işleri onlarca saniye sürebilir
+var latencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+
+// HTTPMetrics - Endpoint başına istek metrikleri
+type HTTPMetrics struct {
+	mu         sync.Mutex
+	endpoints  map[string]*endpointMetrics
+	collectors []func(*strings.Builder)
+	results    *resultRecorder // -json verildiyse (bkz. results.go)
+	cost       *costMeter      // StartCostAttribution çağrıldıysa (bkz. request_cost.go)
+}
+
+type endpointMetrics struct {
+	inFlight int64
+	requests map[requestKey]int64
+	buckets  []int64 // latencyBuckets'a karşılık gelen sayılar (kümülatif değil)
+	count    int64
+	sum      float64
+	result   *endpointResult // results açıksa
+	cost     endpointCost
+}
+
+type requestKey struct {
+	method string
+	code   int
+}
+
+// NewHTTPMetrics - Boş kayıt
+func NewHTTPMetrics() *HTTPMetrics {
+	return &HTTPMetrics{endpoints: map[string]*endpointMetrics{}}
+}
+
+func (m *HTTPMetrics) endpoint(name string) *endpointMetrics {
+	e, ok := m.endpoints[name]
+	if !ok {
+		e = &endpointMetrics{requests: map[requestKey]int64{}, buckets: make([]int64, len(latencyBuckets))}
+		if m.results != nil {
+			e.result = m.results.newEndpoint()
+		}
+		m.endpoints[name] = e
+	}
+	return e
+}
+
+// Handle - Handler'ı ölçerek mux'a kaydeder
+// Etiket olarak pattern'in yolu kullanılır ("GET /job/{id}" → "/job/{id}"): İş ID'leri
+// etiket sayısını patlatmaz
+func (m *HTTPMetrics) Handle(mux *http.ServeMux, pattern string, h http.HandlerFunc) {
+	name := pattern
+	if i := strings.IndexByte(pattern, ' '); i >= 0 {
+		name = pattern[i+1:]
+	}
+	m.mu.Lock()
+	m.endpoint(name) // İstek gelmeden de /metrics'te görünsün
+	m.mu.Unlock()
+	mux.HandleFunc(pattern, m.instrument(name, h))
+}
+
+func (m *HTTPMetrics) instrument(name string, h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		done := m.Start(name)
+		rec := &statusRecorder{ResponseWriter: w, code: http.StatusOK}
+		defer func() { done(r.Method, rec.code) }()
+		h(rec, r)
+	}
+}
+
+// Start - İsteği işlenmekte olarak sayar; dönen fonksiyon istek bitince method ve kodla çağrılır
+// HTTP dışı istekler (gRPC, bkz. grpc.go) aynı metriklere buradan kaydedilir
+func (m *HTTPMetrics) Start(name string) func(method string, code int) {
+	m.mu.Lock()
+	m.endpoint(name).inFlight++
+	m.mu.Unlock()
+
+	start := time.Now()
+	return func(method string, code int) {
+		elapsed := time.Since(start)
+		seconds := elapsed.Seconds()
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		e := m.endpoint(name)
+		e.inFlight--
+		e.requests[requestKey{method: method, code: code}]++
+		e.count++
+		e.sum += seconds
+		if i := sort.SearchFloat64s(latencyBuckets, seconds); i < len(latencyBuckets) {
+			e.buckets[i]++
+		}
+		if e.result != nil {
+			e.result.record(elapsed, code)
+		}
+	}
+}
+
+// AddCollector - /metrics çıktısına eklenecek metrikleri yazan fonksiyon
+// Sunucu başlamadan önce çağrılmalıdır (kayıt kilitsiz okunur)
+func (m *HTTPMetrics) AddCollector(collect func(*strings.Builder)) {
+	m.collectors = append(m.collectors, collect)
+}
+
+// InFlight - Tüm endpoint'lerde işlenmekte olan istek sayısı
+func (m *HTTPMetrics) InFlight() int64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var n int64
+	for _, e := range m.endpoints {
+		n += e.inFlight
+	}
+	return n
+}
+
+// sortedEndpoints - Endpoint adları (sıralı)
+func sortedEndpoints(endpoints map[string]*endpointMetrics) []string {
+	names := make([]string, 0, len(endpoints))
+	for name := range endpoints {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ServeHTTP - GET /metrics
+func (m *HTTPMetrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var b strings.Builder
+	m.mu.Lock()
+	names := sortedEndpoints(m.endpoints)
+
+	b.WriteString("# HELP http_requests_total Biten HTTP istekleri\n# TYPE http_requests_total counter\n")
+	for _, name := range names {
+		e := m.endpoints[name]
+		keys := make([]requestKey, 0, len(e.requests))
+		for k := range e.requests {
+			keys = append(keys, k)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			if keys[i].method != keys[j].method {
+				return keys[i].method < keys[j].method
+			}
+			return keys[i].code < keys[j].code
+		})
+		for _, k := range keys {
+			fmt.Fprintf(&b, "http_requests_total{endpoint=%q,method=%q,code=\"%d\"} %d\n", name, k.method, k.code, e.requests[k])
+		}
+	}
+
+	b.WriteString("# HELP http_requests_in_flight İşlenmekte olan HTTP istekleri\n# TYPE http_requests_in_flight gauge\n")
+	for _, name := range names {
+		fmt.Fprintf(&b, "http_requests_in_flight{endpoint=%q} %d\n", name, m.endpoints[name].inFlight)
+	}
+
+	b.WriteString("# HELP http_request_duration_seconds Handler süresi\n# TYPE http_request_duration_seconds histogram\n")
+	for _, name := range names {
+		e := m.endpoints[name]
+		var cumulative int64
+		for i, le := range latencyBuckets {
+			cumulative += e.buckets[i]
+			fmt.Fprintf(&b, "http_request_duration_seconds_bucket{endpoint=%q,le=\"%g\"} %d\n", name, le, cumulative)
+		}
+		fmt.Fprintf(&b, "http_request_duration_seconds_bucket{endpoint=%q,le=\"+Inf\"} %d\n", name, e.count)
+		fmt.Fprintf(&b, "http_request_duration_seconds_sum{endpoint=%q} %g\n", name, e.sum)
+		fmt.Fprintf(&b, "http_request_duration_seconds_count{endpoint=%q} %d\n", name, e.count)
+	}
+	m.writeCostMetrics(&b, names)
+	m.mu.Unlock()
+
+	for _, collect := range m.collectors {
+		collect(&b)
+	}
+	threads, _ := runtime.ThreadCreateProfile(nil)
+	fmt.Fprintf(&b, "# HELP go_goroutines Goroutine sayısı\n# TYPE go_goroutines gauge\ngo_goroutines %d\n", runtime.NumGoroutine())
+	fmt.Fprintf(&b, "# HELP go_threads Oluşturulan OS thread sayısı\n# TYPE go_threads gauge\ngo_threads %d\n", threads)
+	fmt.Fprintf(&b, "# HELP go_gomaxprocs GOMAXPROCS\n# TYPE go_gomaxprocs gauge\ngo_gomaxprocs %d\n", runtime.GOMAXPROCS(0))
+	fmt.Fprintf(&b, "# HELP process_cpu_seconds_total Harcanan CPU süresi (user + system)\n# TYPE process_cpu_seconds_total counter\nprocess_cpu_seconds_total %g\n", benchkit.CPUTime().Seconds())
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	w.Write([]byte(b.String()))
+}
+
+// statusRecorder - Handler'ın yazdığı durum kodunu yakalar
+type statusRecorder struct {
+	http.ResponseWriter
+	code        int
+	wroteHeader bool
+}
+
+func (r *statusRecorder) WriteHeader(code int) {
+	if !r.wroteHeader {
+		r.code, r.wroteHeader = code, true
+	}
+	r.ResponseWriter.WriteHeader(code)
+}
+
+// Unwrap - http.ResponseController alttaki writer'a ulaşabilsin (Flush, deadline'lar)
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
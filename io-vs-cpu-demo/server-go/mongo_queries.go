@@ -0,0 +1,249 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// mongo_queries.go - mongo-perf-lab'ın orders koleksiyonuna parametreli sorgular (-mongo-uri)
+// sleep bir veritabanı beklemesini taklit eder ama gecikmesi sabittir; gerçek sorguda süre
+// indekse, okunan doküman sayısına, bağlantı havuzuna ve sunucunun o anki yüküne bağlıdır.
+// Üç tipik sorgu şekli I/O görevi olarak kayıtlıdır (/job?iotask=, /io?task=, gRPC IO):
+//   - mongo-point: _id ile n ardışık FindOne (en ucuz sorgu, gecikmenin çoğu ağ gidiş-dönüşü)
+//   - mongo-scan:  Rastgele status ve total aralığıyla find, en fazla n doküman okunur
+//   - mongo-agg:   Rastgele status için son n saatin siparişleri günlük gruplanır ($match + $group)
+//
+// Veri mongo-perf-lab generator'ıyla üretilir; indeksler create_index ile oluşturulur.
+// Sorgu başına süre /metrics'te mongo_query_duration_seconds{query} olarak görünür: HTTP
+// gecikmesinin ne kadarının veritabanında geçtiği ayrılır.
+//
+//	go run ./server-go -mongo-uri mongodb://localhost:27017 -job-mode pool -workers 16
+//	go run ./loadgen-go -url "http://localhost:4000/job?iotask=mongo-point&iosize=1,http://localhost:4000/job?iotask=mongo-agg&iosize=24"
+
+// pointIDPoolSize - mongo-point'in rastgele seçtiği _id havuzu ($sample ile bir kez okunur)
+const pointIDPoolSize = 1000
+
+// queryBuckets - Sorgu süresi histogramının üst sınırları (saniye)
+// Nokta okuma milisaniyenin altındadır; HTTP'nin latencyBuckets'ı 5 ms'den başlar
+var queryBuckets = []float64{0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5}
+
+// mongoQueries - Mongo görevlerinin sorgu metrikleri (configureIOTasks bağlar)
+var mongoQueries = &queryStats{queries: map[string]*queryMetrics{}}
+
+// configureMongoQueries - Sorgu görevlerini koleksiyona bağlar; tüm mongo görevleri ölçülür
+func configureMongoQueries(orders *mongo.Collection) {
+	ids := &idPool{orders: orders}
+	ioTasks["mongo"].run = mongoQueries.measure("mongo", func(ctx context.Context, n int64) (int64, error) {
+		return mongoTask(ctx, orders, n)
+	})
+	ioTasks["mongo-point"].run = mongoQueries.measure("mongo-point", func(ctx context.Context, n int64) (int64, error) {
+		return pointQuery(ctx, orders, ids, n)
+	})
+	ioTasks["mongo-scan"].run = mongoQueries.measure("mongo-scan", func(ctx context.Context, n int64) (int64, error) {
+		return scanQuery(ctx, orders, n)
+	})
+	ioTasks["mongo-agg"].run = mongoQueries.measure("mongo-agg", func(ctx context.Context, n int64) (int64, error) {
+		return aggregateQuery(ctx, orders, n)
+	})
+}
+
+// idPool - mongo-point için örneklenmiş _id'ler
+// İlk istekte okunur; hata olursa sonraki istek tekrar dener (sunucu sonradan açılabilir)
+type idPool struct {
+	orders *mongo.Collection
+	mu     sync.Mutex
+	ids    []any
+}
+
+func (p *idPool) random(ctx context.Context) (any, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.ids) == 0 {
+		cursor, err := p.orders.Aggregate(ctx, mongo.Pipeline{
+			{{Key: "$sample", Value: bson.D{{Key: "size", Value: pointIDPoolSize}}}},
+			{{Key: "$project", Value: bson.D{{Key: "_id", Value: 1}}}},
+		})
+		if err != nil {
+			return nil, err
+		}
+		var docs []struct {
+			ID any `bson:"_id"`
+		}
+		if err := cursor.All(ctx, &docs); err != nil {
+			return nil, err
+		}
+		if len(docs) == 0 {
+			return nil, errors.New("orders koleksiyonu boş (mongo-perf-lab generator ile doldurun)")
+		}
+		for _, doc := range docs {
+			p.ids = append(p.ids, doc.ID)
+		}
+	}
+	return p.ids[rand.Intn(len(p.ids))], nil
+}
+
+// pointQuery - Havuzdan rastgele _id'lerle n ardışık FindOne; sonuç bulunan doküman sayısı
+func pointQuery(ctx context.Context, orders *mongo.Collection, ids *idPool, n int64) (int64, error) {
+	var found int64
+	for i := int64(0); i < n; i++ {
+		id, err := ids.random(ctx)
+		if err != nil {
+			return found, err
+		}
+		var doc bson.Raw
+		err = orders.FindOne(ctx, bson.D{{Key: "_id", Value: id}}).Decode(&doc)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			continue // Havuz okunduktan sonra silinmiş olabilir
+		}
+		if err != nil {
+			return found, err
+		}
+		found++
+	}
+	return found, nil
+}
+
+// scanQuery - Rastgele status ve 1000'lik total aralığındaki ilk n sipariş; sonuç okunan doküman sayısı
+// status indeksi aralığı daraltır, total filtresi indeksten sonra dokümanlarda uygulanır
+// (docsExamined > nReturned); {status, total} bileşik indeksiyle fark görülür
+func scanQuery(ctx context.Context, orders *mongo.Collection, n int64) (int64, error) {
+	low := rand.Intn(4000)
+	filter := bson.D{
+		{Key: "status", Value: orderStatuses[rand.Intn(len(orderStatuses))]},
+		{Key: "total", Value: bson.D{{Key: "$gte", Value: low}, {Key: "$lt", Value: low + 1000}}},
+	}
+	cursor, err := orders.Find(ctx, filter, options.Find().SetLimit(n).SetBatchSize(int32(min(n, 1000))))
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+	var count int64
+	for cursor.Next(ctx) {
+		var doc bson.Raw
+		if err := cursor.Decode(&doc); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, cursor.Err()
+}
+
+// aggregateQuery - Rastgele status için son n saatin siparişlerini gün gün sayar ve toplar
+// Sonuç gruplanan sipariş sayısıdır. Pencere büyüdükçe $group'a giren doküman sayısı artar:
+// Sorgu süresi okunan veriyle büyür, yanıt boyutu (gün sayısı) küçük kalır
+func aggregateQuery(ctx context.Context, orders *mongo.Collection, hours int64) (int64, error) {
+	since := time.Now().Add(-time.Duration(hours) * time.Hour)
+	cursor, err := orders.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.D{
+			{Key: "status", Value: orderStatuses[rand.Intn(len(orderStatuses))]},
+			{Key: "createdAt", Value: bson.D{{Key: "$gte", Value: since}}},
+		}}},
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: bson.D{{Key: "$dateToString", Value: bson.D{{Key: "format", Value: "%Y-%m-%d"}, {Key: "date", Value: "$createdAt"}}}}},
+			{Key: "orders", Value: bson.D{{Key: "$sum", Value: 1}}},
+			{Key: "revenue", Value: bson.D{{Key: "$sum", Value: "$total"}}},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "_id", Value: 1}}}},
+	})
+	if err != nil {
+		return 0, err
+	}
+	var days []struct {
+		Orders int64 `bson:"orders"`
+	}
+	if err := cursor.All(ctx, &days); err != nil {
+		return 0, err
+	}
+	var total int64
+	for _, day := range days {
+		total += day.Orders
+	}
+	return total, nil
+}
+
+// queryStats - Sorgu tipi başına süre histogramı ve sonuç sayıları
+type queryStats struct {
+	mu      sync.Mutex
+	queries map[string]*queryMetrics
+}
+
+type queryMetrics struct {
+	buckets []int64 // queryBuckets'a karşılık gelen sayılar (kümülatif değil)
+	count   int64
+	sum     float64
+	errors  int64
+	docs    int64 // Sorgunun döndürdüğü doküman/sipariş sayısı
+}
+
+// measure - Görevi sarar; her çağrının süresi ve sonucu kaydedilir
+func (s *queryStats) measure(name string, run func(context.Context, int64) (int64, error)) func(context.Context, int64) (int64, error) {
+	s.mu.Lock()
+	s.queries[name] = &queryMetrics{buckets: make([]int64, len(queryBuckets))}
+	s.mu.Unlock()
+	return func(ctx context.Context, n int64) (int64, error) {
+		start := time.Now()
+		result, err := run(ctx, n)
+		seconds := time.Since(start).Seconds()
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		q := s.queries[name]
+		q.count++
+		q.sum += seconds
+		q.docs += result
+		if err != nil {
+			q.errors++
+		}
+		if i := sort.SearchFloat64s(queryBuckets, seconds); i < len(queryBuckets) {
+			q.buckets[i]++
+		}
+		return result, err
+	}
+}
+
+// WritePrometheus - mongo_query_* metrikleri (HTTPMetrics.AddCollector ile bağlanır)
+func (s *queryStats) WritePrometheus(b *strings.Builder) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	names := make([]string, 0, len(s.queries))
+	for name := range s.queries {
+		names = append(names, name)
+	}
+	if len(names) == 0 {
+		return
+	}
+	sort.Strings(names)
+
+	b.WriteString("# HELP mongo_queries_total Biten sorgular\n# TYPE mongo_queries_total counter\n")
+	for _, name := range names {
+		q := s.queries[name]
+		fmt.Fprintf(b, "mongo_queries_total{query=%q,result=\"ok\"} %d\n", name, q.count-q.errors)
+		fmt.Fprintf(b, "mongo_queries_total{query=%q,result=\"error\"} %d\n", name, q.errors)
+	}
+	b.WriteString("# HELP mongo_query_documents_total Sorguların döndürdüğü doküman (mongo-agg: gruplanan sipariş)\n# TYPE mongo_query_documents_total counter\n")
+	for _, name := range names {
+		fmt.Fprintf(b, "mongo_query_documents_total{query=%q} %d\n", name, s.queries[name].docs)
+	}
+	b.WriteString("# HELP mongo_query_duration_seconds Görev süresi (mongo-point'te n FindOne'ın toplamı)\n# TYPE mongo_query_duration_seconds histogram\n")
+	for _, name := range names {
+		q := s.queries[name]
+		var cumulative int64
+		for i, le := range queryBuckets {
+			cumulative += q.buckets[i]
+			fmt.Fprintf(b, "mongo_query_duration_seconds_bucket{query=%q,le=\"%g\"} %d\n", name, le, cumulative)
+		}
+		fmt.Fprintf(b, "mongo_query_duration_seconds_bucket{query=%q,le=\"+Inf\"} %d\n", name, q.count)
+		fmt.Fprintf(b, "mongo_query_duration_seconds_sum{query=%q} %g\n", name, q.sum)
+		fmt.Fprintf(b, "mongo_query_duration_seconds_count{query=%q} %d\n", name, q.count)
+	}
+}
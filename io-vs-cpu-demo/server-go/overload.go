@@ -0,0 +1,129 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+)
+
+// overload.go - Pool kuyruğu dolduğunda ya da yavaşladığında ne yapılacağı (-overload)
+// Kapasiteden fazla iş geldiğinde bir şeyin feda edilmesi kaçınılmazdır; strateji neyin feda
+// edileceğini seçer:
+//
+//	reject          Kuyruk doluysa yeni iş hemen reddedilir (-reject-status 429/503, varsayılan)
+//	block           Kuyrukta yer açılana kadar istek bekletilir; geri basınç istemciye gecikme olarak yansır
+//	drop-oldest     Kuyruk doluysa en eski bekleyen iş atılır, yeni iş alınır: Zaten çok beklemiş
+//	                (istemcisi büyük ihtimalle vazgeçmiş) iş yerine taze iş çalışır
+//	codel:5ms,100ms Adaptif (CoDel): Kuyruk beklemesi interval (100ms) boyunca target'ın (5ms)
+//	                üstünde kalırsa kuyruktan alınan işler artan sıklıkla atılır (interval/√n)
+//
+// CoDel kuyruk uzunluğuna değil beklemeye bakar: Kısa patlamalar emilir, kalıcı kuyruk (standing
+// queue) eritilir. Atılan işler de -reject-status ile yanıtlanır. compare-go -overload stratejileri
+// aynı yükte karşılaştırır (goodput ve başarılı isteklerin gecikmesi).
+
+// errJobDropped - İş kuyruktayken aşırı yük stratejisince atıldı
+var errJobDropped = errors.New("aşırı yük: iş kuyruktan atıldı")
+
+// errQueueFull - Kuyruk dolu, iş kabul edilmedi
+var errQueueFull = errors.New("kuyruk dolu")
+
+// OverloadPolicy - Aşırı yük stratejisi
+type OverloadPolicy struct {
+	Strategy string        // reject, block, drop-oldest, codel
+	Target   time.Duration // codel: Kabul edilebilir kuyruk beklemesi
+	Interval time.Duration // codel: Beklemenin target üstünde kalabileceği süre
+}
+
+// String - Başlangıç satırı için
+func (p OverloadPolicy) String() string {
+	if p.Strategy == "codel" {
+		return fmt.Sprintf("codel:%v,%v", p.Target, p.Interval)
+	}
+	return p.Strategy
+}
+
+// ParseOverload - "reject", "block", "drop-oldest" veya "codel[:target,interval]"
+func ParseOverload(spec string) (OverloadPolicy, error) {
+	strategy, params, _ := strings.Cut(spec, ":")
+	p := OverloadPolicy{Strategy: strategy, Target: 5 * time.Millisecond, Interval: 100 * time.Millisecond}
+	switch strategy {
+	case "", "reject":
+		p.Strategy = "reject"
+	case "block", "drop-oldest":
+	case "codel":
+		if params == "" {
+			break
+		}
+		target, interval, ok := strings.Cut(params, ",")
+		if !ok {
+			return p, fmt.Errorf("codel:target,interval bekleniyor (ör: codel:5ms,100ms)")
+		}
+		var err error
+		if p.Target, err = time.ParseDuration(target); err == nil {
+			p.Interval, err = time.ParseDuration(interval)
+		}
+		if err != nil {
+			return p, fmt.Errorf("codel: %v", err)
+		}
+		if p.Target <= 0 || p.Interval <= 0 {
+			return p, fmt.Errorf("codel: target ve interval pozitif olmalı")
+		}
+	default:
+		return p, fmt.Errorf("bilinmeyen strateji %q (reject, block, drop-oldest, codel)", strategy)
+	}
+	return p, nil
+}
+
+// codel - CoDel'in kuyruktan alma tarafı (RFC 8289'un sadeleştirilmiş hali)
+// Tüm worker'lar tek durumu paylaşır: Bekleme, kuyruğun kendisinin özelliğidir
+type codel struct {
+	target, interval time.Duration
+
+	mu         sync.Mutex
+	firstAbove time.Time // Beklemenin target üstüne çıktığı andan interval sonrası (sıfır: altında)
+	dropping   bool
+	dropNext   time.Time
+	count      int // Bu düşürme döneminde atılan iş
+}
+
+// shouldDrop - Kuyruktan alınan iş sojourn kadar beklemişse atılmalı mı
+func (c *codel) shouldDrop(sojourn time.Duration, now time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if sojourn < c.target {
+		c.firstAbove, c.dropping = time.Time{}, false
+		return false
+	}
+	if c.firstAbove.IsZero() {
+		c.firstAbove = now.Add(c.interval)
+		return false
+	}
+	if now.Before(c.firstAbove) {
+		return false
+	}
+	if !c.dropping {
+		// Yakın zamanda düşürme yapıldıysa sıklık kaldığı yerden devam eder
+		if now.Sub(c.dropNext) < 16*c.interval && c.count > 2 {
+			c.count -= 2
+		} else {
+			c.count = 1
+		}
+		c.dropping = true
+		c.dropNext = now.Add(c.nextGap())
+		return true
+	}
+	if now.Before(c.dropNext) {
+		return false
+	}
+	c.count++
+	c.dropNext = c.dropNext.Add(c.nextGap())
+	return true
+}
+
+// nextGap - interval/√count: Kuyruk erimedikçe düşürme sıklaşır
+func (c *codel) nextGap() time.Duration {
+	return time.Duration(float64(c.interval) / math.Sqrt(float64(c.count)))
+}
@@ -0,0 +1,329 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+
+	"benchkit"
+)
+
+// pool.go - /job için sınırlı kuyruk + sabit worker havuzu
+// Varsayılan (direct) modda her istek kendi goroutine'inde işlenir: Yük arttıkça eşzamanlı iş
+// sayısı sınırsız büyür, gecikme herkes için birden kötüleşir. Pool modunda işler sınırlı bir
+// kuyruğa alınır ve N worker tarafından işlenir; kuyruk doluysa istek hemen reddedilir
+// (429/503 + Retry-After). Böylece kabul edilen işlerin gecikmesi sınırlı kalır ve istemciye
+// "daha sonra tekrar dene" sinyali verilir (backpressure).
+//
+// Kuyruk dolduğunda ne olacağı -overload ile seçilir: reject, block, drop-oldest, codel
+// (bkz. overload.go). Worker sayısı çalışırken Resize ile değişebilir; -autoscale bunu kuyruk derinliği ve bekleme
+// süresine göre otomatik yapar (bkz. autoscale.go).
+
+// jobSpec - Tek bir işin miktarı
+type jobSpec struct {
+	Task       string // CPU görevi (bkz. cpu_tasks.go)
+	Iterations int64
+	IOTask     string // I/O görevi (bkz. io_tasks.go); sleep ise Delay kadar beklenir
+	IOSize     int64
+	Delay      time.Duration
+}
+
+// run - İşi çalıştırır: Önce CPU, sonra I/O; sonuç CPU görevininkidir
+func (j jobSpec) run(ctx context.Context) (int64, error) {
+	result, err := runCPUTask(ctx, j.Task, j.Iterations)
+	if err != nil {
+		return result, err
+	}
+	if _, err := runIOTask(ctx, j.IOTask, j.IOSize, j.Delay); err != nil {
+		return result, fmt.Errorf("I/O (%s): %w", j.IOTask, err)
+	}
+	return result, nil
+}
+
+// poolJob - Kuyruktaki iş
+type poolJob struct {
+	ctx      context.Context // Senkron işte isteğin context'i: İstek bitince iş atlanır/kesilir
+	spec     jobSpec
+	enqueued time.Time
+	onStart  func() // Worker işi aldığında çağrılır (nil olabilir)
+	done     chan poolJobResult
+}
+
+// poolJobResult - İşin sonucu ve süreleri
+type poolJobResult struct {
+	Result int64
+	Err    error         // I/O görevinin hatası
+	Queued time.Duration // Kuyrukta bekleme
+	Run    time.Duration // Worker'da çalışma
+}
+
+// JobPool - Sınırlı kuyruklu worker havuzu
+type JobPool struct {
+	queue  chan *poolJob
+	quit   chan struct{} // Küçülürken her token bir worker'ı (elindeki iş bitince) durdurur
+	policy OverloadPolicy
+	codel  *codel // policy codel ise
+
+	mu         sync.Mutex
+	workers    int                 // Hedef worker sayısı
+	busy       int                 // Şu an iş çalıştıran worker
+	queueWait  *benchkit.Histogram // Kuyrukta geçen süre
+	runTime    *benchkit.Histogram // Çalışma süresi (Retry-After tahmini için)
+	windowWait *benchkit.Histogram // Son TakeWindow'dan beri kuyruk beklemesi (autoscale.go)
+	maxDepth   int
+	accepted   int64
+	rejected   int64
+	dropped    int64 // drop-oldest / codel ile kuyruktan atılan
+	blocked    int64 // block ile yer açılmasını bekleyen gönderimler
+	completed  int64
+}
+
+// NewJobPool - Havuzu oluşturur ve worker'ları başlatır
+func NewJobPool(workers, queueSize int, policy OverloadPolicy) *JobPool {
+	p := &JobPool{
+		queue:      make(chan *poolJob, queueSize),
+		quit:       make(chan struct{}),
+		policy:     policy,
+		queueWait:  benchkit.NewHistogram(),
+		runTime:    benchkit.NewHistogram(),
+		windowWait: benchkit.NewHistogram(),
+	}
+	if policy.Strategy == "codel" {
+		p.codel = &codel{target: policy.Target, interval: policy.Interval}
+	}
+	p.Resize(workers)
+	return p
+}
+
+// Resize - Worker sayısını n yapar
+// Büyürken yeni worker'lar hemen başlar; küçülürken fazla worker'lar ellerindeki işi bitirip
+// çıkar (çalışan iş kesilmez), bu yüzden gerçek goroutine sayısı hedefe kısa sürede yaklaşır
+func (p *JobPool) Resize(n int) {
+	p.mu.Lock()
+	diff := n - p.workers
+	p.workers = n
+	p.mu.Unlock()
+	for i := 0; i < diff; i++ {
+		go p.worker()
+	}
+	if diff < 0 {
+		go func() {
+			for i := 0; i < -diff; i++ {
+				p.quit <- struct{}{}
+			}
+		}()
+	}
+}
+
+func (p *JobPool) worker() {
+	for {
+		select {
+		case <-p.quit:
+			return
+		case job := <-p.queue:
+			p.run(job)
+		}
+	}
+}
+
+func (p *JobPool) run(job *poolJob) {
+	queued := time.Since(job.enqueued)
+	if p.codel != nil && p.codel.shouldDrop(queued, time.Now()) {
+		p.drop(job)
+		return
+	}
+	p.mu.Lock()
+	p.busy++
+	p.queueWait.Record(queued)
+	p.windowWait.Record(queued)
+	p.mu.Unlock()
+	if job.onStart != nil {
+		job.onStart()
+	}
+	start := time.Now()
+	// Kuyrukta beklerken isteği zaman aşımına uğrayan iş hiç başlamaz (ctx zaten bitti)
+	result, err := job.spec.run(job.ctx)
+	run := time.Since(start)
+
+	p.mu.Lock()
+	p.busy--
+	p.runTime.Record(run)
+	p.completed++
+	p.mu.Unlock()
+
+	job.done <- poolJobResult{Result: result, Err: err, Queued: queued, Run: run}
+}
+
+// poolWindow - TakeWindow'un döndürdüğü anlık durum
+type poolWindow struct {
+	Workers   int
+	Busy      int
+	Depth     int
+	Started   int64         // Pencerede işe başlayan (kuyruk beklemesi ölçülen) iş sayısı
+	WaitP95   time.Duration // Pencerede işe başlayanların kuyruk beklemesi (p95)
+	Completed int64         // Başlangıçtan beri biten
+}
+
+// TakeWindow - Anlık durumu ve son çağrıdan beri kuyruk beklemesini döner, pencereyi sıfırlar
+func (p *JobPool) TakeWindow() poolWindow {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	s := p.windowWait.Summary()
+	window := poolWindow{Workers: p.workers, Busy: p.busy, Depth: len(p.queue),
+		Started: s.Count, WaitP95: s.P95, Completed: p.completed}
+	p.windowWait = benchkit.NewHistogram()
+	return window
+}
+
+// drop - Kuyruktaki işi çalıştırmadan errJobDropped ile bitirir
+func (p *JobPool) drop(job *poolJob) {
+	p.mu.Lock()
+	p.dropped++
+	p.mu.Unlock()
+	job.done <- poolJobResult{Err: errJobDropped, Queued: time.Since(job.enqueued)}
+}
+
+// Submit - İşi kuyruğa ekler; kuyruk doluysa stratejiye göre davranır (bkz. overload.go)
+// reject'te errQueueFull döner; block'ta yer açılana ya da wait bitene kadar bekler (wait.Err()).
+// Sonuç job.done'dan okunur (kanal tamponlu: Okuyan olmasa da worker bloklanmaz).
+// ctx iptal edilirse iş kesilir; asenkron işler ctx olarak context.Background(), wait olarak
+// isteğin context'ini verir. block'ta kuyruk beklemesi yer açılmasını beklerken geçen süreyi de içerir
+func (p *JobPool) Submit(wait, ctx context.Context, spec jobSpec, onStart func()) (*poolJob, error) {
+	job := &poolJob{ctx: ctx, spec: spec, enqueued: time.Now(), onStart: onStart, done: make(chan poolJobResult, 1)}
+	for {
+		select {
+		case p.queue <- job:
+			p.markAccepted()
+			return job, nil
+		default:
+		}
+		switch p.policy.Strategy {
+		case "block":
+			p.mu.Lock()
+			p.blocked++
+			p.mu.Unlock()
+			select {
+			case p.queue <- job:
+				p.markAccepted()
+				return job, nil
+			case <-wait.Done():
+				return nil, wait.Err()
+			}
+		case "drop-oldest":
+			select {
+			case old := <-p.queue:
+				p.drop(old)
+			default: // Bu arada bir worker aldı, tekrar denenir
+			}
+			continue
+		}
+		p.mu.Lock()
+		p.rejected++
+		p.mu.Unlock()
+		return nil, errQueueFull
+	}
+}
+
+// markAccepted - Kuyruğa giren işi sayar
+func (p *JobPool) markAccepted() {
+	p.mu.Lock()
+	p.accepted++
+	p.maxDepth = max(p.maxDepth, len(p.queue))
+	p.mu.Unlock()
+}
+
+// RetryAfter - Kuyruğun boşalması için tahmini süre (saniye, en az 1)
+// Kuyruktaki iş sayısı / worker sayısı × ortalama çalışma süresi
+func (p *JobPool) RetryAfter() int {
+	p.mu.Lock()
+	mean := p.runTime.Summary().Mean
+	workers := max(1, p.workers)
+	p.mu.Unlock()
+	wait := time.Duration(float64(len(p.queue)) / float64(workers) * float64(mean))
+	return max(1, int(math.Ceil(wait.Seconds())))
+}
+
+// JobPoolStats - /job/stats yanıtı
+type JobPoolStats struct {
+	Strategy      string              `json:"strategy"`
+	Workers       int                 `json:"workers"`
+	Busy          int                 `json:"busy"`
+	QueueCapacity int                 `json:"queueCapacity"`
+	QueueDepth    int                 `json:"queueDepth"`    // Şu an kuyrukta bekleyen
+	MaxQueueDepth int                 `json:"maxQueueDepth"` // Başlangıçtan beri en yüksek
+	Accepted      int64               `json:"accepted"`
+	Rejected      int64               `json:"rejected"`
+	Dropped       int64               `json:"dropped"`
+	Blocked       int64               `json:"blocked"`
+	Completed     int64               `json:"completed"`
+	QueueWaitMs   *benchkit.LatencyMs `json:"queueWaitMs"`
+	RunMs         *benchkit.LatencyMs `json:"runMs"`
+}
+
+// Stats - Havuzun anlık durumu
+func (p *JobPool) Stats() JobPoolStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return JobPoolStats{
+		Strategy:      p.policy.String(),
+		Workers:       p.workers,
+		Busy:          p.busy,
+		QueueCapacity: cap(p.queue),
+		QueueDepth:    len(p.queue),
+		MaxQueueDepth: p.maxDepth,
+		Accepted:      p.accepted,
+		Rejected:      p.rejected,
+		Dropped:       p.dropped,
+		Blocked:       p.blocked,
+		Completed:     p.completed,
+		QueueWaitMs:   p.queueWait.Summary().Millis(),
+		RunMs:         p.runTime.Summary().Millis(),
+	}
+}
+
+// rejectJob - Kuyruk doluyken yanıt: Kod + tahmini bekleme
+func rejectJob(w http.ResponseWriter, pool *JobPool, status int) {
+	w.Header().Set("Retry-After", fmt.Sprint(pool.RetryAfter()))
+	http.Error(w, "kuyruk dolu, daha sonra tekrar deneyin", status)
+}
+
+// poolJobHandler - Pool modunda GET /job?cpu=N&io=D
+// rejectStatus: Kuyruk doluyken ya da iş kuyruktan atıldığında dönülen kod (429 veya 503)
+func poolJobHandler(defaults *workloadDefaults, pool *JobPool, rejectStatus int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		spec, err := jobParams(r, defaults)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		job, err := pool.Submit(r.Context(), r.Context(), spec, nil)
+		if errors.Is(err, errQueueFull) {
+			rejectJob(w, pool, rejectStatus)
+			return
+		}
+		if err != nil {
+			writeWorkError(w, "Kuyruk bekleme", err)
+			return
+		}
+		select {
+		case result := <-job.done:
+			if errors.Is(result.Err, errJobDropped) {
+				rejectJob(w, pool, rejectStatus)
+				return
+			}
+			if result.Err != nil {
+				writeWorkError(w, "Job", result.Err)
+				return
+			}
+			fmt.Fprintf(w, "Job result: %d (kuyrukta %v, çalışma %v)\n", result.Result, result.Queued, result.Run)
+		case <-r.Context().Done():
+			// Zaman aşımı ya da istemci vazgeçti - iş kuyruktan çıkarılamaz, ama worker onu
+			// aldığında ctx bittiği için çalıştırmadan bırakır
+			writeWorkError(w, "Job", r.Context().Err())
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"net/http"
+	"time"
+)
+
+// protocol.go - Protokol seviyesi ayarlar: keep-alive, TLS ve HTTP/2
+// Aynı iş için throughput ve gecikme farkının ne kadarının protokolden geldiğini loadgen-go ile
+// ölçmek için:
+//   - -keepalive=false: Her yanıttan sonra bağlantı kapanır; her istek yeni TCP (ve TLS) el
+//     sıkışması öder (loadgen "yeni bağlantı" sütununda görünür)
+//   - -tls: HTTPS; -tls-cert/-tls-key verilmezse bellekte self-signed sertifika üretilir
+//     (loadgen -insecure ile bağlanır)
+//   - -http2: TLS'te ALPN ile HTTP/2 (varsayılan açık); tek bağlantıda çoklu istek
+//
+// Şifresiz HTTP/2 (h2c) standart kütüphanede Go 1.24 ile geldi; modül Go 1.22'de olduğu için
+// HTTP/2 yalnızca -tls ile kullanılabilir.
+
+// protocolConfig - Sunucu protokol bayrakları
+type protocolConfig struct {
+	KeepAlive   bool
+	IdleTimeout time.Duration // Boştaki keep-alive bağlantısının kapatılma süresi (0 = sınırsız)
+	TLS         bool
+	CertFile    string
+	KeyFile     string
+	HTTP2       bool
+}
+
+// apply - Ayarları sunucuya uygular
+func (c protocolConfig) apply(server *http.Server) error {
+	server.SetKeepAlivesEnabled(c.KeepAlive)
+	server.IdleTimeout = c.IdleTimeout
+	if !c.HTTP2 {
+		// Boş (nil olmayan) harita, TLS'te otomatik HTTP/2 yükseltmesini kapatır
+		server.TLSNextProto = map[string]func(*http.Server, *tls.Conn, http.Handler){}
+	}
+	if c.TLS && c.CertFile == "" {
+		cert, err := selfSignedCert()
+		if err != nil {
+			return err
+		}
+		server.TLSConfig = &tls.Config{Certificates: []tls.Certificate{cert}}
+	}
+	return nil
+}
+
+// listen - Sunucuyu ayara göre HTTP veya HTTPS olarak başlatır (ListenAndServe gibi bloklar)
+func (c protocolConfig) listen(server *http.Server) error {
+	if !c.TLS {
+		return server.ListenAndServe()
+	}
+	// Self-signed sertifika TLSConfig'te; dosya adları boş geçilir
+	return server.ListenAndServeTLS(c.CertFile, c.KeyFile)
+}
+
+// String - Başlangıç satırı için özet
+func (c protocolConfig) String() string {
+	s := "http/1.1"
+	if c.TLS {
+		s = "https"
+		if c.HTTP2 {
+			s += "+h2"
+		}
+		if c.CertFile == "" {
+			s += " (self-signed)"
+		}
+	}
+	if !c.KeepAlive {
+		s += ", keep-alive kapalı"
+	}
+	return s
+}
+
+// selfSignedCert - localhost ve 127.0.0.1 için bellekte geçici sertifika
+func selfSignedCert() (tls.Certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: "io-vs-cpu-demo"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(365 * 24 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		DNSNames:     []string{"localhost", "server-go"},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1), net.IPv6loopback},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, nil
+}
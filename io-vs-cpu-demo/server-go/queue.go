@@ -0,0 +1,305 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"benchkit"
+)
+
+// queue.go - Kalıcı iş kuyruğu arka uçları (queue modu)
+// Pool modundaki kuyruk process belleğindedir: Sunucu çökerse kuyruktaki ve çalışan işler kaybolur.
+// Queue modunda POST /job işi seçilen arka uca yazar, worker'lar oradan "kiralayarak" (lease) alır:
+//   - memory: Process belleği (karşılaştırma tabanı, kalıcı değil)
+//   - redis://host:6379/0: Redis listesi + lease sorted set'i (bkz. queue_redis.go)
+//   - mongodb://host:27017/iovscpu: Koleksiyon, findAndModify ile atomik alma (bkz. queue_mongo.go)
+//
+// Teslim garantisi en az bir kez (at-least-once): İş, bittikten sonra Ack edilir. Worker Ack
+// etmeden çökerse lease süresi dolunca iş tekrar kuyruğa döner ve başka bir worker'a verilir.
+// Bu yüzden aynı iş birden fazla çalışabilir - işler idempotent olmalıdır.
+// Her Claim işe yeni bir lease token'ı verir ve Ack sadece bu token hâlâ geçerliyse siler:
+// Lease'i dolan ve iş başka bir worker'a verildikten sonra geç kalan Ack, hâlâ çalışan işi silemez.
+// Çökme -crash-prob ile simüle edilir: Worker işi çalıştırır ama Ack etmeden bırakır.
+
+// QueuedJob - Kuyruktaki iş
+type QueuedJob struct {
+	ID         string    `json:"id" bson:"_id"`
+	Spec       jobSpec   `json:"spec" bson:"spec"`
+	EnqueuedAt time.Time `json:"enqueuedAt" bson:"enqueuedAt"`
+	Attempts   int       `json:"attempts" bson:"attempts"` // Kaçıncı teslim (ilk alınışta 1)
+	Lease      string    `json:"-" bson:"lease,omitempty"` // Claim başına token (Ack bununla eşleşir)
+}
+
+// ErrLeaseLost - Ack edilen kiralama artık geçerli değil: İş başka bir worker'a verilmiş
+var ErrLeaseLost = errors.New("lease kaybedildi")
+
+// newLeaseToken - Claim başına rastgele token
+func newLeaseToken() string {
+	return newJobID()
+}
+
+// JobQueue - Kuyruk arka ucu
+type JobQueue interface {
+	// Enqueue - İşi kuyruğun sonuna ekler
+	Enqueue(ctx context.Context, job QueuedJob) error
+	// Claim - Sıradaki işi lease süresi boyunca kiralar; kuyruk boşsa nil döner (beklemez)
+	// Süresi dolmuş lease'ler Claim sırasında kuyruğa geri alınır
+	Claim(ctx context.Context, worker string, lease time.Duration) (*QueuedJob, error)
+	// Ack - İşi tamamlandı olarak kuyruktan siler
+	// job.Lease ile yapılan kiralama başka bir Claim'e geçmişse hiçbir şey silmez, ErrLeaseLost döner
+	Ack(ctx context.Context, job *QueuedJob) error
+	// Depth - Bekleyen ve kiralanmış iş sayısı
+	Depth(ctx context.Context) (pending, inFlight int64, err error)
+	Close() error
+}
+
+// OpenJobQueue - Adrese göre arka ucu açar: "memory", "redis://...", "mongodb://..."
+func OpenJobQueue(ctx context.Context, uri string) (JobQueue, error) {
+	switch {
+	case uri == "memory":
+		return NewMemoryQueue(), nil
+	case strings.HasPrefix(uri, "redis://"):
+		return OpenRedisQueue(uri)
+	case strings.HasPrefix(uri, "mongodb://"), strings.HasPrefix(uri, "mongodb+srv://"):
+		return OpenMongoQueue(ctx, uri)
+	}
+	return nil, fmt.Errorf("bilinmeyen kuyruk arka ucu: %q (memory, redis://, mongodb://)", uri)
+}
+
+// redactQueueURI - Adresteki şifreyi gizler (/job/stats ve loglarda gösterilir)
+func redactQueueURI(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil || u.User == nil {
+		return uri
+	}
+	return u.Redacted()
+}
+
+// QueueRunner - Kuyruktan iş alan worker'lar ve kuyruk metrikleri
+type QueueRunner struct {
+	queue       JobQueue
+	backend     string
+	store       *JobStore
+	lease       time.Duration
+	maxAttempts int
+	crashProb   float64
+
+	mu          sync.Mutex
+	enqueue     *benchkit.Histogram // POST /job'un arka uca yazma süresi
+	claim       *benchkit.Histogram // Boş olmayan Claim çağrılarının süresi
+	endToEnd    *benchkit.Histogram // Kuyruğa girişten Ack'e
+	redelivered int64               // Attempts > 1 ile alınan işler
+	crashed     int64               // Simüle edilen çökmeler (Ack edilmeden bırakılan)
+	failed      int64               // maxAttempts aşıldığı için bırakılan işler
+	leaseLost   int64               // Lease'i başka worker'a geçtiği için reddedilen Ack'ler
+	errors      int64               // Arka uç hataları
+
+	stopping atomic.Bool    // Stop sonrası yeni iş alınmaz
+	running  sync.WaitGroup // Çalışan worker'lar
+}
+
+// queuePollInterval - Kuyruk boşken worker'ın tekrar denemeden önce beklediği süre
+const queuePollInterval = 50 * time.Millisecond
+
+// StartQueueRunner - workers adet worker başlatır
+func StartQueueRunner(queue JobQueue, backend string, store *JobStore, workers int, lease time.Duration, maxAttempts int, crashProb float64) *QueueRunner {
+	r := &QueueRunner{
+		queue:       queue,
+		backend:     backend,
+		store:       store,
+		lease:       lease,
+		maxAttempts: maxAttempts,
+		crashProb:   crashProb,
+		enqueue:     benchkit.NewHistogram(),
+		claim:       benchkit.NewHistogram(),
+		endToEnd:    benchkit.NewHistogram(),
+	}
+	for i := 0; i < workers; i++ {
+		r.running.Add(1)
+		go r.worker(fmt.Sprintf("worker-%d", i))
+	}
+	return r
+}
+
+// Stop - Worker'lar yeni iş almayı bırakır; ellerindeki işi bitirip Ack ederler
+// Kuyrukta bekleyen işler arka uçta kalır (kalıcı arka uçta sonraki process alır)
+func (r *QueueRunner) Stop() {
+	r.stopping.Store(true)
+}
+
+// Wait - Stop sonrası tüm worker'ların çıkmasını bekler (ctx bitene kadar)
+func (r *QueueRunner) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		r.running.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (r *QueueRunner) worker(name string) {
+	defer r.running.Done()
+	ctx := context.Background()
+	for !r.stopping.Load() {
+		start := time.Now()
+		job, err := r.queue.Claim(ctx, name, r.lease)
+		if err != nil {
+			r.count(&r.errors)
+			log.Printf("%s: claim hatası: %v", name, err)
+			time.Sleep(time.Second)
+			continue
+		}
+		if job == nil {
+			time.Sleep(queuePollInterval)
+			continue
+		}
+		r.mu.Lock()
+		r.claim.Record(time.Since(start))
+		if job.Attempts > 1 {
+			r.redelivered++
+		}
+		r.mu.Unlock()
+
+		// Kayıt başka bir process'te (ör: çöken sunucu) oluşturulmuş olabilir
+		r.store.Ensure(*job)
+		if job.Attempts > r.maxAttempts {
+			r.count(&r.failed)
+			r.store.Fail(job.ID)
+			r.ack(ctx, name, job)
+			continue
+		}
+		r.store.Start(job.ID)
+		result, err := job.Spec.run(ctx)
+		if err != nil {
+			// Ack edilmez: Geçici bir I/O hatası olabilir, lease dolunca tekrar denenir
+			r.count(&r.errors)
+			log.Printf("%s: iş hatası (%s): %v", name, job.ID, err)
+			continue
+		}
+
+		if r.crashProb > 0 && rand.Float64() < r.crashProb {
+			// Çökme: İş yapıldı ama Ack edilmedi - lease dolunca tekrar teslim edilir
+			r.count(&r.crashed)
+			continue
+		}
+		if r.ack(ctx, name, job) {
+			r.store.Finish(job.ID, result)
+			r.mu.Lock()
+			r.endToEnd.Record(time.Since(job.EnqueuedAt))
+			r.mu.Unlock()
+		}
+	}
+}
+
+func (r *QueueRunner) ack(ctx context.Context, worker string, job *QueuedJob) bool {
+	err := r.queue.Ack(ctx, job)
+	if errors.Is(err, ErrLeaseLost) {
+		// İş başka bir worker'da yeniden çalışıyor: Sonucu o kaydeder
+		r.count(&r.leaseLost)
+		log.Printf("%s: lease kaybedildi (%s), ack yok sayıldı", worker, job.ID)
+		return false
+	}
+	if err != nil {
+		r.count(&r.errors)
+		log.Printf("%s: ack hatası (%s): %v", worker, job.ID, err)
+		return false
+	}
+	return true
+}
+
+func (r *QueueRunner) count(n *int64) {
+	r.mu.Lock()
+	*n++
+	r.mu.Unlock()
+}
+
+// Enqueue - İşi arka uca yazar ve yazma süresini kaydeder
+func (r *QueueRunner) Enqueue(ctx context.Context, job QueuedJob) error {
+	start := time.Now()
+	err := r.queue.Enqueue(ctx, job)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err != nil {
+		r.errors++
+		return err
+	}
+	r.enqueue.Record(time.Since(start))
+	return nil
+}
+
+// QueueStats - /job/stats içindeki kuyruk metrikleri
+type QueueStats struct {
+	Backend     string              `json:"backend"`
+	Pending     int64               `json:"pending"`
+	InFlight    int64               `json:"inFlight"`
+	Redelivered int64               `json:"redelivered"`
+	Crashed     int64               `json:"crashed"`
+	Failed      int64               `json:"failed"`
+	LeaseLost   int64               `json:"leaseLost"`
+	Errors      int64               `json:"errors"`
+	EnqueueMs   *benchkit.LatencyMs `json:"enqueueMs"`
+	ClaimMs     *benchkit.LatencyMs `json:"claimMs"`
+	EndToEndMs  *benchkit.LatencyMs `json:"endToEndMs"` // Kuyruğa girişten tamamlanmaya
+}
+
+// Stats - Kuyruğun anlık durumu (derinlik arka uçtan okunur)
+func (r *QueueRunner) Stats(ctx context.Context) QueueStats {
+	pending, inFlight, err := r.queue.Depth(ctx)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err != nil {
+		r.errors++
+	}
+	return QueueStats{
+		Backend:     r.backend,
+		Pending:     pending,
+		InFlight:    inFlight,
+		Redelivered: r.redelivered,
+		Crashed:     r.crashed,
+		Failed:      r.failed,
+		LeaseLost:   r.leaseLost,
+		Errors:      r.errors,
+		EnqueueMs:   r.enqueue.Summary().Millis(),
+		ClaimMs:     r.claim.Summary().Millis(),
+		EndToEndMs:  r.endToEnd.Summary().Millis(),
+	}
+}
+
+// queueSubmitHandler - Queue modunda POST /job?cpu=N&io=D
+// Yanıt, iş arka uca yazıldıktan sonra döner: Kalıcı arka uçta 202 = iş kaybolmayacak
+func queueSubmitHandler(defaults *workloadDefaults, store *JobStore, runner *QueueRunner) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		spec, err := jobParams(r, defaults)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		job := store.Create(spec)
+		queued := QueuedJob{ID: job.ID, Spec: spec, EnqueuedAt: job.CreatedAt}
+		if err := runner.Enqueue(r.Context(), queued); err != nil {
+			store.Delete(job.ID)
+			http.Error(w, "kuyruğa yazılamadı: "+err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Location", "/job/"+job.ID)
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(job)
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryQueue - Process belleğinde kuyruk
+// Lease/tekrar teslim mantığı diğer arka uçlarla aynıdır, ama process ölünce her şey kaybolur:
+// Kalıcı arka uçların maliyetini ölçmek için taban çizgisi.
+type MemoryQueue struct {
+	mu       sync.Mutex
+	pending  []QueuedJob
+	inFlight map[string]memoryLease
+}
+
+type memoryLease struct {
+	job   QueuedJob
+	until time.Time
+}
+
+// NewMemoryQueue - Boş kuyruk
+func NewMemoryQueue() *MemoryQueue {
+	return &MemoryQueue{inFlight: map[string]memoryLease{}}
+}
+
+func (q *MemoryQueue) Enqueue(ctx context.Context, job QueuedJob) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.pending = append(q.pending, job)
+	return nil
+}
+
+func (q *MemoryQueue) Claim(ctx context.Context, worker string, lease time.Duration) (*QueuedJob, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	// Süresi dolan lease'ler kuyruğun başına döner (tekrar teslim öncelikli)
+	now := time.Now()
+	for id, l := range q.inFlight {
+		if now.After(l.until) {
+			delete(q.inFlight, id)
+			q.pending = append([]QueuedJob{l.job}, q.pending...)
+		}
+	}
+	if len(q.pending) == 0 {
+		return nil, nil
+	}
+	job := q.pending[0]
+	q.pending = q.pending[1:]
+	job.Attempts++
+	job.Lease = newLeaseToken()
+	q.inFlight[job.ID] = memoryLease{job: job, until: now.Add(lease)}
+	return &job, nil
+}
+
+func (q *MemoryQueue) Ack(ctx context.Context, job *QueuedJob) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if l, ok := q.inFlight[job.ID]; !ok || l.job.Lease != job.Lease {
+		return ErrLeaseLost
+	}
+	delete(q.inFlight, job.ID)
+	return nil
+}
+
+func (q *MemoryQueue) Depth(ctx context.Context) (int64, int64, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return int64(len(q.pending)), int64(len(q.inFlight)), nil
+}
+
+func (q *MemoryQueue) Close() error { return nil }
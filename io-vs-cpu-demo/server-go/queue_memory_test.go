@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// Lease'i dolan ve başka bir Claim'e geçen işin eski Ack'i işi silmemeli:
+// Redis ve MongoDB arka uçları aynı sözleşmeyi lease token'ıyla uygular
+func TestMemoryQueueStaleAckAfterReclaim(t *testing.T) {
+	ctx := context.Background()
+	q := NewMemoryQueue()
+	if err := q.Enqueue(ctx, QueuedJob{ID: "job-1", EnqueuedAt: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+
+	stale, err := q.Claim(ctx, "worker-0", time.Millisecond)
+	if err != nil || stale == nil {
+		t.Fatalf("ilk claim: job=%v err=%v", stale, err)
+	}
+	time.Sleep(5 * time.Millisecond)
+
+	fresh, err := q.Claim(ctx, "worker-1", time.Minute)
+	if err != nil || fresh == nil {
+		t.Fatalf("lease dolduktan sonra claim: job=%v err=%v", fresh, err)
+	}
+	if fresh.ID != stale.ID || fresh.Attempts != 2 {
+		t.Fatalf("tekrar teslim beklenirdi: id=%s attempts=%d", fresh.ID, fresh.Attempts)
+	}
+	if fresh.Lease == stale.Lease {
+		t.Fatalf("her claim yeni lease token'ı almalı: %q", fresh.Lease)
+	}
+
+	if err := q.Ack(ctx, stale); !errors.Is(err, ErrLeaseLost) {
+		t.Fatalf("eski lease ile ack: err=%v, ErrLeaseLost beklenirdi", err)
+	}
+	if _, inFlight, _ := q.Depth(ctx); inFlight != 1 {
+		t.Fatalf("eski ack işi silmemeli: inFlight=%d", inFlight)
+	}
+
+	if err := q.Ack(ctx, fresh); err != nil {
+		t.Fatalf("güncel lease ile ack: %v", err)
+	}
+	if pending, inFlight, _ := q.Depth(ctx); pending != 0 || inFlight != 0 {
+		t.Fatalf("ack sonrası kuyruk boş olmalı: pending=%d inFlight=%d", pending, inFlight)
+	}
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoQueue - MongoDB koleksiyonunda kuyruk
+// Her iş bir dokümandır: {_id, spec, enqueuedAt, attempts, leaseUntil, worker, lease}
+//   - leaseUntil null: Bekliyor
+//   - leaseUntil > şimdi: Bir worker'da (kiralanmış)
+//   - leaseUntil <= şimdi: Worker Ack etmeden çöktü, tekrar alınabilir
+//
+// Alma işlemi tek bir findAndModify'dır (FindOneAndUpdate): Koşula uyan en eski işi seçip
+// lease'ini yazar, iki worker aynı işi alamaz. Süresi dolan lease'ler ayrı bir adımla geri
+// alınmaz - alma koşulu onları zaten kapsar. Alma işlemi lease'e yeni bir token yazar; Ack
+// _id ile birlikte bu token'ı da eşler, böylece geç kalan bir Ack yeniden alınmış işi silemez.
+// Kalıcılık write concern'e bağlıdır: Varsayılan w:majority ile 202 dönen iş, primary değişse
+// bile kaybolmaz.
+type MongoQueue struct {
+	client *mongo.Client
+	col    *mongo.Collection
+}
+
+// OpenMongoQueue - mongodb://host:27017[/db][?collection=...]
+// Veritabanı verilmezse "iovscpu", koleksiyon verilmezse "jobs" kullanılır
+func OpenMongoQueue(ctx context.Context, uri string) (*MongoQueue, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, err
+	}
+	// collection sürücünün bilmediği bir seçenek: Bağlantı adresine geçmeden ayıklanır
+	query := u.Query()
+	coll := query.Get("collection")
+	if coll == "" {
+		coll = "jobs"
+	}
+	query.Del("collection")
+	u.RawQuery = query.Encode()
+	db := strings.TrimPrefix(u.Path, "/")
+	if db == "" {
+		db = "iovscpu"
+	}
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(u.String()))
+	if err != nil {
+		return nil, err
+	}
+	// mongo.Connect bağlantı açmaz: Yanlış adres sunucu açılırken fark edilsin
+	pingCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	if err := client.Ping(pingCtx, nil); err != nil {
+		client.Disconnect(ctx)
+		return nil, fmt.Errorf("mongodb %s: %v", u.Host, err)
+	}
+
+	col := client.Database(db).Collection(coll)
+	// Alma sorgusu (leaseUntil koşulu + enqueuedAt sırası) koleksiyonu taramasın
+	if _, err := col.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "leaseUntil", Value: 1}, {Key: "enqueuedAt", Value: 1}},
+	}); err != nil {
+		client.Disconnect(ctx)
+		return nil, err
+	}
+	return &MongoQueue{client: client, col: col}, nil
+}
+
+func (q *MongoQueue) Enqueue(ctx context.Context, job QueuedJob) error {
+	_, err := q.col.InsertOne(ctx, bson.D{
+		{Key: "_id", Value: job.ID},
+		{Key: "spec", Value: job.Spec},
+		{Key: "enqueuedAt", Value: job.EnqueuedAt},
+		{Key: "attempts", Value: 0},
+		{Key: "leaseUntil", Value: nil},
+	})
+	return err
+}
+
+func (q *MongoQueue) Claim(ctx context.Context, worker string, lease time.Duration) (*QueuedJob, error) {
+	now := time.Now()
+	filter := bson.D{{Key: "$or", Value: bson.A{
+		bson.D{{Key: "leaseUntil", Value: nil}},
+		bson.D{{Key: "leaseUntil", Value: bson.D{{Key: "$lte", Value: now}}}},
+	}}}
+	update := bson.D{
+		{Key: "$set", Value: bson.D{{Key: "leaseUntil", Value: now.Add(lease)}, {Key: "worker", Value: worker}, {Key: "lease", Value: newLeaseToken()}}},
+		{Key: "$inc", Value: bson.D{{Key: "attempts", Value: 1}}},
+	}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "enqueuedAt", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	var job QueuedJob
+	err := q.col.FindOneAndUpdate(ctx, filter, update, opts).Decode(&job)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (q *MongoQueue) Ack(ctx context.Context, job *QueuedJob) error {
+	res, err := q.col.DeleteOne(ctx, bson.D{{Key: "_id", Value: job.ID}, {Key: "lease", Value: job.Lease}})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return ErrLeaseLost
+	}
+	return nil
+}
+
+func (q *MongoQueue) Depth(ctx context.Context) (int64, int64, error) {
+	total, err := q.col.CountDocuments(ctx, bson.D{})
+	if err != nil {
+		return 0, 0, err
+	}
+	// Süresi dolan lease'ler tekrar alınmayı bekler: Bekleyen sayılır
+	inFlight, err := q.col.CountDocuments(ctx, bson.D{{Key: "leaseUntil", Value: bson.D{{Key: "$gt", Value: time.Now()}}}})
+	if err != nil {
+		return 0, 0, err
+	}
+	return total - inFlight, inFlight, nil
+}
+
+func (q *MongoQueue) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return q.client.Disconnect(ctx)
+}
@@ -0,0 +1,309 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RedisQueue - Redis üzerinde kuyruk
+// Anahtarlar (prefix varsayılan "iovscpu:jobs", ?prefix= ile değişir):
+//   - {prefix}:pending   Liste - bekleyen iş ID'leri (LPUSH ile eklenir, RPOP ile alınır → FIFO)
+//   - {prefix}:leases    Sorted set - kiralanmış işler, skor = lease bitişi (unix ms)
+//   - {prefix}:payload   Hash - ID → iş (JSON)
+//   - {prefix}:attempts  Hash - ID → teslim sayısı
+//
+// Alma işlemi Lua script'iyle atomiktir: Süresi dolan lease'ler listeye geri konur, sıradaki ID
+// listeden alınıp lease set'ine yazılır. Alma ve kiralama arasında çökme penceresi yoktur.
+// Redis'in kalıcılığı sunucu ayarına bağlıdır (AOF/RDB): appendfsync always olmadan
+// Redis'in kendisi çökerse son yazılan işler kaybolabilir.
+//
+// İstemci kütüphanesi yerine birkaç komutluk minimal RESP istemcisi kullanılır (bkz. respConn).
+type RedisQueue struct {
+	prefix string
+	conns  chan *respConn // Bağlantı havuzu (worker'lar aynı anda komut gönderir)
+	dial   func() (*respConn, error)
+}
+
+// redisClaimScript - KEYS: pending, leases, payload, attempts; ARGV: şimdi (ms), lease bitişi (ms)
+const redisClaimScript = `
+local expired = redis.call('ZRANGEBYSCORE', KEYS[2], '-inf', ARGV[1])
+for _, id in ipairs(expired) do
+  redis.call('ZREM', KEYS[2], id)
+  redis.call('RPUSH', KEYS[1], id)
+end
+while true do
+  local id = redis.call('RPOP', KEYS[1])
+  if not id then return false end
+  local payload = redis.call('HGET', KEYS[3], id)
+  if payload then
+    redis.call('ZADD', KEYS[2], ARGV[2], id)
+    local attempts = redis.call('HINCRBY', KEYS[4], id, 1)
+    return {payload, attempts}
+  end
+end`
+
+// redisAckScript - KEYS: leases, payload, attempts; ARGV: ID, lease bitişi (ms)
+// Lease bitişi Claim başına token'dır: İş tekrar alınmışsa skor değişmiştir ve hiçbir şey silinmez
+const redisAckScript = `
+local score = redis.call('ZSCORE', KEYS[1], ARGV[1])
+if not score or tonumber(score) ~= tonumber(ARGV[2]) then return 0 end
+redis.call('ZREM', KEYS[1], ARGV[1])
+redis.call('HDEL', KEYS[2], ARGV[1])
+redis.call('HDEL', KEYS[3], ARGV[1])
+return 1`
+
+// redisPoolSize - Havuzdaki en fazla bağlantı
+const redisPoolSize = 16
+
+// OpenRedisQueue - redis://[:şifre@]host:port[/db][?prefix=...]
+func OpenRedisQueue(uri string) (*RedisQueue, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, err
+	}
+	password, _ := u.User.Password()
+	db := strings.TrimPrefix(u.Path, "/")
+	prefix := u.Query().Get("prefix")
+	if prefix == "" {
+		prefix = "iovscpu:jobs"
+	}
+	q := &RedisQueue{prefix: prefix, conns: make(chan *respConn, redisPoolSize)}
+	q.dial = func() (*respConn, error) {
+		c, err := dialRESP(u.Host)
+		if err != nil {
+			return nil, err
+		}
+		if password != "" {
+			if _, err := c.Do("AUTH", password); err != nil {
+				c.Close()
+				return nil, err
+			}
+		}
+		if db != "" {
+			if _, err := c.Do("SELECT", db); err != nil {
+				c.Close()
+				return nil, err
+			}
+		}
+		return c, nil
+	}
+	// Bağlantıyı baştan dene: Yanlış adres sunucu açılırken fark edilsin
+	c, err := q.dial()
+	if err != nil {
+		return nil, fmt.Errorf("redis %s: %v", u.Host, err)
+	}
+	q.put(c)
+	return q, nil
+}
+
+func (q *RedisQueue) key(name string) string {
+	return q.prefix + ":" + name
+}
+
+// do - Havuzdan bağlantı alıp komutu çalıştırır
+// Ağ hatası alan bağlantı havuza geri konmaz
+func (q *RedisQueue) do(args ...string) (any, error) {
+	var c *respConn
+	select {
+	case c = <-q.conns:
+	default:
+		var err error
+		if c, err = q.dial(); err != nil {
+			return nil, err
+		}
+	}
+	reply, err := c.Do(args...)
+	var redisErr respError
+	if err != nil && !errors.As(err, &redisErr) {
+		c.Close()
+		return nil, err
+	}
+	q.put(c)
+	return reply, err
+}
+
+func (q *RedisQueue) put(c *respConn) {
+	select {
+	case q.conns <- c:
+	default:
+		c.Close()
+	}
+}
+
+func (q *RedisQueue) Enqueue(ctx context.Context, job QueuedJob) error {
+	payload, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+	// Önce içerik, sonra ID: Liste ID'yi gösterdiğinde içerik hazırdır
+	if _, err := q.do("HSET", q.key("payload"), job.ID, string(payload)); err != nil {
+		return err
+	}
+	_, err = q.do("LPUSH", q.key("pending"), job.ID)
+	return err
+}
+
+func (q *RedisQueue) Claim(ctx context.Context, worker string, lease time.Duration) (*QueuedJob, error) {
+	now := time.Now()
+	until := strconv.FormatInt(now.Add(lease).UnixMilli(), 10)
+	reply, err := q.do("EVAL", redisClaimScript, "4",
+		q.key("pending"), q.key("leases"), q.key("payload"), q.key("attempts"),
+		strconv.FormatInt(now.UnixMilli(), 10), until)
+	if err != nil || reply == nil {
+		return nil, err
+	}
+	values, ok := reply.([]any)
+	if !ok || len(values) != 2 {
+		return nil, fmt.Errorf("beklenmeyen claim yanıtı: %v", reply)
+	}
+	payload, _ := values[0].(string)
+	var job QueuedJob
+	if err := json.Unmarshal([]byte(payload), &job); err != nil {
+		return nil, err
+	}
+	attempts, _ := values[1].(int64)
+	job.Attempts = int(attempts)
+	job.Lease = until
+	return &job, nil
+}
+
+func (q *RedisQueue) Ack(ctx context.Context, job *QueuedJob) error {
+	reply, err := q.do("EVAL", redisAckScript, "3",
+		q.key("leases"), q.key("payload"), q.key("attempts"), job.ID, job.Lease)
+	if err != nil {
+		return err
+	}
+	if n, _ := reply.(int64); n == 0 {
+		return ErrLeaseLost
+	}
+	return nil
+}
+
+func (q *RedisQueue) Depth(ctx context.Context) (int64, int64, error) {
+	pending, err := q.do("LLEN", q.key("pending"))
+	if err != nil {
+		return 0, 0, err
+	}
+	inFlight, err := q.do("ZCARD", q.key("leases"))
+	if err != nil {
+		return 0, 0, err
+	}
+	p, _ := pending.(int64)
+	f, _ := inFlight.(int64)
+	return p, f, nil
+}
+
+func (q *RedisQueue) Close() error {
+	for {
+		select {
+		case c := <-q.conns:
+			c.Close()
+		default:
+			return nil
+		}
+	}
+}
+
+// respConn - Minimal RESP (Redis protokolü) bağlantısı: Komut gönderir, yanıtı okur
+// Yanıt tipleri: string (simple/bulk), int64, nil, []any; "-ERR" yanıtları respError olarak döner
+type respConn struct {
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+// respError - Redis'in döndürdüğü komut hatası (bağlantı sağlam, tekrar kullanılabilir)
+type respError string
+
+func (e respError) Error() string { return "redis: " + string(e) }
+
+func dialRESP(addr string) (*respConn, error) {
+	conn, err := net.DialTimeout("tcp", addr, 5*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	return &respConn{conn: conn, r: bufio.NewReader(conn)}, nil
+}
+
+// Do - Komutu bulk string dizisi olarak gönderir ve yanıtı okur
+func (c *respConn) Do(args ...string) (any, error) {
+	c.conn.SetDeadline(time.Now().Add(10 * time.Second))
+	var b strings.Builder
+	fmt.Fprintf(&b, "*%d\r\n", len(args))
+	for _, a := range args {
+		fmt.Fprintf(&b, "$%d\r\n%s\r\n", len(a), a)
+	}
+	if _, err := c.conn.Write([]byte(b.String())); err != nil {
+		return nil, err
+	}
+	return c.read()
+}
+
+func (c *respConn) read() (any, error) {
+	line, err := c.r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	if line == "" {
+		return nil, fmt.Errorf("boş RESP satırı")
+	}
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, respError(line[1:])
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil || n < 0 {
+			return nil, err // $-1: nil
+		}
+		buf := make([]byte, n+2)
+		if _, err := readFull(c.r, buf); err != nil {
+			return nil, err
+		}
+		return string(buf[:n]), nil
+	case '*':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil || n < 0 {
+			return nil, err // *-1: nil
+		}
+		values := make([]any, n)
+		for i := range values {
+			// Dizi içindeki hata elemanı da dizinin parçasıdır (ör: EXEC), okumaya devam edilir
+			v, err := c.read()
+			var redisErr respError
+			if err != nil && !errors.As(err, &redisErr) {
+				return nil, err
+			}
+			values[i] = v
+		}
+		return values, nil
+	}
+	return nil, fmt.Errorf("bilinmeyen RESP tipi: %q", line)
+}
+
+func readFull(r *bufio.Reader, buf []byte) (int, error) {
+	n := 0
+	for n < len(buf) {
+		m, err := r.Read(buf[n:])
+		n += m
+		if err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
+
+func (c *respConn) Close() error {
+	return c.conn.Close()
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"fmt"
+	"runtime/metrics"
+	"strings"
+	"time"
+
+	"benchkit"
+)
+
+// request_cost.go - Endpoint başına istek maliyeti: CPU süresi ve heap ayırmaları
+// "/cpu istek başına X ms CPU, /io istek başına Y µs CPU harcar" diyebilmek için. Go'da
+// goroutine başına CPU sayacı yoktur (goroutine thread'ler arasında gezer; LockOSThread ile
+// sabitlemek her /io isteğine bir OS thread'i bağlar ve ölçülen şeyi bozar), heap ayırmaları da
+// process geneli sayılır. Bu yüzden maliyet pencere pencere atfedilir:
+//   - Her pencerede (500ms) process'in CPU süresi (getrusage) ve heap ayırmaları
+//     (runtime/metrics /gc/heap/allocs) okunur
+//   - Pencerede yalnızca bir endpoint aktifse (işlenen ya da biten isteği olan tek endpoint),
+//     pencerenin tüm CPU'su ve ayırmaları o endpoint'e, bitirdiği istekler de paydaya yazılır
+//   - Birden fazla endpoint aktifse pencere "mixed" sayılır ve atlanır; boş pencereler "idle"
+//
+// Sonuç, istek sınıflarının ayrı ayrı yüklendiği ölçümlerde (loadgen'in her URL'yi sırayla
+// çalıştırması) doğrudur ve GC, HTTP ayrıştırma, zamanlayıcı gibi isteğin dolaylı
+// maliyetini de içerir. Eşzamanlı karışık yükte endpoint_cost_windows_total{result="mixed"}
+// artar ve istek başına değerler eksik pencerelerden hesaplanır. Asenkron işlerin (POST /job)
+// worker'da harcadığı CPU, o sırada aktif olan endpoint'e yazılır.
+//
+//	endpoint_cpu_seconds_total{endpoint}            Atfedilen CPU süresi
+//	endpoint_alloc_bytes_total{endpoint}            Atfedilen heap ayırması (byte)
+//	endpoint_cost_requests_total{endpoint}          Atfedilen pencerelerde biten istekler
+//	endpoint_cpu_seconds_per_request{endpoint}      CPU / istek (gauge)
+//	endpoint_alloc_bytes_per_request{endpoint}      Byte / istek (gauge)
+//	endpoint_cost_windows_total{result}             isolated, mixed, idle
+//
+// -json sonuçlarında aynı değerler perOp alanındadır.
+
+// costWindow - Atıf penceresi: Kısa pencere sıralı yüklerde daha az veri kaybeder, uzun
+// pencere getrusage çözünürlüğüne ve pencere sınırına taşan isteklere karşı daha az gürültülüdür
+const costWindow = 500 * time.Millisecond
+
+// costSample - Process genelindeki sayaçların anlık değeri
+type costSample struct {
+	cpu        time.Duration
+	allocBytes uint64
+	allocs     uint64
+}
+
+// endpointCost - Endpoint'e atfedilen toplam maliyet (HTTPMetrics kilidi altında)
+type endpointCost struct {
+	cpu        time.Duration
+	allocBytes uint64
+	allocs     uint64
+	requests   int64
+	lastCount  int64 // Önceki pencere sonunda biten istek sayısı
+}
+
+// costMeter - Pencere durumu
+type costMeter struct {
+	last    costSample
+	windows map[string]int64 // isolated, mixed, idle
+}
+
+// readCostSample - CPU süresi ve heap ayırma sayaçları
+func readCostSample() costSample {
+	samples := []metrics.Sample{{Name: "/gc/heap/allocs:bytes"}, {Name: "/gc/heap/allocs:objects"}}
+	metrics.Read(samples)
+	s := costSample{cpu: benchkit.CPUTime()}
+	if samples[0].Value.Kind() == metrics.KindUint64 {
+		s.allocBytes = samples[0].Value.Uint64()
+	}
+	if samples[1].Value.Kind() == metrics.KindUint64 {
+		s.allocs = samples[1].Value.Uint64()
+	}
+	return s
+}
+
+// StartCostAttribution - Endpoint başına maliyet atfını başlatır
+func (m *HTTPMetrics) StartCostAttribution() {
+	m.mu.Lock()
+	m.cost = &costMeter{last: readCostSample(), windows: map[string]int64{}}
+	m.mu.Unlock()
+	go func() {
+		ticker := time.NewTicker(costWindow)
+		defer ticker.Stop()
+		for range ticker.C {
+			m.attributeWindow(readCostSample())
+		}
+	}()
+}
+
+// attributeWindow - Biten pencerenin maliyetini tek aktif endpoint'e yazar
+func (m *HTTPMetrics) attributeWindow(now costSample) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	c := m.cost
+	last := c.last
+	c.last = now
+
+	var active []*endpointMetrics
+	for _, e := range m.endpoints {
+		if e.inFlight > 0 || e.count != e.cost.lastCount {
+			active = append(active, e)
+		}
+	}
+	switch len(active) {
+	case 0:
+		c.windows["idle"]++
+	case 1:
+		c.windows["isolated"]++
+		e := active[0]
+		e.cost.cpu += now.cpu - last.cpu
+		e.cost.allocBytes += now.allocBytes - last.allocBytes
+		e.cost.allocs += now.allocs - last.allocs
+		e.cost.requests += e.count - e.cost.lastCount
+	default:
+		c.windows["mixed"]++
+	}
+	for _, e := range m.endpoints {
+		e.cost.lastCount = e.count
+	}
+}
+
+// perOp - Atfedilen maliyetin istek başına karşılığı (atfedilen istek yoksa nil)
+func (c endpointCost) perOp() *benchkit.OpCost {
+	if c.requests == 0 {
+		return nil
+	}
+	n := float64(c.requests)
+	return &benchkit.OpCost{
+		Ops:        c.requests,
+		CPUMs:      benchkit.Millis(c.cpu) / n,
+		AllocBytes: float64(c.allocBytes) / n,
+		Allocs:     float64(c.allocs) / n,
+	}
+}
+
+// writeCostMetrics - endpoint_cpu_* ve endpoint_alloc_* metrikleri (m.mu altında çağrılır)
+func (m *HTTPMetrics) writeCostMetrics(b *strings.Builder, names []string) {
+	if m.cost == nil {
+		return
+	}
+	b.WriteString("# HELP endpoint_cpu_seconds_total Endpoint'in tek başına aktif olduğu pencerelerde harcanan CPU\n# TYPE endpoint_cpu_seconds_total counter\n")
+	for _, name := range names {
+		fmt.Fprintf(b, "endpoint_cpu_seconds_total{endpoint=%q} %g\n", name, m.endpoints[name].cost.cpu.Seconds())
+	}
+	b.WriteString("# HELP endpoint_alloc_bytes_total Aynı pencerelerde ayrılan heap\n# TYPE endpoint_alloc_bytes_total counter\n")
+	for _, name := range names {
+		fmt.Fprintf(b, "endpoint_alloc_bytes_total{endpoint=%q} %d\n", name, m.endpoints[name].cost.allocBytes)
+	}
+	b.WriteString("# HELP endpoint_cost_requests_total Aynı pencerelerde biten istekler\n# TYPE endpoint_cost_requests_total counter\n")
+	for _, name := range names {
+		fmt.Fprintf(b, "endpoint_cost_requests_total{endpoint=%q} %d\n", name, m.endpoints[name].cost.requests)
+	}
+	b.WriteString("# HELP endpoint_cpu_seconds_per_request İstek başına CPU\n# TYPE endpoint_cpu_seconds_per_request gauge\n")
+	for _, name := range names {
+		if c := m.endpoints[name].cost.perOp(); c != nil {
+			fmt.Fprintf(b, "endpoint_cpu_seconds_per_request{endpoint=%q} %g\n", name, c.CPUMs/1000)
+		}
+	}
+	b.WriteString("# HELP endpoint_alloc_bytes_per_request İstek başına heap ayırması\n# TYPE endpoint_alloc_bytes_per_request gauge\n")
+	for _, name := range names {
+		if c := m.endpoints[name].cost.perOp(); c != nil {
+			fmt.Fprintf(b, "endpoint_alloc_bytes_per_request{endpoint=%q} %g\n", name, c.AllocBytes)
+		}
+	}
+	b.WriteString("# HELP endpoint_cost_windows_total Atıf pencereleri\n# TYPE endpoint_cost_windows_total counter\n")
+	for _, result := range []string{"isolated", "mixed", "idle"} {
+		fmt.Fprintf(b, "endpoint_cost_windows_total{result=%q} %d\n", result, m.cost.windows[result])
+	}
+}
+
+// CostSummary - İstek başına maliyeti bilinen endpoint'ler için konsol satırları (kapanışta)
+func (m *HTTPMetrics) CostSummary() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.cost == nil {
+		return nil
+	}
+	var lines []string
+	for _, name := range sortedEndpoints(m.endpoints) {
+		if c := m.endpoints[name].cost.perOp(); c != nil {
+			lines = append(lines, fmt.Sprintf("%s: istek başına %v CPU, %.1f KB (%.0f ayırma), %d istekten",
+				name, time.Duration(c.CPUMs*float64(time.Millisecond)).Round(time.Microsecond), c.AllocBytes/1024, c.Allocs, c.Ops))
+		}
+	}
+	return lines
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"time"
+
+	"benchkit"
+)
+
+// results.go - Sunucu tarafı ölçümün ortak sonuç formatında kaydı (-json)
+// Kapanışta endpoint başına bir benchkit.Result JSON Lines dosyasına eklenir: loadgen-go'nun
+// istemci tarafı sonuçları ve mongo-perf-lab'ın kayıtlarıyla aynı şema (benchmark "server",
+// variant endpoint adı). /metrics'teki kaba Prometheus kovalarından farklı olarak gecikme
+// benchkit.Histogram'la tutulur; istemci ve sunucu yüzdelikleri aynı çözünürlükte karşılaştırılır.
+// İkisi arasındaki fark ağda, bağlantı kuyruğunda ve istemcide geçen süredir.
+//
+//	go run ./server-go -json results.jsonl
+
+// resultRecorder - Endpoint sonuçlarının ortak ayarları
+type resultRecorder struct {
+	started  time.Time
+	interval time.Duration // Zaman çizelgesi aralığı
+}
+
+// endpointResult - Tek endpoint'in sonuç ölçümü (HTTPMetrics kilidi altında güncellenir)
+type endpointResult struct {
+	latency  *benchkit.Histogram
+	timeline *benchkit.Timeline
+	ok       int64
+	failed   int64 // 2xx dışı yanıtlar
+}
+
+// RecordResults - Endpoint başına sonuç ölçümünü açar; Handle çağrılarından önce çağrılmalıdır
+func (m *HTTPMetrics) RecordResults(interval time.Duration) {
+	m.results = &resultRecorder{started: time.Now(), interval: interval}
+}
+
+func (r *resultRecorder) newEndpoint() *endpointResult {
+	return &endpointResult{latency: benchkit.NewHistogram(), timeline: benchkit.StartTimeline(r.interval)}
+}
+
+func (e *endpointResult) record(elapsed time.Duration, code int) {
+	ok := code >= 200 && code <= 299
+	e.latency.Record(elapsed)
+	e.timeline.Add(ok)
+	if ok {
+		e.ok++
+	} else {
+		e.failed++
+	}
+}
+
+// Results - İstek almış endpoint'lerin sonuçları (endpoint adına göre sıralı)
+// Zaman çizelgeleri durdurulur: Kapanışta bir kez çağrılır
+func (m *HTTPMetrics) Results(params map[string]string) []benchkit.Result {
+	if m.results == nil {
+		return nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var results []benchkit.Result
+	for _, name := range sortedEndpoints(m.endpoints) {
+		e := m.endpoints[name].result
+		timeline := e.timeline.Stop()
+		if e.ok+e.failed == 0 {
+			continue
+		}
+		r := benchkit.NewResult("io-vs-cpu-demo", "server", m.results.started, time.Since(m.results.started), e.ok)
+		r.Variant = name
+		r.Params = params
+		r.Latency = e.latency.Summary().Millis()
+		r.Histogram = benchkit.HistogramMs(e.latency)
+		r.Timeline = timeline
+		r.Errors = e.failed
+		r.PerOp = m.endpoints[name].cost.perOp()
+		results = append(results, r)
+	}
+	return results
+}
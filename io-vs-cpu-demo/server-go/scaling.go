@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// scaling.go - GOMAXPROCS ve paralellik ölçekleme deneyi (-scaling)
+// HTTP katmanı olmadan, cpuHeavyTask ve ioTask doğrudan N goroutine ile sürekli çalıştırılır.
+// Her GOMAXPROCS değeri ve worker sayısı (1..2×NumCPU) için saniyedeki iş sayısı ölçülür:
+//   - cpu: Hızlanma worker sayısı GOMAXPROCS'a ulaşınca durur; fazla worker yalnızca sıra bekler
+//   - io:  Hızlanma worker sayısıyla neredeyse doğrusaldır, GOMAXPROCS=1'de bile
+//
+// Verim = hızlanma / worker sayısı: %100 doğrusal ölçekleme, düşüş doygunluk demektir.
+
+// ScalingCell - Tek bir (GOMAXPROCS, worker) ölçümü
+type ScalingCell struct {
+	Procs   int
+	Workers int
+	Ops     int64
+	Elapsed time.Duration
+}
+
+// OpsPerSec - Saniyedeki iş
+func (c ScalingCell) OpsPerSec() float64 {
+	if c.Elapsed <= 0 {
+		return 0
+	}
+	return float64(c.Ops) / c.Elapsed.Seconds()
+}
+
+// scalingWorkers - 1, 2, 4, ... ve NumCPU, 2×NumCPU (tekrarsız, sıralı)
+func scalingWorkers() []int {
+	limit := 2 * runtime.NumCPU()
+	set := map[int]bool{runtime.NumCPU(): true, limit: true}
+	for n := 1; n < limit; n *= 2 {
+		set[n] = true
+	}
+	return sortedKeys(set)
+}
+
+// defaultScalingProcs - 1, NumCPU/2, NumCPU (tekrarsız)
+func defaultScalingProcs() []int {
+	return sortedKeys(map[int]bool{1: true, max(1, runtime.NumCPU()/2): true, runtime.NumCPU(): true})
+}
+
+func sortedKeys(set map[int]bool) []int {
+	keys := make([]int, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+	return keys
+}
+
+// parseProcs - "1,2,4" listesi; boşsa varsayılanlar
+func parseProcs(s string) ([]int, error) {
+	if s == "" {
+		return defaultScalingProcs(), nil
+	}
+	var procs []int
+	for _, part := range strings.Split(s, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("geçersiz GOMAXPROCS %q", part)
+		}
+		procs = append(procs, n)
+	}
+	return procs, nil
+}
+
+// measureScaling - task'ı workers goroutine ile window boyunca çalıştırır
+// Süre dolduktan sonra biten son işler de sayılır ve geçen süre ona göre ölçülür
+func measureScaling(procs, workers int, window time.Duration, task func()) ScalingCell {
+	var ops atomic.Int64
+	var stop atomic.Bool
+	var wg sync.WaitGroup
+	start := time.Now()
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for !stop.Load() {
+				task()
+				ops.Add(1)
+			}
+		}()
+	}
+	time.Sleep(window)
+	stop.Store(true)
+	wg.Wait()
+	return ScalingCell{Procs: procs, Workers: workers, Ops: ops.Load(), Elapsed: time.Since(start)}
+}
+
+// RunScaling - Deneyi çalıştırır ve tabloları yazar; GOMAXPROCS sonunda eski değerine döner
+func RunScaling(defaults *workloadDefaults, procs []int, window time.Duration) {
+	previous := runtime.GOMAXPROCS(0)
+	defer runtime.GOMAXPROCS(previous)
+
+	workloads := []struct {
+		name string
+		task func()
+	}{
+		{fmt.Sprintf("cpu (iterations=%d)", defaults.Iterations()), func() { cpuHeavyTask(context.Background(), defaults.Iterations()) }},
+		{fmt.Sprintf("io (delay=%v)", defaults.Delay()), func() { ioTask(context.Background(), defaults.Delay()) }},
+	}
+	workers := scalingWorkers()
+	fmt.Printf("📈 Ölçekleme deneyi: NumCPU=%d, GOMAXPROCS %v × worker %v, hücre başına %v\n",
+		runtime.NumCPU(), procs, workers, window)
+
+	for _, w := range workloads {
+		fmt.Printf("\n=== %s ===\n", w.name)
+		for _, p := range procs {
+			runtime.GOMAXPROCS(p)
+			fmt.Printf("\n  GOMAXPROCS=%d\n", p)
+			fmt.Printf("  %8s %12s %10s %8s\n", "worker", "iş/s", "hızlanma", "verim")
+			var base float64
+			for _, n := range workers {
+				cell := measureScaling(p, n, window, w.task)
+				if base == 0 {
+					base = cell.OpsPerSec()
+				}
+				speedup := 0.0
+				if base > 0 {
+					speedup = cell.OpsPerSec() / base
+				}
+				fmt.Printf("  %8d %12.1f %9.2fx %7.0f%%\n", n, cell.OpsPerSec(), speedup, speedup/float64(n)*100)
+			}
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// shutdown.go - SIGTERM/SIGINT'te bağlantıları boşaltarak kapanma
+// Çıplak ListenAndServe, process öldürüldüğünde yarım kalan istekleri ve arka plandaki işleri
+// sessizce kaybeder. Kapanış sırası:
+//  1. Yeni işler reddedilir (POST/GET /job → 503 + Connection: close)
+//  2. Queue modunda worker'lar yeni iş almayı bırakır (ellerindekini bitirir)
+//  3. http.Server.Shutdown: Dinleme kapanır, süren HTTP istekleri beklenir
+//  4. Kabul edilmiş asenkron işler (direct goroutine'ler, pool kuyruğu) beklenir
+//
+// -shutdown-timeout dolarsa kalan bağlantılar kapatılır. Rapor: Boşaltılan (bitirilen) ve
+// düşürülen (yarım kalan) iş sayısı; queue modunda hiç başlamamış işler arka uçta kalır.
+
+// drainState - Kapanış başladı mı (handler'lar okur)
+type drainState struct {
+	draining atomic.Bool
+}
+
+// rejectWhileDraining - Kapanış sırasında yeni işi 503 ile reddeder
+// Connection: close, istemcinin bu bağlantıyı tekrar kullanmamasını sağlar
+func (d *drainState) rejectWhileDraining(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if d.draining.Load() {
+			w.Header().Set("Connection", "close")
+			w.Header().Set("Retry-After", "1")
+			http.Error(w, "sunucu kapanıyor, yeni iş kabul edilmiyor", http.StatusServiceUnavailable)
+			return
+		}
+		h(w, r)
+	}
+}
+
+// DrainReport - Kapanış sonucu
+type DrainReport struct {
+	Jobs            int           // Kapanış başladığında bitmemiş asenkron işler
+	Drained         int           // Kapanış sırasında tamamlananlar
+	Dropped         int           // Süre dolduğunda hâlâ bitmemiş olanlar (kayıp)
+	LeftInQueue     int           // Queue modunda hiç alınmamış işler (arka uçta kaldı)
+	Requests        int64         // Kapanış başladığında süren HTTP istekleri
+	DroppedRequests int64         // Süre dolduğunda kesilen HTTP istekleri
+	Elapsed         time.Duration // Kapanış süresi
+}
+
+// String - Tek satırlık özet
+func (r DrainReport) String() string {
+	s := fmt.Sprintf("iş: %d boşaltıldı, %d düşürüldü", r.Drained, r.Dropped)
+	if r.LeftInQueue > 0 {
+		s += fmt.Sprintf(", %d kuyrukta kaldı", r.LeftInQueue)
+	}
+	return s + fmt.Sprintf(" · istek: %d tamamlandı, %d kesildi · %v",
+		r.Requests-r.DroppedRequests, r.DroppedRequests, r.Elapsed.Round(time.Millisecond))
+}
+
+// drain - Sunucuyu ve işleri timeout içinde boşaltır
+// runner nil değilse (queue modu) durable, kuyrukta kalan işlerin kaybolup kaybolmadığını belirler
+func drain(server *http.Server, state *drainState, store *JobStore, metrics *HTTPMetrics, runner *QueueRunner, durable bool, timeout time.Duration) DrainReport {
+	start := time.Now()
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	state.draining.Store(true)
+	report := DrainReport{Requests: metrics.InFlight()}
+
+	// Queue modunda yalnızca çalışan işler beklenir: Alınmamış işler bu process'te çalışmayacak
+	var waiting, queued []string
+	if runner != nil {
+		runner.Stop()
+		waiting = store.Unfinished(JobRunning)
+		queued = store.Unfinished(JobQueued)
+	} else {
+		waiting = store.Unfinished(JobQueued, JobRunning)
+	}
+	report.Jobs = len(waiting)
+	fmt.Printf("🛑 Kapanıyor: %d iş ve %d istek bekleniyor (en fazla %v)\n", report.Jobs, report.Requests, timeout)
+
+	if err := server.Shutdown(ctx); err != nil {
+		report.DroppedRequests = metrics.InFlight()
+		server.Close()
+	}
+	if runner != nil {
+		runner.Wait(ctx)
+	}
+	for store.CountUnfinished(waiting) > 0 && ctx.Err() == nil {
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	report.Dropped = store.CountUnfinished(waiting)
+	report.Drained = report.Jobs - report.Dropped
+	if left := store.CountUnfinished(queued); durable {
+		report.LeftInQueue = left
+	} else {
+		report.Dropped += left // Bellek kuyruğu process'le birlikte kaybolur
+	}
+	report.Elapsed = time.Since(start)
+	return report
+}
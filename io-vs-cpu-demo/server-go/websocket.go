@@ -0,0 +1,120 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"iovscpu/internal/ws"
+)
+
+// websocket.go - GET /ws: WebSocket echo
+// İstek/yanıt HTTP'nin ötesi: Bağlantı başına bir goroutine uzun süre yaşar ve çoğunlukla
+// mesaj bekler (I/O-bound). Binlerce açık bağlantının maliyeti bellek ve goroutine sayısıdır,
+// CPU değil. loadgen-go ws:// hedefinde her worker için bir bağlantı açar ve mesaj gidiş-dönüş
+// süresini ölçer.
+//
+//	GET /ws               Gelen her mesaj aynen geri gönderilir
+//	GET /ws?delay=10ms    Her mesajdan önce bekleme (mesaj başına I/O benzetimi)
+//
+// Bağlantı devralındıktan (hijack) sonra http.Server.Shutdown onu beklemez; kapanışta açık
+// bağlantılara close frame'i gönderilir (bkz. WSHub.CloseAll).
+
+// WSHub - Açık WebSocket bağlantıları ve metrikleri
+type WSHub struct {
+	mu       sync.Mutex
+	conns    map[*ws.Conn]struct{}
+	peak     int
+	opened   int64
+	messages int64
+	closing  bool
+}
+
+// NewWSHub - Boş hub
+func NewWSHub() *WSHub {
+	return &WSHub{conns: map[*ws.Conn]struct{}{}}
+}
+
+func (h *WSHub) add(conn *ws.Conn) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closing {
+		return false
+	}
+	h.conns[conn] = struct{}{}
+	h.opened++
+	h.peak = max(h.peak, len(h.conns))
+	return true
+}
+
+func (h *WSHub) remove(conn *ws.Conn) {
+	h.mu.Lock()
+	delete(h.conns, conn)
+	h.mu.Unlock()
+}
+
+// CloseAll - Açık bağlantılara close frame'i gönderip kapatır (http.Server.RegisterOnShutdown)
+func (h *WSHub) CloseAll() {
+	h.mu.Lock()
+	h.closing = true
+	conns := make([]*ws.Conn, 0, len(h.conns))
+	for conn := range h.conns {
+		conns = append(conns, conn)
+	}
+	h.mu.Unlock()
+	for _, conn := range conns {
+		conn.CloseGracefully()
+	}
+}
+
+// ServeHTTP - GET /ws
+func (h *WSHub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	delay, err := delayParam(r, "delay", 0)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	conn, err := ws.Accept(w, r)
+	if err != nil {
+		return // Yanıt Accept'te yazıldı
+	}
+	if !h.add(conn) {
+		conn.CloseGracefully()
+		return
+	}
+	defer h.remove(conn)
+	defer conn.Close()
+
+	for {
+		opcode, message, err := conn.ReadMessage()
+		if err != nil {
+			if !errors.Is(err, ws.ErrClosed) && !errors.Is(err, io.EOF) {
+				conn.CloseGracefully()
+			}
+			return
+		}
+		if delay > 0 {
+			time.Sleep(delay)
+		}
+		if err := conn.WriteMessage(opcode, message); err != nil {
+			return
+		}
+		h.mu.Lock()
+		h.messages++
+		h.mu.Unlock()
+	}
+}
+
+// WritePrometheus - /metrics'e eklenen WebSocket metrikleri
+func (h *WSHub) WritePrometheus(b *strings.Builder) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	fmt.Fprintf(b, "# HELP ws_connections Açık WebSocket bağlantıları\n# TYPE ws_connections gauge\nws_connections %d\n", len(h.conns))
+	fmt.Fprintf(b, "# HELP ws_connections_max Aynı anda açık en fazla WebSocket bağlantısı\n# TYPE ws_connections_max gauge\nws_connections_max %d\n", h.peak)
+	fmt.Fprintf(b, "# HELP ws_connections_opened_total Açılan WebSocket bağlantıları\n# TYPE ws_connections_opened_total counter\nws_connections_opened_total %d\n", h.opened)
+	fmt.Fprintf(b, "# HELP ws_messages_total Geri gönderilen mesajlar\n# TYPE ws_messages_total counter\nws_messages_total %d\n", h.messages)
+}
@@ -4,8 +4,12 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"os"
+	"strconv"
+	"strings"
 	"time"
 
+	"benchkit"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
@@ -15,12 +19,67 @@ import (
 // Bu yapı, bir MongoDB sorgusunun ne kadar sürede çalıştığını,
 // kaç kayıt okunduğunu, ne kadar bellek kullanıldığını ve
 // MongoDB'nin kendi execution stats'ını saklar
+// JSON alan adları Logger'ın JSON özetinde (…_results.json) kullanılır
 type QueryMetrics struct {
-	Duration       time.Duration // Toplam sorgu süresi (Go tarafında ölçülen)
-	RecordsRead    int           // Okunan toplam kayıt sayısı
-	MemoryUsed     int64         // Kullanılan bellek miktarı (bytes)
-	ExecutionStats *ExecutionStats // MongoDB'nin kendi execution istatistikleri
-	QueryPlan      *QueryPlan     // MongoDB query plan bilgisi
+	Duration       time.Duration   `json:"durationNs"`               // Toplam sorgu süresi (Go tarafında ölçülen)
+	RecordsRead    int             `json:"recordsRead"`              // Okunan toplam kayıt sayısı
+	RecordsWritten int             `json:"recordsWritten,omitempty"` // Yazılan toplam kayıt sayısı (yazma serisi: write_bad, write_v1, write_v2)
+	Variant        string          `json:"variant,omitempty"`        // Aynı script'in parametre kombinasyonu (ör: write_v1 "b1000"); metrik kaydına geçer
+	MemoryUsed     int64           `json:"memoryUsedBytes"`          // Heap'in ölçüm başına göre en yüksek büyümesi (bytes, bkz. benchkit.MemUsage)
+	MaxHeap        int64           `json:"maxHeapBytes,omitempty"`   // Ölçüm boyunca en yüksek canlı heap (HeapAlloc)
+	TotalAllocated int64           `json:"allocatedBytes,omitempty"` // Ölçüm boyunca ayrılan toplam bayt (GC'nin topladıkları dahil)
+	NumGC          uint32          `json:"numGC,omitempty"`          // Ölçüm sırasında tamamlanan GC döngüsü
+	GCPause        time.Duration   `json:"gcPauseNs,omitempty"`      // GC'nin toplam stop-the-world süresi
+	ExecutionStats *ExecutionStats `json:"executionStats,omitempty"` // MongoDB'nin kendi execution istatistikleri
+	QueryPlan      *QueryPlan      `json:"queryPlan,omitempty"`      // MongoDB query plan bilgisi
+	Phases         *CursorPhases   `json:"phases,omitempty"`         // İlk batch / getMore / decode süre dağılımı (nil = ölçülmedi)
+	Iterations     *IterationStats `json:"iterations,omitempty"`     // -iterations ile tekrarlanan turların dağılımı (nil = tek tur ve ısınma yok, bkz. iterations.go)
+	ChunkTuning    *ChunkTuning    `json:"chunkTuning,omitempty"`    // Paralel okumada seçilen chunk boyutu ve kalibrasyon turları (bkz. chunktune.go)
+}
+
+// WriteRate - Yazılan doküman/sn (yazma yoksa 0)
+func (m QueryMetrics) WriteRate() float64 {
+	if m.Duration <= 0 {
+		return 0
+	}
+	return float64(m.RecordsWritten) / m.Duration.Seconds()
+}
+
+// SetMemory - benchkit.StartMemProbe ölçümünü metriklere yazar
+func (m *QueryMetrics) SetMemory(mem benchkit.MemUsage) {
+	m.MemoryUsed = mem.PeakGrowth
+	m.MaxHeap = int64(mem.PeakHeap)
+	m.TotalAllocated = int64(mem.Allocated)
+	m.NumGC = mem.NumGC
+	m.GCPause = mem.GCPause
+}
+
+// IterationStats - Ölçülen turların süre ve bellek dağılımı (ısınma turları ayrı tutulur)
+// Duration/MemoryUsed son turun değeridir; metrik kaydına medyanlar yazılır
+type IterationStats struct {
+	Iterations int                   `json:"iterations"`         // Ölçülen tur sayısı
+	Warmup     int                   `json:"warmup"`             // Atılan ısınma turu sayısı
+	WarmupMs   []float64             `json:"warmupMs,omitempty"` // Isınma turlarının süreleri (dağılıma dahil değil)
+	DurationMs benchkit.Distribution `json:"durationMs"`
+	MemoryMB   benchkit.Distribution `json:"memoryMB"`
+}
+
+// ChunkTuning - Paralel okumada chunk boyutu kalibrasyonunun sonucu (bkz. chunktune.go)
+type ChunkTuning struct {
+	Workers   int          `json:"workers"`
+	ProbeDocs int64        `json:"probeDocs,omitempty"` // Aday başına okunan en fazla doküman (0 = kalibrasyon yapılmadı)
+	Chosen    int64        `json:"chosen"`
+	Fixed     bool         `json:"fixed,omitempty"` // Chunk boyutu parametreyle verildi
+	Probes    []ChunkProbe `json:"probes,omitempty"`
+}
+
+// ChunkProbe - Tek bir adayın kalibrasyon turu
+type ChunkProbe struct {
+	ChunkSize  int64   `json:"chunkSize"`
+	Docs       int64   `json:"docs"`
+	DurationMs float64 `json:"durationMs"`
+	DocsPerSec float64 `json:"docsPerSec"`
+	Error      string  `json:"error,omitempty"`
 }
 
 // ExecutionStats - MongoDB explain komutundan gelen execution istatistikleri
@@ -84,11 +143,21 @@ func ExplainQuery(col *mongo.Collection, filter bson.M, opts ...*options.FindOpt
 			// Skip: İlk N kaydı atla (pagination için)
 			explainCmd = append(explainCmd, bson.E{Key: "skip", Value: *opts[0].Skip})
 		}
+		if opts[0].Sort != nil {
+			// Sort: Sıralama - index sırası mı yoksa bellekte SORT mu kullanılacağını belirler
+			explainCmd = append(explainCmd, bson.E{Key: "sort", Value: opts[0].Sort})
+		}
+		if opts[0].Hint != nil {
+			// Hint: Belirli bir index'i (veya {$natural: 1} ile collection scan'i) zorla
+			explainCmd = append(explainCmd, bson.E{Key: "hint", Value: opts[0].Hint})
+		}
 	}
 	
 	// MongoDB'ye explain komutunu gönder
 	// verbosity: "executionStats" - Detaylı execution istatistikleri iste
-	var result bson.M
+	// bson.M yerine map[string]interface{}: İç içe dokümanlar da map[string]interface{} olarak
+	// decode edilir (bson.M'de primitive.M olur ve .(map[string]interface{}) kontrolleri başarısız olur)
+	var result map[string]interface{}
 	err := col.Database().RunCommand(ctx, bson.D{
 		{Key: "explain", Value: explainCmd},           // Explain edilecek komut
 		{Key: "verbosity", Value: "executionStats"},   // Detay seviyesi: executionStats = en detaylı
@@ -110,6 +179,9 @@ func ExplainQuery(col *mongo.Collection, filter bson.M, opts ...*options.FindOpt
 //   - version: Test edilen versiyon adı (read_bad, read_v1 vb.)
 //   - logger: Logger instance'ı (nil ise sadece ekrana yazar)
 func PrintExplainResults(explainResult map[string]interface{}, version string, logger *Logger) {
+	// Anti-pattern kuralları: Bulgular çalıştırma sonunda PrintAntiPatterns ile listelenir
+	CheckExplain(explainResult, version)
+
 	// Print fonksiyonlarını seç - logger varsa onu kullan, yoksa fmt kullan
 	if logger != nil {
 		logger.Printf("\n=== EXPLAIN SONUÇLARI - %s ===\n", version)
@@ -178,10 +250,10 @@ func PrintExplainResults(explainResult map[string]interface{}, version string, l
 				// Bu durumda index kullanılmıyor demektir
 				if stage == "COLLSCAN" {
 					if logger != nil {
-						logger.Println("  ⚠️  UYARI: Collection scan tespit edildi - INDEX GEREKLİ!")
+						logger.Println("  ⚠️  UYARI: Collection scan (COLLSCAN) tespit edildi - INDEX GEREKLİ!")
 						logger.Println("     → Tüm collection taranıyor, bu çok yavaş olabilir")
 					} else {
-						fmt.Println("  ⚠️  UYARI: Collection scan tespit edildi - INDEX GEREKLİ!")
+						fmt.Println("  ⚠️  UYARI: Collection scan (COLLSCAN) tespit edildi - INDEX GEREKLİ!")
 						fmt.Println("     → Tüm collection taranıyor, bu çok yavaş olabilir")
 					}
 				} else if stage == "IXSCAN" {
@@ -225,6 +297,72 @@ func PrintExplainResults(explainResult map[string]interface{}, version string, l
 	}
 }
 
+// PlanSummary - Explain çıktısının karşılaştırma için özetlenmiş hali
+type PlanSummary struct {
+	Stages          []string // Kazanan planın stage'leri, kökten yaprağa (ör: LIMIT → FETCH → IXSCAN)
+	IndexName       string   // Kullanılan index (IXSCAN yoksa boş)
+	BlockingSort    bool     // Planda SORT stage'i var mı (sonuçlar bellekte sıralanıyor)
+	DocsExamined    int64
+	KeysExamined    int64
+	NReturned       int64
+	ExecutionTimeMs int64
+}
+
+// StageChain - Stage'leri okunabilir zincir olarak döndürür
+func (p PlanSummary) StageChain() string {
+	return strings.Join(p.Stages, " → ")
+}
+
+// SummarizeExplain - ExplainQuery sonucundan plan özetini çıkarır
+// Stage ağacı inputStage/inputStages üzerinden gezilir. MongoDB 7+ slot-based engine (SBE)
+// kullandığında plan winningPlan.queryPlan altındadır, o da desteklenir.
+func SummarizeExplain(explainResult map[string]interface{}) PlanSummary {
+	var summary PlanSummary
+
+	if execStats, ok := explainResult["executionStats"].(map[string]interface{}); ok {
+		summary.DocsExamined = toInt64(execStats["totalDocsExamined"])
+		summary.KeysExamined = toInt64(execStats["totalKeysExamined"])
+		summary.NReturned = toInt64(execStats["nReturned"])
+		summary.ExecutionTimeMs = toInt64(execStats["executionTimeMillis"])
+	}
+
+	planner, _ := explainResult["queryPlanner"].(map[string]interface{})
+	plan, _ := planner["winningPlan"].(map[string]interface{})
+	if queryPlan, ok := plan["queryPlan"].(map[string]interface{}); ok {
+		plan = queryPlan
+	}
+
+	var walk func(stage map[string]interface{})
+	walk = func(stage map[string]interface{}) {
+		if stage == nil {
+			return
+		}
+		name, _ := stage["stage"].(string)
+		summary.Stages = append(summary.Stages, name)
+		switch name {
+		case "SORT":
+			summary.BlockingSort = true
+		case "IXSCAN":
+			summary.IndexName, _ = stage["indexName"].(string)
+		}
+
+		if input, ok := stage["inputStage"].(map[string]interface{}); ok {
+			walk(input)
+		}
+		// Diziler primitive.A (bson.A) olarak decode edilir
+		if inputs, ok := stage["inputStages"].(bson.A); ok {
+			for _, in := range inputs {
+				if m, ok := in.(map[string]interface{}); ok {
+					walk(m)
+				}
+			}
+		}
+	}
+	walk(plan)
+
+	return summary
+}
+
 // PrintMetrics - Performans metriklerini yazdırır
 // Bu fonksiyon, bir sorgunun performans metriklerini okunabilir formatta gösterir
 // Hem Go tarafında ölçülen süreleri hem de MongoDB'nin kendi istatistiklerini içerir
@@ -234,16 +372,38 @@ func PrintExplainResults(explainResult map[string]interface{}, version string, l
 //   - version: Test edilen versiyon adı
 //   - logger: Logger instance'ı (nil ise sadece ekrana yazar)
 func PrintMetrics(metrics QueryMetrics, version string, logger *Logger) {
+	// perflab altında çalışıyorsak metrikleri makine-okunabilir olarak da kaydet
+	AppendMetricsRecord(metrics, version, logger)
+	if logger != nil {
+		logger.WriteMetrics(version, metrics)
+	}
+
 	if logger != nil {
 		logger.Printf("\n=== PERFORMANS METRİKLERİ - %s ===\n", version)
 		logger.Printf("⏱️  Toplam Süre (Go): %v\n", metrics.Duration)
-		logger.Printf("📦 Okunan Kayıt Sayısı: %d\n", metrics.RecordsRead)
+		if metrics.RecordsWritten > 0 {
+			logger.Printf("✍️  Yazılan Kayıt Sayısı: %d (%.0f doküman/sn)\n", metrics.RecordsWritten, metrics.WriteRate())
+		} else {
+			logger.Printf("📦 Okunan Kayıt Sayısı: %d\n", metrics.RecordsRead)
+		}
 		logger.Printf("💾 Kullanılan Bellek: %.2f MB\n", float64(metrics.MemoryUsed)/(1024*1024))
+		if metrics.TotalAllocated > 0 {
+			logger.Printf("🧮 Heap: zirve %.2f MB, toplam ayırma %.2f MB, %d GC (%v duraklama)\n",
+				float64(metrics.MaxHeap)/(1024*1024), float64(metrics.TotalAllocated)/(1024*1024), metrics.NumGC, metrics.GCPause)
+		}
 	} else {
 		fmt.Printf("\n=== PERFORMANS METRİKLERİ - %s ===\n", version)
 		fmt.Printf("⏱️  Toplam Süre (Go): %v\n", metrics.Duration)
-		fmt.Printf("📦 Okunan Kayıt Sayısı: %d\n", metrics.RecordsRead)
+		if metrics.RecordsWritten > 0 {
+			fmt.Printf("✍️  Yazılan Kayıt Sayısı: %d (%.0f doküman/sn)\n", metrics.RecordsWritten, metrics.WriteRate())
+		} else {
+			fmt.Printf("📦 Okunan Kayıt Sayısı: %d\n", metrics.RecordsRead)
+		}
 		fmt.Printf("💾 Kullanılan Bellek: %.2f MB\n", float64(metrics.MemoryUsed)/(1024*1024))
+		if metrics.TotalAllocated > 0 {
+			fmt.Printf("🧮 Heap: zirve %.2f MB, toplam ayırma %.2f MB, %d GC (%v duraklama)\n",
+				float64(metrics.MaxHeap)/(1024*1024), float64(metrics.TotalAllocated)/(1024*1024), metrics.NumGC, metrics.GCPause)
+		}
 	}
 	
 	// MongoDB'nin kendi execution istatistikleri varsa göster
@@ -282,6 +442,26 @@ func PrintMetrics(metrics QueryMetrics, version string, logger *Logger) {
 			}
 		}
 	}
+
+	// Aşama dağılımı: Süre sunucuda mı, network'te mi, decode'da mı geçiyor?
+	if metrics.Phases != nil {
+		printCursorPhases(metrics, logger)
+	}
+
+	// -iterations: Tek turun değeri yerine turların dağılımı
+	if metrics.Iterations != nil {
+		printIterationStats(metrics.Iterations, logger)
+	}
+
+	// PERFLAB_COST=atlas-m30 gibi bir model seçildiyse maliyet/enerji tahmini
+	printCostEstimate(metrics, logger)
+
+	// Explain'lerden toplanan anti-pattern bulguları, önem sırasıyla
+	PrintAntiPatterns(logger)
+
+	// Çalıştırma sırasında HandleError ile bildirilen hatalar
+	PrintErrorSummary(logger)
+
 	if logger != nil {
 		logger.Println("=" + string(make([]byte, 50)) + "\n")
 	} else {
@@ -289,3 +469,348 @@ func PrintMetrics(metrics QueryMetrics, version string, logger *Logger) {
 	}
 }
 
+// noisyCV - Bu varyasyon katsayısının (%) üstündeki ölçümler için uyarı verilir
+const noisyCV = 10
+
+// printIterationStats - Tekrarlanan turların süre ve bellek dağılımını yazdırır
+func printIterationStats(stats *IterationStats, logger *Logger) {
+	printf := fmt.Printf
+	if logger != nil {
+		printf = logger.Printf
+	}
+	printf("\n📐 Tur İstatistikleri (%d ölçülen tur, %d ısınma turu atıldı; yukarıdaki değerler son tur):\n", stats.Iterations, stats.Warmup)
+	if len(stats.WarmupMs) > 0 {
+		printf("  🔥 Isınma turları (ms, ölçüme dahil değil):")
+		for _, ms := range stats.WarmupMs {
+			printf(" %.2f", ms)
+		}
+		printf("\n")
+		if median := stats.DurationMs.Median; median > 0 && stats.WarmupMs[0] > median*1.5 {
+			printf("  💡 İlk ısınma turu ölçülen medyandan %.1fx yavaş: Soğuk cache/bağlantı maliyeti ölçüme karışmadı\n", stats.WarmupMs[0]/median)
+		}
+	}
+	if stats.Iterations < 2 {
+		return
+	}
+	printf("  %-12s %10s %10s %10s %10s %10s %10s %8s\n", "metrik", "ortalama", "medyan", "p95", "min", "max", "std", "cv %")
+	for _, row := range []struct {
+		name string
+		d    benchkit.Distribution
+	}{{"süre (ms)", stats.DurationMs}, {"bellek (MB)", stats.MemoryMB}} {
+		printf("  %-12s %10.2f %10.2f %10.2f %10.2f %10.2f %10.2f %8.1f\n",
+			row.name, row.d.Mean, row.d.Median, row.d.P95, row.d.Min, row.d.Max, row.d.StdDev, row.d.CV())
+	}
+	if stats.DurationMs.CV() > noisyCV {
+		printf("  ⚠️  Süre turlar arasında %%%.0f'ten fazla değişiyor: Karşılaştırmalarda medyanı kullanın, tur sayısını artırın\n", float64(noisyCV))
+	}
+}
+
+// printCursorPhases - Toplam okuma süresini aşamalara bölerek yazdırır
+// Darboğaz tahmini:
+//   - Sunucu: explain'in executionTimeMillis değeri (MongoDB içinde geçen süre)
+//   - Network + bekleme: komut süreleri (ilk batch + getMore) - sunucu süresi
+//   - Decode: client tarafında BSON → Go dönüşümü
+func printCursorPhases(metrics QueryMetrics, logger *Logger) {
+	printf := fmt.Printf
+	if logger != nil {
+		printf = logger.Printf
+	}
+	phases := metrics.Phases
+	getMoreTotal := phases.GetMoreTotal()
+	commandTotal := phases.FirstBatch + getMoreTotal
+
+	printf("\n🧩 Aşama Dağılımı (command monitoring):\n")
+	printf("  🥇 İlk batch (find/aggregate): %v (%d cursor)\n", phases.FirstBatch, phases.FirstBatchCount)
+	printf("  🔁 getMore: %d adet, toplam %v\n", len(phases.GetMores), getMoreTotal)
+	if len(phases.GetMores) > 0 {
+		printf("     p50=%v p95=%v p99=%v max=%v\n",
+			Percentile(phases.GetMores, 50),
+			Percentile(phases.GetMores, 95),
+			Percentile(phases.GetMores, 99),
+			Percentile(phases.GetMores, 100))
+	}
+	if phases.Decode > 0 {
+		printf("  🧮 Client decode: %v\n", phases.Decode)
+	}
+	if phases.ReplyBytes > 0 {
+		printf("  📡 Transfer edilen veri: %.2f MB\n", float64(phases.ReplyBytes)/(1024*1024))
+	}
+	if phases.ClientCPU > 0 {
+		printf("  🔥 Client CPU: %.3f CPU-sn\n", phases.ClientCPU.Seconds())
+	}
+
+	// Paralel okumada (birden fazla cursor) süreler toplandığı için duvar saatini aşabilir,
+	// bu durumda yüzdelik dağılım anlamsız olur
+	if phases.FirstBatchCount > 1 {
+		printf("  ℹ️  %d paralel cursor - süreler worker'lar üzerinden toplamdır\n", phases.FirstBatchCount)
+		return
+	}
+
+	total := metrics.Duration
+	if total <= 0 {
+		return
+	}
+	var server time.Duration
+	if metrics.ExecutionStats != nil {
+		server = time.Duration(metrics.ExecutionStats.ExecutionTimeMillis) * time.Millisecond
+	}
+	if server > commandTotal {
+		server = commandTotal // explain ayrı çalıştığı için küçük sapmalar olabilir
+	}
+	network := commandTotal - server
+	other := total - commandTotal - phases.Decode
+	if other < 0 {
+		other = 0
+	}
+
+	share := func(d time.Duration) float64 { return float64(d) / float64(total) * 100 }
+	printf("  📊 Toplam sürenin dağılımı:\n")
+	printf("     Sunucu (explain):     %6.1f%%\n", share(server))
+	printf("     Network + bekleme:    %6.1f%%\n", share(network))
+	printf("     Client decode:        %6.1f%%\n", share(phases.Decode))
+	printf("     Diğer (client işleme): %5.1f%%\n", share(other))
+
+	switch {
+	case server >= network && server >= phases.Decode:
+		printf("  🎯 Darboğaz: SUNUCU - index / sorgu planı optimizasyonuna bakın\n")
+	case network >= phases.Decode:
+		printf("  🎯 Darboğaz: NETWORK - projection, batch size veya sıkıştırma deneyin\n")
+	default:
+		printf("  🎯 Darboğaz: DECODE - daha küçük struct'lar veya projection deneyin\n")
+	}
+}
+
+// serverTime - Maliyet hesabı için sunucunun meşgul olduğu süre
+// explain'in executionTimeMillis'i varsa o (sadece sunucu), yoksa komut süreleri toplamı (network dahil, üst sınır)
+func serverTime(metrics QueryMetrics) time.Duration {
+	if metrics.ExecutionStats != nil && metrics.ExecutionStats.ExecutionTimeMillis > 0 {
+		return time.Duration(metrics.ExecutionStats.ExecutionTimeMillis) * time.Millisecond
+	}
+	if metrics.Phases != nil {
+		return metrics.Phases.FirstBatch + metrics.Phases.GetMoreTotal()
+	}
+	return 0
+}
+
+// printCostEstimate - PERFLAB_COST ortam değişkeniyle seçilen modelle maliyet tahmini yazdırır
+// Değişken tanımlı değilse hiçbir şey yapmaz (maliyet bölümü opsiyoneldir)
+func printCostEstimate(metrics QueryMetrics, logger *Logger) {
+	preset := os.Getenv("PERFLAB_COST")
+	if preset == "" || metrics.Phases == nil {
+		return
+	}
+	printf := fmt.Printf
+	if logger != nil {
+		printf = logger.Printf
+	}
+	model, err := benchkit.ResolveCostModel(preset, benchkit.CostModel{})
+	if err != nil {
+		printf("⚠️  PERFLAB_COST: %v\n", err)
+		return
+	}
+	model.Estimate(benchkit.CostInputs{
+		Ops:        int64(metrics.RecordsRead),
+		Duration:   metrics.Duration,
+		ClientCPU:  metrics.Phases.ClientCPU,
+		ServerTime: serverTime(metrics),
+		Bytes:      metrics.Phases.ReplyBytes,
+	}).WriteText(printf)
+}
+
+// Percentile - Sıralı gecikme listesinden p. yüzdelik değeri döndürür
+// Parametreler:
+//   - sorted: Küçükten büyüğe sıralı gecikmeler
+//   - p: Yüzdelik (0-100 arası, örn: 99 = p99)
+//
+// Tanım benchkit ile ortaktır: Diğer lab'ların p99'u ile aynı şekilde hesaplanır
+func Percentile(sorted []time.Duration, p float64) time.Duration {
+	return benchkit.Percentile(sorted, p)
+}
+
+// MetricsRecord - Bir benchmark çalıştırmasının makine-okunabilir özeti
+// perflab, benchmark script'lerini ayrı process olarak çalıştırır. Script'ler
+// PERFLAB_METRICS_FILE ortam değişkeni tanımlıysa metriklerini bu dosyaya
+// JSON satırı (JSON Lines) olarak ekler, perflab da assertion'ları bu satırlardan kontrol eder.
+// Aynı kayıt PERFLAB_SINKS ile seçilen diğer hedeflere de (mongo, http, s3) gider
+type MetricsRecord struct {
+	Benchmark    string   `json:"benchmark"`
+	Variant      string   `json:"variant,omitempty"`     // Aynı benchmark'ın parametre kombinasyonu (ör: insert_bench "insertmany/w1/8")
+	Environment  string   `json:"environment,omitempty"` // Ölçümün yapıldığı ortam (bkz. config.go)
+	Repetition   int      `json:"repetition"`
+	DurationMs   float64  `json:"durationMs"`
+	RecordsRead  int      `json:"recordsRead"`
+	MemoryMB     float64  `json:"memoryMB"`
+	DocsExamined int64    `json:"docsExamined"`
+	KeysExamined int64    `json:"keysExamined"`
+	NReturned    int64    `json:"nReturned"`
+	Efficiency   float64  `json:"efficiency"`               // nReturned / docsExamined * 100
+	IngestRate   float64  `json:"ingestRate,omitempty"`     // Ölçüm sırasındaki eşzamanlı yazma hızı (doküman/sn, perflab doldurur)
+	DocsPerSec   float64  `json:"docsPerSec,omitempty"`     // Yazma benchmark'larında ölçülen yazma hızı
+	OutputBytes  int64    `json:"outputBytes,omitempty"`    // Dışa aktarma benchmark'larında yazılan dosya boyutu (ör: read_export)
+	Findings     []string `json:"findings,omitempty"`       // Anti-pattern bulgu kodları (bkz. antipattern.go)
+	Targeting    float64  `json:"targetingRatio,omitempty"` // Çalıştırma boyunca incelenen/dönen doküman oranı (bkz. targeting.go)
+	Errors       int      `json:"errors"`                   // HandleError ile bildirilen hata sayısı
+
+	Histogram []benchkit.HistogramBucket `json:"histogram,omitempty"` // İşlem gecikmesi dağılımı (işlem başına ölçen script'lerde, ör: insert_bench)
+	P50Ms     float64                    `json:"p50Ms,omitempty"`     // İşlem gecikmesi yüzdelikleri (Histogram ile aynı ölçüm)
+	P99Ms     float64                    `json:"p99Ms,omitempty"`
+
+	Iterations  *IterationStats `json:"iterations,omitempty"`  // -iterations ile ölçüldüyse turların dağılımı (DurationMs/MemoryMB medyandır)
+	ChunkTuning *ChunkTuning    `json:"chunkTuning,omitempty"` // Paralel okumada chunk boyutu kalibrasyonu (ör: read_v4)
+
+	// İstek bütçesiyle çalışan workload'lar (ör: workload_profile -budget)
+	BudgetExceeded map[string]int `json:"budgetExceeded,omitempty"` // Deadline'ın aşıldığı aşama → işlem sayısı (bkz. budget.go)
+
+	// Eşzamanlı güncelleme benchmark'ları (ör: optimistic)
+	Conflicts   int64 `json:"conflicts,omitempty"`   // Versiyon uyuşmadığı için tekrarlanan güncelleme denemeleri
+	LostUpdates int64 `json:"lostUpdates,omitempty"` // Beklenen ile saklanan değer arasındaki fark (last-write-wins)
+
+	// Tekrar tespiti benchmark'ları (ör: dedup)
+	Duplicates int64   `json:"duplicates,omitempty"` // Tespit edilen fazla (tekrar) doküman
+	Accuracy   float64 `json:"accuracy,omitempty"`   // Tespitin generator'ın işaretlediği tekrar sayısına göre doğruluğu (%)
+
+	// Maliyet modeli girdileri (bkz. benchkit/cost.go)
+	CPUSeconds    float64 `json:"cpuSeconds,omitempty"`    // Client CPU-saniye
+	ServerSeconds float64 `json:"serverSeconds,omitempty"` // Sunucu süresi (explain, yoksa komut süreleri toplamı)
+	BytesReceived int64   `json:"bytesReceived,omitempty"` // Sunucudan gelen veri
+
+	// Bellek muhasebesi (bkz. benchkit.MemUsage; memoryMB heap'in zirve büyümesidir)
+	MaxHeapMB   float64 `json:"maxHeapMB,omitempty"`
+	AllocatedMB float64 `json:"allocatedMB,omitempty"` // Ölçüm boyunca ayrılan toplam bellek
+	NumGC       uint32  `json:"numGC,omitempty"`
+	GCPauseMs   float64 `json:"gcPauseMs,omitempty"`
+
+	Host *benchkit.HostInfo `json:"host,omitempty"` // Ölçümün yapıldığı makine
+}
+
+// Name - Tablolarda gösterilen ad: benchmark, varyantı varsa "benchmark/varyant"
+func (r MetricsRecord) Name() string {
+	if r.Variant == "" {
+		return r.Benchmark
+	}
+	return r.Benchmark + "/" + r.Variant
+}
+
+// Value - Manifest assertion'larında kullanılan metrik adına göre değeri döndürür
+func (r MetricsRecord) Value(metric string) (float64, bool) {
+	switch metric {
+	case "duration_ms":
+		return r.DurationMs, true
+	case "records":
+		return float64(r.RecordsRead), true
+	case "memory_mb":
+		return r.MemoryMB, true
+	case "max_heap_mb":
+		return r.MaxHeapMB, true
+	case "allocated_mb":
+		return r.AllocatedMB, true
+	case "num_gc":
+		return float64(r.NumGC), true
+	case "gc_pause_ms":
+		return r.GCPauseMs, true
+	case "docs_examined":
+		return float64(r.DocsExamined), true
+	case "keys_examined":
+		return float64(r.KeysExamined), true
+	case "n_returned":
+		return float64(r.NReturned), true
+	case "cpu_seconds":
+		return r.CPUSeconds, true
+	case "bytes_received":
+		return float64(r.BytesReceived), true
+	case "efficiency":
+		return r.Efficiency, true
+	case "ingest_rate":
+		return r.IngestRate, true
+	case "docs_per_sec":
+		return r.DocsPerSec, true
+	case "output_mb":
+		return float64(r.OutputBytes) / (1024 * 1024), true
+	case "p50_ms":
+		return r.P50Ms, true
+	case "p99_ms":
+		return r.P99Ms, true
+	case "conflicts":
+		return float64(r.Conflicts), true
+	case "lost_updates":
+		return float64(r.LostUpdates), true
+	case "duplicates":
+		return float64(r.Duplicates), true
+	case "accuracy":
+		return r.Accuracy, true
+	case "findings":
+		return float64(len(r.Findings)), true
+	case "targeting_ratio":
+		return r.Targeting, true
+	case "errors":
+		return float64(r.Errors), true
+	}
+	return 0, false
+}
+
+// newMetricsRecord - Tüm benchmark'larda ortak alanları (tekrar, hata sayısı, bulgular, ortam, host) doldurulmuş kayıt
+func newMetricsRecord(benchmark string) MetricsRecord {
+	host := benchkit.CollectHostInfo()
+	record := MetricsRecord{
+		Benchmark: benchmark,
+		Errors:    ErrorCount(),
+		Findings:  FindingCodes(),
+		Host:      &host,
+	}
+	record.Repetition, _ = strconv.Atoi(os.Getenv("PERFLAB_REPETITION"))
+	if env, err := SelectEnvironment(); err == nil {
+		record.Environment = env.Name
+	}
+	return record
+}
+
+// AppendMetricsRecord - Metrikleri logger'ın kayıt hedeflerine gönderir
+// logger nil ise sadece PERFLAB_METRICS_FILE'a (tanımlıysa) JSON satırı olarak ekler
+func AppendMetricsRecord(metrics QueryMetrics, version string, logger *Logger) {
+	record := newMetricsRecord(version)
+	record.DurationMs = float64(metrics.Duration) / float64(time.Millisecond)
+	record.Variant = metrics.Variant
+	record.RecordsRead = metrics.RecordsRead
+	record.MemoryMB = float64(metrics.MemoryUsed) / (1024 * 1024)
+	record.MaxHeapMB = float64(metrics.MaxHeap) / (1024 * 1024)
+	record.AllocatedMB = float64(metrics.TotalAllocated) / (1024 * 1024)
+	record.NumGC = metrics.NumGC
+	record.GCPauseMs = benchkit.Millis(metrics.GCPause)
+	if stats := metrics.Iterations; stats != nil {
+		// Tek turun değeri yerine medyan: Aykırı bir tur assertion'ları ve karşılaştırmaları bozmasın
+		record.DurationMs = stats.DurationMs.Median
+		record.MemoryMB = stats.MemoryMB.Median
+		record.Iterations = stats
+	}
+	record.ChunkTuning = metrics.ChunkTuning
+	if metrics.RecordsWritten > 0 {
+		// Yazma benchmark'larında records yazılan doküman sayısıdır (insert_bench gibi)
+		record.RecordsRead = metrics.RecordsWritten
+		if record.DurationMs > 0 {
+			record.DocsPerSec = float64(metrics.RecordsWritten) / (record.DurationMs / 1000)
+		}
+	}
+	if phases := metrics.Phases; phases != nil {
+		record.CPUSeconds = phases.ClientCPU.Seconds()
+		record.BytesReceived = phases.ReplyBytes
+		record.ServerSeconds = serverTime(metrics).Seconds()
+	}
+	if stats := metrics.ExecutionStats; stats != nil {
+		record.DocsExamined = stats.TotalDocsExamined
+		record.KeysExamined = stats.TotalKeysExamined
+		record.NReturned = stats.NReturned
+		if stats.TotalDocsExamined > 0 {
+			record.Efficiency = float64(stats.NReturned) / float64(stats.TotalDocsExamined) * 100
+		}
+	}
+
+	if logger != nil {
+		logger.WriteRecord(record)
+		return
+	}
+	if path := os.Getenv("PERFLAB_METRICS_FILE"); path != "" {
+		if err := (&jsonFileSink{path: path}).WriteRecord(record); err != nil {
+			fmt.Printf("⚠️  Metrik dosyası açılamadı: %v\n", err)
+		}
+	}
+}
@@ -0,0 +1,341 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// antipattern.go - Otomatik anti-pattern dedektörü (kural motoru)
+// Explain çıktısından ve sorgunun kendisinden (filter, skip, pipeline) bilinen performans
+// hatalarını bulur. Her bulgu kodludur (AP001...) ve dokümantasyon bağlantısı taşır.
+// Bulgular çalıştırma boyunca toplanır ve sonunda önem sırasına göre listelenir:
+// Tek tek "⚠️ UYARI" satırlarını okumak yerine "bu çalıştırmada ne yanlış" sorusunun cevabı.
+//
+// Yeni kural eklemek için antiPatternRules listesine bir eleman eklemek yeterlidir.
+
+// FindingSeverity - Bulgunun önemi (büyük olan önce listelenir)
+type FindingSeverity int
+
+const (
+	SeverityLow FindingSeverity = iota + 1
+	SeverityMedium
+	SeverityHigh
+)
+
+func (s FindingSeverity) String() string {
+	switch s {
+	case SeverityHigh:
+		return "🔴 yüksek"
+	case SeverityMedium:
+		return "🟠 orta"
+	default:
+		return "🟡 düşük"
+	}
+}
+
+// Finding - Tek bir anti-pattern bulgusu
+type Finding struct {
+	Code     string
+	Severity FindingSeverity
+	Title    string
+	Source   string // Bulgunun geldiği sorgu (ör: "read_bad", "read_topn: hint yok")
+	Detail   string // Somut sayılarla açıklama
+	DocURL   string
+}
+
+// AntiPatternInput - Kuralların değerlendirdiği sorgu bilgisi
+// Explain varsa Plan ondan doldurulur; filter/skip/pipeline explain'in "command" alanından
+// (MongoDB 5.0+) veya çağıran taraftan gelir
+type AntiPatternInput struct {
+	Source       string
+	Plan         *PlanSummary // nil = explain yok, plan kuralları atlanır
+	Filter       bson.M
+	Skip         int64
+	Pipeline     []bson.M // Aggregation stage'leri (find sorgularında boş)
+	AllowDiskUse bool
+}
+
+// antiPatternRule - Kural: check bulgu varsa açıklamayı döndürür
+type antiPatternRule struct {
+	code     string
+	severity FindingSeverity
+	title    string
+	docURL   string
+	check    func(in AntiPatternInput) (string, bool)
+}
+
+// Eşikler
+const (
+	selectiveRatio     = 0.10  // Dönen/incelenen bu orandan azsa filtre seçicidir
+	lowEfficiencyRatio = 0.50  // PrintMetrics'teki "düşük verimlilik" uyarısıyla aynı eşik
+	largeSkip          = 10000 // Bu değerden büyük skip'ler sayfalamada sorun olur
+)
+
+var antiPatternRules = []antiPatternRule{
+	{
+		code:     "AP001",
+		severity: SeverityHigh,
+		title:    "Seçici filtrede collection scan",
+		docURL:   "https://www.mongodb.com/docs/manual/tutorial/analyze-query-plan/",
+		check: func(in AntiPatternInput) (string, bool) {
+			p := in.Plan
+			if p == nil || !hasStage(p, "COLLSCAN") || len(in.Filter) == 0 || p.DocsExamined == 0 {
+				return "", false
+			}
+			ratio := float64(p.NReturned) / float64(p.DocsExamined)
+			if ratio >= selectiveRatio {
+				return "", false
+			}
+			return fmt.Sprintf("%d doküman tarandı, %d döndü (%%%.2f) - filtre alanlarına index: %s",
+				p.DocsExamined, p.NReturned, ratio*100, filterFields(in.Filter)), true
+		},
+	},
+	{
+		code:     "AP002",
+		severity: SeverityHigh,
+		title:    "Index'siz sıralama (bellekte SORT)",
+		docURL:   "https://www.mongodb.com/docs/manual/tutorial/sort-results-with-indexes/",
+		check: func(in AntiPatternInput) (string, bool) {
+			if in.Plan == nil || !in.Plan.BlockingSort {
+				return "", false
+			}
+			return fmt.Sprintf("plan: %s - sonuçlar bellekte sıralanıyor (100 MB sınırı), sort alanını index'e ekleyin",
+				in.Plan.StageChain()), true
+		},
+	},
+	{
+		code:     "AP003",
+		severity: SeverityMedium,
+		title:    "Düşük verimlilik (incelenen ≫ dönen)",
+		docURL:   "https://www.mongodb.com/docs/atlas/reference/alert-resolutions/query-targeting/",
+		check: func(in AntiPatternInput) (string, bool) {
+			p := in.Plan
+			if p == nil || p.NReturned == 0 {
+				return "", false
+			}
+			examined := max(p.DocsExamined, p.KeysExamined)
+			ratio := float64(p.NReturned) / float64(examined)
+			// Seçici COLLSCAN zaten AP001 - aynı sorunu iki kez raporlama
+			if ratio >= lowEfficiencyRatio || (hasStage(p, "COLLSCAN") && ratio < selectiveRatio && len(in.Filter) > 0) {
+				return "", false
+			}
+			return fmt.Sprintf("dönen başına %.1f doküman/key incelendi (verim %%%.1f)",
+				float64(examined)/float64(p.NReturned), ratio*100), true
+		},
+	},
+	{
+		code:     "AP004",
+		severity: SeverityMedium,
+		title:    "allowDiskUse olmadan bloklayan aggregation stage'i",
+		docURL:   "https://www.mongodb.com/docs/manual/core/aggregation-pipeline-limits/",
+		check: func(in AntiPatternInput) (string, bool) {
+			if in.AllowDiskUse {
+				return "", false
+			}
+			var blocking []string
+			for _, stage := range in.Pipeline {
+				for name := range stage {
+					switch name {
+					case "$group", "$bucketAuto", "$sortByCount": // $bucketAuto tüm girdiyi sıralar
+						blocking = append(blocking, name)
+					}
+				}
+			}
+			if len(blocking) == 0 {
+				return "", false
+			}
+			return fmt.Sprintf("%s tüm girdiyi bellekte tutar, 100 MB'ı aşınca hata verir - allowDiskUse: true veya önce $match ile daraltın",
+				strings.Join(blocking, ", ")), true
+		},
+	},
+	{
+		code:     "AP005",
+		severity: SeverityLow,
+		title:    "Büyük skip ile sayfalama",
+		docURL:   "https://www.mongodb.com/docs/manual/reference/method/cursor.skip/",
+		check: func(in AntiPatternInput) (string, bool) {
+			skip := in.Skip
+			for _, stage := range in.Pipeline {
+				if v, ok := stage["$skip"]; ok {
+					skip = max(skip, toInt64(v))
+				}
+			}
+			if skip <= largeSkip {
+				return "", false
+			}
+			return fmt.Sprintf("skip %d: atlanan her kayıt yine de taranır - son görülen anahtarla (range) sayfalama kullanın", skip), true
+		},
+	},
+}
+
+// findingCollector - Çalıştırma boyunca görülen bulgular (kod+kaynak başına bir kez)
+type findingCollector struct {
+	mu       sync.Mutex
+	seen     map[string]bool
+	findings []Finding
+}
+
+var runFindings = &findingCollector{seen: map[string]bool{}}
+
+// CheckAntiPatterns - Kuralları çalıştırır, yeni bulguları kaydeder ve döndürür
+// Goroutine'lerden eşzamanlı çağrılabilir
+func CheckAntiPatterns(in AntiPatternInput) []Finding {
+	var found []Finding
+	for _, rule := range antiPatternRules {
+		detail, ok := rule.check(in)
+		if !ok {
+			continue
+		}
+		found = append(found, Finding{
+			Code:     rule.code,
+			Severity: rule.severity,
+			Title:    rule.title,
+			Source:   in.Source,
+			Detail:   detail,
+			DocURL:   rule.docURL,
+		})
+	}
+
+	runFindings.mu.Lock()
+	for _, f := range found {
+		key := f.Code + "|" + f.Source
+		if !runFindings.seen[key] {
+			runFindings.seen[key] = true
+			runFindings.findings = append(runFindings.findings, f)
+		}
+	}
+	runFindings.mu.Unlock()
+	return found
+}
+
+// CheckExplain - Explain çıktısından girdiyi çıkarıp kuralları çalıştırır
+// Find ve aggregate explain'leri desteklenir; aggregate'te plan ilk stage'in $cursor'ından okunur
+func CheckExplain(explainResult map[string]interface{}, source string) []Finding {
+	in := AntiPatternInput{Source: source}
+
+	planned := explainResult
+	if _, ok := explainResult["queryPlanner"]; !ok {
+		if stages, ok := explainResult["stages"].(bson.A); ok && len(stages) > 0 {
+			if first, ok := stages[0].(map[string]interface{}); ok {
+				planned, _ = first["$cursor"].(map[string]interface{})
+			}
+		}
+	}
+	if planned != nil {
+		plan := SummarizeExplain(planned)
+		if len(plan.Stages) > 0 {
+			in.Plan = &plan
+		}
+	}
+
+	// "command": Explain edilen komutun kendisi (MongoDB 5.0+)
+	if command, ok := explainResult["command"].(map[string]interface{}); ok {
+		if filter, ok := command["filter"].(map[string]interface{}); ok {
+			in.Filter = filter
+		}
+		in.Skip = toInt64(command["skip"])
+		in.AllowDiskUse, _ = command["allowDiskUse"].(bool)
+		if pipeline, ok := command["pipeline"].(bson.A); ok {
+			for _, stage := range pipeline {
+				if m, ok := stage.(map[string]interface{}); ok {
+					in.Pipeline = append(in.Pipeline, m)
+				}
+			}
+		}
+	}
+	// Aggregate'te filtre ilk $match'tir
+	if in.Filter == nil && len(in.Pipeline) > 0 {
+		if match, ok := in.Pipeline[0]["$match"].(map[string]interface{}); ok {
+			in.Filter = match
+		}
+	}
+	return CheckAntiPatterns(in)
+}
+
+// PipelineStages - mongo.Pipeline'ı kuralların okuduğu stage listesine çevirir
+func PipelineStages(pipeline mongo.Pipeline) []bson.M {
+	stages := make([]bson.M, 0, len(pipeline))
+	for _, d := range pipeline {
+		stage := bson.M{}
+		for _, e := range d {
+			stage[e.Key] = e.Value
+		}
+		stages = append(stages, stage)
+	}
+	return stages
+}
+
+// FindingCodes - Kaydedilen bulguların kodları (tekrarsız, sıralı) - metrik kaydı için
+func FindingCodes() []string {
+	runFindings.mu.Lock()
+	defer runFindings.mu.Unlock()
+
+	seen := map[string]bool{}
+	var codes []string
+	for _, f := range runFindings.findings {
+		if !seen[f.Code] {
+			seen[f.Code] = true
+			codes = append(codes, f.Code)
+		}
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// PrintAntiPatterns - Bulguları önem sırasına göre yazdırır (bulgu yoksa hiçbir şey yazmaz)
+// logger nil ise sadece ekrana yazar
+func PrintAntiPatterns(logger *Logger) {
+	runFindings.mu.Lock()
+	findings := append([]Finding(nil), runFindings.findings...)
+	runFindings.mu.Unlock()
+
+	if len(findings) == 0 {
+		return
+	}
+	printf := fmt.Printf
+	if logger != nil {
+		printf = logger.Printf
+	}
+
+	// Önce önem, sonra kod; aynı koddakiler görülme sırasında kalır
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].Severity != findings[j].Severity {
+			return findings[i].Severity > findings[j].Severity
+		}
+		return findings[i].Code < findings[j].Code
+	})
+
+	printf("\n=== ANTI-PATTERN'LER (%d bulgu) ===\n", len(findings))
+	for i, f := range findings {
+		printf("  %d. [%s] %s - %s\n", i+1, f.Code, f.Severity, f.Title)
+		printf("     📍 %s: %s\n", f.Source, f.Detail)
+		printf("     📖 %s\n", f.DocURL)
+	}
+}
+
+// hasStage - Planda verilen stage var mı
+func hasStage(p *PlanSummary, name string) bool {
+	for _, s := range p.Stages {
+		if s == name {
+			return true
+		}
+	}
+	return false
+}
+
+// filterFields - Filtredeki alan adları (operatörler hariç), index önerisi için
+func filterFields(filter map[string]interface{}) string {
+	var fields []string
+	for k := range filter {
+		if !strings.HasPrefix(k, "$") {
+			fields = append(fields, k)
+		}
+	}
+	sort.Strings(fields)
+	return strings.Join(fields, ", ")
+}
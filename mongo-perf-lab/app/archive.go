@@ -0,0 +1,367 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"math/rand"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"benchkit"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// archive.go - Toplu arşivleme işi: Eski siparişleri arşiv koleksiyonuna taşıma
+// Her yöntem için orders'ın ilk -docs dokümanı archive_orders'a kopyalanır ve createdAt'i
+// -older-than'dan eski olanlar archive_orders_old'a taşınır:
+//
+//	merge: aggregate [$match eski, $merge → arşiv] tek komut + deleteMany batch'leri
+//	batch: find eski _id'ler (limit B) → insertMany arşive → deleteMany {_id: $in}, bitene kadar
+//
+// Ölçülenler:
+//   - İşin toplam süresi (kopyalama + silme) ve taşınan doküman/sn
+//   - Eşzamanlı okumalara etkisi: Kalan (yeni) siparişlere _id ile point read yapan okuyucuların
+//     iş öncesi (-baseline süresince) ve iş sırasındaki gecikmesi
+//   - Geri kazanılan alan: Silme sonrası WiredTiger dosyayı küçültmez, boşalan alan
+//     freeStorageSize olarak yeniden kullanıma ayrılır; -compact ile dosya küçültülür ve
+//     validate ile koleksiyonun tutarlılığı kontrol edilir
+//
+// Not: orders'ın createdAt alanı Date olmalı (generator -date-format date, varsayılan).
+//
+// KULLANIM:
+//   go run main.go config.go analyzer.go antipattern.go errors.go logger.go samples.go sink.go dataset.go archive.go
+//   go run main.go config.go analyzer.go antipattern.go errors.go logger.go samples.go sink.go dataset.go archive.go -docs 500000 -older-than 360h -methods batch -compact
+
+// archiveResult - Bir yöntemin sonucu
+type archiveResult struct {
+	method       string
+	moved        int64
+	copyTime     time.Duration
+	deleteTime   time.Duration
+	baseline     *benchkit.Histogram // Okuma gecikmesi: İş öncesi
+	during       *benchkit.Histogram // Okuma gecikmesi: İş sırasında
+	before       *StorageStats       // Kaynak koleksiyon: İş öncesi
+	after        *StorageStats       // Silme sonrası
+	compacted    *StorageStats       // compact sonrası (-compact)
+	compactTime  time.Duration
+	validRecords int64
+	valid        bool
+}
+
+func (r archiveResult) duration() time.Duration {
+	return r.copyTime + r.deleteTime
+}
+
+func main() {
+	docs := flag.Int64("docs", 200000, "orders'tan kopyalanan doküman sayısı (her yöntem aynı kopyadan başlar)")
+	olderThan := flag.Duration("older-than", 30*24*time.Hour, "Bu süreden eski siparişler arşivlenir (createdAt)")
+	batch := flag.Int("batch", 1000, "batch yöntemi ve silme işlemleri için batch boyutu")
+	methodList := flag.String("methods", "merge,batch", "Karşılaştırılacak yöntemler (merge, batch)")
+	readers := flag.Int("readers", 4, "Eşzamanlı point read yapan okuyucu sayısı (0 = okuma yükü yok)")
+	baseline := flag.Duration("baseline", 5*time.Second, "İş başlamadan önce okuma gecikmesinin ölçüldüğü süre")
+	compact := flag.Bool("compact", false, "Silme sonrası compact çalıştır (koleksiyonu kilitleyebilir, kaynağı yorar)")
+	flag.Parse()
+
+	var methods []string
+	for _, m := range strings.Split(*methodList, ",") {
+		switch m = strings.TrimSpace(m); m {
+		case "merge", "batch":
+			methods = append(methods, m)
+		case "":
+		default:
+			fmt.Printf("❌ Bilinmeyen yöntem %q (merge, batch)\n", m)
+			return
+		}
+	}
+	if len(methods) == 0 || *docs <= 0 || *batch <= 0 || *readers < 0 || *olderThan <= 0 {
+		fmt.Println("❌ -methods boş olamaz; -docs, -batch ve -older-than pozitif olmalı")
+		return
+	}
+
+	logger, err := NewLogger("archive_results.txt")
+	if err != nil {
+		fmt.Printf("Logger oluşturulamadı: %v\n", err)
+		return
+	}
+	defer CloseLogger(logger)
+
+	logger.WriteHeader("archive - Toplu Arşivleme İşi ($merge vs insert+delete)")
+
+	orders := GetMongo()
+	db := orders.Database()
+	ctx := context.Background()
+	source := db.Collection("archive_orders")
+	archive := db.Collection("archive_orders_old")
+	cutoff := time.Now().Add(-*olderThan)
+	logger.Printf("📋 %d doküman, createdAt < %s arşivlenir, batch %d, %d okuyucu, yöntemler %v\n",
+		*docs, cutoff.Format(time.RFC3339), *batch, *readers, methods)
+
+	var results []archiveResult
+	for _, method := range methods {
+		logger.Printf("\n▶️  %s\n", method)
+		r, ok := runArchive(ctx, orders, source, archive, method, cutoff, *docs, *batch, *readers, *baseline, *compact, logger)
+		if !ok {
+			continue
+		}
+		results = append(results, r)
+	}
+	if len(results) == 0 {
+		PrintErrorSummary(logger)
+		return
+	}
+
+	logger.Println("\n=== KARŞILAŞTIRMA ===")
+	logger.Printf("  %-8s %10s %12s %12s %12s %12s %12s %12s %12s\n",
+		"yöntem", "taşınan", "kopyalama", "silme", "toplam", "doküman/sn", "okuma p50", "okuma p99", "p99 x")
+	for _, r := range results {
+		base, during := r.baseline.Summary(), r.during.Summary()
+		ratio := "-"
+		if base.P99 > 0 {
+			ratio = fmt.Sprintf("%.2fx", float64(during.P99)/float64(base.P99))
+		}
+		logger.Printf("  %-8s %10d %12v %12v %12v %12.0f %12v %12v %12s\n",
+			r.method, r.moved, r.copyTime.Round(time.Millisecond), r.deleteTime.Round(time.Millisecond),
+			r.duration().Round(time.Millisecond), float64(r.moved)/r.duration().Seconds(),
+			during.P50.Round(time.Microsecond), during.P99.Round(time.Microsecond), ratio)
+	}
+
+	logger.Println("\n💾 Kaynak koleksiyonun alanı (MB):")
+	logger.Printf("  %-8s %12s %12s %12s %12s %12s\n", "yöntem", "önce", "silme sonrası", "boş (free)", "compact sonrası", "index")
+	for _, r := range results {
+		compacted := "-"
+		if r.compacted != nil {
+			compacted = fmt.Sprintf("%.1f", mb(r.compacted.StorageSize))
+		}
+		logger.Printf("  %-8s %12.1f %12.1f %12.1f %12s %12.1f\n", r.method,
+			mb(r.before.StorageSize), mb(r.after.StorageSize), mb(r.after.FreeStorage), compacted, mb(r.after.TotalIndexSize))
+		if r.compacted != nil {
+			status := "✅"
+			if !r.valid {
+				status = "❌"
+			}
+			logger.Printf("  %s validate: %d kayıt, compact %v\n", status, r.validRecords, r.compactTime.Round(time.Millisecond))
+		}
+	}
+
+	logger.Println("\n💡 $merge kopyalamayı tek komutta sunucuda yapar (veri client'a gelmez); silme her iki yöntemde de")
+	logger.Println("   index bakımı ve oplog yazar. Okuma p99'u artıyorsa batch'leri küçültüp aralarına bekleme koyun.")
+	logger.Println("💡 Silinen alan dosyadan geri verilmez: Sonraki yazmalar freeStorageSize'ı kullanır; diski geri almak için compact.")
+
+	for _, r := range results {
+		record := newMetricsRecord("archive")
+		record.Variant = r.method
+		record.DurationMs = benchkit.Millis(r.duration())
+		record.RecordsRead = int(r.moved)
+		record.DocsPerSec = float64(r.moved) / r.duration().Seconds()
+		if r.during.Count() > 0 {
+			record.Histogram = benchkit.HistogramMs(r.during)
+			record.P50Ms = benchkit.Millis(r.during.Summary().P50)
+			record.P99Ms = benchkit.Millis(r.during.Summary().P99)
+		}
+		logger.WriteRecord(record)
+	}
+
+	PrintErrorSummary(logger)
+
+	logger.Println("\n✅ Test tamamlandı! Sonuçlar 'archive_results.txt' dosyasına kaydedildi.")
+}
+
+// runArchive - Kaynağı hazırlar, okuyucuları başlatır ve yöntemi çalıştırır
+func runArchive(ctx context.Context, orders, source, archive *mongo.Collection, method string, cutoff time.Time,
+	docs int64, batch, readers int, baseline time.Duration, compact bool, logger *Logger) (archiveResult, bool) {
+	r := archiveResult{method: method, baseline: benchkit.NewHistogram(), during: benchkit.NewHistogram()}
+
+	// Her yöntem aynı kopyadan başlar: orders'ın ilk docs dokümanı ($out kaynağı baştan yazar)
+	for _, col := range []*mongo.Collection{source, archive} {
+		if HandleError(logger, "drop "+col.Name(), col.Drop(ctx)) {
+			return r, false
+		}
+	}
+	cursor, err := orders.Aggregate(ctx, bson.A{
+		bson.M{"$sort": bson.M{"_id": 1}},
+		bson.M{"$limit": docs},
+		bson.M{"$out": source.Name()},
+	})
+	if HandleError(logger, "$out", err) {
+		return r, false
+	}
+	cursor.Close(ctx)
+	if _, err := source.Indexes().CreateOne(ctx, mongo.IndexModel{Keys: bson.D{{Key: "createdAt", Value: 1}}}); HandleError(logger, "index", err) {
+		return r, false
+	}
+	old := bson.M{"createdAt": bson.M{"$lt": cutoff}}
+	total, err := source.CountDocuments(ctx, bson.M{})
+	if HandleError(logger, "count", err) {
+		return r, false
+	}
+	expected, err := source.CountDocuments(ctx, old)
+	if HandleError(logger, "count", err) {
+		return r, false
+	}
+	if r.before, err = CollectStorageStats(ctx, source); HandleError(logger, "stats", err) {
+		return r, false
+	}
+	logger.Printf("  📦 %d doküman kopyalandı, %d tanesi arşivlenecek (%%%.1f)\n", total, expected, float64(expected)/float64(max(total, 1))*100)
+
+	// Okuyucular kalan (yeni) dokümanları okur: Arşivlenen dokümanlar okuma sonucunu değiştirmesin
+	ids, err := sampleIDs(ctx, source, bson.M{"createdAt": bson.M{"$gte": cutoff}}, 10000)
+	if HandleError(logger, "sample", err) {
+		return r, false
+	}
+	var phase atomic.Int32 // 0: baseline, 1: iş, 2: dur
+	var wg sync.WaitGroup
+	hists := make([][2]*benchkit.Histogram, readers)
+	samples := logger.LatencySamples()
+	phases := [2]string{"read/baseline/" + method, "read/during/" + method}
+	if len(ids) == 0 {
+		readers = 0
+	}
+	for i := 0; i < readers; i++ {
+		hists[i] = [2]*benchkit.Histogram{benchkit.NewHistogram(), benchkit.NewHistogram()}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			rng := rand.New(rand.NewSource(int64(i)))
+			for p := phase.Load(); p < 2; p = phase.Load() {
+				start := time.Now()
+				err := source.FindOne(ctx, bson.M{"_id": ids[rng.Intn(len(ids))]}).Err()
+				elapsed := time.Since(start)
+				hists[i][p].Record(elapsed)
+				samples.Record(phases[p], i, start, elapsed, err == nil)
+				HandleError(logger, "read", err)
+			}
+		}(i)
+	}
+	if readers > 0 {
+		time.Sleep(baseline)
+	}
+	phase.Store(1)
+
+	// İş
+	start := time.Now()
+	switch method {
+	case "merge":
+		cursor, err := source.Aggregate(ctx, bson.A{
+			bson.M{"$match": old},
+			bson.M{"$merge": bson.M{"into": archive.Name(), "whenMatched": "keepExisting", "whenNotMatched": "insert"}},
+		})
+		if !HandleError(logger, "$merge", err) {
+			cursor.Close(ctx)
+		}
+		r.copyTime = time.Since(start)
+		// Tek deleteMany tüm eski dokümanları tek işlemde siler ve uzun süre yazma kilidi/oplog baskısı yaratır:
+		// Silme, batch yöntemiyle aynı boyutta _id batch'leriyle yapılır
+		deleteStart := time.Now()
+		for {
+			batchIDs, err := sampleIDs(ctx, source, old, batch)
+			if HandleError(logger, "find", err) || len(batchIDs) == 0 {
+				break
+			}
+			res, err := source.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": batchIDs}})
+			if HandleError(logger, "delete", err) {
+				break
+			}
+			r.moved += res.DeletedCount
+		}
+		r.deleteTime = time.Since(deleteStart)
+	case "batch":
+		findOpts := options.Find().SetSort(bson.M{"createdAt": 1}).SetLimit(int64(batch))
+		for {
+			copyStart := time.Now()
+			cursor, err := source.Find(ctx, old, findOpts)
+			if HandleError(logger, "find", err) {
+				break
+			}
+			var page []bson.Raw
+			if err := cursor.All(ctx, &page); HandleError(logger, "find", err) || len(page) == 0 {
+				break
+			}
+			docs := make([]interface{}, len(page))
+			batchIDs := make([]interface{}, len(page))
+			for i, d := range page {
+				docs[i] = d
+				batchIDs[i] = d.Lookup("_id")
+			}
+			// Yarıda kesilip yeniden başlatılan iş aynı dokümanları tekrar yazabilir: Duplicate key atlanır
+			_, err = archive.InsertMany(ctx, docs, options.InsertMany().SetOrdered(false))
+			if err != nil && !mongo.IsDuplicateKeyError(err) && HandleError(logger, "insert", err) {
+				break
+			}
+			r.copyTime += time.Since(copyStart)
+
+			deleteStart := time.Now()
+			res, err := source.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": batchIDs}})
+			r.deleteTime += time.Since(deleteStart)
+			if HandleError(logger, "delete", err) {
+				break
+			}
+			r.moved += res.DeletedCount
+		}
+	}
+	phase.Store(2)
+	wg.Wait()
+	for _, h := range hists {
+		if h[0] != nil {
+			r.baseline.Merge(h[0])
+			r.during.Merge(h[1])
+		}
+	}
+
+	archived, err := archive.CountDocuments(ctx, bson.M{})
+	if !HandleError(logger, "count", err) && (archived != expected || r.moved != expected) {
+		logger.Printf("  ⚠️  Beklenen %d, arşivde %d, silinen %d\n", expected, archived, r.moved)
+	}
+	logger.Printf("  ⏱️  Kopyalama %v, silme %v, %d doküman taşındı (%.0f doküman/sn)\n",
+		r.copyTime.Round(time.Millisecond), r.deleteTime.Round(time.Millisecond), r.moved, float64(r.moved)/r.duration().Seconds())
+	logger.Printf("  📖 Okuma p99: iş öncesi %v, iş sırasında %v\n",
+		r.baseline.Summary().P99.Round(time.Microsecond), r.during.Summary().P99.Round(time.Microsecond))
+
+	if r.after, err = CollectStorageStats(ctx, source); HandleError(logger, "stats", err) {
+		return r, false
+	}
+	if compact {
+		compactStart := time.Now()
+		err := source.Database().RunCommand(ctx, bson.D{{Key: "compact", Value: source.Name()}}).Err()
+		r.compactTime = time.Since(compactStart)
+		if !HandleError(logger, "compact", err) {
+			r.compacted, err = CollectStorageStats(ctx, source)
+			HandleError(logger, "stats", err)
+		}
+		var validate struct {
+			Valid    bool  `bson:"valid"`
+			NRecords int64 `bson:"nrecords"`
+		}
+		err = source.Database().RunCommand(ctx, bson.D{{Key: "validate", Value: source.Name()}}).Decode(&validate)
+		if !HandleError(logger, "validate", err) {
+			r.valid, r.validRecords = validate.Valid, validate.NRecords
+		}
+	}
+	return r, true
+}
+
+// sampleIDs - Filtreye uyan ilk limit dokümanın _id'leri
+func sampleIDs(ctx context.Context, col *mongo.Collection, filter bson.M, limit int) ([]interface{}, error) {
+	cursor, err := col.Find(ctx, filter, options.Find().SetProjection(bson.M{"_id": 1}).SetLimit(int64(limit)))
+	if err != nil {
+		return nil, err
+	}
+	var docs []bson.Raw
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+	ids := make([]interface{}, len(docs))
+	for i, d := range docs {
+		ids[i] = d.Lookup("_id")
+	}
+	return ids, nil
+}
+
+// mb - Byte → MB
+func mb(bytes int64) float64 {
+	return float64(bytes) / (1024 * 1024)
+}
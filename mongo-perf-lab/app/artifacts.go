@@ -0,0 +1,254 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// artifacts.go - Çalıştırma klasörlerinin saklanması: zstd sıkıştırma ve saklama süresi (perflab gc)
+// Explain çıktısı içeren _repN.json'lar, events.jsonl ve ham gecikme örnekleri runs/ klasörünü hızla
+// büyütür. perflab run, artifacts.json yazılmadan hemen önce compressMinSize'dan büyük sonuç dosyalarını
+// <ad>.zst olarak sıkıştırır (-compress=false ile kapanır); hash listesi sıkıştırılmış dosyaları kapsar,
+// imzalı klasörler doğrulanmaya devam eder.
+//
+// Okuyan komutlar (writeup, browse, logs, topology) dosyayı OpenArtifact ile açar: <ad> yoksa <ad>.zst
+// akış olarak açılır, dosyanın tamamı belleğe alınmaz. Elle bakmak için:
+//
+//	zstd -dc runs/paid_orders_20250101_120000/events.jsonl.zst | less
+//
+// Eski çalıştırmalar klasör adındaki zamana göre silinir:
+//
+//	perflab gc --keep 30d            (30 günden eski çalıştırmalar)
+//	perflab gc --keep 12h -dry-run   (sadece listele)
+
+// compressedExt - Sıkıştırılmış dosyanın uzantısı (<ad>.zst)
+const compressedExt = ".zst"
+
+// compressMinSize - Bundan küçük dosyalar sıkıştırılmaz: Elle açmak kolay kalsın
+const compressMinSize = 64 << 10
+
+// compressibleExts - Sıkıştırılan dosyalar: Metin ve ham gecikme örnekleri (bkz. samples.go)
+// pprof zaten gzip'li, growth binary'si ve manifest atlanır
+var compressibleExts = []string{".json", ".jsonl", ".txt", ".csv", ".bin"}
+
+// zstdFile - Sıkıştırılmış dosyanın akış okuyucusu
+type zstdFile struct {
+	dec  *zstd.Decoder
+	file *os.File
+}
+
+func (z *zstdFile) Read(p []byte) (int, error) { return z.dec.Read(p) }
+
+func (z *zstdFile) Close() error {
+	z.dec.Close()
+	return z.file.Close()
+}
+
+// OpenArtifact - Çalıştırma dosyasını okumak için açar; path yoksa path.zst açılır ve akış olarak çözülür
+// path doğrudan .zst ile bitiyorsa da çözülür. İkisi de yoksa path'in hatası döner
+func OpenArtifact(path string) (io.ReadCloser, error) {
+	if !strings.HasSuffix(path, compressedExt) {
+		file, err := os.Open(path)
+		if err == nil {
+			return file, nil
+		}
+		if !os.IsNotExist(err) {
+			return nil, err
+		}
+		compressed, zerr := os.Open(path + compressedExt)
+		if zerr != nil {
+			return nil, err
+		}
+		return newZstdFile(compressed)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	return newZstdFile(file)
+}
+
+func newZstdFile(file *os.File) (io.ReadCloser, error) {
+	// Tek goroutine: Dosyalar sırayla okunur, varsayılan (GOMAXPROCS) decoder bellek harcar
+	dec, err := zstd.NewReader(file, zstd.WithDecoderConcurrency(1))
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("%s: %v", file.Name(), err)
+	}
+	return &zstdFile{dec: dec, file: file}, nil
+}
+
+// GlobArtifacts - pattern'e uyan dosyalar ve sıkıştırılmış halleri (pattern.zst), yola göre sıralı
+// Aynı dosyanın iki hali de varsa sıkıştırılmamış olan döner
+func GlobArtifacts(pattern string) ([]string, error) {
+	plain, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, err
+	}
+	compressed, err := filepath.Glob(pattern + compressedExt)
+	if err != nil {
+		return nil, err
+	}
+	paths := plain
+	for _, path := range compressed {
+		if !slices.Contains(plain, strings.TrimSuffix(path, compressedExt)) {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// CompressArtifacts - runDir'deki compressMinSize'dan büyük sonuç dosyalarını <ad>.zst ile değiştirir
+// Döndürür: sıkıştırılan dosya sayısı, önceki ve sonraki toplam boyut
+func CompressArtifacts(runDir string) (files int, before, after int64, err error) {
+	err = filepath.WalkDir(runDir, func(path string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !slices.Contains(compressibleExts, filepath.Ext(path)) {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil || info.Size() < compressMinSize {
+			return err
+		}
+		size, err := compressFile(path)
+		if err != nil {
+			return fmt.Errorf("%s: %v", path, err)
+		}
+		files++
+		before += info.Size()
+		after += size
+		return nil
+	})
+	return files, before, after, err
+}
+
+// compressFile - path'i path.zst'ye sıkıştırır ve orijinali siler
+// Önce geçici dosyaya yazılır: Yarıda kalan sıkıştırma okunabilir dosyayı bozmaz
+func compressFile(path string) (int64, error) {
+	in, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer in.Close()
+	tmp := path + compressedExt + ".tmp"
+	out, err := os.Create(tmp)
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(tmp) // Başarılıysa zaten taşınmıştır
+	enc, err := zstd.NewWriter(out, zstd.WithEncoderLevel(zstd.SpeedBetterCompression))
+	if err != nil {
+		out.Close()
+		return 0, err
+	}
+	if _, err := io.Copy(enc, in); err != nil {
+		enc.Close()
+		out.Close()
+		return 0, err
+	}
+	if err := enc.Close(); err != nil {
+		out.Close()
+		return 0, err
+	}
+	info, err := out.Stat()
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return 0, err
+	}
+	if err := os.Rename(tmp, path+compressedExt); err != nil {
+		return 0, err
+	}
+	in.Close()
+	return info.Size(), os.Remove(path)
+}
+
+// ParseRetention - Saklama süresi: time.ParseDuration biçimi veya gün ("30d", "1.5d")
+func ParseRetention(s string) (time.Duration, error) {
+	var d time.Duration
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.ParseFloat(days, 64)
+		if err != nil {
+			return 0, fmt.Errorf("geçersiz süre %q (ör: 30d, 12h)", s)
+		}
+		d = time.Duration(n * float64(24*time.Hour))
+	} else {
+		var err error
+		if d, err = time.ParseDuration(s); err != nil {
+			return 0, fmt.Errorf("geçersiz süre %q (ör: 30d, 12h)", s)
+		}
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("süre pozitif olmalı: %q", s)
+	}
+	return d, nil
+}
+
+// runDirTime - Klasör adının sonundaki çalıştırma zamanı (<deney>_20060102_150405, perflab run ve topology)
+var runDirTime = regexp.MustCompile(`_(\d{8}_\d{6})$`)
+
+// RunDir - runs/ altındaki bir çalıştırma klasörü
+type RunDir struct {
+	Path      string
+	StartedAt time.Time
+	Size      int64 // Alt klasörler dahil toplam byte
+}
+
+// ListRunDirs - runsDir altındaki çalıştırma klasörleri (eskiden yeniye)
+// Adında zaman olmayan klasörler skipped'da döner: Başka bir şey olabilirler, gc onlara dokunmaz
+func ListRunDirs(runsDir string) (runs []RunDir, skipped []string, err error) {
+	entries, err := os.ReadDir(runsDir)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		path := filepath.Join(runsDir, e.Name())
+		m := runDirTime.FindStringSubmatch(e.Name())
+		if m == nil {
+			skipped = append(skipped, path)
+			continue
+		}
+		// Klasör adı yerel saatle yazılır (bkz. cmdRun)
+		started, err := time.ParseInLocation("20060102_150405", m[1], time.Local)
+		if err != nil {
+			skipped = append(skipped, path)
+			continue
+		}
+		size, err := dirSize(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		runs = append(runs, RunDir{Path: path, StartedAt: started, Size: size})
+	}
+	sort.Slice(runs, func(i, j int) bool { return runs[i].StartedAt.Before(runs[j].StartedAt) })
+	return runs, skipped, nil
+}
+
+func dirSize(dir string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		info, err := d.Info()
+		if err == nil {
+			size += info.Size()
+		}
+		return err
+	})
+	return size, err
+}
@@ -0,0 +1,639 @@
+package main
+
+import (
+	"fmt"
+	"html/template"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"benchkit"
+	"golang.org/x/term"
+)
+
+// browse.go - Geçmiş çalıştırmalar için terminal arayüzü (perflab browse)
+// runs/ altındaki summary.json'lar listelenir; sorgu yazmadan çalıştırmalar arasında gezinmek,
+// iki çalıştırmayı karşılaştırmak ve gecikme dağılımına bakmak için:
+//
+//	↑/↓ j/k    gezin               PgUp/PgDn   sayfa
+//	space      işaretle (en fazla 2) enter       ayrıntı (benchmark'lar)
+//	d          işaretli iki çalıştırmanın farkı
+//	h          (ayrıntıda) seçili benchmark'ın histogramı
+//	e          işaretlileri (yoksa seçiliyi) HTML'e aktar
+//	esc/b      geri                q           çık
+//
+// Histogram, kayıtlarda işlem gecikmesi varsa (MetricsRecord.Histogram, ör: insert_bench) onu,
+// yoksa tekrar sürelerinin dağılımını gösterir.
+
+// browseRun - Listedeki bir çalıştırma
+type browseRun struct {
+	Dir     string
+	Summary *RunSummary
+	Stats   []benchmarkStats
+}
+
+// Label - Listede ve başlıklarda gösterilen kısa ad
+func (r *browseRun) Label() string {
+	return fmt.Sprintf("%s %s", r.Summary.StartedAt.Local().Format("2006-01-02 15:04"), r.Summary.Manifest.Name)
+}
+
+// AssertionsPassed - Geçen/toplam assertion
+func (r *browseRun) AssertionsPassed() (int, int) {
+	passed := 0
+	for _, a := range r.Summary.Assertions {
+		if a.Passed {
+			passed++
+		}
+	}
+	return passed, len(r.Summary.Assertions)
+}
+
+// Histogram - Benchmark'ın tüm tekrarlarındaki gecikme histogramı (birleştirilmiş)
+// ok false ise kayıtlarda histogram yoktur; dönen kovalar tekrar sürelerinin dağılımıdır
+func (r *browseRun) Histogram(name string) (buckets []benchkit.HistogramBucket, ok bool) {
+	counts := map[float64]uint64{}
+	var durations []float64
+	for _, rec := range r.Summary.Records {
+		if rec.Name() != name {
+			continue
+		}
+		durations = append(durations, rec.DurationMs)
+		for _, b := range rec.Histogram {
+			counts[b.LeMs] += b.Count
+		}
+	}
+	if len(counts) == 0 {
+		sort.Float64s(durations)
+		for _, d := range durations {
+			buckets = append(buckets, benchkit.HistogramBucket{LeMs: d, Count: 1})
+		}
+		return buckets, false
+	}
+	for le, n := range counts {
+		buckets = append(buckets, benchkit.HistogramBucket{LeMs: le, Count: n})
+	}
+	sort.Slice(buckets, func(i, j int) bool { return buckets[i].LeMs < buckets[j].LeMs })
+	return buckets, true
+}
+
+// LoadBrowseRuns - dir altındaki çalıştırmalar (en yeni önce); summary.json'ı olmayanlar atlanır
+func LoadBrowseRuns(dir string) ([]*browseRun, error) {
+	paths, err := GlobArtifacts(filepath.Join(dir, "*", "summary.json"))
+	if err != nil {
+		return nil, err
+	}
+	var runs []*browseRun
+	for _, path := range paths {
+		s, err := LoadRunSummary(path)
+		if err != nil {
+			continue
+		}
+		runs = append(runs, &browseRun{Dir: filepath.Dir(path), Summary: s, Stats: summarizeBenchmarks(s.Records)})
+	}
+	sort.Slice(runs, func(i, j int) bool { return runs[i].Summary.StartedAt.After(runs[j].Summary.StartedAt) })
+	return runs, nil
+}
+
+// runDiffRow - İki çalıştırmada aynı addaki benchmark (yalnızca birinde varsa diğeri nil)
+type runDiffRow struct {
+	Name string
+	A, B *benchmarkStats
+}
+
+// DeltaPct - Ortalama süre değişimi (B, A'ya göre; negatif = hızlandı)
+func (d runDiffRow) DeltaPct() (float64, bool) {
+	if d.A == nil || d.B == nil || d.A.MeanMs == 0 {
+		return 0, false
+	}
+	return (d.B.MeanMs - d.A.MeanMs) / d.A.MeanMs * 100, true
+}
+
+// diffRuns - A'nın sırasıyla, sonra yalnızca B'de olanlar
+func diffRuns(a, b *browseRun) []runDiffRow {
+	var rows []runDiffRow
+	inA := map[string]bool{}
+	for i := range a.Stats {
+		row := runDiffRow{Name: a.Stats[i].Name, A: &a.Stats[i]}
+		for j := range b.Stats {
+			if b.Stats[j].Name == row.Name {
+				row.B = &b.Stats[j]
+			}
+		}
+		inA[row.Name] = true
+		rows = append(rows, row)
+	}
+	for j := range b.Stats {
+		if !inA[b.Stats[j].Name] {
+			rows = append(rows, runDiffRow{Name: b.Stats[j].Name, B: &b.Stats[j]})
+		}
+	}
+	return rows
+}
+
+// significantDelta - Bu yüzdeden küçük değişimler renklendirilmez (tekrarlar arası gürültü)
+const significantDelta = 5.0
+
+// Görünümler
+const (
+	viewList = iota
+	viewDetail
+	viewHistogram
+	viewDiff
+)
+
+// browser - TUI durumu
+type browser struct {
+	runs    []*browseRun
+	view    int
+	cursor  int // Listede seçili çalıştırma
+	offset  int // Listenin ilk görünen satırı
+	marked  []int
+	bench   int // Ayrıntıda seçili benchmark
+	status  string
+	width   int
+	height  int
+	outDir  string
+	exports int
+}
+
+// RunBrowser - Terminali raw moda alıp arayüzü q'ya basılana kadar çalıştırır
+func RunBrowser(runs []*browseRun, outDir string) error {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return fmt.Errorf("browse bir terminal gerektirir")
+	}
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return err
+	}
+	// Alternatif ekran: Çıkışta terminal eski haline döner
+	fmt.Print("\x1b[?1049h\x1b[?25l")
+	defer func() {
+		fmt.Print("\x1b[?25h\x1b[?1049l")
+		term.Restore(fd, state)
+	}()
+
+	b := &browser{runs: runs, outDir: outDir}
+	buf := make([]byte, 16)
+	for {
+		b.width, b.height, err = term.GetSize(fd)
+		if err != nil || b.width <= 0 {
+			b.width, b.height = 100, 30
+		}
+		b.draw()
+		n, err := os.Stdin.Read(buf)
+		if err != nil {
+			return err
+		}
+		if !b.handle(string(buf[:n])) {
+			return nil
+		}
+	}
+}
+
+// handle - Tuşu işler; false dönerse çıkılır
+func (b *browser) handle(key string) bool {
+	b.status = ""
+	switch key {
+	case "q", "\x03": // q, Ctrl-C
+		return false
+	case "\x1b", "b", "\x7f":
+		switch b.view {
+		case viewHistogram:
+			b.view = viewDetail
+		case viewList:
+			return false
+		default:
+			b.view = viewList
+		}
+		return true
+	}
+
+	switch b.view {
+	case viewList:
+		switch key {
+		case "\x1b[A", "k":
+			b.cursor = max(0, b.cursor-1)
+		case "\x1b[B", "j":
+			b.cursor = min(len(b.runs)-1, b.cursor+1)
+		case "\x1b[5~":
+			b.cursor = max(0, b.cursor-b.pageSize())
+		case "\x1b[6~":
+			b.cursor = min(len(b.runs)-1, b.cursor+b.pageSize())
+		case " ":
+			b.toggleMark(b.cursor)
+		case "\r", "\n":
+			b.view, b.bench = viewDetail, 0
+		case "d":
+			if len(b.marked) != 2 {
+				b.status = "Fark için space ile iki çalıştırma işaretleyin"
+			} else {
+				b.view = viewDiff
+			}
+		case "e":
+			b.export()
+		}
+	case viewDetail:
+		stats := b.runs[b.cursor].Stats
+		switch key {
+		case "\x1b[A", "k":
+			b.bench = max(0, b.bench-1)
+		case "\x1b[B", "j":
+			b.bench = min(len(stats)-1, b.bench+1)
+		case "h", "\r", "\n":
+			if len(stats) > 0 {
+				b.view = viewHistogram
+			}
+		case "e":
+			b.export()
+		}
+	case viewDiff:
+		if key == "e" {
+			b.export()
+		}
+	}
+	return true
+}
+
+func (b *browser) toggleMark(i int) {
+	for j, m := range b.marked {
+		if m == i {
+			b.marked = append(b.marked[:j], b.marked[j+1:]...)
+			return
+		}
+	}
+	if len(b.marked) == 2 {
+		b.marked = b.marked[1:] // En eski işaret düşer
+	}
+	b.marked = append(b.marked, i)
+}
+
+func (b *browser) isMarked(i int) bool {
+	for _, m := range b.marked {
+		if m == i {
+			return true
+		}
+	}
+	return false
+}
+
+// diffPair - İşaretli iki çalıştırma; A her zaman eskisi (liste en yeni önce sıralı)
+func (b *browser) diffPair() (a, c *browseRun) {
+	i, j := b.marked[0], b.marked[1]
+	if i < j {
+		i, j = j, i
+	}
+	return b.runs[i], b.runs[j]
+}
+
+// pageSize - Başlık (2) ve alt bilgi (2) satırları dışında kalan alan
+func (b *browser) pageSize() int {
+	return max(1, b.height-4)
+}
+
+// draw - Ekranı baştan çizer (raw modda satır sonu \r\n olmalı)
+func (b *browser) draw() {
+	var title, help string
+	var body []string
+	switch b.view {
+	case viewList:
+		title = fmt.Sprintf("perflab browse - %d çalıştırma", len(b.runs))
+		help = "↑↓ gezin · space işaretle · enter ayrıntı · d fark · e HTML · q çık"
+		body = b.listLines()
+	case viewDetail:
+		title = b.runs[b.cursor].Label()
+		help = "↑↓ benchmark · h histogram · e HTML · esc geri"
+		body = b.detailLines()
+	case viewHistogram:
+		run := b.runs[b.cursor]
+		title = fmt.Sprintf("%s › %s", run.Label(), run.Stats[b.bench].Name)
+		help = "esc geri"
+		body = b.histogramLines()
+	case viewDiff:
+		a, c := b.diffPair()
+		title = fmt.Sprintf("A: %s  ↔  B: %s", a.Label(), c.Label())
+		help = "e HTML · esc geri"
+		body = b.diffLines()
+	}
+	if len(body) > b.pageSize() {
+		body = body[:b.pageSize()]
+	}
+
+	var sb strings.Builder
+	sb.WriteString("\x1b[H\x1b[2J")
+	sb.WriteString("\x1b[1m" + b.fit(title) + "\x1b[0m\r\n\r\n")
+	for _, line := range body {
+		sb.WriteString(b.fit(line) + "\r\n")
+	}
+	for i := len(body); i < b.pageSize(); i++ {
+		sb.WriteString("\r\n")
+	}
+	status := help
+	if b.status != "" {
+		status = b.status
+	}
+	sb.WriteString("\r\n\x1b[2m" + b.fit(status) + "\x1b[0m")
+	os.Stdout.WriteString(sb.String())
+}
+
+// fit - Satırı terminal genişliğine kırpar (renk kodları genişliğe sayılmaz)
+func (b *browser) fit(line string) string {
+	visible := 0
+	for i, inEscape := 0, false; i < len(line); {
+		r, size := utf8.DecodeRuneInString(line[i:])
+		switch {
+		case r == '\x1b':
+			inEscape = true
+		case inEscape:
+			inEscape = r != 'm'
+		default:
+			visible++
+			if visible > b.width {
+				return line[:i] + "\x1b[0m"
+			}
+		}
+		i += size
+	}
+	return line
+}
+
+func (b *browser) listLines() []string {
+	// İmleç görünür kalsın
+	if b.cursor < b.offset {
+		b.offset = b.cursor
+	}
+	if b.cursor >= b.offset+b.pageSize() {
+		b.offset = b.cursor - b.pageSize() + 1
+	}
+	var lines []string
+	for i := b.offset; i < len(b.runs) && i < b.offset+b.pageSize(); i++ {
+		run := b.runs[i]
+		mark := "[ ]"
+		if b.isMarked(i) {
+			mark = "[x]"
+		}
+		checks := "-"
+		if passed, total := run.AssertionsPassed(); total > 0 {
+			checks = fmt.Sprintf("%d/%d", passed, total)
+			if passed < total {
+				checks = "\x1b[31m" + checks + "\x1b[0m"
+			}
+		}
+		line := fmt.Sprintf(" %s %-40s %-10s %3d benchmark %4d kayıt  assert %s  %s",
+			mark, run.Label(), run.Summary.Environment, len(run.Stats), len(run.Summary.Records), checks, filepath.Base(run.Dir))
+		if i == b.cursor {
+			line = "\x1b[7m" + line + "\x1b[0m"
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func (b *browser) detailLines() []string {
+	run := b.runs[b.cursor]
+	s := run.Summary
+	lines := []string{
+		fmt.Sprintf(" ortam %s · %s · süre %v", s.Environment, s.Host, s.FinishedAt.Sub(s.StartedAt).Round(time.Second)),
+		fmt.Sprintf(" klasör %s", run.Dir),
+		"",
+		fmt.Sprintf("   %-28s %6s %18s %12s %10s %10s %6s", "benchmark", "tekrar", "süre ms (ort±sapma)", "kayıt", "bellek MB", "verim %", "hata"),
+	}
+	for i, st := range run.Stats {
+		line := fmt.Sprintf("   %-28s %6d %11.1f ± %-6.1f %12.0f %10.2f %10.2f %6d",
+			st.Name, st.Reps, st.MeanMs, st.StdDevMs, st.Records, st.MemoryMB, st.Efficiency, st.Errors)
+		if len(st.Findings) > 0 {
+			line += "  " + strings.Join(st.Findings, ",")
+		}
+		if i == b.bench {
+			line = "\x1b[7m" + line + "\x1b[0m"
+		}
+		lines = append(lines, line)
+	}
+	if len(s.Assertions) > 0 {
+		lines = append(lines, "", " assertion'lar:")
+		for _, a := range s.Assertions {
+			mark := "\x1b[32m✓\x1b[0m"
+			if !a.Passed {
+				mark = "\x1b[31m✗\x1b[0m"
+			}
+			lines = append(lines, fmt.Sprintf("   %s %s.%s = %.2f %s", mark, a.Name(), a.Metric, a.Value, a.Reason))
+		}
+	}
+	return lines
+}
+
+func (b *browser) histogramLines() []string {
+	run := b.runs[b.cursor]
+	name := run.Stats[b.bench].Name
+	buckets, ok := run.Histogram(name)
+	var lines []string
+	if ok {
+		lines = append(lines, " İşlem gecikmesi (tüm tekrarlar):", "")
+	} else {
+		lines = append(lines, " Kayıtlarda işlem gecikmesi yok - tekrar süreleri:", "")
+	}
+	buckets = compactBuckets(buckets, b.pageSize()-len(lines))
+	var total, maxCount uint64
+	for _, bk := range buckets {
+		total += bk.Count
+		maxCount = max(maxCount, bk.Count)
+	}
+	barWidth := max(10, b.width-50)
+	var cumulative uint64
+	for _, bk := range buckets {
+		cumulative += bk.Count
+		bar := strings.Repeat("█", int(math.Round(float64(bk.Count)/float64(maxCount)*float64(barWidth))))
+		lines = append(lines, fmt.Sprintf(" ≤ %10s ms │%-*s %8d %6.1f%%",
+			formatBucketMs(bk.LeMs), barWidth, bar, bk.Count, float64(cumulative)/float64(total)*100))
+	}
+	return lines
+}
+
+// compactBuckets - Kova sayısı rows'u geçiyorsa ardışık kovalar birleştirilir (üst sınır sonuncununki)
+func compactBuckets(buckets []benchkit.HistogramBucket, rows int) []benchkit.HistogramBucket {
+	if rows <= 0 || len(buckets) <= rows {
+		return buckets
+	}
+	per := (len(buckets) + rows - 1) / rows
+	var out []benchkit.HistogramBucket
+	for i := 0; i < len(buckets); i += per {
+		group := buckets[i:min(i+per, len(buckets))]
+		merged := benchkit.HistogramBucket{LeMs: group[len(group)-1].LeMs}
+		for _, bk := range group {
+			merged.Count += bk.Count
+		}
+		out = append(out, merged)
+	}
+	return out
+}
+
+func formatBucketMs(ms float64) string {
+	switch {
+	case ms >= 100:
+		return fmt.Sprintf("%.0f", ms)
+	case ms >= 1:
+		return fmt.Sprintf("%.2f", ms)
+	default:
+		return fmt.Sprintf("%.3f", ms)
+	}
+}
+
+func (b *browser) diffLines() []string {
+	a, c := b.diffPair()
+	lines := []string{
+		fmt.Sprintf("   %-28s %12s %12s %9s %11s %11s", "benchmark", "A ms", "B ms", "Δ süre", "A bellek", "B bellek"),
+	}
+	cell := func(st *benchmarkStats, f func(*benchmarkStats) float64) string {
+		if st == nil {
+			return "-"
+		}
+		return fmt.Sprintf("%.1f", f(st))
+	}
+	mean := func(st *benchmarkStats) float64 { return st.MeanMs }
+	memory := func(st *benchmarkStats) float64 { return st.MemoryMB }
+	for _, row := range diffRuns(a, c) {
+		delta := "-"
+		if pct, ok := row.DeltaPct(); ok {
+			delta = fmt.Sprintf("%+8.1f%%", pct)
+			switch {
+			case pct <= -significantDelta:
+				delta = "\x1b[32m" + delta + "\x1b[0m"
+			case pct >= significantDelta:
+				delta = "\x1b[31m" + delta + "\x1b[0m"
+			}
+		}
+		lines = append(lines, fmt.Sprintf("   %-28s %12s %12s %9s %11s %11s",
+			row.Name, cell(row.A, mean), cell(row.B, mean), delta, cell(row.A, memory), cell(row.B, memory)))
+	}
+	lines = append(lines, "", fmt.Sprintf(" Δ: B'nin A'ya göre süre değişimi (yeşil: %%%.0f+ hızlı, kırmızı: %%%.0f+ yavaş)", significantDelta, significantDelta))
+	return lines
+}
+
+// export - İşaretli çalıştırmaları (yoksa seçili olanı) HTML'e yazar
+func (b *browser) export() {
+	var runs []*browseRun
+	switch len(b.marked) {
+	case 0:
+		runs = []*browseRun{b.runs[b.cursor]}
+	case 1:
+		runs = []*browseRun{b.runs[b.marked[0]]}
+	default:
+		a, c := b.diffPair()
+		runs = []*browseRun{a, c}
+	}
+	b.exports++
+	path := filepath.Join(b.outDir, fmt.Sprintf("perflab-browse-%s-%d.html", time.Now().Format("20060102_150405"), b.exports))
+	if err := ExportRunsHTML(path, runs); err != nil {
+		b.status = "❌ " + err.Error()
+		return
+	}
+	b.status = "📄 " + path
+}
+
+// htmlBucket - HTML histogram satırı
+type htmlBucket struct {
+	Label string
+	Count uint64
+	Width float64 // %
+}
+
+// htmlBenchmark - HTML'deki benchmark satırı ve histogramı
+type htmlBenchmark struct {
+	benchmarkStats
+	Latency bool // false: Tekrar süreleri
+	Buckets []htmlBucket
+}
+
+// htmlDiffRow - HTML fark tablosu satırı
+type htmlDiffRow struct {
+	Name, A, B, Delta string
+	Class             string // faster / slower
+}
+
+var browseTemplate = template.Must(template.New("browse").Parse(`<!DOCTYPE html>
+<html lang="tr"><head><meta charset="utf-8"><title>perflab - {{range $i, $r := .Runs}}{{if $i}} ↔ {{end}}{{$r.Label}}{{end}}</title>
+<style>
+body{font-family:system-ui,sans-serif;margin:2em;color:#222}
+table{border-collapse:collapse;margin:1em 0}th,td{border:1px solid #ddd;padding:4px 8px;text-align:right}
+th:first-child,td:first-child{text-align:left}.faster{color:#080}.slower{color:#c00}
+.bar{background:#4878d0;height:12px}.hist td{border:none;padding:1px 6px}.muted{color:#777}
+</style></head><body>
+{{if .Diff}}<h1>Fark</h1>
+<p>A: {{(index .Runs 0).Label}} · B: {{(index .Runs 1).Label}}</p>
+<table><tr><th>benchmark</th><th>A ms</th><th>B ms</th><th>Δ süre</th></tr>
+{{range .Diff}}<tr><td>{{.Name}}</td><td>{{.A}}</td><td>{{.B}}</td><td class="{{.Class}}">{{.Delta}}</td></tr>
+{{end}}</table>{{end}}
+{{range .Runs}}<h2>{{.Label}}</h2>
+<p class="muted">{{.Summary.Environment}} · {{.Summary.Host}} · {{.Dir}}</p>
+<table><tr><th>benchmark</th><th>tekrar</th><th>ort ms</th><th>sapma ms</th><th>kayıt</th><th>bellek MB</th><th>verim %</th><th>hata</th></tr>
+{{range .Benchmarks}}<tr><td>{{.Name}}</td><td>{{.Reps}}</td><td>{{printf "%.1f" .MeanMs}}</td><td>{{printf "%.1f" .StdDevMs}}</td><td>{{printf "%.0f" .Records}}</td><td>{{printf "%.2f" .MemoryMB}}</td><td>{{printf "%.2f" .Efficiency}}</td><td>{{.Errors}}</td></tr>
+{{end}}</table>
+{{range .Benchmarks}}<h3>{{.Name}} <span class="muted">{{if .Latency}}işlem gecikmesi{{else}}tekrar süreleri{{end}}</span></h3>
+<table class="hist">{{range .Buckets}}<tr><td>≤ {{.Label}} ms</td><td style="width:400px"><div class="bar" style="width:{{printf "%.1f" .Width}}%"></div></td><td>{{.Count}}</td></tr>
+{{end}}</table>{{end}}{{end}}
+</body></html>
+`))
+
+// ExportRunsHTML - Çalıştırmaları (ikiyse farkıyla birlikte) tek HTML dosyasına yazar
+func ExportRunsHTML(path string, runs []*browseRun) error {
+	type htmlRun struct {
+		*browseRun
+		Benchmarks []htmlBenchmark
+	}
+	data := struct {
+		Runs []htmlRun
+		Diff []htmlDiffRow
+	}{}
+	for _, run := range runs {
+		hr := htmlRun{browseRun: run}
+		for _, st := range run.Stats {
+			buckets, latency := run.Histogram(st.Name)
+			buckets = compactBuckets(buckets, 40)
+			var maxCount uint64
+			for _, bk := range buckets {
+				maxCount = max(maxCount, bk.Count)
+			}
+			hb := htmlBenchmark{benchmarkStats: st, Latency: latency}
+			for _, bk := range buckets {
+				hb.Buckets = append(hb.Buckets, htmlBucket{Label: formatBucketMs(bk.LeMs), Count: bk.Count, Width: float64(bk.Count) / float64(maxCount) * 100})
+			}
+			hr.Benchmarks = append(hr.Benchmarks, hb)
+		}
+		data.Runs = append(data.Runs, hr)
+	}
+	if len(runs) == 2 {
+		for _, row := range diffRuns(runs[0], runs[1]) {
+			hd := htmlDiffRow{Name: row.Name, A: "-", B: "-", Delta: "-"}
+			if row.A != nil {
+				hd.A = fmt.Sprintf("%.1f", row.A.MeanMs)
+			}
+			if row.B != nil {
+				hd.B = fmt.Sprintf("%.1f", row.B.MeanMs)
+			}
+			if pct, ok := row.DeltaPct(); ok {
+				hd.Delta = fmt.Sprintf("%+.1f%%", pct)
+				switch {
+				case pct <= -significantDelta:
+					hd.Class = "faster"
+				case pct >= significantDelta:
+					hd.Class = "slower"
+				}
+			}
+			data.Diff = append(data.Diff, hd)
+		}
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := browseTemplate.Execute(f, data); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
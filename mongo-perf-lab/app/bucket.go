@@ -0,0 +1,261 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// bucket.go - Bucket pattern: Olay başına doküman vs N olaylık bucket dokümanları
+// Zaman serisi benzeri veride (mağaza başına satış olayları) her olayı ayrı doküman yazmak basittir
+// ama doküman ve index girdisi sayısı olay sayısı kadardır. Bucket pattern'de bir mağazanın
+// ardışık N olayı tek dokümanda (events dizisi) toplanır:
+//
+//	{storeId, count, first, last, events: [{ts, type, amount}, ...]}
+//
+// Yazma, dolmamış bucket'a upsert + $push'tur; okuma zaman aralığıyla kesişen bucket'ları getirir
+// ve aralık dışındaki olayları client'ta ayıklar.
+//
+// Aynı mantıksal olay akışı (seed'den deterministik) her tasarıma ayrı koleksiyona yazılır:
+//   - write/flat, write/bucket-N: Batch başına gecikme ve olay/sn
+//   - read/flat, read/bucket-N:   Rastgele mağaza + zaman penceresi sorgusunun gecikmesi
+//
+// Not: Bucket'lar sayıya göre kapanır (count < N); index {storeId, count} upsert'ün dolmamış
+// bucket'ı bulması içindir ve her yazmada güncellenir - bu maliyet de ölçüme dahildir.
+//
+// KULLANIM:
+//   go run main.go config.go analyzer.go antipattern.go errors.go logger.go samples.go sink.go dataset.go flags.go latency.go bucket.go
+//   go run main.go config.go analyzer.go antipattern.go errors.go logger.go samples.go sink.go dataset.go flags.go latency.go bucket.go -events 2000000 -sizes 50,200,1000 -window 6h
+
+// bucketEvent - Mantıksal olay (iki tasarımda da aynı içerik)
+type bucketEvent struct {
+	StoreID int       `bson:"storeId"`
+	TS      time.Time `bson:"ts"`
+	Type    string    `bson:"type"`
+	Amount  int       `bson:"amount"`
+}
+
+// bucketEventTypes - Olay türleri
+var bucketEventTypes = []string{"sale", "refund", "view", "cart"}
+
+// bucketDoc - Okumada decode edilen bucket
+type bucketDoc struct {
+	Events []struct {
+		TS time.Time `bson:"ts"`
+	} `bson:"events"`
+}
+
+func main() {
+	events := flag.Int("events", 1_000_000, "Olay akışındaki olay sayısı")
+	stores := flag.Int("stores", 100, "Mağaza sayısı (olaylar mağazalara rastgele dağılır)")
+	span := flag.Duration("span", 30*24*time.Hour, "Olay akışının kapsadığı süre")
+	sizeList := flag.String("sizes", "100,500", "Bucket başına olay sayıları (N, tarama)")
+	batch := flag.Int("batch", 1000, "Yazma batch boyutu (olay)")
+	queries := flag.Int("queries", 2000, "Tasarım başına aralık sorgusu")
+	window := flag.Duration("window", time.Hour, "Aralık sorgusunun zaman penceresi")
+	seed := flag.Int64("seed", 42, "Olay akışı ve sorgu sırası için seed")
+	flag.Parse()
+
+	sizes, err := parseIntList(*sizeList)
+	if err != nil {
+		fmt.Printf("Geçersiz -sizes: %v\n", err)
+		return
+	}
+	if *events <= 0 || *stores <= 0 || *batch <= 0 || *queries <= 0 || *span <= 0 || *window <= 0 {
+		fmt.Println("❌ -events, -stores, -batch, -queries, -span ve -window pozitif olmalı")
+		return
+	}
+
+	logger, err := NewLogger("bucket_results.txt")
+	if err != nil {
+		fmt.Printf("Logger oluşturulamadı: %v\n", err)
+		return
+	}
+	defer CloseLogger(logger)
+
+	logger.WriteHeader("bucket - Olay Başına Doküman vs Bucket Pattern")
+
+	db := GetMongo().Database()
+	ctx := context.Background()
+
+	// Olay akışı bir kez üretilir: Üretim maliyeti yazma ölçümüne karışmaz
+	rng := rand.New(rand.NewSource(*seed))
+	origin := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	step := *span / time.Duration(*events)
+	stream := make([]bucketEvent, *events)
+	for i := range stream {
+		stream[i] = bucketEvent{
+			StoreID: rng.Intn(*stores),
+			TS:      origin.Add(time.Duration(i) * step),
+			Type:    bucketEventTypes[rng.Intn(len(bucketEventTypes))],
+			Amount:  rng.Intn(1000),
+		}
+	}
+	logger.Printf("📋 %d olay, %d mağaza, %v (mağaza başına ~%d olay), batch %d, bucket boyutları %v\n",
+		*events, *stores, *span, *events / *stores, *batch, sizes)
+
+	// Sorgular: Her tasarım aynı (mağaza, pencere) sırasını okur
+	type rangeQuery struct {
+		store      int
+		start, end time.Time
+	}
+	ranges := make([]rangeQuery, *queries)
+	for i := range ranges {
+		start := origin.Add(time.Duration(rng.Int63n(int64(*span - *window))))
+		ranges[i] = rangeQuery{store: rng.Intn(*stores), start: start, end: start.Add(*window)}
+	}
+	batches := (*events + *batch - 1) / *batch
+	batchSize := *batch
+	eventsOf := func(i int) []bucketEvent { return stream[i*batchSize : min((i+1)*batchSize, len(stream))] }
+
+	type design struct {
+		name    string
+		col     *mongo.Collection
+		indexes []mongo.IndexModel // Yazmadan önce oluşturulur: Her tasarım kendi index bakım maliyetini öder
+		write   func(events []bucketEvent) error
+		read    func(q rangeQuery) (int, error)
+	}
+
+	flat := db.Collection("events_flat")
+	designs := []design{{
+		name:    "flat",
+		col:     flat,
+		indexes: []mongo.IndexModel{{Keys: bson.D{{Key: "storeId", Value: 1}, {Key: "ts", Value: 1}}}},
+		write: func(events []bucketEvent) error {
+			docs := make([]interface{}, len(events))
+			for i, e := range events {
+				docs[i] = e
+			}
+			_, err := flat.InsertMany(ctx, docs, options.InsertMany().SetOrdered(false))
+			return err
+		},
+		read: func(q rangeQuery) (int, error) {
+			cursor, err := flat.Find(ctx, bson.M{"storeId": q.store, "ts": bson.M{"$gte": q.start, "$lt": q.end}})
+			if err != nil {
+				return 0, err
+			}
+			var docs []bucketEvent
+			err = cursor.All(ctx, &docs)
+			return len(docs), err
+		},
+	}}
+	for _, size := range sizes {
+		col := db.Collection(fmt.Sprintf("events_bucket_%d", size))
+		designs = append(designs, design{
+			name: fmt.Sprintf("bucket-%d", size),
+			col:  col,
+			indexes: []mongo.IndexModel{
+				{Keys: bson.D{{Key: "storeId", Value: 1}, {Key: "count", Value: 1}}},
+				{Keys: bson.D{{Key: "storeId", Value: 1}, {Key: "first", Value: 1}}},
+			},
+			write: func(events []bucketEvent) error {
+				// Sıralı: Aynı mağazanın art arda olayları aynı bucket'ı doldurur, dolunca upsert yenisini açar
+				models := make([]mongo.WriteModel, len(events))
+				for i, e := range events {
+					models[i] = mongo.NewUpdateOneModel().
+						SetFilter(bson.M{"storeId": e.StoreID, "count": bson.M{"$lt": size}}).
+						SetUpdate(bson.M{
+							"$push": bson.M{"events": bson.M{"ts": e.TS, "type": e.Type, "amount": e.Amount}},
+							"$inc":  bson.M{"count": 1},
+							"$min":  bson.M{"first": e.TS},
+							"$max":  bson.M{"last": e.TS},
+						}).
+						SetUpsert(true)
+				}
+				_, err := col.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(true))
+				return err
+			},
+			read: func(q rangeQuery) (int, error) {
+				// Pencereyle kesişen bucket'lar: first < end ve last >= start
+				cursor, err := col.Find(ctx, bson.M{"storeId": q.store, "first": bson.M{"$lt": q.end}, "last": bson.M{"$gte": q.start}})
+				if err != nil {
+					return 0, err
+				}
+				var docs []bucketDoc
+				if err := cursor.All(ctx, &docs); err != nil {
+					return 0, err
+				}
+				n := 0
+				for _, d := range docs {
+					for _, e := range d.Events {
+						if !e.TS.Before(q.start) && e.TS.Before(q.end) {
+							n++
+						}
+					}
+				}
+				return n, nil
+			},
+		})
+	}
+
+	var writes, reads []latencyCase
+	for _, d := range designs {
+		logger.Printf("\n▶️  %s (%s)...\n", d.name, d.col.Name())
+		if HandleError(logger, "drop "+d.col.Name(), d.col.Drop(ctx)) {
+			continue
+		}
+		if _, err := d.col.Indexes().CreateMany(ctx, d.indexes); HandleError(logger, "index "+d.col.Name(), err) {
+			continue
+		}
+
+		w := measureLatency("write/"+d.name, batches, func(i int) (int, error) {
+			events := eventsOf(i)
+			return len(events), d.write(events)
+		}, logger)
+		eventsPerSec := float64(w.docs) / w.duration.Seconds()
+		logger.Printf("  ✍️  Yazma: %v, %.0f olay/sn, batch p50 %v p99 %v\n", w.duration.Round(time.Millisecond), eventsPerSec,
+			w.Summary().P50.Round(time.Microsecond), w.Summary().P99.Round(time.Microsecond))
+		record := latencyRecord("bucket", w)
+		record.DocsPerSec = eventsPerSec
+		logger.WriteRecord(record)
+		writes = append(writes, w)
+
+		r := measureLatency("read/"+d.name, *queries, func(i int) (int, error) { return d.read(ranges[i]) }, logger)
+		logger.Printf("  📖 Okuma: p50 %v, p99 %v, sorgu başına %.1f olay\n",
+			r.Summary().P50.Round(time.Microsecond), r.Summary().P99.Round(time.Microsecond), float64(r.docs)/float64(*queries))
+		writeLatencyRecord("bucket", r, logger)
+		reads = append(reads, r)
+	}
+	if len(writes) == 0 {
+		PrintErrorSummary(logger)
+		return
+	}
+
+	logger.Println("\n=== YAZMA (batch gecikmesi, p50 x: flat'e göre) ===")
+	printLatencyTable(writes, "write/flat", logger)
+	logger.Printf("\n  %-24s %12s\n", "tasarım", "olay/sn")
+	for _, w := range writes {
+		logger.Printf("  %-24s %12.0f\n", w.name, float64(w.docs)/w.duration.Seconds())
+	}
+	logger.Println("\n=== ARALIK OKUMA (p50 x: flat'e göre) ===")
+	printLatencyTable(reads, "read/flat", logger)
+	for _, r := range reads[1:] {
+		if r.docs != reads[0].docs {
+			logger.Printf("⚠️  %s %d olay döndü, flat %d - tasarımlar aynı olayları içermiyor\n", r.name, r.docs, reads[0].docs)
+		}
+	}
+
+	logger.Println("\n💾 Depolama:")
+	logger.Printf("  %-22s %10s %14s %14s %12s\n", "koleksiyon", "doküman", "ort. boyut", "veri MB", "index MB")
+	for _, d := range designs {
+		stats, err := CollectStorageStats(ctx, d.col)
+		if HandleError(logger, "collStats "+d.col.Name(), err) {
+			continue
+		}
+		logger.Printf("  %-22s %10d %12d B %14.1f %12.1f\n", d.col.Name(), stats.Count, stats.AvgObjSize,
+			float64(stats.DataSize)/(1024*1024), float64(stats.TotalIndexSize)/(1024*1024))
+	}
+	logger.Println("\n💡 Bucket'lar index girdisini ~N kat azaltır ve aralık okumasını az sayıda dokümana indirir;")
+	logger.Println("   pencere bucket'tan çok küçükse okunan ama atılan olaylar (KB/op) maliyeti artırır.")
+	logger.Println("💡 MongoDB 5.0+ time series koleksiyonları bu deseni sunucu tarafında otomatik uygular.")
+
+	PrintErrorSummary(logger)
+
+	logger.Println("\n✅ Test tamamlandı! Sonuçlar 'bucket_results.txt' dosyasına kaydedildi.")
+}
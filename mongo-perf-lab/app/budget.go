@@ -0,0 +1,260 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// budget.go - İstek bütçesi: Tek bir toplam deadline'ın Mongo çağrı aşamalarına bölünmesi
+// Üretim servisleri bir API isteğine toplam süre verir (ör: 200ms) ve bu süreyi isteğin Mongo
+// çağrıları arasında paylaştırır. Her çağrıya sabit bir timeout vermek yerine aşamalar sırayla
+// kümülatif deadline alır: count %15'lik payını kullanmazsa artan süre sonraki aşamalara kalır,
+// ama count payını aşarsa getMore'un süresini yiyemez, istek count'ta kesilir.
+//
+//	-budget 200ms -budget-split count=15,explain=15,find=30,getMore=40
+//	  count   → başlangıç + 30ms
+//	  explain → başlangıç + 60ms
+//	  find    → başlangıç + 120ms
+//	  getMore → başlangıç + 200ms (toplam deadline)
+//
+// Workload runner her işlem için bir BudgetRun başlatır ve context ile işleme verir
+// (BudgetFrom); işlem Mongo çağrılarını run.Phase içinde yapar. Deadline aşılınca hangi aşamada
+// kesildiği ve bütçeyi en çok hangi aşamanın tükettiği raporlanır.
+
+// budgetPhases - İstek aşamaları, çalışma sırasıyla
+var budgetPhases = []string{"count", "explain", "find", "getMore"}
+
+// defaultBudgetSplit - -budget-split varsayılanı (yüzde)
+const defaultBudgetSplit = "count=15,explain=15,find=30,getMore=40"
+
+// RequestBudget - Toplam süre ve aşamaların payları
+type RequestBudget struct {
+	Total  time.Duration
+	Shares []float64 // budgetPhases sırasıyla yüzde (toplam 100)
+}
+
+// ParseRequestBudget - "count=15,find=45,getMore=40" formatındaki paylaşımı ayrıştırır
+// Verilmeyen aşamanın payı 0'dır (önceki aşamalardan artan süreyi kullanabilir);
+// paylar toplamı 100 değilse orantılı olarak 100'e ölçeklenir
+func ParseRequestBudget(total time.Duration, split string) (*RequestBudget, error) {
+	if total <= 0 {
+		return nil, fmt.Errorf("toplam süre pozitif olmalı: %v", total)
+	}
+	budget := &RequestBudget{Total: total, Shares: make([]float64, len(budgetPhases))}
+	sum := 0.0
+	for _, part := range strings.Split(split, ",") {
+		name, value, found := strings.Cut(strings.TrimSpace(part), "=")
+		i := slices.Index(budgetPhases, name)
+		if !found || i < 0 {
+			return nil, fmt.Errorf("%q: aşama=yüzde bekleniyor (%s)", part, strings.Join(budgetPhases, ", "))
+		}
+		share, err := strconv.ParseFloat(value, 64)
+		if err != nil || share < 0 {
+			return nil, fmt.Errorf("%q: geçersiz yüzde", part)
+		}
+		budget.Shares[i] = share
+		sum += share
+	}
+	if sum <= 0 {
+		return nil, fmt.Errorf("paylar toplamı pozitif olmalı: %q", split)
+	}
+	for i := range budget.Shares {
+		budget.Shares[i] *= 100 / sum
+	}
+	return budget, nil
+}
+
+// Allowance - Aşamanın istek başından itibaren kümülatif deadline'ı
+func (b *RequestBudget) Allowance(phase int) time.Duration {
+	if phase == len(budgetPhases)-1 {
+		return b.Total // Yuvarlama hatası son aşamayı kısaltmasın
+	}
+	cum := 0.0
+	for _, share := range b.Shares[:phase+1] {
+		cum += share
+	}
+	return time.Duration(float64(b.Total) * cum / 100)
+}
+
+func (b *RequestBudget) String() string {
+	parts := make([]string, len(budgetPhases))
+	for i, name := range budgetPhases {
+		parts[i] = fmt.Sprintf("%s %%%.0f", name, b.Shares[i])
+	}
+	return fmt.Sprintf("%v (%s)", b.Total, strings.Join(parts, ", "))
+}
+
+// BudgetExceededError - İstek deadline'ı bir aşamada aşıldı
+type BudgetExceededError struct {
+	Phase   string
+	Elapsed time.Duration // İstek başından aşılana kadar geçen süre
+	Err     error
+}
+
+func (e *BudgetExceededError) Error() string {
+	return fmt.Sprintf("bütçe %s aşamasında aşıldı (%v): %v", e.Phase, e.Elapsed.Round(time.Microsecond), e.Err)
+}
+
+func (e *BudgetExceededError) Unwrap() error { return e.Err }
+
+// BudgetRun - Tek bir isteğin bütçe kullanımı
+type BudgetRun struct {
+	budget   *RequestBudget
+	start    time.Time
+	spent    []time.Duration // Aşama başına harcanan süre
+	ran      []bool
+	exceeded int // Deadline'ın aşıldığı aşama (-1 = aşılmadı)
+}
+
+// Start - Yeni bir istek için bütçe sayacını başlatır
+func (b *RequestBudget) Start() *BudgetRun {
+	return &BudgetRun{
+		budget:   b,
+		start:    time.Now(),
+		spent:    make([]time.Duration, len(budgetPhases)),
+		ran:      make([]bool, len(budgetPhases)),
+		exceeded: -1,
+	}
+}
+
+// budgetKey - BudgetRun'ın context anahtarı
+type budgetKey struct{}
+
+// WithBudgetRun - İşleme verilen context'e isteğin bütçesini ekler
+func WithBudgetRun(ctx context.Context, run *BudgetRun) context.Context {
+	return context.WithValue(ctx, budgetKey{}, run)
+}
+
+// BudgetFrom - Context'teki bütçe (yoksa nil; nil BudgetRun'ın Phase'i bütçesiz çalışır)
+func BudgetFrom(ctx context.Context) *BudgetRun {
+	run, _ := ctx.Value(budgetKey{}).(*BudgetRun)
+	return run
+}
+
+// Phase - fn'i aşamanın kümülatif deadline'ı ile çalıştırır ve harcanan süreyi kaydeder
+// Deadline aşılırsa *BudgetExceededError döner; r nil ise fn ctx ile olduğu gibi çalışır
+func (r *BudgetRun) Phase(ctx context.Context, name string, fn func(ctx context.Context) error) error {
+	if r == nil {
+		return fn(ctx)
+	}
+	i := slices.Index(budgetPhases, name)
+	if i < 0 {
+		return fmt.Errorf("bilinmeyen bütçe aşaması: %s", name)
+	}
+	phaseCtx, cancel := context.WithDeadline(ctx, r.start.Add(r.budget.Allowance(i)))
+	defer cancel()
+
+	phaseStart := time.Now()
+	err := fn(phaseCtx)
+	r.spent[i] += time.Since(phaseStart)
+	r.ran[i] = true
+	if err != nil && phaseCtx.Err() == context.DeadlineExceeded && (mongo.IsTimeout(err) || errors.Is(err, context.DeadlineExceeded)) {
+		r.exceeded = i
+		return &BudgetExceededError{Phase: name, Elapsed: time.Since(r.start), Err: err}
+	}
+	return err
+}
+
+// budgetPhaseStats - Bir aşamanın tüm işlemlerdeki bütçe kullanımı
+type budgetPhaseStats struct {
+	runs     int // Aşamanın çalıştığı işlem sayısı
+	spent    time.Duration
+	max      time.Duration
+	exceeded int // Deadline'ın bu aşamada aşıldığı işlemler
+	consumer int // Aşılan işlemlerde bütçenin en büyük kısmını bu aşamanın harcadığı işlemler
+}
+
+// BudgetStats - Workload boyunca bütçe kullanımı (worker'lardan eşzamanlı Record edilir)
+type BudgetStats struct {
+	Budget   *RequestBudget
+	mu       sync.Mutex
+	ops      int
+	exceeded int
+	phases   []budgetPhaseStats
+}
+
+// NewBudgetStats - Boş istatistik
+func NewBudgetStats(budget *RequestBudget) *BudgetStats {
+	return &BudgetStats{Budget: budget, phases: make([]budgetPhaseStats, len(budgetPhases))}
+}
+
+// Record - Biten isteğin aşama sürelerini ekler
+// Aşılan istekte "tüketici", deadline'ın dolduğu aşama değil en çok süre harcayan aşamadır:
+// getMore'da kesilen bir istekte süreyi count yemiş olabilir
+func (s *BudgetStats) Record(run *BudgetRun) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ops++
+	consumer := 0
+	for i, spent := range run.spent {
+		if !run.ran[i] {
+			continue
+		}
+		p := &s.phases[i]
+		p.runs++
+		p.spent += spent
+		p.max = max(p.max, spent)
+		if spent > run.spent[consumer] {
+			consumer = i
+		}
+	}
+	if run.exceeded >= 0 {
+		s.exceeded++
+		s.phases[run.exceeded].exceeded++
+		s.phases[consumer].consumer++
+	}
+}
+
+// Exceeded - Aşama başına deadline aşımı sayısı (metrik kaydı için; aşım yoksa nil)
+func (s *BudgetStats) Exceeded() map[string]int {
+	if s.exceeded == 0 {
+		return nil
+	}
+	counts := map[string]int{}
+	for i, p := range s.phases {
+		if p.exceeded > 0 {
+			counts[budgetPhases[i]] = p.exceeded
+		}
+	}
+	return counts
+}
+
+// PrintBudgetReport - Aşama başına bütçe kullanımını ve aşımları yazdırır
+func PrintBudgetReport(s *BudgetStats, logger *Logger) {
+	logger.Printf("\n⏳ İstek Bütçesi: %s\n", s.Budget)
+	logger.Printf("  %-8s %6s %10s %12s %12s %8s %9s\n",
+		"aşama", "pay", "deadline", "ort. süre", "max süre", "aşım", "tüketici")
+	for i, p := range s.phases {
+		avg := time.Duration(0)
+		if p.runs > 0 {
+			avg = p.spent / time.Duration(p.runs)
+		}
+		logger.Printf("  %-8s %5.0f%% %10v %12v %12v %8d %9d\n", budgetPhases[i], s.Budget.Shares[i],
+			s.Budget.Allowance(i), avg.Round(time.Microsecond), p.max.Round(time.Microsecond), p.exceeded, p.consumer)
+	}
+	if s.exceeded == 0 {
+		logger.Printf("  ✅ %d isteğin hiçbiri bütçeyi aşmadı\n", s.ops)
+		return
+	}
+
+	worst, top := 0, 0
+	for i, p := range s.phases {
+		if p.consumer > s.phases[top].consumer {
+			top = i
+		}
+		if p.exceeded > s.phases[worst].exceeded {
+			worst = i
+		}
+	}
+	logger.Printf("  🚨 %d/%d istek bütçeyi aştı (%.1f%%): en çok %s aşamasında kesildi (%d), bütçeyi en çok %s tüketti (%d istekte)\n",
+		s.exceeded, s.ops, float64(s.exceeded)/float64(s.ops)*100,
+		budgetPhases[worst], s.phases[worst].exceeded, budgetPhases[top], s.phases[top].consumer)
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"benchkit"
+)
+
+// chunktune.go - Paralel okumada chunk boyutunun otomatik ayarı
+// read_v4 tarzı okumalarda her worker $skip/$limit ile bir chunk okur. En iyi chunk boyutu worker
+// sayısına, veri boyutuna ve sunucuya bağlıdır: Büyük chunk'ta worker'lar dengesiz biter,
+// küçük chunk'ta her chunk yeni bir aggregate ve artan $skip maliyeti demektir.
+// Sabit bir değer (eski 100000) yerine ölçümden önce adaylar kısa kalibrasyon turlarıyla denenir:
+// Her aday aynı okuma yolundan en fazla probeDocs doküman okur, en yüksek doküman/sn seçilir.
+// Kalibrasyon verisi metrik kaydına (chunkTuning) ve JSON özete yazılır (tipler analyzer.go'da:
+// QueryMetrics'in parçası oldukları için her script'le derlenirler).
+
+// ChunkCandidates - Denenecek chunk boyutları, büyükten küçüğe
+// list boşsa worker başına düşen pay ve onun 1/2, 1/4, 1/8'i (en az 1000) denenir:
+// En büyük aday her worker'a tek chunk verir (eski read_v4 davranışı)
+func ChunkCandidates(list string, total int64, workers int) ([]int64, error) {
+	var candidates []int64
+	if list != "" {
+		values, err := parseIntList(list)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range values {
+			candidates = append(candidates, int64(v))
+		}
+	} else {
+		share := max((total+int64(workers)-1)/int64(workers), 1000)
+		for _, div := range []int64{1, 2, 4, 8} {
+			candidates = append(candidates, max(share/div, 1000))
+		}
+	}
+	slices.Sort(candidates)
+	slices.Reverse(candidates)
+	return slices.Compact(candidates), nil
+}
+
+// TuneChunkSize - Adayları sırayla dener ve en yüksek doküman/sn veren boyutu seçer
+// read: chunkSize ile en fazla limit doküman okuyan tur (ölçülen okumayla aynı kod yolu)
+// Hiçbir aday başarılı olmazsa ilk aday seçilir
+func TuneChunkSize(logger *Logger, workers int, probeDocs int64, candidates []int64, read func(chunkSize, limit int64) (int64, error)) *ChunkTuning {
+	tuning := &ChunkTuning{Workers: workers, ProbeDocs: probeDocs, Chosen: candidates[0]}
+	logger.Printf("\n🎛️  Chunk boyutu kalibrasyonu: %d worker, aday başına en fazla %d doküman\n", workers, probeDocs)
+	logger.Printf("  %10s %10s %12s %14s\n", "chunk", "doküman", "süre", "doküman/sn")
+
+	best := 0.0
+	for _, size := range candidates {
+		start := time.Now()
+		docs, err := read(size, probeDocs)
+		elapsed := time.Since(start)
+		probe := ChunkProbe{ChunkSize: size, Docs: docs, DurationMs: benchkit.Millis(elapsed)}
+		if err != nil {
+			probe.Error = err.Error()
+			logger.Printf("  %10d ⚠️  %v\n", size, err)
+		} else {
+			if elapsed > 0 {
+				probe.DocsPerSec = float64(docs) / elapsed.Seconds()
+			}
+			logger.Printf("  %10d %10d %12v %14.0f\n", size, docs, elapsed.Round(time.Millisecond), probe.DocsPerSec)
+			if probe.DocsPerSec > best {
+				best, tuning.Chosen = probe.DocsPerSec, size
+			}
+		}
+		tuning.Probes = append(tuning.Probes, probe)
+	}
+	logger.Printf("  ✅ Seçilen chunk boyutu: %d\n", tuning.Chosen)
+	return tuning
+}
+
+// String - "25000 (kalibrasyonla, 4 aday)" biçiminde özet
+func (t *ChunkTuning) String() string {
+	if t.Fixed {
+		return fmt.Sprintf("%d (parametreyle)", t.Chosen)
+	}
+	return fmt.Sprintf("%d (kalibrasyonla, %d aday)", t.Chosen, len(t.Probes))
+}
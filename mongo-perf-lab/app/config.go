@@ -0,0 +1,563 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"gopkg.in/yaml.v3"
+)
+
+// config.go - İsimli ortam (environment) profilleri
+// Aynı manifest'ler laptop'ta, staging'de ve Atlas'ta değişmeden çalışabilsin diye
+// ortama özel her şey (bağlantı, kimlik bilgisi kaynağı, veri seti boyutu, eşikler)
+// perflab.yaml'daki environments altında tanımlanır ve ortam adıyla seçilir:
+//
+//	perflab run -f experiments/paid_orders.yaml --env staging
+//	PERFLAB_ENV=staging go run main.go config.go ... read_v3.go
+//
+// perflab, seçilen ortamı PERFLAB_ENV ile çalıştırdığı script'lere iletir.
+//
+// Kimlik bilgileri asla URI'de taşınmaz: credentials altındaki kullanıcı adı ve şifre
+// bir kaynaktan okunur (env:DEGISKEN veya file:/run/secrets/mongo_password) ve SetAuth ile verilir.
+// Okunan her değer maskeleme listesine eklenir; Logger, hata özeti ve sonuç hedefleri
+// yazmadan önce Redact'tan geçirir - şifre log'a, rapora veya uzak depoya düşmez.
+// Config dosyası yoksa ve ortam seçilmemişse localhost'a (eski davranış) bağlanılır.
+// Örnek profiller: perflab.yaml
+//
+// Bağlantı ayarları (uri, database, collection, havuz, timeout'lar, TLS) profilden sonra
+// PERFLAB_MONGO_* ortam değişkenleriyle, en son da script'in -mongo-* parametreleriyle ezilebilir
+// (bkz. connectionSettings) - staging'e veya Atlas'a bakmak için yeniden derleme gerekmez:
+//
+//	go run main.go config.go ... read_v3.go -mongo-uri mongodb+srv://perf.example.net -mongo-tls
+//	PERFLAB_MONGO_POOL_SIZE=20 perflab run -f experiments/paid_orders.yaml
+
+// defaultConfigFile - PERFLAB_CONFIG verilmezse okunan dosya (app klasörüne göre)
+const defaultConfigFile = "perflab.yaml"
+
+// Config - perflab.yaml'ın tamamı
+type Config struct {
+	Default      string                  `yaml:"default"` // --env / PERFLAB_ENV verilmezse kullanılan ortam
+	Environments map[string]*Environment `yaml:"environments"`
+}
+
+// Environment - Tek bir ortamın bağlantı tanımı
+// Ortamın deneye etkisi (dataset, thresholds) aynı dosyadan manifest.go'da okunur:
+// Bağlantı her script'e lazım, deney ayarları sadece perflab'a
+type Environment struct {
+	Name        string           `yaml:"-"`
+	URI         string           `yaml:"uri"`         // Kimlik bilgisi içermeyen bağlantı adresi
+	Database    string           `yaml:"database"`    // Varsayılan perfdb
+	Collection  string           `yaml:"collection"`  // GetMongo'nun döndürdüğü koleksiyon, varsayılan orders
+	Credentials *CredentialsSpec `yaml:"credentials"` // nil = kimlik doğrulama yok
+	Pool        PoolSpec         `yaml:"pool"`
+	Timeouts    TimeoutSpec      `yaml:"timeouts"`
+	TLS         *TLSSpec         `yaml:"tls"` // nil = URI'deki ayar (mongodb+srv varsayılan olarak TLS kullanır)
+}
+
+// PoolSpec - Bağlantı havuzu boyutu (0 = varsayılan: max 100, min 0)
+type PoolSpec struct {
+	MaxSize uint64 `yaml:"maxSize"`
+	MinSize uint64 `yaml:"minSize"`
+}
+
+// TimeoutSpec - Driver timeout'ları ("10s", "500ms"; 0 = driver varsayılanı)
+type TimeoutSpec struct {
+	Connect         time.Duration `yaml:"connect"`         // TCP + TLS + handshake
+	ServerSelection time.Duration `yaml:"serverSelection"` // Uygun sunucu bulunana kadar beklenen süre
+	Socket          time.Duration `yaml:"socket"`          // Tek bir okuma/yazmanın network'te bekleyebileceği süre
+}
+
+// TLSSpec - TLS ayarları; dosyalar PEM formatındadır
+type TLSSpec struct {
+	Enabled            bool   `yaml:"enabled"`
+	CAFile             string `yaml:"caFile"`             // Sunucu sertifikasını imzalayan CA (boş = sistem CA'ları)
+	CertificateKeyFile string `yaml:"certificateKeyFile"` // Client sertifikası + private key (x.509 auth)
+	Insecure           bool   `yaml:"insecure"`           // Sertifika doğrulanmaz - sadece self-signed test sunucuları için
+}
+
+// defaultMaxPoolSize - Profil/parametre verilmezse havuz boyutu (eski sabit değer)
+const defaultMaxPoolSize = 100
+
+// CredentialsSpec - Kullanıcı adı/şifrenin nereden okunacağı
+// Değerler doğrudan yazılmaz, kaynak referansıdır:
+//   - "env:STAGING_MONGO_PASSWORD": Ortam değişkeni
+//   - "file:/run/secrets/mongo_password": Dosya (Docker/Kubernetes secret mount'u) - sondaki satır sonu atılır
+type CredentialsSpec struct {
+	Username   string `yaml:"username"`
+	Password   string `yaml:"password"`
+	AuthSource string `yaml:"authSource"` // Varsayılan admin
+}
+
+// localEnvironment - Config dosyası olmadan kullanılan ortam
+var localEnvironment = &Environment{Name: "local", URI: "mongodb://localhost:27017", Database: "perfdb", Collection: "orders"}
+
+// LoadConfig - Config dosyasını okur ve doğrular
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var c Config
+	if err := yaml.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("%s parse edilemedi: %v", path, err)
+	}
+	for name, env := range c.Environments {
+		if env == nil {
+			return nil, fmt.Errorf("%s: ortam boş olamaz", name)
+		}
+		env.Name = name
+		if env.Database == "" {
+			env.Database = "perfdb"
+		}
+		if env.Collection == "" {
+			env.Collection = "orders"
+		}
+		if err := env.Validate(); err != nil {
+			return nil, fmt.Errorf("%s: %v", name, err)
+		}
+	}
+	if c.Default != "" && c.Environments[c.Default] == nil {
+		return nil, fmt.Errorf("default ortam tanımlı değil: %s", c.Default)
+	}
+	return &c, nil
+}
+
+// Validate - Bağlantı tanımını kontrol eder (kimlik bilgisi kaynakları dahil, değerler okunmaz)
+func (e *Environment) Validate() error {
+	if e.URI == "" {
+		return fmt.Errorf("uri boş olamaz")
+	}
+	if _, password, ok := uriCredentials(e.URI); ok && password != "" {
+		return fmt.Errorf("uri şifre içeremez (%s) - credentials.password ile env: veya file: kaynağı kullanın", Redact(e.URI))
+	}
+	if c := e.Credentials; c != nil {
+		for _, ref := range []string{c.Username, c.Password} {
+			if _, _, err := parseSecretRef(ref); err != nil {
+				return fmt.Errorf("credentials: %v", err)
+			}
+		}
+	}
+	if e.Pool.MaxSize > 0 && e.Pool.MinSize > e.Pool.MaxSize {
+		return fmt.Errorf("pool.minSize (%d) pool.maxSize'dan (%d) büyük olamaz", e.Pool.MinSize, e.Pool.MaxSize)
+	}
+	if e.Timeouts.Connect < 0 || e.Timeouts.ServerSelection < 0 || e.Timeouts.Socket < 0 {
+		return fmt.Errorf("timeouts negatif olamaz")
+	}
+	if t := e.TLS; t != nil && !t.Enabled && (t.CAFile != "" || t.CertificateKeyFile != "" || t.Insecure) {
+		return fmt.Errorf("tls ayarları verilmiş ama tls.enabled false")
+	}
+	return nil
+}
+
+// Get - Adı verilen ortamı döndürür; ad boşsa default ortamı
+func (c *Config) Get(name string) (*Environment, error) {
+	if name == "" {
+		name = c.Default
+	}
+	if name == "" {
+		return localEnvironment, nil
+	}
+	env, ok := c.Environments[name]
+	if !ok {
+		names := make([]string, 0, len(c.Environments))
+		for n := range c.Environments {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("bilinmeyen ortam %q (%s)", name, strings.Join(names, ", "))
+	}
+	return env, nil
+}
+
+// ConfigPath - Okunan config dosyası: PERFLAB_CONFIG, yoksa perflab.yaml
+func ConfigPath() string {
+	if path := os.Getenv("PERFLAB_CONFIG"); path != "" {
+		return path
+	}
+	return defaultConfigFile
+}
+
+var (
+	envOnce    sync.Once
+	currentEnv *Environment
+	envErr     error
+)
+
+// SelectEnvironment - PERFLAB_CONFIG ve PERFLAB_ENV'e göre ortamı seçer
+// Config dosyası yoksa sadece ortam seçilmemişse hata değildir (localhost)
+func SelectEnvironment() (*Environment, error) {
+	envOnce.Do(func() {
+		name := os.Getenv("PERFLAB_ENV")
+		config, err := LoadConfig(ConfigPath())
+		if os.IsNotExist(err) && name == "" {
+			currentEnv = localEnvironment
+			return
+		}
+		if err != nil {
+			envErr = fmt.Errorf("config okunamadı: %v", err)
+			return
+		}
+		currentEnv, envErr = config.Get(name)
+	})
+	if envErr != nil {
+		return nil, envErr
+	}
+	return currentEnv.withOverrides()
+}
+
+// CurrentEnvironment - Seçili ortam; seçilemiyorsa process'i sonlandırır
+// (GetMongo'nun bağlantı hatasındaki davranışıyla aynı: ortam yoksa ölçüm de yok)
+func CurrentEnvironment() *Environment {
+	env, err := SelectEnvironment()
+	if err != nil {
+		log.Fatal(err)
+	}
+	return env
+}
+
+// ClientOptions - Ortamın bağlantı ayarları (izleyiciler olmadan)
+// Kimlik bilgileri URI'ye eklenmez, SetAuth ile ayrı verilir
+func (e *Environment) ClientOptions() (*options.ClientOptions, error) {
+	opts := options.Client().ApplyURI(e.URI)
+	if c := e.Credentials; c != nil {
+		username, err := resolveSecret(c.Username)
+		if err != nil {
+			return nil, fmt.Errorf("kullanıcı adı: %v", err)
+		}
+		password, err := resolveSecret(c.Password)
+		if err != nil {
+			return nil, fmt.Errorf("şifre: %v", err)
+		}
+		opts.SetAuth(options.Credential{Username: username, Password: password, AuthSource: c.AuthSource})
+	}
+
+	maxPool := e.Pool.MaxSize
+	if maxPool == 0 {
+		maxPool = defaultMaxPoolSize
+	}
+	opts.SetMaxPoolSize(maxPool).SetMinPoolSize(e.Pool.MinSize)
+	if t := e.Timeouts.Connect; t > 0 {
+		opts.SetConnectTimeout(t)
+	}
+	if t := e.Timeouts.ServerSelection; t > 0 {
+		opts.SetServerSelectionTimeout(t)
+	}
+	if t := e.Timeouts.Socket; t > 0 {
+		opts.SetSocketTimeout(t)
+	}
+	if e.TLS != nil && e.TLS.Enabled {
+		config, err := e.TLS.Config()
+		if err != nil {
+			return nil, fmt.Errorf("tls: %v", err)
+		}
+		opts.SetTLSConfig(config)
+	}
+	return opts, nil
+}
+
+// Config - TLSSpec'ten driver'a verilecek tls.Config'i oluşturur
+func (t *TLSSpec) Config() (*tls.Config, error) {
+	config := &tls.Config{InsecureSkipVerify: t.Insecure}
+	if t.CAFile != "" {
+		data, err := os.ReadFile(t.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("caFile okunamadı: %v", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("caFile PEM sertifika içermiyor: %s", t.CAFile)
+		}
+		config.RootCAs = pool
+	}
+	if t.CertificateKeyFile != "" {
+		// MongoDB'nin tlsCertificateKeyFile'ı gibi: Sertifika ve key aynı dosyada
+		cert, err := tls.LoadX509KeyPair(t.CertificateKeyFile, t.CertificateKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("certificateKeyFile: %v", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+	return config, nil
+}
+
+// connectionSetting - Profildeki bir bağlantı ayarını ezen parametre / ortam değişkeni çifti
+type connectionSetting struct {
+	flag   string // Script parametresi (-mongo-uri)
+	env    string // Ortam değişkeni (PERFLAB_MONGO_URI) - perflab run script'lere bununla aktarır
+	usage  string
+	apply  func(e *Environment, value string) error
+	isBool bool // Değersiz verilebilir (-mongo-tls)
+}
+
+// connectionSettings - Ezilebilen bağlantı ayarları (öncelik: parametre > ortam değişkeni > profil)
+var connectionSettings = []connectionSetting{
+	{"mongo-uri", "PERFLAB_MONGO_URI", "MongoDB bağlantı adresi (şifre içeremez, bkz. perflab.yaml credentials)",
+		func(e *Environment, v string) error { e.URI = v; return nil }, false},
+	{"mongo-db", "PERFLAB_MONGO_DATABASE", "Veritabanı adı",
+		func(e *Environment, v string) error { e.Database = v; return nil }, false},
+	{"mongo-collection", "PERFLAB_MONGO_COLLECTION", "GetMongo'nun döndürdüğü koleksiyon",
+		func(e *Environment, v string) error { e.Collection = v; return nil }, false},
+	{"mongo-pool-size", "PERFLAB_MONGO_POOL_SIZE", "Bağlantı havuzunun en fazla bağlantı sayısı",
+		func(e *Environment, v string) error { return parseUintSetting(v, &e.Pool.MaxSize) }, false},
+	{"mongo-min-pool-size", "PERFLAB_MONGO_MIN_POOL_SIZE", "Havuzda açık tutulan en az bağlantı sayısı",
+		func(e *Environment, v string) error { return parseUintSetting(v, &e.Pool.MinSize) }, false},
+	{"mongo-connect-timeout", "PERFLAB_MONGO_CONNECT_TIMEOUT", "Bağlantı kurma timeout'u (ör: 10s)",
+		func(e *Environment, v string) error { return parseDurationSetting(v, &e.Timeouts.Connect) }, false},
+	{"mongo-server-selection-timeout", "PERFLAB_MONGO_SERVER_SELECTION_TIMEOUT", "Sunucu seçme timeout'u (ör: 30s)",
+		func(e *Environment, v string) error { return parseDurationSetting(v, &e.Timeouts.ServerSelection) }, false},
+	{"mongo-socket-timeout", "PERFLAB_MONGO_SOCKET_TIMEOUT", "Tek okuma/yazma timeout'u (ör: 1m)",
+		func(e *Environment, v string) error { return parseDurationSetting(v, &e.Timeouts.Socket) }, false},
+	{"mongo-tls", "PERFLAB_MONGO_TLS", "TLS kullan (true/false)",
+		func(e *Environment, v string) error { return parseBoolSetting(v, &e.tlsSpec().Enabled) }, true},
+	{"mongo-tls-ca", "PERFLAB_MONGO_TLS_CA_FILE", "TLS CA dosyası (PEM), TLS'i açar",
+		func(e *Environment, v string) error { e.tlsSpec().Enabled, e.tlsSpec().CAFile = true, v; return nil }, false},
+	{"mongo-tls-cert", "PERFLAB_MONGO_TLS_CERT_KEY_FILE", "Client sertifikası + key dosyası (PEM), TLS'i açar",
+		func(e *Environment, v string) error {
+			e.tlsSpec().Enabled, e.tlsSpec().CertificateKeyFile = true, v
+			return nil
+		}, false},
+	{"mongo-tls-insecure", "PERFLAB_MONGO_TLS_INSECURE", "Sunucu sertifikasını doğrulama (sadece test), TLS'i açar",
+		func(e *Environment, v string) error {
+			t := e.tlsSpec()
+			if err := parseBoolSetting(v, &t.Insecure); err != nil {
+				return err
+			}
+			t.Enabled = t.Enabled || t.Insecure
+			return nil
+		}, true},
+}
+
+// connectionFlags - Script'te verilen -mongo-* parametrelerinin değerleri (parametre adı → değer)
+var connectionFlags = map[string]string{}
+
+// RegisterConnectionFlags - -mongo-* parametrelerini flag set'e ekler
+// Script'ler için init'te flag.CommandLine'a eklenir; perflab run kendi flag set'ine ekler
+// ve verilen değerleri ExportConnectionFlags ile script'lere aktarır.
+func RegisterConnectionFlags(fs *flag.FlagSet) {
+	for _, s := range connectionSettings {
+		s := s
+		apply := func(v string) error {
+			// Değer parametre okunurken doğrulanır: Hatalı değer kullanım mesajıyla birlikte raporlanır
+			if err := s.apply(&Environment{}, v); err != nil {
+				return err
+			}
+			connectionFlags[s.flag] = v
+			return nil
+		}
+		if s.isBool {
+			fs.BoolFunc(s.flag, s.usage, apply)
+			continue
+		}
+		fs.Func(s.flag, s.usage, apply)
+	}
+}
+
+func init() {
+	RegisterConnectionFlags(flag.CommandLine)
+}
+
+// ExportConnectionFlags - Verilen -mongo-* parametrelerini ortam değişkeni olarak ayarlar
+// (perflab'ın çalıştırdığı script'ler os.Environ'u devralır)
+func ExportConnectionFlags() {
+	for _, s := range connectionSettings {
+		if v, ok := connectionFlags[s.flag]; ok {
+			os.Setenv(s.env, v)
+		}
+	}
+}
+
+// withOverrides - Ortamın PERFLAB_MONGO_* ve -mongo-* ile ezilmiş kopyası
+// Script -mongo-* parametrelerini flag.Parse'tan önce GetMongo'yu çağırarak kaçırmasın diye
+// parse edilmemişse burada parse edilir (parametresiz read_v* script'leri)
+func (e *Environment) withOverrides() (*Environment, error) {
+	if !flag.Parsed() {
+		flag.Parse()
+	}
+	env := *e
+	if e.TLS != nil {
+		tlsCopy := *e.TLS
+		env.TLS = &tlsCopy
+	}
+	for _, s := range connectionSettings {
+		source, value := s.env, os.Getenv(s.env)
+		if v, ok := connectionFlags[s.flag]; ok {
+			source, value = "-"+s.flag, v
+		}
+		if value == "" {
+			continue
+		}
+		if err := s.apply(&env, value); err != nil {
+			return nil, fmt.Errorf("%s: %v", source, err)
+		}
+	}
+	if err := env.Validate(); err != nil {
+		return nil, fmt.Errorf("%s (ezilen ayarlarla): %v", env.Name, err)
+	}
+	return &env, nil
+}
+
+// tlsSpec - Ezme sırasında TLS ayarı yoksa oluşturur
+func (e *Environment) tlsSpec() *TLSSpec {
+	if e.TLS == nil {
+		e.TLS = &TLSSpec{}
+	}
+	return e.TLS
+}
+
+func parseUintSetting(v string, out *uint64) error {
+	n, err := strconv.ParseUint(v, 10, 64)
+	if err != nil {
+		return fmt.Errorf("geçersiz sayı %q", v)
+	}
+	*out = n
+	return nil
+}
+
+func parseDurationSetting(v string, out *time.Duration) error {
+	d, err := time.ParseDuration(v)
+	if err != nil || d < 0 {
+		return fmt.Errorf("geçersiz süre %q (ör: 10s, 500ms)", v)
+	}
+	*out = d
+	return nil
+}
+
+func parseBoolSetting(v string, out *bool) error {
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return fmt.Errorf("geçersiz değer %q (true/false)", v)
+	}
+	*out = b
+	return nil
+}
+
+// parseSecretRef - "kaynak:ad" formatındaki referansı ayırır
+func parseSecretRef(ref string) (source, name string, err error) {
+	source, name, ok := strings.Cut(ref, ":")
+	if !ok || name == "" {
+		return "", "", fmt.Errorf("geçersiz kaynak %q (beklenen: env:DEGISKEN veya file:/yol)", ref)
+	}
+	switch source {
+	case "env", "file":
+		return source, name, nil
+	}
+	return "", "", fmt.Errorf("bilinmeyen kaynak %q (env, file)", source)
+}
+
+// resolveSecret - Referansın gösterdiği değeri okur ve maskeleme listesine ekler
+// Hata mesajları değeri değil, sadece kaynağın adını içerir
+func resolveSecret(ref string) (string, error) {
+	source, name, err := parseSecretRef(ref)
+	if err != nil {
+		return "", err
+	}
+	var value string
+	switch source {
+	case "env":
+		v, ok := os.LookupEnv(name)
+		if !ok {
+			return "", fmt.Errorf("%s ortam değişkeni tanımlı değil", name)
+		}
+		value = v
+	case "file":
+		data, err := os.ReadFile(name)
+		if err != nil {
+			return "", fmt.Errorf("%s okunamadı: %v", name, err)
+		}
+		// Secret dosyaları çoğunlukla echo/kubectl ile yazılır ve satır sonuyla biter
+		value = strings.TrimRight(string(data), "\r\n")
+	}
+	if value == "" {
+		return "", fmt.Errorf("%s boş", ref)
+	}
+	RegisterSecret(value)
+	return value, nil
+}
+
+// minSecretLength - Bu uzunluktan kısa değerler maskelenmez
+// ("a" gibi bir kullanıcı adını maskelemek log'daki her "a"yı bozar)
+const minSecretLength = 4
+
+var secrets struct {
+	mu     sync.Mutex
+	values []string
+}
+
+// RegisterSecret - Değeri maskeleme listesine ekler (Redact bundan sonra onu gizler)
+// Config dışından okunan gizli değerler (ör: S3 anahtarı) de buraya eklenmelidir
+func RegisterSecret(value string) {
+	if len(value) < minSecretLength {
+		return
+	}
+	secrets.mu.Lock()
+	defer secrets.mu.Unlock()
+	for _, v := range secrets.values {
+		if v == value {
+			return
+		}
+	}
+	secrets.values = append(secrets.values, value)
+	// Uzun değerler önce: Bir secret diğerini içeriyorsa kısmi maske kalmasın
+	sort.Slice(secrets.values, func(i, j int) bool { return len(secrets.values[i]) > len(secrets.values[j]) })
+}
+
+// redactedValue - Maskelenen değerin yerine yazılan metin
+const redactedValue = "****"
+
+// uriPasswordPattern - Metin içindeki URI'lerde kullanıcı:şifre@ kısmı
+var uriPasswordPattern = regexp.MustCompile(`([a-zA-Z][a-zA-Z0-9+.-]*://[^:/@\s]*):[^@\s/]*@`)
+
+// Redact - Metindeki kayıtlı gizli değerleri ve URI'lerdeki şifreleri maskeler
+// Kayıtlı değer yoksa ve metinde URI şifresi yoksa metni olduğu gibi döndürür
+func Redact(s string) string {
+	s = uriPasswordPattern.ReplaceAllString(s, "${1}:"+redactedValue+"@")
+	secrets.mu.Lock()
+	defer secrets.mu.Unlock()
+	for _, v := range secrets.values {
+		s = strings.ReplaceAll(s, v, redactedValue)
+	}
+	return s
+}
+
+// uriCredentials - URI'deki kullanıcı adı ve şifre (ok = URI kullanıcı bilgisi içeriyor)
+// mongodb:// URI'leri birden fazla host içerebildiği için net/url yerine elle ayrıştırılır
+func uriCredentials(uri string) (username, password string, ok bool) {
+	_, rest, found := strings.Cut(uri, "://")
+	if !found {
+		return "", "", false
+	}
+	if i := strings.IndexAny(rest, "/?"); i >= 0 {
+		rest = rest[:i]
+	}
+	i := strings.LastIndex(rest, "@")
+	if i < 0 {
+		return "", "", false
+	}
+	username, password, _ = strings.Cut(rest[:i], ":")
+	return username, password, true
+}
+
+// redactWriter - Yazılanları Redact'tan geçirip alttaki writer'a iletir
+// Logger.Printf tek satırı tek Write çağrısıyla yazar, bu yüzden değerler bölünmez
+type redactWriter struct {
+	w io.Writer
+}
+
+func (r redactWriter) Write(p []byte) (int, error) {
+	if _, err := io.WriteString(r.w, Redact(string(p))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
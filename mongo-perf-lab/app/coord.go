@@ -0,0 +1,362 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"benchkit"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// coord.go - Birden fazla perflab'ın (farklı makinelerde) aynı deneyi birlikte çalıştırması
+// Tek client'ın ulaşabildiği verim, client makinesinin CPU'su ve ağıyla sınırlıdır; küme
+// seviyesindeki verimi ölçmek için aynı deney N makineden aynı anda çalıştırılır. Koordinasyon
+// ölçülen veritabanının kendisi üzerinden yapılır (her client'ın zaten erişimi var):
+//
+//   - perflab_barriers: Her bariyer bir doküman; gelen client'lar "clients" dizisine eklenir,
+//     dizi N'e ulaşınca herkes devam eder. Devam anı sunucu saatiyle (released.at) yazılır,
+//     client'lar arasındaki başlama farkı (skew) bu zamanlardan hesaplanır
+//   - perflab_results: Her client'ın metrik kayıtları; lider hepsini birleştirir
+//
+// Veri seti, index'ler ve arka plan yazma yükü yalnızca lider (adı alfabetik olarak ilk
+// client) tarafından hazırlanır; diğerleri "prepared" bariyerinde bekler. Her benchmark
+// tekrarından önce ayrı bir bariyer vardır: Tekrarlar tüm client'larda aynı anda başlar.
+// Başarısız olan client iptal kaydı bırakır, bekleyen diğerleri zaman aşımını beklemeden çıkar.
+
+// Koordinasyon koleksiyonları (ortamın veritabanında)
+const (
+	barrierCollection = "perflab_barriers"
+	resultCollection  = "perflab_results"
+)
+
+// barrierPoll - Bariyer sorgulama aralığı: Client'lar arasındaki başlama farkının üst sınırı
+// yaklaşık bu süre + bir gidiş-dönüştür
+const barrierPoll = 20 * time.Millisecond
+
+// Coordinator - Tek client'ın koordinasyon durumu
+type Coordinator struct {
+	ID      string        // Çalıştırma kimliği (tüm client'larda aynı)
+	Client  string        // Bu client'ın adı (client'lar arasında tekil)
+	Clients int           // Beklenen client sayısı
+	Leader  bool          // Join sonrasında belirlenir
+	Timeout time.Duration // Tek bariyerde en fazla bekleme
+
+	barriers *mongo.Collection
+	results  *mongo.Collection
+}
+
+// CoordinationInfo - summary.json'a yazılan koordinasyon bilgisi
+type CoordinationInfo struct {
+	ID      string `json:"id"`
+	Client  string `json:"client"`
+	Clients int    `json:"clients"`
+	Leader  bool   `json:"leader"`
+}
+
+// ClientResult - Bir client'ın yüklediği sonuç
+type ClientResult struct {
+	Client  string             `bson:"client" json:"client"`
+	Host    *benchkit.HostInfo `bson:"host" json:"host"`
+	Records []MetricsRecord    `bson:"records" json:"records"`
+}
+
+// ClusterRecord - Aynı benchmark tekrarının tüm client'lardaki toplamı
+type ClusterRecord struct {
+	Benchmark     string  `json:"benchmark"` // MetricsRecord.Name()
+	Repetition    int     `json:"repetition"`
+	Clients       int     `json:"clients"`
+	RecordsRead   int     `json:"recordsRead"`
+	MaxDurationMs float64 `json:"maxDurationMs"` // En yavaş client
+	RecordsPerSec float64 `json:"recordsPerSec"` // Client'ların okuma hızlarının toplamı
+	DocsPerSec    float64 `json:"docsPerSec,omitempty"`
+	Errors        int     `json:"errors"`
+}
+
+// BarrierSkew - Bir bariyerde client'ların devam etme anları arasındaki fark (sunucu saati)
+type BarrierSkew struct {
+	Barrier string        `json:"barrier"`
+	Skew    time.Duration `json:"skewNs"`
+}
+
+// NewCoordinator - db: Ortamın veritabanı
+func NewCoordinator(db *mongo.Database, id, client string, clients int, timeout time.Duration) *Coordinator {
+	return &Coordinator{
+		ID: id, Client: client, Clients: clients, Timeout: timeout,
+		barriers: db.Collection(barrierCollection),
+		results:  db.Collection(resultCollection),
+	}
+}
+
+// Info - summary.json için
+func (c *Coordinator) Info() *CoordinationInfo {
+	return &CoordinationInfo{ID: c.ID, Client: c.Client, Clients: c.Clients, Leader: c.Leader}
+}
+
+// Join - "join" bariyeri: Tüm client'lar gelene kadar bekler ve lideri belirler
+// Aynı adla ikinci kez katılmak hatadır (iki makinenin hostname'i aynıysa -client verilmeli)
+func (c *Coordinator) Join() error {
+	ctx, cancel := context.WithTimeout(context.Background(), c.Timeout)
+	defer cancel()
+
+	var before struct {
+		Clients []string `bson:"clients"`
+	}
+	join := func(upsert bool) error {
+		return c.barriers.FindOneAndUpdate(ctx, bson.M{"_id": c.barrierID("join")}, c.arriveUpdate("join"),
+			options.FindOneAndUpdate().SetUpsert(upsert).SetReturnDocument(options.Before)).Decode(&before)
+	}
+	err := join(true)
+	if mongo.IsDuplicateKeyError(err) {
+		err = join(false) // bkz. Wait
+	}
+	if err != nil && err != mongo.ErrNoDocuments {
+		return err
+	}
+	if slices.Contains(before.Clients, c.Client) {
+		return fmt.Errorf("%q adlı client bu koordinasyona zaten katılmış (-client ile farklı ad verin)", c.Client)
+	}
+
+	clients, err := c.wait(ctx, "join")
+	if err != nil {
+		return err
+	}
+	c.Leader = slices.Min(clients) == c.Client
+	return nil
+}
+
+// Wait - name bariyerinde tüm client'ları bekler
+func (c *Coordinator) Wait(name string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), c.Timeout)
+	defer cancel()
+
+	_, err := c.barriers.UpdateOne(ctx, bson.M{"_id": c.barrierID(name)}, c.arriveUpdate(name), options.Update().SetUpsert(true))
+	if mongo.IsDuplicateKeyError(err) {
+		// İki client aynı anda ilk gelirse upsert'lerden biri çakışabilir; doküman artık var
+		_, err = c.barriers.UpdateOne(ctx, bson.M{"_id": c.barrierID(name)}, c.arriveUpdate(name))
+	}
+	if err != nil {
+		return err
+	}
+	_, err = c.wait(ctx, name)
+	return err
+}
+
+// wait - Bariyer dolana kadar sorgular; devam anını sunucu saatiyle kaydeder
+func (c *Coordinator) wait(ctx context.Context, name string) ([]string, error) {
+	var doc struct {
+		Clients []string `bson:"clients"`
+	}
+	for {
+		if err := c.barriers.FindOne(ctx, bson.M{"_id": c.barrierID(name)}).Decode(&doc); err != nil {
+			return nil, c.waitError(ctx, name, doc.Clients, err)
+		}
+		if len(doc.Clients) >= c.Clients {
+			break
+		}
+		if err := c.aborted(ctx); err != nil {
+			return nil, err
+		}
+		select {
+		case <-ctx.Done():
+			return nil, c.waitError(ctx, name, doc.Clients, ctx.Err())
+		case <-time.After(barrierPoll):
+		}
+	}
+
+	// $$NOW: Client saatleri farklı olabilir, skew sunucunun saatiyle ölçülür
+	released := bson.M{"$concatArrays": bson.A{
+		bson.M{"$ifNull": bson.A{"$released", bson.A{}}},
+		bson.A{bson.M{"client": c.Client, "at": "$$NOW"}},
+	}}
+	_, err := c.barriers.UpdateOne(ctx, bson.M{"_id": c.barrierID(name)},
+		mongo.Pipeline{{{Key: "$set", Value: bson.M{"released": released}}}})
+	return doc.Clients, err
+}
+
+func (c *Coordinator) waitError(ctx context.Context, name string, arrived []string, err error) error {
+	if ctx.Err() != nil {
+		return fmt.Errorf("%q bariyeri %v içinde dolmadı: %d/%d client (%s)",
+			name, c.Timeout, len(arrived), c.Clients, strings.Join(arrived, ", "))
+	}
+	return err
+}
+
+func (c *Coordinator) barrierID(name string) string {
+	return c.ID + "/" + name
+}
+
+func (c *Coordinator) arriveUpdate(name string) bson.M {
+	return bson.M{
+		"$addToSet":    bson.M{"clients": c.Client},
+		"$setOnInsert": bson.M{"coord": c.ID, "name": name, "createdAt": time.Now()},
+	}
+}
+
+// Abort - Diğer client'ların beklemeyi bırakması için iptal kaydı bırakır
+func (c *Coordinator) Abort(reason string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	c.barriers.UpdateOne(ctx, bson.M{"_id": c.barrierID("!abort")},
+		bson.M{"$setOnInsert": bson.M{"coord": c.ID, "client": c.Client, "reason": reason, "createdAt": time.Now()}},
+		options.Update().SetUpsert(true))
+}
+
+func (c *Coordinator) aborted(ctx context.Context) error {
+	var doc struct {
+		Client string `bson:"client"`
+		Reason string `bson:"reason"`
+	}
+	err := c.barriers.FindOne(ctx, bson.M{"_id": c.barrierID("!abort")}).Decode(&doc)
+	if err == mongo.ErrNoDocuments {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return fmt.Errorf("%s koordinasyonu iptal etti: %s", doc.Client, doc.Reason)
+}
+
+// Upload - Bu client'ın kayıtlarını yükler (tekrar çalıştırmada üzerine yazar)
+func (c *Coordinator) Upload(records []MetricsRecord, host benchkit.HostInfo) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	_, err := c.results.ReplaceOne(ctx, bson.M{"_id": c.ID + "/" + c.Client},
+		bson.M{"coord": c.ID, "client": c.Client, "host": host, "records": records, "uploadedAt": time.Now()},
+		options.Replace().SetUpsert(true))
+	return err
+}
+
+// Collect - Tüm client'ların sonuçları yüklenene kadar bekler ve döndürür (client adına göre sıralı)
+func (c *Coordinator) Collect() ([]ClientResult, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.Timeout)
+	defer cancel()
+	filter := bson.M{"coord": c.ID}
+	for {
+		n, err := c.results.CountDocuments(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		if int(n) >= c.Clients {
+			break
+		}
+		if err := c.aborted(ctx); err != nil {
+			return nil, err
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("sonuçlar %v içinde toplanamadı: %d/%d client", c.Timeout, n, c.Clients)
+		case <-time.After(time.Second):
+		}
+	}
+	cursor, err := c.results.Find(ctx, filter, options.Find().SetSort(bson.M{"client": 1}))
+	if err != nil {
+		return nil, err
+	}
+	var results []ClientResult
+	err = cursor.All(ctx, &results)
+	return results, err
+}
+
+// Skews - Koordinasyondaki bariyerlerin başlama farkları (oluşturulma sırasıyla)
+func (c *Coordinator) Skews() ([]BarrierSkew, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	cursor, err := c.barriers.Find(ctx, bson.M{"coord": c.ID, "released": bson.M{"$exists": true}},
+		options.Find().SetSort(bson.M{"createdAt": 1}))
+	if err != nil {
+		return nil, err
+	}
+	var docs []struct {
+		Name     string `bson:"name"`
+		Released []struct {
+			At time.Time `bson:"at"`
+		} `bson:"released"`
+	}
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+	var skews []BarrierSkew
+	for _, d := range docs {
+		if len(d.Released) == 0 {
+			continue
+		}
+		first, last := d.Released[0].At, d.Released[0].At
+		for _, r := range d.Released[1:] {
+			if r.At.Before(first) {
+				first = r.At
+			}
+			if r.At.After(last) {
+				last = r.At
+			}
+		}
+		skews = append(skews, BarrierSkew{Barrier: d.Name, Skew: last.Sub(first)})
+	}
+	return skews, nil
+}
+
+// MergeClusterRecords - Client'ların kayıtlarını benchmark tekrarı bazında toplar
+// Tekrarlar aynı anda başladığından client'ların hızları toplanabilir: Kümenin o tekrardaki
+// toplam verimi. Sıra, ilk client'ın kayıt sırasıdır
+func MergeClusterRecords(results []ClientResult) []ClusterRecord {
+	var order []string
+	byKey := map[string]*ClusterRecord{}
+	for _, res := range results {
+		for _, r := range res.Records {
+			key := fmt.Sprintf("%s#%d", r.Name(), r.Repetition)
+			cr, ok := byKey[key]
+			if !ok {
+				cr = &ClusterRecord{Benchmark: r.Name(), Repetition: r.Repetition}
+				byKey[key] = cr
+				order = append(order, key)
+			}
+			cr.Clients++
+			cr.RecordsRead += r.RecordsRead
+			cr.MaxDurationMs = max(cr.MaxDurationMs, r.DurationMs)
+			if r.DurationMs > 0 {
+				cr.RecordsPerSec += float64(r.RecordsRead) / (r.DurationMs / 1000)
+			}
+			cr.DocsPerSec += r.DocsPerSec
+			cr.Errors += r.Errors
+		}
+	}
+	merged := make([]ClusterRecord, 0, len(order))
+	for _, key := range order {
+		merged = append(merged, *byKey[key])
+	}
+	return merged
+}
+
+// printClusterSummary - Lider client'ın birleştirilmiş tablosu
+func printClusterSummary(id string, results []ClientResult, merged []ClusterRecord, skews []BarrierSkew) {
+	fmt.Printf("\n=== KÜME ÖZETİ (%s, %d client) ===\n", id, len(results))
+	for _, res := range results {
+		host := "-"
+		if res.Host != nil {
+			host = res.Host.String()
+		}
+		fmt.Printf("  🖥️  %-16s %s\n", res.Client, host)
+	}
+
+	fmt.Printf("\n  %-28s %5s %7s %12s %14s %12s %8s\n", "benchmark", "tekrar", "client", "kayıt", "kayıt/sn", "en yavaş ms", "hata")
+	for _, r := range merged {
+		fmt.Printf("  %-28s %5d %7d %12d %14.0f %12.1f %8d\n",
+			r.Benchmark, r.Repetition, r.Clients, r.RecordsRead, r.RecordsPerSec, r.MaxDurationMs, r.Errors)
+	}
+
+	// Yalnızca tekrar bariyerleri ("<benchmark>#<tekrar>"): Ölçümlerin ne kadar eşzamanlı başladığı
+	var worst *BarrierSkew
+	for i, s := range skews {
+		if strings.Contains(s.Barrier, "#") && (worst == nil || s.Skew > worst.Skew) {
+			worst = &skews[i]
+		}
+	}
+	if worst != nil {
+		fmt.Printf("\n  ⏱️  Tekrarların başlama farkı (sunucu saati): en fazla %v (%s)\n",
+			worst.Skew.Round(time.Millisecond), worst.Barrier)
+	}
+}
@@ -14,7 +14,7 @@ import (
 // Bu script, performans testleri için gerekli index'leri oluşturur
 //
 // KULLANIM:
-//   go run main.go create_index.go
+//   go run main.go config.go create_index.go
 //
 // Index'ler neden önemli?
 // - Index olmadan MongoDB tüm collection'ı tarar (COLLSCAN) - ÇOK YAVAŞ!
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// dataset.go - Veri seti profili ve depolama (storage) istatistikleri
+// Generator her çalıştığında, oluşturduğu veri setinin profilini ve diskte kapladığı alanı
+// datasets/ klasörüne kaydeder. Böylece farklı veri setleri (ör: farklı doküman şekilleri,
+// farklı index'ler) arasında depolama karşılaştırması yapılabilir.
+
+// DatasetProfile - Oluşturulacak veri setinin tanımı
+// Documents 0 ise mevcut veri kullanılır (generator çalıştırılmaz)
+type DatasetProfile struct {
+	Documents  int          `yaml:"documents" json:"documents"`   // Oluşturulacak kayıt sayısı
+	BatchSize  int          `yaml:"batchSize" json:"batchSize"`   // InsertMany batch boyutu
+	Drop       bool         `yaml:"drop" json:"drop"`             // Üretimden önce collection silinsin mi
+	Seed       int64        `yaml:"seed" json:"seed"`             // Rastgele veri seed'i (0 = zamana göre)
+	Workers    int          `yaml:"workers" json:"workers"`       // Paralel üretim yapan worker sayısı
+	Shape      ShapeProfile `yaml:"shape" json:"shape"`           // Doküman şekli (uniform / varied)
+	DateFormat string       `yaml:"dateFormat" json:"dateFormat"` // createdAt formatı: date (varsayılan) veya string
+	// Tekrar eden sipariş yüzdesi (0-100): Aynı siparişin tekrar gönderilmesi (retry, çift tıklama) gibi
+	// önceki bir siparişin _id hariç kopyası eklenir ve injectedDuplicate: true ile işaretlenir (bkz. dedup.go)
+	DuplicatePct float64 `yaml:"duplicatePct" json:"duplicatePct,omitempty"`
+}
+
+// ShapeProfile - Doküman şekli çeşitliliği
+// Gerçek koleksiyonlar nadiren tek tip dokümanlardan oluşur: eski kayıtlarda olmayan alanlar,
+// sonradan eklenen opsiyonel alanlar, 0 ile yüzlerce eleman arası diziler...
+// Mode "varied" olduğunda generator bu olasılıklarla heterojen dokümanlar üretir.
+type ShapeProfile struct {
+	Mode        string  `yaml:"mode" json:"mode"`               // "uniform" (varsayılan) veya "varied"
+	MissingProb float64 `yaml:"missingProb" json:"missingProb"` // Her opsiyonel alanın eksik olma olasılığı
+	ExtraProb   float64 `yaml:"extraProb" json:"extraProb"`     // Her ekstra alanın eklenme olasılığı
+	MaxItems    int     `yaml:"maxItems" json:"maxItems"`       // items dizisinin maksimum uzunluğu (0..MaxItems)
+}
+
+// Varied - Heterojen doküman modu açık mı
+func (s ShapeProfile) Varied() bool {
+	return s.Mode == "varied"
+}
+
+// Validate - Shape ayarlarını kontrol eder
+func (s ShapeProfile) Validate() error {
+	if s.Mode != "" && s.Mode != "uniform" && s.Mode != "varied" {
+		return fmt.Errorf("geçersiz shape modu %q (uniform veya varied olmalı)", s.Mode)
+	}
+	if s.MissingProb < 0 || s.MissingProb > 1 {
+		return fmt.Errorf("missingProb 0 ile 1 arasında olmalı: %v", s.MissingProb)
+	}
+	if s.ExtraProb < 0 || s.ExtraProb > 1 {
+		return fmt.Errorf("extraProb 0 ile 1 arasında olmalı: %v", s.ExtraProb)
+	}
+	if s.MaxItems < 0 {
+		return fmt.Errorf("maxItems negatif olamaz: %d", s.MaxItems)
+	}
+	return nil
+}
+
+// StorageStats - Collection ve veritabanının diskte kapladığı alan
+// Tüm boyutlar byte cinsindendir
+type StorageStats struct {
+	Collection     string           `json:"collection"`
+	Count          int64            `json:"count"`           // Doküman sayısı
+	DataSize       int64            `json:"dataSize"`        // Sıkıştırılmamış veri boyutu
+	StorageSize    int64            `json:"storageSize"`     // Diskte kaplanan alan (WiredTiger sıkıştırması sonrası)
+	AvgObjSize     int64            `json:"avgObjSize"`      // Ortalama doküman boyutu
+	FreeStorage    int64            `json:"freeStorageSize"` // Yeniden kullanılabilir boş alan
+	TotalIndexSize int64            `json:"totalIndexSize"`
+	IndexSizes     map[string]int64 `json:"indexSizes"` // Index adı → boyut
+	DBDataSize     int64            `json:"dbDataSize"`
+	DBStorageSize  int64            `json:"dbStorageSize"`
+	DBIndexSize    int64            `json:"dbIndexSize"`
+}
+
+// DatasetSnapshot - datasets/ klasörüne kaydedilen kayıt
+type DatasetSnapshot struct {
+	Profile        DatasetProfile `json:"profile"`
+	GeneratedAt    time.Time      `json:"generatedAt"`
+	GenerationTime float64        `json:"generationSeconds"`
+	Storage        *StorageStats  `json:"storage"`
+}
+
+// CollectStorageStats - $collStats ve dbStats ile depolama istatistiklerini toplar
+// collStats komutu MongoDB 6.2'de deprecated olduğu için $collStats aggregation stage'i kullanılıyor
+func CollectStorageStats(ctx context.Context, col *mongo.Collection) (*StorageStats, error) {
+	stats := &StorageStats{Collection: col.Name(), IndexSizes: map[string]int64{}}
+
+	cursor, err := col.Aggregate(ctx, []bson.M{
+		{"$collStats": bson.M{"storageStats": bson.M{}}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("$collStats çalıştırılamadı: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	if cursor.Next(ctx) {
+		var result struct {
+			StorageStats bson.M `bson:"storageStats"`
+		}
+		if err := cursor.Decode(&result); err != nil {
+			return nil, err
+		}
+		s := result.StorageStats
+		stats.Count = toInt64(s["count"])
+		stats.DataSize = toInt64(s["size"])
+		stats.StorageSize = toInt64(s["storageSize"])
+		stats.AvgObjSize = toInt64(s["avgObjSize"])
+		stats.FreeStorage = toInt64(s["freeStorageSize"])
+		stats.TotalIndexSize = toInt64(s["totalIndexSize"])
+		if indexSizes, ok := s["indexSizes"].(bson.M); ok {
+			for name, size := range indexSizes {
+				stats.IndexSizes[name] = toInt64(size)
+			}
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+
+	var dbStats bson.M
+	if err := col.Database().RunCommand(ctx, bson.D{{Key: "dbStats", Value: 1}}).Decode(&dbStats); err != nil {
+		return nil, fmt.Errorf("dbStats çalıştırılamadı: %v", err)
+	}
+	stats.DBDataSize = toInt64(dbStats["dataSize"])
+	stats.DBStorageSize = toInt64(dbStats["storageSize"])
+	stats.DBIndexSize = toInt64(dbStats["indexSize"])
+
+	return stats, nil
+}
+
+// PrintStorageStats - Depolama istatistiklerini yazdırır
+// logger nil ise sadece ekrana yazar
+func PrintStorageStats(stats *StorageStats, logger *Logger) {
+	printf := fmt.Printf
+	if logger != nil {
+		printf = logger.Printf
+	}
+	mb := func(b int64) float64 { return float64(b) / (1024 * 1024) }
+
+	printf("\n💽 Depolama İstatistikleri (%s):\n", stats.Collection)
+	printf("  📦 Doküman sayısı: %d\n", stats.Count)
+	printf("  📏 Ortalama doküman boyutu: %d byte\n", stats.AvgObjSize)
+	printf("  🗃️  Veri boyutu (sıkıştırılmamış): %.2f MB\n", mb(stats.DataSize))
+	printf("  💾 Disk kullanımı (storageSize): %.2f MB\n", mb(stats.StorageSize))
+	if stats.StorageSize > 0 {
+		printf("  🗜️  Sıkıştırma oranı: %.2fx\n", float64(stats.DataSize)/float64(stats.StorageSize))
+	}
+	printf("  ♻️  Yeniden kullanılabilir boş alan: %.2f MB\n", mb(stats.FreeStorage))
+	printf("  📇 Toplam index boyutu: %.2f MB\n", mb(stats.TotalIndexSize))
+
+	names := make([]string, 0, len(stats.IndexSizes))
+	for name := range stats.IndexSizes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		printf("     - %s: %.2f MB\n", name, mb(stats.IndexSizes[name]))
+	}
+	printf("  🗄️  Veritabanı toplamı: veri %.2f MB, disk %.2f MB, index %.2f MB\n",
+		mb(stats.DBDataSize), mb(stats.DBStorageSize), mb(stats.DBIndexSize))
+}
+
+// SaveDatasetSnapshot - Veri seti profilini ve depolama istatistiklerini datasets/ klasörüne kaydeder
+// Döndürür: Kaydedilen dosyanın yolu
+func SaveDatasetSnapshot(snapshot DatasetSnapshot) (string, error) {
+	if err := os.MkdirAll("datasets", 0755); err != nil {
+		return "", err
+	}
+	name := fmt.Sprintf("%s_%s.json", snapshot.Storage.Collection, snapshot.GeneratedAt.Format("20060102_150405"))
+	path := filepath.Join("datasets", name)
+
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return path, os.WriteFile(path, data, 0644)
+}
@@ -0,0 +1,358 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"hash"
+	"hash/fnv"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"benchkit"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// dedup.go - Tekrar eden siparişlerin tespiti: unique index + upsert vs $group vs client-side hash
+// Veri seti generator -dup-pct ile üretilmelidir: Kopyalar injectedDuplicate: true ile işaretlidir,
+// yöntemler bu alanı yok sayar ve buldukları fazla doküman sayısı işaretli kopya sayısıyla karşılaştırılır.
+//
+//	index:    dedup_orders'ta {userId, createdAt, total} unique index'i, her sipariş bu anahtarla
+//	          upsert ($setOnInsert) edilir → eşleşen upsert = tekrar, sonuç tekrarsız koleksiyondur
+//	group:    $group {userId, createdAt, total} → n > 1 olan gruplar (allowDiskUse, sunucuda)
+//	hash:     Client tüm dokümanları okur, _id hariç içeriği alan sırasından bağımsız hash'ler (FNV-64a)
+//	hash-raw: Aynı hash, ham BSON byte'ları üzerinden - alan sırası farklı olan kopyaları kaçırır
+//
+// index ve group iş anahtarını (business key) kullanır: Anahtar çok dar seçilirse farklı siparişler
+// birleşir, çok geniş seçilirse tekrarlar kaçar. hash tüm içeriği karşılaştırır, anahtar seçimi gerekmez
+// ama her doküman network'ten geçer ve hash'ler client belleğinde tutulur.
+//
+// KULLANIM:
+//   go run main.go config.go analyzer.go antipattern.go errors.go logger.go samples.go sink.go dataset.go orders.go generator.go -dup-pct 5 -drop
+//   go run main.go config.go analyzer.go antipattern.go errors.go logger.go samples.go sink.go dedup.go
+//   go run main.go config.go analyzer.go antipattern.go errors.go logger.go samples.go sink.go dedup.go -methods group,hash -batch 5000
+
+// dedupKeyFields - index ve group yöntemlerinin iş anahtarı
+var dedupKeyFields = []string{"userId", "createdAt", "total"}
+
+// dedupResult - Bir yöntemin sonucu
+type dedupResult struct {
+	method     string
+	scanned    int64 // Okunan / işlenen doküman
+	duplicates int64 // Tespit edilen fazla doküman (grup başına n-1)
+	groups     int64 // Birden fazla dokümanı olan grup
+	duration   time.Duration
+	allocMB    float64 // Client allocation (hash yöntemleri için anlamlı)
+}
+
+// accuracy - Tespitin işaretli kopya sayısına göre doğruluğu (%): Eksik ve fazla tespit aynı şekilde cezalandırılır
+func (r dedupResult) accuracy(truth int64) float64 {
+	if truth == 0 {
+		return 0
+	}
+	diff := r.duplicates - truth
+	if diff < 0 {
+		diff = -diff
+	}
+	return max(0, 100-float64(diff)/float64(truth)*100)
+}
+
+func main() {
+	methodList := flag.String("methods", "index,group,hash,hash-raw", "Karşılaştırılacak yöntemler (index, group, hash, hash-raw)")
+	batch := flag.Int("batch", 1000, "index: BulkWrite başına upsert, hash: cursor batch boyutu")
+	flag.Parse()
+
+	var methods []string
+	for _, m := range strings.Split(*methodList, ",") {
+		switch m = strings.TrimSpace(m); m {
+		case "index", "group", "hash", "hash-raw":
+			methods = append(methods, m)
+		case "":
+		default:
+			fmt.Printf("❌ Bilinmeyen yöntem %q (index, group, hash, hash-raw)\n", m)
+			return
+		}
+	}
+	if len(methods) == 0 || *batch <= 0 {
+		fmt.Println("❌ -methods boş olamaz, -batch pozitif olmalı")
+		return
+	}
+
+	logger, err := NewLogger("dedup_results.txt")
+	if err != nil {
+		fmt.Printf("Logger oluşturulamadı: %v\n", err)
+		return
+	}
+	defer CloseLogger(logger)
+
+	logger.WriteHeader("dedup - Tekrar Eden Sipariş Tespiti (unique index / $group / hash)")
+
+	col := GetMongo()
+	ctx := context.Background()
+
+	total, err := col.CountDocuments(ctx, bson.M{})
+	if HandleError(logger, "count", err) {
+		PrintErrorSummary(logger)
+		return
+	}
+	truth, err := col.CountDocuments(ctx, bson.M{"injectedDuplicate": true})
+	if HandleError(logger, "count", err) {
+		PrintErrorSummary(logger)
+		return
+	}
+	logger.Printf("📋 %s: %d doküman, %d işaretli kopya (%%%.2f), anahtar %v\n",
+		col.Name(), total, truth, float64(truth)/float64(max(total, 1))*100, dedupKeyFields)
+	if truth == 0 {
+		logger.Println("⚠️  İşaretli kopya yok: Doğruluk hesaplanamaz. Veri setini generator -dup-pct 5 -drop ile üretin.")
+	}
+
+	var results []dedupResult
+	for _, method := range methods {
+		logger.Printf("\n▶️  %s...\n", method)
+		var memBefore, memAfter runtime.MemStats
+		runtime.GC()
+		runtime.ReadMemStats(&memBefore)
+		start := time.Now()
+
+		var r dedupResult
+		var ok bool
+		switch method {
+		case "index":
+			r, ok = dedupByIndex(ctx, col, *batch, logger)
+		case "group":
+			r, ok = dedupByGroup(ctx, col, logger)
+		case "hash", "hash-raw":
+			r, ok = dedupByHash(ctx, col, *batch, method == "hash", logger)
+		}
+		r.method = method
+		r.duration = time.Since(start)
+		runtime.ReadMemStats(&memAfter)
+		r.allocMB = float64(memAfter.TotalAlloc-memBefore.TotalAlloc) / (1024 * 1024)
+		if !ok {
+			continue
+		}
+		logger.Printf("  ⏱️  %v, %d doküman, %d tekrar grubu, %d fazla doküman, doğruluk %%%.1f\n",
+			r.duration.Round(time.Millisecond), r.scanned, r.groups, r.duplicates, r.accuracy(truth))
+		results = append(results, r)
+	}
+	if len(results) == 0 {
+		PrintErrorSummary(logger)
+		return
+	}
+
+	logger.Println("\n=== KARŞILAŞTIRMA ===")
+	logger.Printf("  %-10s %12s %12s %12s %12s %12s %12s\n", "yöntem", "süre", "doküman/sn", "fazla", "gerçek", "doğruluk %", "alloc MB")
+	for _, r := range results {
+		logger.Printf("  %-10s %12v %12.0f %12d %12d %12.1f %12.1f\n",
+			r.method, r.duration.Round(time.Millisecond), float64(r.scanned)/r.duration.Seconds(),
+			r.duplicates, truth, r.accuracy(truth), r.allocMB)
+
+		record := newMetricsRecord("dedup")
+		record.Variant = r.method
+		record.DurationMs = benchkit.Millis(r.duration)
+		record.RecordsRead = int(r.scanned)
+		record.MemoryMB = r.allocMB
+		record.DocsPerSec = float64(r.scanned) / r.duration.Seconds()
+		record.Duplicates = r.duplicates
+		record.Accuracy = r.accuracy(truth)
+		logger.WriteRecord(record)
+	}
+
+	logger.Println("\n💡 Yeni tekrarları önlemek için unique index + upsert (veya insert + duplicate key hatası) yazma")
+	logger.Println("   anında çalışır; $group ve hash mevcut veriyi taramak içindir.")
+	logger.Println("💡 hash-raw'ın kaçırdığı kopyalar alan sırası farklı olanlardır: bson.M (Go map) alan sırasını korumaz,")
+	logger.Println("   aynı sipariş farklı byte'larla saklanabilir. İçerik karşılaştırmasında alanları sıralayın veya bson.D kullanın.")
+
+	PrintErrorSummary(logger)
+
+	logger.Println("\n✅ Test tamamlandı! Sonuçlar 'dedup_results.txt' dosyasına kaydedildi.")
+}
+
+// dedupKey - Dokümanın iş anahtarı (eksik alan null olarak eşleşir)
+func dedupKey(doc bson.Raw) bson.D {
+	key := make(bson.D, 0, len(dedupKeyFields))
+	for _, f := range dedupKeyFields {
+		var v interface{}
+		if rv, err := doc.LookupErr(f); err == nil {
+			v = rv
+		}
+		key = append(key, bson.E{Key: f, Value: v})
+	}
+	return key
+}
+
+// dedupByIndex - Siparişleri iş anahtarıyla dedup_orders'a upsert eder
+// Unique index, eşzamanlı upsert'lerin aynı anahtarla iki doküman oluşturmasını da engeller
+func dedupByIndex(ctx context.Context, col *mongo.Collection, batch int, logger *Logger) (dedupResult, bool) {
+	var r dedupResult
+	target := col.Database().Collection("dedup_orders")
+	if HandleError(logger, "drop", target.Drop(ctx)) {
+		return r, false
+	}
+	keys := bson.D{}
+	for _, f := range dedupKeyFields {
+		keys = append(keys, bson.E{Key: f, Value: 1})
+	}
+	if _, err := target.Indexes().CreateOne(ctx, mongo.IndexModel{Keys: keys, Options: options.Index().SetUnique(true)}); HandleError(logger, "index", err) {
+		return r, false
+	}
+
+	cursor, err := col.Find(ctx, bson.M{}, options.Find().SetBatchSize(int32(batch)).SetProjection(bson.M{"injectedDuplicate": 0}))
+	if HandleError(logger, "find", err) {
+		return r, false
+	}
+	defer cursor.Close(ctx)
+
+	var matched int64
+	models := make([]mongo.WriteModel, 0, batch)
+	flush := func() bool {
+		if len(models) == 0 {
+			return true
+		}
+		res, err := target.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+		models = models[:0]
+		if HandleError(logger, "bulkWrite", err) {
+			return false
+		}
+		matched += res.MatchedCount
+		return true
+	}
+	for cursor.Next(ctx) {
+		r.scanned++
+		doc := bson.D{}
+		elems, err := cursor.Current.Elements()
+		if HandleError(logger, "decode", err) {
+			continue
+		}
+		for _, e := range elems {
+			switch e.Key() {
+			case "_id":
+				doc = append(doc, bson.E{Key: "sourceId", Value: e.Value()})
+			case "userId", "createdAt", "total":
+				// Filtredeki eşitlikler upsert'te dokümana zaten yazılır
+			default:
+				doc = append(doc, bson.E{Key: e.Key(), Value: e.Value()})
+			}
+		}
+		models = append(models, mongo.NewUpdateOneModel().
+			SetFilter(dedupKey(cursor.Current)).
+			SetUpdate(bson.M{"$setOnInsert": doc}).
+			SetUpsert(true))
+		if len(models) == batch && !flush() {
+			return r, false
+		}
+	}
+	if HandleError(logger, "cursor", cursor.Err()) || !flush() {
+		return r, false
+	}
+
+	// Eşleşen upsert'ler tekrar eden siparişlerdir; grup sayısı tekrarsız koleksiyondan okunamaz
+	r.duplicates = matched
+	stored, _ := target.EstimatedDocumentCount(ctx)
+	logger.Printf("  📦 dedup_orders: %d tekil sipariş (%d upsert eşleşti)\n", stored, matched)
+	return r, true
+}
+
+// dedupByGroup - İş anahtarına göre gruplar, birden fazla dokümanı olan grupları sayar
+func dedupByGroup(ctx context.Context, col *mongo.Collection, logger *Logger) (dedupResult, bool) {
+	var r dedupResult
+	groupKey := bson.M{}
+	for _, f := range dedupKeyFields {
+		groupKey[f] = "$" + f
+	}
+	pipeline := mongo.Pipeline{
+		{{Key: "$group", Value: bson.M{"_id": groupKey, "n": bson.M{"$sum": 1}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":        nil,
+			"scanned":    bson.M{"$sum": "$n"},
+			"groups":     bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$gt": bson.A{"$n", 1}}, 1, 0}}},
+			"duplicates": bson.M{"$sum": bson.M{"$subtract": bson.A{"$n", 1}}},
+		}}},
+	}
+	CheckAntiPatterns(AntiPatternInput{Source: "dedup", Pipeline: PipelineStages(pipeline)})
+	cursor, err := col.Aggregate(ctx, pipeline, options.Aggregate().SetAllowDiskUse(true))
+	if HandleError(logger, "aggregate", err) {
+		return r, false
+	}
+	var rows []struct {
+		Scanned    int64 `bson:"scanned"`
+		Groups     int64 `bson:"groups"`
+		Duplicates int64 `bson:"duplicates"`
+	}
+	if err := cursor.All(ctx, &rows); HandleError(logger, "aggregate", err) {
+		return r, false
+	}
+	if len(rows) > 0 {
+		r.scanned, r.groups, r.duplicates = rows[0].Scanned, rows[0].Groups, rows[0].Duplicates
+	}
+	return r, true
+}
+
+// dedupByHash - Tüm dokümanları okuyup _id ve işaret hariç içeriği hash'ler
+// canonical: Alanlar (alt dokümanlar dahil) ada göre sıralanarak hash'lenir; false ise ham byte sırası
+func dedupByHash(ctx context.Context, col *mongo.Collection, batch int, canonical bool, logger *Logger) (dedupResult, bool) {
+	var r dedupResult
+	cursor, err := col.Find(ctx, bson.M{}, options.Find().SetBatchSize(int32(batch)).
+		SetProjection(bson.M{"_id": 0, "injectedDuplicate": 0}))
+	if HandleError(logger, "find", err) {
+		return r, false
+	}
+	defer cursor.Close(ctx)
+
+	seen := make(map[uint64]int32)
+	h := fnv.New64a()
+	for cursor.Next(ctx) {
+		r.scanned++
+		h.Reset()
+		if canonical {
+			writeCanonicalDoc(h, cursor.Current)
+		} else {
+			h.Write(cursor.Current)
+		}
+		sum := h.Sum64()
+		seen[sum]++
+		switch n := seen[sum]; {
+		case n == 2:
+			r.groups++
+			r.duplicates++
+		case n > 2:
+			r.duplicates++
+		}
+	}
+	if HandleError(logger, "cursor", cursor.Err()) {
+		return r, false
+	}
+	logger.Printf("  🧮 %d farklı hash (map'te tutulan)\n", len(seen))
+	return r, true
+}
+
+// writeCanonicalDoc - Dokümanı alan sırasından bağımsız olarak hash'e yazar
+func writeCanonicalDoc(h hash.Hash64, doc bson.Raw) {
+	elems, _ := doc.Elements()
+	sort.Slice(elems, func(i, j int) bool { return elems[i].Key() < elems[j].Key() })
+	for _, e := range elems {
+		h.Write([]byte(e.Key()))
+		h.Write([]byte{0})
+		writeCanonicalValue(h, e.Value())
+	}
+}
+
+// writeCanonicalValue - Değeri tipiyle birlikte yazar; alt dokümanlar sıralanır, dizilerin sırası korunur
+func writeCanonicalValue(h hash.Hash64, v bson.RawValue) {
+	h.Write([]byte{byte(v.Type)})
+	switch v.Type {
+	case bson.TypeEmbeddedDocument:
+		writeCanonicalDoc(h, v.Document())
+	case bson.TypeArray:
+		values, _ := v.Array().Values()
+		for _, item := range values {
+			writeCanonicalValue(h, item)
+		}
+	default:
+		h.Write(v.Value)
+	}
+}
@@ -0,0 +1,286 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"benchkit"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// delete_bulk.go - Toplu silme stratejileri: Eski siparişlerin kopyası olmadan silinmesi (archive'ın silme yarısı)
+// Her yöntem için orders'ın ilk -docs dokümanı -collection'a kopyalanır ve createdAt'i -older-than'dan
+// eski olanlar silinir:
+//
+//	deletemany: {createdAt} index'iyle tek DeleteMany {createdAt: {$lt: cutoff}}
+//	idrange:    Koleksiyon _id sırasıyla -batch'lik aralıklara bölünür, her aralık için
+//	            DeleteMany {_id: {$gte: alt, $lte: üst}, createdAt: {$lt: cutoff}}
+//	ttl:        createdAt'e expireAfterSeconds = -older-than ile TTL index'i; silmeyi sunucunun TTL
+//	            monitörü yapar, script kalan eski doküman sayısını -poll aralığıyla izler
+//
+// Ölçülenler:
+//   - Süre ve silinen doküman/sn. ttl için süre iki parçadır: Index oluşturulduktan ilk silmeye kadar
+//     bekleme (TTL monitörü -ttlMonitorSleepSecs, varsayılan 60 sn- aralıkla uyanır) ve silme;
+//     doküman/sn silme süresinden hesaplanır
+//   - Koleksiyon istatistiklerine etkisi: Doküman sayısı, veri boyutu, storageSize, freeStorageSize
+//     ve index boyutu silme öncesi ve sonrası ($collStats)
+//
+// idrange her aralıkta sadece -batch index girdisi tarar ve tek komutun süresi sınırlıdır (kilit ve
+// oplog baskısı küçük parçalara bölünür); bedeli komut sayısıdır. Veri setinde _id ile createdAt
+// ilişkili değildir: Tüm koleksiyon taranır. _id zamanla artıyorsa (ObjectID) aralıklar cutoff'ta durdurulabilir.
+//
+// Not: orders'ın createdAt alanı Date olmalı (generator -date-format date, varsayılan); TTL index'i
+// Date olmayan alanları silmez.
+//
+// KULLANIM:
+//   go run main.go config.go analyzer.go antipattern.go errors.go logger.go samples.go sink.go dataset.go orders.go iterations.go writes.go delete_bulk.go
+//   go run main.go config.go analyzer.go antipattern.go errors.go logger.go samples.go sink.go dataset.go orders.go iterations.go writes.go delete_bulk.go -docs 500000 -methods deletemany,idrange -batch 5000
+
+// deleteResult - Bir yöntemin sonucu
+type deleteResult struct {
+	method   string
+	removed  int64
+	expected int64
+	commands int           // Gönderilen silme komutu (ttl: 0)
+	wait     time.Duration // ttl: Index oluşturulduktan ilk silmeye kadar
+	duration time.Duration // Silme süresi
+	before   *StorageStats
+	after    *StorageStats
+}
+
+// docsPerSec - Silme süresine göre silinen doküman/sn
+func (r deleteResult) docsPerSec() float64 {
+	if r.duration <= 0 {
+		return 0
+	}
+	return float64(r.removed) / r.duration.Seconds()
+}
+
+func main() {
+	docs := flag.Int64("docs", 200000, "orders'tan kopyalanan doküman sayısı (her yöntem aynı kopyadan başlar)")
+	olderThan := flag.Duration("older-than", 30*24*time.Hour, "Bu süreden eski siparişler silinir (createdAt)")
+	batch := flag.Int("batch", 1000, "idrange: Aralık başına doküman sayısı")
+	methodList := flag.String("methods", "deletemany,idrange,ttl", "Karşılaştırılacak yöntemler (deletemany, idrange, ttl)")
+	collection := flag.String("collection", "orders_deletes", "Çalışma kopyası (her yöntemde yeniden oluşturulur)")
+	poll := flag.Duration("poll", 250*time.Millisecond, "ttl: Kalan doküman sayısının kontrol aralığı")
+	ttlTimeout := flag.Duration("ttl-timeout", 5*time.Minute, "ttl: Silmenin bitmesi için beklenecek en uzun süre")
+	flag.Parse()
+
+	var methods []string
+	for _, m := range strings.Split(*methodList, ",") {
+		switch m = strings.TrimSpace(m); m {
+		case "deletemany", "idrange", "ttl":
+			methods = append(methods, m)
+		case "":
+		default:
+			fmt.Printf("❌ Bilinmeyen yöntem %q (deletemany, idrange, ttl)\n", m)
+			return
+		}
+	}
+	if len(methods) == 0 || *docs <= 0 || *batch <= 0 || *olderThan < time.Second || *poll <= 0 {
+		fmt.Println("❌ -methods boş olamaz; -docs, -batch ve -poll pozitif, -older-than en az 1s olmalı")
+		return
+	}
+
+	logger, err := NewLogger("delete_bulk_results.txt")
+	if err != nil {
+		fmt.Printf("Logger oluşturulamadı: %v\n", err)
+		return
+	}
+	defer CloseLogger(logger)
+
+	logger.WriteHeader("delete_bulk - Toplu Silme (DeleteMany vs _id Aralıkları vs TTL)")
+
+	col, err := WriteTarget(*collection)
+	if err != nil {
+		logger.Printf("❌ %v\n", err)
+		return
+	}
+	ctx := context.Background()
+	cutoff := time.Now().Add(-*olderThan)
+	logger.Printf("📋 %d doküman, createdAt < %s silinir, batch %d, yöntemler %v\n",
+		*docs, cutoff.Format(time.RFC3339), *batch, methods)
+
+	var results []deleteResult
+	for _, method := range methods {
+		logger.Printf("\n▶️  %s\n", method)
+		r, ok := runDelete(ctx, col, method, *olderThan, *docs, *batch, *poll, *ttlTimeout, logger)
+		if !ok {
+			continue
+		}
+		results = append(results, r)
+	}
+	col.Drop(ctx)
+	if len(results) == 0 {
+		PrintErrorSummary(logger)
+		return
+	}
+
+	logger.Println("\n=== KARŞILAŞTIRMA ===")
+	logger.Printf("  %-10s %10s %10s %10s %12s %12s %12s\n",
+		"yöntem", "silinen", "beklenen", "komut", "bekleme", "silme", "doküman/sn")
+	for _, r := range results {
+		wait := "-"
+		if r.method == "ttl" {
+			wait = r.wait.Round(time.Millisecond).String()
+		}
+		logger.Printf("  %-10s %10d %10d %10d %12s %12v %12.0f\n", r.method, r.removed, r.expected, r.commands,
+			wait, r.duration.Round(time.Millisecond), r.docsPerSec())
+	}
+
+	logger.Println("\n💾 Koleksiyon istatistikleri (önce → sonra, MB):")
+	logger.Printf("  %-10s %22s %18s %18s %18s %18s\n", "yöntem", "doküman", "veri", "storage", "boş (free)", "index")
+	for _, r := range results {
+		logger.Printf("  %-10s %10d → %-9d %7.1f → %-8.1f %7.1f → %-8.1f %7.1f → %-8.1f %7.1f → %-8.1f\n", r.method,
+			r.before.Count, r.after.Count, mb(r.before.DataSize), mb(r.after.DataSize),
+			mb(r.before.StorageSize), mb(r.after.StorageSize), mb(r.before.FreeStorage), mb(r.after.FreeStorage),
+			mb(r.before.TotalIndexSize), mb(r.after.TotalIndexSize))
+	}
+
+	logger.Println("\n💡 DeleteMany tek komuttur ama süresi silinecek doküman sayısıyla büyür: Uzun süre yazma yükü ve oplog")
+	logger.Println("   baskısı yaratır. idrange aynı işi sınırlı parçalara böler; aralarına bekleme koyarak yük yayılabilir.")
+	logger.Println("💡 TTL uygulamadan iş almaz ama ne zaman sileceği kontrol edilemez (monitör aralığı + bekleme);")
+	logger.Println("   silme anı önemli değilse ve dokümanlar zaten süreyle ölüyorsa en az bakım gerektiren yöntemdir.")
+	logger.Println("💡 Silinen alan dosyadan geri verilmez: storageSize kalır, boşalan alan freeStorageSize'a geçer (bkz. archive -compact).")
+
+	for _, r := range results {
+		record := newMetricsRecord("delete_bulk")
+		record.Variant = r.method
+		record.DurationMs = benchkit.Millis(r.duration)
+		record.RecordsRead = int(r.removed)
+		record.DocsPerSec = r.docsPerSec()
+		logger.WriteRecord(record)
+	}
+
+	PrintErrorSummary(logger)
+
+	logger.Println("\n✅ Test tamamlandı! Sonuçlar 'delete_bulk_results.txt' dosyasına kaydedildi.")
+}
+
+// runDelete - Çalışma kopyasını hazırlar ve yöntemi çalıştırır
+func runDelete(ctx context.Context, col *mongo.Collection, method string, olderThan time.Duration,
+	docs int64, batch int, poll, ttlTimeout time.Duration, logger *Logger) (deleteResult, bool) {
+	r := deleteResult{method: method}
+
+	// ttl index'i ölçülen işin parçasıdır; aynı anahtarla ikinci bir (TTL'siz) index oluşturulamaz
+	var indexes []mongo.IndexModel
+	if method == "deletemany" {
+		indexes = []mongo.IndexModel{{Keys: bson.D{{Key: "createdAt", Value: 1}}}}
+	}
+	if HandleError(logger, "reset", CopyOrders(GetMongo(), col, docs, indexes)(ctx)) {
+		return r, false
+	}
+	cutoff := time.Now().Add(-olderThan)
+	old := bson.M{"createdAt": bson.M{"$lt": cutoff}}
+	var err error
+	if r.expected, err = col.CountDocuments(ctx, old); HandleError(logger, "count", err) {
+		return r, false
+	}
+	if r.before, err = CollectStorageStats(ctx, col); HandleError(logger, "stats", err) {
+		return r, false
+	}
+	logger.Printf("  📦 %d doküman kopyalandı, %d tanesi silinecek (%%%.1f)\n",
+		r.before.Count, r.expected, float64(r.expected)/float64(max(r.before.Count, 1))*100)
+
+	switch method {
+	case "deletemany":
+		start := time.Now()
+		res, err := col.DeleteMany(ctx, old)
+		r.duration = time.Since(start)
+		r.commands = 1
+		if HandleError(logger, "deleteMany", err) {
+			return r, false
+		}
+		r.removed = res.DeletedCount
+
+	case "idrange":
+		// Aralığın üst sınırı: Alt sınırdan sonraki batch'inci _id (sadece _id index'i okunur)
+		boundOpts := options.FindOne().SetSort(bson.M{"_id": 1}).SetSkip(int64(batch - 1)).SetProjection(bson.M{"_id": 1})
+		lowerOp, lower := "$gte", interface{}(primitive.MinKey{})
+		start := time.Now()
+		for {
+			var bound struct {
+				ID interface{} `bson:"_id"`
+			}
+			idRange := bson.M{lowerOp: lower}
+			err := col.FindOne(ctx, bson.M{"_id": idRange}, boundOpts).Decode(&bound)
+			last := err == mongo.ErrNoDocuments // Son aralığın üst sınırı yok
+			if !last {
+				if HandleError(logger, "find", err) {
+					break
+				}
+				idRange["$lte"] = bound.ID
+			}
+			res, err := col.DeleteMany(ctx, bson.M{"_id": idRange, "createdAt": bson.M{"$lt": cutoff}})
+			r.commands++
+			if HandleError(logger, "deleteMany", err) {
+				break
+			}
+			r.removed += res.DeletedCount
+			if last {
+				break
+			}
+			lowerOp, lower = "$gt", bound.ID
+		}
+		r.duration = time.Since(start)
+
+	case "ttl":
+		start := time.Now()
+		_, err := col.Indexes().CreateOne(ctx, mongo.IndexModel{
+			Keys:    bson.D{{Key: "createdAt", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(int32(olderThan.Seconds())),
+		})
+		if HandleError(logger, "ttl index", err) {
+			return r, false
+		}
+		logger.Printf("  ⏳ TTL index'i %v'de oluşturuldu, monitör bekleniyor (en fazla %v)...\n",
+			time.Since(start).Round(time.Millisecond), ttlTimeout)
+		// Silme, hiçbir şeyin silinmediği son kontrolden sonra başlamıştır: Süre en fazla -poll kadar fazla ölçülür
+		idle, started := start, false
+		remaining := r.expected
+		for remaining > 0 && time.Since(start) < ttlTimeout {
+			time.Sleep(poll)
+			n, err := col.CountDocuments(ctx, old)
+			if HandleError(logger, "count", err) {
+				break
+			}
+			if n == r.expected {
+				idle = time.Now()
+			} else {
+				started = true
+			}
+			remaining = n
+		}
+		r.removed = r.expected - remaining
+		r.wait = idle.Sub(start)
+		if started {
+			r.duration = time.Since(idle)
+		}
+		if remaining > 0 {
+			logger.Printf("  ⚠️  %v içinde %d doküman silinmedi\n", ttlTimeout, remaining)
+		}
+	}
+
+	if r.removed != r.expected {
+		logger.Printf("  ⚠️  Beklenen %d, silinen %d\n", r.expected, r.removed)
+	}
+	logger.Printf("  ⏱️  %d komut, silme %v, %d doküman silindi (%.0f doküman/sn)\n",
+		r.commands, r.duration.Round(time.Millisecond), r.removed, r.docsPerSec())
+
+	if r.after, err = CollectStorageStats(ctx, col); HandleError(logger, "stats", err) {
+		return r, false
+	}
+	return r, true
+}
+
+// mb - Byte → MB
+func mb(bytes int64) float64 {
+	return float64(bytes) / (1024 * 1024)
+}
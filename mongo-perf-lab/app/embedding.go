@@ -0,0 +1,445 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"benchkit"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// embedding.go - Gömme (embedding) vs referans (referencing) veri modeli
+// MongoDB'nin klasik şema sorusu: Sipariş kalemleri siparişin içinde mi (items dizisi) yoksa
+// ayrı bir order_items koleksiyonunda mı (orderId ile) durmalı? Cevap iş yüküne bağlıdır;
+// bu script aynı veri setini iki modelde üretir ve eşleştirilmiş iş yüklerini ölçer:
+//
+//   read/order         Tek sipariş ve kalemleri (_id ile)         gömme: 1 sorgu, referans: 2 sorgu veya $lookup
+//   read/user-orders   Kullanıcının son N siparişi + kalemleri     gömme: 1 sorgu, referans: + $in
+//   read/product       Bir ürünün satıldığı kalemler               gömme: multikey index + $unwind, referans: index'li find
+//   write/add-item     Siparişe kalem ekleme (+ total güncelleme)  gömme: 1 update, referans: insert + update
+//   write/update-qty   Kalemin adedini değiştirme                 gömme: positional update, referans: tek doküman update
+//   write/new-order    K kalemli yeni sipariş                     gömme: 1 insert, referans: insert + insertMany
+//
+// write/* iş yükleri veriyi değiştirir: Karşılaştırma iki model arasında adildir (aynı siparişler,
+// aynı sıra), ama tekrarlanabilir sayılar için her çalıştırmadan önce -mode generate çalıştırın.
+//
+// KULLANIM:
+//   go run main.go config.go analyzer.go antipattern.go errors.go logger.go samples.go sink.go dataset.go orders.go latency.go embedding.go -mode generate
+//   go run main.go config.go analyzer.go antipattern.go errors.go logger.go samples.go sink.go dataset.go orders.go latency.go embedding.go
+//   go run main.go config.go analyzer.go antipattern.go errors.go logger.go samples.go sink.go dataset.go orders.go latency.go embedding.go -queries 5000 -workloads read
+
+// Koleksiyon adları (perfdb.orders'a dokunulmaz)
+const (
+	embeddedOrders = "orders_embedded"
+	refOrders      = "orders_ref"
+	refItems       = "order_items"
+)
+
+// embedItem - Kalem; gömme modelde items dizisinin elemanı, referans modelde orderId ile ayrı doküman
+func embedItem(rng *rand.Rand, products []primitive.ObjectID) bson.M {
+	return bson.M{
+		"productId": products[rng.Intn(len(products))],
+		"title":     randomText(rng, 30),
+		"price":     rng.Intn(1000),
+		"qty":       rng.Intn(5) + 1,
+	}
+}
+
+func main() {
+	mode := flag.String("mode", "bench", "generate (veri setini üret) veya bench (ölç)")
+	total := flag.Int("n", 200000, "generate: Sipariş sayısı")
+	users := flag.Int("users", 10000, "generate: Kullanıcı sayısı")
+	products := flag.Int("products", 5000, "generate: Ürün sayısı (read/product seçiciliği)")
+	avgItems := flag.Int("items", 5, "generate: Sipariş başına ortalama kalem (1..2×items)")
+	batchSize := flag.Int("batch", 1000, "generate: InsertMany batch boyutu (sipariş)")
+	seed := flag.Int64("seed", 42, "Rastgele veri ve iş yükü sırası için seed")
+	queries := flag.Int("queries", 2000, "bench: Durum başına işlem sayısı")
+	warmup := flag.Int("warmup", 200, "bench: Okuma durumlarında ölçüm öncesi ısınma")
+	limit := flag.Int64("limit", 20, "bench: read/user-orders'ta sipariş sayısı, read/product'ta kalem sayısı")
+	workloads := flag.String("workloads", "read,write", "bench: Çalıştırılacak iş yükleri (read, write)")
+	flag.Parse()
+
+	logger, err := NewLogger("embedding_results.txt")
+	if err != nil {
+		fmt.Printf("Logger oluşturulamadı: %v\n", err)
+		return
+	}
+	defer CloseLogger(logger)
+
+	db := GetMongo().Database()
+	ctx := context.Background()
+
+	switch *mode {
+	case "generate":
+		logger.WriteHeader("embedding - Veri Seti Üretimi (gömme ↔ referans)")
+		if *total <= 0 || *users <= 0 || *products <= 0 || *avgItems <= 0 || *batchSize <= 0 {
+			logger.Println("❌ -n, -users, -products, -items ve -batch pozitif olmalı")
+			return
+		}
+		generateEmbedding(ctx, db, *total, *users, *products, *avgItems, *batchSize, *seed, logger)
+	case "bench":
+		logger.WriteHeader("embedding - Gömme vs Referans Veri Modeli")
+		if *queries <= 0 || *limit <= 0 {
+			logger.Println("❌ -queries ve -limit pozitif olmalı")
+			return
+		}
+		run := map[string]bool{}
+		for _, w := range strings.Split(*workloads, ",") {
+			if w != "read" && w != "write" {
+				logger.Printf("❌ Geçersiz iş yükü %q (read, write)\n", w)
+				return
+			}
+			run[w] = true
+		}
+		benchEmbedding(ctx, db, *queries, *warmup, *limit, *seed, run, logger)
+	default:
+		logger.Printf("❌ Geçersiz -mode %q (generate veya bench)\n", *mode)
+		return
+	}
+
+	PrintErrorSummary(logger)
+
+	logger.Println("\n✅ Test tamamlandı! Sonuçlar 'embedding_results.txt' dosyasına kaydedildi.")
+}
+
+// generateEmbedding - Her sipariş bir kez üretilir, iki modele de aynı _id ve kalemlerle yazılır
+func generateEmbedding(ctx context.Context, db *mongo.Database, total, users, products, avgItems, batchSize int, seed int64, logger *Logger) {
+	embedded, orders, items := db.Collection(embeddedOrders), db.Collection(refOrders), db.Collection(refItems)
+	for _, col := range []*mongo.Collection{embedded, orders, items} {
+		if HandleError(logger, "drop "+col.Name(), col.Drop(ctx)) {
+			return
+		}
+	}
+
+	rng := workerRand(seed, 0)
+	userIDs := make([]primitive.ObjectID, users)
+	for i := range userIDs {
+		userIDs[i] = randomObjectID(rng)
+	}
+	productIDs := make([]primitive.ObjectID, products)
+	for i := range productIDs {
+		productIDs[i] = randomObjectID(rng)
+	}
+
+	logger.Printf("🚀 %d sipariş, %d kullanıcı, %d ürün, sipariş başına 1-%d kalem\n", total, users, products, 2*avgItems)
+	start := time.Now()
+	now := start
+	progress := benchkit.NewProgress(int64(total), 50_000, logger.Printf)
+	var itemCount int64
+	for first := 0; first < total; first += batchSize {
+		n := min(batchSize, total-first)
+		embeddedDocs, orderDocs := make([]interface{}, n), make([]interface{}, n)
+		var itemDocs []interface{}
+		for j := 0; j < n; j++ {
+			id := randomObjectID(rng)
+			lines := make([]bson.M, 1+rng.Intn(2*avgItems))
+			sum := 0
+			for k := range lines {
+				lines[k] = embedItem(rng, productIDs)
+				sum += lines[k]["price"].(int) * lines[k]["qty"].(int)
+				itemDocs = append(itemDocs, bson.M{
+					"orderId":   id,
+					"productId": lines[k]["productId"],
+					"title":     lines[k]["title"],
+					"price":     lines[k]["price"],
+					"qty":       lines[k]["qty"],
+				})
+			}
+			order := bson.D{
+				{Key: "_id", Value: id},
+				{Key: "userId", Value: userIDs[rng.Intn(users)]},
+				{Key: "status", Value: orderStatuses[rng.Intn(len(orderStatuses))]},
+				{Key: "total", Value: sum},
+				{Key: "createdAt", Value: now.Add(-time.Duration(rng.Intn(1000)) * time.Hour)},
+			}
+			orderDocs[j] = order
+			embeddedDocs[j] = append(append(bson.D{}, order...), bson.E{Key: "items", Value: lines})
+		}
+		for _, w := range []struct {
+			col  *mongo.Collection
+			docs []interface{}
+		}{{embedded, embeddedDocs}, {orders, orderDocs}, {items, itemDocs}} {
+			if _, err := w.col.InsertMany(ctx, w.docs, options.InsertMany().SetOrdered(false)); HandleError(logger, "insert "+w.col.Name(), err) {
+				return
+			}
+		}
+		itemCount += int64(len(itemDocs))
+		progress.Add(int64(n))
+	}
+
+	userIndex := mongo.IndexModel{Keys: bson.D{{Key: "userId", Value: 1}, {Key: "createdAt", Value: -1}}}
+	indexes := []struct {
+		col   *mongo.Collection
+		index mongo.IndexModel
+	}{
+		{embedded, userIndex},
+		{embedded, mongo.IndexModel{Keys: bson.D{{Key: "items.productId", Value: 1}}}}, // Multikey
+		{orders, userIndex},
+		{items, mongo.IndexModel{Keys: bson.D{{Key: "orderId", Value: 1}}}},
+		{items, mongo.IndexModel{Keys: bson.D{{Key: "productId", Value: 1}}}},
+	}
+	for _, ix := range indexes {
+		if _, err := ix.col.Indexes().CreateOne(ctx, ix.index); HandleError(logger, "index "+ix.col.Name(), err) {
+			return
+		}
+	}
+	logger.Printf("\n✅ Üretim tamamlandı: %v (%d kalem)\n", time.Since(start).Round(time.Millisecond), itemCount)
+	printEmbeddingStorage(ctx, embedded, orders, items, logger)
+}
+
+// benchEmbedding - Eşleştirilmiş okuma ve yazma iş yüklerini iki modelde ölçer
+func benchEmbedding(ctx context.Context, db *mongo.Database, queries, warmup int, limit, seed int64, run map[string]bool, logger *Logger) {
+	embedded, orders, items := db.Collection(embeddedOrders), db.Collection(refOrders), db.Collection(refItems)
+
+	// İş yükü girdileri: Her durum aynı sipariş/kullanıcı/ürün sırasını kullanır
+	var sample []struct {
+		ID     primitive.ObjectID `bson:"_id"`
+		UserID primitive.ObjectID `bson:"userId"`
+	}
+	cursor, err := orders.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$sample", Value: bson.M{"size": queries + warmup}}},
+		{{Key: "$project", Value: bson.M{"userId": 1}}},
+	})
+	if HandleError(logger, "sample", err) || HandleError(logger, "sample", cursor.All(ctx, &sample)) {
+		return
+	}
+	productIDs, err := items.Distinct(ctx, "productId", bson.M{})
+	if HandleError(logger, "distinct productId", err) {
+		return
+	}
+	if len(sample) == 0 || len(productIDs) == 0 {
+		logger.Println("❌ orders_ref boş - önce -mode generate çalıştırın")
+		return
+	}
+	printEmbeddingStorage(ctx, embedded, orders, items, logger)
+
+	rng := rand.New(rand.NewSource(seed))
+	pick := func(i int) (primitive.ObjectID, primitive.ObjectID) {
+		s := sample[i%len(sample)]
+		return s.ID, s.UserID
+	}
+	productPicks := make([]interface{}, queries+warmup)
+	for i := range productPicks {
+		productPicks[i] = productIDs[rng.Intn(len(productIDs))]
+	}
+	newItems := make([]bson.M, queries)
+	for i := range newItems {
+		newItems[i] = bson.M{"productId": productPicks[i], "title": randomText(rng, 30), "price": rng.Intn(1000), "qty": rng.Intn(5) + 1}
+	}
+	logger.Printf("\n📋 Durum başına %d işlem (okumada +%d ısınma), %d sipariş örneği, %d ürün\n",
+		queries, warmup, len(sample), len(productIDs))
+
+	findAll := func(col *mongo.Collection, filter interface{}, opts ...*options.FindOptions) ([]bson.M, error) {
+		cursor, err := col.Find(ctx, filter, opts...)
+		if err != nil {
+			return nil, err
+		}
+		var docs []bson.M
+		err = cursor.All(ctx, &docs)
+		return docs, err
+	}
+	aggregate := func(col *mongo.Collection, pipeline mongo.Pipeline) (int, error) {
+		cursor, err := col.Aggregate(ctx, pipeline)
+		if err != nil {
+			return 0, err
+		}
+		var docs []bson.M
+		err = cursor.All(ctx, &docs)
+		return len(docs), err
+	}
+	latest := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}}).SetLimit(limit)
+	lookupItems := bson.D{{Key: "$lookup", Value: bson.M{"from": refItems, "localField": "_id", "foreignField": "orderId", "as": "items"}}}
+
+	type benchCase struct {
+		name string
+		op   func(i int) (int, error)
+	}
+	reads := []benchCase{
+		{"read/order/embedded", func(i int) (int, error) {
+			id, _ := pick(i)
+			var doc bson.M
+			err := embedded.FindOne(ctx, bson.M{"_id": id}).Decode(&doc)
+			return 1, err
+		}},
+		{"read/order/ref", func(i int) (int, error) {
+			id, _ := pick(i)
+			var doc bson.M
+			if err := orders.FindOne(ctx, bson.M{"_id": id}).Decode(&doc); err != nil {
+				return 0, err
+			}
+			_, err := findAll(items, bson.M{"orderId": id})
+			return 1, err
+		}},
+		{"read/order/ref-lookup", func(i int) (int, error) {
+			id, _ := pick(i)
+			return aggregate(orders, mongo.Pipeline{{{Key: "$match", Value: bson.M{"_id": id}}}, lookupItems})
+		}},
+		{"read/user-orders/embedded", func(i int) (int, error) {
+			_, user := pick(i)
+			docs, err := findAll(embedded, bson.M{"userId": user}, latest)
+			return len(docs), err
+		}},
+		{"read/user-orders/ref", func(i int) (int, error) {
+			_, user := pick(i)
+			docs, err := findAll(orders, bson.M{"userId": user}, latest)
+			if err != nil || len(docs) == 0 {
+				return 0, err
+			}
+			ids := make([]interface{}, len(docs))
+			for j, d := range docs {
+				ids[j] = d["_id"]
+			}
+			_, err = findAll(items, bson.M{"orderId": bson.M{"$in": ids}})
+			return len(docs), err
+		}},
+		{"read/user-orders/ref-lookup", func(i int) (int, error) {
+			_, user := pick(i)
+			return aggregate(orders, mongo.Pipeline{
+				{{Key: "$match", Value: bson.M{"userId": user}}},
+				{{Key: "$sort", Value: bson.M{"createdAt": -1}}},
+				{{Key: "$limit", Value: limit}},
+				lookupItems,
+			})
+		}},
+		{"read/product/embedded", func(i int) (int, error) {
+			// Multikey index siparişi bulur; kalemi ayıklamak için $unwind + ikinci $match gerekir
+			return aggregate(embedded, mongo.Pipeline{
+				{{Key: "$match", Value: bson.M{"items.productId": productPicks[i]}}},
+				{{Key: "$limit", Value: limit}},
+				{{Key: "$unwind", Value: "$items"}},
+				{{Key: "$match", Value: bson.M{"items.productId": productPicks[i]}}},
+				{{Key: "$replaceRoot", Value: bson.M{"newRoot": "$items"}}},
+			})
+		}},
+		{"read/product/ref", func(i int) (int, error) {
+			docs, err := findAll(items, bson.M{"productId": productPicks[i]}, options.Find().SetLimit(limit))
+			return len(docs), err
+		}},
+	}
+
+	writes := []benchCase{
+		{"write/add-item/embedded", func(i int) (int, error) {
+			id, _ := pick(i)
+			it := newItems[i]
+			_, err := embedded.UpdateOne(ctx, bson.M{"_id": id}, bson.M{
+				"$push": bson.M{"items": it},
+				"$inc":  bson.M{"total": it["price"].(int) * it["qty"].(int)},
+			})
+			return 1, err
+		}},
+		{"write/add-item/ref", func(i int) (int, error) {
+			id, _ := pick(i)
+			it := newItems[i]
+			doc := bson.M{"orderId": id, "productId": it["productId"], "title": it["title"], "price": it["price"], "qty": it["qty"]}
+			if _, err := items.InsertOne(ctx, doc); err != nil {
+				return 0, err
+			}
+			_, err := orders.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$inc": bson.M{"total": it["price"].(int) * it["qty"].(int)}})
+			return 1, err
+		}},
+		{"write/update-qty/embedded", func(i int) (int, error) {
+			id, _ := pick(i)
+			// add-item'ın eklediği kalem: positional operatör dizide eşleşen ilk elemanı günceller
+			_, err := embedded.UpdateOne(ctx,
+				bson.M{"_id": id, "items.productId": newItems[i]["productId"]},
+				bson.M{"$inc": bson.M{"items.$.qty": 1}})
+			return 1, err
+		}},
+		{"write/update-qty/ref", func(i int) (int, error) {
+			id, _ := pick(i)
+			_, err := items.UpdateOne(ctx,
+				bson.M{"orderId": id, "productId": newItems[i]["productId"]},
+				bson.M{"$inc": bson.M{"qty": 1}})
+			return 1, err
+		}},
+		{"write/new-order/embedded", func(i int) (int, error) {
+			id, user := primitive.NewObjectID(), sample[i%len(sample)].UserID
+			_, err := embedded.InsertOne(ctx, bson.M{"_id": id, "userId": user, "status": "PENDING", "createdAt": time.Now(), "items": newOrderItems(newItems, i)})
+			return 1, err
+		}},
+		{"write/new-order/ref", func(i int) (int, error) {
+			id, user := primitive.NewObjectID(), sample[i%len(sample)].UserID
+			if _, err := orders.InsertOne(ctx, bson.M{"_id": id, "userId": user, "status": "PENDING", "createdAt": time.Now()}); err != nil {
+				return 0, err
+			}
+			lines := newOrderItems(newItems, i)
+			docs := make([]interface{}, len(lines))
+			for j, it := range lines {
+				docs[j] = bson.M{"orderId": id, "productId": it["productId"], "title": it["title"], "price": it["price"], "qty": it["qty"]}
+			}
+			_, err := items.InsertMany(ctx, docs)
+			return 1, err
+		}},
+	}
+
+	measure := func(cases []benchCase, warm int) []latencyCase {
+		var results []latencyCase
+		for _, c := range cases {
+			logger.Printf("\n▶️  %s...\n", c.name)
+			for i := 0; i < warm; i++ {
+				c.op(queries + i)
+			}
+			res := measureLatency(c.name, queries, c.op, logger)
+			s := res.Summary()
+			logger.Printf("  ⏱️  p50 %v, p99 %v\n", s.P50.Round(time.Microsecond), s.P99.Round(time.Microsecond))
+			writeLatencyRecord("embedding", res, logger)
+			results = append(results, res)
+		}
+		return results
+	}
+
+	var results []latencyCase
+	if run["read"] {
+		results = append(results, measure(reads, warmup)...)
+	}
+	if run["write"] {
+		// Yazmalarda ısınma yok: Isınma yazmaları da veriyi değiştirirdi
+		results = append(results, measure(writes, 0)...)
+	}
+
+	// Her iş yükü kendi gömme durumuna göre karşılaştırılır ("read/order/ref" → "read/order")
+	logger.Println("\n=== KARŞILAŞTIRMA (p50 x: gömme modele göre) ===")
+	workload := func(c latencyCase) string { return c.name[:strings.LastIndex(c.name, "/")] }
+	for first := 0; first < len(results); {
+		last := first + 1
+		for last < len(results) && workload(results[last]) == workload(results[first]) {
+			last++
+		}
+		logger.Printf("\n%s\n", workload(results[first]))
+		printLatencyTable(results[first:last], workload(results[first])+"/embedded", logger)
+		first = last
+	}
+	logger.Println("\n💡 Birlikte okunan ve sınırlı büyüyen veri gömülür; bağımsız sorgulanan (read/product),")
+	logger.Println("   sınırsız büyüyen veya sık tek başına güncellenen veri ayrı koleksiyona alınır.")
+}
+
+// newOrderItems - write/new-order için 1-5 kalem (newItems'tan sırayla)
+func newOrderItems(newItems []bson.M, i int) []bson.M {
+	n := 1 + i%5
+	lines := make([]bson.M, n)
+	for j := range lines {
+		lines[j] = newItems[(i+j)%len(newItems)]
+	}
+	return lines
+}
+
+// printEmbeddingStorage - Modellerin disk ve index boyutları
+func printEmbeddingStorage(ctx context.Context, embedded, orders, items *mongo.Collection, logger *Logger) {
+	logger.Println("\n💾 Depolama:")
+	logger.Printf("  %-16s %10s %14s %14s %12s\n", "koleksiyon", "doküman", "ort. boyut", "veri MB", "index MB")
+	for _, col := range []*mongo.Collection{embedded, orders, items} {
+		stats, err := CollectStorageStats(ctx, col)
+		if HandleError(logger, "collStats "+col.Name(), err) {
+			continue
+		}
+		logger.Printf("  %-16s %10d %12d B %14.1f %12.1f\n", col.Name(), stats.Count, stats.AvgObjSize,
+			float64(stats.DataSize)/(1024*1024), float64(stats.TotalIndexSize)/(1024*1024))
+	}
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/bsonrw"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// errors.go - Merkezi hata işleyici ve hata sınıflandırması
+// Benchmark'lar hata aldığında panic ile yarıda kesilmek yerine hatayı buraya bildirir.
+// Hatalar sınıflandırılır (network, timeout, decode, server), sayılır ve sonuçların sonunda
+// "HATA ÖZETİ" bölümü olarak raporlanır. Böylece 1 milyon dokümanlık bir okumada tek bir
+// bozuk doküman tüm ölçümü çöpe atmaz, ama gözden de kaçmaz.
+
+// ErrorKind - Hata sınıfı
+type ErrorKind string
+
+const (
+	ErrorKindNetwork ErrorKind = "network" // Bağlantı koptu, sunucuya ulaşılamadı
+	ErrorKindTimeout ErrorKind = "timeout" // Context deadline, socket/server timeout
+	ErrorKindDecode  ErrorKind = "decode"  // BSON → Go dönüşümü başarısız
+	ErrorKindServer  ErrorKind = "server"  // Sunucunun döndürdüğü komut hatası (kod ile)
+	ErrorKindOther   ErrorKind = "other"
+)
+
+// ClassifyError - Hatayı sınıfına göre ayırır
+// Sıra önemli: Timeout'lar çoğu zaman network hatası etiketi de taşır, önce timeout kontrol edilir
+func ClassifyError(err error) ErrorKind {
+	if mongo.IsTimeout(err) || errors.Is(err, context.DeadlineExceeded) {
+		return ErrorKindTimeout
+	}
+	if mongo.IsNetworkError(err) {
+		return ErrorKindNetwork
+	}
+
+	var (
+		decodeErr       *bsoncodec.DecodeError
+		valueDecoderErr bsoncodec.ValueDecoderError
+		noDecoderErr    bsoncodec.ErrNoDecoder
+		transitionErr   bsonrw.TransitionError
+	)
+	if errors.As(err, &decodeErr) || errors.As(err, &valueDecoderErr) ||
+		errors.As(err, &noDecoderErr) || errors.As(err, &transitionErr) {
+		return ErrorKindDecode
+	}
+
+	var serverErr mongo.ServerError
+	if errors.As(err, &serverErr) {
+		return ErrorKindServer
+	}
+	return ErrorKindOther
+}
+
+// maxLoggedErrors - Her sınıftan ekrana yazılan hata sayısı (gerisi sadece sayılır)
+const maxLoggedErrors = 5
+
+// errorCollector - Çalıştırma boyunca görülen hatalar
+type errorCollector struct {
+	mu     sync.Mutex
+	counts map[ErrorKind]int
+	ops    map[string]int       // "işlem/sınıf" → sayı
+	first  map[ErrorKind]string // Her sınıfın ilk hata mesajı
+}
+
+var runErrors = &errorCollector{
+	counts: map[ErrorKind]int{},
+	ops:    map[string]int{},
+	first:  map[ErrorKind]string{},
+}
+
+// HandleError - Hatayı sınıflandırır, sayar ve ilk birkaçını loglar
+// Goroutine'lerden eşzamanlı çağrılabilir.
+// Parametreler:
+//   - logger: Logger instance'ı (nil ise sadece ekrana yazar)
+//   - op: Hatanın oluştuğu işlem (ör: "find", "decode", "worker 3 aggregate")
+//   - err: Hata (nil ise hiçbir şey yapılmaz)
+//
+// Döndürür: err != nil ise true - çağıran taraf devam mı edeceğine, döngüden mi çıkacağına karar verir
+func HandleError(logger *Logger, op string, err error) bool {
+	if err == nil {
+		return false
+	}
+	kind := ClassifyError(err)
+	// Bağlantı hataları URI içerebilir - logger'sız yazımda da maskelensin
+	message := Redact(err.Error())
+
+	runErrors.mu.Lock()
+	runErrors.counts[kind]++
+	runErrors.ops[op+"/"+string(kind)]++
+	n := runErrors.counts[kind]
+	if n == 1 {
+		runErrors.first[kind] = fmt.Sprintf("%s: %s", op, message)
+	}
+	runErrors.mu.Unlock()
+
+	printf := fmt.Printf
+	if logger != nil {
+		printf = logger.Printf
+	}
+	if n <= maxLoggedErrors {
+		printf("  ⚠️  [%s] %s hatası: %s\n", kind, op, message)
+	} else if n == maxLoggedErrors+1 {
+		printf("  ⚠️  [%s] hataları artık sadece sayılıyor (özet sonda)\n", kind)
+	}
+	return true
+}
+
+// ErrorCount - Şu ana kadar görülen toplam hata sayısı
+func ErrorCount() int {
+	runErrors.mu.Lock()
+	defer runErrors.mu.Unlock()
+
+	total := 0
+	for _, n := range runErrors.counts {
+		total += n
+	}
+	return total
+}
+
+// PrintErrorSummary - Hata özetini yazdırır (hata yoksa hiçbir şey yazmaz)
+// logger nil ise sadece ekrana yazar
+func PrintErrorSummary(logger *Logger) {
+	runErrors.mu.Lock()
+	defer runErrors.mu.Unlock()
+
+	if len(runErrors.counts) == 0 {
+		return
+	}
+	printf := fmt.Printf
+	if logger != nil {
+		printf = logger.Printf
+	}
+
+	total := 0
+	for _, n := range runErrors.counts {
+		total += n
+	}
+	printf("\n=== HATA ÖZETİ ===\n")
+	printf("  ❌ Toplam %d hata - sonuçlar eksik veriyle hesaplanmış olabilir\n", total)
+	for _, kind := range []ErrorKind{ErrorKindNetwork, ErrorKindTimeout, ErrorKindDecode, ErrorKindServer, ErrorKindOther} {
+		if n := runErrors.counts[kind]; n > 0 {
+			printf("  %-8s %6d   ilk: %s\n", kind, n, runErrors.first[kind])
+		}
+	}
+
+	ops := make([]string, 0, len(runErrors.ops))
+	for op := range runErrors.ops {
+		ops = append(ops, op)
+	}
+	sort.Strings(ops)
+	printf("  İşlem bazında:\n")
+	for _, op := range ops {
+		printf("     - %s: %d\n", op, runErrors.ops[op])
+	}
+}
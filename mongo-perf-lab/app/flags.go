@@ -0,0 +1,30 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// flags.go - Script'lerin komut satırı parametreleri için ortak yardımcılar
+
+// parseIntList - "10,100,1000" formatındaki listeyi []int'e çevirir
+// Sweep (tarama) yapan script'ler birden fazla değeri tek parametrede alır
+func parseIntList(s string) ([]int, error) {
+	var values []int
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		v, err := strconv.Atoi(part)
+		if err != nil || v <= 0 {
+			return nil, fmt.Errorf("geçersiz değer %q", part)
+		}
+		values = append(values, v)
+	}
+	if len(values) == 0 {
+		return nil, fmt.Errorf("liste boş: %q", s)
+	}
+	return values, nil
+}
@@ -2,39 +2,132 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
-	"math/rand"
+	"sync"
 	"time"
 
+	"benchkit"
 	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // generator.go - Test verisi oluşturma scripti
 // Bu script, performans testleri için 1 milyon test kaydı oluşturur
 // 
 // Kullanım:
-//   go run generator.go
+//   go run main.go config.go analyzer.go antipattern.go errors.go logger.go samples.go sink.go dataset.go orders.go generator.go
+//   go run main.go config.go analyzer.go antipattern.go errors.go logger.go samples.go sink.go dataset.go orders.go generator.go -n 100000 -batch 500 -drop
+//   go run main.go config.go analyzer.go antipattern.go errors.go logger.go samples.go sink.go dataset.go orders.go generator.go -workers 8 -seed 42
+//   go run main.go config.go analyzer.go antipattern.go errors.go logger.go samples.go sink.go dataset.go orders.go generator.go -shape varied -missing-prob 0.2 -max-items 50
+//   go run main.go config.go analyzer.go antipattern.go errors.go logger.go samples.go sink.go dataset.go orders.go generator.go -date-format string -collection orders_strdate -drop
+//   go run main.go config.go analyzer.go antipattern.go errors.go logger.go samples.go sink.go dataset.go orders.go generator.go -dup-pct 5 -drop
+//
+// -shape varied: Heterojen dokümanlar üretir (eksik alanlar, ekstra alanlar, 0-100 arası items).
+// Tek tip veriyle yapılan decode ve şema analizi testleri gerçekçi olmayan iyimser sonuçlar verir.
+//
+// -dup-pct P: Kayıtların %P'si, worker'ın son duplicateWindow siparişinden birinin _id hariç kopyasıdır
+// (aynı siparişin tekrar gönderilmesi). Kopyalar injectedDuplicate: true ile işaretlenir; tekrar tespit
+// yöntemleri (dedup.go) bu alanı yok sayar, doğruluk bu işarete göre hesaplanır.
+//
+// Üretim bittikten sonra depolama istatistikleri (disk, index boyutları) raporlanır
+// ve veri seti profiliyle birlikte datasets/ klasörüne kaydedilir.
 //
 // Not: Bu işlem birkaç dakika sürebilir (1 milyon kayıt)
 func main() {
-	col := GetMongo()
-	ctx := context.Background()
-
 	// Batch size: Her seferde kaç kayıt insert edilecek
 	// Büyük batch size daha hızlı ama daha fazla bellek kullanır
-	batchSize := 1000
-	
+	batchSizeFlag := flag.Int("batch", 1000, "Her InsertMany çağrısındaki kayıt sayısı")
+
 	// Toplam kayıt sayısı
-	total := 1_000_000
+	totalFlag := flag.Int("n", 1_000_000, "Oluşturulacak toplam kayıt sayısı")
+
+	// drop: Üretimden önce collection'ı sil (manifest'lerde tekrarlanabilir veri seti için)
+	drop := flag.Bool("drop", false, "Üretimden önce collection'ı sil")
+
+	// seed: Aynı seed ile aynı veri seti tekrar üretilebilir (0 = zamana göre rastgele)
+	seedFlag := flag.Int64("seed", 0, "Rastgele veri için seed (0 = zamana göre)")
+
+	// workers: Paralel insert yapan goroutine sayısı
+	workersFlag := flag.Int("workers", 1, "Paralel üretim yapan worker sayısı")
+
+	// shape: uniform = her doküman aynı şekilde, varied = eksik/ekstra alanlar, değişken items uzunluğu
+	shapeMode := flag.String("shape", "uniform", "Doküman şekli: uniform veya varied")
+	missingProb := flag.Float64("missing-prob", 0.1, "varied modda her opsiyonel alanın eksik olma olasılığı")
+	extraProb := flag.Float64("extra-prob", 0.2, "varied modda her ekstra alanın eklenme olasılığı")
+	maxItems := flag.Int("max-items", 100, "varied modda items dizisinin maksimum uzunluğu")
+
+	// date-format: createdAt'in saklanma şekli - string tarih, sık görülen bir performans tuzağıdır
+	dateFormat := flag.String("date-format", "date", "createdAt saklama formatı: date (ISODate) veya string")
 
-	fmt.Printf("🚀 %d kayıt oluşturuluyor...\n", total)
+	// collection: Farklı veri setlerini yan yana tutmak için (ör: orders_strdate)
+	collection := flag.String("collection", "", "Verinin yazılacağı collection (varsayılan: bağlantı ayarındaki, orders)")
+
+	// dup-pct: Tekrar eden sipariş yüzdesi (dedup benchmark'ı için)
+	dupPct := flag.Float64("dup-pct", 0, "Önceki bir siparişin kopyası olarak eklenecek kayıt yüzdesi (0-100)")
+	flag.Parse()
+
+	if *dateFormat != "date" && *dateFormat != "string" {
+		panic(fmt.Sprintf("geçersiz -date-format %q (date veya string olmalı)", *dateFormat))
+	}
+	if *dupPct < 0 || *dupPct >= 100 {
+		panic(fmt.Sprintf("geçersiz -dup-pct %v (0 ile 100 arasında olmalı)", *dupPct))
+	}
+
+	shape := ShapeProfile{Mode: *shapeMode}
+	if shape.Varied() {
+		shape.MissingProb = *missingProb
+		shape.ExtraProb = *extraProb
+		shape.MaxItems = *maxItems
+	}
+	if err := shape.Validate(); err != nil {
+		panic(err)
+	}
+
+	seed := *seedFlag
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	workers := *workersFlag
+	if workers < 1 {
+		workers = 1
+	}
+
+	col := GetMongo()
+	if *collection != "" && *collection != col.Name() {
+		col = col.Database().Collection(*collection)
+	}
+	ctx := context.Background()
+
+	batchSize := *batchSizeFlag
+	total := *totalFlag
+
+	if *drop {
+		if err := col.Drop(ctx); err != nil {
+			panic(err)
+		}
+		fmt.Println("🗑️  Collection silindi")
+	}
+
+	fmt.Printf("🚀 %d kayıt oluşturuluyor (%s)...\n", total, col.Name())
 	fmt.Printf("📦 Batch size: %d\n", batchSize)
-	
+	fmt.Printf("👥 Worker sayısı: %d\n", workers)
+	fmt.Printf("🎲 Seed: %d (aynı seed + worker sayısı = aynı veri)\n", seed)
+	if *dateFormat == "string" {
+		fmt.Printf("📅 createdAt string olarak saklanıyor (%s)\n", orderDateLayout)
+	}
+	if *dupPct > 0 {
+		fmt.Printf("👯 Kayıtların %%%.1f'i önceki bir siparişin kopyası (injectedDuplicate: true)\n", *dupPct)
+	}
+	if shape.Varied() {
+		fmt.Printf("🧩 Shape: varied (eksik alan: %%%.0f, ekstra alan: %%%.0f, items: 0-%d)\n",
+			shape.MissingProb*100, shape.ExtraProb*100, shape.MaxItems)
+	}
+
 	start := time.Now()
 
-	// Random seed ayarla (her çalıştırmada farklı veri için)
-	rand.Seed(time.Now().UnixNano())
+	// Tüm dokümanlardaki tarihler aynı referans zamana göre üretilir
+	// (her dokümanda time.Now() çağırmak yerine)
+	now := start
 
 	// Batch'ler halinde kayıt oluştur
 	// Tüm kayıtları bir kerede insert etmek yerine batch'ler halinde insert et
@@ -42,45 +135,57 @@ func main() {
 	// 1. Daha az bellek kullanımı
 	// 2. İlerleme takibi yapılabilir
 	// 3. Hata durumunda daha kolay recovery
-	for i := 0; i < total; i += batchSize {
-		var docs []interface{}
-
-		// Bu batch için kayıtları oluştur
-		for j := 0; j < batchSize && (i+j) < total; j++ {
-			// Rastgele bir order dokümanı oluştur
-			docs = append(docs, bson.M{
-				"userId": primitive.NewObjectID(), // Rastgele user ID
-				"status": []string{"PAID", "CANCELLED", "PENDING"}[rand.Intn(3)], // Rastgele status
-				"total":  rand.Intn(5000), // Rastgele toplam tutar (0-5000 arası)
-				"items": []bson.M{
-					{
-						"productId": primitive.NewObjectID(), // Rastgele ürün ID
-						"price":     rand.Intn(1000),         // Rastgele fiyat (0-1000 arası)
-						"qty":       rand.Intn(5) + 1,        // Rastgele miktar (1-5 arası)
-					},
-				},
-				// Rastgele bir tarih oluştur (son 1000 saat içinden)
-				"createdAt": time.Now().Add(-time.Duration(rand.Intn(1000)) * time.Hour),
-			})
-		}
+	//
+	// Paralel üretim: Batch'ler worker'lara sabit olarak dağıtılır (worker w → w, w+W, w+2W...)
+	// Her worker kendi *rand.Rand kaynağını kullanır:
+	// - Global rand kaynağı bir mutex ile korunur, paralel üretimde çekişme noktası olur
+	// - rand.Seed deprecated (Go 1.20+)
+	// - Sabit dağıtım + seed'den türetilen kaynaklar sayesinde sonuç deterministiktir
+	numBatches := (total + batchSize - 1) / batchSize
+	progress := benchkit.NewProgress(int64(total), 100_000, fmt.Printf)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(workerID int) {
+			defer wg.Done()
+			rng := workerRand(seed, workerID)
+			var recent []bson.M // Kopyalanabilecek son siparişler (halka tampon)
 
-		// Bu batch'i MongoDB'ye insert et
-		// InsertMany, batch insert için optimize edilmiştir
-		_, err := col.InsertMany(ctx, docs)
-		if err != nil {
-			panic(err)
-		}
+			for b := workerID; b < numBatches; b += workers {
+				first := b * batchSize
+				var docs []interface{}
 
-		// Her 100k kayıtta bir ilerleme göster
-		if i%100_000 == 0 && i > 0 {
-			elapsed := time.Since(start)
-			rate := float64(i) / elapsed.Seconds()
-			remaining := total - i
-			eta := time.Duration(float64(remaining)/rate) * time.Second
-			fmt.Printf("  ✅ İlerleme: %d/%d kayıt (%.1f kayıt/sn, Kalan: ~%v)\n", 
-				i, total, rate, eta)
-		}
+				// Bu batch için kayıtları oluştur
+				for j := 0; j < batchSize && (first+j) < total; j++ {
+					if len(recent) > 0 && rng.Float64()*100 < *dupPct {
+						docs = append(docs, duplicateOrder(recent[rng.Intn(len(recent))]))
+						continue
+					}
+					order := newOrder(rng, now, shape)
+					applyDateFormat(order, *dateFormat)
+					docs = append(docs, order)
+					if *dupPct > 0 {
+						if len(recent) < duplicateWindow {
+							recent = append(recent, order)
+						} else {
+							recent[(first+j)%duplicateWindow] = order
+						}
+					}
+				}
+
+				// Bu batch'i MongoDB'ye insert et
+				// InsertMany, batch insert için optimize edilmiştir
+				_, err := col.InsertMany(ctx, docs)
+				if err != nil {
+					panic(err)
+				}
+
+				// Her 100k kayıtta bir ilerleme göster
+				progress.Add(int64(len(docs)))
+			}
+		}(w)
 	}
+	wg.Wait()
 
 	duration := time.Since(start)
 	rate := float64(total) / duration.Seconds()
@@ -97,6 +202,10 @@ func main() {
 	} else {
 		fmt.Printf("📋 Collection'daki toplam kayıt: %d\n", count)
 	}
+	if *dupPct > 0 {
+		dups, _ := col.CountDocuments(ctx, bson.M{"injectedDuplicate": true})
+		fmt.Printf("👯 Tekrar eden sipariş: %d (%%%.1f)\n", dups, float64(dups)/float64(total)*100)
+	}
 	
 	// Status dağılımını göster
 	fmt.Println("\n📊 Status Dağılımı:")
@@ -106,4 +215,25 @@ func main() {
 		percentage := float64(count) / float64(total) * 100
 		fmt.Printf("  %s: %d (%.1f%%)\n", status, count, percentage)
 	}
+
+	// Depolama raporu: Disk kullanımı, index boyutları, ortalama doküman boyutu
+	// Veri seti profiliyle birlikte saklanır, böylece farklı üretimler karşılaştırılabilir
+	stats, err := CollectStorageStats(ctx, col)
+	if err != nil {
+		fmt.Printf("⚠️  Depolama istatistikleri alınamadı: %v\n", err)
+		return
+	}
+	PrintStorageStats(stats, nil)
+
+	path, err := SaveDatasetSnapshot(DatasetSnapshot{
+		Profile:        DatasetProfile{Documents: total, BatchSize: batchSize, Drop: *drop, Seed: seed, Workers: workers, Shape: shape, DateFormat: *dateFormat, DuplicatePct: *dupPct},
+		GeneratedAt:    start,
+		GenerationTime: duration.Seconds(),
+		Storage:        stats,
+	})
+	if err != nil {
+		fmt.Printf("⚠️  Veri seti profili kaydedilemedi: %v\n", err)
+		return
+	}
+	fmt.Printf("\n💾 Veri seti profili kaydedildi: %s\n", path)
 }
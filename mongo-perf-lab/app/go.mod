@@ -2,16 +2,26 @@ module mongo-perf-lab
 
 go 1.25.5
 
+require (
+	benchkit v0.0.0
+	github.com/klauspost/compress v1.16.7
+	go.mongodb.org/mongo-driver v1.17.6
+	golang.org/x/term v0.23.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
 require (
 	github.com/golang/snappy v0.0.4 // indirect
-	github.com/klauspost/compress v1.16.7 // indirect
 	github.com/montanaflynn/stats v0.7.1 // indirect
 	github.com/xdg-go/pbkdf2 v1.0.0 // indirect
 	github.com/xdg-go/scram v1.1.2 // indirect
 	github.com/xdg-go/stringprep v1.0.4 // indirect
 	github.com/youmark/pkcs8 v0.0.0-20240726163527-a2c0da244d78 // indirect
-	go.mongodb.org/mongo-driver v1.17.6 // indirect
 	golang.org/x/crypto v0.26.0 // indirect
 	golang.org/x/sync v0.8.0 // indirect
+	golang.org/x/sys v0.23.0 // indirect
 	golang.org/x/text v0.17.0 // indirect
 )
+
+// Tüm lab'ların ortak ölçüm kütüphanesi (repo kökündeki benchkit modülü)
+replace benchkit => ../../benchkit
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"benchkit"
+)
+
+// growth.go - Order geçmişi büyüme simülatörü (canlı veri akışı)
+// Gerçek sistemlerde okuma sorguları boş bir veritabanında değil, sürekli yeni sipariş
+// yazılan bir koleksiyonda çalışır. Yazmalar cache'i kirletir, index'leri büyütür,
+// WiredTiger checkpoint'lerini tetikler ve okumalarla kilit/IO için yarışır.
+//
+// Bu script belirtilen hızda (doküman/sn) sürekli yeni order ekler. Ayrı bir terminalde
+// çalıştırıp aynı anda read benchmark'larını başlatarak "canlı yazma altında okuma" ölçülebilir.
+// Manifest'te `ingest:` tanımlanırsa perflab bu script'i benchmark'lar boyunca otomatik çalıştırır
+// ve her benchmark tekrarı sırasındaki gerçek yazma hızını sonuçlara ekler.
+//
+// Ctrl+C (SIGINT) veya SIGTERM ile durdurulur, ya da -duration sonunda kendiliğinden biter.
+//
+// KULLANIM:
+//   go run main.go config.go analyzer.go antipattern.go errors.go logger.go samples.go sink.go dataset.go orders.go ingest.go growth.go
+//   go run main.go config.go analyzer.go antipattern.go errors.go logger.go samples.go sink.go dataset.go orders.go ingest.go growth.go -rate 2000 -batch 100
+//   go run main.go config.go analyzer.go antipattern.go errors.go logger.go samples.go sink.go dataset.go orders.go ingest.go growth.go -rate 500 -duration 5m -shape varied
+func main() {
+	rate := flag.Float64("rate", 500, "Hedef yazma hızı (doküman/sn)")
+	batchSize := flag.Int("batch", 50, "Her InsertMany çağrısındaki doküman sayısı")
+	duration := flag.Duration("duration", 0, "Çalışma süresi (0 = durdurulana kadar)")
+	seedFlag := flag.Int64("seed", 0, "Rastgele veri için seed (0 = zamana göre)")
+	shapeMode := flag.String("shape", "uniform", "Doküman şekli: uniform veya varied")
+	flag.Parse()
+
+	if *rate <= 0 || *batchSize <= 0 {
+		fmt.Println("❌ -rate ve -batch pozitif olmalı")
+		os.Exit(2)
+	}
+	shape := ShapeProfile{Mode: *shapeMode, MissingProb: 0.1, ExtraProb: 0.2, MaxItems: 100}
+	if err := shape.Validate(); err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(2)
+	}
+	seed := *seedFlag
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+
+	logger, err := NewLogger("growth_results.txt")
+	if err != nil {
+		fmt.Printf("Logger oluşturulamadı: %v\n", err)
+		return
+	}
+	defer CloseLogger(logger)
+
+	logger.WriteHeader("growth - Canlı Veri Akışı (Order Geçmişi Büyümesi)")
+
+	col := GetMongo()
+
+	// Ctrl+C / SIGTERM: Yarım kalan batch'i bitir, özeti yaz ve çık
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if *duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *duration)
+		defer cancel()
+	}
+
+	// Her batch arasındaki süre: 50 doküman @ 500/sn → 100ms
+	interval := time.Duration(float64(*batchSize) / *rate * float64(time.Second))
+	logger.Printf("🎯 Hedef: %.0f doküman/sn, batch %d, her %v'de bir InsertMany\n", *rate, *batchSize, interval)
+	if *duration > 0 {
+		logger.Printf("⏱️  Süre: %v\n", *duration)
+	} else {
+		logger.Println("⏱️  Süre: Ctrl+C ile durdurulana kadar")
+	}
+
+	rng := workerRand(seed, 0)
+	batchTicker := time.NewTicker(interval)
+	defer batchTicker.Stop()
+	secondTicker := time.NewTicker(time.Second)
+	defer secondTicker.Stop()
+
+	start := time.Now()
+	totalDocs, totalFailures := 0, 0
+	secondDocs, secondFailures := 0, 0
+	var latencies []time.Duration
+	samples := logger.LatencySamples()
+
+	// flushSecond - Son saniyenin örneğini perflab için dosyaya yazar
+	flushSecond := func(now time.Time) {
+		if err := AppendIngestSample(IngestSample{Time: now, Docs: secondDocs, Target: *rate, Failures: secondFailures}); err != nil {
+			logger.Printf("⚠️  Örnek yazılamadı: %v\n", err)
+		}
+		secondDocs, secondFailures = 0, 0
+	}
+
+loop:
+	for {
+		select {
+		case <-ctx.Done():
+			break loop
+
+		case now := <-secondTicker.C:
+			flushSecond(now)
+			elapsed := now.Sub(start)
+			if int(elapsed.Seconds())%10 == 0 {
+				logger.Printf("  ✍️  %v: %d doküman (ort. %.0f/sn)\n",
+					elapsed.Round(time.Second), totalDocs, float64(totalDocs)/elapsed.Seconds())
+			}
+
+		case <-batchTicker.C:
+			// Yeni siparişler "şimdi" oluşturulmuş olmalı - geçmişe dağıtılmış tarih yerine
+			now := time.Now()
+			docs := make([]interface{}, *batchSize)
+			for i := range docs {
+				order := newOrder(rng, now, shape)
+				order["createdAt"] = now
+				docs[i] = order
+			}
+
+			// Sinyal gelse bile başlamış insert'in bitmesi beklenir (yarım batch kalmasın)
+			insertStart := time.Now()
+			_, err := col.InsertMany(context.Background(), docs)
+			insertTime := time.Since(insertStart)
+			latencies = append(latencies, insertTime)
+			samples.Record("insertMany", 0, insertStart, insertTime, err == nil)
+			if err != nil {
+				totalFailures++
+				secondFailures++
+				logger.Printf("  ⚠️  InsertMany hatası: %v\n", err)
+				continue
+			}
+			totalDocs += len(docs)
+			secondDocs += len(docs)
+		}
+	}
+	flushSecond(time.Now())
+
+	elapsed := time.Since(start)
+	achieved := float64(totalDocs) / elapsed.Seconds()
+
+	logger.Printf("\n✅ GROWTH SONUÇLARI:\n")
+	logger.Printf("⏱️  Süre: %v\n", elapsed.Round(time.Millisecond))
+	logger.Printf("📦 Eklenen Doküman: %d\n", totalDocs)
+	logger.Printf("✍️  Gerçekleşen Hız: %.1f doküman/sn (hedef %.0f, %%%.1f)\n", achieved, *rate, achieved / *rate * 100)
+	logger.Printf("❌ Başarısız InsertMany: %d\n", totalFailures)
+
+	if len(latencies) > 0 {
+		s := benchkit.Summarize(latencies)
+		logger.Printf("⏱️  InsertMany gecikmesi: p50 %v, p99 %v, max %v\n", s.P50, s.P99, s.Max)
+	}
+
+	// InsertMany interval'dan uzun sürerse ticker tick'leri düşer ve hedefe ulaşılamaz
+	if achieved < *rate*0.95 {
+		logger.Println("⚠️  Hedef hıza ulaşılamadı - insert gecikmesi batch aralığını aşıyor olabilir (-batch'i artırın).")
+	}
+
+	logger.Println("\n✅ Test tamamlandı! Sonuçlar 'growth_results.txt' dosyasına kaydedildi.")
+}
@@ -0,0 +1,286 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"benchkit"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// hotcold.go - Sıcak/soğuk alan ayrımı (hot/cold split) deneyi
+// Sipariş listesi ekranı userId, status, total, createdAt okur; items ve not gibi büyük alanlar
+// sadece sipariş detayında gerekir. Hepsi tek dokümandaysa ("fat") liste sorgusu da büyük
+// dokümanları cache'e çeker ve (projection yoksa) network'ten taşır.
+// Ayrık tasarımda sıcak alanlar orders_hot'ta, items ve not orders_cold'da (aynı _id) durur;
+// tam veri gerektiğinde ikinci bir sorgu ($in) veya $lookup ile birleştirilir.
+//
+// Modlar:
+//   - generate: Aynı rastgele sipariş akışından iki tasarımı üretir
+//     orders_fat (tam doküman) ↔ orders_hot + orders_cold, {userId: 1, createdAt: -1} index'leri
+//   - bench: Aynı kullanıcı sırasıyla sorgu başına gecikmeyi ölçer
+//     hot/*:  Kullanıcının son N siparişi (liste ekranı)
+//     full/*: Aynı siparişler items ile birlikte (detay) - ayrık tasarımın ek maliyeti
+//
+// KULLANIM:
+//   go run main.go config.go analyzer.go antipattern.go errors.go logger.go samples.go sink.go dataset.go orders.go latency.go hotcold.go -mode generate
+//   go run main.go config.go analyzer.go antipattern.go errors.go logger.go samples.go sink.go dataset.go orders.go latency.go hotcold.go
+//   go run main.go config.go analyzer.go antipattern.go errors.go logger.go samples.go sink.go dataset.go orders.go latency.go hotcold.go -queries 5000 -limit 50
+
+// Koleksiyon adları (perfdb.orders'a dokunulmaz)
+const (
+	hotColdFat  = "orders_fat"
+	hotColdHot  = "orders_hot"
+	hotColdCold = "orders_cold"
+)
+
+// hotFields - Liste ekranının okuduğu alanlar (fat-projection ve orders_hot'un şekli)
+var hotFields = bson.D{{Key: "userId", Value: 1}, {Key: "status", Value: 1}, {Key: "total", Value: 1}, {Key: "itemCount", Value: 1}, {Key: "createdAt", Value: 1}}
+
+func main() {
+	mode := flag.String("mode", "bench", "generate (veri setini üret) veya bench (ölç)")
+	total := flag.Int("n", 200000, "generate: Sipariş sayısı")
+	users := flag.Int("users", 10000, "generate: Kullanıcı sayısı (sipariş/kullanıcı = n/users)")
+	avgItems := flag.Int("items", 10, "generate: Sipariş başına ortalama ürün sayısı (1..2×items)")
+	descLen := flag.Int("desc", 120, "generate: Ürün açıklaması uzunluğu (soğuk veri hacmi)")
+	batchSize := flag.Int("batch", 1000, "generate: InsertMany batch boyutu")
+	seed := flag.Int64("seed", 42, "Rastgele veri ve sorgu sırası için seed")
+	queries := flag.Int("queries", 2000, "bench: Durum başına sorgu sayısı")
+	warmup := flag.Int("warmup", 200, "bench: Ölçüm öncesi durum başına ısınma sorgusu")
+	limit := flag.Int64("limit", 20, "bench: Sorgu başına sipariş (kullanıcının son N siparişi)")
+	flag.Parse()
+
+	logger, err := NewLogger("hotcold_results.txt")
+	if err != nil {
+		fmt.Printf("Logger oluşturulamadı: %v\n", err)
+		return
+	}
+	defer CloseLogger(logger)
+
+	db := GetMongo().Database()
+	ctx := context.Background()
+
+	switch *mode {
+	case "generate":
+		logger.WriteHeader("hotcold - Veri Seti Üretimi (fat ↔ hot + cold)")
+		if *total <= 0 || *users <= 0 || *avgItems <= 0 || *batchSize <= 0 {
+			logger.Println("❌ -n, -users, -items ve -batch pozitif olmalı")
+			return
+		}
+		generateHotCold(ctx, db, *total, *users, *avgItems, *descLen, *batchSize, *seed, logger)
+	case "bench":
+		logger.WriteHeader("hotcold - Sıcak/Soğuk Alan Ayrımı")
+		if *queries <= 0 || *limit <= 0 {
+			logger.Println("❌ -queries ve -limit pozitif olmalı")
+			return
+		}
+		benchHotCold(ctx, db, *queries, *warmup, *limit, *seed, logger)
+	default:
+		logger.Printf("❌ Geçersiz -mode %q (generate veya bench)\n", *mode)
+		return
+	}
+
+	PrintErrorSummary(logger)
+
+	logger.Println("\n✅ Test tamamlandı! Sonuçlar 'hotcold_results.txt' dosyasına kaydedildi.")
+}
+
+// generateHotCold - Her sipariş bir kez üretilir, iki tasarıma da aynı içerikle yazılır
+func generateHotCold(ctx context.Context, db *mongo.Database, total, users, avgItems, descLen, batchSize int, seed int64, logger *Logger) {
+	fat, hot, cold := db.Collection(hotColdFat), db.Collection(hotColdHot), db.Collection(hotColdCold)
+	for _, col := range []*mongo.Collection{fat, hot, cold} {
+		if HandleError(logger, "drop "+col.Name(), col.Drop(ctx)) {
+			return
+		}
+	}
+
+	rng := workerRand(seed, 0)
+	userIDs := make([]interface{}, users)
+	for i := range userIDs {
+		userIDs[i] = randomObjectID(rng)
+	}
+
+	logger.Printf("🚀 %d sipariş, %d kullanıcı, sipariş başına 1-%d ürün (açıklama %d karakter)\n", total, users, 2*avgItems, descLen)
+	start := time.Now()
+	now := start
+	progress := benchkit.NewProgress(int64(total), 50_000, logger.Printf)
+	for first := 0; first < total; first += batchSize {
+		n := min(batchSize, total-first)
+		fatDocs, hotDocs, coldDocs := make([]interface{}, n), make([]interface{}, n), make([]interface{}, n)
+		for j := 0; j < n; j++ {
+			id := randomObjectID(rng)
+			items := make([]bson.M, 1+rng.Intn(2*avgItems))
+			sum := 0
+			for k := range items {
+				price, qty := rng.Intn(1000), rng.Intn(5)+1
+				sum += price * qty
+				items[k] = bson.M{
+					"productId":   randomObjectID(rng),
+					"title":       randomText(rng, 30),
+					"description": randomText(rng, descLen),
+					"price":       price,
+					"qty":         qty,
+				}
+			}
+			note := randomText(rng, 200+rng.Intn(800))
+			hotDoc := bson.D{
+				{Key: "_id", Value: id},
+				{Key: "userId", Value: userIDs[rng.Intn(users)]},
+				{Key: "status", Value: orderStatuses[rng.Intn(len(orderStatuses))]},
+				{Key: "total", Value: sum},
+				{Key: "itemCount", Value: len(items)},
+				{Key: "createdAt", Value: now.Add(-time.Duration(rng.Intn(1000)) * time.Hour)},
+			}
+			hotDocs[j] = hotDoc
+			fatDocs[j] = append(append(bson.D{}, hotDoc...), bson.E{Key: "items", Value: items}, bson.E{Key: "note", Value: note})
+			coldDocs[j] = bson.D{{Key: "_id", Value: id}, {Key: "items", Value: items}, {Key: "note", Value: note}}
+		}
+		for _, w := range []struct {
+			col  *mongo.Collection
+			docs []interface{}
+		}{{fat, fatDocs}, {hot, hotDocs}, {cold, coldDocs}} {
+			if _, err := w.col.InsertMany(ctx, w.docs); HandleError(logger, "insert "+w.col.Name(), err) {
+				return
+			}
+		}
+		progress.Add(int64(n))
+	}
+
+	index := mongo.IndexModel{Keys: bson.D{{Key: "userId", Value: 1}, {Key: "createdAt", Value: -1}}}
+	for _, col := range []*mongo.Collection{fat, hot} {
+		if _, err := col.Indexes().CreateOne(ctx, index); HandleError(logger, "index "+col.Name(), err) {
+			return
+		}
+	}
+	logger.Printf("\n✅ Üretim tamamlandı: %v\n", time.Since(start).Round(time.Millisecond))
+	printHotColdStorage(ctx, fat, hot, cold, logger)
+}
+
+// benchHotCold - Liste (hot/*) ve detay (full/*) sorgularını iki tasarımda ölçer
+func benchHotCold(ctx context.Context, db *mongo.Database, queries, warmup int, limit, seed int64, logger *Logger) {
+	fat, hot, cold := db.Collection(hotColdFat), db.Collection(hotColdHot), db.Collection(hotColdCold)
+
+	userIDs, err := hot.Distinct(ctx, "userId", bson.M{})
+	if HandleError(logger, "distinct userId", err) {
+		return
+	}
+	if len(userIDs) == 0 {
+		logger.Println("❌ orders_hot boş - önce -mode generate çalıştırın")
+		return
+	}
+	printHotColdStorage(ctx, fat, hot, cold, logger)
+
+	// Her durum aynı kullanıcı sırasını sorgular
+	rng := rand.New(rand.NewSource(seed))
+	picks := make([]interface{}, queries+warmup)
+	for i := range picks {
+		picks[i] = userIDs[rng.Intn(len(userIDs))]
+	}
+	logger.Printf("\n📋 %d kullanıcı, durum başına %d sorgu (+%d ısınma), sorgu başına son %d sipariş\n",
+		len(userIDs), queries, warmup, limit)
+
+	latest := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}}).SetLimit(limit)
+	latestHot := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}}).SetLimit(limit).SetProjection(hotFields)
+	findAll := func(col *mongo.Collection, filter interface{}, opts *options.FindOptions) ([]bson.M, error) {
+		cursor, err := col.Find(ctx, filter, opts)
+		if err != nil {
+			return nil, err
+		}
+		var docs []bson.M
+		err = cursor.All(ctx, &docs)
+		return docs, err
+	}
+	list := func(col *mongo.Collection, opts *options.FindOptions, i int) (int, error) {
+		docs, err := findAll(col, bson.M{"userId": picks[i]}, opts)
+		return len(docs), err
+	}
+
+	type benchCase struct {
+		name string
+		op   func(i int) (int, error)
+	}
+	cases := []benchCase{
+		{"hot/fat", func(i int) (int, error) { return list(fat, latest, i) }},
+		{"hot/fat-projection", func(i int) (int, error) { return list(fat, latestHot, i) }},
+		{"hot/split", func(i int) (int, error) { return list(hot, latest, i) }},
+		{"full/fat", func(i int) (int, error) { return list(fat, latest, i) }},
+		{"full/split-in", func(i int) (int, error) {
+			orders, err := findAll(hot, bson.M{"userId": picks[i]}, latest)
+			if err != nil || len(orders) == 0 {
+				return 0, err
+			}
+			ids := make([]interface{}, len(orders))
+			for j, o := range orders {
+				ids[j] = o["_id"]
+			}
+			details, err := findAll(cold, bson.M{"_id": bson.M{"$in": ids}}, nil)
+			if err == nil && len(details) != len(orders) {
+				err = fmt.Errorf("%d siparişin %d detayı bulundu", len(orders), len(details))
+			}
+			return len(orders), err
+		}},
+		{"full/split-lookup", func(i int) (int, error) {
+			cursor, err := hot.Aggregate(ctx, mongo.Pipeline{
+				{{Key: "$match", Value: bson.M{"userId": picks[i]}}},
+				{{Key: "$sort", Value: bson.M{"createdAt": -1}}},
+				{{Key: "$limit", Value: limit}},
+				{{Key: "$lookup", Value: bson.M{"from": hotColdCold, "localField": "_id", "foreignField": "_id", "as": "detail"}}},
+			})
+			if err != nil {
+				return 0, err
+			}
+			var docs []bson.M
+			err = cursor.All(ctx, &docs)
+			return len(docs), err
+		}},
+	}
+
+	var results []latencyCase
+	for _, c := range cases {
+		logger.Printf("\n▶️  %s...\n", c.name)
+		for i := 0; i < warmup; i++ {
+			c.op(queries + i)
+		}
+		res := measureLatency(c.name, queries, c.op, logger)
+		s := res.Summary()
+		logger.Printf("  ⏱️  p50 %v, p99 %v, %.1f KB/sorgu\n",
+			s.P50.Round(time.Microsecond), s.P99.Round(time.Microsecond), float64(res.bytes)/1024/float64(queries))
+		writeLatencyRecord("hotcold", res, logger)
+		results = append(results, res)
+	}
+
+	logger.Println("\n=== LİSTE EKRANI (sıcak alanlar) ===")
+	printLatencyTable(results[:3], "hot/fat", logger)
+	logger.Println("\n=== DETAY (tam veri) ===")
+	printLatencyTable(results[3:], "full/fat", logger)
+
+	p50 := func(i int) time.Duration { return results[i].Summary().P50 }
+	logger.Println()
+	if p50(2) > 0 {
+		logger.Printf("💡 Liste: Ayrık tasarım fat'e göre %.2fx, projection'lı fat'e göre %.2fx\n",
+			float64(p50(0))/float64(p50(2)), float64(p50(1))/float64(p50(2)))
+	}
+	logger.Printf("💡 Detay: Ayrık tasarımın ek maliyeti p50'de $in ile %v, $lookup ile %v\n",
+		(p50(4) - p50(3)).Round(time.Microsecond), (p50(5) - p50(3)).Round(time.Microsecond))
+	logger.Println("💡 Liste sorguları detaydan çok daha sıksa ayrım kazandırır; projection'lı fat, network'ü")
+	logger.Println("   azaltır ama sunucu yine tam dokümanı okur ve cache'te tutar (veri seti RAM'i aşınca fark büyür).")
+}
+
+// printHotColdStorage - Tasarımların disk ve ortalama doküman boyutu (cache'te kapladığı yer)
+func printHotColdStorage(ctx context.Context, fat, hot, cold *mongo.Collection, logger *Logger) {
+	logger.Println("\n💾 Depolama:")
+	logger.Printf("  %-12s %10s %14s %14s %12s\n", "koleksiyon", "doküman", "ort. boyut", "veri MB", "index MB")
+	for _, col := range []*mongo.Collection{fat, hot, cold} {
+		stats, err := CollectStorageStats(ctx, col)
+		if HandleError(logger, "collStats "+col.Name(), err) {
+			continue
+		}
+		logger.Printf("  %-12s %10d %12d B %14.1f %12.1f\n", col.Name(), stats.Count, stats.AvgObjSize,
+			float64(stats.DataSize)/(1024*1024), float64(stats.TotalIndexSize)/(1024*1024))
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"time"
+
+	"benchkit"
+)
+
+// ingest.go - Canlı veri akışı (live ingest) örnekleri
+// growth script'i, okuma benchmark'ları çalışırken arka planda sürekli yeni order ekler.
+// Her saniye kaç doküman eklediğini PERFLAB_INGEST_FILE dosyasına JSON satırı olarak yazar.
+// perflab bu örnekleri her benchmark tekrarının zaman aralığıyla eşleştirerek
+// "bu ölçüm sırasında saniyede kaç yazma vardı" bilgisini sonuçlara ekler.
+
+// IngestSample - Bir saniyelik yazma örneği
+type IngestSample struct {
+	Time     time.Time `json:"time"`     // Saniyenin bitiş zamanı
+	Docs     int       `json:"docs"`     // Bu saniyede eklenen doküman sayısı
+	Target   float64   `json:"target"`   // Hedef yazma hızı (doküman/sn)
+	Failures int       `json:"failures"` // Başarısız InsertMany sayısı
+}
+
+// AppendIngestSample - Örneği PERFLAB_INGEST_FILE dosyasına ekler
+// Değişken tanımlı değilse hiçbir şey yapmaz
+func AppendIngestSample(sample IngestSample) error {
+	path := os.Getenv("PERFLAB_INGEST_FILE")
+	if path == "" {
+		return nil
+	}
+
+	return benchkit.AppendJSONL(path, sample)
+}
+
+// ReadIngestSamples - Örnek dosyasındaki tüm satırları okur
+func ReadIngestSamples(path string) ([]IngestSample, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	var samples []IngestSample
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var s IngestSample
+		if err := json.Unmarshal(scanner.Bytes(), &s); err != nil {
+			return samples, err
+		}
+		samples = append(samples, s)
+	}
+	return samples, scanner.Err()
+}
+
+// IngestRateBetween - [from, to] aralığına düşen örneklerden ortalama yazma hızını hesaplar
+// Döndürür: doküman/sn (aralıkta örnek yoksa 0)
+func IngestRateBetween(samples []IngestSample, from, to time.Time) float64 {
+	docs, seconds := 0, 0
+	for _, s := range samples {
+		// Örnek, bitiş zamanından önceki 1 saniyeyi kapsar
+		if s.Time.After(from) && !s.Time.After(to.Add(time.Second)) {
+			docs += s.Docs
+			seconds++
+		}
+	}
+	if seconds == 0 {
+		return 0
+	}
+	return float64(docs) / float64(seconds)
+}
@@ -0,0 +1,279 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"benchkit"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
+)
+
+// insert_bench.go - Yazma (insert) throughput benchmark'ı
+// generator'ın yaptığı işi ölçülebilir hale getirir: Aynı dokümanlar farklı yöntemlerle yazılır.
+// - Yöntem: InsertMany (ordered) veya BulkWrite (unordered InsertOne modelleri)
+// - Writer sayısı: 1 (tek akış) → N paralel goroutine; batch'ler ortak bir sayaçtan alınır
+// - Write concern: w:1 (primary onayı) veya majority (replica set çoğunluğu - standalone'da w:1 ile aynı)
+//
+// Her kombinasyon için doküman/sn ve batch gecikmesi (p50/p99) ölçülür, sonunda writer sayısına göre
+// throughput eğrisi ve ölçeklenme verimi (N writer / N × tek writer) yazılır.
+// Dokümanlar bir kez üretilir ve her kombinasyonda aynen kullanılır: Üretim maliyeti ölçüme karışmaz.
+// Her kombinasyondan önce hedef koleksiyon silinir (perfdb.orders'a dokunulmaz).
+//
+// perflab altında her kombinasyon ayrı metrik kaydıdır (benchmark: insert_bench, variant: yöntem/wc/writer),
+// örnek manifest: experiments/insert_throughput.yaml
+//
+// KULLANIM:
+//   go run main.go config.go analyzer.go antipattern.go errors.go logger.go samples.go sink.go orders.go dataset.go flags.go insert_bench.go
+//   go run main.go config.go analyzer.go antipattern.go errors.go logger.go samples.go sink.go orders.go dataset.go flags.go insert_bench.go -n 500000 -writers 1,4,16
+//   go run main.go config.go analyzer.go antipattern.go errors.go logger.go samples.go sink.go orders.go dataset.go flags.go insert_bench.go -methods insertmany -concerns majority
+
+// insertCase - Tek bir (yöntem, write concern, writer sayısı) ölçümü
+type insertCase struct {
+	method    string
+	concern   string
+	writers   int
+	docs      int64
+	failures  int64
+	duration  time.Duration
+	latency   benchkit.Summary // Batch (InsertMany/BulkWrite çağrısı) gecikmesi
+	histogram []benchkit.HistogramBucket
+}
+
+// variant - Metrik kaydındaki ve tablolardaki kısa ad: "insertmany/w1/4"
+func (c insertCase) variant() string {
+	return fmt.Sprintf("%s/%s/%d", c.method, c.concern, c.writers)
+}
+
+// docsPerSec - Ölçülen yazma hızı
+func (c insertCase) docsPerSec() float64 {
+	if c.duration <= 0 {
+		return 0
+	}
+	return float64(c.docs) / c.duration.Seconds()
+}
+
+// insertWriteConcerns - -concerns parametresindeki adlar
+var insertWriteConcerns = map[string]*writeconcern.WriteConcern{
+	"w1":       writeconcern.W1(),
+	"majority": writeconcern.Majority(),
+}
+
+func main() {
+	n := flag.Int("n", 200000, "Her kombinasyonda yazılacak doküman sayısı")
+	batchSize := flag.Int("batch", 1000, "Batch başına doküman sayısı")
+	writersFlag := flag.String("writers", "1,2,4,8", "Paralel writer sayıları (virgülle)")
+	methodsFlag := flag.String("methods", "insertmany,bulkwrite", "Yazma yöntemleri: insertmany, bulkwrite")
+	concernsFlag := flag.String("concerns", "w1,majority", "Write concern'ler: w1, majority")
+	collection := flag.String("collection", "orders_insertbench", "Yazılacak (her kombinasyonda silinen) koleksiyon")
+	seed := flag.Int64("seed", 42, "Doküman üretimi için seed")
+	flag.Parse()
+
+	writerCounts, err := parseIntList(*writersFlag)
+	if err != nil {
+		fmt.Printf("❌ -writers: %v\n", err)
+		return
+	}
+	methods := strings.Split(*methodsFlag, ",")
+	for _, m := range methods {
+		if m != "insertmany" && m != "bulkwrite" {
+			fmt.Printf("❌ -methods: bilinmeyen yöntem %q (insertmany, bulkwrite)\n", m)
+			return
+		}
+	}
+	concerns := strings.Split(*concernsFlag, ",")
+	for _, c := range concerns {
+		if insertWriteConcerns[c] == nil {
+			fmt.Printf("❌ -concerns: bilinmeyen write concern %q (w1, majority)\n", c)
+			return
+		}
+	}
+	if *n < 1 || *batchSize < 1 {
+		fmt.Println("❌ -n ve -batch pozitif olmalı")
+		return
+	}
+	if *collection == "orders" {
+		fmt.Println("❌ -collection orders olamaz: koleksiyon her kombinasyonda silinir")
+		return
+	}
+
+	logger, err := NewLogger("insert_bench_results.txt")
+	if err != nil {
+		fmt.Printf("Logger oluşturulamadı: %v\n", err)
+		return
+	}
+	defer CloseLogger(logger)
+
+	logger.WriteHeader("insert_bench - Paralel vs Tek Akış Insert Throughput")
+
+	db := GetMongo().Database()
+	ctx := context.Background()
+
+	// majority, standalone sunucuda w:1'den farksızdır - sonucu yorumlarken bilinmeli
+	var hello bson.M
+	if err := db.RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&hello); err == nil {
+		if _, ok := hello["setName"]; !ok {
+			logger.Println("ℹ️  Sunucu replica set değil: majority, w:1 ile aynı davranır")
+		}
+	}
+
+	// Dokümanları bir kez üret - tüm kombinasyonlar aynı veriyi yazar
+	logger.Printf("📦 %d doküman üretiliyor (batch %d, seed %d)...\n", *n, *batchSize, *seed)
+	rng := workerRand(*seed, 0)
+	now := time.Now()
+	var batches [][]interface{}
+	for first := 0; first < *n; first += *batchSize {
+		size := min(*batchSize, *n-first)
+		batch := make([]interface{}, size)
+		for i := range batch {
+			batch[i] = newOrder(rng, now, ShapeProfile{})
+		}
+		batches = append(batches, batch)
+	}
+
+	var cases []insertCase
+	for _, method := range methods {
+		for _, concern := range concerns {
+			col := db.Collection(*collection, options.Collection().SetWriteConcern(insertWriteConcerns[concern]))
+			for _, writers := range writerCounts {
+				if err := col.Drop(ctx); HandleError(logger, "drop", err) {
+					PrintErrorSummary(logger)
+					return
+				}
+				c := runInsertCase(col, batches, method, writers, logger)
+				c.concern = concern
+				cases = append(cases, c)
+
+				logger.Printf("  %-28s %10.0f doküman/sn   batch p50 %-10v p99 %-10v hata %d\n",
+					c.variant(), c.docsPerSec(), c.latency.P50.Round(time.Microsecond),
+					c.latency.P99.Round(time.Microsecond), c.failures)
+				writeInsertRecord(c, logger)
+			}
+		}
+	}
+	col := db.Collection(*collection)
+	col.Drop(ctx)
+
+	// Throughput eğrileri: Her (yöntem, write concern) için writer sayısına göre
+	logger.Println("\n=== THROUGHPUT EĞRİLERİ (doküman/sn) ===")
+	best := 0.0
+	for _, c := range cases {
+		best = max(best, c.docsPerSec())
+	}
+	for _, method := range methods {
+		for _, concern := range concerns {
+			logger.Printf("\n  %s, %s\n", method, concern)
+			var single float64
+			for _, c := range cases {
+				if c.method != method || c.concern != concern {
+					continue
+				}
+				if c.writers == 1 {
+					single = c.docsPerSec()
+				}
+				bar := ""
+				if best > 0 {
+					bar = strings.Repeat("█", int(c.docsPerSec()/best*40))
+				}
+				efficiency := ""
+				if single > 0 && c.writers > 1 {
+					efficiency = fmt.Sprintf("  verim %%%.0f", c.docsPerSec()/(single*float64(c.writers))*100)
+				}
+				logger.Printf("  %3d writer %10.0f %s%s\n", c.writers, c.docsPerSec(), bar, efficiency)
+			}
+		}
+	}
+
+	var winner insertCase
+	for _, c := range cases {
+		if c.docsPerSec() > winner.docsPerSec() {
+			winner = c
+		}
+	}
+	logger.Printf("\n🏆 En yüksek throughput: %s (%.0f doküman/sn)\n", winner.variant(), winner.docsPerSec())
+	logger.Println("💡 Verim %100'ün çok altına düşüyorsa ek writer sunucuda (kilit, journal, disk) sıraya giriyordur -")
+	logger.Println("   daha fazla writer yerine daha büyük batch veya unordered yazma deneyin.")
+
+	PrintErrorSummary(logger)
+
+	logger.Println("\n✅ Test tamamlandı! Sonuçlar 'insert_bench_results.txt' dosyasına kaydedildi.")
+}
+
+// runInsertCase - Batch'leri `writers` goroutine ile yazar
+// Batch'ler ortak bir sayaçtan dağıtılır: Yavaş kalan writer diğerlerini bekletmez
+func runInsertCase(col *mongo.Collection, batches [][]interface{}, method string, writers int, logger *Logger) insertCase {
+	ctx := context.Background()
+	c := insertCase{method: method, writers: writers}
+
+	var next int64 = -1
+	var wg sync.WaitGroup
+	histograms := make([]*benchkit.Histogram, writers)
+	samples := logger.LatencySamples()
+
+	start := time.Now()
+	for w := 0; w < writers; w++ {
+		histograms[w] = benchkit.NewHistogram()
+		wg.Add(1)
+		go func(w int, h *benchkit.Histogram) {
+			defer wg.Done()
+			for {
+				i := int(atomic.AddInt64(&next, 1))
+				if i >= len(batches) {
+					return
+				}
+				batch := batches[i]
+
+				callStart := time.Now()
+				var err error
+				switch method {
+				case "insertmany":
+					_, err = col.InsertMany(ctx, batch)
+				case "bulkwrite":
+					models := make([]mongo.WriteModel, len(batch))
+					for j, doc := range batch {
+						models[j] = mongo.NewInsertOneModel().SetDocument(doc)
+					}
+					_, err = col.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+				}
+				elapsed := time.Since(callStart)
+				h.Record(elapsed)
+				samples.Record(method, w, callStart, elapsed, err == nil)
+
+				if HandleError(logger, method, err) {
+					atomic.AddInt64(&c.failures, 1)
+					continue
+				}
+				atomic.AddInt64(&c.docs, int64(len(batch)))
+			}
+		}(w, histograms[w])
+	}
+	wg.Wait()
+	c.duration = time.Since(start)
+
+	merged := benchkit.NewHistogram()
+	for _, h := range histograms {
+		merged.Merge(h)
+	}
+	c.latency = merged.Summary()
+	c.histogram = benchkit.HistogramMs(merged)
+	return c
+}
+
+// writeInsertRecord - Kombinasyonu perflab metrik kaydı olarak yazar
+func writeInsertRecord(c insertCase, logger *Logger) {
+	record := newMetricsRecord("insert_bench")
+	record.Variant = c.variant()
+	record.DurationMs = benchkit.Millis(c.duration)
+	record.RecordsRead = int(c.docs)
+	record.DocsPerSec = c.docsPerSec()
+	record.Errors = int(c.failures)
+	record.Histogram = c.histogram
+	logger.WriteRecord(record)
+}
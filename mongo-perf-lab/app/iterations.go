@@ -0,0 +1,280 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/pprof"
+	"time"
+
+	"benchkit"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// iterations.go - Senaryonun istatistiksel tekrarı ve ölçüm öncesi ısınma
+// Tek bir okuma ölçümü gürültülüdür (cache durumu, GC, diğer process'ler). read_* script'leri
+// ölçülen bölümü -iterations kez tekrarlayabilir; ilk -warmup-iterations tur (cache'i ve bağlantı
+// havuzunu ısıtan turlar) atılır, kalan turların süre ve bellek dağılımı raporlanır:
+//
+//	go run main.go config.go ... read_v2.go -iterations 10 -warmup-iterations 2
+//	go run main.go config.go ... read_v2.go -warmup-duration 30s   (30 sn boyunca ısınma turu)
+//
+// Isınma, -warmup-iterations turu VE -warmup-duration süresi dolana kadar sürer; ısınma
+// turlarının sonuçları ölçülen turlardan ayrı loglanır ve metrik kaydında warmupMs olarak tutulur.
+//
+// Kullanım (ölçülen bölümün etrafında, read_bad ve read_v1..v5):
+//
+//	iterations := NewIterations(logger)
+//	for iterations.Next() {
+//		... ölçüm ...
+//		iterations.Record(duration, memoryUsed)
+//	}
+//	metrics.Iterations = iterations.Stats()
+//
+// -pprof ile ölçülen turlar (ısınma hariç) boyunca CPU profili alınır, son turdan sonra heap profili
+// yazılır: read_v3_cpu.pprof, read_v3_heap.pprof (perflab run bunları runs/ klasörüne taşır).
+// CPU profilinde BSON decode (bson/bsonrw, bsoncodec) ve GC (runtime.gcBgMarkWorker) görünür;
+// ağ ve sunucu beklemesi CPU harcamadığı için görünmez, loglanan CPU/duvar süresi farkıdır:
+//
+//	go run main.go config.go ... read_v3.go -pprof
+//	go tool pprof -http=: read_v3_cpu.pprof
+//
+// Kendi tekrar döngüsü olan read_* script'leri (-runs, varyantlar) ölçümden önce WarmUp çağırır.
+// read_resume ve read_index_drop ısınma yapmaz: Cursor zaman aşımını ve plan değişimini zaman
+// çizelgesinde ölçerler, "önce" aşamaları zaten kararlı durumu gösterir.
+
+var (
+	iterationsFlag       = flag.Int("iterations", 1, "Ölçülen bölümün tekrar sayısı (ısınma turları hariç)")
+	warmupIterationsFlag = flag.Int("warmup-iterations", -1, "Ölçümden önce çalıştırılıp atılan tur sayısı (-1: ölçüm tekrarlanıyorsa 1, değilse 0)")
+	warmupDurationFlag   = flag.Duration("warmup-duration", 0, "Isınma turlarının en az süresi (0: sadece -warmup-iterations)")
+	pprofFlag            = flag.Bool("pprof", false, "Ölçülen turların CPU ve heap profilini <script>_cpu.pprof / <script>_heap.pprof dosyalarına yaz")
+)
+
+// warmupPlan - Isınma aşamasının uzunluğu: En az passes tur ve en az duration süre
+type warmupPlan struct {
+	passes   int
+	duration time.Duration
+}
+
+// newWarmupPlan - Parametrelerden ısınma planı; repeated: ölçüm birden fazla tur mu
+func newWarmupPlan(repeated bool) warmupPlan {
+	if !flag.Parsed() {
+		flag.Parse()
+	}
+	passes := *warmupIterationsFlag
+	if passes < 0 {
+		passes = 0
+		if repeated {
+			passes = 1
+		}
+	}
+	return warmupPlan{passes: passes, duration: max(*warmupDurationFlag, 0)}
+}
+
+// active - Plan en az bir ısınma turu gerektiriyor mu
+func (p warmupPlan) active() bool {
+	return p.passes > 0 || p.duration > 0
+}
+
+// more - done tur ve elapsed süreden sonra yeni bir ısınma turu gerekiyor mu
+func (p warmupPlan) more(done int, elapsed time.Duration) bool {
+	return done < p.passes || elapsed < p.duration
+}
+
+// String - "2 ısınma turu, en az 30s" biçiminde özet
+func (p warmupPlan) String() string {
+	s := fmt.Sprintf("%d ısınma turu", p.passes)
+	if p.duration > 0 {
+		s += fmt.Sprintf(", en az %v", p.duration)
+	}
+	return s
+}
+
+// Iterations - Tur döngüsünün durumu
+type Iterations struct {
+	logger      *Logger
+	measured    int
+	warmup      warmupPlan
+	warming     bool      // Isınma aşaması sürüyor mu
+	warmupStart time.Time // İlk ısınma turunun başlangıcı
+	warmedUp    int       // Başlatılan ısınma turu
+	current     int       // Next'in son döndürdüğü ölçülen tur (1'den başlar)
+	durations   []float64
+	memory      []float64
+	warmupMs    []float64
+	profile     *measureProfile // -pprof ile ölçülen turlar boyunca açık CPU profili
+}
+
+// NewIterations - -iterations, -warmup-iterations ve -warmup-duration parametrelerine göre tur döngüsü oluşturur
+// Parametreler henüz parse edilmemişse (parametresiz read_* script'leri) burada parse edilir
+func NewIterations(logger *Logger) *Iterations {
+	if !flag.Parsed() {
+		flag.Parse()
+	}
+	measured := max(*iterationsFlag, 1)
+	warmup := newWarmupPlan(measured > 1)
+	if measured > 1 || warmup.active() {
+		logger.Printf("🔁 %d ölçülen tur + %s\n", measured, warmup)
+	}
+	return &Iterations{logger: logger, measured: measured, warmup: warmup, warming: warmup.active()}
+}
+
+// Next - Sıradaki turu başlatır; tüm turlar bittiyse false döner
+func (it *Iterations) Next() bool {
+	if it.warming {
+		if it.warmedUp == 0 {
+			it.warmupStart = time.Now()
+		}
+		if it.warmup.more(it.warmedUp, time.Since(it.warmupStart)) {
+			it.warmedUp++
+			it.logger.Printf("\n🔥 Isınma turu %d (sonuçlar ölçüme dahil edilmez)\n", it.warmedUp)
+			return true
+		}
+		it.warming = false
+		it.logger.Printf("\n🔥 Isınma bitti: %d tur, %v\n", it.warmedUp, time.Since(it.warmupStart).Round(time.Millisecond))
+	}
+	if it.current >= it.measured {
+		it.stopProfile()
+		return false
+	}
+	if it.current == 0 && *pprofFlag {
+		it.startProfile()
+	}
+	it.current++
+	if it.measured > 1 {
+		it.logger.Printf("\n🔁 Tur %d/%d\n", it.current, it.measured)
+	}
+	return true
+}
+
+// Warmup - Geçerli tur ısınma turu mu
+func (it *Iterations) Warmup() bool {
+	return it.warming
+}
+
+// Record - Geçerli turun ölçümünü ekler (ısınma turları ayrı tutulur ve ayrı loglanır)
+func (it *Iterations) Record(duration time.Duration, memoryUsed int64) {
+	if it.Warmup() {
+		it.warmupMs = append(it.warmupMs, benchkit.Millis(duration))
+		it.logger.Printf("  🔥 Isınma turu %d: %v, %.2f MB\n", it.warmedUp, duration, float64(memoryUsed)/(1024*1024))
+		return
+	}
+	it.durations = append(it.durations, benchkit.Millis(duration))
+	it.memory = append(it.memory, float64(memoryUsed)/(1024*1024))
+}
+
+// Stats - Ölçülen turların dağılımı ve ısınma turları; tek tur ölçüldüyse ve ısınma yapılmadıysa nil
+func (it *Iterations) Stats() *IterationStats {
+	if len(it.durations) < 2 && len(it.warmupMs) == 0 {
+		return nil
+	}
+	return &IterationStats{
+		Iterations: len(it.durations),
+		Warmup:     it.warmedUp,
+		WarmupMs:   it.warmupMs,
+		DurationMs: benchkit.Describe(it.durations),
+		MemoryMB:   benchkit.Describe(it.memory),
+	}
+}
+
+// measureProfile - Ölçülen turlar boyunca alınan CPU profili
+type measureProfile struct {
+	file     *os.File
+	start    time.Time
+	cpuStart time.Duration
+}
+
+// startProfile - CPU profilini ilk ölçülen turun başında başlatır (hata ölçümü durdurmaz)
+func (it *Iterations) startProfile() {
+	file, err := os.Create(it.logger.run.Script + "_cpu.pprof")
+	if err != nil {
+		it.logger.Printf("⚠️  CPU profili açılamadı: %v\n", err)
+		return
+	}
+	if err := pprof.StartCPUProfile(file); err != nil {
+		file.Close()
+		it.logger.Printf("⚠️  CPU profili başlatılamadı: %v\n", err)
+		return
+	}
+	it.profile = &measureProfile{file: file, start: time.Now(), cpuStart: benchkit.CPUTime()}
+}
+
+// stopProfile - Son ölçülen turdan sonra CPU profilini kapatır ve heap profilini yazar
+func (it *Iterations) stopProfile() {
+	p := it.profile
+	if p == nil {
+		return
+	}
+	it.profile = nil
+	pprof.StopCPUProfile()
+	p.file.Close()
+	wall, cpu := time.Since(p.start), benchkit.CPUTime()-p.cpuStart
+	it.logger.Printf("\n🧪 CPU profili: %s (%v CPU / %v duvar süresi, %.0f%%; kalanı ağ ve sunucu beklemesi)\n",
+		p.file.Name(), cpu.Round(time.Millisecond), wall.Round(time.Millisecond), cpu.Seconds()/wall.Seconds()*100)
+
+	// Heap profili canlı nesneleri (inuse) ve process başından beri yapılan ayırmaları (alloc_space) içerir
+	heapPath := it.logger.run.Script + "_heap.pprof"
+	file, err := os.Create(heapPath)
+	if err != nil {
+		it.logger.Printf("⚠️  Heap profili yazılamadı: %v\n", err)
+		return
+	}
+	defer file.Close()
+	runtime.GC()
+	if err := pprof.WriteHeapProfile(file); err != nil {
+		it.logger.Printf("⚠️  Heap profili yazılamadı: %v\n", err)
+		return
+	}
+	it.logger.Printf("🧪 Heap profili: %s (go tool pprof -http=: %s)\n", heapPath, heapPath)
+}
+
+// WarmUp - Kendi tekrar döngüsü olan senaryolarda ölçümden önce pass'i ısınma planı kadar çalıştırır
+// Ölçüm tekrarlandığı için -warmup-iterations -1 iken 1 tur yapılır. pass hata dönerse ısınma kesilir;
+// turların süresi ölçülen sonuçlardan ayrı, tek satırda loglanır.
+func WarmUp(logger *Logger, label string, pass func() error) {
+	plan := newWarmupPlan(true)
+	if !plan.active() {
+		return
+	}
+	var first, last time.Duration
+	rounds := 0
+	start := time.Now()
+	for plan.more(rounds, time.Since(start)) {
+		passStart := time.Now()
+		err := pass()
+		last = time.Since(passStart)
+		if rounds == 0 {
+			first = last
+		}
+		rounds++
+		if err != nil {
+			logger.Printf("  ⚠️  Isınma (%s) kesildi: %v\n", label, err)
+			break
+		}
+	}
+	logger.Printf("  🔥 Isınma (%s): %d tur, %v (ilk tur %v, son tur %v) - ölçüme dahil değil\n",
+		label, rounds, time.Since(start).Round(time.Millisecond), first.Round(time.Microsecond), last.Round(time.Microsecond))
+}
+
+// FindPass - Sorgunun tüm sonuçlarını bson.M'e decode ederek okuyan ısınma turu (WarmUp için)
+func FindPass(col *mongo.Collection, filter interface{}, opts ...*options.FindOptions) func() error {
+	return func() error {
+		ctx := context.Background()
+		cursor, err := col.Find(ctx, filter, opts...)
+		if err != nil {
+			return err
+		}
+		defer cursor.Close(ctx)
+		for cursor.Next(ctx) {
+			var doc bson.M
+			if err := cursor.Decode(&doc); err != nil {
+				return err
+			}
+		}
+		return cursor.Err()
+	}
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"benchkit"
+)
+
+// latency.go - Aynı işlemin tekrar tekrar çalıştırıldığı gecikme ölçümleri
+// Şema tasarımı deneyleri (hotcold, ...) tek bir büyük okuma yerine "kullanıcının siparişleri"
+// gibi küçük sorguları binlerce kez çalıştırır; önemli olan toplam süre değil, sorgu başına
+// gecikme dağılımı ve sorgu başına transfer edilen veridir.
+
+// latencyCase - Bir işlemin queries kez çalıştırılmasının sonucu
+type latencyCase struct {
+	name     string // Metrik kaydındaki varyant (ör: "hot/split")
+	ops      int
+	docs     int64 // Toplam dönen/yazılan doküman
+	failures int
+	duration time.Duration
+	bytes    int64 // Sunucudan gelen veri (command monitoring)
+	hist     *benchkit.Histogram
+}
+
+// Summary - Gecikme yüzdelikleri
+func (c latencyCase) Summary() benchkit.Summary {
+	return c.hist.Summary()
+}
+
+// measureLatency - op'u ops kez sırayla çalıştırır ve her çağrının süresini kaydeder
+// op, i. çağrıda dönen (veya yazılan) doküman sayısını döndürür
+func measureLatency(name string, ops int, op func(i int) (int, error), logger *Logger) latencyCase {
+	c := latencyCase{name: name, ops: ops, hist: benchkit.NewHistogram()}
+	samples := logger.LatencySamples()
+	ResetCursorPhases()
+	start := time.Now()
+	for i := 0; i < ops; i++ {
+		opStart := time.Now()
+		n, err := op(i)
+		elapsed := time.Since(opStart)
+		c.hist.Record(elapsed)
+		samples.Record(name, 0, opStart, elapsed, err == nil)
+		if HandleError(logger, name, err) {
+			c.failures++
+			continue
+		}
+		c.docs += int64(n)
+	}
+	c.duration = time.Since(start)
+	c.bytes = SnapshotCursorPhases(0).ReplyBytes
+	return c
+}
+
+// printLatencyTable - Durumları tek tabloda karşılaştırır; baseline'a göre p50 oranı
+func printLatencyTable(cases []latencyCase, baseline string, logger *Logger) {
+	var base *latencyCase
+	for i := range cases {
+		if cases[i].name == baseline {
+			base = &cases[i]
+		}
+	}
+	logger.Printf("  %-24s %8s %10s %10s %10s %12s %12s %8s\n", "durum", "sorgu", "p50", "p95", "p99", "doküman/op", "KB/op", "p50 x")
+	for _, c := range cases {
+		s := c.Summary()
+		ratio := "-"
+		if base != nil && s.P50 > 0 {
+			ratio = fmt.Sprintf("%.2fx", float64(base.Summary().P50)/float64(s.P50))
+		}
+		logger.Printf("  %-24s %8d %10v %10v %10v %12.1f %12.1f %8s\n",
+			c.name, c.ops, s.P50.Round(time.Microsecond), s.P95.Round(time.Microsecond), s.P99.Round(time.Microsecond),
+			float64(c.docs)/float64(max(c.ops, 1)), float64(c.bytes)/1024/float64(max(c.ops, 1)), ratio)
+	}
+}
+
+// writeLatencyRecord - Durumu perflab metrik kaydı olarak yazar (variant: c.name)
+func writeLatencyRecord(benchmark string, c latencyCase, logger *Logger) {
+	logger.WriteRecord(latencyRecord(benchmark, c))
+}
+
+// latencyRecord - Durumun metrik kaydı (yazma durumlarında çağıran DocsPerSec gibi alanları ekler)
+func latencyRecord(benchmark string, c latencyCase) MetricsRecord {
+	s := c.Summary()
+	record := newMetricsRecord(benchmark)
+	record.Variant = c.name
+	record.DurationMs = benchkit.Millis(c.duration)
+	record.RecordsRead = int(c.docs)
+	record.BytesReceived = c.bytes
+	record.Histogram = benchkit.HistogramMs(c.hist)
+	record.P50Ms = benchkit.Millis(s.P50)
+	record.P99Ms = benchkit.Millis(s.P99)
+	return record
+}
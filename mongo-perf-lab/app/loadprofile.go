@@ -0,0 +1,150 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// loadprofile.go - Deklaratif yük profilleri (ramp, steady, spike)
+// Workload runner'a "saniyede kaç işlem yapılsın" bilgisini zamana bağlı olarak verir.
+//
+// Profil formatı (virgülle ayrılmış aşamalar):
+//   ramp:0-500:30s      → 30 saniyede 0'dan 500 ops/sn'ye doğrusal artış
+//   steady:500:60s      → 60 saniye boyunca 500 ops/sn sabit yük
+//   spike:2000:10s      → 10 saniye boyunca ani 2000 ops/sn sıçrama
+//
+// Örnek:
+//   "ramp:0-500:30s,steady:500:60s,spike:2000:10s,steady:500:30s"
+
+// LoadPhase - Yük profilinin tek bir aşaması
+type LoadPhase struct {
+	Kind     string        // Aşama tipi: "ramp", "steady" veya "spike"
+	From     float64       // Aşama başındaki hedef hız (ops/sn) - sadece ramp için farklıdır
+	To       float64       // Aşama sonundaki hedef hız (ops/sn)
+	Duration time.Duration // Aşamanın süresi
+}
+
+// LoadProfile - Sırayla çalıştırılan aşamalardan oluşan yük profili
+type LoadProfile struct {
+	Name   string
+	Phases []LoadPhase
+}
+
+// LoadProfilePresets - Hazır profiller
+// -profile parametresine profil string'i yerine bu isimlerden biri de verilebilir
+var LoadProfilePresets = map[string]string{
+	"ramp":   "ramp:0-500:60s",
+	"steady": "steady:500:60s",
+	"spike":  "steady:200:20s,spike:2000:10s,steady:200:30s",
+}
+
+// ParseLoadProfile - Profil string'ini (veya hazır profil adını) LoadProfile'a çevirir
+// Parametreler:
+//   - spec: "ramp:0-500:30s,steady:500:60s" formatında profil veya hazır profil adı
+//
+// Döndürür:
+//   - *LoadProfile: Parse edilmiş profil
+//   - error: Format hatası varsa
+func ParseLoadProfile(spec string) (*LoadProfile, error) {
+	name := spec
+	if preset, ok := LoadProfilePresets[spec]; ok {
+		spec = preset
+	}
+
+	profile := &LoadProfile{Name: name}
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		fields := strings.Split(part, ":")
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("geçersiz aşama %q (beklenen: tip:hız:süre)", part)
+		}
+
+		duration, err := time.ParseDuration(fields[2])
+		if err != nil || duration <= 0 {
+			return nil, fmt.Errorf("geçersiz süre %q: %v", fields[2], err)
+		}
+
+		phase := LoadPhase{Kind: fields[0], Duration: duration}
+		switch phase.Kind {
+		case "ramp":
+			// ramp iki hız alır: başlangıç-bitiş
+			rates := strings.SplitN(fields[1], "-", 2)
+			if len(rates) != 2 {
+				return nil, fmt.Errorf("ramp aşaması başlangıç-bitiş hızı ister: %q", part)
+			}
+			if phase.From, err = parseRate(rates[0]); err != nil {
+				return nil, err
+			}
+			if phase.To, err = parseRate(rates[1]); err != nil {
+				return nil, err
+			}
+		case "steady", "spike":
+			rate, err := parseRate(fields[1])
+			if err != nil {
+				return nil, err
+			}
+			phase.From, phase.To = rate, rate
+		default:
+			return nil, fmt.Errorf("bilinmeyen aşama tipi %q (ramp, steady, spike)", phase.Kind)
+		}
+
+		profile.Phases = append(profile.Phases, phase)
+	}
+
+	if len(profile.Phases) == 0 {
+		return nil, fmt.Errorf("profil boş: %q", spec)
+	}
+	return profile, nil
+}
+
+func parseRate(s string) (float64, error) {
+	rate, err := strconv.ParseFloat(s, 64)
+	if err != nil || rate < 0 {
+		return 0, fmt.Errorf("geçersiz hız %q", s)
+	}
+	return rate, nil
+}
+
+// TotalDuration - Profilin toplam süresi
+func (p *LoadProfile) TotalDuration() time.Duration {
+	var total time.Duration
+	for _, phase := range p.Phases {
+		total += phase.Duration
+	}
+	return total
+}
+
+// RateAt - Profilin başlangıcından elapsed kadar süre sonraki hedef hızı döndürür
+// Döndürür:
+//   - float64: Hedef hız (ops/sn)
+//   - string: O anda aktif olan aşamanın tipi ("" ise profil bitmiştir)
+func (p *LoadProfile) RateAt(elapsed time.Duration) (float64, string) {
+	for _, phase := range p.Phases {
+		if elapsed < phase.Duration {
+			// ramp için doğrusal interpolasyon, diğerlerinde From == To
+			progress := float64(elapsed) / float64(phase.Duration)
+			return phase.From + (phase.To-phase.From)*progress, phase.Kind
+		}
+		elapsed -= phase.Duration
+	}
+	return 0, ""
+}
+
+// String - Profili okunabilir formatta döndürür (rapor başlıkları için)
+func (p *LoadProfile) String() string {
+	parts := make([]string, 0, len(p.Phases))
+	for _, phase := range p.Phases {
+		if phase.Kind == "ramp" {
+			parts = append(parts, fmt.Sprintf("ramp:%g-%g:%v", phase.From, phase.To, phase.Duration))
+		} else {
+			parts = append(parts, fmt.Sprintf("%s:%g:%v", phase.Kind, phase.To, phase.Duration))
+		}
+	}
+	return strings.Join(parts, ",")
+}
@@ -1,17 +1,83 @@
 package main
 
 import (
+	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
+	"strings"
 	"time"
+
+	"benchkit"
 )
 
 // Logger - Hem ekrana hem dosyaya yazma için logger yapısı
 // Bu yapı, tüm çıktıları hem terminal'e hem de bir dosyaya yazar
+// Hedefler PERFLAB_SINKS ile değiştirilebilir (bkz. sink.go) - varsayılan: terminal + dosya
+// Yazılan her şey önce maskelenir (bkz. config.go Redact): Şifreler hiçbir hedefe düşmez
+// Text dosyası yazılıyorsa yanına çalıştırmanın JSON özeti de yazılır (read_v3_results.json, bkz. RunResults)
 type Logger struct {
-	file   *os.File
-	writer io.Writer
+	sinks       *MultiSink
+	writer      io.Writer
+	specs       []SinkSpec
+	csvAttached bool // -csv parametresine bakıldı mı (bkz. attachCSVFlag)
+	run         *RunResults
+	resultsPath string // JSON özetin yolu (boş = yazılmaz)
+
+	samples       *LatencySamples // -latency-samples ile ham gecikme örnekleri (bkz. samples.go)
+	samplesOpened bool
+}
+
+// csvFlag - Her script'te geçerli: Metrik kayıtları bu CSV dosyasına da eklenir (PERFLAB_CSV ile aynı)
+var csvFlag = flag.String("csv", "", "Metrik kayıtlarını bu CSV dosyasına da ekle (kayıt başına bir satır, bkz. sink.go)")
+
+// RunResults - Çalıştırmanın makine-okunabilir özeti (pandas/jq ile analiz için)
+// Text log'daki sayıların aynısı: PrintMetrics'e verilen QueryMetrics (ExecutionStats dahil) ve
+// WriteRecord ile yazılan metrik kayıtları, çalıştırmanın ayarları ve zamanlarıyla birlikte.
+// Süreler QueryMetrics'te nanosaniye (…Ns), kayıtlarda milisaniyedir (…Ms).
+type RunResults struct {
+	Script     string             `json:"script"` // ör: read_v3
+	Test       string             `json:"test,omitempty"`
+	StartedAt  time.Time          `json:"startedAt"`
+	FinishedAt time.Time          `json:"finishedAt"`
+	DurationMs float64            `json:"durationMs"`
+	Args       []string           `json:"args"`            // Komut satırı (maskelenmiş)
+	Flags      map[string]string  `json:"flags,omitempty"` // Tüm parametrelerin son değerleri (varsayılanlar dahil)
+	Config     *RunConfig         `json:"config,omitempty"`
+	Host       *benchkit.HostInfo `json:"host,omitempty"`
+	Metrics    []VersionMetrics   `json:"metrics,omitempty"`
+	Records    []MetricsRecord    `json:"records,omitempty"`
+	Errors     int                `json:"errors"`
+	Leaks      []string           `json:"leaks,omitempty"`        // Çıkışta serbest bırakılmamış session/cursor/transaction'lar (bkz. CheckLeaks)
+	LeakWarn   []string           `json:"leakWarnings,omitempty"` // Sunucu genelinde artan açık cursor/transaction (başka client'lar da olabilir)
+}
+
+// RunConfig - Ölçümün yapıldığı bağlantı ayarları (URI maskelenir, kimlik bilgileri yazılmaz)
+type RunConfig struct {
+	Environment string `json:"environment"`
+	URI         string `json:"uri"`
+	Database    string `json:"database"`
+	Collection  string `json:"collection"`
+	MaxPoolSize uint64 `json:"maxPoolSize,omitempty"`
+	MinPoolSize uint64 `json:"minPoolSize,omitempty"`
+	TLS         bool   `json:"tls,omitempty"`
+	// Driver timeout'ları (0 = driver varsayılanı)
+	ConnectTimeoutMs         float64 `json:"connectTimeoutMs,omitempty"`
+	ServerSelectionTimeoutMs float64 `json:"serverSelectionTimeoutMs,omitempty"`
+	SocketTimeoutMs          float64 `json:"socketTimeoutMs,omitempty"`
+	Repetition               string  `json:"repetition,omitempty"`   // perflab altında PERFLAB_REPETITION
+	MetricsFile              string  `json:"metricsFile,omitempty"`  // perflab altında PERFLAB_METRICS_FILE
+	ConfigSource             string  `json:"configSource,omitempty"` // Ortamların okunduğu dosya (bkz. ConfigPath)
+}
+
+// VersionMetrics - PrintMetrics'e verilen metrikler
+type VersionMetrics struct {
+	Version    string       `json:"version"`
+	RecordedAt time.Time    `json:"recordedAt"`
+	Metrics    QueryMetrics `json:"metrics"`
 }
 
 // NewLogger - Yeni bir logger oluşturur
@@ -20,22 +86,43 @@ type Logger struct {
 //
 // Döndürür:
 //   - *Logger: Logger instance'ı
-//   - error: Dosya oluşturma hatası varsa
+//   - error: Dosya oluşturma hatası veya geçersiz PERFLAB_SINKS varsa
 func NewLogger(filename string) (*Logger, error) {
-	// Dosyayı oluştur veya aç (append mode - varsa üzerine ekle)
-	file, err := os.OpenFile(filename, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	specs, err := SinkSpecsFromEnv()
 	if err != nil {
-		return nil, fmt.Errorf("dosya oluşturulamadı: %v", err)
+		return nil, err
 	}
 
-	// Hem ekrana (os.Stdout) hem dosyaya yazmak için MultiWriter kullan
-	// Bu sayede her yazı hem terminal'e hem dosyaya gider
-	writer := io.MultiWriter(os.Stdout, file)
+	// Tüm hedefleri tek bir MultiSink'te topla
+	// Bu sayede her yazı hem terminal'e hem dosyaya (ve seçilen diğer hedeflere) gider
+	sinks, err := OpenSinks(specs, filename)
+	if err != nil {
+		return nil, fmt.Errorf("dosya oluşturulamadı: %v", err)
+	}
 
-	return &Logger{
-		file:   file,
-		writer: writer,
-	}, nil
+	logger := &Logger{
+		sinks:  sinks,
+		writer: redactWriter{w: sinks},
+		specs:  specs,
+		run: &RunResults{
+			Script:    strings.TrimSuffix(filepath.Base(filename), "_results.txt"),
+			StartedAt: time.Now(),
+		},
+	}
+	for _, arg := range os.Args[1:] {
+		logger.run.Args = append(logger.run.Args, Redact(arg))
+	}
+	// JSON özet text dosyasının yanına yazılır: file=yol ile başka bir yol seçildiyse oraya
+	for _, spec := range specs {
+		if spec.Kind == "file" {
+			path := spec.Target
+			if path == "" {
+				path = filename
+			}
+			logger.resultsPath = strings.TrimSuffix(path, filepath.Ext(path)) + ".json"
+		}
+	}
+	return logger, nil
 }
 
 // Printf - Formatlanmış string'i hem ekrana hem dosyaya yazar
@@ -57,23 +144,177 @@ func (l *Logger) Println(args ...any) (int, error) {
 	return fmt.Fprintln(l.writer, args...)
 }
 
+// WriteRecord - Metrik kaydını kayıt kabul eden hedeflere (json, mongo, http, s3) gönderir
+// Uzak bir hedef hata verirse ölçüm yarıda kesilmez, sadece uyarı yazılır
+func (l *Logger) WriteRecord(record MetricsRecord) {
+	l.attachCSVFlag()
+	l.run.Records = append(l.run.Records, record)
+	if err := l.sinks.WriteRecord(record); err != nil {
+		fmt.Printf("⚠️  Metrik kaydı yazılamadı: %s\n", Redact(err.Error()))
+	}
+}
+
+// attachCSVFlag - -csv verildiyse CSV hedefini ekler (PERFLAB_CSV ile aynı dosyaysa eklemez)
+// Bazı script'ler parametrelerini NewLogger'dan sonra parse eder, bu yüzden ilk kayıtta bakılır
+func (l *Logger) attachCSVFlag() {
+	if l.csvAttached || !flag.Parsed() {
+		return
+	}
+	l.csvAttached = true
+	if *csvFlag == "" {
+		return
+	}
+	spec := SinkSpec{Kind: "csv", Target: *csvFlag}
+	if len(appendSinkSpec(l.specs, spec)) == len(l.specs) {
+		return
+	}
+	l.specs = append(l.specs, spec)
+	l.sinks.sinks = append(l.sinks.sinks, &csvFileSink{path: *csvFlag})
+}
+
+// LatencySamples - -latency-samples verildiyse ham gecikme örneklerinin yazıcısı, yoksa nil (bkz. samples.go)
+// Dosya ilk çağrıda açılır: Worker'lar başlamadan önce çağrılmalı, dönen yazıcı goroutine'ler arasında paylaşılabilir
+func (l *Logger) LatencySamples() *LatencySamples {
+	if l.samplesOpened || *latencySamplesFlag == "" {
+		return l.samples
+	}
+	l.samplesOpened = true
+	samples, err := newLatencySamples(l.run.Script, *latencySamplesFlag)
+	if err != nil {
+		l.Printf("⚠️  Gecikme örnekleri yazılmayacak: %v\n", err)
+		return nil
+	}
+	l.samples = samples
+	return samples
+}
+
+// WriteMetrics - PrintMetrics'e verilen metrikleri JSON özete ekler
+func (l *Logger) WriteMetrics(version string, metrics QueryMetrics) {
+	l.run.Metrics = append(l.run.Metrics, VersionMetrics{Version: version, RecordedAt: time.Now(), Metrics: metrics})
+}
+
 // Close - Logger'ı kapatır ve dosyayı kapatır
-// Mutlaka defer ile çağrılmalı (dosya kaynaklarını serbest bırakmak için)
+// Mutlaka defer ile çağrılmalı (dosya kaynaklarını serbest bırakmak için); script'lerde CloseLogger ile
+// S3 gibi biriktiren hedefler yüklemeyi burada yapar
+// Kaynak sızıntısı bulunduysa *LeakError döner (çıkış kodu çağıranın kararıdır)
 func (l *Logger) Close() error {
-	if l.file != nil {
-		return l.file.Close()
+	if l.sinks == nil {
+		return nil
+	}
+	leakErr := l.checkLeaks()
+	if l.samples != nil {
+		if n, err := l.samples.Close(); err != nil {
+			l.Printf("⚠️  Gecikme örnekleri yazılamadı (%s): %v\n", l.samples.path, err)
+		} else {
+			l.Printf("\n📈 %d gecikme örneği: %s\n", n, l.samples.path)
+		}
+	}
+	if l.resultsPath != "" {
+		if err := l.writeRunResults(); err != nil {
+			fmt.Printf("⚠️  JSON sonuç dosyası yazılamadı: %s\n", Redact(err.Error()))
+		}
+	}
+	err := l.sinks.Close()
+	if err != nil {
+		fmt.Printf("⚠️  Sonuç hedefleri kapatılamadı: %s\n", Redact(err.Error()))
+	}
+	if leakErr != nil {
+		return leakErr
+	}
+	return err
+}
+
+// CloseLogger - Script'lerin main'inde ilk defer: Logger'ı kapatır, sızıntı varsa çıkış kodu 1 olur
+// İlk defer en son çalışır; script'in diğer temizlikleri bu noktada yapılmış olur.
+// Sızıntı benchmark'ı başarısız sayar: perflab run çıkış koduyla durur
+func CloseLogger(logger *Logger) {
+	var leakErr *LeakError
+	if err := logger.Close(); errors.As(err, &leakErr) {
+		os.Exit(1)
 	}
-	return nil
+}
+
+// checkLeaks - GetMongo client'larında sızıntı kontrolü yapar ve sonucu loglar (-leak-check=false ile kapanır)
+// Close'un başında çalışır: Script'in defer ettiği cursor.Close çağrıları bu noktada yapılmış olur
+func (l *Logger) checkLeaks() error {
+	mongoClients.mu.Lock()
+	tracked := len(mongoClients.clients)
+	mongoClients.mu.Unlock()
+	if !*leakCheckFlag || tracked == 0 {
+		return nil
+	}
+	warnings, err := CheckLeaks()
+	l.run.LeakWarn = warnings
+	var leakErr *LeakError
+	if errors.As(err, &leakErr) {
+		l.run.Leaks = leakErr.Leaks
+		l.Println("\n❌ Kaynak sızıntısı:")
+		for _, leak := range leakErr.Leaks {
+			l.Printf("  ❌ %s\n", leak)
+		}
+	} else {
+		l.Println("\n🔎 Kaynak kontrolü: Açık session, cursor veya transaction kalmadı")
+	}
+	for _, warning := range warnings {
+		l.Printf("  ⚠️  %s (sunucu geneli, başka client'lar da olabilir)\n", warning)
+	}
+	return err
 }
 
 // WriteHeader - Test başlığını yazar (test adı, tarih, saat vb.)
 // Bu, her test dosyasının başına yazılır
 func (l *Logger) WriteHeader(testName string) {
+	l.run.Test = testName
 	l.Printf("\n")
 	l.Printf("=" + string(make([]byte, 60)) + "\n")
 	l.Printf("TEST: %s\n", testName)
 	l.Printf("Tarih: %s\n", time.Now().Format("2006-01-02 15:04:05"))
+	l.Printf("Host: %s\n", benchkit.CollectHostInfo())
 	l.Printf("=" + string(make([]byte, 60)) + "\n")
 	l.Printf("\n")
 }
 
+// writeRunResults - JSON özeti tamamlar ve resultsPath'e yazar (her çalıştırmada baştan)
+// Ortam ve parametreler burada okunur: Script'ler parametrelerini NewLogger'dan sonra da parse edebilir
+func (l *Logger) writeRunResults() error {
+	run := l.run
+	run.FinishedAt = time.Now()
+	run.DurationMs = benchkit.Millis(run.FinishedAt.Sub(run.StartedAt))
+	run.Errors = ErrorCount()
+	host := benchkit.CollectHostInfo()
+	run.Host = &host
+	if flag.Parsed() {
+		run.Flags = map[string]string{}
+		flag.VisitAll(func(f *flag.Flag) {
+			run.Flags[f.Name] = Redact(f.Value.String())
+		})
+		// -mongo-* parametreleri flag.Func'tır, değerlerini String() döndürmez
+		for name, v := range connectionFlags {
+			run.Flags[name] = Redact(v)
+		}
+	}
+	if env, err := SelectEnvironment(); err == nil {
+		run.Config = &RunConfig{
+			Environment: env.Name,
+			URI:         Redact(env.URI),
+			Database:    env.Database,
+			Collection:  env.Collection,
+			MaxPoolSize: env.Pool.MaxSize,
+			MinPoolSize: env.Pool.MinSize,
+			TLS:         env.TLS != nil && env.TLS.Enabled,
+
+			ConnectTimeoutMs:         benchkit.Millis(env.Timeouts.Connect),
+			ServerSelectionTimeoutMs: benchkit.Millis(env.Timeouts.ServerSelection),
+			SocketTimeoutMs:          benchkit.Millis(env.Timeouts.Socket),
+			Repetition:               os.Getenv("PERFLAB_REPETITION"),
+			MetricsFile:              os.Getenv("PERFLAB_METRICS_FILE"),
+			ConfigSource:             ConfigPath(),
+		}
+	}
+
+	data, err := json.MarshalIndent(run, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(l.resultsPath, append(data, '\n'), 0644)
+}
@@ -2,9 +2,19 @@ package main
 
 import (
 	"context"
+	"flag"
+	"fmt"
 	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
+	"benchkit"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/event"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
@@ -12,14 +22,284 @@ import (
 func GetMongo() *mongo.Collection {
 	ctx, _ := context.WithTimeout(context.Background(), 10*time.Second)
 
-	client, err := mongo.Connect(ctx, options.Client().
-		ApplyURI("mongodb://localhost:27017").
-		SetMaxPoolSize(100),
-	)
+	client, err := mongo.Connect(ctx, MongoClientOptions())
 
+	if err != nil {
+		log.Fatal(Redact(err.Error()))
+	}
+
+	trackClient(client)
+	env := CurrentEnvironment()
+	col := client.Database(env.Database).Collection(env.Collection)
+
+	// PERFLAB_PREWARM=N: Ölçümden önce havuzda N bağlantı açılır, böylece ilk sorgular
+	// TCP + handshake + auth maliyetini ödemez ve benchmark'lar kararlı durumu (steady-state) ölçer
+	if n, _ := strconv.Atoi(os.Getenv("PERFLAB_PREWARM")); n > 0 {
+		ready, elapsed := WarmUpPool(col, n)
+		fmt.Printf("🔥 Bağlantı havuzu ısıtıldı: %d bağlantı, %v\n", ready, elapsed.Round(time.Millisecond))
+	}
+
+	return col
+}
+
+// leakCheckFlag - Çıkışta kaynak sızıntısı kontrolü (bkz. CheckLeaks, Logger.Close)
+var leakCheckFlag = flag.Bool("leak-check", true, "Çıkışta kapatılmamış session, cursor ve transaction'ları kontrol et (sızıntı varsa çıkış kodu 1)")
+
+// LeakError - Bu process'in client'larında serbest bırakılmamış kaynaklar (bkz. CheckLeaks)
+type LeakError struct {
+	Leaks []string
+}
+
+func (e *LeakError) Error() string {
+	return fmt.Sprintf("%d kaynak sızıntısı: %s", len(e.Leaks), strings.Join(e.Leaks, "; "))
+}
+
+// mongoClients - GetMongo ile açılan client'lar ve ilk bağlantıdaki sunucu kaynakları
+var mongoClients struct {
+	mu       sync.Mutex
+	clients  []*mongo.Client
+	baseline *serverResources // nil = serverStatus okunamadı (ör: clusterMonitor yetkisi yok)
+}
+
+// serverResources - Sunucuda açık kalan kaynaklar (serverStatus)
+type serverResources struct {
+	OpenCursors      int64
+	OpenTransactions int64
+}
+
+// trackClient - Client'ı sızıntı kontrolüne ekler; ilk client'ta sunucunun başlangıç durumu okunur
+func trackClient(client *mongo.Client) {
+	mongoClients.mu.Lock()
+	defer mongoClients.mu.Unlock()
+	if len(mongoClients.clients) == 0 {
+		if res, err := readServerResources(client); err == nil {
+			mongoClients.baseline = &res
+		}
+	}
+	mongoClients.clients = append(mongoClients.clients, client)
+}
+
+// readServerResources - serverStatus'tan açık cursor ve transaction sayıları
+func readServerResources(client *mongo.Client) (serverResources, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	var status struct {
+		Metrics struct {
+			Cursor struct {
+				Open struct {
+					Total int64 `bson:"total"`
+				} `bson:"open"`
+			} `bson:"cursor"`
+		} `bson:"metrics"`
+		Transactions struct {
+			CurrentOpen int64 `bson:"currentOpen"`
+		} `bson:"transactions"`
+	}
+	err := client.Database("admin").RunCommand(ctx, bson.D{{Key: "serverStatus", Value: 1}}).Decode(&status)
+	return serverResources{OpenCursors: status.Metrics.Cursor.Open.Total, OpenTransactions: status.Transactions.CurrentOpen}, err
+}
+
+// CheckLeaks - Benchmark bittiğinde serbest bırakılmamış kaynakları bulur
+// Sızıntı (*LeakError): Bu process'in client'larında bitmemiş session'lar. Close edilmeyen (ve sonuna
+// kadar okunmayan) her cursor örtük session'ını tutar, EndSession çağrılmayan session'lar ve
+// bitirilmemiş transaction'lar da burada görünür.
+// Uyarı: İlk bağlantıya göre artan açık cursor ve transaction sayısı (serverStatus). Sunucu geneli
+// sayaçlardır - aynı anda çalışan başka client'lar (ör: -coord ile eşler, ingest) da artırır - bu yüzden
+// çalıştırmayı başarısız saymaz, sadece raporlanır.
+func CheckLeaks() (warnings []string, err error) {
+	mongoClients.mu.Lock()
+	defer mongoClients.mu.Unlock()
+
+	var leaks []string
+	for i, client := range mongoClients.clients {
+		if n := client.NumberSessionsInProgress(); n > 0 {
+			leaks = append(leaks, fmt.Sprintf("client %d: %d session bitmedi (Close edilmemiş cursor veya EndSession çağrılmamış session)", i+1, n))
+		}
+	}
+	if len(leaks) > 0 {
+		err = &LeakError{Leaks: leaks}
+	}
+	if base := mongoClients.baseline; base != nil {
+		now, readErr := readServerResources(mongoClients.clients[0])
+		if readErr != nil {
+			return nil, err
+		}
+		if d := now.OpenCursors - base.OpenCursors; d > 0 {
+			warnings = append(warnings, fmt.Sprintf("sunucuda %d cursor fazla açık (metrics.cursor.open.total %d → %d)", d, base.OpenCursors, now.OpenCursors))
+		}
+		if d := now.OpenTransactions - base.OpenTransactions; d > 0 {
+			warnings = append(warnings, fmt.Sprintf("sunucuda %d transaction fazla açık (transactions.currentOpen %d → %d)", d, base.OpenTransactions, now.OpenTransactions))
+		}
+	}
+	return warnings, err
+}
+
+// MongoClientOptions - GetMongo'nun kullandığı client ayarları
+// Bağlantı adresi, kimlik bilgileri, havuz, timeout ve TLS ayarları seçili ortamdan gelir
+// (perflab.yaml + PERFLAB_MONGO_* + -mongo-* parametreleri, bkz. config.go)
+// Yeni client oluşturması gereken script'ler (ör: warmup) aynı ayarları ve izleyicileri kullanır
+func MongoClientOptions() *options.ClientOptions {
+	opts, err := CurrentEnvironment().ClientOptions()
 	if err != nil {
 		log.Fatal(err)
 	}
+	return opts.
+		SetMonitor(&event.CommandMonitor{Succeeded: phaseRecorder.succeeded}).
+		SetPoolMonitor(&event.PoolMonitor{Event: poolRecorder.event})
+}
+
+// PoolStats - Bağlantı havuzu olay sayaçları
+type PoolStats struct {
+	Created       int           // Açılan bağlantı sayısı
+	Ready         int           // Handshake'i tamamlanıp kullanıma hazır hale gelen bağlantı sayısı
+	EstablishTime time.Duration // Hazır bağlantıların toplam kurulma süresi (TCP + TLS + handshake + auth)
+	CheckedOut    int           // Havuzdan bağlantı alma sayısı
+}
+
+// poolRecorder - GetMongo/MongoClientOptions ile oluşturulan client'ların havuz izleyicisi
+var poolRecorder = &poolEventRecorder{}
 
-	return client.Database("perfdb").Collection("orders")
+type poolEventRecorder struct {
+	mu    sync.Mutex
+	stats PoolStats
+}
+
+func (r *poolEventRecorder) event(e *event.PoolEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	switch e.Type {
+	case event.ConnectionCreated:
+		r.stats.Created++
+	case event.ConnectionReady:
+		r.stats.Ready++
+		r.stats.EstablishTime += e.Duration
+	case event.GetSucceeded:
+		r.stats.CheckedOut++
+	}
+}
+
+// ResetPoolStats - Havuz sayaçlarını sıfırlar
+func ResetPoolStats() {
+	poolRecorder.mu.Lock()
+	poolRecorder.stats = PoolStats{}
+	poolRecorder.mu.Unlock()
+}
+
+// SnapshotPoolStats - ResetPoolStats'tan bu yana toplanan havuz sayaçlarını döndürür
+func SnapshotPoolStats() PoolStats {
+	poolRecorder.mu.Lock()
+	defer poolRecorder.mu.Unlock()
+	return poolRecorder.stats
+}
+
+// WarmUpPool - Havuzda en az n bağlantı açılana kadar eşzamanlı ping gönderir
+// Driver aynı anda en fazla 2 bağlantı kurar (maxConnecting), bekleyen istekler de boşa çıkan
+// bağlantıları kapabilir. Bu yüzden tek dalga yetmeyebilir - n bağlantıya ulaşana kadar
+// (en fazla 10 dalga) tekrarlanır.
+// Döndürür: Isıtma sırasında açılan bağlantı sayısı ve geçen süre
+func WarmUpPool(col *mongo.Collection, n int) (int, time.Duration) {
+	start := time.Now()
+	db := col.Database()
+	before := SnapshotPoolStats().Ready
+
+	for wave := 0; wave < 10 && SnapshotPoolStats().Ready-before < n; wave++ {
+		var wg sync.WaitGroup
+		for i := 0; i < n; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				db.RunCommand(context.Background(), bson.D{{Key: "ping", Value: 1}})
+			}()
+		}
+		wg.Wait()
+	}
+	return SnapshotPoolStats().Ready - before, time.Since(start)
+}
+
+// CursorPhases - Bir okuma işleminin aşamalara bölünmüş süreleri
+// Toplam süre tek başına "yavaş" der ama nedenini söylemez. Bu yapı süreyi
+// ilk batch (find/aggregate komutu), getMore'lar ve client tarafı decode olarak ayırır:
+// - FirstBatch + GetMores: sunucu + network süresi (driver'ın komut izlemesiyle ölçülür)
+// - Decode: BSON → Go dönüşümü (client CPU'su)
+type CursorPhases struct {
+	FirstBatch      time.Duration   `json:"firstBatchNs"`    // find/aggregate komutlarının toplam süresi (ilk batch dahil)
+	FirstBatchCount int             `json:"firstBatchCount"` // Kaç cursor açıldı (paralel okumada > 1)
+	GetMores        []time.Duration `json:"getMoresNs"`      // Her getMore komutunun süresi (sıralı)
+	Decode          time.Duration   `json:"decodeNs"`        // cursor.Decode içinde geçen toplam süre (0 = ölçülmedi)
+	ReplyBytes      int64           `json:"replyBytes"`      // Sunucudan gelen cevapların toplam boyutu (network'e taşınan veri)
+	ClientCPU       time.Duration   `json:"clientCpuNs"`     // Ölçülen bölümde process'in harcadığı CPU (user + system, tüm goroutine'ler)
+}
+
+// GetMoreTotal - Tüm getMore komutlarının toplam süresi
+func (p *CursorPhases) GetMoreTotal() time.Duration {
+	var total time.Duration
+	for _, d := range p.GetMores {
+		total += d
+	}
+	return total
+}
+
+// phaseRecorder - GetMongo ile oluşturulan client'ın komut izleyicisi
+// Driver her komut tamamlandığında süresini buraya bildirir
+var phaseRecorder = &commandPhaseRecorder{}
+
+type commandPhaseRecorder struct {
+	mu       sync.Mutex
+	phases   CursorPhases
+	cpuStart time.Duration // ResetCursorPhases anındaki process CPU süresi
+}
+
+func (r *commandPhaseRecorder) succeeded(_ context.Context, e *event.CommandSucceededEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	switch e.CommandName {
+	case "find", "aggregate":
+		r.phases.FirstBatch += e.Duration
+		r.phases.FirstBatchCount++
+		r.phases.ReplyBytes += int64(len(e.Reply))
+	case "getMore":
+		r.phases.GetMores = append(r.phases.GetMores, e.Duration)
+		r.phases.ReplyBytes += int64(len(e.Reply))
+	}
+}
+
+// ResetCursorPhases - Ölçülen bölümün başında çağrılır
+// (explain, count gibi hazırlık komutları aşama sürelerine karışmasın diye)
+func ResetCursorPhases() {
+	phaseRecorder.mu.Lock()
+	phaseRecorder.phases = CursorPhases{}
+	phaseRecorder.cpuStart = benchkit.CPUTime()
+	phaseRecorder.mu.Unlock()
+}
+
+// SnapshotCursorPhases - ResetCursorPhases'ten bu yana toplanan aşama sürelerini döndürür
+// Parametreler:
+//   - decode: Script'in kendi ölçtüğü toplam decode süresi
+func SnapshotCursorPhases(decode time.Duration) *CursorPhases {
+	phaseRecorder.mu.Lock()
+	defer phaseRecorder.mu.Unlock()
+
+	phases := phaseRecorder.phases
+	phases.GetMores = append([]time.Duration(nil), phases.GetMores...)
+	sort.Slice(phases.GetMores, func(i, j int) bool { return phases.GetMores[i] < phases.GetMores[j] })
+	phases.Decode = decode
+	phases.ClientCPU = benchkit.CPUTime() - phaseRecorder.cpuStart
+	return &phases
+}
+
+// toInt64 - MongoDB'den gelen sayısal değeri (int32/int64/double) int64'e çevirir
+func toInt64(v interface{}) int64 {
+	switch n := v.(type) {
+	case int: // Go tarafında oluşturulan pipeline'lar (decode edilmemiş)
+		return int64(n)
+	case int32:
+		return int64(n)
+	case int64:
+		return n
+	case float64:
+		return int64(n)
+	}
+	return 0
 }
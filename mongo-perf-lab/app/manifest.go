@@ -0,0 +1,374 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"benchkit"
+	"go.mongodb.org/mongo-driver/bson"
+	"gopkg.in/yaml.v3"
+)
+
+// manifest.go - Deney (experiment) manifest dosyası formatı
+// Bir manifest, bir deneyi baştan sona tanımlar:
+// veri seti, oluşturulacak index'ler, çalıştırılacak benchmark'lar,
+// tekrar sayıları, doğrulamalar (assertion) ve çıktı formatları.
+//
+// Böylece bir deney YAML dosyası olarak code review'dan geçebilir ve
+// herkes aynı deneyi `perflab run -f experiment.yaml` ile tekrar çalıştırabilir.
+//
+// Örnek manifest: experiments/paid_orders.yaml
+
+// Manifest - Deney tanımının tamamı
+type Manifest struct {
+	Name        string          `yaml:"name" json:"name"`
+	Description string          `yaml:"description" json:"description"`
+	Hypothesis  string          `yaml:"hypothesis" json:"hypothesis,omitempty"` // Beklenen sonuç (writeup raporunun başına yazılır)
+	Dataset     DatasetProfile  `yaml:"dataset" json:"dataset"`
+	Indexes     []IndexSpec     `yaml:"indexes" json:"indexes"`
+	Ingest      *IngestSpec     `yaml:"ingest" json:"ingest,omitempty"` // Benchmark'lar sırasında arka plan yazma yükü
+	Benchmarks  []BenchmarkSpec `yaml:"benchmarks" json:"benchmarks"`
+	Assertions  []AssertionSpec `yaml:"assertions" json:"assertions"`
+	Outputs     []string        `yaml:"outputs" json:"outputs"`       // "text", "json"
+	Sinks       []string        `yaml:"sinks" json:"sinks,omitempty"` // Benchmark script'lerinin sonuç hedefleri (PERFLAB_SINKS, bkz. sink.go)
+	Cost        *CostSpec       `yaml:"cost" json:"cost,omitempty"`   // Opsiyonel maliyet/enerji tahmini
+}
+
+// CostSpec - Özette maliyet tahmini için kullanılacak model
+// Preset seçilir, istenen alanlar ezilir:
+//
+//	cost:
+//	  preset: atlas-m30
+//	  egressPerGBUSD: 0.02   # aynı bölge içi trafik
+type CostSpec struct {
+	Preset             string `yaml:"preset" json:"preset,omitempty"` // atlas-m10, atlas-m30, atlas-m40, atlas-m50, local
+	benchkit.CostModel `yaml:",inline"`
+}
+
+// IndexSpec - Oluşturulacak index
+// Keys sıralı olmalı (compound index'lerde alan sırası önemli), bu yüzden
+// map yerine "alan:yön" listesi kullanıyoruz: ["status:1", "createdAt:-1"]
+type IndexSpec struct {
+	Name string   `yaml:"name" json:"name"`
+	Keys []string `yaml:"keys" json:"keys"`
+}
+
+// BenchmarkSpec - Çalıştırılacak benchmark
+// Name, app klasöründeki script adıdır (read_v3 → read_v3.go)
+type BenchmarkSpec struct {
+	Name        string   `yaml:"name" json:"name"`
+	Repetitions int      `yaml:"repetitions" json:"repetitions"` // Kaç kez tekrar edilecek (varsayılan 1)
+	Args        []string `yaml:"args" json:"args"`               // Script'e geçilecek ek parametreler
+	Prewarm     int      `yaml:"prewarm" json:"prewarm"`         // Ölçümden önce havuzda açılacak bağlantı sayısı (0 = ısıtma yok)
+}
+
+// IngestSpec - Benchmark'lar çalışırken arka planda sürekli order ekleyen yazma yükü (growth script'i)
+// Tanımlanmazsa benchmark'lar yazma olmadan çalışır
+type IngestSpec struct {
+	Rate      float64 `yaml:"rate" json:"rate"`           // Hedef yazma hızı (doküman/sn)
+	BatchSize int     `yaml:"batchSize" json:"batchSize"` // InsertMany batch boyutu (varsayılan 50)
+	Shape     string  `yaml:"shape" json:"shape"`         // Doküman şekli: uniform veya varied
+}
+
+// AssertionSpec - Benchmark sonuçları üzerinde doğrulama
+// Örnek: read_v3'ün ortalama süresi 2000 ms'yi geçmemeli
+//   - benchmark: read_v3
+//     metric: duration_ms
+//     max: 2000
+//
+// Birden fazla kayıt yazan benchmark'larda (ör: insert_bench) variant ile tek kombinasyon seçilir;
+// variant verilmezse benchmark'ın tüm kayıtlarının ortalaması alınır.
+type AssertionSpec struct {
+	Benchmark string   `yaml:"benchmark" json:"benchmark"`
+	Variant   string   `yaml:"variant" json:"variant,omitempty"`
+	Metric    string   `yaml:"metric" json:"metric"` // MetricsRecord.Value ile okunabilen metrik adı
+	Min       *float64 `yaml:"min" json:"min,omitempty"`
+	Max       *float64 `yaml:"max" json:"max,omitempty"`
+}
+
+// LoadManifest - YAML manifest dosyasını okur ve doğrular
+// Parametreler:
+//   - path: Manifest dosyasının yolu
+//
+// Döndürür:
+//   - *Manifest: Varsayılan değerleri doldurulmuş manifest
+//   - error: Dosya okunamazsa veya manifest geçersizse
+func LoadManifest(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("manifest okunamadı: %v", err)
+	}
+
+	var m Manifest
+	if err := yaml.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("manifest parse edilemedi: %v", err)
+	}
+
+	// Varsayılan değerler
+	if m.Name == "" {
+		m.Name = strings.TrimSuffix(strings.TrimSuffix(path, ".yaml"), ".yml")
+	}
+	if m.Dataset.BatchSize == 0 {
+		m.Dataset.BatchSize = 1000
+	}
+	if len(m.Outputs) == 0 {
+		m.Outputs = []string{"text"}
+	}
+	if m.Ingest != nil && m.Ingest.BatchSize == 0 {
+		m.Ingest.BatchSize = 50
+	}
+	for i := range m.Benchmarks {
+		if m.Benchmarks[i].Repetitions == 0 {
+			m.Benchmarks[i].Repetitions = 1
+		}
+	}
+
+	return &m, m.Validate()
+}
+
+// Validate - Manifest'in tutarlı olup olmadığını kontrol eder
+// Hataları deney başlamadan yakalamak, 10 dakikalık bir çalıştırmanın
+// sonunda yazım hatası yüzünden patlamasından iyidir
+func (m *Manifest) Validate() error {
+	if len(m.Benchmarks) == 0 {
+		return fmt.Errorf("manifest en az bir benchmark içermeli")
+	}
+
+	known := map[string]bool{}
+	for _, b := range m.Benchmarks {
+		if b.Name == "" {
+			return fmt.Errorf("benchmark adı boş olamaz")
+		}
+		if b.Repetitions < 0 {
+			return fmt.Errorf("%s: repetitions negatif olamaz", b.Name)
+		}
+		if b.Prewarm < 0 {
+			return fmt.Errorf("%s: prewarm negatif olamaz", b.Name)
+		}
+		known[b.Name] = true
+	}
+
+	if f := m.Dataset.DateFormat; f != "" && f != "date" && f != "string" {
+		return fmt.Errorf("dataset: geçersiz dateFormat %q (date veya string olmalı)", f)
+	}
+	if err := m.Dataset.Shape.Validate(); err != nil {
+		return fmt.Errorf("dataset: %v", err)
+	}
+	if p := m.Dataset.DuplicatePct; p < 0 || p >= 100 {
+		return fmt.Errorf("dataset: duplicatePct 0 ile 100 arasında olmalı: %v", p)
+	}
+
+	if m.Ingest != nil {
+		if m.Ingest.Rate <= 0 {
+			return fmt.Errorf("ingest: rate pozitif olmalı")
+		}
+		if err := (ShapeProfile{Mode: m.Ingest.Shape}).Validate(); err != nil {
+			return fmt.Errorf("ingest: %v", err)
+		}
+	}
+
+	for _, idx := range m.Indexes {
+		if _, err := idx.KeysDoc(); err != nil {
+			return err
+		}
+	}
+
+	for _, a := range m.Assertions {
+		if !known[a.Benchmark] {
+			return fmt.Errorf("assertion bilinmeyen benchmark'a ait: %s", a.Benchmark)
+		}
+		if _, ok := (MetricsRecord{}).Value(a.Metric); !ok {
+			return fmt.Errorf("assertion bilinmeyen metrik kullanıyor: %s", a.Metric)
+		}
+		if a.Min == nil && a.Max == nil {
+			return fmt.Errorf("%s/%s assertion'ı min veya max içermeli", a.Name(), a.Metric)
+		}
+	}
+
+	for _, out := range m.Outputs {
+		if out != "text" && out != "json" {
+			return fmt.Errorf("bilinmeyen çıktı formatı: %s (text, json)", out)
+		}
+	}
+
+	if m.Cost != nil {
+		if _, err := m.Cost.Model(); err != nil {
+			return fmt.Errorf("cost: %v", err)
+		}
+	}
+
+	if len(m.Sinks) > 0 {
+		specs, err := ParseSinkSpecs(strings.Join(m.Sinks, ","))
+		if err != nil {
+			return fmt.Errorf("sinks: %v", err)
+		}
+		// text çıktısı, script'in _results.txt dosyasından kopyalanır
+		hasFile := false
+		for _, s := range specs {
+			if s.Kind == "file" && s.Target == "" {
+				hasFile = true
+			}
+		}
+		if !hasFile {
+			for _, out := range m.Outputs {
+				if out == "text" {
+					return fmt.Errorf("sinks: text çıktısı için listede file olmalı")
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Model - Preset ve override'lardan son maliyet modelini oluşturur
+func (c CostSpec) Model() (benchkit.CostModel, error) {
+	return benchkit.ResolveCostModel(c.Preset, c.CostModel)
+}
+
+// KeysDoc - "alan:yön" listesini sıralı bson.D'ye çevirir
+func (idx IndexSpec) KeysDoc() (bson.D, error) {
+	if len(idx.Keys) == 0 {
+		return nil, fmt.Errorf("index %q en az bir alan içermeli", idx.Name)
+	}
+
+	keys := bson.D{}
+	for _, k := range idx.Keys {
+		field, dir, found := strings.Cut(k, ":")
+		if !found {
+			dir = "1"
+		}
+		direction, err := strconv.Atoi(dir)
+		if err != nil || (direction != 1 && direction != -1) {
+			return nil, fmt.Errorf("index %q: geçersiz yön %q (1 veya -1)", idx.Name, k)
+		}
+		keys = append(keys, bson.E{Key: field, Value: direction})
+	}
+	return keys, nil
+}
+
+// EnvironmentProfile - perflab.yaml'daki bir ortamın deneye etki eden kısmı
+// Bağlantı kısmı (uri, credentials) config.go'dadır; ikisi aynı ortam tanımından okunur:
+//
+//	environments:
+//	  staging:
+//	    uri: mongodb://staging-db:27017
+//	    dataset:
+//	      documents: 10000000
+//	    thresholds:
+//	      - benchmark: read_v2
+//	        metric: duration_ms
+//	        max: 20000
+type EnvironmentProfile struct {
+	Dataset    *DatasetProfile `yaml:"dataset"`    // Manifest'in veri setini ezen alanlar (ör: staging'de 10 kat veri)
+	Thresholds []AssertionSpec `yaml:"thresholds"` // Bu ortamda geçerli assertion sınırları
+	ShardKey   string          `yaml:"shardKey"`   // Sharded kümede koleksiyonun shard key'i, ör: "_id:hashed" (bkz. topology.go)
+}
+
+// LoadEnvironmentProfile - Seçili ortamın dataset/thresholds ayarlarını okur
+// Config dosyası olmadan çalışan yerel ortamda boş profil döner
+func LoadEnvironmentProfile(env *Environment) (*EnvironmentProfile, error) {
+	data, err := os.ReadFile(ConfigPath())
+	if os.IsNotExist(err) {
+		return &EnvironmentProfile{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var config struct {
+		Environments map[string]*EnvironmentProfile `yaml:"environments"`
+	}
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("%s parse edilemedi: %v", ConfigPath(), err)
+	}
+	profile := config.Environments[env.Name]
+	if profile == nil {
+		return &EnvironmentProfile{}, nil
+	}
+
+	if profile.Dataset != nil {
+		if err := profile.Dataset.Shape.Validate(); err != nil {
+			return nil, fmt.Errorf("%s: dataset: %v", env.Name, err)
+		}
+	}
+	if profile.ShardKey != "" {
+		if _, err := ParseShardKey(profile.ShardKey); err != nil {
+			return nil, fmt.Errorf("%s: shardKey: %v", env.Name, err)
+		}
+	}
+	for _, t := range profile.Thresholds {
+		if _, ok := (MetricsRecord{}).Value(t.Metric); !ok {
+			return nil, fmt.Errorf("%s: threshold bilinmeyen metrik kullanıyor: %s", env.Name, t.Metric)
+		}
+		if t.Min == nil && t.Max == nil {
+			return nil, fmt.Errorf("%s: %s/%s threshold'u min veya max içermeli", env.Name, t.Name(), t.Metric)
+		}
+	}
+	return profile, nil
+}
+
+// ApplyTo - Ortamın veri seti ve eşiklerini manifest'e uygular
+// Veri setinde sıfır olmayan alanlar ezilir; eşikler aynı benchmark/variant/metrik
+// assertion'ının sınırlarını değiştirir, eşleşen yoksa yeni assertion olarak eklenir.
+// Manifest'te olmayan benchmark'ların eşikleri atlanır (ortam tüm manifest'ler için ortaktır).
+func (e *EnvironmentProfile) ApplyTo(m *Manifest) {
+	if d := e.Dataset; d != nil {
+		if d.Documents > 0 {
+			m.Dataset.Documents = d.Documents
+		}
+		if d.BatchSize > 0 {
+			m.Dataset.BatchSize = d.BatchSize
+		}
+		if d.Seed != 0 {
+			m.Dataset.Seed = d.Seed
+		}
+		if d.Workers > 0 {
+			m.Dataset.Workers = d.Workers
+		}
+		if d.DateFormat != "" {
+			m.Dataset.DateFormat = d.DateFormat
+		}
+		if d.Shape.Mode != "" {
+			m.Dataset.Shape = d.Shape
+		}
+		if d.DuplicatePct > 0 {
+			m.Dataset.DuplicatePct = d.DuplicatePct
+		}
+		m.Dataset.Drop = m.Dataset.Drop || d.Drop
+	}
+
+	known := map[string]bool{}
+	for _, b := range m.Benchmarks {
+		known[b.Name] = true
+	}
+threshold:
+	for _, t := range e.Thresholds {
+		if !known[t.Benchmark] {
+			continue
+		}
+		for i, a := range m.Assertions {
+			if a.Benchmark == t.Benchmark && a.Variant == t.Variant && a.Metric == t.Metric {
+				m.Assertions[i].Min, m.Assertions[i].Max = t.Min, t.Max
+				continue threshold
+			}
+		}
+		m.Assertions = append(m.Assertions, t)
+	}
+}
+
+// Name - Assertion'ın hedefi: benchmark veya "benchmark/varyant"
+func (a AssertionSpec) Name() string {
+	return MetricsRecord{Benchmark: a.Benchmark, Variant: a.Variant}.Name()
+}
+
+// Check - Assertion'ı verilen değer için değerlendirir
+func (a AssertionSpec) Check(value float64) bool {
+	if a.Min != nil && value < *a.Min {
+		return false
+	}
+	if a.Max != nil && value > *a.Max {
+		return false
+	}
+	return true
+}
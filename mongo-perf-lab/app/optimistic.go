@@ -0,0 +1,221 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"benchkit"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// optimistic.go - Optimistic concurrency (version alanı) vs last-write-wins
+// İki yöntem de read-modify-write yapar: dokümanı okur, yeni değeri client'ta hesaplar, yazar.
+//
+//	lww: updateOne {_id}           → $set value  (araya giren yazma sessizce ezilir)
+//	cas: updateOne {_id, version}  → $set value, version+1 (matchedCount 0 = çakışma → tekrar oku, tekrar dene)
+//
+// Her mantıksal işlem sayacı 1 artırır, bu yüzden sonunda beklenen toplam bellidir:
+// lww'de eksik kalan miktar kaybolan güncellemelerdir (lost update), cas'ta her çakışma
+// bir yeniden denemedir. Çekişme, güncellenen doküman sayısıyla (-keys) ayarlanır:
+// 1 doküman = tüm worker'lar aynı dokümanı günceller, 10000 doküman = çakışma nadir.
+//
+// Efektif verim: Saniyede tamamlanan mantıksal işlem (cas'ta yeniden denemeler verimi düşürür,
+// lww'de kaybolan güncellemeler verime sayılmaz).
+//
+// Not: Sayaç gibi tek alanlı değişikliklerde doğru çözüm $inc'tir (sunucuda atomik, çakışma yok);
+// bu benchmark client'ta hesaplanan değişiklikleri (durum geçişi, doküman birleştirme) modeller.
+//
+// KULLANIM:
+//   go run main.go config.go analyzer.go antipattern.go errors.go logger.go samples.go sink.go flags.go latency.go optimistic.go
+//   go run main.go config.go analyzer.go antipattern.go errors.go logger.go samples.go sink.go flags.go latency.go optimistic.go -keys 1,4,64 -workers 32
+
+// occCounter - Güncellenen doküman
+type occCounter struct {
+	ID      int   `bson:"_id"`
+	Value   int64 `bson:"value"`
+	Version int64 `bson:"version"`
+}
+
+// occResult - Bir yöntem + çekişme seviyesinin sonucu
+type occResult struct {
+	latencyCase
+	method    string
+	keys      int
+	attempts  int64 // Yazma denemesi (cas'ta çakışmalar dahil)
+	conflicts int64
+	gaveUp    int64 // -max-retries aşıldığı için tamamlanamayan işlem
+	lost      int64 // Beklenen - saklanan toplam
+}
+
+func main() {
+	keyList := flag.String("keys", "1,16,256,4096", "Güncellenen doküman sayıları (az = yüksek çekişme, tarama)")
+	workers := flag.Int("workers", 16, "Eşzamanlı güncelleme yapan goroutine sayısı")
+	ops := flag.Int("ops", 2000, "Worker başına mantıksal güncelleme")
+	maxRetries := flag.Int("max-retries", 1000, "cas: Bir işlemin en fazla deneme sayısı")
+	seed := flag.Int64("seed", 42, "Doküman seçimi için seed")
+	flag.Parse()
+
+	keySizes, err := parseIntList(*keyList)
+	if err != nil {
+		fmt.Printf("Geçersiz -keys: %v\n", err)
+		return
+	}
+	if *workers <= 0 || *ops <= 0 || *maxRetries <= 0 {
+		fmt.Println("❌ -workers, -ops ve -max-retries pozitif olmalı")
+		return
+	}
+
+	logger, err := NewLogger("optimistic_results.txt")
+	if err != nil {
+		fmt.Printf("Logger oluşturulamadı: %v\n", err)
+		return
+	}
+	defer CloseLogger(logger)
+
+	logger.WriteHeader("optimistic - Optimistic Concurrency (version) vs Last-Write-Wins")
+
+	col := GetMongo().Database().Collection("occ_counters")
+	ctx := context.Background()
+	logger.Printf("📋 %d worker × %d işlem, doküman sayıları %v, cas en fazla %d deneme\n", *workers, *ops, keySizes, *maxRetries)
+
+	var results []occResult
+	for _, keys := range keySizes {
+		for _, method := range []string{"lww", "cas"} {
+			logger.Printf("\n▶️  %s, %d doküman...\n", method, keys)
+			r, ok := runOCC(ctx, col, method, keys, *workers, *ops, *maxRetries, *seed, logger)
+			if !ok {
+				continue
+			}
+			logger.Printf("  %.0f işlem/sn, %d deneme, %d çakışma, %d kayıp güncelleme, p99 %v\n",
+				float64(r.docs)/r.duration.Seconds(), r.attempts, r.conflicts, r.lost, r.Summary().P99.Round(time.Microsecond))
+			results = append(results, r)
+		}
+	}
+	if len(results) == 0 {
+		PrintErrorSummary(logger)
+		return
+	}
+
+	logger.Println("\n=== KARŞILAŞTIRMA ===")
+	logger.Printf("  %-12s %8s %12s %12s %10s %12s %12s %10s %10s\n",
+		"durum", "doküman", "efektif/sn", "deneme/işlem", "çakışma %", "vazgeçilen", "kayıp", "p50", "p99")
+	for _, r := range results {
+		s := r.Summary()
+		logger.Printf("  %-12s %8d %12.0f %12.2f %10.1f %12d %12d %10v %10v\n",
+			r.method, r.keys, float64(r.docs)/r.duration.Seconds(), float64(r.attempts)/float64(max(r.ops, 1)),
+			float64(r.conflicts)/float64(max(r.attempts, 1))*100, r.gaveUp, r.lost,
+			s.P50.Round(time.Microsecond), s.P99.Round(time.Microsecond))
+
+		record := latencyRecord("optimistic", r.latencyCase)
+		record.DocsPerSec = float64(r.docs) / r.duration.Seconds()
+		record.Conflicts = r.conflicts
+		record.LostUpdates = r.lost
+		logger.WriteRecord(record)
+	}
+
+	logger.Println("\n💡 lww hızlı görünür ama çekişme arttıkça kayıp güncelleme sayısı da artar - sonuç yanlıştır.")
+	logger.Println("💡 cas doğru sonucu verir; bedeli çakışma oranı kadar ek okuma + yazmadır. Çakışma %'si yüksekse")
+	logger.Println("   sıcak dokümanı bölmek (sharded counter) veya değişikliği $inc/$push gibi atomik operatörlerle")
+	logger.Println("   sunucuya yaptırmak yeniden denemeleri tamamen ortadan kaldırır.")
+
+	PrintErrorSummary(logger)
+
+	logger.Println("\n✅ Test tamamlandı! Sonuçlar 'optimistic_results.txt' dosyasına kaydedildi.")
+}
+
+// runOCC - Dokümanları sıfırlar ve workers × ops mantıksal güncellemeyi yöntemle çalıştırır
+func runOCC(ctx context.Context, col *mongo.Collection, method string, keys, workers, ops, maxRetries int, seed int64, logger *Logger) (occResult, bool) {
+	r := occResult{method: method, keys: keys}
+	r.name = fmt.Sprintf("%s/k%d", method, keys)
+	r.ops = workers * ops
+
+	if HandleError(logger, "drop", col.Drop(ctx)) {
+		return r, false
+	}
+	docs := make([]interface{}, keys)
+	for i := range docs {
+		docs[i] = occCounter{ID: i}
+	}
+	if _, err := col.InsertMany(ctx, docs); HandleError(logger, "insert", err) {
+		return r, false
+	}
+
+	hists := make([]*benchkit.Histogram, workers)
+	samples := logger.LatencySamples()
+	var done, attempts, conflicts, gaveUp int64
+	var wg sync.WaitGroup
+	start := time.Now()
+	for w := 0; w < workers; w++ {
+		hists[w] = benchkit.NewHistogram()
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			// Her yöntem aynı doküman sırasını kullanır: Fark sadece yazma yönteminden gelir
+			rng := rand.New(rand.NewSource(seed + int64(w)))
+			for i := 0; i < ops; i++ {
+				id := rng.Intn(keys)
+				opStart := time.Now()
+				ok := false
+				for try := 0; try < maxRetries && !ok; try++ {
+					var current occCounter
+					if err := col.FindOne(ctx, bson.M{"_id": id}).Decode(&current); HandleError(logger, "find", err) {
+						break
+					}
+					filter := bson.M{"_id": id}
+					if method == "cas" {
+						filter["version"] = current.Version
+					}
+					res, err := col.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"value": current.Value + 1, "version": current.Version + 1}})
+					atomic.AddInt64(&attempts, 1)
+					if HandleError(logger, "update", err) {
+						break
+					}
+					if res.MatchedCount == 0 {
+						atomic.AddInt64(&conflicts, 1)
+						continue
+					}
+					ok = true
+				}
+				elapsed := time.Since(opStart)
+				hists[w].Record(elapsed)
+				samples.Record(method, w, opStart, elapsed, ok)
+				if !ok {
+					atomic.AddInt64(&gaveUp, 1)
+					continue
+				}
+				atomic.AddInt64(&done, 1)
+			}
+		}(w)
+	}
+	wg.Wait()
+	r.duration = time.Since(start)
+
+	// Beklenen toplam = tamamlanan işlem sayısı; eksik kalan kısım ezilen güncellemelerdir
+	cursor, err := col.Aggregate(ctx, bson.A{bson.M{"$group": bson.M{"_id": nil, "sum": bson.M{"$sum": "$value"}}}})
+	if HandleError(logger, "sum", err) {
+		return r, false
+	}
+	var sums []struct {
+		Sum int64 `bson:"sum"`
+	}
+	if err := cursor.All(ctx, &sums); HandleError(logger, "sum", err) || len(sums) == 0 {
+		return r, false
+	}
+
+	r.hist = benchkit.NewHistogram()
+	for _, h := range hists {
+		r.hist.Merge(h)
+	}
+	r.attempts, r.conflicts, r.gaveUp = attempts, conflicts, gaveUp
+	r.failures = int(gaveUp)
+	r.lost = done - sums[0].Sum
+	// Efektif işlem: Değeri gerçekten sayaca yansıyan işlem (lww'de ezilenler düşülür)
+	r.docs = done - r.lost
+	return r, true
+}
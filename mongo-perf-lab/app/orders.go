@@ -0,0 +1,156 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// orders.go - Rastgele order dokümanı üretimi
+// generator (toplu veri üretimi) ve growth (canlı veri akışı) aynı doküman şeklini kullanır.
+
+// workerRand - Run seed'inden worker'a özel, birbirinden bağımsız bir rand kaynağı türetir
+// seed+workerID gibi ardışık seed'ler yerine SplitMix64 karıştırması kullanılır,
+// böylece komşu worker'ların sayı dizileri birbiriyle ilişkili olmaz
+func workerRand(seed int64, workerID int) *rand.Rand {
+	z := uint64(seed) + uint64(workerID+1)*0x9E3779B97F4A7C15
+	z = (z ^ (z >> 30)) * 0xBF58476D1CE4E5B9
+	z = (z ^ (z >> 27)) * 0x94D049BB133111EB
+	z ^= z >> 31
+	return rand.New(rand.NewSource(int64(z)))
+}
+
+// randomObjectID - rng'den deterministik ObjectID üretir
+// primitive.NewObjectID() zaman + sayaç kullanır, bu yüzden aynı seed ile aynı veri üretilemez
+func randomObjectID(rng *rand.Rand) primitive.ObjectID {
+	var id primitive.ObjectID
+	rng.Read(id[:])
+	return id
+}
+
+// orderDateLayout - createdAt string olarak saklandığında kullanılan format
+// Sabit uzunluklu, UTC ve büyükten küçüğe (yıl→saniye) olduğu için string karşılaştırması
+// tarih sırasıyla aynıdır. Farklı format/zone karışırsa $gt/$lt sorguları sessizce yanlış sonuç verir.
+const orderDateLayout = "2006-01-02T15:04:05Z"
+
+// applyDateFormat - createdAt alanını istenen saklama formatına çevirir
+// Parametreler:
+//   - format: "date" (BSON Date / ISODate, varsayılan) veya "string"
+func applyDateFormat(doc bson.M, format string) {
+	if format != "string" {
+		return
+	}
+	if t, ok := doc["createdAt"].(time.Time); ok {
+		doc["createdAt"] = t.UTC().Format(orderDateLayout)
+	}
+}
+
+// orderStatuses - Üretilen siparişlerin olası status değerleri
+var orderStatuses = []string{"PAID", "CANCELLED", "PENDING"}
+
+// newOrder - Rastgele bir order dokümanı oluşturur
+// Parametreler:
+//   - rng: Worker'ın kendi rand kaynağı (goroutine'ler arasında paylaşılmaz)
+//   - now: Tarihlerin referans zamanı
+//   - shape: Doküman şekli; varied modda alanlar olasılıklara göre eklenir/çıkarılır
+func newOrder(rng *rand.Rand, now time.Time, shape ShapeProfile) bson.M {
+	if !shape.Varied() {
+		return bson.M{
+			"userId": randomObjectID(rng),                         // Rastgele user ID
+			"status": orderStatuses[rng.Intn(len(orderStatuses))], // Rastgele status
+			"total":  rng.Intn(5000),                              // Rastgele toplam tutar (0-5000 arası)
+			"items":  randomItems(rng, 1),                         // Tek ürün
+			// Rastgele bir tarih oluştur (son 1000 saat içinden)
+			"createdAt": now.Add(-time.Duration(rng.Intn(1000)) * time.Hour),
+		}
+	}
+
+	// userId ve status her zaman vardır (index'li alanlar, sorgular bunlara dayanır)
+	doc := bson.M{
+		"userId": randomObjectID(rng),
+		"status": orderStatuses[rng.Intn(len(orderStatuses))],
+	}
+
+	// Opsiyonel alanlar: Her biri MissingProb olasılıkla eksik
+	// (ör: eski şema versiyonundan kalan kayıtlar)
+	if rng.Float64() >= shape.MissingProb {
+		doc["total"] = rng.Intn(5000)
+	}
+	if rng.Float64() >= shape.MissingProb {
+		doc["items"] = randomItems(rng, rng.Intn(shape.MaxItems+1))
+	}
+	if rng.Float64() >= shape.MissingProb {
+		doc["createdAt"] = now.Add(-time.Duration(rng.Intn(1000)) * time.Hour)
+	}
+
+	// Ekstra alanlar: Her biri ExtraProb olasılıkla eklenir
+	// (ör: sonradan eklenen özellikler, sadece bazı kayıtlarda olan alanlar)
+	if rng.Float64() < shape.ExtraProb {
+		doc["couponCode"] = fmt.Sprintf("SAVE%d", rng.Intn(50))
+	}
+	if rng.Float64() < shape.ExtraProb {
+		doc["note"] = randomText(rng, 20+rng.Intn(480))
+	}
+	if rng.Float64() < shape.ExtraProb {
+		tags := make([]string, rng.Intn(6))
+		for i := range tags {
+			tags[i] = fmt.Sprintf("tag%d", rng.Intn(20))
+		}
+		doc["tags"] = tags
+	}
+	if rng.Float64() < shape.ExtraProb {
+		doc["shipping"] = bson.M{
+			"method":  []string{"standard", "express", "pickup"}[rng.Intn(3)],
+			"cost":    rng.Float64() * 50,
+			"address": bson.M{"city": fmt.Sprintf("city%d", rng.Intn(81)), "zip": fmt.Sprintf("%05d", rng.Intn(100000))},
+		}
+	}
+	if rng.Float64() < shape.ExtraProb {
+		doc["giftWrap"] = rng.Intn(2) == 0
+	}
+	return doc
+}
+
+// randomItems - n elemanlı rastgele items dizisi oluşturur
+func randomItems(rng *rand.Rand, n int) []bson.M {
+	items := make([]bson.M, n)
+	for i := range items {
+		items[i] = bson.M{
+			"productId": randomObjectID(rng), // Rastgele ürün ID
+			"price":     rng.Intn(1000),      // Rastgele fiyat (0-1000 arası)
+			"qty":       rng.Intn(5) + 1,     // Rastgele miktar (1-5 arası)
+		}
+	}
+	return items
+}
+
+// randomText - n karakterlik rastgele metin oluşturur (not gibi serbest metin alanları için)
+func randomText(rng *rand.Rand, n int) string {
+	const letters = "abcdefghijklmnopqrstuvwxyz     "
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = letters[rng.Intn(len(letters))]
+	}
+	return string(b)
+}
+
+// duplicateWindow - Tekrar eden siparişin kopyalanabileceği son sipariş sayısı (worker başına)
+// Gerçek tekrarlar (retry, çift tıklama) orijinalden kısa süre sonra gelir
+const duplicateWindow = 1000
+
+// duplicateOrder - Siparişin _id hariç kopyası; injectedDuplicate ile işaretlenir
+// Kopya yeni bir map'tir: bson.M alan sırası sabit olmadığından BSON'daki alan sırası orijinalden farklı olabilir
+// (tekrar tespitinde ham byte karşılaştırmasının tuzağı, bkz. dedup.go)
+func duplicateOrder(order bson.M) bson.M {
+	dup := make(bson.M, len(order)+1)
+	for k, v := range order {
+		if k != "_id" {
+			dup[k] = v
+		}
+	}
+	dup["injectedDuplicate"] = true
+	return dup
+}
@@ -0,0 +1,315 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"benchkit"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// outbox.go - Transactional outbox pattern: Yazma verimi ve uçtan uca teslim gecikmesi
+// Sipariş ve "OrderCreated" olayı aynı transaction'da yazılır (ya ikisi de vardır ya hiçbiri);
+// ayrı çalışan poller'lar bekleyen olayları okuyup "gönderildi" olarak işaretler:
+//
+//	writer:  startTransaction → insert outbox_orders → insert outbox → commit
+//	poller:  find {status: pending, partition: p} sort createdAt limit B → updateMany {_id: $in} → sent
+//
+// Her poller kendi partition'ını okur (olay yazılırken seq % pollers ile atanır): Poller'lar
+// aynı olayı iki kez almaz ve claim için findAndModify döngüsü gerekmez.
+//
+// Ölçülenler:
+//   - txn:      Transaction gecikmesi (commit dahil) ve sipariş/sn
+//   - delivery: Olayın transaction içinde oluşturulmasından poller'ın işaretlemesine kadar geçen süre
+//   - poll:     Poll sorgusunun gecikmesi, boş poll oranı ve query targeting (incelenen/dönen)
+//
+// Poll sorgusunun index'i {status, partition, createdAt}: ESR kuralına göre eşitlikler önce, sıralama
+// sonra - pending olaylar index'ten sırayla okunur, gönderilmiş olaylar hiç incelenmez.
+// -index=false ile aynı sorgu COLLSCAN + SORT yapar; outbox büyüdükçe her poll yavaşlar.
+//
+// Not: Transaction replica set (veya sharded cluster) gerektirir. Tek node'lu replica set için
+// mongod.conf'a replication.replSetName: rs0 ekleyip bir kez rs.initiate() çalıştırmak yeterli.
+// createdAt BSON Date olarak ms hassasiyetinde saklanır: Teslim gecikmesinde ±1ms hata payı vardır.
+//
+// KULLANIM:
+//   go run main.go config.go analyzer.go antipattern.go errors.go logger.go samples.go sink.go dataset.go orders.go targeting.go latency.go outbox.go
+//   go run main.go config.go analyzer.go antipattern.go errors.go logger.go samples.go sink.go dataset.go orders.go targeting.go latency.go outbox.go -writers 16 -pollers 4 -index=false
+
+// outboxEntry - Outbox'a transaction içinde yazılan olay
+type outboxEntry struct {
+	ID        primitive.ObjectID `bson:"_id"`
+	OrderID   primitive.ObjectID `bson:"orderId"`
+	Type      string             `bson:"type"`
+	Partition int                `bson:"partition"`
+	Status    string             `bson:"status"`
+	CreatedAt time.Time          `bson:"createdAt"`
+	SentAt    *time.Time         `bson:"sentAt,omitempty"`
+	Payload   bson.M             `bson:"payload"`
+}
+
+func main() {
+	orderCount := flag.Int("orders", 20000, "Yazılacak sipariş (ve olay) sayısı")
+	writers := flag.Int("writers", 8, "Eşzamanlı transaction yazan goroutine sayısı")
+	pollers := flag.Int("pollers", 2, "Poller sayısı (her biri bir partition okur)")
+	pollBatch := flag.Int("poll-batch", 100, "Poll başına en fazla okunan olay")
+	pollInterval := flag.Duration("poll-interval", 20*time.Millisecond, "Boş poll'dan sonra bekleme")
+	withIndex := flag.Bool("index", true, "Poll sorgusu için {status, partition, createdAt} index'i oluştur")
+	seed := flag.Int64("seed", 42, "Sipariş içerikleri için seed")
+	flag.Parse()
+
+	if *orderCount <= 0 || *writers <= 0 || *pollers <= 0 || *pollBatch <= 0 || *pollInterval < 0 {
+		fmt.Println("❌ -orders, -writers, -pollers ve -poll-batch pozitif olmalı")
+		return
+	}
+
+	logger, err := NewLogger("outbox_results.txt")
+	if err != nil {
+		fmt.Printf("Logger oluşturulamadı: %v\n", err)
+		return
+	}
+	defer CloseLogger(logger)
+
+	logger.WriteHeader("outbox - Transactional Outbox Verimi ve Teslim Gecikmesi")
+
+	db := GetMongo().Database()
+	client := db.Client()
+	ctx := context.Background()
+
+	var hello bson.M
+	if err := db.RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&hello); HandleError(logger, "hello", err) {
+		PrintErrorSummary(logger)
+		return
+	}
+	if _, rs := hello["setName"]; !rs && hello["msg"] != "isdbgrid" {
+		logger.Println("❌ Sunucu replica set değil: Transaction kullanılamaz (bkz. dosya başındaki not)")
+		return
+	}
+
+	orders := db.Collection("outbox_orders")
+	outbox := db.Collection("outbox")
+	for _, col := range []*mongo.Collection{orders, outbox} {
+		if HandleError(logger, "drop "+col.Name(), col.Drop(ctx)) {
+			PrintErrorSummary(logger)
+			return
+		}
+	}
+	// Transaction içinde koleksiyon oluşturmak (implicit create) 4.4 öncesinde desteklenmez
+	// ve ilk transaction'ları yavaşlatır: Koleksiyonlar önceden oluşturulur
+	for _, col := range []*mongo.Collection{orders, outbox} {
+		if HandleError(logger, "create "+col.Name(), db.CreateCollection(ctx, col.Name())) {
+			PrintErrorSummary(logger)
+			return
+		}
+	}
+	if *withIndex {
+		_, err := outbox.Indexes().CreateOne(ctx, mongo.IndexModel{
+			Keys:    bson.D{{Key: "status", Value: 1}, {Key: "partition", Value: 1}, {Key: "createdAt", Value: 1}},
+			Options: options.Index().SetName("status_partition_createdAt"),
+		})
+		if HandleError(logger, "index", err) {
+			PrintErrorSummary(logger)
+			return
+		}
+	}
+	logger.Printf("📋 %d sipariş, %d writer, %d poller (batch %d, boşta %v bekleme), poll index'i: %v\n",
+		*orderCount, *writers, *pollers, *pollBatch, *pollInterval, *withIndex)
+
+	targeting := StartTargetingSampler(db, time.Second)
+	start := time.Now()
+
+	// Writer'lar: Her biri kendi session'ını ve histogramını tutar
+	var next int64 = -1
+	var attempts, committed int64
+	txnHists := make([]*benchkit.Histogram, *writers)
+	samples := logger.LatencySamples()
+	var writersWG sync.WaitGroup
+	var writersDone atomic.Bool
+	for w := 0; w < *writers; w++ {
+		txnHists[w] = benchkit.NewHistogram()
+		writersWG.Add(1)
+		go func(w int) {
+			defer writersWG.Done()
+			session, err := client.StartSession()
+			if HandleError(logger, "session", err) {
+				return
+			}
+			defer session.EndSession(ctx)
+			rng := workerRand(*seed, w)
+			now := time.Now()
+			for {
+				seq := atomic.AddInt64(&next, 1)
+				if seq >= int64(*orderCount) {
+					return
+				}
+				order := newOrder(rng, now, ShapeProfile{})
+				orderID := randomObjectID(rng)
+				order["_id"] = orderID
+
+				txnStart := time.Now()
+				_, err := session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+					atomic.AddInt64(&attempts, 1)
+					if _, err := orders.InsertOne(sc, order); err != nil {
+						return nil, err
+					}
+					// createdAt her denemede yenilenir: Teslim gecikmesi commit edilen denemeden başlar
+					_, err := outbox.InsertOne(sc, outboxEntry{
+						ID:        primitive.NewObjectID(),
+						OrderID:   orderID,
+						Type:      "OrderCreated",
+						Partition: int(seq % int64(*pollers)),
+						Status:    "pending",
+						CreatedAt: time.Now(),
+						Payload:   bson.M{"userId": order["userId"], "total": order["total"], "status": order["status"]},
+					})
+					return nil, err
+				})
+				elapsed := time.Since(txnStart)
+				txnHists[w].Record(elapsed)
+				samples.Record("txn", w, txnStart, elapsed, err == nil)
+				if HandleError(logger, "transaction", err) {
+					continue
+				}
+				atomic.AddInt64(&committed, 1)
+			}
+		}(w)
+	}
+
+	// Poller'lar: Writer'lar bittikten sonra partition'ı boşalana kadar devam eder
+	type pollerStats struct {
+		polls, empty, delivered int
+		pollHist, deliveryHist  *benchkit.Histogram
+	}
+	stats := make([]*pollerStats, *pollers)
+	var pollersWG sync.WaitGroup
+	findOpts := options.Find().
+		SetSort(bson.D{{Key: "createdAt", Value: 1}}).
+		SetLimit(int64(*pollBatch)).
+		SetProjection(bson.M{"createdAt": 1})
+	for p := 0; p < *pollers; p++ {
+		s := &pollerStats{pollHist: benchkit.NewHistogram(), deliveryHist: benchkit.NewHistogram()}
+		stats[p] = s
+		pollersWG.Add(1)
+		go func(p int) {
+			defer pollersWG.Done()
+			filter := bson.M{"status": "pending", "partition": p}
+			for {
+				// Bayrak poll'dan önce okunur: Writer'lar bittikten sonraki boş poll partition'ın boş olduğunu kanıtlar
+				done := writersDone.Load()
+				pollStart := time.Now()
+				cursor, err := outbox.Find(ctx, filter, findOpts)
+				if HandleError(logger, "poll", err) {
+					return
+				}
+				var pending []struct {
+					ID        primitive.ObjectID `bson:"_id"`
+					CreatedAt time.Time          `bson:"createdAt"`
+				}
+				err = cursor.All(ctx, &pending)
+				elapsed := time.Since(pollStart)
+				s.pollHist.Record(elapsed)
+				samples.Record("poll", p, pollStart, elapsed, err == nil)
+				s.polls++
+				if HandleError(logger, "poll", err) {
+					return
+				}
+				if len(pending) == 0 {
+					s.empty++
+					if done {
+						return
+					}
+					time.Sleep(*pollInterval)
+					continue
+				}
+
+				// Gerçek sistemde burada olaylar broker'a gönderilir; gönderim sonrası işaretlenir
+				ids := make([]primitive.ObjectID, len(pending))
+				for i, e := range pending {
+					ids[i] = e.ID
+				}
+				sentAt := time.Now()
+				_, err = outbox.UpdateMany(ctx, bson.M{"_id": bson.M{"$in": ids}},
+					bson.M{"$set": bson.M{"status": "sent", "sentAt": sentAt}})
+				if HandleError(logger, "mark sent", err) {
+					return
+				}
+				for _, e := range pending {
+					s.deliveryHist.Record(sentAt.Sub(e.CreatedAt))
+				}
+				s.delivered += len(pending)
+			}
+		}(p)
+	}
+
+	writersWG.Wait()
+	writeDuration := time.Since(start)
+	writersDone.Store(true)
+	pollersWG.Wait()
+	duration := time.Since(start)
+	total := TotalTargeting(targeting.Stop())
+
+	txnHist := benchkit.NewHistogram()
+	for _, h := range txnHists {
+		txnHist.Merge(h)
+	}
+	pollHist, deliveryHist := benchkit.NewHistogram(), benchkit.NewHistogram()
+	var polls, empty, delivered int
+	for _, s := range stats {
+		pollHist.Merge(s.pollHist)
+		deliveryHist.Merge(s.deliveryHist)
+		polls += s.polls
+		empty += s.empty
+		delivered += s.delivered
+	}
+
+	txn := latencyCase{name: "txn", ops: *orderCount, docs: committed, failures: *orderCount - int(committed), duration: writeDuration, hist: txnHist}
+	delivery := latencyCase{name: "delivery", ops: delivered, docs: int64(delivered), duration: duration, hist: deliveryHist}
+	poll := latencyCase{name: "poll", ops: polls, docs: int64(delivered), duration: duration, hist: pollHist}
+
+	logger.Println("\n=== SONUÇLAR ===")
+	printLatencyTable([]latencyCase{txn, poll, delivery}, "", logger)
+	logger.Printf("\n✍️  Transaction: %d commit, %.0f sipariş/sn, %d deneme (%d yeniden deneme)\n",
+		committed, float64(committed)/writeDuration.Seconds(), attempts, attempts-committed)
+	logger.Printf("📬 Teslim: %d/%d olay, toplam %v, p50 %v, p99 %v\n", delivered, committed,
+		duration.Round(time.Millisecond), deliveryHist.Summary().P50.Round(time.Millisecond), deliveryHist.Summary().P99.Round(time.Millisecond))
+	logger.Printf("🔁 Poll: %d poll, %d boş (%%%.1f), poll başına %.1f olay\n",
+		polls, empty, float64(empty)/float64(max(polls, 1))*100, float64(delivered)/float64(max(polls-empty, 1)))
+	logger.Printf("🎯 Query targeting (çalıştırma boyunca, sunucu geneli): %d doküman + %d key incelendi, %d döndü → oran %.1f\n",
+		total.DocsExamined, total.KeysExamined, total.Returned, total.Ratio())
+	if int64(delivered) != committed {
+		logger.Printf("⚠️  Commit edilen %d olaydan %d tanesi teslim edildi\n", committed, delivered)
+	}
+
+	// Poll sorgusunun planı: Çalıştırma sonunda tüm olaylar gönderilmiş durumda - index'le
+	// hiçbir şey incelenmemeli, index'siz her poll tüm outbox'ı tarar
+	explain, err := ExplainQuery(outbox, bson.M{"status": "pending", "partition": 0}, findOpts)
+	if !HandleError(logger, "explain", err) {
+		plan := SummarizeExplain(explain)
+		logger.Printf("\n🔍 Poll planı: %s (index: %s), %d doküman + %d key incelendi, %d döndü\n",
+			plan.StageChain(), plan.IndexName, plan.DocsExamined, plan.KeysExamined, plan.NReturned)
+		if plan.IndexName == "" {
+			logger.Println("💡 Poll COLLSCAN yapıyor: Outbox'ta biriken gönderilmiş olaylar her poll'da yeniden taranır.")
+			logger.Println("   {status, partition, createdAt} index'i (-index) veya gönderilenleri silen/TTL'li bir outbox kullanın.")
+		}
+	}
+
+	txnRecord := latencyRecord("outbox", txn)
+	txnRecord.DocsPerSec = float64(committed) / writeDuration.Seconds()
+	logger.WriteRecord(txnRecord)
+	writeLatencyRecord("outbox", delivery, logger)
+	pollRecord := latencyRecord("outbox", poll)
+	pollRecord.DocsExamined = total.DocsExamined
+	pollRecord.KeysExamined = total.KeysExamined
+	pollRecord.Targeting = total.Ratio()
+	logger.WriteRecord(pollRecord)
+
+	PrintErrorSummary(logger)
+
+	logger.Println("\n✅ Test tamamlandı! Sonuçlar 'outbox_results.txt' dosyasına kaydedildi.")
+}
@@ -0,0 +1,1308 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/ed25519"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"benchkit"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// perflab.go - Deney çalıştırıcı komut satırı aracı
+// Manifest dosyasında tanımlanan deneyi baştan sona çalıştırır:
+// 1. Veri setini oluşturur (generator)
+// 2. Index'leri oluşturur
+// 3. Manifest'te ingest tanımlıysa arka planda yazma yükünü (growth) başlatır
+// 4. Benchmark script'lerini tekrar sayısı kadar çalıştırır
+// 5. Assertion'ları kontrol eder ve sonuçları runs/ klasörüne kaydeder
+//
+// KULLANIM:
+//   go run main.go config.go analyzer.go antipattern.go errors.go logger.go samples.go sink.go dataset.go manifest.go ingest.go coord.go provenance.go summary.go writeup.go browse.go runlog.go artifacts.go topology.go perflab.go run -f experiments/paid_orders.yaml
+//
+// Küme seviyesinde verim için aynı komut N makinede -coord ile çalıştırılır (bkz. coord.go):
+//   ... perflab.go run -f experiments/paid_orders.yaml -coord paid-4x -clients 4
+//
+// Sonuçlar imzalanabilir ve sonradan doğrulanabilir (bkz. provenance.go):
+//   ... perflab.go keygen -o perflab
+//   ... perflab.go run -f experiments/paid_orders.yaml -sign perflab.key
+//   ... perflab.go verify runs/paid_orders_20250101_120000 -pub perflab.pub
+//
+// Sonuçlardan Markdown deney raporu (bkz. writeup.go):
+//   ... perflab.go writeup runs/paid_orders_20250101_120000
+//
+// Tüm read versiyonları (read_bad → read_v5) aynı veri setinde, tek karşılaştırma tablosuyla:
+//   ... perflab.go run-all
+//
+// Geçmiş çalıştırmalar arasında gezinme, fark ve histogramlar (bkz. browse.go):
+//   ... perflab.go browse
+//
+// Tüm çalıştırmaların log satırlarında seviye ve desenle arama (bkz. runlog.go):
+//   ... perflab.go logs -level warn -grep COLLSCAN
+//
+// Aynı deneyin tek düğüm, replica set ve sharded kümede karşılaştırması (bkz. topology.go):
+//   ... perflab.go topology -up -f experiments/read_versions.yaml
+//
+// Büyük sonuç dosyaları zstd ile sıkıştırılır, eski çalıştırmalar silinebilir (bkz. artifacts.go):
+//   ... perflab.go gc --keep 30d
+//
+// Benchmark'lar ayrı process olarak çalıştırılır (go run <yardımcı dosyalar> <script>.go),
+// çünkü her read versiyonu kendi main() fonksiyonuna sahip bağımsız bir script'tir.
+func main() {
+	if len(os.Args) < 2 {
+		printPerflabUsage()
+		os.Exit(2)
+	}
+
+	switch os.Args[1] {
+	case "run":
+		os.Exit(cmdRun(os.Args[2:]))
+	case "run-all":
+		os.Exit(cmdRunAll(os.Args[2:]))
+	case "verify":
+		os.Exit(cmdVerify(os.Args[2:]))
+	case "keygen":
+		os.Exit(cmdKeygen(os.Args[2:]))
+	case "writeup":
+		os.Exit(cmdWriteup(os.Args[2:]))
+	case "browse":
+		os.Exit(cmdBrowse(os.Args[2:]))
+	case "logs":
+		os.Exit(cmdLogs(os.Args[2:]))
+	case "topology":
+		os.Exit(cmdTopology(os.Args[2:]))
+	case "gc":
+		os.Exit(cmdGC(os.Args[2:]))
+	default:
+		printPerflabUsage()
+		os.Exit(2)
+	}
+}
+
+func printPerflabUsage() {
+	fmt.Println("Kullanım: perflab <komut> [parametreler]")
+	fmt.Println()
+	fmt.Println("Komutlar:")
+	fmt.Println("  run -f experiment.yaml [--env staging]   Manifest'te tanımlanan deneyi (seçilen ortamda) çalıştırır")
+	fmt.Println("      [-mongo-uri ... -mongo-tls ...]       Ortamın bağlantı ayarlarını ezer (bkz. run -h)")
+	fmt.Println("      [-coord id -clients N [-client ad]]   N makinede eşzamanlı çalıştırır ve sonuçları birleştirir")
+	fmt.Println("      [-sign perflab.key]                   Sonuç dosyalarının hash listesini (artifacts.json) imzalar")
+	fmt.Println("      [-csv results.csv]                    Her çalıştırmanın metriklerini CSV'ye satır olarak ekler")
+	fmt.Println("      [-latency-samples csv|bin]            İşlem başına her gecikmeyi çalıştırma klasörüne yazar (dış analiz için)")
+	fmt.Println("      [-compress=false]                     Büyük sonuç dosyalarını zstd ile sıkıştırma")
+	fmt.Println("  run-all [run parametreleri]              read_bad → read_v5'i aynı veri setinde çalıştırıp karşılaştırır")
+	fmt.Println("  verify runs/<deney> [-pub perflab.pub]   Sonuç dosyalarının değiştirilmediğini doğrular")
+	fmt.Println("  keygen [-o perflab]                      İmza için Ed25519 anahtar çifti üretir")
+	fmt.Println("  writeup runs/<deney> [-baseline read_v1] Sonuçlardan Markdown deney raporu üretir (WRITEUP.md)")
+	fmt.Println("  browse [-runs runs] [-o .]               Geçmiş çalıştırmaları gezer, iki çalıştırmayı karşılaştırır, HTML'e aktarır")
+	fmt.Println("  logs [-run id] [-level warn] [-grep re]  Çalıştırmaların log satırlarında arar (-script, -runs, -i)")
+	fmt.Println("  topology -f experiment.yaml [-up]        Deneyi tek düğüm, replica set ve sharded kümede çalıştırıp karşılaştırır")
+	fmt.Println("  gc --keep 30d [-runs runs] [-dry-run]    Saklama süresinden eski çalıştırma klasörlerini siler")
+}
+
+// cmdRun - `perflab run -f experiment.yaml` komutu
+// Döndürür: process çıkış kodu (0 = tüm assertion'lar geçti)
+func cmdRun(args []string) int {
+	fs := flag.NewFlagSet("run", flag.ExitOnError)
+	manifestPath := fs.String("f", "", "Deney manifest dosyası (YAML)")
+	envName := fs.String("env", "", "perflab.yaml'daki ortam adı (varsayılan: config'in default'u)")
+	coordID := fs.String("coord", "", "Koordinasyon kimliği: Aynı kimlikle çalışan perflab'lar bariyerlerle birlikte ilerler (bkz. coord.go)")
+	clients := fs.Int("clients", 0, "-coord ile: Beklenen perflab sayısı")
+	clientName := fs.String("client", "", "-coord ile: Bu perflab'ın adı (varsayılan: hostname)")
+	coordTimeout := fs.Duration("coord-timeout", 30*time.Minute, "-coord ile: Bir bariyerde en fazla bekleme (veri seti üretimi dahil)")
+	signKey := fs.String("sign", "", "artifacts.json'ı bu Ed25519 özel anahtarıyla imzala (PEM, bkz. keygen)")
+	datasetChecksum := fs.Bool("dataset-checksum", true, "Ölçüm öncesi veri setinin dbHash checksum'ını provenance'a ekle")
+	csvPath := fs.String("csv", "", "Her benchmark çalıştırmasının metriklerini bu CSV dosyasına da ekle (çalıştırmalar arasında birikir)")
+	latencySamples := fs.String("latency-samples", "", "Script'lerin işlem başına her gecikmeyi yazdığı format: csv veya bin (bkz. samples.go)")
+	compress := fs.Bool("compress", true, "64 KB'tan büyük metin sonuçlarını (explain JSON'ları, events.jsonl) zstd ile sıkıştır (bkz. artifacts.go)")
+	RegisterConnectionFlags(fs)
+	fs.Parse(args)
+
+	if *manifestPath == "" {
+		fmt.Println("❌ -f parametresi zorunlu")
+		return 2
+	}
+	if *latencySamples != "" && *latencySamples != "csv" && *latencySamples != "bin" {
+		fmt.Printf("❌ -latency-samples: bilinmeyen format %q (csv, bin)\n", *latencySamples)
+		return 2
+	}
+	if *coordID != "" && *clients < 1 {
+		fmt.Println("❌ -coord ile -clients (beklenen perflab sayısı) zorunlu")
+		return 2
+	}
+	if *clientName == "" {
+		*clientName, _ = os.Hostname()
+	}
+
+	manifest, err := LoadManifest(*manifestPath)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return 2
+	}
+	// Anahtar ve provenance deney başlamadan okunur: Saatler sürecek ölçümün sonunda
+	// imzalanamadığı fark edilmesin
+	var signingKey ed25519.PrivateKey
+	if *signKey != "" {
+		if signingKey, err = LoadSigningKey(*signKey); err != nil {
+			fmt.Printf("❌ -sign: %v\n", err)
+			return 2
+		}
+	}
+	provenance, err := CollectProvenance(*manifestPath)
+	if err != nil {
+		fmt.Printf("❌ Provenance: %v\n", err)
+		return 2
+	}
+
+	// Ortam: Script'ler aynı ortamı PERFLAB_ENV'den, -mongo-* ezmelerini PERFLAB_MONGO_*'dan okur (os.Environ ile aktarılır)
+	if *envName != "" {
+		os.Setenv("PERFLAB_ENV", *envName)
+	}
+	ExportConnectionFlags()
+	// Script'ler CSV satırlarını PERFLAB_CSV'ye ekler; yol mutlak: Çalışma klasörü değişse de aynı dosya
+	if *csvPath != "" {
+		abs, err := filepath.Abs(*csvPath)
+		if err != nil {
+			fmt.Printf("❌ -csv: %v\n", err)
+			return 2
+		}
+		os.Setenv("PERFLAB_CSV", abs)
+	}
+	env, err := SelectEnvironment()
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return 2
+	}
+	// Kimlik bilgileri veri seti üretilmeden önce okunur: Eksik secret erken fark edilir ve
+	// okunan değerler bu process'in maskeleme listesine de girer (özet, kopyalanan çıktılar)
+	if _, err := env.ClientOptions(); err != nil {
+		fmt.Printf("❌ %s ortamı: %v\n", env.Name, err)
+		return 2
+	}
+	profile, err := LoadEnvironmentProfile(env)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return 2
+	}
+	profile.ApplyTo(manifest)
+	if err := manifest.Validate(); err != nil {
+		fmt.Printf("❌ %s ortamı uygulandıktan sonra: %v\n", env.Name, err)
+		return 2
+	}
+
+	summary := RunSummary{Manifest: manifest, Environment: env.Name, Host: benchkit.CollectHostInfo(), StartedAt: time.Now(), Provenance: provenance}
+
+	// Her çalıştırma kendi klasörüne yazılır, manifest de yanına kopyalanır
+	// Böylece sonuçlar hangi tanımla üretildiğiyle birlikte saklanır
+	runDir := filepath.Join("runs", fmt.Sprintf("%s_%s",
+		sanitizeName(manifest.Name), summary.StartedAt.Format("20060102_150405")))
+	if err := os.MkdirAll(runDir, 0755); err != nil {
+		fmt.Printf("❌ Sonuç klasörü oluşturulamadı: %v\n", err)
+		return 1
+	}
+	if data, err := os.ReadFile(*manifestPath); err == nil {
+		os.WriteFile(filepath.Join(runDir, "manifest.yaml"), []byte(Redact(string(data))), 0644)
+	}
+	metricsFile, _ := filepath.Abs(filepath.Join(runDir, "metrics.jsonl"))
+	eventsPath, _ := filepath.Abs(filepath.Join(runDir, eventsFile))
+
+	fmt.Printf("🧪 Deney: %s\n", manifest.Name)
+	if manifest.Description != "" {
+		fmt.Printf("   %s\n", manifest.Description)
+	}
+	fmt.Printf("🌍 Ortam: %s (%s, %s.%s)\n", env.Name, Redact(env.URI), env.Database, env.Collection)
+	fmt.Printf("📁 Sonuç klasörü: %s\n", runDir)
+
+	// Tek client: Topoloji, koordinasyon, shard'lama, index'ler ve checksum aynı havuzu kullanır
+	// (her GetMongo yeni havuz, izleyiciler ve serverStatus turu demek); Disconnect en son çalışır
+	col := GetMongo()
+	client := col.Database().Client()
+	defer client.Disconnect(context.Background())
+	if topology, err := DetectTopology(client); err != nil {
+		fmt.Printf("⚠️  Topoloji tespit edilemedi: %v\n", err)
+	} else {
+		summary.Topology = topology
+		fmt.Printf("🕸️  Topoloji: %s\n", topology)
+	}
+
+	// Koordinasyon: Tüm client'lar gelene kadar beklenir; hazırlığı (veri seti, index'ler,
+	// yazma yükü) yalnızca lider yapar. Başarısız çıkışta diğer client'lar iptal kaydıyla bırakılır
+	var coord *Coordinator
+	finished := false
+	if *coordID != "" {
+		coord = NewCoordinator(col.Database(), *coordID, *clientName, *clients, *coordTimeout)
+		fmt.Printf("\n🤝 Koordinasyon %s: %s olarak katılınıyor, %d client bekleniyor...\n", coord.ID, coord.Client, coord.Clients)
+		if err := coord.Join(); err != nil {
+			fmt.Printf("❌ Koordinasyon: %v\n", err)
+			return 1
+		}
+		defer func() {
+			if !finished {
+				coord.Abort(coord.Client + " başarısız oldu (çıktısına bakın)")
+			}
+		}()
+		summary.Coordination = coord.Info()
+		role := "client"
+		if coord.Leader {
+			role = "lider (veri seti, index'ler ve yazma yükü bu client'ta)"
+		}
+		fmt.Printf("   ✅ %d client hazır, rol: %s\n", coord.Clients, role)
+	}
+	prepare := coord == nil || coord.Leader
+
+	// Sharded ortam: Koleksiyon veri seti üretilmeden önce shard'lanır. Silme burada yapılır,
+	// generator'a -drop verilmez (koleksiyonla birlikte shard ayarı da silinirdi)
+	sharded := prepare && profile.ShardKey != ""
+	if sharded {
+		fmt.Printf("\n🧩 Koleksiyon shard'lanıyor: %s\n", profile.ShardKey)
+		if manifest.Dataset.Documents > 0 && manifest.Dataset.Drop {
+			if err := col.Drop(context.Background()); err != nil {
+				fmt.Printf("❌ Collection silinemedi: %v\n", err)
+				return 1
+			}
+		}
+		if err := ShardCollection(col, profile.ShardKey); err != nil {
+			fmt.Printf("❌ %v\n", err)
+			return 1
+		}
+	}
+
+	// 1. Veri seti
+	if prepare && manifest.Dataset.Documents > 0 {
+		fmt.Printf("\n📦 Veri seti oluşturuluyor: %d kayıt\n", manifest.Dataset.Documents)
+		genArgs := []string{
+			"-n", strconv.Itoa(manifest.Dataset.Documents),
+			"-batch", strconv.Itoa(manifest.Dataset.BatchSize),
+		}
+		if manifest.Dataset.Drop && !sharded {
+			genArgs = append(genArgs, "-drop")
+		}
+		if manifest.Dataset.Seed != 0 {
+			genArgs = append(genArgs, "-seed", strconv.FormatInt(manifest.Dataset.Seed, 10))
+		}
+		if manifest.Dataset.Workers > 0 {
+			genArgs = append(genArgs, "-workers", strconv.Itoa(manifest.Dataset.Workers))
+		}
+		if manifest.Dataset.DateFormat != "" {
+			genArgs = append(genArgs, "-date-format", manifest.Dataset.DateFormat)
+		}
+		if manifest.Dataset.DuplicatePct > 0 {
+			genArgs = append(genArgs, "-dup-pct", strconv.FormatFloat(manifest.Dataset.DuplicatePct, 'f', -1, 64))
+		}
+		if shape := manifest.Dataset.Shape; shape.Varied() {
+			genArgs = append(genArgs, "-shape", "varied")
+			if shape.MissingProb > 0 {
+				genArgs = append(genArgs, "-missing-prob", strconv.FormatFloat(shape.MissingProb, 'f', -1, 64))
+			}
+			if shape.ExtraProb > 0 {
+				genArgs = append(genArgs, "-extra-prob", strconv.FormatFloat(shape.ExtraProb, 'f', -1, 64))
+			}
+			if shape.MaxItems > 0 {
+				genArgs = append(genArgs, "-max-items", strconv.Itoa(shape.MaxItems))
+			}
+		}
+		if err := runScript("generator", genArgs, nil); err != nil {
+			fmt.Printf("❌ Veri seti oluşturulamadı: %v\n", err)
+			return 1
+		}
+	}
+
+	// 2. Index'ler
+	if prepare && len(manifest.Indexes) > 0 {
+		fmt.Println("\n🔧 Index'ler oluşturuluyor...")
+		if err := createManifestIndexes(col, manifest.Indexes); err != nil {
+			fmt.Printf("❌ Index oluşturulamadı: %v\n", err)
+			return 1
+		}
+	}
+
+	// Veri seti checksum'ı yazma yükü başlamadan alınır (sonrasında veri sürekli değişir).
+	// Koordinasyonda veriyi lider hazırladığı için checksum da lider'in özetindedir
+	if prepare && *datasetChecksum {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+		checksum, count, err := DatasetChecksum(ctx, col)
+		cancel()
+		if err != nil {
+			fmt.Printf("⚠️  Veri seti checksum'ı alınamadı: %v\n", err)
+		} else {
+			provenance.DatasetChecksum, provenance.DatasetCount = checksum, count
+			fmt.Printf("\n🧾 Veri seti: %d doküman, %s\n", count, checksum)
+		}
+	}
+
+	// 3. Arka plan yazma yükü (opsiyonel): Benchmark'lar canlı veri akışı altında ölçülür
+	ingestFile, _ := filepath.Abs(filepath.Join(runDir, "ingest.jsonl"))
+	var ingest *exec.Cmd
+	if prepare && manifest.Ingest != nil {
+		fmt.Printf("\n✍️  Arka plan yazma yükü başlatılıyor: %.0f doküman/sn\n", manifest.Ingest.Rate)
+		ingest, err = startIngest(manifest.Ingest, runDir, ingestFile)
+		if err != nil {
+			fmt.Printf("❌ Yazma yükü başlatılamadı: %v\n", err)
+			return 1
+		}
+		defer stopIngest(ingest)
+	}
+	if coord != nil {
+		if err := coord.Wait("prepared"); err != nil {
+			fmt.Printf("❌ Koordinasyon: %v\n", err)
+			return 1
+		}
+	}
+
+	// 4. Benchmark'lar
+	// Her tekrarın zaman aralığı saklanır, yazma hızı bu aralıklara göre eşleştirilir
+	windows := map[string][2]time.Time{}
+	for _, bench := range manifest.Benchmarks {
+		for rep := 1; rep <= bench.Repetitions; rep++ {
+			fmt.Printf("\n▶️  %s (tekrar %d/%d)\n", bench.Name, rep, bench.Repetitions)
+			env := []string{
+				"PERFLAB_METRICS_FILE=" + metricsFile,
+				"PERFLAB_EVENTS_FILE=" + eventsPath,
+				"PERFLAB_REPETITION=" + strconv.Itoa(rep),
+			}
+			if len(manifest.Sinks) > 0 {
+				env = append(env, "PERFLAB_SINKS="+strings.Join(manifest.Sinks, ","))
+			}
+			if bench.Prewarm > 0 {
+				env = append(env, "PERFLAB_PREWARM="+strconv.Itoa(bench.Prewarm))
+			}
+			if *latencySamples != "" {
+				env = append(env, "PERFLAB_LATENCY_SAMPLES="+*latencySamples)
+			}
+			// Tekrar tüm client'larda aynı anda başlar (go run derlemesi bariyerden sonradır,
+			// makineler arasındaki derleme süresi farkı ölçümün başına karışabilir)
+			if coord != nil {
+				if err := coord.Wait(fmt.Sprintf("%s#%d", bench.Name, rep)); err != nil {
+					fmt.Printf("❌ Koordinasyon: %v\n", err)
+					return 1
+				}
+			}
+			repStart := time.Now()
+			if err := runScript(bench.Name, bench.Args, env); err != nil {
+				fmt.Printf("❌ %s başarısız: %v\n", bench.Name, err)
+				// Script'in kendi satırları olay günlüğünde; çıkış nedeni perflab'dan eklenir
+				benchkit.AppendJSONL(eventsPath, LogEvent{Time: time.Now(), Level: "error", Script: bench.Name,
+					Repetition: rep, Line: fmt.Sprintf("❌ %s başarısız: %v", bench.Name, err)})
+				return 1
+			}
+			windows[fmt.Sprintf("%s#%d", bench.Name, rep)] = [2]time.Time{repStart, time.Now()}
+
+			// Script'in text çıktısını tekrar numarasıyla sakla
+			if hasOutput(manifest, "text") {
+				resultFile := bench.Name + "_results.txt"
+				if data, err := os.ReadFile(resultFile); err == nil {
+					os.WriteFile(filepath.Join(runDir, fmt.Sprintf("%s_rep%d.txt", bench.Name, rep)), []byte(Redact(string(data))), 0644)
+				}
+			}
+			// Script'in JSON özeti (Logger text dosyasının yanına yazar, bkz. RunResults)
+			if hasOutput(manifest, "json") {
+				if data, err := os.ReadFile(bench.Name + "_results.json"); err == nil {
+					os.WriteFile(filepath.Join(runDir, fmt.Sprintf("%s_rep%d.json", bench.Name, rep)), data, 0644)
+				}
+			}
+			// -pprof ile alınan profiller (bkz. iterations.go); önceki elle çalıştırmalardan kalanlar taşınmaz
+			for _, kind := range []string{"cpu", "heap"} {
+				path := fmt.Sprintf("%s_%s.pprof", bench.Name, kind)
+				if info, err := os.Stat(path); err == nil && info.ModTime().After(repStart) {
+					os.Rename(path, filepath.Join(runDir, fmt.Sprintf("%s_rep%d_%s.pprof", bench.Name, rep, kind)))
+				}
+			}
+			// -latency-samples ile yazılan ham gecikme örnekleri (bkz. samples.go)
+			if *latencySamples != "" {
+				path := fmt.Sprintf("%s_latency.%s", bench.Name, *latencySamples)
+				if info, err := os.Stat(path); err == nil && info.ModTime().After(repStart) {
+					os.Rename(path, filepath.Join(runDir, fmt.Sprintf("%s_rep%d_latency.%s", bench.Name, rep, *latencySamples)))
+				}
+			}
+		}
+	}
+
+	// Lider yazma yükünü diğer client'lar da bitirene kadar sürdürür
+	if coord != nil {
+		if err := coord.Wait("done"); err != nil {
+			fmt.Printf("❌ Koordinasyon: %v\n", err)
+			return 1
+		}
+	}
+
+	// 5. Assertion'lar
+	summary.Records, err = readMetricsRecords(metricsFile)
+	if err != nil {
+		fmt.Printf("⚠️  Metrik kayıtları okunamadı: %v\n", err)
+	}
+	if ingest != nil {
+		stopIngest(ingest)
+		samples, err := ReadIngestSamples(ingestFile)
+		if err != nil {
+			fmt.Printf("⚠️  Yazma örnekleri okunamadı: %v\n", err)
+		}
+		// Her kaydın eşzamanlı yazma hızı, kendi tekrarının zaman aralığından hesaplanır
+		// (go run derleme süresi de aralığa dahildir, yazma yükü o sırada da çalışır)
+		for i, r := range summary.Records {
+			if w, ok := windows[fmt.Sprintf("%s#%d", r.Benchmark, r.Repetition)]; ok {
+				summary.Records[i].IngestRate = IngestRateBetween(samples, w[0], w[1])
+			}
+		}
+	}
+	summary.Assertions = evaluateAssertions(manifest.Assertions, summary.Records)
+	if manifest.Cost != nil {
+		model, _ := manifest.Cost.Model() // Validate'te kontrol edildi
+		summary.Costs = estimateCosts(model, summary.Records)
+	}
+	summary.FinishedAt = time.Now()
+
+	failed := printRunSummary(summary)
+
+	if hasOutput(manifest, "json") {
+		data, _ := json.MarshalIndent(summary, "", "  ")
+		os.WriteFile(filepath.Join(runDir, "summary.json"), []byte(Redact(string(data))), 0644)
+	}
+
+	if coord != nil {
+		finished = true
+		if code := finishCoordination(coord, summary, runDir); code != 0 {
+			return code
+		}
+	}
+
+	// Sıkıştırma hash listesinden önce: Liste diskte kalan (.zst) dosyaları kapsar
+	if *compress {
+		files, before, after, err := CompressArtifacts(runDir)
+		if err != nil {
+			fmt.Printf("⚠️  Sonuçlar sıkıştırılamadı: %v\n", err)
+		} else if files > 0 {
+			fmt.Printf("\n🗜️  %d dosya sıkıştırıldı: %.1f MB → %.1f MB\n", files, float64(before)/(1024*1024), float64(after)/(1024*1024))
+		}
+	}
+
+	// Hash listesi en son yazılır: Klasördeki tüm dosyaları (summary.json, cluster.json dahil) kapsar
+	artifacts, err := WriteArtifactManifest(runDir, manifest.Name, provenance, signingKey)
+	if err != nil {
+		fmt.Printf("⚠️  %s yazılamadı: %v\n", artifactsFile, err)
+	} else {
+		signed := "imzasız"
+		if artifacts.Signature != nil {
+			signed = "imzalı"
+		}
+		fmt.Printf("\n🔏 %s: %d dosya, %s\n", artifactsFile, len(artifacts.Files), signed)
+	}
+
+	fmt.Printf("\n📁 Sonuçlar: %s\n", runDir)
+	if failed > 0 {
+		return 1
+	}
+	return 0
+}
+
+// readVersionsManifest - run-all'un çalıştırdığı deney (read_bad → read_v5)
+const readVersionsManifest = "experiments/read_versions.yaml"
+
+// cmdRunAll - `perflab run-all` komutu
+// Tüm read versiyonlarını tanımlayan manifest'le `run`u çalıştırır: Veri seti ve index'ler bir kez
+// hazırlanır, versiyonlar arka arkaya çalışır ve özet karşılaştırma tablosuyla biter.
+// Parametreler olduğu gibi run'a aktarılır (--env, -mongo-*, -sign ...); -f verilirse manifest değişir.
+func cmdRunAll(args []string) int {
+	for _, arg := range args {
+		if arg == "-f" || arg == "--f" || strings.HasPrefix(arg, "-f=") || strings.HasPrefix(arg, "--f=") {
+			return cmdRun(args)
+		}
+	}
+	return cmdRun(append([]string{"-f", readVersionsManifest}, args...))
+}
+
+// cmdTopology - `perflab topology -f experiment.yaml [-envs ...] [-up] [-- run parametreleri]` komutu
+// Deneyi her ortamda ayrı bir `perflab run` process'iyle çalıştırır (ortamlar arasında client ve
+// bağlantı ayarı paylaşılmaz), ardından runs/ altındaki özetlerden karşılaştırma raporu yazar.
+// "--" sonrası parametreler her run'a aynen aktarılır (ör: -- -sign perflab.key).
+// Döndürür: 1 = en az bir ortamda özet üretilemedi
+func cmdTopology(args []string) int {
+	fs := flag.NewFlagSet("topology", flag.ExitOnError)
+	manifestPath := fs.String("f", readVersionsManifest, "Deney manifest dosyası (YAML, outputs: [json] gerekli)")
+	envs := fs.String("envs", topologyEnvironments, "Karşılaştırılacak perflab.yaml ortamları (virgülle ayrılmış, ilki oranların referansı)")
+	up := fs.Bool("up", false, "Önce topology container'larını başlat (docker compose --profile topology up -d --wait)")
+	down := fs.Bool("down", false, "Sonunda replica set ve sharded container'larını durdur")
+	fs.Parse(args)
+
+	manifest, err := LoadManifest(*manifestPath)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return 2
+	}
+	if !hasOutput(manifest, "json") {
+		fmt.Println("❌ Karşılaştırma summary.json'dan yapılır: manifest'te outputs: [json] gerekli")
+		return 2
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return 1
+	}
+
+	// docker-compose.yml mongo-perf-lab klasöründe, perflab app klasöründe çalışır
+	compose := func(args ...string) error {
+		cmd := exec.Command("docker", append([]string{"compose", "-f", "../docker-compose.yml", "--profile", "topology"}, args...)...)
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		return cmd.Run()
+	}
+	if *up {
+		fmt.Println("🐳 Topoloji container'ları başlatılıyor (sağlıklı olana kadar beklenir)...")
+		if err := compose("up", "-d", "--wait"); err != nil {
+			fmt.Printf("❌ docker compose: %v\n", err)
+			return 1
+		}
+	}
+	if *down {
+		defer compose("stop", "mongo-replset", "mongo-sharded")
+	}
+
+	var runs []TopologyRun
+	failed := 0
+	for _, name := range strings.Split(*envs, ",") {
+		name = strings.TrimSpace(name)
+		fmt.Printf("\n🕸️  ===== %s =====\n", name)
+		before, _ := filepath.Glob(filepath.Join("runs", "*"))
+
+		cmd := exec.Command(exe, append([]string{"run", "-f", *manifestPath, "--env", name}, fs.Args()...)...)
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		runErr := cmd.Run() // Çıkış kodu 1 = assertion başarısız: Özet yine de yazılmıştır
+
+		run := TopologyRun{Environment: name}
+		after, _ := filepath.Glob(filepath.Join("runs", "*"))
+		for _, dir := range after {
+			if slices.Contains(before, dir) {
+				continue
+			}
+			if s, err := LoadRunSummary(dir); err == nil {
+				run.Dir, run.Summary, run.Topology = dir, s, s.Topology
+			}
+		}
+		if run.Summary == nil {
+			run.Error = "özet yok"
+			if runErr != nil {
+				run.Error = runErr.Error()
+			}
+			fmt.Printf("❌ %s: %s\n", name, run.Error)
+			failed++
+		}
+		runs = append(runs, run)
+	}
+
+	reportDir := filepath.Join("runs", fmt.Sprintf("topology_%s_%s", sanitizeName(manifest.Name), time.Now().Format("20060102_150405")))
+	if err := os.MkdirAll(reportDir, 0755); err != nil {
+		fmt.Printf("❌ Rapor klasörü oluşturulamadı: %v\n", err)
+		return 1
+	}
+	report, err := os.Create(filepath.Join(reportDir, "TOPOLOGY.md"))
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return 1
+	}
+	fmt.Println()
+	err = WriteTopologyReport(io.MultiWriter(report, os.Stdout), manifest.Name, runs)
+	if cerr := report.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		fmt.Printf("❌ Rapor yazılamadı: %v\n", err)
+		return 1
+	}
+	data, _ := json.MarshalIndent(runs, "", "  ")
+	os.WriteFile(filepath.Join(reportDir, "topology.json"), data, 0644)
+
+	fmt.Printf("\n📝 %s\n", filepath.Join(reportDir, "TOPOLOGY.md"))
+	if failed > 0 {
+		return 1
+	}
+	return 0
+}
+
+// cmdVerify - `perflab verify runs/<deney> [-pub perflab.pub]` komutu
+// Döndürür: 0 = tüm dosyalar ve imza doğrulandı
+func cmdVerify(args []string) int {
+	fs := flag.NewFlagSet("verify", flag.ExitOnError)
+	pubPath := fs.String("pub", "", "Güvenilen Ed25519 açık anahtarı (PEM); verilmezse imza yalnızca kendi anahtarıyla kontrol edilir")
+	// flag paketi ilk parametre olmayan argümanda durur: "verify runs/x -pub k.pub" de çalışsın
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		args = append(args[1:], args[0])
+	}
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fmt.Println("❌ Kullanım: perflab verify runs/<deney> [-pub perflab.pub]")
+		return 2
+	}
+	var trusted ed25519.PublicKey
+	if *pubPath != "" {
+		var err error
+		if trusted, err = LoadPublicKey(*pubPath); err != nil {
+			fmt.Printf("❌ -pub: %v\n", err)
+			return 2
+		}
+	}
+
+	m, problems, err := VerifyArtifacts(fs.Arg(0), trusted)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return 2
+	}
+	fmt.Printf("🧪 %s (%s)\n", m.Experiment, m.CreatedAt.Format(time.RFC3339))
+	if p := m.Provenance; p != nil {
+		fmt.Printf("   manifest  %s\n", p.ManifestSHA256)
+		fmt.Printf("   kaynak    %s %s\n", p.SourceSHA256, p.SourceRevision)
+		if p.DatasetChecksum != "" {
+			fmt.Printf("   veri seti %s (%d doküman)\n", p.DatasetChecksum, p.DatasetCount)
+		}
+		fmt.Printf("   sürümler  %s, mongo-go-driver %s\n", p.GoVersion, p.DriverVersion)
+	}
+	if len(problems) > 0 {
+		fmt.Printf("\n❌ %d sorun:\n", len(problems))
+		for _, p := range problems {
+			fmt.Printf("  - %s\n", p)
+		}
+		return 1
+	}
+	switch {
+	case m.Signature == nil:
+		fmt.Printf("\n✅ %d dosya değişmemiş (imzasız: artifacts.json'ın kendisi doğrulanamaz)\n", len(m.Files))
+	case trusted == nil:
+		fmt.Printf("\n✅ %d dosya değişmemiş, imza tutarlı (⚠️  -pub verilmedi: imzalayan doğrulanmadı)\n", len(m.Files))
+	default:
+		fmt.Printf("\n✅ %d dosya değişmemiş, imza güvenilen anahtarla doğrulandı\n", len(m.Files))
+	}
+	return 0
+}
+
+// cmdWriteup - `perflab writeup runs/<deney> [-o rapor.md] [-baseline read_v1]` komutu
+// Rapor varsayılan olarak çalıştırma klasörüne yazılır. Klasör imzalıysa (artifacts.json) rapor
+// listede olmadığından verify onu "listede olmayan dosya" olarak gösterir; bu durumda -o ile
+// klasör dışına yazın
+func cmdWriteup(args []string) int {
+	fs := flag.NewFlagSet("writeup", flag.ExitOnError)
+	out := fs.String("o", "", "Rapor dosyası (varsayılan: runs/<deney>/WRITEUP.md, - = ekran)")
+	baseline := fs.String("baseline", "", "Hız oranlarının referans benchmark'ı (varsayılan: ilk benchmark)")
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		args = append(args[1:], args[0]) // bkz. cmdVerify
+	}
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fmt.Println("❌ Kullanım: perflab writeup runs/<deney> [-o rapor.md] [-baseline read_v1]")
+		return 2
+	}
+	runDir := fs.Arg(0)
+	summary, err := LoadRunSummary(runDir)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return 2
+	}
+	if info, err := os.Stat(runDir); err != nil || !info.IsDir() {
+		runDir = filepath.Dir(runDir)
+	}
+
+	if *out == "-" {
+		if err := WriteExperimentWriteup(os.Stdout, summary, runDir, *baseline); err != nil {
+			fmt.Printf("❌ %v\n", err)
+			return 1
+		}
+		return 0
+	}
+	path := *out
+	if path == "" {
+		path = filepath.Join(runDir, "WRITEUP.md")
+	}
+	if err := WriteExperimentWriteupFile(path, summary, runDir, *baseline); err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return 1
+	}
+	fmt.Printf("📝 %s\n", path)
+	return 0
+}
+
+// cmdBrowse - `perflab browse [-runs runs] [-o .]` komutu
+func cmdBrowse(args []string) int {
+	fs := flag.NewFlagSet("browse", flag.ExitOnError)
+	runsDir := fs.String("runs", "runs", "Çalıştırma klasörlerinin bulunduğu dizin")
+	outDir := fs.String("o", ".", "HTML dışa aktarımlarının yazılacağı dizin")
+	fs.Parse(args)
+
+	runs, err := LoadBrowseRuns(*runsDir)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return 2
+	}
+	if len(runs) == 0 {
+		fmt.Printf("📭 %s altında summary.json olan çalıştırma yok (manifest'te outputs: [json] gerekli)\n", *runsDir)
+		return 1
+	}
+	if err := RunBrowser(runs, *outDir); err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// cmdLogs - `perflab logs [-run id] [-level warn] [-grep COLLSCAN]` komutu
+// grep gibi: Eşleşme yoksa çıkış kodu 1
+func cmdLogs(args []string) int {
+	fs := flag.NewFlagSet("logs", flag.ExitOnError)
+	runsDir := fs.String("runs", "runs", "Çalıştırma klasörlerinin bulunduğu dizin")
+	run := fs.String("run", "", "Çalıştırma kimliği (runs/ altındaki klasör adı) veya öneki - boş: tümü")
+	script := fs.String("script", "", "Sadece bu script'in satırları (ör: read_v3)")
+	level := fs.String("level", "info", "En düşük seviye: info, warn, error")
+	pattern := fs.String("grep", "", "Satırda aranacak düzenli ifade")
+	ignoreCase := fs.Bool("i", false, "-grep büyük/küçük harf duyarsız")
+	fs.Parse(args)
+
+	if !ValidLogLevel(*level) {
+		fmt.Printf("❌ Geçersiz -level: %q (info, warn, error)\n", *level)
+		return 2
+	}
+	q := LogQuery{Run: *run, Script: *script, Level: *level}
+	if *pattern != "" {
+		expr := *pattern
+		if *ignoreCase {
+			expr = "(?i)" + expr
+		}
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			fmt.Printf("❌ -grep: %v\n", err)
+			return 2
+		}
+		q.Grep = re
+	}
+
+	events, scanned, err := SearchRunEvents(*runsDir, q)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return 2
+	}
+	if scanned == 0 {
+		if *run != "" {
+			fmt.Printf("📭 %s altında %q ile başlayan ve %s olan çalıştırma yok\n", *runsDir, *run, eventsFile)
+		} else {
+			fmt.Printf("📭 %s altında %s olan çalıştırma yok\n", *runsDir, eventsFile)
+		}
+		return 1
+	}
+	for _, e := range events {
+		source := e.Script
+		if e.Repetition > 0 {
+			source += fmt.Sprintf("#%d", e.Repetition)
+		}
+		fmt.Printf("%s  %s  %s  %-5s  %s\n", e.Run, source, e.Time.Format("15:04:05"), strings.ToUpper(e.Level), e.Line)
+	}
+	fmt.Printf("\n🔎 %d satır (%d çalıştırma tarandı)\n", len(events), scanned)
+	if len(events) == 0 {
+		return 1
+	}
+	return 0
+}
+
+// cmdKeygen - `perflab keygen [-o perflab]` komutu
+func cmdKeygen(args []string) int {
+	fs := flag.NewFlagSet("keygen", flag.ExitOnError)
+	prefix := fs.String("o", "perflab", "Dosya adı öneki: <o>.key (özel) ve <o>.pub (açık)")
+	fs.Parse(args)
+	if _, err := os.Stat(*prefix + ".key"); err == nil {
+		fmt.Printf("❌ %s.key zaten var (üzerine yazılmaz)\n", *prefix)
+		return 1
+	}
+	if err := GenerateKeyPair(*prefix); err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return 1
+	}
+	fmt.Printf("🔑 %s.key (özel, paylaşmayın) ve %s.pub (doğrulayanlara verin) yazıldı\n", *prefix, *prefix)
+	return 0
+}
+
+// cmdGC - `perflab gc --keep 30d [-runs runs] [-dry-run]` komutu
+// Klasör adındaki zamana göre (<deney>_20060102_150405) saklama süresini aşan çalıştırmaları siler;
+// adında zaman olmayan klasörlere dokunmaz
+func cmdGC(args []string) int {
+	fs := flag.NewFlagSet("gc", flag.ExitOnError)
+	runsDir := fs.String("runs", "runs", "Çalıştırma klasörlerinin bulunduğu dizin")
+	keep := fs.String("keep", "", "Saklama süresi: Bundan eski çalıştırmalar silinir (ör: 30d, 12h)")
+	dryRun := fs.Bool("dry-run", false, "Silmeden sadece listele")
+	fs.Parse(args)
+
+	if *keep == "" {
+		fmt.Println("❌ Kullanım: perflab gc --keep 30d [-runs runs] [-dry-run]")
+		return 2
+	}
+	retention, err := ParseRetention(*keep)
+	if err != nil {
+		fmt.Printf("❌ -keep: %v\n", err)
+		return 2
+	}
+	runs, skipped, err := ListRunDirs(*runsDir)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return 2
+	}
+
+	cutoff := time.Now().Add(-retention)
+	fmt.Printf("🧹 %s: %s öncesindeki çalıştırmalar siliniyor\n", *runsDir, cutoff.Format("2006-01-02 15:04"))
+	removed, kept, failed := 0, 0, 0
+	var freed int64
+	for _, run := range runs {
+		if !run.StartedAt.Before(cutoff) {
+			kept++
+			continue
+		}
+		if !*dryRun {
+			if err := os.RemoveAll(run.Path); err != nil {
+				fmt.Printf("  ❌ %s: %v\n", run.Path, err)
+				failed++
+				continue
+			}
+		}
+		fmt.Printf("  🗑️  %s (%s, %.1f MB)\n", run.Path, run.StartedAt.Format("2006-01-02 15:04"), float64(run.Size)/(1024*1024))
+		removed++
+		freed += run.Size
+	}
+	for _, path := range skipped {
+		fmt.Printf("  ⏭️  %s: adında çalıştırma zamanı yok, atlandı\n", path)
+	}
+
+	verb := "silindi"
+	if *dryRun {
+		verb = "silinecek (-dry-run: hiçbir şey silinmedi)"
+	}
+	fmt.Printf("\n✅ %d çalıştırma %s, %.1f MB; %d çalıştırma saklandı\n", removed, verb, float64(freed)/(1024*1024), kept)
+	if failed > 0 {
+		return 1
+	}
+	return 0
+}
+
+// finishCoordination - Kayıtları yükler; lider tüm client'ların sonuçlarını bekleyip birleştirir
+// ve runs/<deney>/cluster.json'a yazar
+func finishCoordination(coord *Coordinator, summary RunSummary, runDir string) int {
+	if err := coord.Upload(summary.Records, summary.Host); err != nil {
+		fmt.Printf("❌ Sonuçlar yüklenemedi: %v\n", err)
+		coord.Abort(coord.Client + " sonuçlarını yükleyemedi")
+		return 1
+	}
+	if !coord.Leader {
+		fmt.Printf("\n📤 Sonuçlar yüklendi, küme özeti lider client'ta\n")
+		return 0
+	}
+	fmt.Printf("\n📥 %d client'ın sonuçları bekleniyor...\n", coord.Clients)
+	results, err := coord.Collect()
+	if err != nil {
+		fmt.Printf("❌ Koordinasyon: %v\n", err)
+		return 1
+	}
+	merged := MergeClusterRecords(results)
+	skews, err := coord.Skews()
+	if err != nil {
+		fmt.Printf("⚠️  Başlama farkları okunamadı: %v\n", err)
+	}
+	printClusterSummary(coord.ID, results, merged, skews)
+
+	cluster := map[string]any{"coordination": coord.Info(), "clients": results, "records": merged, "skews": skews}
+	data, _ := json.MarshalIndent(cluster, "", "  ")
+	os.WriteFile(filepath.Join(runDir, "cluster.json"), []byte(Redact(string(data))), 0644)
+	return 0
+}
+
+// mainFuncPattern - Bir dosyanın bağımsız script (kendi main'i olan) olup olmadığını anlamak için
+var mainFuncPattern = regexp.MustCompile(`(?m)^func main\(\)`)
+
+// scriptFiles - Bir script'i çalıştırmak için gereken dosya listesini döndürür
+// main() içermeyen tüm .go dosyaları ortak yardımcı dosyalardır (main.go, analyzer.go, logger.go...)
+// ve her script'le birlikte derlenir. Böylece yeni yardımcı dosya eklendiğinde bu listeyi
+// elle güncellemek gerekmez.
+func scriptFiles(name string) ([]string, error) {
+	script := name + ".go"
+	if _, err := os.Stat(script); err != nil {
+		return nil, fmt.Errorf("script bulunamadı: %s", script)
+	}
+
+	goFiles, err := filepath.Glob("*.go")
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, f := range goFiles {
+		if strings.HasSuffix(f, "_test.go") {
+			continue
+		}
+		data, err := os.ReadFile(f)
+		if err != nil {
+			return nil, err
+		}
+		if !mainFuncPattern.Match(data) {
+			files = append(files, f)
+		}
+	}
+	return append(files, script), nil
+}
+
+// runScript - Bir benchmark/yardımcı script'i ayrı process olarak çalıştırır
+// Çıktı doğrudan terminale aktarılır
+func runScript(name string, args []string, env []string) error {
+	files, err := scriptFiles(name)
+	if err != nil {
+		return err
+	}
+
+	cmdArgs := append([]string{"run"}, files...)
+	cmdArgs = append(cmdArgs, args...)
+	cmd := exec.Command("go", cmdArgs...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = append(os.Environ(), env...)
+	return cmd.Run()
+}
+
+// startIngest - growth script'ini arka planda başlatır ve ilk yazma örneğini bekler
+// go run yerine önce binary derlenir: go run, aldığı sinyali çalıştırdığı programa iletmez,
+// bu yüzden durdurma sinyalinin doğrudan growth process'ine gitmesi gerekir.
+func startIngest(spec *IngestSpec, runDir, samplesFile string) (*exec.Cmd, error) {
+	files, err := scriptFiles("growth")
+	if err != nil {
+		return nil, err
+	}
+	binary, _ := filepath.Abs(filepath.Join(runDir, "growth"))
+	if runtime.GOOS == "windows" {
+		binary += ".exe"
+	}
+	build := exec.Command("go", append([]string{"build", "-o", binary}, files...)...)
+	build.Stdout = os.Stdout
+	build.Stderr = os.Stderr
+	if err := build.Run(); err != nil {
+		return nil, fmt.Errorf("growth derlenemedi: %v", err)
+	}
+
+	args := []string{
+		"-rate", strconv.FormatFloat(spec.Rate, 'f', -1, 64),
+		"-batch", strconv.Itoa(spec.BatchSize),
+	}
+	if spec.Shape != "" {
+		args = append(args, "-shape", spec.Shape)
+	}
+	cmd := exec.Command(binary, args...)
+	// growth kendi ilerleme satırlarını growth_results.txt'ye yazar,
+	// terminalde benchmark çıktılarıyla karışmasın diye stdout bağlanmaz
+	cmd.Stderr = os.Stderr
+	cmd.Env = append(os.Environ(), "PERFLAB_INGEST_FILE="+samplesFile)
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	// İlk örnek gelene kadar bekle - benchmark'lar yazma yükü oturduktan sonra başlasın
+	deadline := time.Now().Add(30 * time.Second)
+	for time.Now().Before(deadline) {
+		if info, err := os.Stat(samplesFile); err == nil && info.Size() > 0 {
+			return cmd, nil
+		}
+		time.Sleep(200 * time.Millisecond)
+	}
+	stopIngest(cmd)
+	return nil, fmt.Errorf("growth 30 saniye içinde yazmaya başlamadı")
+}
+
+// stopIngest - growth process'ini durdurur (birden fazla çağrılabilir)
+// Önce SIGINT gönderilir ki son saniyenin örneği ve özet yazılsın;
+// 15 saniye içinde çıkmazsa process öldürülür
+func stopIngest(cmd *exec.Cmd) {
+	if cmd.ProcessState != nil {
+		return
+	}
+	done := make(chan struct{})
+	go func() {
+		cmd.Wait()
+		close(done)
+	}()
+
+	// Windows'ta os.Interrupt desteklenmez, doğrudan Kill'e düşer
+	if err := cmd.Process.Signal(os.Interrupt); err != nil {
+		cmd.Process.Kill()
+	}
+	select {
+	case <-done:
+	case <-time.After(15 * time.Second):
+		cmd.Process.Kill()
+		<-done
+	}
+}
+
+// createManifestIndexes - Manifest'teki index'leri oluşturur
+// Aynı isimde index zaten varsa MongoDB hata vermez (idempotent)
+func createManifestIndexes(col *mongo.Collection, indexes []IndexSpec) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	for _, idx := range indexes {
+		keys, err := idx.KeysDoc()
+		if err != nil {
+			return err
+		}
+		opts := options.Index()
+		if idx.Name != "" {
+			opts.SetName(idx.Name)
+		}
+		name, err := col.Indexes().CreateOne(ctx, mongo.IndexModel{Keys: keys, Options: opts})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("  ✅ %s\n", name)
+	}
+	return nil
+}
+
+// readMetricsRecords - metrics.jsonl dosyasındaki tüm kayıtları okur
+func readMetricsRecords(path string) ([]MetricsRecord, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil // Hiçbir benchmark metrik yazmadı
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	var records []MetricsRecord
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var r MetricsRecord
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			return records, err
+		}
+		records = append(records, r)
+	}
+	return records, scanner.Err()
+}
+
+// evaluateAssertions - Her assertion'ı ilgili benchmark'ın tekrarlarının ortalamasıyla değerlendirir
+func evaluateAssertions(assertions []AssertionSpec, records []MetricsRecord) []AssertionResult {
+	results := make([]AssertionResult, 0, len(assertions))
+	for _, a := range assertions {
+		res := AssertionResult{AssertionSpec: a}
+
+		sum, n := 0.0, 0
+		for _, r := range records {
+			if r.Benchmark != a.Benchmark || (a.Variant != "" && r.Variant != a.Variant) {
+				continue
+			}
+			v, _ := r.Value(a.Metric)
+			sum += v
+			n++
+		}
+
+		if n == 0 {
+			// Metrik yoksa assertion geçmiş sayılmaz - sessizce geçmek yanıltıcı olur
+			res.Reason = "metrik kaydı bulunamadı"
+		} else {
+			res.Value = sum / float64(n)
+			res.Passed = a.Check(res.Value)
+		}
+		results = append(results, res)
+	}
+	return results
+}
+
+// estimateCosts - Kayıtları benchmark (ve varyant) bazında ortalayıp maliyet modeline uygular
+// Sıra, kayıtlardaki ilk görülme sırasıdır (manifest sırası)
+func estimateCosts(model benchkit.CostModel, records []MetricsRecord) []BenchmarkCost {
+	type totals struct {
+		n                     int
+		ops, bytes            int64
+		duration, cpu, server float64
+	}
+	var order []string
+	byName := map[string]*totals{}
+	for _, r := range records {
+		t, ok := byName[r.Name()]
+		if !ok {
+			t = &totals{}
+			byName[r.Name()] = t
+			order = append(order, r.Name())
+		}
+		t.n++
+		t.ops += int64(r.RecordsRead)
+		t.bytes += r.BytesReceived
+		t.duration += r.DurationMs
+		t.cpu += r.CPUSeconds
+		t.server += r.ServerSeconds
+	}
+
+	var costs []BenchmarkCost
+	for _, name := range order {
+		t := byName[name]
+		n := float64(t.n)
+		estimate := model.Estimate(benchkit.CostInputs{
+			Ops:        t.ops / int64(t.n),
+			Duration:   time.Duration(t.duration / n * float64(time.Millisecond)),
+			ClientCPU:  time.Duration(t.cpu / n * float64(time.Second)),
+			ServerTime: time.Duration(t.server / n * float64(time.Second)),
+			Bytes:      t.bytes / int64(t.n),
+		})
+		costs = append(costs, BenchmarkCost{Benchmark: name, CostEstimate: estimate})
+	}
+	return costs
+}
+
+// printRunSummary - Benchmark ve assertion özetini yazdırır
+// Döndürür: başarısız assertion sayısı
+func printRunSummary(summary RunSummary) int {
+	fmt.Println("\n=== DENEY ÖZETİ ===")
+	fmt.Printf("🖥️  Host: %s\n", summary.Host)
+	fmt.Printf("⏱️  Toplam Süre: %v\n", summary.FinishedAt.Sub(summary.StartedAt).Round(time.Millisecond))
+
+	if len(summary.Records) > 0 {
+		withIngest := summary.Manifest.Ingest != nil
+		fmt.Printf("\n  %-28s %5s %12s %10s %12s %10s", "benchmark", "tekrar", "süre (ms)", "bellek MB", "incelenen", "verim %")
+		if withIngest {
+			fmt.Printf(" %12s", "yazma/sn")
+		}
+		fmt.Println()
+		for _, r := range summary.Records {
+			fmt.Printf("  %-28s %5d %12.1f %10.2f %12d %10.2f",
+				r.Name(), r.Repetition, r.DurationMs, r.MemoryMB, r.DocsExamined, r.Efficiency)
+			if withIngest {
+				fmt.Printf(" %12.1f", r.IngestRate)
+			}
+			fmt.Println()
+		}
+		printComparison(summary.Records)
+	}
+
+	// Anti-pattern bulguları: Ayrıntılar script'lerin kendi çıktısında, burada sadece kodlar
+	var withFindings []string
+	findings := map[string][]string{}
+	for _, r := range summary.Records {
+		name := r.Name()
+		for _, code := range r.Findings {
+			if len(findings[name]) == 0 {
+				withFindings = append(withFindings, name)
+			}
+			if !slices.Contains(findings[name], code) {
+				findings[name] = append(findings[name], code)
+			}
+		}
+	}
+	if len(withFindings) > 0 {
+		fmt.Println("\n🧭 Anti-pattern'ler (ayrıntı: <benchmark>_rep<N>.txt):")
+		for _, name := range withFindings {
+			fmt.Printf("  %-28s %s\n", name, strings.Join(findings[name], ", "))
+		}
+	}
+
+	if len(summary.Costs) > 0 {
+		fmt.Printf("\n💰 Maliyet Tahmini (%s, tekrar ortalaması):\n", summary.Costs[0].Model)
+		fmt.Printf("  %-28s %10s %10s %10s %14s %14s\n", "benchmark", "CPU-sn", "sunucu sn", "veri MB", "$/1M işlem", "Wh/1M işlem")
+		for _, c := range summary.Costs {
+			fmt.Printf("  %-28s %10.3f %10.3f %10.2f %14.4f %14.4f\n",
+				c.Benchmark, c.ClientCPUSeconds, c.ServerSeconds, float64(c.BytesTransferred)/(1024*1024),
+				c.PerMillionOpsUSD, c.EnergyPerMillionOpsWh)
+		}
+		fmt.Println("  ℹ️  Liste fiyatlarıyla kaba tahmin - versiyonlar arası oran anlamlıdır")
+	}
+
+	failed := 0
+	if len(summary.Assertions) > 0 {
+		fmt.Println("\n📏 Assertion'lar:")
+		for _, a := range summary.Assertions {
+			bounds := ""
+			if a.Min != nil {
+				bounds += fmt.Sprintf(" min=%g", *a.Min)
+			}
+			if a.Max != nil {
+				bounds += fmt.Sprintf(" max=%g", *a.Max)
+			}
+
+			switch {
+			case a.Passed:
+				fmt.Printf("  ✅ %s.%s = %.2f (%s )\n", a.Name(), a.Metric, a.Value, bounds)
+			case a.Reason != "":
+				failed++
+				fmt.Printf("  ❌ %s.%s: %s\n", a.Name(), a.Metric, a.Reason)
+			default:
+				failed++
+				fmt.Printf("  ❌ %s.%s = %.2f (%s )\n", a.Name(), a.Metric, a.Value, bounds)
+			}
+		}
+	}
+	return failed
+}
+
+// printComparison - Birden fazla benchmark varsa tekrar ortalamalarını tek tabloda karşılaştırır
+// Oran (x) ilk benchmark'a göredir: run-all'da read_bad, yani her versiyonun toplam iyileşmesi
+func printComparison(records []MetricsRecord) {
+	type average struct {
+		name                         string
+		n                            int
+		duration, memory, efficiency float64
+		examined                     int64
+	}
+	var rows []*average
+	byName := map[string]*average{}
+	for _, r := range records {
+		a := byName[r.Name()]
+		if a == nil {
+			a = &average{name: r.Name()}
+			byName[r.Name()] = a
+			rows = append(rows, a)
+		}
+		a.n++
+		a.duration += r.DurationMs
+		a.memory += r.MemoryMB
+		a.examined += r.DocsExamined
+		a.efficiency += r.Efficiency
+	}
+	if len(rows) < 2 {
+		return
+	}
+
+	fmt.Printf("\n📊 Karşılaştırma (tekrar ortalaması, x: %s'e göre):\n", rows[0].name)
+	fmt.Printf("  %-28s %12s %8s %10s %8s %12s %10s\n", "benchmark", "süre (ms)", "x", "bellek MB", "x", "incelenen", "verim %")
+	base := rows[0]
+	ratio := func(base, v float64) string {
+		if v <= 0 || base <= 0 {
+			return "-"
+		}
+		return fmt.Sprintf("%.2fx", base/v)
+	}
+	for _, a := range rows {
+		n := float64(a.n)
+		fmt.Printf("  %-28s %12.1f %8s %10.2f %8s %12d %10.2f\n",
+			a.name, a.duration/n, ratio(base.duration/float64(base.n), a.duration/n),
+			a.memory/n, ratio(base.memory/float64(base.n), a.memory/n), a.examined/int64(a.n), a.efficiency/n)
+	}
+}
+
+func hasOutput(m *Manifest, format string) bool {
+	for _, out := range m.Outputs {
+		if out == format {
+			return true
+		}
+	}
+	return false
+}
+
+// sanitizeName - Deney adını klasör adı olarak kullanılabilir hale getirir
+func sanitizeName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r == ' ' || r == ':' {
+			return '_'
+		}
+		return r
+	}, filepath.Base(name))
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// planwatch.go - Plan değişikliği dedektörü
+// Bir sorgunun planını arka planda periyodik olarak explain eder ve kazanan plan
+// değiştiğinde (ör: index silindi → IXSCAN'den COLLSCAN'e geçiş) olayı zamanıyla kaydeder.
+// Workload çalışırken açılıp kapatılır; zaman çizelgesindeki gecikme sıçramasıyla
+// plan değişikliği aynı zaman ekseninde karşılaştırılabilir.
+
+// PlanChange - Tespit edilen tek bir plan değişikliği
+type PlanChange struct {
+	At   time.Duration // Dedektör başladıktan ne kadar sonra görüldü
+	From PlanSummary   // Önceki plan (ilk örnekte boş)
+	To   PlanSummary   // Yeni plan
+}
+
+// PlanWatcher - Arka planda çalışan dedektör
+type PlanWatcher struct {
+	start   time.Time
+	stop    chan struct{}
+	done    chan struct{}
+	mu      sync.Mutex
+	current PlanSummary
+	changes []PlanChange
+	samples int
+	errors  int
+}
+
+// planKey - İki planın aynı olup olmadığını belirleyen anahtar
+// Sayaçlar (docsExamined vb.) her çalıştırmada değişebilir, plan sadece stage zinciri ve index'tir
+func planKey(p PlanSummary) string {
+	return p.StageChain() + "|" + p.IndexName
+}
+
+// WatchPlan - Dedektörü başlatır
+// Parametreler:
+//   - col: Sorgunun collection'ı
+//   - filter, opts: İzlenecek sorgu (workload'un çalıştırdığı sorguyla aynı şekilde olmalı)
+//   - interval: Explain aralığı - kısa aralık değişikliği daha erken yakalar ama sunucuya yük bindirir
+//
+// İlk örnek başlangıç planı olarak kaydedilir (From boş bir PlanChange)
+func WatchPlan(col *mongo.Collection, filter bson.M, interval time.Duration, opts *options.FindOptions) *PlanWatcher {
+	w := &PlanWatcher{
+		start: time.Now(),
+		stop:  make(chan struct{}),
+		done:  make(chan struct{}),
+	}
+	go func() {
+		defer close(w.done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			w.sample(col, filter, opts)
+			select {
+			case <-w.stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return w
+}
+
+func (w *PlanWatcher) sample(col *mongo.Collection, filter bson.M, opts *options.FindOptions) {
+	explainResult, err := ExplainQuery(col, filter, opts)
+	at := time.Since(w.start)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if err != nil {
+		w.errors++
+		return
+	}
+	w.samples++
+	plan := SummarizeExplain(explainResult)
+	if len(w.changes) == 0 || planKey(plan) != planKey(w.current) {
+		w.changes = append(w.changes, PlanChange{At: at, From: w.current, To: plan})
+	}
+	w.current = plan
+}
+
+// Stop - Dedektörü durdurur ve görülen planları döndürür
+// Döndürür: İlk eleman başlangıç planıdır, sonrakiler değişikliklerdir
+func (w *PlanWatcher) Stop() []PlanChange {
+	close(w.stop)
+	<-w.done
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.changes
+}
+
+// Samples - Başarılı ve hatalı explain sayısı
+func (w *PlanWatcher) Samples() (ok, failed int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.samples, w.errors
+}
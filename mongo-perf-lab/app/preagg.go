@@ -0,0 +1,351 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"benchkit"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// preagg.go - Ön-toplama (counter dokümanları) ↔ okumada aggregation
+// "Kullanıcının toplam harcaması ve sipariş sayısı" iki şekilde sunulabilir:
+//   - ondemand: Her okumada siparişler üzerinde $match + $group (userId index'iyle)
+//   - counter:  Her siparişle birlikte kullanıcının sayaç dokümanı $inc ile güncellenir,
+//     okuma tek bir _id araması olur
+//
+//	{_id: userId, total: <toplam>, count: <sipariş sayısı>}
+//
+// Counter okumayı kullanıcının sipariş sayısından bağımsız hale getirir; bedeli her yazmada ikinci
+// bir update'tir, aynı kullanıcının sayacına gelen yazmalar da aynı dokümanda sıraya girer.
+// Hangisinin kazandığı okuma/yazma oranına bağlıdır: -reads ile birkaç oran taranır, her oranda
+// iki tasarım aynı (okuma|yazma, kullanıcı) işlem dizisini çalıştırır.
+//
+// Sipariş insert'ü ve sayaç güncellemesi transaction'sız ayrı komutlardır: Arada hata olursa sayaç
+// kayar. Sonda sayaçlar siparişlerden yeniden hesaplanan toplamlarla karşılaştırılır.
+//
+// KULLANIM:
+//   go run main.go config.go analyzer.go antipattern.go errors.go logger.go samples.go sink.go dataset.go flags.go latency.go preagg.go
+//   go run main.go config.go analyzer.go antipattern.go errors.go logger.go samples.go sink.go dataset.go flags.go latency.go preagg.go -users 500 -initial 500000 -reads 50,90,99
+
+// preaggOp - İşlem dizisinin bir adımı (iki tasarım aynı diziyi çalıştırır)
+type preaggOp struct {
+	read  bool
+	user  int
+	total int
+}
+
+// preaggDesign - Toplamların sunulma şekli
+type preaggDesign struct {
+	name     string
+	orders   *mongo.Collection
+	counters *mongo.Collection // ondemand'da nil
+	write    func(ctx context.Context, op preaggOp) error
+	read     func(ctx context.Context, user int) (total int64, err error)
+}
+
+// preaggRun - Bir (tasarım, okuma oranı) çalıştırmasının sonucu
+type preaggRun struct {
+	design   string
+	readPct  int
+	duration time.Duration
+	reads    latencyCase
+	writes   latencyCase
+}
+
+// opsPerSec - Tüm işlemlerin (okuma + yazma) hızı
+func (r preaggRun) opsPerSec() float64 {
+	if r.duration <= 0 {
+		return 0
+	}
+	return float64(r.reads.ops+r.writes.ops) / r.duration.Seconds()
+}
+
+func main() {
+	users := flag.Int("users", 1000, "Kullanıcı sayısı")
+	initial := flag.Int("initial", 200000, "Ölçümden önce yazılan sipariş sayısı (kullanıcı başına geçmiş)")
+	ops := flag.Int("ops", 20000, "Her okuma oranında çalıştırılan işlem sayısı")
+	readList := flag.String("reads", "10,50,90", "İşlemlerin okuma yüzdeleri (tarama)")
+	seed := flag.Int64("seed", 42, "Sipariş ve işlem dizisi için seed")
+	flag.Parse()
+
+	readPcts, err := parseIntList(*readList)
+	if err != nil {
+		fmt.Printf("Geçersiz -reads: %v\n", err)
+		return
+	}
+	for _, pct := range readPcts {
+		if pct > 100 {
+			fmt.Printf("Geçersiz -reads: %d (1-100)\n", pct)
+			return
+		}
+	}
+	if *users <= 0 || *initial < 0 || *ops <= 0 {
+		fmt.Println("❌ -users ve -ops pozitif, -initial negatif olmamalı")
+		return
+	}
+
+	logger, err := NewLogger("preagg_results.txt")
+	if err != nil {
+		fmt.Printf("Logger oluşturulamadı: %v\n", err)
+		return
+	}
+	defer CloseLogger(logger)
+
+	logger.WriteHeader("preagg - Counter Dokümanları vs Okumada Aggregation")
+
+	db := GetMongo().Database()
+	ctx := context.Background()
+	logger.Printf("📋 %d kullanıcı, başlangıçta %d sipariş (kullanıcı başına ~%d), oran başına %d işlem, okuma oranları %v%%\n",
+		*users, *initial, *initial / *users, *ops, readPcts)
+
+	ondemandOrders := db.Collection("preagg_orders_ondemand")
+	counterOrders := db.Collection("preagg_orders_counter")
+	counters := db.Collection("preagg_counters")
+	designs := []preaggDesign{
+		{
+			name:   "ondemand",
+			orders: ondemandOrders,
+			write: func(ctx context.Context, op preaggOp) error {
+				_, err := ondemandOrders.InsertOne(ctx, bson.M{"userId": op.user, "total": op.total, "createdAt": time.Now()})
+				return err
+			},
+			read: func(ctx context.Context, user int) (int64, error) {
+				return userTotal(ctx, ondemandOrders, user)
+			},
+		},
+		{
+			name:     "counter",
+			orders:   counterOrders,
+			counters: counters,
+			write: func(ctx context.Context, op preaggOp) error {
+				if _, err := counterOrders.InsertOne(ctx, bson.M{"userId": op.user, "total": op.total, "createdAt": time.Now()}); err != nil {
+					return err
+				}
+				_, err := counters.UpdateOne(ctx, bson.M{"_id": op.user},
+					bson.M{"$inc": bson.M{"total": op.total, "count": 1}}, options.Update().SetUpsert(true))
+				return err
+			},
+			read: func(ctx context.Context, user int) (int64, error) {
+				var c struct {
+					Total int64 `bson:"total"`
+				}
+				err := counters.FindOne(ctx, bson.M{"_id": user}).Decode(&c)
+				if err == mongo.ErrNoDocuments {
+					return 0, nil // Henüz siparişi olmayan kullanıcı
+				}
+				return c.Total, err
+			},
+		},
+	}
+
+	// Başlangıç verisi: Aynı siparişler iki tasarıma da yazılır, sayaçlar $group + $merge ile kurulur
+	logger.Println("\n⚙️  Başlangıç verisi yazılıyor...")
+	rng := rand.New(rand.NewSource(*seed))
+	history := make([]interface{}, *initial)
+	for i := range history {
+		history[i] = bson.M{"userId": rng.Intn(*users), "total": rng.Intn(5000), "createdAt": time.Now()}
+	}
+	for _, d := range designs {
+		for _, col := range []*mongo.Collection{d.orders, d.counters} {
+			if col != nil && HandleError(logger, "drop "+col.Name(), col.Drop(ctx)) {
+				return
+			}
+		}
+		if _, err := d.orders.Indexes().CreateOne(ctx, mongo.IndexModel{Keys: bson.D{{Key: "userId", Value: 1}}}); HandleError(logger, "index", err) {
+			return
+		}
+		for first := 0; first < len(history); first += 10000 {
+			batch := history[first:min(first+10000, len(history))]
+			if _, err := d.orders.InsertMany(ctx, batch, options.InsertMany().SetOrdered(false)); HandleError(logger, "insertMany", err) {
+				return
+			}
+		}
+	}
+	if *initial > 0 {
+		cursor, err := counterOrders.Aggregate(ctx, mongo.Pipeline{
+			{{Key: "$group", Value: bson.M{"_id": "$userId", "total": bson.M{"$sum": "$total"}, "count": bson.M{"$sum": 1}}}},
+			{{Key: "$merge", Value: bson.M{"into": counters.Name()}}},
+		})
+		if HandleError(logger, "counter $merge", err) {
+			return
+		}
+		cursor.Close(ctx)
+	}
+	logger.Println("  ✅ Hazır")
+
+	var runs []preaggRun
+	for _, pct := range readPcts {
+		// Her oran için tek işlem dizisi; iki tasarım aynı diziyi aynı sırayla çalıştırır
+		sequence := make([]preaggOp, *ops)
+		for i := range sequence {
+			sequence[i] = preaggOp{read: rng.Intn(100) < pct, user: rng.Intn(*users), total: rng.Intn(5000)}
+		}
+		logger.Printf("\n▶️  Okuma %%%d / yazma %%%d\n", pct, 100-pct)
+		for _, d := range designs {
+			run := runPreagg(ctx, d, pct, sequence, logger)
+			runs = append(runs, run)
+			logger.Printf("  %-9s %10v %10.0f işlem/sn   okuma p50 %-10v yazma p50 %v\n", d.name,
+				run.duration.Round(time.Millisecond), run.opsPerSec(),
+				run.reads.Summary().P50.Round(time.Microsecond), run.writes.Summary().P50.Round(time.Microsecond))
+			writePreaggRecords(run, logger)
+		}
+	}
+
+	// Yazma ek yükü ve okuma hızlanması: Her oranda counter'ın ondemand'a göre oranı
+	logger.Println("\n=== KARŞILAŞTIRMA (counter / ondemand) ===")
+	logger.Printf("  %-8s %14s %14s %14s %14s %10s\n", "okuma %", "yazma p50 x", "okuma p50 x", "ondemand op/s", "counter op/s", "kazanan")
+	for i := 0; i+1 < len(runs); i += 2 {
+		ondemand, counter := runs[i], runs[i+1]
+		winner := "ondemand"
+		if counter.opsPerSec() > ondemand.opsPerSec() {
+			winner = "counter"
+		}
+		logger.Printf("  %-8d %14s %14s %14.0f %14.0f %10s\n", ondemand.readPct,
+			p50Ratio(counter.writes, ondemand.writes), p50Ratio(ondemand.reads, counter.reads),
+			ondemand.opsPerSec(), counter.opsPerSec(), winner)
+	}
+	logger.Println("  yazma p50 x: counter yazmasının ondemand'a göre ek yükü (>1 yavaş)")
+	logger.Println("  okuma p50 x: counter okumasının ondemand'a göre hızlanması (>1 hızlı)")
+
+	// Sayaçlar siparişlerle tutarlı mı?
+	drift, err := counterDrift(ctx, counterOrders, counters)
+	if HandleError(logger, "counter kontrolü", err) {
+		PrintErrorSummary(logger)
+		return
+	}
+	if drift == 0 {
+		logger.Println("\n✅ Sayaçlar siparişlerden hesaplanan toplamlarla tutarlı")
+	} else {
+		logger.Printf("\n🚨 %d kullanıcının sayacı siparişlerle tutarsız: Insert ile $inc arasında hata olmuş\n", drift)
+		logger.Println("   → Tutarlılık şartsa ikisini transaction'da yazın ya da sayaçları periyodik yeniden hesaplayın")
+	}
+	logger.Println("\n💡 Counter'ın okuma kazancı kullanıcı başına sipariş sayısıyla büyür (-initial / -users);")
+	logger.Println("   yazmanın çoğunlukta olduğu iş yükünde her siparişe eklenen $inc, kazancı geçebilir.")
+
+	PrintErrorSummary(logger)
+
+	logger.Println("\n✅ Test tamamlandı! Sonuçlar 'preagg_results.txt' dosyasına kaydedildi.")
+}
+
+// runPreagg - İşlem dizisini tasarım üzerinde sırayla çalıştırır; okuma ve yazma gecikmeleri ayrı tutulur
+func runPreagg(ctx context.Context, d preaggDesign, readPct int, sequence []preaggOp, logger *Logger) preaggRun {
+	variant := fmt.Sprintf("%s/r%d", d.name, readPct)
+	run := preaggRun{
+		design:  d.name,
+		readPct: readPct,
+		reads:   latencyCase{name: "read/" + variant, hist: benchkit.NewHistogram()},
+		writes:  latencyCase{name: "write/" + variant, hist: benchkit.NewHistogram()},
+	}
+	samples := logger.LatencySamples()
+	start := time.Now()
+	for _, op := range sequence {
+		c := &run.writes
+		opStart := time.Now()
+		var err error
+		if op.read {
+			c = &run.reads
+			_, err = d.read(ctx, op.user)
+		} else {
+			err = d.write(ctx, op)
+		}
+		elapsed := time.Since(opStart)
+		c.hist.Record(elapsed)
+		samples.Record(c.name, 0, opStart, elapsed, err == nil)
+		c.duration += elapsed
+		c.ops++
+		if HandleError(logger, c.name, err) {
+			c.failures++
+			continue
+		}
+		c.docs++
+	}
+	run.duration = time.Since(start)
+	return run
+}
+
+// writePreaggRecords - Okuma ve yazma gecikmelerini ayrı kayıtlar, toplam hızı "<tasarım>/r<oran>" olarak yazar
+func writePreaggRecords(run preaggRun, logger *Logger) {
+	for _, c := range []latencyCase{run.reads, run.writes} {
+		if c.ops > 0 {
+			writeLatencyRecord("preagg", c, logger)
+		}
+	}
+	record := newMetricsRecord("preagg")
+	record.Variant = fmt.Sprintf("%s/r%d", run.design, run.readPct)
+	record.DurationMs = benchkit.Millis(run.duration)
+	record.RecordsRead = int(run.reads.docs)
+	record.DocsPerSec = run.opsPerSec()
+	logger.WriteRecord(record)
+}
+
+// p50Ratio - a'nın p50'si / b'nin p50'si ("2.50x"); işlem yoksa "-"
+func p50Ratio(a, b latencyCase) string {
+	if a.ops == 0 || b.ops == 0 || b.Summary().P50 <= 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.2fx", float64(a.Summary().P50)/float64(b.Summary().P50))
+}
+
+// userTotal - Kullanıcının sipariş toplamını aggregation ile hesaplar (ondemand okuması)
+func userTotal(ctx context.Context, orders *mongo.Collection, user int) (int64, error) {
+	cursor, err := orders.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"userId": user}}},
+		{{Key: "$group", Value: bson.M{"_id": nil, "total": bson.M{"$sum": "$total"}, "count": bson.M{"$sum": 1}}}},
+	})
+	if err != nil {
+		return 0, err
+	}
+	var rows []struct {
+		Total int64 `bson:"total"`
+	}
+	if err := cursor.All(ctx, &rows); err != nil || len(rows) == 0 {
+		return 0, err
+	}
+	return rows[0].Total, nil
+}
+
+// counterDrift - Sayacı, siparişlerden hesaplanan toplamdan veya sayıdan farklı olan kullanıcı sayısı
+func counterDrift(ctx context.Context, orders, counters *mongo.Collection) (int, error) {
+	cursor, err := orders.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$group", Value: bson.M{"_id": "$userId", "total": bson.M{"$sum": "$total"}, "count": bson.M{"$sum": 1}}}},
+	}, options.Aggregate().SetAllowDiskUse(true))
+	if err != nil {
+		return 0, err
+	}
+	type sums struct {
+		User  int   `bson:"_id"`
+		Total int64 `bson:"total"`
+		Count int64 `bson:"count"`
+	}
+	var expected []sums
+	if err := cursor.All(ctx, &expected); err != nil {
+		return 0, err
+	}
+	cursor, err = counters.Find(ctx, bson.M{})
+	if err != nil {
+		return 0, err
+	}
+	var stored []sums
+	if err := cursor.All(ctx, &stored); err != nil {
+		return 0, err
+	}
+
+	actual := make(map[int]sums, len(stored))
+	for _, s := range stored {
+		actual[s.User] = s
+	}
+	drift := 0
+	for _, e := range expected {
+		if actual[e.User] != e {
+			drift++
+		}
+		delete(actual, e.User)
+	}
+	return drift + len(actual), nil // Siparişi olmayan sayaçlar da tutarsızdır
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// prefetch.go - Çift tamponlu cursor (PrefetchCursor)
+// Normal cursor.Next döngüsünde batch bitince getMore gönderilir ve cevap gelene kadar decode
+// bekler; cevap gelince de network boşta kalır. Yani süre ≈ network + decode.
+// PrefetchCursor, cursor'ı arka plandaki bir goroutine'de batch batch okur: Uygulama bir
+// batch'i decode ederken sonraki batch'in getMore'u zaten yoldadır. Süre ≈ max(network, decode).
+//
+// Kullanım mongo.Cursor ile aynıdır:
+//
+//	pc := NewPrefetchCursor(ctx, cursor, 1)
+//	defer pc.Close(ctx)
+//	for pc.Next(ctx) {
+//		pc.Decode(&doc)
+//	}
+//	err := pc.Err()
+//
+// Dikkat: Her doküman kopyalanır (cursor.Current bir sonraki Next'te geçersizleşir) ve bellekte
+// depth+1 batch tutulur. Batch'ler büyükse (batchSize × doküman boyutu) bellek buna göre artar.
+
+// PrefetchCursor - Bir sonraki batch'i arka planda getiren cursor sarmalayıcısı
+type PrefetchCursor struct {
+	// Current - Next'in döndürdüğü doküman (mongo.Cursor.Current gibi)
+	Current bson.Raw
+
+	cursor  *mongo.Cursor
+	batches chan []bson.Raw
+	batch   []bson.Raw
+	pos     int
+	cancel  context.CancelFunc
+	done    chan struct{}
+
+	mu    sync.Mutex
+	err   error
+	wait  time.Duration // Next'in batch beklediği toplam süre
+	waits int
+}
+
+// NewPrefetchCursor - cursor'ı okumaya hemen başlar
+// depth: Decode edilen batch dışında hazırda bekleyebilecek batch sayısı (1 = çift tampon)
+// cursor'ın sahibi artık PrefetchCursor'dır: Close ile kapatılır
+func NewPrefetchCursor(ctx context.Context, cursor *mongo.Cursor, depth int) *PrefetchCursor {
+	if depth < 1 {
+		depth = 1
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	pc := &PrefetchCursor{
+		cursor:  cursor,
+		batches: make(chan []bson.Raw, depth),
+		cancel:  cancel,
+		done:    make(chan struct{}),
+	}
+	go pc.fetch(ctx)
+	return pc
+}
+
+// fetch - Arka plan goroutine'i: Batch dolunca (RemainingBatchLength == 0) kanala gönderir,
+// ardından cursor.Next sonraki getMore'u başlatır
+func (pc *PrefetchCursor) fetch(ctx context.Context) {
+	defer close(pc.done)
+	defer close(pc.batches)
+
+	var batch []bson.Raw
+	for pc.cursor.Next(ctx) {
+		if batch == nil {
+			batch = make([]bson.Raw, 0, pc.cursor.RemainingBatchLength()+1)
+		}
+		batch = append(batch, append(bson.Raw(nil), pc.cursor.Current...))
+		if pc.cursor.RemainingBatchLength() > 0 {
+			continue
+		}
+		select {
+		case pc.batches <- batch:
+			batch = nil
+		case <-ctx.Done():
+			return
+		}
+	}
+	if len(batch) > 0 {
+		select {
+		case pc.batches <- batch:
+		case <-ctx.Done():
+		}
+	}
+	if err := pc.cursor.Err(); err != nil && ctx.Err() == nil {
+		pc.setErr(err)
+	}
+}
+
+// Next - Sıradaki dokümana geçer; batch hazır değilse gelene kadar bekler
+func (pc *PrefetchCursor) Next(ctx context.Context) bool {
+	for pc.pos >= len(pc.batch) {
+		waitStart := time.Now()
+		select {
+		case batch, ok := <-pc.batches:
+			pc.mu.Lock()
+			pc.wait += time.Since(waitStart)
+			pc.waits++
+			pc.mu.Unlock()
+			if !ok {
+				pc.Current = nil
+				return false
+			}
+			pc.batch, pc.pos = batch, 0
+		case <-ctx.Done():
+			pc.setErr(ctx.Err())
+			return false
+		}
+	}
+	pc.Current = pc.batch[pc.pos]
+	pc.pos++
+	return true
+}
+
+// Decode - Current'ı val'e decode eder
+func (pc *PrefetchCursor) Decode(val interface{}) error {
+	return bson.Unmarshal(pc.Current, val)
+}
+
+// Err - Cursor'ın (veya Next'e verilen context'in) hatası
+func (pc *PrefetchCursor) Err() error {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+	return pc.err
+}
+
+// Wait - Next'in batch beklediği toplam süre ve bekleme sayısı
+// Süre küçükse getMore'lar decode ile tamamen örtüşmüştür (darboğaz decode), büyükse
+// decode network'ü bekliyordur (darboğaz network/sunucu)
+func (pc *PrefetchCursor) Wait() (time.Duration, int) {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+	return pc.wait, pc.waits
+}
+
+// Close - Arka plan okumasını durdurur ve cursor'ı kapatır
+func (pc *PrefetchCursor) Close(ctx context.Context) error {
+	pc.cancel()
+	<-pc.done
+	return pc.cursor.Close(ctx)
+}
+
+func (pc *PrefetchCursor) setErr(err error) {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+	if pc.err == nil {
+		pc.err = err
+	}
+}
@@ -0,0 +1,339 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/version"
+)
+
+// provenance.go - Sonuçların kaynağı (provenance) ve bütünlüğü
+// Performans değerlendirme belgelerine eklenen sayıların hangi deney tanımıyla, hangi veriyle
+// ve hangi kodla üretildiği, ve sonradan değiştirilmediği doğrulanabilmeli. perflab her
+// çalıştırmada:
+//
+//   - summary.json'a Provenance yazar: manifest dosyasının hash'i, ölçüm öncesi veri setinin
+//     checksum'ı (dbHash), script kaynaklarının hash'i ve git revizyonu, Go/sürücü sürümü
+//   - Çalıştırma klasöründeki her dosyanın SHA-256'sını artifacts.json'a yazar
+//   - -sign ile verilen Ed25519 anahtarıyla artifacts.json'ı imzalar
+//
+// `perflab verify runs/<deney>` dosyaları yeniden hash'ler ve imzayı kontrol eder. Anahtar
+// çifti `perflab keygen` ile üretilir (OpenSSL ile uyumlu PEM).
+
+// artifactsFile - Çalıştırma klasöründeki hash listesi (kendisi listede yoktur)
+const artifactsFile = "artifacts.json"
+
+// Provenance - Sonucun neyle üretildiği
+type Provenance struct {
+	ManifestSHA256  string `json:"manifestSha256"`            // Manifest dosyasının (maskelenmemiş) hash'i
+	DatasetChecksum string `json:"datasetChecksum,omitempty"` // dbHash md5: Ölçüm öncesi koleksiyonun içeriği
+	DatasetCount    int64  `json:"datasetCount,omitempty"`
+	SourceSHA256    string `json:"sourceSha256"`             // app/*.go: Script'ler go run ile bu kaynaktan derlenir
+	SourceRevision  string `json:"sourceRevision,omitempty"` // git HEAD, çalışma kopyası değişmişse "-dirty" ekli
+	GoVersion       string `json:"goVersion"`
+	DriverVersion   string `json:"driverVersion"`
+}
+
+// CollectProvenance - Manifest ve kaynak hash'leri, sürümler (veri seti checksum'ı ayrıca eklenir)
+func CollectProvenance(manifestPath string) (*Provenance, error) {
+	manifestHash, err := fileSHA256(manifestPath)
+	if err != nil {
+		return nil, err
+	}
+	sourceHash, err := sourceSHA256(".")
+	if err != nil {
+		return nil, err
+	}
+	return &Provenance{
+		ManifestSHA256: manifestHash,
+		SourceSHA256:   sourceHash,
+		SourceRevision: gitRevision(),
+		GoVersion:      runtime.Version(),
+		DriverVersion:  version.Driver,
+	}, nil
+}
+
+// DatasetChecksum - dbHash ile koleksiyonun içerik hash'i ve doküman sayısı
+// dbHash mongos'ta ve bazı yönetilen servislerde yoktur; hata dönerse checksum boş kalır
+func DatasetChecksum(ctx context.Context, col *mongo.Collection) (string, int64, error) {
+	var result struct {
+		Collections map[string]string `bson:"collections"`
+	}
+	cmd := bson.D{{Key: "dbHash", Value: 1}, {Key: "collections", Value: bson.A{col.Name()}}}
+	if err := col.Database().RunCommand(ctx, cmd).Decode(&result); err != nil {
+		return "", 0, fmt.Errorf("dbHash: %v", err)
+	}
+	count, err := col.EstimatedDocumentCount(ctx)
+	if err != nil {
+		return "", 0, err
+	}
+	return "md5:" + result.Collections[col.Name()], count, nil
+}
+
+// sourceSHA256 - dir'deki .go dosyalarının (ad + içerik, ada göre sıralı) hash'i
+func sourceSHA256(dir string) (string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return "", err
+	}
+	sort.Strings(files)
+	h := sha256.New()
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(h, "%s %d\n", filepath.Base(f), len(data))
+		h.Write(data)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// gitRevision - git yoksa veya repo değilse boş
+func gitRevision() string {
+	out, err := exec.Command("git", "rev-parse", "HEAD").Output()
+	if err != nil {
+		return ""
+	}
+	rev := strings.TrimSpace(string(out))
+	if status, err := exec.Command("git", "status", "--porcelain", "--", ".").Output(); err == nil && len(bytes.TrimSpace(status)) > 0 {
+		rev += "-dirty"
+	}
+	return rev
+}
+
+func fileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Artifact - Çalıştırma klasöründeki bir dosya
+type Artifact struct {
+	Path   string `json:"path"` // Klasöre göre, / ayraçlı
+	SHA256 string `json:"sha256"`
+	Size   int64  `json:"size"`
+}
+
+// ArtifactManifest - artifacts.json
+// İmza, Signature alanı boşken JSON'a (json.Marshal) çevrilmiş halin üzerindedir
+type ArtifactManifest struct {
+	Experiment string      `json:"experiment"`
+	CreatedAt  time.Time   `json:"createdAt"`
+	Provenance *Provenance `json:"provenance"`
+	Files      []Artifact  `json:"files"`
+	Signature  *Signature  `json:"signature,omitempty"`
+}
+
+// Signature - Ed25519 imzası
+type Signature struct {
+	Algorithm string `json:"algorithm"` // ed25519
+	PublicKey string `json:"publicKey"` // base64, doğrulamada güvenilen anahtarla karşılaştırılır
+	Value     string `json:"value"`     // base64
+}
+
+func (m *ArtifactManifest) signedPayload() ([]byte, error) {
+	unsigned := *m
+	unsigned.Signature = nil
+	return json.Marshal(unsigned)
+}
+
+// WriteArtifactManifest - runDir'deki dosyaları hash'ler ve artifacts.json'ı yazar (key nil: imzasız)
+// Çalıştırmanın en son adımıdır: Sonradan klasöre yazılan dosyalar doğrulamada "fazla" görünür
+func WriteArtifactManifest(runDir, experiment string, prov *Provenance, key ed25519.PrivateKey) (*ArtifactManifest, error) {
+	files, err := hashArtifacts(runDir)
+	if err != nil {
+		return nil, err
+	}
+	m := &ArtifactManifest{Experiment: experiment, CreatedAt: time.Now().UTC(), Provenance: prov, Files: files}
+	if key != nil {
+		payload, err := m.signedPayload()
+		if err != nil {
+			return nil, err
+		}
+		m.Signature = &Signature{
+			Algorithm: "ed25519",
+			PublicKey: base64.StdEncoding.EncodeToString(key.Public().(ed25519.PublicKey)),
+			Value:     base64.StdEncoding.EncodeToString(ed25519.Sign(key, payload)),
+		}
+	}
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return m, os.WriteFile(filepath.Join(runDir, artifactsFile), data, 0644)
+}
+
+// hashArtifacts - Klasördeki tüm dosyalar (alt klasörler dahil, artifacts.json hariç), yola göre sıralı
+func hashArtifacts(runDir string) ([]Artifact, error) {
+	var files []Artifact
+	err := filepath.WalkDir(runDir, func(path string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(runDir, path)
+		if err != nil || rel == artifactsFile {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		sum, err := fileSHA256(path)
+		if err != nil {
+			return err
+		}
+		files = append(files, Artifact{Path: filepath.ToSlash(rel), SHA256: sum, Size: info.Size()})
+		return nil
+	})
+	return files, err
+}
+
+// VerifyArtifacts - artifacts.json'daki hash'leri ve imzayı kontrol eder
+// trusted verilirse imza o anahtarla atılmış olmalıdır; verilmezse yalnızca dosyadaki anahtarla
+// tutarlılık kontrol edilir (dosyayı değiştiren kişi yeniden imzalamış olabilir)
+// Döndürür: Bulunan sorunlar (boş = doğrulandı)
+func VerifyArtifacts(runDir string, trusted ed25519.PublicKey) (*ArtifactManifest, []string, error) {
+	data, err := os.ReadFile(filepath.Join(runDir, artifactsFile))
+	if err != nil {
+		return nil, nil, err
+	}
+	var m ArtifactManifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, nil, fmt.Errorf("%s: %v", artifactsFile, err)
+	}
+
+	var problems []string
+	current, err := hashArtifacts(runDir)
+	if err != nil {
+		return nil, nil, err
+	}
+	byPath := map[string]Artifact{}
+	for _, a := range current {
+		byPath[a.Path] = a
+	}
+	for _, want := range m.Files {
+		got, ok := byPath[want.Path]
+		switch {
+		case !ok:
+			problems = append(problems, fmt.Sprintf("%s: dosya yok", want.Path))
+		case got.SHA256 != want.SHA256:
+			problems = append(problems, fmt.Sprintf("%s: içerik değişmiş", want.Path))
+		}
+		delete(byPath, want.Path)
+	}
+	for path := range byPath {
+		problems = append(problems, fmt.Sprintf("%s: listede olmayan dosya", path))
+	}
+
+	switch {
+	case m.Signature == nil && trusted != nil:
+		problems = append(problems, "imza yok")
+	case m.Signature != nil:
+		pub, err1 := base64.StdEncoding.DecodeString(m.Signature.PublicKey)
+		sig, err2 := base64.StdEncoding.DecodeString(m.Signature.Value)
+		payload, err3 := m.signedPayload()
+		switch {
+		case err1 != nil || err2 != nil || err3 != nil || m.Signature.Algorithm != "ed25519" || len(pub) != ed25519.PublicKeySize:
+			problems = append(problems, "imza okunamadı")
+		case !ed25519.Verify(pub, payload, sig):
+			problems = append(problems, "imza geçersiz (artifacts.json değiştirilmiş)")
+		case trusted != nil && !bytes.Equal(pub, trusted):
+			problems = append(problems, "imza güvenilen anahtarla atılmamış")
+		}
+	}
+	sort.Strings(problems)
+	return &m, problems, nil
+}
+
+// GenerateKeyPair - <prefix>.key (özel, 0600) ve <prefix>.pub dosyalarını yazar
+func GenerateKeyPair(prefix string) error {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return err
+	}
+	privDER, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		return err
+	}
+	pubDER, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(prefix+".key", pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privDER}), 0600); err != nil {
+		return err
+	}
+	return os.WriteFile(prefix+".pub", pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER}), 0644)
+}
+
+// LoadSigningKey - PEM (PKCS#8) Ed25519 özel anahtarı
+func LoadSigningKey(path string) (ed25519.PrivateKey, error) {
+	der, err := readPEM(path, "PRIVATE KEY")
+	if err != nil {
+		return nil, err
+	}
+	key, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	priv, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("%s: Ed25519 anahtarı değil", path)
+	}
+	return priv, nil
+}
+
+// LoadPublicKey - PEM (PKIX) Ed25519 açık anahtarı
+func LoadPublicKey(path string) (ed25519.PublicKey, error) {
+	der, err := readPEM(path, "PUBLIC KEY")
+	if err != nil {
+		return nil, err
+	}
+	key, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	pub, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("%s: Ed25519 anahtarı değil", path)
+	}
+	return pub, nil
+}
+
+func readPEM(path, blockType string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != blockType {
+		return nil, fmt.Errorf("%s: %s PEM bloğu bulunamadı", path, blockType)
+	}
+	return block.Bytes, nil
+}
@@ -3,9 +3,9 @@ package main
 import (
 	"context"
 	"fmt"
-	"runtime"
 	"time"
 
+	"benchkit"
 	"go.mongodb.org/mongo-driver/bson"
 )
 
@@ -19,11 +19,10 @@ func main() {
 		fmt.Printf("Logger oluşturulamadı: %v\n", err)
 		return
 	}
-	defer logger.Close()
-	
+	defer CloseLogger(logger)
 
 	logger.WriteHeader("read_bad - KÖTÜ YÖNTEM (Baseline)")
-	
+
 	col := GetMongo()
 	ctx := context.Background()
 
@@ -37,55 +36,64 @@ func main() {
 		PrintExplainResults(explainResult, "read_bad (KÖTÜ YÖNTEM)", logger)
 	}
 
-	// Performans ölçümü başlat
-	start := time.Now()
-	
-	// Bellek kullanımını ölçmek için başlangıç durumunu al
-	var memBefore runtime.MemStats
-	runtime.GC() // Garbage collection yap ki ölçüm doğru olsun 
-	// (erişilmeyen, kullanılmayan nesneleri değişkenleri bellekten sileriz bu şekilde memory leak önune geçmiş oluruz)
-	runtime.ReadMemStats(&memBefore)
+	// Performans ölçümü: -iterations ile tekrarlanır, ısınma turları atılır (bkz. iterations.go)
+	var results []interface{}
+	var duration time.Duration
+	var mem benchkit.MemUsage
+	var phases *CursorPhases
+	iterations := NewIterations(logger)
+	for iterations.Next() {
+		results = nil // Önceki turun sonuçları ölçüm başındaki GC'de toplansın
+		start := time.Now()
 
+		// Bellek kullanımını ölçmek için başlangıç durumunu al
+		// GC yapar; ölçüm boyunca heap zirvesi, ayırmalar ve GC sayılır (bkz. benchkit.MemUsage)
+		memProbe := benchkit.StartMemProbe()
+		ResetCursorPhases() // explain/count komutları aşama sürelerine karışmasın
 
-	// Find: TÜM kayıtları bul (filtre yok)
-	cursor, err := col.Find(ctx, bson.M{}) // Boş filter = tüm kayıtlar
-	if err != nil {
-		panic(err)
-	}
+		// Find: TÜM kayıtları bul (filtre yok)
+		cursor, err := col.Find(ctx, bson.M{}) // Boş filter = tüm kayıtlar
+		if HandleError(logger, "find", err) {
+			// Sorgu açılamadı: ölçülecek bir şey yok, hata özetini yazıp çık
+			PrintErrorSummary(logger)
+			return
+		}
 
-	//  KÖTÜ YÖNTEM: cursor.All() - Tüm sonuçları bir kerede memory'ye yükle
-	// Bu, 1 milyon kayıt için çok fazla bellek kullanır
-	// Tüm kayıtlar memory'de bekler, bu da:
-	// 1. Yüksek bellek kullanımı
-	// 2. Yavaş başlangıç (tüm veri gelene kadar bekler)
-	// 3. Network buffer overflow riski
-	var results []interface{}
-	if err := cursor.All(ctx, &results); err != nil {
-		panic(err)
-	}
+		//  KÖTÜ YÖNTEM: cursor.All() - Tüm sonuçları bir kerede memory'ye yükle
+		// Bu, 1 milyon kayıt için çok fazla bellek kullanır
+		// Tüm kayıtlar memory'de bekler, bu da:
+		// 1. Yüksek bellek kullanımı
+		// 2. Yavaş başlangıç (tüm veri gelene kadar bekler)
+		// 3. Network buffer overflow riski
+		// Kısmi sonuçlarla devam edilir, hata özette raporlanır
+		HandleError(logger, "cursor.All", cursor.All(ctx, &results))
 
-	// Bellek kullanımını ölçmek için bitiş durumunu al
-	var memAfter runtime.MemStats
-	runtime.ReadMemStats(&memAfter)
-	memoryUsed := int64(memAfter.Alloc - memBefore.Alloc)
+		// Bellek kullanımını ölçmek için bitiş durumunu al
+		mem = memProbe.Stop()
 
-	duration := time.Since(start)
+		duration = time.Since(start)
+		// cursor.All decode işlemini kendi içinde yapar, bu yüzden decode süresi ayrı ölçülemez
+		phases = SnapshotCursorPhases(0)
+		iterations.Record(duration, mem.PeakGrowth)
+	}
 
 	// Sonuçları göster
 	logger.Printf("\n❌ KÖTÜ YÖNTEM SONUÇLARI:\n")
 	logger.Printf("📦 Okunan Kayıt: %d\n", len(results))
 	logger.Printf("⏱️  Süre: %v\n", duration)
-	logger.Printf("💾 Bellek Kullanımı: %.2f MB\n", float64(memoryUsed)/(1024*1024))
-	
+	logger.Printf("💾 Bellek Kullanımı: %.2f MB\n", float64(mem.PeakGrowth)/(1024*1024))
+
 	// Execution stats'i parse et ve göster
 	if explainResult != nil {
 		if execStats, ok := explainResult["executionStats"].(map[string]interface{}); ok {
 			metrics := QueryMetrics{
 				Duration:    duration,
 				RecordsRead: len(results),
-				MemoryUsed:  memoryUsed,
+				Phases:      phases,
+				Iterations:  iterations.Stats(),
 			}
-			
+			metrics.SetMemory(mem)
+
 			// Execution stats'i parse et
 			if execTime, ok := execStats["executionTimeMillis"].(int64); ok {
 				metrics.ExecutionStats = &ExecutionStats{
@@ -110,10 +118,10 @@ func main() {
 				}
 				metrics.ExecutionStats.NReturned = nReturned
 			}
-			
+
 			PrintMetrics(metrics, "read_bad", logger)
 		}
 	}
-	
+
 	logger.Println("\n✅ Test tamamlandı! Sonuçlar 'read_bad_results.txt' dosyasına kaydedildi.")
 }
@@ -0,0 +1,230 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"reflect"
+	"runtime"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/bsonrw"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// read_codec.go - Özel BSON codec registry ile decode hızlandırma
+// Önceki versiyonlarda decode hep bson.M'e yapılıyordu. bson.M her doküman için
+// map + interface{} değerler oluşturur: çok allocation, çok GC.
+// Bu script aynı sorguyu (tam doküman, projection yok) 4 farklı decode yöntemiyle okur:
+// 1. bson.M (varsayılan registry) - baseline
+// 2. Struct (varsayılan registry) - reflection tabanlı struct codec, kullanılmayan alanları atlar
+// 3. Struct (özel registry) - orderCompact için elle yazılmış decoder, reflection yok
+// 4. Raw lookup - decode yok, cursor.Current üzerinden doğrudan alan okuma
+//
+// KULLANIM:
+//   go run main.go config.go analyzer.go antipattern.go errors.go logger.go samples.go sink.go iterations.go read_codec.go
+//   go run main.go config.go analyzer.go antipattern.go errors.go logger.go samples.go sink.go iterations.go read_codec.go -limit 0   (tüm koleksiyon)
+
+// orderCompact - Sadece ihtiyaç duyulan alanları taşıyan küçük struct
+// items ve createdAt gibi büyük alanlar decode edilmez
+type orderCompact struct {
+	UserID primitive.ObjectID `bson:"userId"`
+	Status string             `bson:"status"`
+	Total  int64              `bson:"total"`
+}
+
+var orderCompactType = reflect.TypeOf(orderCompact{})
+
+// decodeOrderCompact - orderCompact için özel ValueDecoder
+// Dokümanı eleman eleman okur, tanımadığı alanları Skip() ile byte seviyesinde atlar.
+// Varsayılan struct codec'in aksine alan eşleştirmesi için reflection kullanmaz.
+func decodeOrderCompact(_ bsoncodec.DecodeContext, vr bsonrw.ValueReader, val reflect.Value) error {
+	if !val.CanSet() || val.Type() != orderCompactType {
+		return bsoncodec.ValueDecoderError{Name: "decodeOrderCompact", Types: []reflect.Type{orderCompactType}, Received: val}
+	}
+
+	dr, err := vr.ReadDocument()
+	if err != nil {
+		return err
+	}
+
+	var out orderCompact
+	for {
+		key, evr, err := dr.ReadElement()
+		if errors.Is(err, bsonrw.ErrEOD) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		switch key {
+		case "userId":
+			out.UserID, err = evr.ReadObjectID()
+		case "status":
+			out.Status, err = evr.ReadString()
+		case "total":
+			// Go int, değere göre int32 veya int64 olarak yazılmış olabilir
+			switch evr.Type() {
+			case bsontype.Int32:
+				var v int32
+				v, err = evr.ReadInt32()
+				out.Total = int64(v)
+			case bsontype.Int64:
+				out.Total, err = evr.ReadInt64()
+			case bsontype.Double:
+				var v float64
+				v, err = evr.ReadDouble()
+				out.Total = int64(v)
+			default:
+				err = evr.Skip()
+			}
+		default:
+			// items, createdAt, _id: hiç decode etmeden atla
+			err = evr.Skip()
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	val.Set(reflect.ValueOf(out))
+	return nil
+}
+
+// codecResult - Tek bir decode yönteminin sonucu
+type codecResult struct {
+	name       string
+	records    int
+	duration   time.Duration
+	decodeTime time.Duration
+	allocBytes uint64
+	numGC      uint32
+}
+
+func main() {
+	limit := flag.Int64("limit", 200000, "Okunacak doküman sayısı (0 = hepsi)")
+	flag.Parse()
+
+	logger, err := NewLogger("read_codec_results.txt")
+	if err != nil {
+		fmt.Printf("Logger oluşturulamadı: %v\n", err)
+		return
+	}
+	defer CloseLogger(logger)
+
+	logger.WriteHeader("read_codec - Özel BSON Codec Registry")
+
+	col := GetMongo()
+	ctx := context.Background()
+
+	// Özel registry: Varsayılan registry'nin tüm codec'leri + orderCompact için özel decoder
+	registry := bson.NewRegistry()
+	registry.RegisterTypeDecoder(orderCompactType, bsoncodec.ValueDecoderFunc(decodeOrderCompact))
+	customCol := col.Database().Collection(col.Name(), options.Collection().SetRegistry(registry))
+
+	findOpts := options.Find().SetBatchSize(1000)
+	if *limit > 0 {
+		findOpts.SetLimit(*limit)
+	}
+	logger.Printf("📋 Okunacak doküman: %d (0 = hepsi), projection yok (tam doküman)\n", *limit)
+
+	// İlk yöntem soğuk cache'in maliyetini tek başına ödemesin
+	WarmUp(logger, "tam okuma", FindPass(col, bson.M{}, findOpts))
+
+	// run - Aynı sorguyu verilen koleksiyon ve decode fonksiyonuyla çalıştırır
+	run := func(name string, c *mongo.Collection, decode func(cursor *mongo.Cursor) error) codecResult {
+		logger.Printf("\n▶️  %s...\n", name)
+
+		var memBefore, memAfter runtime.MemStats
+		runtime.GC()
+		runtime.ReadMemStats(&memBefore)
+
+		start := time.Now()
+		cursor, err := c.Find(ctx, bson.M{}, findOpts)
+		if HandleError(logger, "find", err) {
+			return codecResult{name: name}
+		}
+		defer cursor.Close(ctx)
+
+		res := codecResult{name: name}
+		for cursor.Next(ctx) {
+			decodeStart := time.Now()
+			if err := decode(cursor); HandleError(logger, "decode", err) {
+				continue
+			}
+			res.decodeTime += time.Since(decodeStart)
+			res.records++
+		}
+		HandleError(logger, "cursor", cursor.Err())
+		res.duration = time.Since(start)
+
+		// TotalAlloc monoton artar - GC çalışsa bile negatif olmaz
+		runtime.ReadMemStats(&memAfter)
+		res.allocBytes = memAfter.TotalAlloc - memBefore.TotalAlloc
+		res.numGC = memAfter.NumGC - memBefore.NumGC
+
+		logger.Printf("  📦 %d kayıt, toplam %v, decode %v, %.1f MB allocation, %d GC\n",
+			res.records, res.duration, res.decodeTime, float64(res.allocBytes)/(1024*1024), res.numGC)
+		return res
+	}
+
+	var sink int64 // Derleyicinin decode sonuçlarını "kullanılmıyor" diye elemesini önler
+
+	results := []codecResult{
+		run("bson.M (varsayılan registry)", col, func(cursor *mongo.Cursor) error {
+			var doc bson.M
+			err := cursor.Decode(&doc)
+			sink += int64(len(doc))
+			return err
+		}),
+		run("struct (varsayılan registry)", col, func(cursor *mongo.Cursor) error {
+			var order orderCompact
+			err := cursor.Decode(&order)
+			sink += order.Total
+			return err
+		}),
+		run("struct (özel registry)", customCol, func(cursor *mongo.Cursor) error {
+			var order orderCompact
+			err := cursor.Decode(&order)
+			sink += order.Total
+			return err
+		}),
+		run("raw lookup (decode yok)", col, func(cursor *mongo.Cursor) error {
+			// cursor.Current, batch buffer'ındaki ham BSON'dur - kopyalama yapılmaz
+			total, ok := cursor.Current.Lookup("total").AsInt64OK()
+			if ok {
+				sink += total
+			}
+			_ = cursor.Current.Lookup("status").StringValue()
+			return nil
+		}),
+	}
+	_ = sink
+
+	// Karşılaştırma tablosu - baseline bson.M
+	base := results[0]
+	logger.Println("\n=== KARŞILAŞTIRMA ===")
+	logger.Printf("  %-30s %12s %12s %10s %10s %6s\n", "yöntem", "toplam", "decode", "decode x", "alloc MB", "GC")
+	for _, r := range results {
+		speedup := 0.0
+		if r.decodeTime > 0 {
+			speedup = float64(base.decodeTime) / float64(r.decodeTime)
+		}
+		logger.Printf("  %-30s %12v %12v %9.1fx %10.1f %6d\n",
+			r.name, r.duration.Round(time.Millisecond), r.decodeTime.Round(time.Millisecond),
+			speedup, float64(r.allocBytes)/(1024*1024), r.numGC)
+	}
+	logger.Println("\n💡 Decode süresi toplam sürenin küçük bir kısmıysa darboğaz network/sunucudadır,")
+	logger.Println("   codec optimizasyonu toplam süreyi fazla değiştirmez.")
+
+	PrintErrorSummary(logger)
+
+	logger.Println("\n✅ Test tamamlandı! Sonuçlar 'read_codec_results.txt' dosyasına kaydedildi.")
+}
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// read_daterange.go - createdAt zaman aralığı sorguları (son 1 saat / 1 gün / 1 hafta)
+// "Son X zamandaki siparişler" en sık yazılan sorgulardan biri, tarih saklama şekli ise
+// en sık düşülen tuzaklardan biri:
+// - ISODate (BSON Date): 8 byte, sıralanabilir, index'lenebilir, $gte/$lt doğru çalışır
+// - String tarih: ~20 byte + uzunluk, index daha büyük. Sadece sabit formatta (aynı zone,
+//   sıfır dolgulu) doğru sıralanır. Date değeriyle sorgulanırsa HİÇBİR şey döndürmez
+//   (BSON tip sıralaması: string ve date farklı tip aralıklarıdır) - hata da vermez!
+//
+// Her aralık dört şekilde ölçülür: {ISODate, string} × {index yok, createdAt_1 index}
+// String veri seti generator ile oluşturulur:
+//   go run main.go config.go analyzer.go antipattern.go errors.go logger.go samples.go sink.go dataset.go orders.go generator.go -date-format string -collection orders_strdate -drop
+// Yoksa bu script orders koleksiyonundan $out ile türetir.
+//
+// Aralıklar, veri setindeki en yeni createdAt'e göre hesaplanır (veri ne zaman üretilmiş olursa olsun
+// "son 1 saat" dolu olsun diye).
+//
+// KULLANIM:
+//   go run main.go config.go analyzer.go antipattern.go errors.go logger.go samples.go sink.go dataset.go orders.go iterations.go read_daterange.go
+//   go run main.go config.go analyzer.go antipattern.go errors.go logger.go samples.go sink.go dataset.go orders.go iterations.go read_daterange.go -string-collection orders_strdate -runs 10
+
+// dateRangeCase - Tek bir (veri seti, index, aralık) ölçümü
+type dateRangeCase struct {
+	storage  string
+	indexed  bool
+	window   string
+	records  int
+	duration time.Duration // Tekrarların medyanı
+	plan     PlanSummary
+}
+
+func main() {
+	stringCollection := flag.String("string-collection", "orders_strdate", "createdAt'i string olarak saklayan collection")
+	runs := flag.Int("runs", 5, "Her ölçüm için tekrar sayısı (medyan alınır)")
+	flag.Parse()
+
+	logger, err := NewLogger("read_daterange_results.txt")
+	if err != nil {
+		fmt.Printf("Logger oluşturulamadı: %v\n", err)
+		return
+	}
+	defer CloseLogger(logger)
+
+	logger.WriteHeader("read_daterange - Tarih Aralığı Sorguları (ISODate vs String)")
+
+	dateCol := GetMongo()
+	strCol := dateCol.Database().Collection(*stringCollection)
+	ctx := context.Background()
+
+	// String veri seti yoksa ISODate koleksiyonundan türet
+	if n, _ := strCol.EstimatedDocumentCount(ctx); n == 0 {
+		logger.Printf("⚙️  %s boş - %s'dan $dateToString ile türetiliyor...\n", strCol.Name(), dateCol.Name())
+		start := time.Now()
+		cursor, err := dateCol.Aggregate(ctx, mongo.Pipeline{
+			{{Key: "$set", Value: bson.M{"createdAt": bson.M{"$dateToString": bson.M{
+				"format": "%Y-%m-%dT%H:%M:%SZ", "date": "$createdAt", "timezone": "UTC",
+			}}}}},
+			{{Key: "$out", Value: strCol.Name()}},
+		}, options.Aggregate().SetAllowDiskUse(true))
+		if err != nil {
+			logger.Printf("❌ String veri seti oluşturulamadı: %v\n", err)
+			return
+		}
+		cursor.Close(ctx)
+		logger.Printf("  ✅ %v\n", time.Since(start).Round(time.Millisecond))
+	}
+
+	// En yeni createdAt: aralıkların bitiş noktası
+	var latest struct {
+		CreatedAt time.Time `bson:"createdAt"`
+	}
+	err = dateCol.FindOne(ctx, bson.M{"createdAt": bson.M{"$type": "date"}},
+		options.FindOne().SetSort(bson.D{{Key: "createdAt", Value: -1}}).SetProjection(bson.M{"createdAt": 1})).Decode(&latest)
+	if err != nil {
+		logger.Printf("❌ En yeni createdAt bulunamadı: %v\n", err)
+		return
+	}
+	anchor := latest.CreatedAt.UTC()
+	logger.Printf("📅 Referans zaman (en yeni sipariş): %s\n", anchor.Format(time.RFC3339))
+
+	windows := []struct {
+		name     string
+		duration time.Duration
+	}{
+		{"son 1 saat", time.Hour},
+		{"son 1 gün", 24 * time.Hour},
+		{"son 1 hafta", 7 * 24 * time.Hour},
+	}
+
+	datasets := []struct {
+		storage string
+		col     *mongo.Collection
+		// bound - Aralık sınırını koleksiyondaki tipe göre hazırlar
+		bound func(t time.Time) interface{}
+	}{
+		{"ISODate", dateCol, func(t time.Time) interface{} { return t }},
+		{"string", strCol, func(t time.Time) interface{} { return t.Format(orderDateLayout) }},
+	}
+
+	var cases []dateRangeCase
+	for _, ds := range datasets {
+		for _, indexed := range []bool{false, true} {
+			if indexed {
+				if _, err := ds.col.Indexes().CreateOne(ctx, mongo.IndexModel{
+					Keys:    bson.D{{Key: "createdAt", Value: 1}},
+					Options: options.Index().SetName("createdAt_1"),
+				}); err != nil {
+					logger.Printf("❌ %s createdAt_1 index'i oluşturulamadı: %v\n", ds.col.Name(), err)
+					return
+				}
+			}
+
+			for _, w := range windows {
+				filter := bson.M{"createdAt": bson.M{
+					"$gt":  ds.bound(anchor.Add(-w.duration)),
+					"$lte": ds.bound(anchor),
+				}}
+				findOpts := options.Find().SetProjection(bson.M{"_id": 1})
+				if indexed {
+					findOpts.SetHint("createdAt_1")
+				} else {
+					findOpts.SetHint(bson.D{{Key: "$natural", Value: 1}})
+				}
+
+				c := dateRangeCase{storage: ds.storage, indexed: indexed, window: w.name}
+				if explainResult, err := ExplainQuery(ds.col, filter, findOpts); err == nil {
+					c.plan = SummarizeExplain(explainResult)
+					CheckExplain(explainResult, fmt.Sprintf("read_daterange: %s, index=%v, %s", ds.storage, indexed, w.name))
+				}
+
+				WarmUp(logger, fmt.Sprintf("%s, index=%v, %s", ds.storage, indexed, w.name), FindPass(ds.col, filter, findOpts))
+				var durations []time.Duration
+				for i := 0; i < *runs; i++ {
+					start := time.Now()
+					cursor, err := ds.col.Find(ctx, filter, findOpts)
+					if HandleError(logger, "find", err) {
+						continue
+					}
+					n := 0
+					for cursor.Next(ctx) {
+						n++
+					}
+					HandleError(logger, "cursor", cursor.Err())
+					cursor.Close(ctx)
+					durations = append(durations, time.Since(start))
+					c.records = n
+				}
+				sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+				c.duration = Percentile(durations, 50)
+				cases = append(cases, c)
+			}
+		}
+	}
+
+	logger.Println("\n=== SONUÇLAR (medyan) ===")
+	logger.Printf("  %-8s %-8s %-12s %10s %12s %12s %12s %-20s\n",
+		"saklama", "index", "aralık", "kayıt", "süre", "key", "doküman", "plan")
+	for _, c := range cases {
+		index := "yok"
+		if c.indexed {
+			index = "var"
+		}
+		logger.Printf("  %-8s %-8s %-12s %10d %12v %12d %12d %-20s\n",
+			c.storage, index, c.window, c.records, c.duration.Round(time.Microsecond),
+			c.plan.KeysExamined, c.plan.DocsExamined, c.plan.StageChain())
+	}
+
+	// Depolama: String tarih hem dokümanı hem de index'i büyütür
+	logger.Println("\n=== DEPOLAMA ===")
+	for _, ds := range datasets {
+		stats, err := CollectStorageStats(ctx, ds.col)
+		if err != nil {
+			logger.Printf("  ⚠️  %s: %v\n", ds.col.Name(), err)
+			continue
+		}
+		logger.Printf("  %-8s ortalama doküman %d byte, createdAt_1 index %.2f MB\n",
+			ds.storage, stats.AvgObjSize, float64(stats.IndexSizes["createdAt_1"])/(1024*1024))
+	}
+
+	// Tuzak: String alanı Date değeriyle sorgulamak hata vermez, sadece boş sonuç döner
+	wrongType, err := strCol.CountDocuments(ctx, bson.M{"createdAt": bson.M{"$gt": anchor.Add(-24 * time.Hour)}})
+	if err == nil {
+		logger.Printf("\n🪤 Tuzak: %s koleksiyonunda createdAt > ISODate(...) sorgusu %d kayıt döndürdü", strCol.Name(), wrongType)
+		if wrongType == 0 {
+			logger.Printf(" - tip uyuşmazlığı hata değil, boş sonuç üretir!\n")
+		} else {
+			logger.Printf("\n")
+		}
+	}
+
+	PrintAntiPatterns(logger)
+	PrintErrorSummary(logger)
+
+	logger.Println("\n✅ Test tamamlandı! Sonuçlar 'read_daterange_results.txt' dosyasına kaydedildi.")
+}
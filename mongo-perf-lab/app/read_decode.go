@@ -0,0 +1,282 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// read_decode.go - Decode paralelliği: Tek cursor, N decode goroutine'i
+// 1M dokümanda okuma yavaşsa üç şüpheli var: sunucu (sorgu), network (transfer) ve client
+// (BSON → bson.M decode). read_v4 paralelliği cursor seviyesinde yapar; bu durumda sunucu,
+// network ve decode birlikte paralelleşir ve hangisinin darboğaz olduğu görünmez.
+// Bu script network okumasını tek cursor'da sabit tutar, sadece decode'u paralelleştirir:
+// cursor ham BSON'u (cursor.Current kopyası) parça parça kanala yazar, N worker decode eder.
+//
+// Çalıştırmalar:
+// 1. raw    - Decode yok, sadece kopyalama: sunucu + network'ün alt sınırı
+// 2. inline - Decode cursor döngüsünde (read_v1 gibi): network ve decode sırayla
+// 3. wN     - N decode worker'ı (-workers ile tarama)
+//
+// Yorum: inline ≈ raw ise decode darboğaz değildir. wN worker sayısıyla raw'a yaklaşıyorsa
+// darboğaz client decode'dur. Cursor'ın kanala yazarken beklediği süre (backpressure) de
+// worker'ların yetişemediğini gösterir.
+//
+// KULLANIM:
+//   go run main.go config.go analyzer.go antipattern.go errors.go logger.go samples.go sink.go flags.go iterations.go read_decode.go
+//   go run main.go config.go analyzer.go antipattern.go errors.go logger.go samples.go sink.go flags.go iterations.go read_decode.go -limit 0 -workers 1,2,4,8,16
+
+// decodeRun - Tek bir çalıştırmanın sonucu
+type decodeRun struct {
+	name       string
+	variant    string // Metrik kaydındaki varyant: raw, inline, wN
+	workers    int    // 0 = decode cursor döngüsünde (raw, inline)
+	records    int64
+	duration   time.Duration
+	decodeCPU  time.Duration // Worker'ların decode içinde geçirdiği toplam süre
+	blocked    time.Duration // Cursor'ın kanala yazarken beklediği süre (worker'lar yetişemedi)
+	allocBytes uint64
+	phases     *CursorPhases
+}
+
+// commandTime - Sunucu + network (find + getMore komutlarının süresi)
+func (r decodeRun) commandTime() time.Duration {
+	if r.phases == nil {
+		return 0
+	}
+	return r.phases.FirstBatch + r.phases.GetMoreTotal()
+}
+
+func main() {
+	limit := flag.Int64("limit", 1000000, "Okunacak doküman sayısı (0 = hepsi)")
+	workerList := flag.String("workers", "1,2,4,8", "Decode worker sayıları (tarama)")
+	chunk := flag.Int("chunk", 256, "Kanala tek seferde gönderilen doküman sayısı")
+	batchSize := flag.Int("batch", 1000, "Cursor batch size")
+	flag.Parse()
+
+	workerCounts, err := parseIntList(*workerList)
+	if err != nil {
+		fmt.Printf("Geçersiz -workers: %v\n", err)
+		return
+	}
+	if *chunk <= 0 || *batchSize <= 0 {
+		fmt.Println("❌ -chunk ve -batch pozitif olmalı")
+		return
+	}
+
+	logger, err := NewLogger("read_decode_results.txt")
+	if err != nil {
+		fmt.Printf("Logger oluşturulamadı: %v\n", err)
+		return
+	}
+	defer CloseLogger(logger)
+
+	logger.WriteHeader("read_decode - Decode Paralelliği (Tek Cursor, N Worker)")
+
+	col := GetMongo()
+	ctx := context.Background()
+
+	// Sunucu süresi için explain (tüm koleksiyon taraması; limit'li çalıştırmalarda üst sınırdır)
+	var serverTime time.Duration
+	logger.Println("🔍 Sorgu analizi yapılıyor (explain)...")
+	if explainResult, err := ExplainQuery(col, bson.M{}); err != nil {
+		logger.Printf("⚠️  Explain hatası: %v\n", err)
+	} else if execStats, ok := explainResult["executionStats"].(map[string]interface{}); ok {
+		serverTime = time.Duration(toInt64(execStats["executionTimeMillis"])) * time.Millisecond
+		logger.Printf("  🖥️  Sunucu süresi (explain): %v\n", serverTime)
+	}
+
+	findOpts := options.Find().SetBatchSize(int32(*batchSize))
+	if *limit > 0 {
+		findOpts.SetLimit(*limit)
+	}
+	logger.Printf("📋 Okunacak doküman: %d (0 = hepsi), batch %d, kanal parçası %d doküman, GOMAXPROCS %d\n",
+		*limit, *batchSize, *chunk, runtime.GOMAXPROCS(0))
+
+	// run - Tek cursor açar; decode nil ise ham dokümanlar sadece kopyalanır.
+	// workers == 0: decode cursor döngüsünde, > 0: kopyalanan dokümanlar worker'lara dağıtılır
+	var copied int // Kopyaların derleyici tarafından elenmemesi için
+	run := func(name, variant string, workers int, decode func(raw bson.Raw) error) decodeRun {
+		logger.Printf("\n▶️  %s...\n", name)
+		res := decodeRun{name: name, variant: variant, workers: workers}
+
+		var memBefore, memAfter runtime.MemStats
+		runtime.GC()
+		runtime.ReadMemStats(&memBefore)
+		ResetCursorPhases()
+
+		var decodeNanos int64
+		var wg sync.WaitGroup
+		chunks := make(chan []bson.Raw, workers*2)
+		for w := 0; w < workers; w++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				var local time.Duration
+				for docs := range chunks {
+					decodeStart := time.Now()
+					for _, raw := range docs {
+						HandleError(logger, "decode", decode(raw))
+					}
+					local += time.Since(decodeStart)
+				}
+				atomic.AddInt64(&decodeNanos, int64(local))
+			}()
+		}
+
+		start := time.Now()
+		cursor, err := col.Find(ctx, bson.M{}, findOpts)
+		if HandleError(logger, "find", err) {
+			close(chunks)
+			wg.Wait()
+			return res
+		}
+
+		pending := make([]bson.Raw, 0, *chunk)
+		send := func() {
+			sendStart := time.Now()
+			chunks <- pending
+			res.blocked += time.Since(sendStart)
+			pending = make([]bson.Raw, 0, *chunk)
+		}
+		for cursor.Next(ctx) {
+			res.records++
+			if workers == 0 {
+				if decode == nil {
+					// cursor.Current bir sonraki Next'te üzerine yazılır: worker'lı çalıştırmalarla
+					// aynı işi yapmak için kopyalanır
+					copied += len(append(bson.Raw(nil), cursor.Current...))
+					continue
+				}
+				decodeStart := time.Now()
+				if err := decode(cursor.Current); HandleError(logger, "decode", err) {
+					continue
+				}
+				decodeNanos += int64(time.Since(decodeStart))
+				continue
+			}
+			pending = append(pending, append(bson.Raw(nil), cursor.Current...))
+			if len(pending) == *chunk {
+				send()
+			}
+		}
+		HandleError(logger, "cursor", cursor.Err())
+		cursor.Close(ctx)
+		if len(pending) > 0 {
+			send()
+		}
+		close(chunks)
+		wg.Wait()
+		res.duration = time.Since(start)
+
+		res.decodeCPU = time.Duration(decodeNanos)
+		res.phases = SnapshotCursorPhases(res.decodeCPU)
+		runtime.ReadMemStats(&memAfter)
+		res.allocBytes = memAfter.TotalAlloc - memBefore.TotalAlloc
+
+		logger.Printf("  📦 %d kayıt, toplam %v, sunucu+network %v, decode %v (worker toplamı), bekleme %v, %.1f MB allocation\n",
+			res.records, res.duration.Round(time.Millisecond), res.commandTime().Round(time.Millisecond),
+			res.decodeCPU.Round(time.Millisecond), res.blocked.Round(time.Millisecond), float64(res.allocBytes)/(1024*1024))
+		writeDecodeRecord(res, serverTime, logger)
+		return res
+	}
+
+	// decodeM - Script'lerin çoğunda olduğu gibi bson.M'e decode (read_codec'teki baseline)
+	decodeM := func(raw bson.Raw) error {
+		var doc bson.M
+		return bson.Unmarshal(raw, &doc)
+	}
+
+	// İlk çalıştırma (raw) soğuk cache'in maliyetini tek başına ödemesin: raw'a göre oranlar bozulur
+	WarmUp(logger, "tam okuma", FindPass(col, bson.M{}, findOpts))
+
+	results := []decodeRun{
+		run("raw (decode yok)", "raw", 0, nil),
+		run("inline decode", "inline", 0, decodeM),
+	}
+	for _, w := range workerCounts {
+		results = append(results, run(fmt.Sprintf("%d decode worker", w), fmt.Sprintf("w%d", w), w, decodeM))
+	}
+	_ = copied
+
+	raw, inline := results[0], results[1]
+	logger.Println("\n=== KARŞILAŞTIRMA ===")
+	logger.Printf("  %-20s %12s %16s %12s %12s %10s %10s\n", "çalıştırma", "toplam", "sunucu+network", "decode", "bekleme", "raw'a göre", "alloc MB")
+	best := inline
+	for _, r := range results {
+		ratio := 0.0
+		if raw.duration > 0 {
+			ratio = float64(r.duration) / float64(raw.duration)
+		}
+		logger.Printf("  %-20s %12v %16v %12v %12v %9.2fx %10.1f\n",
+			r.name, r.duration.Round(time.Millisecond), r.commandTime().Round(time.Millisecond),
+			r.decodeCPU.Round(time.Millisecond), r.blocked.Round(time.Millisecond), ratio, float64(r.allocBytes)/(1024*1024))
+		if r.workers > 0 && r.records > 0 && r.duration < best.duration {
+			best = r
+		}
+	}
+
+	printDecodeVerdict(raw, inline, best, serverTime, logger)
+
+	PrintErrorSummary(logger)
+
+	logger.Println("\n✅ Test tamamlandı! Sonuçlar 'read_decode_results.txt' dosyasına kaydedildi.")
+}
+
+// decodeBound - Bu orandan yakın süreler "aynı" sayılır (tekrarlar arası gürültü)
+const decodeBound = 1.10
+
+// printDecodeVerdict - raw / inline / en iyi worker sürelerinden darboğaz tahmini
+func printDecodeVerdict(raw, inline, best decodeRun, serverTime time.Duration, logger *Logger) {
+	if raw.duration <= 0 || inline.duration <= 0 {
+		return
+	}
+	logger.Println("\n🎯 Darboğaz tahmini:")
+	switch {
+	case float64(inline.duration) <= float64(raw.duration)*decodeBound:
+		// Decode eklenince süre değişmiyor: Süre sunucu ve network'te geçiyor
+		network := raw.commandTime() - serverTime
+		if network < 0 {
+			network = 0
+		}
+		if serverTime >= network {
+			logger.Printf("  🖥️  Sunucu: Decode süreyi değiştirmiyor, komut süresinin çoğu sunucuda (%v / %v)\n",
+				serverTime, raw.commandTime().Round(time.Millisecond))
+			logger.Println("     → Index, projection veya filtre ile sunucunun okuduğu veriyi azaltın")
+		} else {
+			logger.Printf("  📡 Network: Decode süreyi değiştirmiyor, komut süresinin %v'si sunucu dışında geçiyor\n",
+				network.Round(time.Millisecond))
+			logger.Println("     → Projection ile transfer edilen veriyi azaltın, batch size'ı artırın")
+		}
+	case float64(best.duration) <= float64(raw.duration)*decodeBound:
+		logger.Printf("  🧮 Client decode: Inline decode %.2fx yavaş, %s ile raw seviyesine iniyor\n",
+			float64(inline.duration)/float64(raw.duration), best.name)
+		logger.Printf("     → Decode'u %d worker'a dağıtmak yeterli; ayrıca read_codec'teki struct/özel codec'e bakın\n", best.workers)
+	default:
+		logger.Printf("  🔥 Client CPU: En iyi sonuç (%s) bile raw'ın %.2fx'i\n",
+			best.name, float64(best.duration)/float64(raw.duration))
+		logger.Println("     → Decode maliyetinin kendisini azaltın (struct decode, özel codec, projection);")
+		logger.Println("       worker sayısı GOMAXPROCS'u aştığında paralellik fayda sağlamaz")
+	}
+}
+
+// writeDecodeRecord - Çalıştırmayı perflab metrik kaydı olarak yazar
+func writeDecodeRecord(r decodeRun, serverTime time.Duration, logger *Logger) {
+	record := newMetricsRecord("read_decode")
+	record.Variant = r.variant
+	record.DurationMs = float64(r.duration) / float64(time.Millisecond)
+	record.RecordsRead = int(r.records)
+	if r.phases != nil {
+		record.CPUSeconds = r.phases.ClientCPU.Seconds()
+		record.BytesReceived = r.phases.ReplyBytes
+	}
+	record.ServerSeconds = serverTime.Seconds()
+	logger.WriteRecord(record)
+}
@@ -0,0 +1,234 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"benchkit"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// read_export.go - Dışa aktarma formatları: BSON ↔ canonical Extended JSON ↔ relaxed Extended JSON
+// Aynı sorgu sonucu cursor'dan dosyaya stream edilir; format çoğu zaman alttaki aracın seçimidir:
+// - bson:      cursor.Current olduğu gibi yazılır (mongodump/mongorestore biçimi), dönüşüm yok
+// - canonical: Tip bilgisi tam korunur ({"total": {"$numberInt": "42"}}); mongoexport --jsonFormat=canonical
+// - relaxed:   Sayılar düz JSON sayısı, tarihler ISO-8601 ({"$date": "..."}); mongoexport varsayılanı,
+//              çoğu JSON aracı doğrudan okur ama int32/int64/double ayrımı kaybolur
+// JSON formatları satır başına bir doküman yazar (JSON Lines).
+//
+// Her format için çıktı boyutu, toplam süre, serileştirme süresi (Extended JSON'a çevirme + buffer'a
+// yazma) ve serileştirme hızı (MB/sn) ölçülür. Formatlar -runs kez dönüşümlü çalışır, medyan alınır.
+// Dosyalar page cache'e yazılır (fsync yok): Disk hızı değil format maliyeti ölçülür.
+//
+// KULLANIM:
+//   go run main.go config.go analyzer.go antipattern.go errors.go logger.go samples.go sink.go iterations.go read_export.go
+//   go run main.go config.go analyzer.go antipattern.go errors.go logger.go samples.go sink.go iterations.go read_export.go -limit 0 -runs 5 -out exports -keep
+
+// exportFormats - Desteklenen formatlar; marshal nil ise ham BSON yazılır
+var exportFormats = map[string]func(raw bson.Raw) ([]byte, error){
+	"bson":      nil,
+	"canonical": func(raw bson.Raw) ([]byte, error) { return bson.MarshalExtJSON(raw, true, false) },
+	"relaxed":   func(raw bson.Raw) ([]byte, error) { return bson.MarshalExtJSON(raw, false, false) },
+}
+
+// exportRun - Tek bir dışa aktarmanın sonucu
+type exportRun struct {
+	docs      int64
+	bytes     int64
+	duration  time.Duration
+	serialize time.Duration // Dönüşüm + buffer'a yazma
+}
+
+// exportResult - Bir formatın tüm tekrarları
+type exportResult struct {
+	format string
+	path   string
+	runs   []exportRun
+}
+
+// median - Süresi medyan olan tekrar
+func (r *exportResult) median() exportRun {
+	sorted := append([]exportRun(nil), r.runs...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].duration < sorted[j].duration })
+	return sorted[len(sorted)/2]
+}
+
+func main() {
+	limit := flag.Int64("limit", 200000, "Dışa aktarılacak doküman sayısı (0 = hepsi)")
+	formatList := flag.String("formats", "bson,canonical,relaxed", "Formatlar: bson, canonical, relaxed")
+	runs := flag.Int("runs", 3, "Her format için tekrar sayısı (formatlar dönüşümlü çalışır, medyan alınır)")
+	outDir := flag.String("out", "", "Çıktı klasörü (boş = geçici klasör)")
+	keep := flag.Bool("keep", false, "Çıktı dosyalarını sonunda silme")
+	flag.Parse()
+
+	logger, err := NewLogger("read_export_results.txt")
+	if err != nil {
+		fmt.Printf("Logger oluşturulamadı: %v\n", err)
+		return
+	}
+	defer CloseLogger(logger)
+
+	logger.WriteHeader("read_export - BSON vs Canonical vs Relaxed Extended JSON")
+
+	formats := strings.Split(*formatList, ",")
+	for _, f := range formats {
+		if _, ok := exportFormats[f]; !ok {
+			logger.Printf("❌ -formats: bilinmeyen format %q (bson, canonical, relaxed)\n", f)
+			return
+		}
+	}
+	if *runs < 1 {
+		logger.Println("❌ -runs en az 1 olmalı")
+		return
+	}
+	dir := *outDir
+	if dir == "" {
+		if dir, err = os.MkdirTemp("", "read_export"); err != nil {
+			logger.Printf("❌ Geçici klasör oluşturulamadı: %v\n", err)
+			return
+		}
+	} else if err := os.MkdirAll(dir, 0755); err != nil {
+		logger.Printf("❌ %s oluşturulamadı: %v\n", dir, err)
+		return
+	}
+
+	col := GetMongo()
+	findOpts := options.Find().SetBatchSize(1000)
+	if *limit > 0 {
+		findOpts.SetLimit(*limit)
+	}
+	logger.Printf("📋 Doküman: %d (0 = hepsi), formatlar: %v, %d tekrar, çıktı: %s\n", *limit, formats, *runs, dir)
+
+	WarmUp(logger, "tam okuma", FindPass(col, bson.M{}, findOpts))
+
+	// export - Sorgu sonucunu path'e format'ta stream eder
+	export := func(path string, marshal func(raw bson.Raw) ([]byte, error)) (exportRun, error) {
+		ctx := context.Background()
+		var run exportRun
+		file, err := os.Create(path)
+		if err != nil {
+			return run, err
+		}
+		defer file.Close()
+		w := bufio.NewWriterSize(file, 1<<20)
+
+		start := time.Now()
+		cursor, err := col.Find(ctx, bson.M{}, findOpts)
+		if err != nil {
+			return run, err
+		}
+		defer cursor.Close(ctx)
+		for cursor.Next(ctx) {
+			serializeStart := time.Now()
+			data := []byte(cursor.Current)
+			if marshal != nil {
+				if data, err = marshal(cursor.Current); err != nil {
+					return run, err
+				}
+				data = append(data, '\n')
+			}
+			if _, err := w.Write(data); err != nil {
+				return run, err
+			}
+			run.serialize += time.Since(serializeStart)
+			run.docs++
+			run.bytes += int64(len(data))
+		}
+		if err := cursor.Err(); err != nil {
+			return run, err
+		}
+		if err := w.Flush(); err != nil {
+			return run, err
+		}
+		run.duration = time.Since(start)
+		return run, nil
+	}
+
+	results := make([]*exportResult, len(formats))
+	for i, f := range formats {
+		ext := "json"
+		if f == "bson" {
+			ext = "bson"
+		}
+		results[i] = &exportResult{format: f, path: filepath.Join(dir, fmt.Sprintf("orders_%s.%s", f, ext))}
+	}
+
+	// Formatlar dönüşümlü: Cache ve sunucu yükündeki kayma hepsini eşit etkilesin
+	logger.Println("\n▶️  Ölçüm")
+	for run := 1; run <= *runs; run++ {
+		for _, r := range results {
+			res, err := export(r.path, exportFormats[r.format])
+			if HandleError(logger, "export "+r.format, err) {
+				continue
+			}
+			r.runs = append(r.runs, res)
+			logger.Printf("  #%d %-10s %10v %10d doküman %10.1f MB\n",
+				run, r.format, res.duration.Round(time.Millisecond), res.docs, float64(res.bytes)/(1024*1024))
+		}
+	}
+
+	logger.Println("\n=== KARŞILAŞTIRMA (medyan) ===")
+	logger.Printf("  %-10s %10s %8s %12s %14s %14s %16s\n",
+		"format", "boyut MB", "x bson", "süre", "doküman/sn", "serileştirme", "serileştirme MB/s")
+	var bsonBytes int64
+	for _, r := range results {
+		if r.format == "bson" && len(r.runs) > 0 {
+			bsonBytes = r.median().bytes
+		}
+	}
+	for _, r := range results {
+		if len(r.runs) == 0 {
+			logger.Printf("  %-10s ❌ başarılı tekrar yok\n", r.format)
+			continue
+		}
+		m := r.median()
+		ratio := "-"
+		if bsonBytes > 0 {
+			ratio = fmt.Sprintf("%.2fx", float64(m.bytes)/float64(bsonBytes))
+		}
+		docsPerSec, serializeMBs := 0.0, 0.0
+		if m.duration > 0 {
+			docsPerSec = float64(m.docs) / m.duration.Seconds()
+		}
+		if m.serialize > 0 {
+			serializeMBs = float64(m.bytes) / (1024 * 1024) / m.serialize.Seconds()
+		}
+		logger.Printf("  %-10s %10.1f %8s %12v %14.0f %14v %16.1f\n", r.format, float64(m.bytes)/(1024*1024), ratio,
+			m.duration.Round(time.Millisecond), docsPerSec, m.serialize.Round(time.Millisecond), serializeMBs)
+
+		record := newMetricsRecord("read_export")
+		record.Variant = r.format
+		record.DurationMs = benchkit.Millis(m.duration)
+		record.RecordsRead = int(m.docs)
+		record.DocsPerSec = docsPerSec
+		record.OutputBytes = m.bytes
+		logger.WriteRecord(record)
+	}
+	logger.Println("\n💡 bson dönüşüm yapmaz: Süresi sorgu + ağ + disk yazmanın tabanıdır, JSON formatlarının farkı serileştirmedir.")
+	logger.Println("   Tip bilgisinin korunması gerekmiyorsa relaxed hem daha küçük hem daha hızlıdır;")
+	logger.Println("   geri yükleme (mongoimport/mongorestore) aynı tipleri üretmeliyse bson veya canonical seçin.")
+
+	if *keep {
+		logger.Printf("\n📁 Çıktılar: %s\n", dir)
+	} else {
+		for _, r := range results {
+			os.Remove(r.path)
+		}
+		if *outDir == "" {
+			os.Remove(dir)
+		}
+	}
+
+	PrintErrorSummary(logger)
+
+	logger.Println("\n✅ Test tamamlandı! Sonuçlar 'read_export_results.txt' dosyasına kaydedildi.")
+}
@@ -0,0 +1,214 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// read_facet.go - $facet ile çok metrikli dashboard sorgusu
+// Bir yönetim paneli genelde aynı anda birkaç metrik ister:
+// - Status'e göre sipariş sayıları
+// - Günlük toplam ciro
+// - En çok harcayan kullanıcılar (top 10)
+//
+// Üç yaklaşım karşılaştırılır:
+// 1. Tek $facet pipeline: Koleksiyon bir kez okunur, alt pipeline'lar aynı doküman akışını paylaşır.
+//    Ama alt pipeline'lar sunucuda SIRAYLA çalışır, index kullanamaz ve sonuç tek dokümandır (16 MB sınırı).
+// 2. Üç ayrı aggregation, seri: Her biri koleksiyonu ayrı ayrı okur, 3 round trip
+// 3. Üç ayrı aggregation, paralel goroutine'ler: Sunucuda 3 çekirdek aynı anda çalışabilir
+//
+// Hangisinin kazandığı veri boyutuna, sunucu çekirdek sayısına ve cache durumuna bağlıdır.
+//
+// KULLANIM:
+//   go run main.go config.go analyzer.go antipattern.go errors.go logger.go samples.go sink.go iterations.go read_facet.go
+//   go run main.go config.go analyzer.go antipattern.go errors.go logger.go samples.go sink.go iterations.go read_facet.go -runs 5 -top 20
+
+// facetRun - Bir yaklaşımın tekrar süreleri ve son sonucu
+type facetRun struct {
+	name      string
+	durations []time.Duration // Sıralı
+	byStatus  map[string]int64
+}
+
+func main() {
+	runs := flag.Int("runs", 3, "Her yaklaşım için tekrar sayısı (medyan alınır)")
+	top := flag.Int("top", 10, "Top kullanıcı sayısı")
+	flag.Parse()
+
+	logger, err := NewLogger("read_facet_results.txt")
+	if err != nil {
+		fmt.Printf("Logger oluşturulamadı: %v\n", err)
+		return
+	}
+	defer CloseLogger(logger)
+
+	logger.WriteHeader("read_facet - $facet Dashboard Sorgusu")
+
+	col := GetMongo()
+	ctx := context.Background()
+
+	// Alt pipeline'lar - hem $facet içinde hem de ayrı aggregation olarak kullanılır
+	byStatus := bson.A{
+		bson.M{"$group": bson.M{"_id": "$status", "count": bson.M{"$sum": 1}}},
+		bson.M{"$sort": bson.M{"_id": 1}},
+	}
+	byDay := bson.A{
+		bson.M{"$group": bson.M{
+			"_id":   bson.M{"$dateToString": bson.M{"format": "%Y-%m-%d", "date": "$createdAt"}},
+			"total": bson.M{"$sum": "$total"},
+			"count": bson.M{"$sum": 1},
+		}},
+		bson.M{"$sort": bson.M{"_id": 1}},
+	}
+	topUsers := bson.A{
+		bson.M{"$group": bson.M{"_id": "$userId", "spent": bson.M{"$sum": "$total"}}},
+		bson.M{"$sort": bson.M{"spent": -1}},
+		bson.M{"$limit": *top},
+	}
+	facets := []struct {
+		name     string
+		pipeline bson.A
+	}{
+		{"byStatus", byStatus},
+		{"byDay", byDay},
+		{"topUsers", topUsers},
+	}
+
+	// topUsers'taki $group, kullanıcı sayısı kadar grup oluşturur - 100 MB stage sınırını aşabilir
+	aggOpts := options.Aggregate().SetAllowDiskUse(true)
+
+	// aggregate - Pipeline'ı çalıştırır ve tüm sonuçları döndürür
+	aggregate := func(pipeline interface{}) []bson.M {
+		cursor, err := col.Aggregate(ctx, pipeline, aggOpts)
+		if HandleError(logger, "aggregate", err) {
+			return nil
+		}
+		var rows []bson.M
+		HandleError(logger, "cursor.All", cursor.All(ctx, &rows))
+		return rows
+	}
+
+	approaches := []struct {
+		name string
+		run  func() map[string][]bson.M
+	}{
+		{"tek $facet", func() map[string][]bson.M {
+			facet := bson.M{}
+			for _, f := range facets {
+				facet[f.name] = f.pipeline
+			}
+			rows := aggregate(mongo.Pipeline{{{Key: "$facet", Value: facet}}})
+
+			out := map[string][]bson.M{}
+			if len(rows) == 0 {
+				return out // Hata HandleError ile raporlandı
+			}
+			for _, f := range facets {
+				// $facet sonucu tek dokümandır: {byStatus: [...], byDay: [...], topUsers: [...]}
+				if arr, ok := rows[0][f.name].(bson.A); ok {
+					for _, v := range arr {
+						if m, ok := v.(bson.M); ok {
+							out[f.name] = append(out[f.name], m)
+						}
+					}
+				}
+			}
+			return out
+		}},
+		{"3 ayrı aggregation (seri)", func() map[string][]bson.M {
+			out := map[string][]bson.M{}
+			for _, f := range facets {
+				out[f.name] = aggregate(f.pipeline)
+			}
+			return out
+		}},
+		{"3 ayrı aggregation (paralel)", func() map[string][]bson.M {
+			out := map[string][]bson.M{}
+			var mu sync.Mutex
+			var wg sync.WaitGroup
+			for _, f := range facets {
+				wg.Add(1)
+				go func(name string, pipeline bson.A) {
+					defer wg.Done()
+					rows := aggregate(pipeline)
+					mu.Lock()
+					out[name] = rows
+					mu.Unlock()
+				}(f.name, f.pipeline)
+			}
+			wg.Wait()
+			return out
+		}},
+	}
+
+	var results []*facetRun
+	for _, a := range approaches {
+		logger.Printf("\n▶️  %s (%d tekrar)\n", a.name, *runs)
+		res := &facetRun{name: a.name}
+		var last map[string][]bson.M
+		WarmUp(logger, a.name, func() error {
+			a.run()
+			return nil
+		})
+		for i := 0; i < *runs; i++ {
+			start := time.Now()
+			last = a.run()
+			d := time.Since(start)
+			res.durations = append(res.durations, d)
+			logger.Printf("  #%d: %v\n", i+1, d.Round(time.Millisecond))
+		}
+		sort.Slice(res.durations, func(i, j int) bool { return res.durations[i] < res.durations[j] })
+
+		res.byStatus = map[string]int64{}
+		for _, row := range last["byStatus"] {
+			res.byStatus[fmt.Sprint(row["_id"])] = toInt64(row["count"])
+		}
+		logger.Printf("  📊 status: %v, %d gün, top %d kullanıcı\n", res.byStatus, len(last["byDay"]), len(last["topUsers"]))
+		results = append(results, res)
+	}
+
+	// Sonuçlar tutarlı mı? (growth çalışıyorsa sayılar değişebilir)
+	for _, r := range results[1:] {
+		if fmt.Sprint(r.byStatus) != fmt.Sprint(results[0].byStatus) {
+			logger.Printf("\n⚠️  %s status sayıları $facet'ten farklı - veri ölçüm sırasında değişmiş olabilir.\n", r.name)
+		}
+	}
+
+	// Karşılaştırma - medyan süreler
+	logger.Println("\n=== KARŞILAŞTIRMA (medyan) ===")
+	winner := results[0]
+	for _, r := range results {
+		if Percentile(r.durations, 50) < Percentile(winner.durations, 50) {
+			winner = r
+		}
+	}
+	logger.Printf("  %-30s %12s %12s %10s\n", "yaklaşım", "medyan", "min", "oran")
+	for _, r := range results {
+		median := Percentile(r.durations, 50)
+		logger.Printf("  %-30s %12v %12v %9.2fx\n",
+			r.name, median.Round(time.Millisecond), r.durations[0].Round(time.Millisecond),
+			float64(median)/float64(Percentile(winner.durations, 50)))
+	}
+
+	logger.Printf("\n🏆 Bu veri boyutunda kazanan: %s\n", winner.name)
+	switch winner {
+	case results[0]:
+		logger.Println("💡 Koleksiyonu bir kez okumak, üç ayrı taramadan ucuz: $facet I/O'yu paylaştırıyor.")
+	case results[2]:
+		logger.Println("💡 Sunucuda boş çekirdek var: Paralel aggregation'lar $facet'in seri alt pipeline'larını geçiyor.")
+	default:
+		logger.Println("💡 Seri çalıştırma kazandı - ölçüm gürültüsü olabilir, -runs değerini artırmayı deneyin.")
+	}
+
+	PrintErrorSummary(logger)
+
+	logger.Println("\n✅ Test tamamlandı! Sonuçlar 'read_facet_results.txt' dosyasına kaydedildi.")
+}
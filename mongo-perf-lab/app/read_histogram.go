@@ -0,0 +1,249 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// read_histogram.go - Sipariş tutarı (total) histogramı: $bucket / $bucketAuto vs client-side
+// Dashboard'lardaki "tutar dağılımı" grafiği için üç yol var:
+// 1. $bucket: Sabit sınırlarla (0-500, 500-1000...) sunucuda grupla → sadece N satır döner
+// 2. $bucketAuto: Sınırları MongoDB seçer, her bucket'a yaklaşık eşit doküman düşer
+// 3. Client-side: Sadece total alanını projection ile çek, histogramı Go'da hesapla
+//    → her doküman network'ten geçer
+//
+// Her yöntem için süre ve transfer edilen byte (command monitoring) karşılaştırılır.
+//
+// KULLANIM:
+//   go run main.go config.go analyzer.go antipattern.go errors.go logger.go samples.go sink.go iterations.go read_histogram.go
+//   go run main.go config.go analyzer.go antipattern.go errors.go logger.go samples.go sink.go iterations.go read_histogram.go -width 250 -auto-buckets 20
+
+// histogramBucket - Tek bir histogram satırı
+type histogramBucket struct {
+	label string
+	count int64
+}
+
+// histogramResult - Bir yöntemin sonucu
+type histogramResult struct {
+	name     string
+	buckets  []histogramBucket
+	duration time.Duration
+	phases   *CursorPhases
+}
+
+func main() {
+	width := flag.Int("width", 500, "$bucket ve client-side için bucket genişliği")
+	maxTotal := flag.Int("max", 5000, "Histogramın üst sınırı (generator total'ı 0-5000 arası üretir)")
+	autoBuckets := flag.Int("auto-buckets", 10, "$bucketAuto için bucket sayısı")
+	flag.Parse()
+
+	if *width <= 0 || *maxTotal <= 0 {
+		fmt.Println("❌ -width ve -max pozitif olmalı")
+		return
+	}
+
+	logger, err := NewLogger("read_histogram_results.txt")
+	if err != nil {
+		fmt.Printf("Logger oluşturulamadı: %v\n", err)
+		return
+	}
+	defer CloseLogger(logger)
+
+	logger.WriteHeader("read_histogram - $bucket / $bucketAuto / Client-side Histogram")
+
+	col := GetMongo()
+	ctx := context.Background()
+
+	// Sınırlar: 0, width, 2*width, ..., maxTotal
+	var boundaries []int
+	for b := 0; b <= *maxTotal; b += *width {
+		boundaries = append(boundaries, b)
+	}
+	if boundaries[len(boundaries)-1] != *maxTotal {
+		boundaries = append(boundaries, *maxTotal)
+	}
+	logger.Printf("📋 Sınırlar: %v (dışında kalan / total'ı olmayan → \"diğer\")\n", boundaries)
+
+	// measure - Ölçüm sırasında gelen cevap byte'larını command monitoring ile toplar
+	measure := func(name string, fn func() []histogramBucket) histogramResult {
+		logger.Printf("\n▶️  %s...\n", name)
+		WarmUp(logger, name, func() error {
+			fn()
+			return nil
+		})
+		ResetCursorPhases()
+		start := time.Now()
+		buckets := fn()
+		res := histogramResult{name: name, buckets: buckets, duration: time.Since(start), phases: SnapshotCursorPhases(0)}
+		logger.Printf("  ⏱️  %v, %.3f MB transfer, %d getMore\n",
+			res.duration, float64(res.phases.ReplyBytes)/(1024*1024), len(res.phases.GetMores))
+		printHistogram(res.buckets, logger)
+		return res
+	}
+
+	// aggregateBuckets - $bucket/$bucketAuto çıktısını histogram satırlarına çevirir
+	aggregateBuckets := func(pipeline mongo.Pipeline) []histogramBucket {
+		CheckAntiPatterns(AntiPatternInput{Source: "read_histogram", Pipeline: PipelineStages(pipeline)})
+		cursor, err := col.Aggregate(ctx, pipeline)
+		if HandleError(logger, "aggregate", err) {
+			return nil
+		}
+		var rows []struct {
+			ID    interface{} `bson:"_id"`
+			Count int64       `bson:"count"`
+		}
+		HandleError(logger, "cursor.All", cursor.All(ctx, &rows))
+		buckets := make([]histogramBucket, 0, len(rows))
+		for _, row := range rows {
+			var label string
+			switch id := row.ID.(type) {
+			case bson.D:
+				// $bucketAuto _id'si {min, max} dokümanıdır
+				var min, max interface{}
+				for _, e := range id {
+					switch e.Key {
+					case "min":
+						min = e.Value
+					case "max":
+						max = e.Value
+					}
+				}
+				label = fmt.Sprintf("%v-%v", min, max)
+			case int32, int64, float64:
+				// $bucket _id'si bucket'ın alt sınırıdır
+				lower := int(toInt64(id))
+				upper := lower + *width
+				if upper > *maxTotal {
+					upper = *maxTotal
+				}
+				label = fmt.Sprintf("%d-%d", lower, upper)
+			default:
+				label = fmt.Sprint(id) // default bucket ("diğer")
+			}
+			buckets = append(buckets, histogramBucket{label: label, count: row.Count})
+		}
+		return buckets
+	}
+
+	results := []histogramResult{
+		measure("$bucket (sabit sınırlar)", func() []histogramBucket {
+			return aggregateBuckets(mongo.Pipeline{
+				{{Key: "$bucket", Value: bson.M{
+					"groupBy":    "$total",
+					"boundaries": boundaries,
+					"default":    "diğer",
+					"output":     bson.M{"count": bson.M{"$sum": 1}},
+				}}},
+			})
+		}),
+		measure(fmt.Sprintf("$bucketAuto (%d bucket)", *autoBuckets), func() []histogramBucket {
+			return aggregateBuckets(mongo.Pipeline{
+				{{Key: "$bucketAuto", Value: bson.M{
+					"groupBy": "$total",
+					"buckets": *autoBuckets,
+					"output":  bson.M{"count": bson.M{"$sum": 1}},
+				}}},
+			})
+		}),
+		measure("client-side (projection {total})", func() []histogramBucket {
+			cursor, err := col.Find(ctx, bson.M{},
+				options.Find().SetProjection(bson.M{"total": 1, "_id": 0}).SetBatchSize(1000))
+			if HandleError(logger, "find", err) {
+				return nil
+			}
+			defer cursor.Close(ctx)
+
+			counts := make([]int64, len(boundaries)-1)
+			var other int64
+			for cursor.Next(ctx) {
+				// Raw lookup: Tek alan için bson.M decode etmeye gerek yok
+				total, ok := cursor.Current.Lookup("total").AsInt64OK()
+				if !ok || total < int64(boundaries[0]) || total >= int64(boundaries[len(boundaries)-1]) {
+					other++
+					continue
+				}
+				counts[int(total)/(*width)]++
+			}
+			HandleError(logger, "cursor", cursor.Err())
+
+			buckets := make([]histogramBucket, 0, len(counts)+1)
+			// $bucket boş bucket'ları döndürmez - karşılaştırılabilir olması için aynısı yapılır
+			for i, c := range counts {
+				if c == 0 {
+					continue
+				}
+				buckets = append(buckets, histogramBucket{label: fmt.Sprintf("%d-%d", boundaries[i], boundaries[i+1]), count: c})
+			}
+			if other > 0 {
+				buckets = append(buckets, histogramBucket{label: "diğer", count: other})
+			}
+			return buckets
+		}),
+	}
+
+	// $bucket ve client-side aynı sınırları kullandığı için sayılar birebir aynı olmalı
+	if sameHistogram(results[0].buckets, results[2].buckets) {
+		logger.Println("\n✅ $bucket ve client-side histogramları birebir aynı.")
+	} else {
+		logger.Println("\n⚠️  $bucket ve client-side histogramları farklı - veri ölçüm sırasında değişmiş olabilir (growth çalışıyor mu?).")
+	}
+
+	// Karşılaştırma - baseline client-side
+	base := results[len(results)-1]
+	logger.Println("\n=== KARŞILAŞTIRMA ===")
+	logger.Printf("  %-34s %12s %14s %10s %10s\n", "yöntem", "süre", "transfer MB", "hızlanma", "byte oranı")
+	for _, r := range results {
+		byteRatio := 0.0
+		if r.phases.ReplyBytes > 0 {
+			byteRatio = float64(base.phases.ReplyBytes) / float64(r.phases.ReplyBytes)
+		}
+		logger.Printf("  %-34s %12v %14.3f %9.1fx %9.0fx\n",
+			r.name, r.duration.Round(time.Millisecond), float64(r.phases.ReplyBytes)/(1024*1024),
+			float64(base.duration)/float64(r.duration), byteRatio)
+	}
+	logger.Println("\n💡 Sunucu tarafı gruplama tüm koleksiyonu yine tarar, ama network'e sadece histogram satırları çıkar.")
+	logger.Println("   Client-side yöntem, farklı sınırlarla tekrar tekrar hesaplama gerekiyorsa (tek okuma) avantajlı olabilir.")
+
+	PrintAntiPatterns(logger)
+	PrintErrorSummary(logger)
+
+	logger.Println("\n✅ Test tamamlandı! Sonuçlar 'read_histogram_results.txt' dosyasına kaydedildi.")
+}
+
+// printHistogram - Histogramı yatay çubuklarla yazdırır
+func printHistogram(buckets []histogramBucket, logger *Logger) {
+	var max int64
+	for _, b := range buckets {
+		if b.count > max {
+			max = b.count
+		}
+	}
+	for _, b := range buckets {
+		bar := 0
+		if max > 0 {
+			bar = int(b.count * 40 / max)
+		}
+		logger.Printf("  %-14s %10d %s\n", b.label, b.count, strings.Repeat("█", bar))
+	}
+}
+
+// sameHistogram - İki histogramın etiket ve sayılarının aynı olup olmadığını kontrol eder
+func sameHistogram(a, b []histogramBucket) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
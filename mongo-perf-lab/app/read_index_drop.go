@@ -0,0 +1,195 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"math/rand"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// read_index_drop.go - Okuma sürerken index silme deneyi
+// PAID sipariş listesi okuması sabit hızda çalışırken status_1 index'i silinir.
+// Ölçülen:
+// - Plan değişikliği: Dedektör (planwatch.go) IXSCAN → COLLSCAN geçişini zamanıyla yakalar
+// - Gecikme sıçraması: Workload zaman çizelgesinde silmeden önceki ve sonraki saniyeler
+// - Geçiş gecikmesi: Index'in silinmesi ile yeni planın görülmesi arasındaki süre
+//
+// Sorgu: {status: "PAID"} sayfası (rastgele skip + limit) - tipik sayfalama okuması.
+// Index'le skip edilen kayıtlar index key'leri üzerinden geçilir; index yokken her sayfa
+// için collection'ın başından PAID olmayanlar dahil tüm dokümanlar taranır.
+//
+// Deney sonunda index varsayılan olarak yeniden oluşturulur (-recreate=false ile kapatılır).
+//
+// KULLANIM:
+//   go run main.go config.go analyzer.go antipattern.go errors.go logger.go samples.go sink.go loadprofile.go budget.go workload.go planwatch.go targeting.go read_index_drop.go
+//   go run main.go config.go analyzer.go antipattern.go errors.go logger.go samples.go sink.go loadprofile.go budget.go workload.go planwatch.go targeting.go read_index_drop.go -rate 100 -before 20s -after 40s
+func main() {
+	rate := flag.Float64("rate", 200, "Okuma hızı (ops/sn)")
+	workers := flag.Int("workers", 20, "Paralel worker sayısı")
+	before := flag.Duration("before", 10*time.Second, "Index silinmeden önceki ölçüm süresi")
+	after := flag.Duration("after", 20*time.Second, "Index silindikten sonraki ölçüm süresi")
+	limit := flag.Int64("limit", 100, "Sayfa boyutu")
+	maxSkip := flag.Int("max-skip", 10000, "Rastgele skip üst sınırı")
+	watch := flag.Duration("watch", 200*time.Millisecond, "Plan dedektörünün explain aralığı")
+	recreate := flag.Bool("recreate", true, "Deney sonunda status_1 index'ini yeniden oluştur")
+	flag.Parse()
+
+	profile, err := ParseLoadProfile(fmt.Sprintf("steady:%g:%v,steady:%g:%v", *rate, *before, *rate, *after))
+	if err != nil {
+		fmt.Printf("Profil hatası: %v\n", err)
+		return
+	}
+
+	logger, err := NewLogger("read_index_drop_results.txt")
+	if err != nil {
+		fmt.Printf("Logger oluşturulamadı: %v\n", err)
+		return
+	}
+	defer CloseLogger(logger)
+
+	logger.WriteHeader("read_index_drop - Okuma Sırasında Index Silme")
+
+	col := GetMongo()
+	ctx := context.Background()
+
+	// Deney index'le başlamalı - yoksa oluştur
+	statusIndex := mongo.IndexModel{
+		Keys:    bson.D{{Key: "status", Value: 1}},
+		Options: options.Index().SetName("status_1"),
+	}
+	if _, err := col.Indexes().CreateOne(ctx, statusIndex); HandleError(logger, "createIndex", err) {
+		PrintErrorSummary(logger)
+		return
+	}
+
+	filter := bson.M{"status": "PAID"}
+	// Dedektör sayfa şeklini izler; skip değeri planı değiştirmez
+	watchOpts := options.Find().SetLimit(*limit)
+
+	logger.Printf("🚀 %.0f ops/sn, %v index'li + %v index'siz (toplam %v)\n", *rate, *before, *after, profile.TotalDuration())
+
+	start := time.Now()
+	watcher := WatchPlan(col, filter, *watch, watchOpts)
+	targeting := StartTargetingSampler(col.Database(), time.Second)
+
+	// Index, profilin ilk aşaması bitince silinir
+	dropped := make(chan time.Duration, 1)
+	go func() {
+		time.Sleep(*before)
+		at := time.Since(start)
+		if _, err := col.Indexes().DropOne(ctx, "status_1"); HandleError(logger, "dropIndex", err) {
+			close(dropped)
+			return
+		}
+		logger.Printf("🗑️  status_1 silindi (%v)\n", at.Round(time.Millisecond))
+		dropped <- at
+	}()
+
+	result := RunWorkload(ctx, profile, *workers, nil, logger.LatencySamples(), func(ctx context.Context, workerID int) error {
+		opts := options.Find().SetLimit(*limit).SetSkip(int64(rand.Intn(*maxSkip + 1)))
+		cursor, err := col.Find(ctx, filter, opts)
+		if err != nil {
+			return err
+		}
+		var page []bson.M
+		return cursor.All(ctx, &page)
+	})
+	changes := watcher.Stop()
+	targetingSamples := targeting.Stop()
+	dropAt, ok := <-dropped
+
+	PrintWorkloadReport(result, logger)
+
+	// Oran, plan değişikliğiyle aynı saniyede sıçramalı (IXSCAN'de ~1, COLLSCAN'de yüzlerce)
+	PrintTargetingTimeline(targetingSamples, logger)
+
+	// Plan değişiklikleri
+	samples, failed := watcher.Samples()
+	logger.Printf("=== PLAN DEĞİŞİKLİKLERİ (%d explain, %d hata, aralık %v) ===\n", samples, failed, *watch)
+	for i, c := range changes {
+		if i == 0 {
+			logger.Printf("  %8v  başlangıç: %s\n", c.At.Round(time.Millisecond), c.To.StageChain())
+			continue
+		}
+		logger.Printf("  %8v  %s → %s\n", c.At.Round(time.Millisecond), c.From.StageChain(), c.To.StageChain())
+	}
+
+	if !ok {
+		logger.Println("❌ Index silinemedi, karşılaştırma yapılamıyor")
+		PrintErrorSummary(logger)
+		return
+	}
+
+	// Silmeden önceki ve sonraki saniyeler ayrı ayrı
+	// Silmenin olduğu saniye iki tarafa da dahil edilmez (karışık ölçüm)
+	dropSecond := int(dropAt / time.Second)
+	var beforeP99, afterP99 []time.Duration
+	var beforeOps, afterOps, peakSecond int
+	var peak time.Duration
+	for _, point := range result.Timeline {
+		switch {
+		case point.Second < dropSecond:
+			beforeP99 = append(beforeP99, point.P99)
+			beforeOps += point.Completed
+		case point.Second > dropSecond:
+			afterP99 = append(afterP99, point.P99)
+			afterOps += point.Completed
+			if point.P99 > peak {
+				peak, peakSecond = point.P99, point.Second
+			}
+		}
+	}
+	baseline := medianDuration(beforeP99)
+	steady := medianDuration(afterP99)
+
+	logger.Println("\n=== INDEX SİLME ETKİSİ ===")
+	logger.Printf("  🗑️  Silme anı:          %v\n", dropAt.Round(time.Millisecond))
+	if len(changes) > 1 {
+		// Dedektörün start'ı workload'dan birkaç mikrosaniye sonradır, ihmal edilebilir
+		lag := changes[1].At - dropAt
+		logger.Printf("  🔀 Plan değişikliği:   %v (silmeden %v sonra, dedektör aralığı %v)\n",
+			changes[1].At.Round(time.Millisecond), lag.Round(time.Millisecond), *watch)
+	} else {
+		logger.Println("  🔀 Plan değişikliği:   görülmedi (başka bir index status sorgusunu karşılıyor olabilir)")
+	}
+	logger.Printf("  📈 p99 önce (medyan):  %v\n", baseline)
+	logger.Printf("  📈 p99 sonra (medyan): %v\n", steady)
+	logger.Printf("  🔥 En yüksek p99:      %v (%d. saniye)\n", peak, peakSecond)
+	if baseline > 0 {
+		logger.Printf("  ⚠️  Sıçrama:            %.1fx (kalıcı: %.1fx)\n",
+			float64(peak)/float64(baseline), float64(steady)/float64(baseline))
+	}
+	logger.Printf("  ✅ Tamamlanan/sn:      önce %.1f, sonra %.1f (hedef %.0f)\n",
+		float64(beforeOps)/max(1, float64(len(beforeP99))), float64(afterOps)/max(1, float64(len(afterP99))), *rate)
+	logger.Println("\n💡 Plan önbelleği index silinince hemen temizlenir: Değişiklik ilk sorguda görülür.")
+	logger.Println("   Kalıcı sıçrama, o sorgunun index'siz gerçek maliyetidir - silmeden önce $indexStats ile kullanımı kontrol edin.")
+
+	if *recreate {
+		if _, err := col.Indexes().CreateOne(ctx, statusIndex); !HandleError(logger, "createIndex", err) {
+			logger.Println("\n🔧 status_1 yeniden oluşturuldu")
+		}
+	}
+
+	record := newMetricsRecord("read_index_drop")
+	record.DurationMs = float64(result.Duration) / float64(time.Millisecond)
+	record.RecordsRead = result.TotalOps
+	record.Targeting = TotalTargeting(targetingSamples).Ratio()
+	logger.WriteRecord(record)
+
+	PrintErrorSummary(logger)
+
+	logger.Println("\n✅ Test tamamlandı! Sonuçlar 'read_index_drop_results.txt' dosyasına kaydedildi.")
+}
+
+// medianDuration - Sırasız listenin medyanı
+func medianDuration(values []time.Duration) time.Duration {
+	sorted := append([]time.Duration(nil), values...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return Percentile(sorted, 50)
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// read_nplus1.go - N+1 sorgu problemi vs $in ile toplu (batch) okuma
+// Klasik bir hata: 10.000 siparişin kullanıcılarını getirmek için her sipariş için ayrı
+// FindOne çağırmak. Her çağrı bir network round-trip demektir → 10.000 round-trip!
+// Doğrusu: userId'leri K'lık gruplara bölüp {_id: {$in: [...]}} ile toplu getirmek.
+//
+// KULLANIM:
+//   go run main.go config.go analyzer.go antipattern.go errors.go logger.go samples.go sink.go flags.go iterations.go read_nplus1.go
+//   go run main.go config.go analyzer.go antipattern.go errors.go logger.go samples.go sink.go flags.go iterations.go read_nplus1.go -orders 10000 -batches 10,100,500,1000
+//
+// Hazırlık (ölçüme dahil değil):
+// orders koleksiyonundaki userId'ler rastgele ObjectID'ler olduğu için, ilk N siparişin
+// userId'leri için "users" koleksiyonunda kullanıcı dokümanları oluşturulur (upsert).
+func main() {
+	orderCount := flag.Int("orders", 10000, "Kullanıcıları getirilecek sipariş sayısı")
+	batchList := flag.String("batches", "10,100,500,1000", "$in sorgusu başına userId sayıları (K)")
+	flag.Parse()
+
+	batchSizes, err := parseIntList(*batchList)
+	if err != nil {
+		fmt.Printf("Geçersiz -batches: %v\n", err)
+		return
+	}
+
+	logger, err := NewLogger("read_nplus1_results.txt")
+	if err != nil {
+		fmt.Printf("Logger oluşturulamadı: %v\n", err)
+		return
+	}
+	defer CloseLogger(logger)
+
+	logger.WriteHeader("read_nplus1 - N+1 Sorgu vs $in Batching")
+
+	orders := GetMongo()
+	users := orders.Database().Collection("users")
+	ctx := context.Background()
+
+	// 1. Siparişlerin userId'lerini topla
+	logger.Printf("📦 %d siparişin userId'leri okunuyor...\n", *orderCount)
+	cursor, err := orders.Find(ctx, bson.M{},
+		options.Find().SetProjection(bson.M{"userId": 1, "_id": 0}).SetLimit(int64(*orderCount)))
+	if HandleError(logger, "find", err) {
+		PrintErrorSummary(logger)
+		return
+	}
+	var userIDs []interface{}
+	for cursor.Next(ctx) {
+		var order struct {
+			UserID interface{} `bson:"userId"`
+		}
+		if err := cursor.Decode(&order); HandleError(logger, "decode", err) {
+			continue
+		}
+		userIDs = append(userIDs, order.UserID)
+	}
+	HandleError(logger, "cursor", cursor.Err())
+	cursor.Close(ctx)
+
+	if len(userIDs) == 0 {
+		logger.Println("❌ Koleksiyonda sipariş yok - önce generator çalıştırın")
+		return
+	}
+
+	// 2. users koleksiyonunu hazırla (BulkWrite upsert - tekrar çalıştırılınca çoğaltmaz)
+	logger.Printf("👤 %d kullanıcı dokümanı hazırlanıyor (users koleksiyonu)...\n", len(userIDs))
+	models := make([]mongo.WriteModel, 0, len(userIDs))
+	for i, id := range userIDs {
+		models = append(models, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": id}).
+			SetUpdate(bson.M{"$setOnInsert": bson.M{
+				"name":  fmt.Sprintf("user-%d", i),
+				"email": fmt.Sprintf("user-%d@example.com", i),
+				"tier":  []string{"FREE", "PRO", "ENTERPRISE"}[i%3],
+			}}).
+			SetUpsert(true))
+	}
+	if _, err := users.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false)); HandleError(logger, "bulkWrite", err) {
+		PrintErrorSummary(logger)
+		return
+	}
+
+	// users dokümanları cache'e alınsın: Aksi halde ilk ölçülen yöntem (N+1) diskten okuma maliyetini de öder
+	WarmUp(logger, "users", FindPass(users, bson.M{"_id": bson.M{"$in": userIDs}}))
+
+	// 3. KÖTÜ YÖNTEM: N+1 - her sipariş için ayrı FindOne
+	logger.Println("\n❌ N+1: Her sipariş için ayrı FindOne çağrılıyor...")
+	samples := logger.LatencySamples()
+	start := time.Now()
+	found := 0
+	for _, id := range userIDs {
+		var user bson.M
+		opStart := time.Now()
+		err := users.FindOne(ctx, bson.M{"_id": id}).Decode(&user)
+		samples.Record("findOne", 0, opStart, time.Since(opStart), err == nil || err == mongo.ErrNoDocuments)
+		if err != nil {
+			if err != mongo.ErrNoDocuments {
+				HandleError(logger, "findOne", err)
+			}
+			continue
+		}
+		found++
+	}
+	nPlusOne := time.Since(start)
+	logger.Printf("  ⏱️  Süre: %v\n", nPlusOne)
+	logger.Printf("  🔁 Round-trip: %d\n", len(userIDs))
+	logger.Printf("  👤 Bulunan kullanıcı: %d\n", found)
+	logger.Printf("  📉 Round-trip başına: %v\n", nPlusOne/time.Duration(len(userIDs)))
+
+	// 4. İYİ YÖNTEM: $in ile K'lık gruplar halinde
+	type batchResult struct {
+		size       int
+		duration   time.Duration
+		roundTrips int
+		found      int
+	}
+	var results []batchResult
+
+	for _, k := range batchSizes {
+		logger.Printf("\n✅ $in batching (K=%d)...\n", k)
+		start := time.Now()
+		res := batchResult{size: k}
+		op := fmt.Sprintf("in/K=%d", k) // Örnek: Find + cursor'ın sonuna kadar okunması
+		for i := 0; i < len(userIDs); i += k {
+			end := i + k
+			if end > len(userIDs) {
+				end = len(userIDs)
+			}
+
+			opStart := time.Now()
+			cursor, err := users.Find(ctx, bson.M{"_id": bson.M{"$in": userIDs[i:end]}})
+			if HandleError(logger, "find", err) {
+				samples.Record(op, 0, opStart, time.Since(opStart), false)
+				continue
+			}
+			for cursor.Next(ctx) {
+				var user bson.M
+				if err := cursor.Decode(&user); HandleError(logger, "decode", err) {
+					continue
+				}
+				res.found++
+			}
+			err = cursor.Err()
+			samples.Record(op, 0, opStart, time.Since(opStart), err == nil)
+			HandleError(logger, "cursor", err)
+			cursor.Close(ctx)
+			res.roundTrips++
+		}
+		res.duration = time.Since(start)
+		results = append(results, res)
+
+		logger.Printf("  ⏱️  Süre: %v\n", res.duration)
+		logger.Printf("  🔁 Sorgu sayısı: %d\n", res.roundTrips)
+		logger.Printf("  👤 Bulunan kullanıcı: %d\n", res.found)
+	}
+
+	// 5. Karşılaştırma tablosu
+	logger.Println("\n=== KARŞILAŞTIRMA ===")
+	logger.Printf("  %-14s %10s %14s %10s\n", "yöntem", "sorgu", "süre", "hızlanma")
+	logger.Printf("  %-14s %10d %14v %9.1fx\n", "N+1 (FindOne)", len(userIDs), nPlusOne.Round(time.Microsecond), 1.0)
+	for _, r := range results {
+		speedup := float64(nPlusOne) / float64(r.duration)
+		logger.Printf("  %-14s %10d %14v %9.1fx\n", "$in K="+strconv.Itoa(r.size), r.roundTrips, r.duration.Round(time.Microsecond), speedup)
+	}
+	logger.Println("\n💡 Maliyetin büyük kısmı sorgunun kendisi değil, round-trip sayısıdır.")
+	logger.Println("💡 Çok büyük K değerleri tek sorguyu ağırlaştırır; genelde 100-1000 arası idealdir.")
+
+	PrintErrorSummary(logger)
+
+	logger.Println("\n✅ Test tamamlandı! Sonuçlar 'read_nplus1_results.txt' dosyasına kaydedildi.")
+}
@@ -0,0 +1,361 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"benchkit"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// read_partitioned.go - Koleksiyon seviyesinde partition: K koleksiyon paralel ↔ tek koleksiyon K worker
+// Sharding olmadan büyük bir koleksiyonu bölmenin client tarafı yolu, veriyi _id aralıklarına göre
+// K ayrı koleksiyona (orders_0 .. orders_{K-1}) dağıtmaktır. Tarama her iki durumda da K worker ile yapılır:
+// 1. single: Tek koleksiyon, her worker kendi _id aralığını okur (_id index'i üzerinden IXSCAN + FETCH)
+// 2. partitioned: Her worker bir koleksiyonun tamamını okur (filtre yok, COLLSCAN)
+//
+// Aralıklar $bucketAuto ile eşit doküman sayılı K parçaya bölünür; partition'lar aynı aralıklardan
+// $out ile oluşturulur, yani iki yöntemde her worker aynı dokümanları okur. Fark sadece erişim yolundadır:
+// Index sırasıyla rastgele sayfalara gitmek ↔ her koleksiyonun kendi dosyasını baştan sona okumak,
+// ve tek koleksiyonun B-tree'sini paylaşan worker'lar ↔ birbirinden bağımsız K dosya.
+// Bedeli: K ayrı _id index'i, K dosya ve uygulamada koleksiyon adı yönlendirmesi (depolama tablosunda).
+//
+// Partition'lar varsa ve aralıklarla tutarlıysa (sayı ve ilk _id) yeniden oluşturulmaz (-rebuild ile zorlanır).
+//
+// KULLANIM:
+//   go run main.go config.go analyzer.go antipattern.go errors.go logger.go samples.go sink.go dataset.go iterations.go read_partitioned.go
+//   go run main.go config.go analyzer.go antipattern.go errors.go logger.go samples.go sink.go dataset.go iterations.go read_partitioned.go -partitions 16 -runs 5 -rebuild
+
+// idRange - Bir partition'ın _id aralığı: [min, max); son partition'da max nil (üst sınır yok)
+type idRange struct {
+	min, max interface{}
+	count    int64
+}
+
+// filter - Aralığın tek koleksiyondaki sorgusu
+func (r idRange) filter() bson.M {
+	cond := bson.M{"$gte": r.min}
+	if r.max != nil {
+		cond["$lt"] = r.max
+	}
+	return bson.M{"_id": cond}
+}
+
+// partitionScan - Tek bir taramanın sonucu
+type partitionScan struct {
+	duration time.Duration
+	docs     int64
+	workers  []time.Duration // Worker başına süre
+}
+
+// imbalance - En yavaş worker / en hızlı worker
+func (s partitionScan) imbalance() float64 {
+	if len(s.workers) == 0 {
+		return 0
+	}
+	fastest, slowest := s.workers[0], s.workers[0]
+	for _, d := range s.workers {
+		fastest, slowest = min(fastest, d), max(slowest, d)
+	}
+	if fastest <= 0 {
+		return 0
+	}
+	return float64(slowest) / float64(fastest)
+}
+
+// partitionVariant - Bir yöntemin tüm tekrarları
+type partitionVariant struct {
+	name  string
+	scans []partitionScan
+	// target - i. worker'ın okuyacağı koleksiyon ve filtre
+	target func(i int) (*mongo.Collection, bson.M)
+}
+
+// median - Tekrarların medyan süreli taraması
+func (v *partitionVariant) median() partitionScan {
+	sorted := append([]partitionScan(nil), v.scans...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].duration < sorted[j].duration })
+	return sorted[len(sorted)/2]
+}
+
+func main() {
+	partitions := flag.Int("partitions", 8, "Partition (koleksiyon) sayısı = worker sayısı")
+	runs := flag.Int("runs", 3, "Her yöntem için tekrar sayısı (yöntemler dönüşümlü çalışır, medyan alınır)")
+	rebuild := flag.Bool("rebuild", false, "Partition koleksiyonlarını mevcut olsalar da yeniden oluştur")
+	drop := flag.Bool("drop", false, "Ölçümden sonra partition koleksiyonlarını sil")
+	flag.Parse()
+
+	logger, err := NewLogger("read_partitioned_results.txt")
+	if err != nil {
+		fmt.Printf("Logger oluşturulamadı: %v\n", err)
+		return
+	}
+	defer CloseLogger(logger)
+
+	logger.WriteHeader("read_partitioned - K Koleksiyon Paralel ↔ Tek Koleksiyon K Worker")
+
+	if *partitions < 1 || *runs < 1 {
+		logger.Println("❌ -partitions ve -runs en az 1 olmalı")
+		return
+	}
+	k := *partitions
+
+	col := GetMongo()
+	ctx := context.Background()
+
+	total, err := col.CountDocuments(ctx, bson.M{})
+	if HandleError(logger, "count", err) {
+		return
+	}
+	if total < int64(k) {
+		logger.Printf("❌ %s koleksiyonunda %d doküman var, %d partition için yetersiz\n", col.Name(), total, k)
+		return
+	}
+	logger.Printf("📋 %s: %d doküman, %d partition / worker, %d tekrar\n", col.Name(), total, k, *runs)
+
+	ranges, err := partitionRanges(ctx, col, k)
+	if err != nil {
+		logger.Printf("❌ _id aralıkları hesaplanamadı: %v\n", err)
+		return
+	}
+	parts := make([]*mongo.Collection, len(ranges))
+	for i := range ranges {
+		parts[i] = col.Database().Collection(fmt.Sprintf("%s_%d", col.Name(), i))
+	}
+	if len(ranges) != k {
+		// $bucketAuto tekrar eden sınırları birleştirebilir; küçük veri setinde daha az bucket döner
+		logger.Printf("⚠️  $bucketAuto %d aralık döndürdü, %d worker ile devam ediliyor\n", len(ranges), len(ranges))
+		k = len(ranges)
+	}
+
+	if *rebuild || !partitionsReady(ctx, parts, ranges) {
+		logger.Printf("\n⚙️  Partition koleksiyonları oluşturuluyor (%s_0 .. %s_%d)...\n", col.Name(), col.Name(), k-1)
+		start := time.Now()
+		for i, r := range ranges {
+			cursor, err := col.Aggregate(ctx, mongo.Pipeline{
+				{{Key: "$match", Value: r.filter()}},
+				{{Key: "$out", Value: parts[i].Name()}},
+			}, options.Aggregate().SetAllowDiskUse(true))
+			if err != nil {
+				logger.Printf("❌ %s oluşturulamadı: %v\n", parts[i].Name(), err)
+				return
+			}
+			cursor.Close(ctx)
+		}
+		logger.Printf("  ✅ %v\n", time.Since(start).Round(time.Millisecond))
+	} else {
+		logger.Println("\n♻️  Partition koleksiyonları mevcut ve kaynakla tutarlı (-rebuild ile yeniden oluşturulur)")
+	}
+	for i, r := range ranges {
+		logger.Printf("  %-14s %10d doküman  _id ≥ %v\n", parts[i].Name(), r.count, r.min)
+	}
+
+	// Her iki yöntemde aynı projection: Fark erişim yolundan gelsin, decode'dan değil
+	findOpts := options.Find().SetBatchSize(1000).SetProjection(bson.M{"userId": 1, "status": 1, "total": 1})
+
+	variants := []*partitionVariant{
+		{name: "single", target: func(i int) (*mongo.Collection, bson.M) { return col, ranges[i].filter() }},
+		{name: "partitioned", target: func(i int) (*mongo.Collection, bson.M) { return parts[i], bson.M{} }},
+	}
+
+	// scan - K worker'ı aynı anda başlatır, her worker kendi hedefini sonuna kadar okur
+	// Her worker'ın taraması -latency-samples'a varyant adıyla tek örnek olarak yazılır
+	samples := logger.LatencySamples()
+	scan := func(v *partitionVariant) (partitionScan, error) {
+		res := partitionScan{workers: make([]time.Duration, k)}
+		counts := make([]int64, k)
+		errs := make([]error, k)
+		var wg sync.WaitGroup
+		start := time.Now()
+		for i := 0; i < k; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				c, filter := v.target(i)
+				workerStart := time.Now()
+				cursor, err := c.Find(ctx, filter, findOpts)
+				if err != nil {
+					errs[i] = err
+					samples.Record(v.name, i, workerStart, time.Since(workerStart), false)
+					return
+				}
+				defer cursor.Close(ctx)
+				for cursor.Next(ctx) {
+					counts[i]++
+				}
+				errs[i] = cursor.Err()
+				res.workers[i] = time.Since(workerStart)
+				samples.Record(v.name, i, workerStart, res.workers[i], errs[i] == nil)
+			}(i)
+		}
+		wg.Wait()
+		res.duration = time.Since(start)
+		for i := range counts {
+			res.docs += counts[i]
+			if errs[i] != nil {
+				return res, errs[i]
+			}
+		}
+		return res, nil
+	}
+
+	for _, v := range variants {
+		WarmUp(logger, v.name, func() error {
+			_, err := scan(v)
+			return err
+		})
+	}
+
+	// Yöntemler dönüşümlü çalışır: Cache ve sunucu yükündeki kayma ikisini eşit etkilesin
+	logger.Println("\n▶️  Ölçüm")
+	for run := 1; run <= *runs; run++ {
+		for _, v := range variants {
+			res, err := scan(v)
+			if HandleError(logger, v.name, err) {
+				continue
+			}
+			v.scans = append(v.scans, res)
+			logger.Printf("  #%d %-12s %10v %10d doküman  worker dengesizliği %.2fx\n",
+				run, v.name, res.duration.Round(time.Millisecond), res.docs, res.imbalance())
+		}
+	}
+
+	logger.Println("\n=== KARŞILAŞTIRMA (medyan) ===")
+	logger.Printf("  %-12s %12s %10s %14s %14s\n", "yöntem", "süre", "doküman", "doküman/sn", "dengesizlik")
+	var medians []partitionScan
+	for _, v := range variants {
+		if len(v.scans) == 0 {
+			logger.Printf("  %-12s ❌ başarılı tekrar yok\n", v.name)
+			continue
+		}
+		m := v.median()
+		medians = append(medians, m)
+		docsPerSec := 0.0
+		if m.duration > 0 {
+			docsPerSec = float64(m.docs) / m.duration.Seconds()
+		}
+		logger.Printf("  %-12s %12v %10d %14.0f %13.2fx\n",
+			v.name, m.duration.Round(time.Millisecond), m.docs, docsPerSec, m.imbalance())
+
+		record := newMetricsRecord("read_partitioned")
+		record.Variant = v.name
+		record.DurationMs = benchkit.Millis(m.duration)
+		record.RecordsRead = int(m.docs)
+		record.DocsPerSec = docsPerSec
+		logger.WriteRecord(record)
+	}
+
+	if len(medians) == 2 {
+		single, partitioned := medians[0], medians[1]
+		if single.docs != partitioned.docs {
+			logger.Printf("\n⚠️  Doküman sayıları farklı (%d ↔ %d) - kaynak koleksiyon partition'lardan sonra değişmiş olabilir (-rebuild)\n",
+				single.docs, partitioned.docs)
+		}
+		ratio := float64(single.duration) / float64(partitioned.duration)
+		switch {
+		case ratio > 1.1:
+			logger.Printf("\n🏆 partitioned %.2fx hızlı: Bağımsız koleksiyonların sıralı taraması, tek koleksiyonda _id aralığı okumayı geçiyor\n", ratio)
+		case ratio < 0.9:
+			logger.Printf("\n🏆 single %.2fx hızlı: Partition'lamanın tarama açısından getirisi yok\n", 1/ratio)
+		default:
+			logger.Printf("\n⚖️  Fark %%10'un altında (%.2fx): Bu veri boyutunda partition'lama taramayı belirgin değiştirmiyor\n", ratio)
+		}
+	}
+
+	// Depolama: K koleksiyon K ayrı _id index'i ve dosyası demektir
+	logger.Println("\n=== DEPOLAMA ===")
+	var partData, partStorage, partIndex int64
+	for _, p := range parts {
+		stats, err := CollectStorageStats(ctx, p)
+		if err != nil {
+			logger.Printf("  ⚠️  %s: %v\n", p.Name(), err)
+			continue
+		}
+		partData += stats.DataSize
+		partStorage += stats.StorageSize
+		partIndex += stats.TotalIndexSize
+	}
+	if stats, err := CollectStorageStats(ctx, col); err == nil {
+		logger.Printf("  %-22s %12s %12s %12s\n", "", "veri MB", "disk MB", "index MB")
+		logger.Printf("  %-22s %12.1f %12.1f %12.1f\n", col.Name(),
+			float64(stats.DataSize)/(1024*1024), float64(stats.StorageSize)/(1024*1024), float64(stats.TotalIndexSize)/(1024*1024))
+		logger.Printf("  %-22s %12.1f %12.1f %12.1f\n", fmt.Sprintf("%d partition toplamı", k),
+			float64(partData)/(1024*1024), float64(partStorage)/(1024*1024), float64(partIndex)/(1024*1024))
+	} else {
+		logger.Printf("  ⚠️  %s: %v\n", col.Name(), err)
+	}
+	logger.Println("\n💡 Partition'lar sorgu yönlendirmesini uygulamaya taşır: _id aralığı bilinmeyen sorgular")
+	logger.Println("   (ör: userId ile arama) K koleksiyonun hepsine gitmek zorundadır.")
+
+	if *drop {
+		for _, p := range parts {
+			HandleError(logger, "drop "+p.Name(), p.Drop(ctx))
+		}
+		logger.Printf("\n🗑️  %d partition koleksiyonu silindi\n", len(parts))
+	}
+
+	PrintErrorSummary(logger)
+
+	logger.Println("\n✅ Test tamamlandı! Sonuçlar 'read_partitioned_results.txt' dosyasına kaydedildi.")
+}
+
+// partitionRanges - Koleksiyonu $bucketAuto ile _id'ye göre eşit doküman sayılı k aralığa böler
+// $bucketAuto'da bucket'ın max'ı bir sonrakinin min'idir (hariç); son bucket'ın max'ı dahildir,
+// bu yüzden son aralık üst sınırsız bırakılır (tarama sırasında eklenen dokümanlar da kapsanır)
+func partitionRanges(ctx context.Context, col *mongo.Collection, k int) ([]idRange, error) {
+	cursor, err := col.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$bucketAuto", Value: bson.M{"groupBy": "$_id", "buckets": k}}},
+	}, options.Aggregate().SetAllowDiskUse(true))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var buckets []struct {
+		ID struct {
+			Min interface{} `bson:"min"`
+			Max interface{} `bson:"max"`
+		} `bson:"_id"`
+		Count int64 `bson:"count"`
+	}
+	if err := cursor.All(ctx, &buckets); err != nil {
+		return nil, err
+	}
+	if len(buckets) == 0 {
+		return nil, fmt.Errorf("%s boş", col.Name())
+	}
+
+	ranges := make([]idRange, len(buckets))
+	for i, b := range buckets {
+		ranges[i] = idRange{min: b.ID.Min, max: b.ID.Max, count: b.Count}
+	}
+	ranges[len(ranges)-1].max = nil
+	return ranges, nil
+}
+
+// partitionsReady - Partition'lar aralıklarla aynı dokümanları mı tutuyor
+// Sayı ve en küçük _id karşılaştırılır: Farklı -partitions ile oluşturulmuş veya veri seti yeniden
+// üretildikten sonra kalmış (aynı sayı, farklı ObjectID'ler) partition'lar tutmaz
+func partitionsReady(ctx context.Context, parts []*mongo.Collection, ranges []idRange) bool {
+	for i, p := range parts {
+		n, err := p.CountDocuments(ctx, bson.M{})
+		if err != nil || n != ranges[i].count {
+			return false
+		}
+		var first struct {
+			ID interface{} `bson:"_id"`
+		}
+		err = p.FindOne(ctx, bson.M{}, options.FindOne().SetSort(bson.M{"_id": 1}).SetProjection(bson.M{"_id": 1})).Decode(&first)
+		if err != nil || fmt.Sprint(first.ID) != fmt.Sprint(ranges[i].min) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,330 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"benchkit"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+// read_point.go - Rastgele _id ile nokta okuma (findOne) throughput testi
+// Diğer read versiyonları büyük taramaları (1M kayıt) ölçer. Production'da ise en sık
+// görülen sorgu tipi "tek dokümanı _id ile getir"dir. Bu test, yüksek eşzamanlılıkta
+// FindOne({_id}) çağrılarının ops/sn ve gecikme yüzdeliklerini (p50/p95/p99) ölçer.
+//
+// KULLANIM:
+//
+//	go run main.go config.go analyzer.go antipattern.go errors.go logger.go samples.go sink.go iterations.go read_point.go
+//	go run main.go config.go analyzer.go antipattern.go errors.go logger.go samples.go sink.go iterations.go read_point.go -workers 128 -duration 60s
+//
+// Nasıl çalışır?
+// 1. $sample ile koleksiyondan rastgele N adet _id alınır (ID havuzu)
+// 2. Her worker, havuzdan rastgele bir _id seçip FindOne çalıştırır (closed-loop, beklemeden)
+// 3. Her işlemin gecikmesi kaydedilir, sonunda yüzdelikler hesaplanır
+//
+// Hedged read (-hedge, sharded küme + MongoDB 4.4+):
+// mongos okumayı shard'ın iki üyesine birden gönderir, ilk gelen cevabı kullanır ve diğerini iptal
+// eder. Tek bir yavaş üyenin (GC, disk takılması, ağ) kuyruk gecikmesini (p99) kesmesi beklenir;
+// bedeli shard'lara giden ek okuma yüküdür. Hedge yalnızca primary olmayan okuma tercihinde
+// geçerlidir: Karşılaştırmada iki varyant da nearest kullanır, tek fark hedge seçeneğidir.
+// Shard'lar tek üyeliyse hedge edilecek ikinci üye olmadığı için fark beklenmez: docker-compose'taki
+// mongo-sharded SHARD_MEMBERS=2 ile iki üyeli shard'larla başlatılır. MongoDB 8.0'da hedged read deprecated'dır.
+//
+//	SHARD_MEMBERS=2 docker compose --profile topology up -d --wait mongo-sharded
+//	PERFLAB_ENV=topo-sharded go run main.go config.go analyzer.go antipattern.go errors.go logger.go samples.go sink.go iterations.go read_point.go -hedge compare
+func main() {
+	workers := flag.Int("workers", 64, "Eşzamanlı worker (goroutine) sayısı")
+	duration := flag.Duration("duration", 30*time.Second, "Ölçüm süresi")
+	sampleSize := flag.Int("sample", 10000, "ID havuzuna alınacak rastgele _id sayısı")
+	hedge := flag.String("hedge", "off", "Hedged read: off, on (sadece hedged) veya compare (nearest ile karşılaştır)")
+	flag.Parse()
+
+	if *hedge != "off" && *hedge != "on" && *hedge != "compare" {
+		fmt.Printf("-hedge off, on veya compare olmalı: %s\n", *hedge)
+		return
+	}
+
+	logger, err := NewLogger("read_point_results.txt")
+	if err != nil {
+		fmt.Printf("Logger oluşturulamadı: %v\n", err)
+		return
+	}
+	defer CloseLogger(logger)
+
+	logger.WriteHeader("read_point - Rastgele _id ile Nokta Okuma (findOne)")
+
+	col := GetMongo()
+	ctx := context.Background()
+
+	// ID havuzu: $sample rastgele dokümanlar seçer, sadece _id'yi getiriyoruz
+	logger.Printf("🎲 ID havuzu oluşturuluyor ($sample %d)...\n", *sampleSize)
+	cursor, err := col.Aggregate(ctx, []bson.M{
+		{"$sample": bson.M{"size": *sampleSize}},
+		{"$project": bson.M{"_id": 1}},
+	})
+	if HandleError(logger, "aggregate", err) {
+		PrintErrorSummary(logger)
+		return
+	}
+	var ids []interface{}
+	for cursor.Next(ctx) {
+		var doc struct {
+			ID interface{} `bson:"_id"`
+		}
+		if err := cursor.Decode(&doc); HandleError(logger, "decode", err) {
+			continue
+		}
+		ids = append(ids, doc.ID)
+	}
+	HandleError(logger, "cursor", cursor.Err())
+	cursor.Close(ctx)
+
+	if len(ids) == 0 {
+		logger.Println("❌ Koleksiyonda kayıt yok - önce generator çalıştırın")
+		return
+	}
+	logger.Printf("  ✅ %d adet _id alındı\n", len(ids))
+
+	// Explain: _id sorgusu IDHACK (veya MongoDB 7+'da EXPRESS_IXSCAN) kullanmalı
+	// Bu, _id index'i üzerinden doğrudan tek dokümana giden en hızlı yoldur
+	logger.Println("🔍 Sorgu analizi yapılıyor (explain)...")
+	explainResult, err := ExplainQuery(col, bson.M{"_id": ids[0]})
+	if err != nil {
+		logger.Printf("⚠️  Explain hatası: %v\n", err)
+	} else {
+		PrintExplainResults(explainResult, "read_point (findOne by _id)", logger)
+	}
+
+	logger.Printf("🚀 %d worker ile %v boyunca nokta okuma yapılıyor...\n", *workers, *duration)
+
+	// ID havuzundaki dokümanlar cache'e alınsın, gecikme dağılımı soğuk okumalarla başlamasın
+	WarmUp(logger, "ID havuzu", func() error {
+		for _, id := range ids {
+			if err := col.FindOne(ctx, bson.M{"_id": id}).Err(); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	if *hedge == "off" {
+		res := runPointReads(ctx, col, ids, *workers, *duration, "", logger)
+		printPointResult(res, *workers, logger)
+		metrics := QueryMetrics{
+			Duration:    res.elapsed,
+			RecordsRead: len(res.latencies),
+		}
+		metrics.SetMemory(res.mem)
+		PrintMetrics(metrics, "read_point", logger)
+		logger.Println("\n✅ Test tamamlandı! Sonuçlar 'read_point_results.txt' dosyasına kaydedildi.")
+		return
+	}
+
+	hedged := col.Database().Collection(col.Name(), options.Collection().SetReadPreference(readpref.Nearest(readpref.WithHedgeEnabled(true))))
+	if err := hedgeSupport(ctx, col.Database(), logger); err != nil {
+		logger.Printf("⚠️  Hedged read kullanılamıyor: %v - sadece standart okuma ölçülür\n", err)
+		hedged = nil
+	}
+
+	var results []pointResult
+	if *hedge == "compare" || hedged == nil {
+		standard := col.Database().Collection(col.Name(), options.Collection().SetReadPreference(readpref.Nearest()))
+		logger.Println("\n📍 Standart okuma (nearest)")
+		results = append(results, runPointReads(ctx, standard, ids, *workers, *duration, "standard", logger))
+	}
+	if hedged != nil {
+		logger.Println("\n🦔 Hedged okuma (nearest + hedge)")
+		results = append(results, runPointReads(ctx, hedged, ids, *workers, *duration, "hedged", logger))
+	}
+	for _, res := range results {
+		logger.Printf("\n=== %s ===", res.variant)
+		printPointResult(res, *workers, logger)
+		writePointRecord(res, logger)
+	}
+	if len(results) == 2 {
+		printHedgeComparison(results[0], results[1], logger)
+	}
+
+	logger.Println("\n✅ Test tamamlandı! Sonuçlar 'read_point_results.txt' dosyasına kaydedildi.")
+}
+
+// pointResult - Tek bir nokta okuma ölçümünün sonucu
+type pointResult struct {
+	variant   string          // standard / hedged (-hedge off'ta boş)
+	latencies []time.Duration // Başarılı işlemlerin gecikmeleri (sıralı)
+	errors    int
+	elapsed   time.Duration
+	mem       benchkit.MemUsage
+}
+
+// runPointReads - workers goroutine ile duration boyunca rastgele _id'lerle FindOne çalıştırır
+// Her işlem -latency-samples'a "findOne" (varyant varsa "findOne/<varyant>") olarak yazılır
+func runPointReads(ctx context.Context, col *mongo.Collection, ids []interface{}, workers int, duration time.Duration, variant string, logger *Logger) pointResult {
+	memProbe := benchkit.StartMemProbe()
+	samples := logger.LatencySamples()
+	op := "findOne"
+	if variant != "" {
+		op += "/" + variant
+	}
+
+	runCtx, cancel := context.WithTimeout(ctx, duration)
+	defer cancel()
+
+	// Her worker kendi gecikme listesini tutar (mutex çekişmesi olmasın diye)
+	// ve kendi rand kaynağını kullanır (global rand kilidi darboğaz olmasın diye)
+	latencies := make([][]time.Duration, workers)
+	errorCounts := make([]int, workers)
+	var wg sync.WaitGroup
+
+	start := time.Now()
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(workerID int) {
+			defer wg.Done()
+			rng := rand.New(rand.NewSource(time.Now().UnixNano() + int64(workerID)))
+
+			for runCtx.Err() == nil {
+				id := ids[rng.Intn(len(ids))]
+
+				opStart := time.Now()
+				var order bson.M
+				err := col.FindOne(runCtx, bson.M{"_id": id}).Decode(&order)
+				latency := time.Since(opStart)
+
+				if err != nil {
+					// Süre dolduğunda iptal edilen son işlem hata sayılmaz (örnek de yazılmaz)
+					if runCtx.Err() == nil {
+						errorCounts[workerID]++
+						HandleError(logger, "findOne", err)
+						samples.Record(op, workerID, opStart, latency, false)
+					}
+					continue
+				}
+				latencies[workerID] = append(latencies[workerID], latency)
+				samples.Record(op, workerID, opStart, latency, true)
+			}
+		}(w)
+	}
+	wg.Wait()
+	res := pointResult{variant: variant, elapsed: time.Since(start)}
+	res.mem = memProbe.Stop()
+
+	// Tüm worker'ların gecikmelerini birleştir ve sırala
+	for w := 0; w < workers; w++ {
+		res.latencies = append(res.latencies, latencies[w]...)
+		res.errors += errorCounts[w]
+	}
+	sort.Slice(res.latencies, func(i, j int) bool { return res.latencies[i] < res.latencies[j] })
+	return res
+}
+
+// printPointResult - Throughput ve gecikme yüzdeliklerini yazdırır
+func printPointResult(res pointResult, workers int, logger *Logger) {
+	all := res.latencies
+	opsPerSec := float64(len(all)) / res.elapsed.Seconds()
+
+	logger.Printf("\n✅ NOKTA OKUMA SONUÇLARI:\n")
+	logger.Printf("👥 Worker sayısı: %d\n", workers)
+	logger.Printf("⏱️  Süre: %v\n", res.elapsed)
+	logger.Printf("📦 Başarılı İşlem: %d\n", len(all))
+	logger.Printf("❌ Hatalı İşlem: %d\n", res.errors)
+	logger.Printf("🚀 Throughput: %.1f ops/sn\n", opsPerSec)
+	logger.Printf("📈 Gecikme:\n")
+	logger.Printf("  p50: %v\n", Percentile(all, 50))
+	logger.Printf("  p95: %v\n", Percentile(all, 95))
+	logger.Printf("  p99: %v\n", Percentile(all, 99))
+	logger.Printf("  max: %v\n", Percentile(all, 100))
+
+	// Little's Law: eşzamanlılık ≈ throughput × ortalama gecikme
+	// Worker sayısını artırmak throughput'u artırmıyor ama gecikmeyi artırıyorsa sunucu doymuştur
+	if len(all) > 0 {
+		var sum time.Duration
+		for _, l := range all {
+			sum += l
+		}
+		avg := sum / time.Duration(len(all))
+		logger.Printf("  ort: %v (Little's Law: %.1f eşzamanlı işlem)\n", avg, opsPerSec*avg.Seconds())
+	}
+}
+
+// writePointRecord - Varyantı perflab metrik kaydı olarak yazar (p50/p99 assertion'larda kullanılabilir)
+func writePointRecord(res pointResult, logger *Logger) {
+	record := newMetricsRecord("read_point")
+	record.Variant = res.variant
+	record.DurationMs = benchkit.Millis(res.elapsed)
+	record.RecordsRead = len(res.latencies)
+	record.MemoryMB = float64(res.mem.PeakGrowth) / (1024 * 1024)
+	record.MaxHeapMB = float64(res.mem.PeakHeap) / (1024 * 1024)
+	record.AllocatedMB = float64(res.mem.Allocated) / (1024 * 1024)
+	record.NumGC = res.mem.NumGC
+	record.GCPauseMs = benchkit.Millis(res.mem.GCPause)
+	record.DocsPerSec = float64(len(res.latencies)) / res.elapsed.Seconds()
+	record.Errors = res.errors
+	record.P50Ms = benchkit.Millis(Percentile(res.latencies, 50))
+	record.P99Ms = benchkit.Millis(Percentile(res.latencies, 99))
+	logger.WriteRecord(record)
+}
+
+// printHedgeComparison - Standart ve hedged okumanın yüzdeliklerini yan yana yazdırır
+// Hedge'in faydası kuyruktadır: p50 değişmezken p99/max düşmesi beklenir
+func printHedgeComparison(standard, hedged pointResult, logger *Logger) {
+	logger.Println("\n📊 Hedged read karşılaştırması (değişim: hedged / standart - 1):")
+	logger.Printf("  %-6s %12s %12s %9s\n", "", "standart", "hedged", "değişim")
+	for _, p := range []float64{50, 95, 99, 99.9, 100} {
+		s, h := Percentile(standard.latencies, p), Percentile(hedged.latencies, p)
+		change := 0.0
+		if s > 0 {
+			change = (float64(h)/float64(s) - 1) * 100
+		}
+		label := fmt.Sprintf("p%g", p)
+		if p == 100 {
+			label = "max"
+		}
+		logger.Printf("  %-6s %12v %12v %+8.1f%%\n", label, s, h, change)
+	}
+	logger.Printf("  %-6s %12.1f %12.1f\n", "ops/sn",
+		float64(len(standard.latencies))/standard.elapsed.Seconds(), float64(len(hedged.latencies))/hedged.elapsed.Seconds())
+}
+
+// hedgeSupport - Hedged read'in bu dağıtımda geçerli olup olmadığını kontrol eder
+// Hedge sadece mongos üzerinden ve MongoDB 4.4+'ta uygulanır; replica set veya tek düğümde
+// seçenek sunucuya gitse de hiçbir şey değiştirmez
+func hedgeSupport(ctx context.Context, db *mongo.Database, logger *Logger) error {
+	admin := db.Client().Database("admin")
+	var hello struct {
+		Msg string `bson:"msg"`
+	}
+	if err := admin.RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&hello); err != nil {
+		return err
+	}
+	if hello.Msg != "isdbgrid" {
+		return fmt.Errorf("sadece sharded kümede (mongos) desteklenir")
+	}
+	var info struct {
+		Version      string  `bson:"version"`
+		VersionArray []int32 `bson:"versionArray"`
+	}
+	if err := admin.RunCommand(ctx, bson.D{{Key: "buildInfo", Value: 1}}).Decode(&info); err != nil {
+		return err
+	}
+	if len(info.VersionArray) < 2 {
+		return fmt.Errorf("sunucu sürümü okunamadı: %q", info.Version)
+	}
+	major, minor := info.VersionArray[0], info.VersionArray[1]
+	if major < 4 || major == 4 && minor < 4 {
+		return fmt.Errorf("MongoDB 4.4+ gerekir (sunucu %s)", info.Version)
+	}
+	if major >= 8 {
+		logger.Printf("⚠️  MongoDB %s: hedged read deprecated, ölçüm yine yapılır\n", info.Version)
+	}
+	return nil
+}
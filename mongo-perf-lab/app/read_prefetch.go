@@ -0,0 +1,199 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"runtime"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// read_prefetch.go - Cursor prefetching (çift tamponlu getMore) vs sıralı cursor.Next
+// Sıralı döngüde getMore ve decode sırayla çalışır: toplam ≈ getMore'lar + decode.
+// PrefetchCursor (bkz. prefetch.go) sonraki batch'i decode sırasında getirir: toplam ≈ max(...).
+// Script iki yöntemi dönüşümlü olarak -rounds kez çalıştırır (ilk okumanın cache'i ısıtması
+// tek tarafa yazılmasın diye) ve her yöntemin en iyi turunu karşılaştırır.
+//
+// Örtüşme kazancı: Sıralı - prefetch süresi. Teorik üst sınırı min(getMore süresi, decode süresi)
+// - ikisi dengeliyse kazanç büyük, biri baskınsa küçüktür.
+//
+// KULLANIM:
+//   go run main.go config.go analyzer.go antipattern.go errors.go logger.go samples.go sink.go prefetch.go iterations.go read_prefetch.go
+//   go run main.go config.go analyzer.go antipattern.go errors.go logger.go samples.go sink.go prefetch.go iterations.go read_prefetch.go -limit 0 -batch 5000 -depth 2
+
+// prefetchRun - Bir turun sonucu
+type prefetchRun struct {
+	records  int
+	duration time.Duration
+	decode   time.Duration
+	wait     time.Duration // Sadece prefetch: Next'in batch beklediği süre
+	phases   *CursorPhases
+}
+
+// commandTime - find + getMore komutlarının süresi (sunucu + network)
+func (r prefetchRun) commandTime() time.Duration {
+	if r.phases == nil {
+		return 0
+	}
+	return r.phases.FirstBatch + r.phases.GetMoreTotal()
+}
+
+func main() {
+	limit := flag.Int64("limit", 200000, "Okunacak doküman sayısı (0 = hepsi)")
+	batchSize := flag.Int("batch", 1000, "Cursor batch size")
+	depth := flag.Int("depth", 1, "Prefetch derinliği: Hazırda bekleyebilecek batch sayısı (1 = çift tampon)")
+	rounds := flag.Int("rounds", 2, "Her yöntemin kaç kez çalıştırılacağı (dönüşümlü, en iyisi alınır)")
+	flag.Parse()
+
+	if *batchSize <= 0 || *depth <= 0 || *rounds <= 0 {
+		fmt.Println("❌ -batch, -depth ve -rounds pozitif olmalı")
+		return
+	}
+
+	logger, err := NewLogger("read_prefetch_results.txt")
+	if err != nil {
+		fmt.Printf("Logger oluşturulamadı: %v\n", err)
+		return
+	}
+	defer CloseLogger(logger)
+
+	logger.WriteHeader("read_prefetch - Cursor Prefetching (Çift Tamponlu getMore)")
+
+	col := GetMongo()
+	ctx := context.Background()
+
+	findOpts := options.Find().SetBatchSize(int32(*batchSize))
+	if *limit > 0 {
+		findOpts.SetLimit(*limit)
+	}
+	logger.Printf("📋 Okunacak doküman: %d (0 = hepsi), batch %d, prefetch derinliği %d, %d tur\n",
+		*limit, *batchSize, *depth, *rounds)
+
+	// sequential - Klasik döngü: Next batch bitince getMore'u bekler
+	sequential := func() prefetchRun {
+		var res prefetchRun
+		ResetCursorPhases()
+		start := time.Now()
+		cursor, err := col.Find(ctx, bson.M{}, findOpts)
+		if HandleError(logger, "find", err) {
+			return res
+		}
+		for cursor.Next(ctx) {
+			var doc bson.M
+			decodeStart := time.Now()
+			err := cursor.Decode(&doc)
+			res.decode += time.Since(decodeStart)
+			if HandleError(logger, "decode", err) {
+				continue
+			}
+			res.records++
+		}
+		HandleError(logger, "cursor", cursor.Err())
+		cursor.Close(ctx)
+		res.duration = time.Since(start)
+		res.phases = SnapshotCursorPhases(res.decode)
+		return res
+	}
+
+	// prefetched - Aynı döngü PrefetchCursor ile
+	prefetched := func() prefetchRun {
+		var res prefetchRun
+		ResetCursorPhases()
+		start := time.Now()
+		cursor, err := col.Find(ctx, bson.M{}, findOpts)
+		if HandleError(logger, "find", err) {
+			return res
+		}
+		pc := NewPrefetchCursor(ctx, cursor, *depth)
+		for pc.Next(ctx) {
+			var doc bson.M
+			decodeStart := time.Now()
+			err := pc.Decode(&doc)
+			res.decode += time.Since(decodeStart)
+			if HandleError(logger, "decode", err) {
+				continue
+			}
+			res.records++
+		}
+		HandleError(logger, "cursor", pc.Err())
+		pc.Close(ctx)
+		res.duration = time.Since(start)
+		res.wait, _ = pc.Wait()
+		res.phases = SnapshotCursorPhases(res.decode)
+		return res
+	}
+
+	var bestSeq, bestPre prefetchRun
+	better := func(best, r prefetchRun) prefetchRun {
+		if r.records > 0 && (best.duration == 0 || r.duration < best.duration) {
+			return r
+		}
+		return best
+	}
+	WarmUp(logger, "sıralı okuma", func() error {
+		sequential()
+		return nil
+	})
+	for i := 1; i <= *rounds; i++ {
+		runtime.GC()
+		seq := sequential()
+		runtime.GC()
+		pre := prefetched()
+		logger.Printf("\n🔁 Tur %d/%d\n", i, *rounds)
+		logger.Printf("  ➡️  Sıralı:   %d kayıt, %v (getMore+find %v, decode %v)\n",
+			seq.records, seq.duration.Round(time.Millisecond), seq.commandTime().Round(time.Millisecond), seq.decode.Round(time.Millisecond))
+		logger.Printf("  ⏩ Prefetch: %d kayıt, %v (getMore+find %v, decode %v, batch bekleme %v)\n",
+			pre.records, pre.duration.Round(time.Millisecond), pre.commandTime().Round(time.Millisecond),
+			pre.decode.Round(time.Millisecond), pre.wait.Round(time.Millisecond))
+		bestSeq, bestPre = better(bestSeq, seq), better(bestPre, pre)
+	}
+	if bestSeq.duration == 0 || bestPre.duration == 0 {
+		PrintErrorSummary(logger)
+		return
+	}
+
+	gain := bestSeq.duration - bestPre.duration
+	ideal := min(bestSeq.commandTime(), bestSeq.decode)
+	logger.Println("\n=== KARŞILAŞTIRMA (en iyi turlar) ===")
+	logger.Printf("  %-10s %12s %14s %12s %14s\n", "yöntem", "toplam", "getMore+find", "decode", "batch bekleme")
+	logger.Printf("  %-10s %12v %14v %12v %14s\n", "sıralı",
+		bestSeq.duration.Round(time.Millisecond), bestSeq.commandTime().Round(time.Millisecond), bestSeq.decode.Round(time.Millisecond), "-")
+	logger.Printf("  %-10s %12v %14v %12v %14v\n", "prefetch",
+		bestPre.duration.Round(time.Millisecond), bestPre.commandTime().Round(time.Millisecond), bestPre.decode.Round(time.Millisecond), bestPre.wait.Round(time.Millisecond))
+	logger.Printf("\n⚡ Örtüşme kazancı: %v (%%%.1f)\n", gain.Round(time.Millisecond), float64(gain)/float64(bestSeq.duration)*100)
+	if ideal > 0 {
+		logger.Printf("🎯 Teorik üst sınır min(getMore+find, decode) = %v → kazancın %%%.0f'i alındı\n",
+			ideal.Round(time.Millisecond), float64(gain)/float64(ideal)*100)
+	}
+	switch {
+	case gain <= 0:
+		logger.Println("💡 Kazanç yok: Batch'ler zaten hızlı geliyor veya kopyalama maliyeti örtüşmeyi yiyor")
+	case bestPre.wait > bestPre.decode:
+		logger.Println("💡 Prefetch'te decode batch bekliyor: Darboğaz network/sunucu (batch size veya projection'a bakın)")
+	default:
+		logger.Println("💡 getMore'lar decode ile örtüşüyor: Kalan süre decode'da (bkz. read_codec, read_decode)")
+	}
+
+	writePrefetchRecord("sequential", bestSeq, logger)
+	writePrefetchRecord("prefetch", bestPre, logger)
+
+	PrintErrorSummary(logger)
+
+	logger.Println("\n✅ Test tamamlandı! Sonuçlar 'read_prefetch_results.txt' dosyasına kaydedildi.")
+}
+
+// writePrefetchRecord - Yöntemin en iyi turunu perflab metrik kaydı olarak yazar
+func writePrefetchRecord(variant string, r prefetchRun, logger *Logger) {
+	record := newMetricsRecord("read_prefetch")
+	record.Variant = variant
+	record.DurationMs = float64(r.duration) / float64(time.Millisecond)
+	record.RecordsRead = r.records
+	if r.phases != nil {
+		record.CPUSeconds = r.phases.ClientCPU.Seconds()
+		record.BytesReceived = r.phases.ReplyBytes
+	}
+	logger.WriteRecord(record)
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"runtime"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// read_projection.go - Projection etkisinin otomatik 3'lü karşılaştırması
+// read_v2, projection'ın faydasını elle gösteriyordu. Bu script aynı filtreyi
+// üç farklı projection ile çalıştırır ve sonuçları yan yana koyar:
+// 1. Tam doküman: projection yok, items/createdAt dahil her şey gelir
+// 2. Dar projection: {userId, status} - daha az byte, ama doküman yine diskten/cache'ten okunur
+// 3. Covered projection: {status} - sadece index'teki alanlar, _id hariç.
+//    MongoDB dokümana hiç gitmez, cevabı index'ten üretir (totalDocsExamined = 0)
+//
+// Her varyant için: transfer edilen byte (command monitoring), decode süresi ve toplam süre.
+//
+// KULLANIM:
+//   go run main.go config.go analyzer.go antipattern.go errors.go logger.go samples.go sink.go iterations.go read_projection.go
+//   go run main.go config.go analyzer.go antipattern.go errors.go logger.go samples.go sink.go iterations.go read_projection.go -status PENDING
+func main() {
+	status := flag.String("status", "PAID", "Filtrelenecek status değeri")
+	flag.Parse()
+
+	logger, err := NewLogger("read_projection_results.txt")
+	if err != nil {
+		fmt.Printf("Logger oluşturulamadı: %v\n", err)
+		return
+	}
+	defer CloseLogger(logger)
+
+	logger.WriteHeader("read_projection - Projection Etkisi (tam / dar / covered)")
+
+	col := GetMongo()
+	ctx := context.Background()
+
+	// Covered query için status_1 index'i şart - yoksa oluştur (varsa MongoDB hata vermez)
+	if _, err := col.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "status", Value: 1}},
+		Options: options.Index().SetName("status_1"),
+	}); err != nil {
+		logger.Printf("⚠️  status_1 index'i oluşturulamadı: %v\n", err)
+	}
+
+	filter := bson.M{"status": *status}
+	variants := []struct {
+		name       string
+		projection bson.M
+	}{
+		{"tam doküman", nil},
+		{"dar {userId,status}", bson.M{"userId": 1, "status": 1, "_id": 0}},
+		{"covered {status}", bson.M{"status": 1, "_id": 0}},
+	}
+
+	type projectionResult struct {
+		name         string
+		records      int
+		duration     time.Duration
+		phases       *CursorPhases
+		docsExamined int64
+		stage        string
+	}
+	var results []projectionResult
+
+	for _, v := range variants {
+		logger.Printf("\n▶️  %s\n", v.name)
+
+		findOpts := options.Find().SetBatchSize(1000)
+		if v.projection != nil {
+			findOpts.SetProjection(v.projection)
+		}
+
+		res := projectionResult{name: v.name}
+
+		// Explain: covered varyantta totalDocsExamined 0 olmalı
+		explainResult, err := ExplainQuery(col, filter, findOpts)
+		if err != nil {
+			logger.Printf("  ⚠️  Explain hatası: %v\n", err)
+		} else {
+			plan := SummarizeExplain(explainResult)
+			CheckExplain(explainResult, "read_projection: "+v.name)
+			res.docsExamined = plan.DocsExamined
+			res.stage = plan.StageChain()
+			logger.Printf("  🎯 Plan: %s, incelenen doküman: %d\n", res.stage, res.docsExamined)
+		}
+
+		// Her varyant farklı index/alan okur: Önceki varyantın ısıttığı cache'ten faydalanmasın diye kendi ısınması
+		WarmUp(logger, v.name, FindPass(col, filter, findOpts))
+		runtime.GC()
+		ResetCursorPhases()
+		start := time.Now()
+
+		cursor, err := col.Find(ctx, filter, findOpts)
+		if HandleError(logger, "find", err) {
+			continue
+		}
+		var decodeTime time.Duration
+		for cursor.Next(ctx) {
+			var doc bson.M
+			decodeStart := time.Now()
+			err := cursor.Decode(&doc)
+			decodeTime += time.Since(decodeStart)
+			if HandleError(logger, "decode", err) {
+				continue
+			}
+			res.records++
+		}
+		HandleError(logger, "cursor", cursor.Err())
+		cursor.Close(ctx)
+
+		res.duration = time.Since(start)
+		res.phases = SnapshotCursorPhases(decodeTime)
+		results = append(results, res)
+
+		logger.Printf("  📦 %d kayıt, %v, %.2f MB transfer, decode %v\n",
+			res.records, res.duration, float64(res.phases.ReplyBytes)/(1024*1024), res.phases.Decode)
+	}
+
+	if len(results) < len(variants) {
+		logger.Println("\n❌ Bazı varyantlar çalıştırılamadı - karşılaştırma yapılmıyor.")
+		PrintErrorSummary(logger)
+		return
+	}
+
+	// 3'lü karşılaştırma - baseline tam doküman
+	base := results[0]
+	logger.Println("\n=== KARŞILAŞTIRMA ===")
+	logger.Printf("  %-22s %10s %12s %12s %12s %10s %12s\n",
+		"varyant", "kayıt", "transfer MB", "decode", "toplam", "hızlanma", "incelenen")
+	for _, r := range results {
+		speedup := float64(base.duration) / float64(r.duration)
+		logger.Printf("  %-22s %10d %12.2f %12v %12v %9.1fx %12d\n",
+			r.name, r.records, float64(r.phases.ReplyBytes)/(1024*1024),
+			r.phases.Decode.Round(time.Millisecond), r.duration.Round(time.Millisecond),
+			speedup, r.docsExamined)
+	}
+
+	covered := results[len(results)-1]
+	if covered.stage != "" && covered.docsExamined == 0 && covered.records > 0 {
+		logger.Println("\n✅ Covered query doğrulandı: MongoDB hiç doküman okumadı, cevap index'ten geldi.")
+	} else {
+		logger.Println("\n⚠️  Covered query gerçekleşmedi - status_1 index'ini ve projection'ı kontrol edin.")
+	}
+
+	PrintAntiPatterns(logger)
+	PrintErrorSummary(logger)
+
+	logger.Println("\n✅ Test tamamlandı! Sonuçlar 'read_projection_results.txt' dosyasına kaydedildi.")
+}
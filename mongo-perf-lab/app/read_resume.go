@@ -0,0 +1,189 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// read_resume.go - Cursor timeout ve kaldığı yerden devam (resume) senaryosu
+// Gerçek batch job'larda her doküman için uzun işlem yapılabilir (ör: dış servis çağrısı).
+// MongoDB, 10 dakika boyunca getMore almayan cursor'ları sunucu tarafında kapatır
+// (cursorTimeoutMillis, varsayılan 600000 ms). Sonraki getMore "CursorNotFound" (kod 43) hatası verir
+// ve saatlerce süren bir job ortasında patlar.
+//
+// Bu script iki savunmayı gösterir:
+// 1. noCursorTimeout: Sunucunun idle cursor'ı kapatmasını engeller
+//    (Not: MongoDB 4.4+ da cursor'ın ait olduğu oturum 30 dk boşta kalırsa yine kapanır)
+// 2. Resume: _id sırasıyla oku, son işlenen _id'yi tut; CursorNotFound gelirse
+//    {_id: {$gt: lastID}} ile yeni cursor aç ve kaldığın yerden devam et
+//
+// KULLANIM:
+//   go run main.go config.go analyzer.go antipattern.go errors.go logger.go samples.go sink.go read_resume.go
+//   go run main.go config.go analyzer.go antipattern.go errors.go logger.go samples.go sink.go read_resume.go -limit 50000 -pause-every 10000 -pause 11m
+//
+// 10 dakika beklemeden denemek için sunucunun cursor timeout'unu düşürün (sadece lab ortamında!):
+//   go run main.go config.go analyzer.go antipattern.go errors.go logger.go samples.go sink.go read_resume.go -server-cursor-timeout 5s -pause-every 5000 -pause 8s
+//   go run main.go config.go analyzer.go antipattern.go errors.go logger.go samples.go sink.go read_resume.go -server-cursor-timeout 5s -pause-every 5000 -pause 8s -no-timeout
+func main() {
+	limit := flag.Int("limit", 0, "İşlenecek maksimum doküman sayısı (0 = hepsi)")
+	batchSize := flag.Int("batch", 1000, "Cursor batch size")
+	processDelay := flag.Duration("process-delay", 0, "Her doküman için simüle edilen işlem süresi")
+	pauseEvery := flag.Int("pause-every", 0, "Her N dokümanda bir uzun duraklama yap (0 = kapalı)")
+	pause := flag.Duration("pause", 11*time.Minute, "Uzun duraklamanın süresi (idle timeout'u aşmak için)")
+	noTimeout := flag.Bool("no-timeout", false, "Cursor'ı noCursorTimeout ile aç")
+	serverTimeout := flag.Duration("server-cursor-timeout", 0, "Sunucunun cursorTimeoutMillis değerini geçici olarak değiştir (lab için)")
+	flag.Parse()
+
+	logger, err := NewLogger("read_resume_results.txt")
+	if err != nil {
+		fmt.Printf("Logger oluşturulamadı: %v\n", err)
+		return
+	}
+	defer CloseLogger(logger)
+
+	logger.WriteHeader("read_resume - Cursor Timeout ve Resume")
+
+	col := GetMongo()
+	ctx := context.Background()
+	admin := col.Database().Client().Database("admin")
+
+	// Sunucu cursor timeout'unu geçici olarak düşür, bitince eski değere geri al
+	if *serverTimeout > 0 {
+		var current bson.M
+		err := admin.RunCommand(ctx, bson.D{{Key: "getParameter", Value: 1}, {Key: "cursorTimeoutMillis", Value: 1}}).Decode(&current)
+		if err != nil {
+			logger.Printf("⚠️  cursorTimeoutMillis okunamadı: %v\n", err)
+		} else {
+			original := current["cursorTimeoutMillis"]
+			err := admin.RunCommand(ctx, bson.D{{Key: "setParameter", Value: 1}, {Key: "cursorTimeoutMillis", Value: serverTimeout.Milliseconds()}}).Err()
+			if err != nil {
+				logger.Printf("⚠️  cursorTimeoutMillis değiştirilemedi: %v\n", err)
+			} else {
+				logger.Printf("⚙️  Sunucu cursorTimeoutMillis: %v → %d\n", original, serverTimeout.Milliseconds())
+				defer admin.RunCommand(ctx, bson.D{{Key: "setParameter", Value: 1}, {Key: "cursorTimeoutMillis", Value: original}})
+			}
+		}
+	}
+
+	logger.Printf("📋 Ayarlar: limit=%d batch=%d process-delay=%v pause-every=%d pause=%v noCursorTimeout=%v\n",
+		*limit, *batchSize, *processDelay, *pauseEvery, *pause, *noTimeout)
+
+	// openCursor - lastID'den sonraki en fazla remaining doküman için _id sıralı cursor açar
+	// _id sıralaması _id index'ini kullanır, bu yüzden resume sorgusu da IXSCAN ile hızlıdır
+	openCursor := func(lastID interface{}, remaining int) (*mongo.Cursor, error) {
+		filter := bson.M{}
+		if lastID != nil {
+			filter["_id"] = bson.M{"$gt": lastID}
+		}
+		opts := options.Find().
+			SetSort(bson.D{{Key: "_id", Value: 1}}).
+			SetBatchSize(int32(*batchSize))
+		if remaining > 0 {
+			opts.SetLimit(int64(remaining))
+		}
+		if *noTimeout {
+			opts.SetNoCursorTimeout(true)
+		}
+		return col.Find(ctx, filter, opts)
+	}
+
+	start := time.Now()
+	processed := 0
+	resumes := 0
+	var lastID interface{}
+	var pausedTotal time.Duration
+
+	cursor, err := openCursor(nil, *limit)
+	if HandleError(logger, "find", err) {
+		PrintErrorSummary(logger)
+		return
+	}
+
+	for {
+		for cursor.Next(ctx) {
+			var doc bson.M
+			if err := cursor.Decode(&doc); HandleError(logger, "decode", err) {
+				continue
+			}
+
+			// Uzun işlem simülasyonu (ör: dış API çağrısı, dosya yazma)
+			if *processDelay > 0 {
+				time.Sleep(*processDelay)
+			}
+
+			// Doküman işlendikten SONRA lastID güncellenir - yarıda kalan doküman tekrar işlenir
+			lastID = doc["_id"]
+			processed++
+
+			if *limit > 0 && processed >= *limit {
+				break
+			}
+
+			if *pauseEvery > 0 && processed%*pauseEvery == 0 {
+				logger.Printf("  ⏸️  %d doküman işlendi, %v duraklanıyor...\n", processed, *pause)
+				time.Sleep(*pause)
+				pausedTotal += *pause
+			}
+		}
+
+		err := cursor.Err()
+		cursor.Close(ctx)
+
+		if err == nil || (*limit > 0 && processed >= *limit) {
+			break
+		}
+
+		// CursorNotFound: Sunucu cursor'ı idle timeout yüzünden kapattı → kaldığın yerden devam et
+		if isCursorNotFound(err) {
+			resumes++
+			logger.Printf("  🔄 CursorNotFound (son _id: %v) - resume #%d\n", lastID, resumes)
+
+			// Limit varsa sadece kalan kadar doküman iste
+			remaining := 0
+			if *limit > 0 {
+				remaining = *limit - processed
+			}
+			cursor, err = openCursor(lastID, remaining)
+			if HandleError(logger, "resume find", err) {
+				break
+			}
+			continue
+		}
+
+		// Resume ile kurtarılamayan hata: işlenenlerle raporla
+		HandleError(logger, "cursor", err)
+		break
+	}
+
+	duration := time.Since(start)
+
+	logger.Printf("\n✅ RESUME SONUÇLARI:\n")
+	logger.Printf("📦 İşlenen Doküman: %d\n", processed)
+	logger.Printf("🔄 Resume Sayısı: %d\n", resumes)
+	logger.Printf("⏱️  Toplam Süre: %v (duraklamalar: %v)\n", duration, pausedTotal)
+	if resumes > 0 {
+		logger.Println("💡 Cursor sunucuda timeout'a uğradı ama job kaldığı yerden devam etti - veri kaybı yok.")
+	} else if *pauseEvery > 0 && *noTimeout {
+		logger.Println("💡 noCursorTimeout sayesinde cursor uzun duraklamalara rağmen açık kaldı.")
+	}
+
+	PrintErrorSummary(logger)
+
+	logger.Println("\n✅ Test tamamlandı! Sonuçlar 'read_resume_results.txt' dosyasına kaydedildi.")
+}
+
+// isCursorNotFound - Hatanın CursorNotFound (kod 43) olup olmadığını kontrol eder
+func isCursorNotFound(err error) bool {
+	var serverErr mongo.ServerError
+	if errors.As(err, &serverErr) {
+		return serverErr.HasErrorCode(43)
+	}
+	return false
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// read_topn.go - Sort + limit (top-N) benchmark'ı: "en son 100 PAID sipariş"
+// Sipariş sistemlerindeki en yaygın sorgu: bir filtre + tarihe göre sıralama + küçük limit.
+// Aynı sorgu üç farklı planla çalıştırılır (hint ile zorlanarak):
+// 1. Index yok ({$natural: 1}): COLLSCAN + bellekte SORT - tüm koleksiyon okunur
+// 2. status_1: Filtre index'ten gelir ama sıralama yine bellekte (SORT stage'i)
+// 3. status_1_createdAt_-1: Index zaten createdAt'e göre sıralı - SORT yok,
+//    ilk 100 key okunur ve durulur (keysExamined ≈ limit)
+//
+// Bellekte SORT, limit varsa yalnızca N dokümanı bellekte tutar (top-N sort),
+// ama eşleşen tüm dokümanları yine de okumak zorundadır - maliyet eşleşme sayısıyla büyür.
+//
+// KULLANIM:
+//   go run main.go config.go analyzer.go antipattern.go errors.go logger.go samples.go sink.go iterations.go read_topn.go
+//   go run main.go config.go analyzer.go antipattern.go errors.go logger.go samples.go sink.go iterations.go read_topn.go -status PENDING -limit 20 -runs 50
+
+// topNVariant - Tek bir plan varyantının sonucu
+type topNVariant struct {
+	name      string
+	hint      interface{}
+	plan      PlanSummary
+	durations []time.Duration // Sıralı
+	records   int
+}
+
+func main() {
+	status := flag.String("status", "PAID", "Filtrelenecek status değeri")
+	limit := flag.Int64("limit", 100, "Kaç sipariş getirilecek (N)")
+	runs := flag.Int("runs", 20, "Her varyant için tekrar sayısı")
+	flag.Parse()
+
+	logger, err := NewLogger("read_topn_results.txt")
+	if err != nil {
+		fmt.Printf("Logger oluşturulamadı: %v\n", err)
+		return
+	}
+	defer CloseLogger(logger)
+
+	logger.WriteHeader("read_topn - Sort + Limit (Top-N)")
+
+	col := GetMongo()
+	ctx := context.Background()
+
+	// Karşılaştırılan index'ler - varsa MongoDB hata vermez
+	_, err = col.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "status", Value: 1}}, Options: options.Index().SetName("status_1")},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "createdAt", Value: -1}}, Options: options.Index().SetName("status_1_createdAt_-1")},
+	})
+	if err != nil {
+		logger.Printf("❌ Index'ler oluşturulamadı: %v\n", err)
+		return
+	}
+
+	filter := bson.M{"status": *status}
+	logger.Printf("📋 Sorgu: find(%v).sort({createdAt: -1}).limit(%d), %d tekrar\n", filter, *limit, *runs)
+
+	variants := []*topNVariant{
+		{name: "index yok (COLLSCAN)", hint: bson.D{{Key: "$natural", Value: 1}}},
+		{name: "status_1 (bellekte sort)", hint: "status_1"},
+		{name: "status_1_createdAt_-1", hint: "status_1_createdAt_-1"},
+	}
+
+	for _, v := range variants {
+		logger.Printf("\n▶️  %s\n", v.name)
+
+		findOpts := options.Find().
+			SetSort(bson.D{{Key: "createdAt", Value: -1}}).
+			SetLimit(*limit).
+			SetHint(v.hint)
+
+		explainResult, err := ExplainQuery(col, filter, findOpts)
+		if err != nil {
+			logger.Printf("  ⚠️  Explain hatası: %v\n", err)
+		} else {
+			v.plan = SummarizeExplain(explainResult)
+			CheckExplain(explainResult, "read_topn: "+v.name)
+			sortInfo := "index sıralı (SORT yok)"
+			if v.plan.BlockingSort {
+				sortInfo = "bellekte SORT"
+			}
+			logger.Printf("  🎯 Plan: %s → %s\n", v.plan.StageChain(), sortInfo)
+			logger.Printf("  🔍 incelenen key: %d, incelenen doküman: %d, dönen: %d\n",
+				v.plan.KeysExamined, v.plan.DocsExamined, v.plan.NReturned)
+		}
+
+		WarmUp(logger, v.name, FindPass(col, filter, findOpts))
+		for i := 0; i < *runs; i++ {
+			start := time.Now()
+			cursor, err := col.Find(ctx, filter, findOpts)
+			if HandleError(logger, "find", err) {
+				continue
+			}
+			var orders []bson.M
+			if err := cursor.All(ctx, &orders); HandleError(logger, "cursor.All", err) {
+				continue
+			}
+			v.durations = append(v.durations, time.Since(start))
+			v.records = len(orders)
+		}
+		if len(v.durations) == 0 {
+			logger.Println("  ❌ Hiçbir tekrar başarılı olmadı")
+			PrintErrorSummary(logger)
+			return
+		}
+		sort.Slice(v.durations, func(i, j int) bool { return v.durations[i] < v.durations[j] })
+
+		logger.Printf("  ⏱️  p50 %v, p95 %v, max %v (%d kayıt)\n",
+			Percentile(v.durations, 50), Percentile(v.durations, 95), v.durations[len(v.durations)-1], v.records)
+	}
+
+	// Karşılaştırma - baseline index'siz plan
+	base := Percentile(variants[0].durations, 50)
+	logger.Println("\n=== KARŞILAŞTIRMA ===")
+	logger.Printf("  %-26s %-8s %12s %12s %12s %12s %10s\n",
+		"varyant", "sort", "key", "doküman", "p50", "p95", "hızlanma")
+	for _, v := range variants {
+		sortKind := "index"
+		if v.plan.BlockingSort {
+			sortKind = "SORT"
+		}
+		p50 := Percentile(v.durations, 50)
+		logger.Printf("  %-26s %-8s %12d %12d %12v %12v %9.1fx\n",
+			v.name, sortKind, v.plan.KeysExamined, v.plan.DocsExamined,
+			p50.Round(time.Microsecond), Percentile(v.durations, 95).Round(time.Microsecond),
+			float64(base)/float64(p50))
+	}
+
+	best := variants[len(variants)-1]
+	if len(best.plan.Stages) > 0 && !best.plan.BlockingSort {
+		logger.Printf("\n✅ {status, createdAt} index'i sıralamayı karşılıyor: SORT yok, %d key okundu (limit %d).\n",
+			best.plan.KeysExamined, *limit)
+	} else {
+		logger.Println("\n⚠️  Compound index ile de SORT stage'i görüldü - index alan sırasını/yönünü kontrol edin.")
+	}
+	logger.Println("💡 ESR kuralı: Equality (status) → Sort (createdAt) → Range. Sort alanı equality alanlarından sonra gelmeli.")
+
+	PrintAntiPatterns(logger)
+	PrintErrorSummary(logger)
+
+	logger.Println("\n✅ Test tamamlandı! Sonuçlar 'read_topn_results.txt' dosyasına kaydedildi.")
+}
@@ -3,9 +3,9 @@ package main
 import (
 	"context"
 	"fmt"
-	"runtime"
 	"time"
 
+	"benchkit"
 	"go.mongodb.org/mongo-driver/bson"
 )
 
@@ -23,10 +23,10 @@ func main() {
 		fmt.Printf("Logger oluşturulamadı: %v\n", err)
 		return
 	}
-	defer logger.Close()
-	
+	defer CloseLogger(logger)
+
 	logger.WriteHeader("read_v1 - İYİLEŞTİRME 1 (Cursor Streaming)")
-	
+
 	col := GetMongo()
 	ctx := context.Background()
 
@@ -40,72 +40,86 @@ func main() {
 		PrintExplainResults(explainResult, "read_v1 (Cursor Streaming)", logger)
 	}
 
-	// Performans ölçümü başlat
-	start := time.Now()
-	
-	// Bellek kullanımını ölçmek için başlangıç durumunu al
-	var memBefore runtime.MemStats
-	runtime.GC() // Garbage collection yap ki ölçüm doğru olsun
-	runtime.ReadMemStats(&memBefore)
-
-	// Sorguyu çalıştır
-	// Find: TÜM kayıtları bul (filtre yok)
-	cursor, err := col.Find(ctx, bson.M{}) // Boş filter = tüm kayıtlar
-	if err != nil {
-		panic(err)
-	}
-	defer cursor.Close(ctx) // Cursor'ı kapatmayı unutma (memory leak önleme)
-
-	// İYİLEŞTİRME: cursor.Next() kullan - Streaming okuma
-	// cursor.All() yerine cursor.Next() kullanarak kayıtları tek tek işle
-	// Bu sayede:
-	// - Tüm kayıtlar memory'de beklemek zorunda değil
-	// - İlk kayıtlar hemen işlenebilir
-	// - Bellek kullanımı çok daha düşük
-	recordCount := 0
-	for cursor.Next(ctx) {
-		var result interface{}
-		if err := cursor.Decode(&result); err != nil {
-			panic(err)
+	// Performans ölçümü: -iterations ile tekrarlanır, ısınma turları atılır (bkz. iterations.go)
+	var recordCount int
+	var duration time.Duration
+	var mem benchkit.MemUsage
+	var phases *CursorPhases
+	iterations := NewIterations(logger)
+	for iterations.Next() {
+		start := time.Now()
+
+		// Bellek kullanımını ölçmek için başlangıç durumunu al
+		// GC yapar; ölçüm boyunca heap zirvesi, ayırmalar ve GC sayılır (bkz. benchkit.MemUsage)
+		memProbe := benchkit.StartMemProbe()
+		ResetCursorPhases() // explain/count komutları aşama sürelerine karışmasın
+
+		// Sorguyu çalıştır
+		// Find: TÜM kayıtları bul (filtre yok)
+		cursor, err := col.Find(ctx, bson.M{}) // Boş filter = tüm kayıtlar
+		if HandleError(logger, "find", err) {
+			// Sorgu açılamadı: ölçülecek bir şey yok, hata özetini yazıp çık
+			PrintErrorSummary(logger)
+			return
 		}
-		
-		// Burada kayıt işlenebilir (örneğin: hesaplama, yazdırma, başka DB'ye kaydetme vb.)
-		// Şu an sadece sayıyoruz, ama gerçek uygulamada burada işlem yapılır
-		recordCount++
-		
-		// Her 100k kayıtta bir ilerleme göster (opsiyonel)
-		if recordCount%100000 == 0 {
-			logger.Printf("  📊 İşlenen kayıt: %d\n", recordCount)
+
+		// İYİLEŞTİRME: cursor.Next() kullan - Streaming okuma
+		// cursor.All() yerine cursor.Next() kullanarak kayıtları tek tek işle
+		// Bu sayede:
+		// - Tüm kayıtlar memory'de beklemek zorunda değil
+		// - İlk kayıtlar hemen işlenebilir
+		// - Bellek kullanımı çok daha düşük
+		recordCount = 0
+		var decodeTime time.Duration // Client tarafında decode için harcanan süre
+		for cursor.Next(ctx) {
+			var result interface{}
+			decodeStart := time.Now()
+			err := cursor.Decode(&result)
+			decodeTime += time.Since(decodeStart)
+			if HandleError(logger, "decode", err) {
+				continue // Bozuk doküman sayılır ve atlanır, okuma devam eder
+			}
+
+			// Burada kayıt işlenebilir (örneğin: hesaplama, yazdırma, başka DB'ye kaydetme vb.)
+			// Şu an sadece sayıyoruz, ama gerçek uygulamada burada işlem yapılır
+			recordCount++
+
+			// Her 100k kayıtta bir ilerleme göster (opsiyonel)
+			if recordCount%100000 == 0 {
+				logger.Printf("  📊 İşlenen kayıt: %d\n", recordCount)
+			}
 		}
-	}
 
-	// Cursor'dan hata var mı kontrol et
-	if err := cursor.Err(); err != nil {
-		panic(err)
-	}
+		// Cursor'dan hata var mı kontrol et
+		// Kısmi sonuçlarla devam edilir, hata özette raporlanır
+		HandleError(logger, "cursor", cursor.Err())
+		cursor.Close(ctx) // Cursor'ı kapatmayı unutma (memory leak önleme)
 
-	// Bellek kullanımını ölçmek için bitiş durumunu al
-	var memAfter runtime.MemStats
-	runtime.ReadMemStats(&memAfter)
-	memoryUsed := int64(memAfter.Alloc - memBefore.Alloc)
+		// Bellek kullanımını ölçmek için bitiş durumunu al
+		mem = memProbe.Stop()
 
-	duration := time.Since(start)
+		duration = time.Since(start)
+		phases = SnapshotCursorPhases(decodeTime)
+		iterations.Record(duration, mem.PeakGrowth)
+	}
 
 	// Sonuçları göster
 	logger.Printf("\n✅ İYİLEŞTİRME 1 SONUÇLARI (Cursor Streaming):\n")
 	logger.Printf("📦 Okunan Kayıt: %d\n", recordCount)
 	logger.Printf("⏱️  Süre: %v\n", duration)
-	logger.Printf("💾 Bellek Kullanımı: %.2f MB\n", float64(memoryUsed)/(1024*1024))
-	
+	logger.Printf("💾 Bellek Kullanımı: %.2f MB\n", float64(mem.PeakGrowth)/(1024*1024))
+
 	// Execution stats'i parse et ve göster
 	if explainResult != nil {
 		if execStats, ok := explainResult["executionStats"].(map[string]interface{}); ok {
 			metrics := QueryMetrics{
 				Duration:    duration,
 				RecordsRead: recordCount,
-				MemoryUsed:  memoryUsed,
+				Phases:      phases,
+				Iterations:  iterations.Stats(),
 			}
-			
+			metrics.SetMemory(mem)
+
 			// Execution stats'i parse et
 			if execTime, ok := execStats["executionTimeMillis"].(int64); ok {
 				metrics.ExecutionStats = &ExecutionStats{
@@ -130,11 +144,10 @@ func main() {
 				}
 				metrics.ExecutionStats.NReturned = nReturned
 			}
-			
+
 			PrintMetrics(metrics, "read_v1", logger)
 		}
 	}
-	
+
 	logger.Println("\n✅ Test tamamlandı! Sonuçlar 'read_v1_results.txt' dosyasına kaydedildi.")
 }
-
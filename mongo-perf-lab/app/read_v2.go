@@ -3,9 +3,9 @@ package main
 import (
 	"context"
 	"fmt"
-	"runtime"
 	"time"
 
+	"benchkit"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
@@ -24,10 +24,10 @@ func main() {
 		fmt.Printf("Logger oluşturulamadı: %v\n", err)
 		return
 	}
-	defer logger.Close()
-	
+	defer CloseLogger(logger)
+
 	logger.WriteHeader("read_v2 - İYİLEŞTİRME 2 (Projection + Batch)")
-	
+
 	col := GetMongo()
 	ctx := context.Background()
 
@@ -35,9 +35,9 @@ func main() {
 	// Bu örnekte sadece userId ve status alanlarını getiriyoruz
 	// items, createdAt gibi alanlar getirilmez (network ve bellek tasarrufu)
 	projection := bson.M{
-		"userId": 1,  // userId alanını getir
-		"status": 1,  // status alanını getir
-		"_id":    0,  // _id alanını getirme (opsiyonel, 0 = getirme)
+		"userId": 1, // userId alanını getir
+		"status": 1, // status alanını getir
+		"_id":    0, // _id alanını getirme (opsiyonel, 0 = getirme)
 	}
 
 	// Batch Size: Her seferde kaç kayıt getirileceğini belirle
@@ -57,69 +57,83 @@ func main() {
 		PrintExplainResults(explainResult, "read_v2 (Projection + Batch)", logger)
 	}
 
-	// Performans ölçümü başlat
-	start := time.Now()
-	
-	// Bellek kullanımını ölçmek için başlangıç durumunu al
-	var memBefore runtime.MemStats
-	runtime.GC()
-	runtime.ReadMemStats(&memBefore)
-
-	// Sorguyu çalıştır - Projection ve batch size ile
-	// TÜM kayıtları oku (filtre yok)
-	cursor, err := col.Find(ctx, bson.M{}, findOpts) // Boş filter = tüm kayıtlar
-	if err != nil {
-		panic(err)
-	}
-	defer cursor.Close(ctx)
-
-	// Streaming okuma (v1'deki gibi)
-	recordCount := 0
-	for cursor.Next(ctx) {
-		// Projection sayesinde sadece userId ve status alanları var
-		var result bson.M
-		if err := cursor.Decode(&result); err != nil {
-			panic(err)
+	// Performans ölçümü: -iterations ile tekrarlanır, ısınma turları atılır (bkz. iterations.go)
+	var recordCount int
+	var duration time.Duration
+	var mem benchkit.MemUsage
+	var phases *CursorPhases
+	iterations := NewIterations(logger)
+	for iterations.Next() {
+		start := time.Now()
+
+		// Bellek kullanımını ölçmek için başlangıç durumunu al
+		// GC yapar; ölçüm boyunca heap zirvesi, ayırmalar ve GC sayılır (bkz. benchkit.MemUsage)
+		memProbe := benchkit.StartMemProbe()
+		ResetCursorPhases() // explain/count komutları aşama sürelerine karışmasın
+
+		// Sorguyu çalıştır - Projection ve batch size ile
+		// TÜM kayıtları oku (filtre yok)
+		cursor, err := col.Find(ctx, bson.M{}, findOpts) // Boş filter = tüm kayıtlar
+		if HandleError(logger, "find", err) {
+			// Sorgu açılamadı: ölçülecek bir şey yok, hata özetini yazıp çık
+			PrintErrorSummary(logger)
+			return
 		}
-		
-		// Burada sadece gerekli alanlar var, bu yüzden işlem daha hızlı
-		// Örnek: result["userId"] ve result["status"] kullanılabilir
-		_ = result // Şu an kullanmıyoruz, sadece decode ediyoruz
-		
-		recordCount++
-		
-		if recordCount%100000 == 0 {
-			logger.Printf("  📊 İşlenen kayıt: %d\n", recordCount)
+
+		// Streaming okuma (v1'deki gibi)
+		recordCount = 0
+		var decodeTime time.Duration // Client tarafında decode için harcanan süre
+		for cursor.Next(ctx) {
+			// Projection sayesinde sadece userId ve status alanları var
+			var result bson.M
+			decodeStart := time.Now()
+			err := cursor.Decode(&result)
+			decodeTime += time.Since(decodeStart)
+			if HandleError(logger, "decode", err) {
+				continue // Bozuk doküman sayılır ve atlanır, okuma devam eder
+			}
+
+			// Burada sadece gerekli alanlar var, bu yüzden işlem daha hızlı
+			// Örnek: result["userId"] ve result["status"] kullanılabilir
+			_ = result // Şu an kullanmıyoruz, sadece decode ediyoruz
+
+			recordCount++
+
+			if recordCount%100000 == 0 {
+				logger.Printf("  📊 İşlenen kayıt: %d\n", recordCount)
+			}
 		}
-	}
 
-	if err := cursor.Err(); err != nil {
-		panic(err)
-	}
+		// Kısmi sonuçlarla devam edilir, hata özette raporlanır
+		HandleError(logger, "cursor", cursor.Err())
+		cursor.Close(ctx)
 
-	// Bellek kullanımını ölç
-	var memAfter runtime.MemStats
-	runtime.ReadMemStats(&memAfter)
-	memoryUsed := int64(memAfter.Alloc - memBefore.Alloc)
+		// Bellek kullanımını ölç
+		mem = memProbe.Stop()
 
-	duration := time.Since(start)
+		duration = time.Since(start)
+		phases = SnapshotCursorPhases(decodeTime)
+		iterations.Record(duration, mem.PeakGrowth)
+	}
 
 	// Sonuçları göster
 	logger.Printf("\n✅ İYİLEŞTİRME 2 SONUÇLARI (Projection + Batch):\n")
 	logger.Printf("📦 Okunan Kayıt: %d\n", recordCount)
 	logger.Printf("⏱️  Süre: %v\n", duration)
-	logger.Printf("💾 Bellek Kullanımı: %.2f MB\n", float64(memoryUsed)/(1024*1024))
+	logger.Printf("💾 Bellek Kullanımı: %.2f MB\n", float64(mem.PeakGrowth)/(1024*1024))
 	logger.Printf("📉 Projection sayesinde daha az veri transfer edildi!\n")
-	
+
 	// Execution stats'i parse et ve göster
 	if explainResult != nil {
 		if execStats, ok := explainResult["executionStats"].(map[string]interface{}); ok {
 			metrics := QueryMetrics{
 				Duration:    duration,
 				RecordsRead: recordCount,
-				MemoryUsed:  memoryUsed,
+				Phases:      phases,
+				Iterations:  iterations.Stats(),
 			}
-			
+			metrics.SetMemory(mem)
+
 			if execTime, ok := execStats["executionTimeMillis"].(int64); ok {
 				metrics.ExecutionStats = &ExecutionStats{
 					ExecutionTimeMillis: execTime,
@@ -143,11 +157,10 @@ func main() {
 				}
 				metrics.ExecutionStats.NReturned = nReturned
 			}
-			
+
 			PrintMetrics(metrics, "read_v2", logger)
 		}
 	}
-	
+
 	logger.Println("\n✅ Test tamamlandı! Sonuçlar 'read_v2_results.txt' dosyasına kaydedildi.")
 }
-
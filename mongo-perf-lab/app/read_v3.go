@@ -3,9 +3,9 @@ package main
 import (
 	"context"
 	"fmt"
-	"runtime"
 	"time"
 
+	"benchkit"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
@@ -13,7 +13,7 @@ import (
 // read_v3.go - İYİLEŞTİRME 3: Aggregation Pipeline + Index Optimizasyonu
 // Bu versiyon, aggregation pipeline kullanır ve index optimizasyonu yapar
 // ÖNEMLİ: Bu versiyon çalışmadan önce index oluşturulmalı!
-// Index oluşturmak için: go run main.go create_index.go
+// Index oluşturmak için: go run main.go config.go create_index.go
 //
 // Avantajları:
 // 1. Aggregation pipeline kullanımı (MongoDB tarafında işleme)
@@ -27,10 +27,10 @@ func main() {
 		fmt.Printf("Logger oluşturulamadı: %v\n", err)
 		return
 	}
-	defer logger.Close()
-	
+	defer CloseLogger(logger)
+
 	logger.WriteHeader("read_v3 - İYİLEŞTİRME 3 (Index Optimized)")
-	
+
 	col := GetMongo()
 	ctx := context.Background()
 
@@ -46,16 +46,16 @@ func main() {
 		},
 		{
 			"$project": bson.M{
-				"userId": 1,  // Sadece bu alanları getir
+				"userId": 1, // Sadece bu alanları getir
 				"status": 1,
-				"_id":    0,  // _id'yi getirme
+				"_id":    0, // _id'yi getirme
 			},
 		},
 	}
 
 	// Explain için aggregation explain komutu
 	logger.Println("🔍 Aggregation pipeline analizi yapılıyor (explain with $match)...")
-	
+
 	// Aggregation explain komutu
 	var explainResult map[string]interface{}
 	err = col.Database().RunCommand(ctx, bson.D{
@@ -66,12 +66,12 @@ func main() {
 		}},
 		{Key: "verbosity", Value: "executionStats"},
 	}).Decode(&explainResult)
-	
+
 	if err != nil {
 		logger.Printf("⚠️  Explain hatası: %v\n", err)
 	} else {
 		PrintExplainResults(explainResult, "read_v3 (Aggregation + Index)", logger)
-		
+
 		// Index kullanılıyor mu kontrol et
 		// $match stage'i index kullanabilir
 		if stages, ok := explainResult["stages"].([]interface{}); ok {
@@ -82,7 +82,7 @@ func main() {
 							logger.Println("✅ Index kullanılıyor (IXSCAN) - İyi!")
 						} else if stageName == "COLLSCAN" {
 							logger.Println("⚠️  UYARI: Collection scan tespit edildi - Index oluşturun!")
-							logger.Println("   go run main.go create_index.go")
+							logger.Println("   go run main.go config.go create_index.go")
 						}
 					}
 				}
@@ -90,63 +90,77 @@ func main() {
 		}
 	}
 
-	// Performans ölçümü başlat
-	start := time.Now()
-	
-	var memBefore runtime.MemStats
-	runtime.GC()
-	runtime.ReadMemStats(&memBefore)
-
-	// Aggregation pipeline'ı çalıştır
-	// Aggregation, MongoDB'de veri işleme için en güçlü yöntemdir
-	// $match stage'i index kullanabilir, bu çok hızlıdır
-	cursor, err := col.Aggregate(ctx, pipeline, options.Aggregate().SetBatchSize(1000))
-	if err != nil {
-		panic(err)
-	}
-	defer cursor.Close(ctx)
-
-	// Streaming okuma
-	recordCount := 0
-	for cursor.Next(ctx) {
-		var result bson.M
-		if err := cursor.Decode(&result); err != nil {
-			panic(err)
+	// Performans ölçümü: -iterations ile tekrarlanır, ısınma turları atılır (bkz. iterations.go)
+	var recordCount int
+	var duration time.Duration
+	var mem benchkit.MemUsage
+	var phases *CursorPhases
+	iterations := NewIterations(logger)
+	for iterations.Next() {
+		start := time.Now()
+
+		// GC yapar; ölçüm boyunca heap zirvesi, ayırmalar ve GC sayılır (bkz. benchkit.MemUsage)
+		memProbe := benchkit.StartMemProbe()
+		ResetCursorPhases() // explain/count komutları aşama sürelerine karışmasın
+
+		// Aggregation pipeline'ı çalıştır
+		// Aggregation, MongoDB'de veri işleme için en güçlü yöntemdir
+		// $match stage'i index kullanabilir, bu çok hızlıdır
+		cursor, err := col.Aggregate(ctx, pipeline, options.Aggregate().SetBatchSize(1000))
+		if HandleError(logger, "aggregate", err) {
+			// Sorgu açılamadı: ölçülecek bir şey yok, hata özetini yazıp çık
+			PrintErrorSummary(logger)
+			return
 		}
-		
-		_ = result
-		recordCount++
-		
-		if recordCount%100000 == 0 {
-			logger.Printf("  📊 İşlenen kayıt: %d\n", recordCount)
+
+		// Streaming okuma
+		recordCount = 0
+		var decodeTime time.Duration // Client tarafında decode için harcanan süre
+		for cursor.Next(ctx) {
+			var result bson.M
+			decodeStart := time.Now()
+			err := cursor.Decode(&result)
+			decodeTime += time.Since(decodeStart)
+			if HandleError(logger, "decode", err) {
+				continue // Bozuk doküman sayılır ve atlanır, okuma devam eder
+			}
+
+			_ = result
+			recordCount++
+
+			if recordCount%100000 == 0 {
+				logger.Printf("  📊 İşlenen kayıt: %d\n", recordCount)
+			}
 		}
-	}
 
-	if err := cursor.Err(); err != nil {
-		panic(err)
-	}
+		// Kısmi sonuçlarla devam edilir, hata özette raporlanır
+		HandleError(logger, "cursor", cursor.Err())
+		cursor.Close(ctx)
 
-	var memAfter runtime.MemStats
-	runtime.ReadMemStats(&memAfter)
-	memoryUsed := int64(memAfter.Alloc - memBefore.Alloc)
+		mem = memProbe.Stop()
 
-	duration := time.Since(start)
+		duration = time.Since(start)
+		phases = SnapshotCursorPhases(decodeTime)
+		iterations.Record(duration, mem.PeakGrowth)
+	}
 
 	logger.Printf("\n✅ İYİLEŞTİRME 3 SONUÇLARI (Aggregation + Index):\n")
 	logger.Printf("📦 Okunan Kayıt: %d\n", recordCount)
 	logger.Printf("⏱️  Süre: %v\n", duration)
-	logger.Printf("💾 Bellek Kullanımı: %.2f MB\n", float64(memoryUsed)/(1024*1024))
+	logger.Printf("💾 Bellek Kullanımı: %.2f MB\n", float64(mem.PeakGrowth)/(1024*1024))
 	logger.Printf("🚀 Aggregation pipeline + Index kullanımı sayesinde çok daha hızlı!\n")
 	logger.Printf("📊 $match stage'i index kullanarak sadece ilgili kayıtları getirdi\n")
-	
+
 	if explainResult != nil {
 		if execStats, ok := explainResult["executionStats"].(map[string]interface{}); ok {
 			metrics := QueryMetrics{
 				Duration:    duration,
 				RecordsRead: recordCount,
-				MemoryUsed:  memoryUsed,
+				Phases:      phases,
+				Iterations:  iterations.Stats(),
 			}
-			
+			metrics.SetMemory(mem)
+
 			if execTime, ok := execStats["executionTimeMillis"].(int64); ok {
 				metrics.ExecutionStats = &ExecutionStats{
 					ExecutionTimeMillis: execTime,
@@ -170,11 +184,10 @@ func main() {
 				}
 				metrics.ExecutionStats.NReturned = nReturned
 			}
-			
+
 			PrintMetrics(metrics, "read_v3", logger)
 		}
 	}
-	
+
 	logger.Println("\n✅ Test tamamlandı! Sonuçlar 'read_v3_results.txt' dosyasına kaydedildi.")
 }
-
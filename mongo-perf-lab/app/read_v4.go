@@ -2,13 +2,15 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
-	"runtime"
 	"sync"
 	"sync/atomic"
 	"time"
 
+	"benchkit"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
@@ -25,17 +27,29 @@ import (
 // Dikkat:
 // - MongoDB connection pool size'ı yeterli olmalı
 // - Çok fazla goroutine memory kullanımını artırabilir
+//
+// KULLANIM:
+//
+//	go run main.go config.go analyzer.go antipattern.go errors.go logger.go samples.go sink.go iterations.go flags.go chunktune.go read_v4.go
+//	... read_v4.go -workers 16                       (chunk boyutu kalibrasyonla seçilir)
+//	... read_v4.go -workers 10 -chunk-size 100000    (eski sabit değer, kalibrasyon yok)
 func main() {
+	workersFlag := flag.Int("workers", 10, "Paralel aggregation worker sayısı")
+	chunkSizeFlag := flag.Int64("chunk-size", 0, "Bir aggregate'in okuduğu kayıt sayısı (0: ölçümden önce kalibrasyonla seç)")
+	candidatesFlag := flag.String("chunk-candidates", "", "Kalibrasyonda denenecek chunk boyutları (boş: worker başına pay ve 1/2, 1/4, 1/8'i)")
+	probeFlag := flag.Int64("chunk-probe", 100000, "Kalibrasyonda aday başına okunacak en fazla doküman")
+	flag.Parse()
+
 	// Logger oluştur
 	logger, err := NewLogger("read_v4_results.txt")
 	if err != nil {
 		fmt.Printf("Logger oluşturulamadı: %v\n", err)
 		return
 	}
-	defer logger.Close()
-	
+	defer CloseLogger(logger)
+
 	logger.WriteHeader("read_v4 - İYİLEŞTİRME 4 (Parallel Reading)")
-	
+
 	col := GetMongo()
 	ctx := context.Background()
 
@@ -51,23 +65,46 @@ func main() {
 		},
 		{
 			"$project": bson.M{
-				"userId": 1,  // Sadece bu alanları getir
+				"userId": 1, // Sadece bu alanları getir
 				"status": 1,
-				"_id":    0,  // _id'yi getirme
+				"_id":    0, // _id'yi getirme
 			},
 		},
 	}
 
 	// Önce eşleşen kayıt sayısını bul
 	totalCount, err := col.CountDocuments(ctx, bson.M{"status": "PAID"})
-	if err != nil {
-		panic(err)
+	if HandleError(logger, "count", err) {
+		// Sorgu açılamadı: ölçülecek bir şey yok, hata özetini yazıp çık
+		PrintErrorSummary(logger)
+		return
 	}
 	logger.Printf("📊 Eşleşen kayıt sayısı (status='PAID'): %d\n", totalCount)
 
 	// Paralel okuma için ayarlar
-	numWorkers := 10        // Kaç goroutine paralel çalışacak
-	chunkSize := int64(100000) // Her chunk'ta kaç kayıt olacak
+	// Worker'lar chunk'ları sırayla çeker (chunk k = $skip k*chunkSize), böylece kayıt sayısı
+	// worker × chunk'tan büyük olsa da tamamı okunur. -chunk-size verilmezse boyut ölçümden önce
+	// kısa kalibrasyon turlarıyla seçilir (bkz. chunktune.go)
+	numWorkers := *workersFlag // Kaç goroutine paralel çalışacak
+	var tuning *ChunkTuning
+	if *chunkSizeFlag > 0 {
+		tuning = &ChunkTuning{Workers: numWorkers, Chosen: *chunkSizeFlag, Fixed: true}
+	} else {
+		candidates, err := ChunkCandidates(*candidatesFlag, totalCount, numWorkers)
+		if err != nil {
+			logger.Printf("❌ -chunk-candidates: %v\n", err)
+			return
+		}
+		tuning = TuneChunkSize(logger, numWorkers, *probeFlag, candidates, func(chunkSize, limit int64) (int64, error) {
+			errorsBefore := ErrorCount()
+			read, _ := readChunks(ctx, col, logger, numWorkers, chunkSize, totalCount, limit, false)
+			if n := ErrorCount() - errorsBefore; n > 0 {
+				return read, fmt.Errorf("%d hata", n)
+			}
+			return read, nil
+		})
+	}
+	chunkSize := tuning.Chosen // Her chunk'ta kaç kayıt olacak
 
 	// Explain çalıştır - Aggregation pipeline için
 	logger.Println("🔍 Aggregation pipeline analizi yapılıyor...")
@@ -80,121 +117,56 @@ func main() {
 		}},
 		{Key: "verbosity", Value: "executionStats"},
 	}).Decode(&explainResult)
-	
+
 	if err != nil {
 		logger.Printf("⚠️  Explain hatası: %v\n", err)
 	} else {
 		PrintExplainResults(explainResult, "read_v4 (Parallel Aggregation)", logger)
 	}
 
-	// Performans ölçümü başlat
-	start := time.Now()
-	
-	var memBefore runtime.MemStats
-	runtime.GC()
-	runtime.ReadMemStats(&memBefore)
-
-	// Paralel okuma için channel ve wait group
-	var wg sync.WaitGroup
-	var totalRead int64 // Atomic counter for thread-safe counting
-
-	// Her worker için goroutine başlat
-	for i := 0; i < numWorkers; i++ {
-		wg.Add(1)
-		go func(workerID int) {
-			defer wg.Done()
-
-			// Bu worker'ın okuyacağı chunk'ı hesapla
-			skip := int64(workerID) * chunkSize
-			
-			// Eğer skip, toplam kayıt sayısından büyükse, bu worker'a iş yok
-			if skip >= totalCount {
-				return
-			}
+	// Performans ölçümü: -iterations ile tekrarlanır, ısınma turları atılır (bkz. iterations.go)
+	var totalRead int64
+	var duration time.Duration
+	var mem benchkit.MemUsage
+	var phases *CursorPhases
+	iterations := NewIterations(logger)
+	for iterations.Next() {
+		start := time.Now()
 
-			// Bu chunk için aggregation pipeline oluştur
-			// $match: Filtreleme (index kullanabilir)
-			// $skip: skip kadar kayıt atla
-			// $limit: chunkSize kadar kayıt getir
-			// $project: Sadece gerekli alanları getir
-			chunkPipeline := []bson.M{
-				{
-					"$match": bson.M{
-						"status": "PAID", // Filtreleme - index kullanılabilir
-					},
-				},
-				{
-					"$skip": skip, // skip kadar kayıt atla
-				},
-				{
-					"$limit": chunkSize, // chunkSize kadar kayıt getir
-				},
-				{
-					"$project": bson.M{
-						"userId": 1,
-						"status": 1,
-						"_id":    0,
-					},
-				},
-			}
+		// GC yapar; ölçüm boyunca heap zirvesi, ayırmalar ve GC sayılır (bkz. benchkit.MemUsage)
+		memProbe := benchkit.StartMemProbe()
+		ResetCursorPhases() // explain/count komutları aşama sürelerine karışmasın
 
-			// Aggregation pipeline'ı çalıştır
-			cursor, err := col.Aggregate(ctx, chunkPipeline, options.Aggregate().SetBatchSize(1000))
-			if err != nil {
-				logger.Printf("⚠️  Worker %d hatası: %v\n", workerID, err)
-				return
-			}
-			defer cursor.Close(ctx)
+		read, decode := readChunks(ctx, col, logger, numWorkers, chunkSize, totalCount, 0, true)
+		totalRead = read
 
-			// Bu chunk'ı oku
-			localCount := 0
-			for cursor.Next(ctx) {
-				var result bson.M
-				if err := cursor.Decode(&result); err != nil {
-					logger.Printf("⚠️  Worker %d decode hatası: %v\n", workerID, err)
-					continue
-				}
-				
-				_ = result
-				localCount++
-			}
+		mem = memProbe.Stop()
 
-			if err := cursor.Err(); err != nil {
-				logger.Printf("⚠️  Worker %d cursor hatası: %v\n", workerID, err)
-			}
-
-			// Toplam sayacı güncelle (thread-safe)
-			atomic.AddInt64(&totalRead, int64(localCount))
-			
-			logger.Printf("  ✅ Worker %d tamamlandı: %d kayıt okundu\n", workerID, localCount)
-		}(i)
+		duration = time.Since(start)
+		phases = SnapshotCursorPhases(decode)
+		iterations.Record(duration, mem.PeakGrowth)
 	}
 
-	// Tüm worker'ların bitmesini bekle
-	wg.Wait()
-
-	var memAfter runtime.MemStats
-	runtime.ReadMemStats(&memAfter)
-	memoryUsed := int64(memAfter.Alloc - memBefore.Alloc)
-
-	duration := time.Since(start)
-
 	logger.Printf("\n✅ İYİLEŞTİRME 4 SONUÇLARI (Parallel Aggregation):\n")
 	logger.Printf("📦 Okunan Kayıt: %d\n", totalRead)
 	logger.Printf("⏱️  Süre: %v\n", duration)
-	logger.Printf("💾 Bellek Kullanımı: %.2f MB\n", float64(memoryUsed)/(1024*1024))
+	logger.Printf("💾 Bellek Kullanımı: %.2f MB\n", float64(mem.PeakGrowth)/(1024*1024))
 	logger.Printf("🚀 Paralel aggregation pipeline sayesinde daha hızlı!\n")
 	logger.Printf("👥 Worker sayısı: %d\n", numWorkers)
+	logger.Printf("🧩 Chunk boyutu: %s\n", tuning)
 	logger.Printf("📊 Her worker ayrı aggregation pipeline çalıştırdı ($match + $project)\n")
-	
+
 	if explainResult != nil {
 		if execStats, ok := explainResult["executionStats"].(map[string]interface{}); ok {
 			metrics := QueryMetrics{
 				Duration:    duration,
 				RecordsRead: int(totalRead),
-				MemoryUsed:  memoryUsed,
+				Phases:      phases,
+				Iterations:  iterations.Stats(),
+				ChunkTuning: tuning,
 			}
-			
+			metrics.SetMemory(mem)
+
 			if execTime, ok := execStats["executionTimeMillis"].(int64); ok {
 				metrics.ExecutionStats = &ExecutionStats{
 					ExecutionTimeMillis: execTime,
@@ -218,11 +190,110 @@ func main() {
 				}
 				metrics.ExecutionStats.NReturned = nReturned
 			}
-			
+
 			PrintMetrics(metrics, "read_v4", logger)
 		}
 	}
-	
+
 	logger.Println("\n✅ Test tamamlandı! Sonuçlar 'read_v4_results.txt' dosyasına kaydedildi.")
 }
 
+// readChunks - status=PAID kayıtlarını workers goroutine'iyle chunkSize'lık parçalar halinde okur
+// Her worker sıradaki chunk'ı alır, $skip/$limit'li aggregate'i çalıştırır ve chunk bitince
+// bir sonrakine geçer. limit > 0 ise toplam limit doküman okununca durulur (kalibrasyon turu).
+// verbose: Worker başına özet satırı yazılır (ölçülen tur)
+// Döndürür: okunan kayıt ve tüm worker'ların decode süresi toplamı
+func readChunks(ctx context.Context, col *mongo.Collection, logger *Logger, workers int, chunkSize, totalCount, limit int64, verbose bool) (int64, time.Duration) {
+	var wg sync.WaitGroup
+	var totalRead int64        // Atomic counter for thread-safe counting
+	var totalDecodeNanos int64 // Tüm worker'ların decode süresi toplamı (atomic)
+	var nextChunk int64        // Sıradaki chunk'ın numarası (atomic)
+	var probed int64           // limit > 0 iken okunan doküman (atomic)
+
+	// Her worker için goroutine başlat
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(workerID int) {
+			defer wg.Done()
+			localCount := 0
+			chunks := 0
+			var localDecode time.Duration
+
+		chunkLoop:
+			for {
+				// Bu worker'ın okuyacağı chunk'ı al
+				// Eğer skip, toplam kayıt sayısından büyükse, okunacak chunk kalmadı
+				skip := (atomic.AddInt64(&nextChunk, 1) - 1) * chunkSize
+				if skip >= totalCount || (limit > 0 && atomic.LoadInt64(&probed) >= limit) {
+					break
+				}
+
+				// Bu chunk için aggregation pipeline oluştur
+				// $match: Filtreleme (index kullanabilir)
+				// $skip: skip kadar kayıt atla
+				// $limit: chunkSize kadar kayıt getir
+				// $project: Sadece gerekli alanları getir
+				chunkPipeline := []bson.M{
+					{
+						"$match": bson.M{
+							"status": "PAID", // Filtreleme - index kullanılabilir
+						},
+					},
+					{
+						"$skip": skip, // skip kadar kayıt atla
+					},
+					{
+						"$limit": chunkSize, // chunkSize kadar kayıt getir
+					},
+					{
+						"$project": bson.M{
+							"userId": 1,
+							"status": 1,
+							"_id":    0,
+						},
+					},
+				}
+
+				// Aggregation pipeline'ı çalıştır
+				cursor, err := col.Aggregate(ctx, chunkPipeline, options.Aggregate().SetBatchSize(1000))
+				if HandleError(logger, fmt.Sprintf("worker %d aggregate", workerID), err) {
+					break
+				}
+				chunks++
+
+				// Bu chunk'ı oku
+				for cursor.Next(ctx) {
+					if limit > 0 && atomic.AddInt64(&probed, 1) > limit {
+						cursor.Close(ctx)
+						break chunkLoop
+					}
+					var result bson.M
+					decodeStart := time.Now()
+					err := cursor.Decode(&result)
+					localDecode += time.Since(decodeStart)
+					if HandleError(logger, "decode", err) {
+						continue
+					}
+
+					_ = result
+					localCount++
+				}
+
+				HandleError(logger, fmt.Sprintf("worker %d cursor", workerID), cursor.Err())
+				cursor.Close(ctx)
+			}
+
+			// Toplam sayacı güncelle (thread-safe)
+			atomic.AddInt64(&totalRead, int64(localCount))
+			atomic.AddInt64(&totalDecodeNanos, int64(localDecode))
+
+			if verbose {
+				logger.Printf("  ✅ Worker %d tamamlandı: %d chunk, %d kayıt okundu\n", workerID, chunks, localCount)
+			}
+		}(i)
+	}
+
+	// Tüm worker'ların bitmesini bekle
+	wg.Wait()
+	return totalRead, time.Duration(totalDecodeNanos)
+}
@@ -3,9 +3,9 @@ package main
 import (
 	"context"
 	"fmt"
-	"runtime"
 	"time"
 
+	"benchkit"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
@@ -27,10 +27,10 @@ func main() {
 		fmt.Printf("Logger oluşturulamadı: %v\n", err)
 		return
 	}
-	defer logger.Close()
-	
+	defer CloseLogger(logger)
+
 	logger.WriteHeader("read_v5 - İYİLEŞTİRME 5 (Aggregation Pipeline)")
-	
+
 	col := GetMongo()
 	ctx := context.Background()
 
@@ -38,7 +38,7 @@ func main() {
 	// Pipeline stage'leri sırayla çalışır:
 	// 1. $match: Filtreleme - index kullanabilir (status="PAID" için index var)
 	// 2. $project: Sadece gerekli alanları getir
-	// 
+	//
 	// Aggregation pipeline'ın avantajları:
 	// - $match stage'i index kullanabilir (IXSCAN) - çok hızlı!
 	// - $project stage'i sadece gerekli alanları getirir - network trafiği azalır
@@ -51,9 +51,9 @@ func main() {
 		},
 		{
 			"$project": bson.M{
-				"userId": 1,  // Sadece bu alanları getir
+				"userId": 1, // Sadece bu alanları getir
 				"status": 1,
-				"_id":    0,  // _id'yi getirme
+				"_id":    0, // _id'yi getirme
 			},
 		},
 	}
@@ -61,7 +61,7 @@ func main() {
 	// Explain için aggregation explain komutu
 	// $match stage'i index kullanabilir, bu çok önemli!
 	logger.Println("🔍 Aggregation pipeline analizi yapılıyor (explain with $match)...")
-	
+
 	// Aggregation explain komutu
 	var explainResult map[string]interface{}
 	// err zaten tanımlı (logger oluştururken), bu yüzden := yerine = kullanıyoruz
@@ -73,62 +73,74 @@ func main() {
 		}},
 		{Key: "verbosity", Value: "executionStats"},
 	}).Decode(&explainResult)
-	
+
 	if err != nil {
 		logger.Printf("⚠️  Explain hatası: %v\n", err)
 	} else {
 		PrintExplainResults(explainResult, "read_v5 (Aggregation Pipeline)", logger)
 	}
 
-	// Performans ölçümü başlat
-	start := time.Now()
-	
-	var memBefore runtime.MemStats
-	runtime.GC()
-	runtime.ReadMemStats(&memBefore)
-
-	// Aggregation pipeline'ı çalıştır
-	// Aggregation, MongoDB'de veri işleme için en güçlü yöntemdir
-	// Veri işleme MongoDB tarafında yapılır, sadece sonuçlar gelir
-	cursor, err := col.Aggregate(ctx, pipeline, options.Aggregate().SetBatchSize(1000))
-	if err != nil {
-		panic(err)
-	}
-	defer cursor.Close(ctx)
-
-	// Sonuçları oku
-	recordCount := 0
-	for cursor.Next(ctx) {
-		var result bson.M
-		if err := cursor.Decode(&result); err != nil {
-			panic(err)
+	// Performans ölçümü: -iterations ile tekrarlanır, ısınma turları atılır (bkz. iterations.go)
+	var recordCount int
+	var duration time.Duration
+	var mem benchkit.MemUsage
+	var phases *CursorPhases
+	iterations := NewIterations(logger)
+	for iterations.Next() {
+		start := time.Now()
+
+		// GC yapar; ölçüm boyunca heap zirvesi, ayırmalar ve GC sayılır (bkz. benchkit.MemUsage)
+		memProbe := benchkit.StartMemProbe()
+		ResetCursorPhases() // explain/count komutları aşama sürelerine karışmasın
+
+		// Aggregation pipeline'ı çalıştır
+		// Aggregation, MongoDB'de veri işleme için en güçlü yöntemdir
+		// Veri işleme MongoDB tarafında yapılır, sadece sonuçlar gelir
+		cursor, err := col.Aggregate(ctx, pipeline, options.Aggregate().SetBatchSize(1000))
+		if HandleError(logger, "aggregate", err) {
+			// Sorgu açılamadı: ölçülecek bir şey yok, hata özetini yazıp çık
+			PrintErrorSummary(logger)
+			return
 		}
-		
-		// Burada sadece işlenmiş veri var (MongoDB tarafında işlendi)
-		_ = result
-		recordCount++
-		
-		if recordCount%100000 == 0 {
-			logger.Printf("  📊 İşlenen kayıt: %d\n", recordCount)
+
+		// Sonuçları oku
+		recordCount = 0
+		var decodeTime time.Duration // Client tarafında decode için harcanan süre
+		for cursor.Next(ctx) {
+			var result bson.M
+			decodeStart := time.Now()
+			err := cursor.Decode(&result)
+			decodeTime += time.Since(decodeStart)
+			if HandleError(logger, "decode", err) {
+				continue // Bozuk doküman sayılır ve atlanır, okuma devam eder
+			}
+
+			// Burada sadece işlenmiş veri var (MongoDB tarafında işlendi)
+			_ = result
+			recordCount++
+
+			if recordCount%100000 == 0 {
+				logger.Printf("  📊 İşlenen kayıt: %d\n", recordCount)
+			}
 		}
-	}
 
-	if err := cursor.Err(); err != nil {
-		panic(err)
-	}
+		// Kısmi sonuçlarla devam edilir, hata özette raporlanır
+		HandleError(logger, "cursor", cursor.Err())
+		cursor.Close(ctx)
 
-	var memAfter runtime.MemStats
-	runtime.ReadMemStats(&memAfter)
-	memoryUsed := int64(memAfter.Alloc - memBefore.Alloc)
+		mem = memProbe.Stop()
 
-	duration := time.Since(start)
+		duration = time.Since(start)
+		phases = SnapshotCursorPhases(decodeTime)
+		iterations.Record(duration, mem.PeakGrowth)
+	}
 
 	logger.Printf("\n✅ İYİLEŞTİRME 5 SONUÇLARI (Aggregation Pipeline):\n")
 	logger.Printf("📦 Okunan Kayıt: %d\n", recordCount)
 	logger.Printf("⏱️  Süre: %v\n", duration)
-	logger.Printf("💾 Bellek Kullanımı: %.2f MB\n", float64(memoryUsed)/(1024*1024))
+	logger.Printf("💾 Bellek Kullanımı: %.2f MB\n", float64(mem.PeakGrowth)/(1024*1024))
 	logger.Printf("🚀 Aggregation pipeline sayesinde MongoDB tarafında işleme yapıldı!\n")
-	
+
 	if explainResult != nil {
 		// Aggregation explain sonuçları biraz farklı yapıda olabilir
 		if stages, ok := explainResult["stages"].([]interface{}); ok {
@@ -141,15 +153,17 @@ func main() {
 				}
 			}
 		}
-		
+
 		// Execution stats varsa göster
 		if execStats, ok := explainResult["executionStats"].(map[string]interface{}); ok {
 			metrics := QueryMetrics{
 				Duration:    duration,
 				RecordsRead: recordCount,
-				MemoryUsed:  memoryUsed,
+				Phases:      phases,
+				Iterations:  iterations.Stats(),
 			}
-			
+			metrics.SetMemory(mem)
+
 			if execTime, ok := execStats["executionTimeMillis"].(int64); ok {
 				metrics.ExecutionStats = &ExecutionStats{
 					ExecutionTimeMillis: execTime,
@@ -173,11 +187,10 @@ func main() {
 				}
 				metrics.ExecutionStats.NReturned = nReturned
 			}
-			
+
 			PrintMetrics(metrics, "read_v5", logger)
 		}
 	}
-	
+
 	logger.Println("\n✅ Test tamamlandı! Sonuçlar 'read_v5_results.txt' dosyasına kaydedildi.")
 }
-
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// runlog.go - Çalıştırmaların olay günlüklerinde arama (perflab logs)
+// perflab run, script'lerin text çıktısını satır satır runs/<çalıştırma>/events.jsonl'a da yazar
+// (events sink'i, bkz. sink.go LogEvent). Böylece bir bulgu onlarca _repN.txt dosyası açılmadan
+// tüm çalıştırmalarda aranabilir:
+//
+//	perflab logs -level warn -grep COLLSCAN                     (tüm çalıştırmalar)
+//	perflab logs -run paid_orders_20250101_120000 -level error
+//	perflab logs -run paid_orders -script read_v3 -grep "Stage: " (önekle eşleşen çalıştırmalar)
+//
+// Çalıştırma kimliği runs/ altındaki klasör adıdır. Sıkıştırılmış günlükler (events.jsonl.zst) akış olarak
+// okunur (bkz. artifacts.go).
+
+// eventsFile - Çalıştırma klasöründeki olay günlüğü
+const eventsFile = "events.jsonl"
+
+// RunEvent - Bir çalıştırmanın olay günlüğündeki satır
+type RunEvent struct {
+	Run string // Çalıştırma kimliği (klasör adı)
+	LogEvent
+}
+
+// LogQuery - Olay filtresi; boş alanlar filtrelemez
+type LogQuery struct {
+	Run    string // Çalıştırma kimliği veya öneki (ör: deney adı)
+	Script string
+	Level  string // En düşük seviye: info, warn, error
+	Grep   *regexp.Regexp
+}
+
+// ValidLogLevel - Seviye adı geçerli mi (boş = hepsi)
+func ValidLogLevel(level string) bool {
+	return level == "" || slices.Contains(logLevels, level)
+}
+
+// Match - Olay filtreye uyuyor mu
+func (q LogQuery) Match(e LogEvent) bool {
+	if q.Script != "" && e.Script != q.Script {
+		return false
+	}
+	if q.Level != "" && slices.Index(logLevels, e.Level) < slices.Index(logLevels, q.Level) {
+		return false
+	}
+	return q.Grep == nil || q.Grep.MatchString(e.Line)
+}
+
+// SearchRunEvents - runsDir altındaki çalıştırmaların olay günlüklerini okur ve filtreye uyanları döndürür
+// Döndürür: eşleşen olaylar (çalıştırma adı, sonra günlük sırasıyla) ve taranan çalıştırma sayısı
+func SearchRunEvents(runsDir string, q LogQuery) ([]RunEvent, int, error) {
+	paths, err := GlobArtifacts(filepath.Join(runsDir, "*", eventsFile))
+	if err != nil {
+		return nil, 0, err
+	}
+	var events []RunEvent
+	scanned := 0
+	for _, path := range paths {
+		run := filepath.Base(filepath.Dir(path))
+		if !strings.HasPrefix(run, q.Run) {
+			continue
+		}
+		scanned++
+		matched, err := readRunEvents(path, run, q)
+		if err != nil {
+			return nil, scanned, err
+		}
+		events = append(events, matched...)
+	}
+	return events, scanned, nil
+}
+
+// readRunEvents - Tek bir events.jsonl dosyasındaki eşleşen olaylar
+func readRunEvents(path, run string, q LogQuery) ([]RunEvent, error) {
+	file, err := OpenArtifact(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var events []RunEvent
+	dec := json.NewDecoder(file)
+	for {
+		var e LogEvent
+		if err := dec.Decode(&e); errors.Is(err, io.EOF) {
+			return events, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+		if q.Match(e) {
+			events = append(events, RunEvent{Run: run, LogEvent: e})
+		}
+	}
+}
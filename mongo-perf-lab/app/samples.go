@@ -0,0 +1,150 @@
+package main
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// samples.go - Ham gecikme örnekleri: İşlem başına ölçülen her gecikmenin dosyaya dökülmesi
+// Histogramlar ve yüzdelikler dağılımın özetidir; kendi analizini yapmak isteyen (zaman içinde
+// değişim, worker'lar arası fark, uç değerler, farklı yüzdelik tahmincileri) her işlemin kaydına
+// ihtiyaç duyar. -latency-samples ile işlem başına gecikme ölçen script'ler (measureLatency kullananlar,
+// read_point, RunWorkload kullananlar (workload_profile, read_index_drop), read_nplus1, read_partitioned,
+// insert_bench, optimistic, outbox, preagg, growth, warmup, archive okuyucuları) her işlemi
+// <script>_latency.<format> dosyasına yazar; perflab run -latency-samples csv ile dosyalar runs/<deney>/<script>_rep<N>_latency.csv
+// olarak taşınır (büyükse zstd ile sıkıştırılır, bkz. artifacts.go).
+//
+// Her örnek: işlemin başladığı an (Unix nanosaniye), gecikme (nanosaniye), işlem türü (ör: "read/counter"),
+// worker numarası ve başarılı olup olmadığı.
+//
+//	csv: start_ns,latency_ns,op,worker,ok başlıklı metin (pandas.read_csv, R read.csv)
+//	bin: Little-endian kayıtlar; "PLLAT1\n" başlığından sonra her kayıt bir tür byte'ıyla başlar:
+//	     'o' işlem türü tanımı: id uint16, ad uzunluğu uint8, ad (türün ilk örneğinden önce bir kez)
+//	     's' örnek:             start_ns int64, latency_ns int64, op id uint16, worker uint16, ok uint8
+//	     Örnek başına sabit 22 byte: CSV'nin yarısından az, sayılar metinden ayrıştırılmaz
+//
+// KULLANIM:
+//   go run main.go config.go ... insert_bench.go -latency-samples csv
+//   ... perflab.go run -f experiments/insert_throughput.yaml -latency-samples bin
+
+// latencySamplesFlag - Her script'te geçerli: Ham gecikme örneklerinin formatı (boş = yazılmaz)
+var latencySamplesFlag = flag.String("latency-samples", os.Getenv("PERFLAB_LATENCY_SAMPLES"),
+	"İşlem başına her gecikmeyi <script>_latency.<format> dosyasına yaz: csv veya bin (boş = kapalı, bkz. samples.go)")
+
+// latencyMagic - bin formatının başlığı
+const latencyMagic = "PLLAT1\n"
+
+// LatencySamples - Ham gecikme örneklerinin yazıcısı
+// Record birçok goroutine'den çağrılabilir; nil yazıcıda Record hiçbir şey yapmaz (örnekler kapalı)
+type LatencySamples struct {
+	mu     sync.Mutex
+	path   string
+	file   *os.File
+	buf    *bufio.Writer
+	csv    *csv.Writer // csv formatı; nil = bin
+	ops    map[string]uint16
+	count  int64
+	failed error // İlk yazma hatası: Sonraki örnekler atılır
+}
+
+// newLatencySamples - format (csv, bin) dosyasını oluşturur ve başlığını yazar
+func newLatencySamples(script, format string) (*LatencySamples, error) {
+	if format != "csv" && format != "bin" {
+		return nil, fmt.Errorf("-latency-samples: bilinmeyen format %q (csv, bin)", format)
+	}
+	path := fmt.Sprintf("%s_latency.%s", script, format)
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	s := &LatencySamples{path: path, file: file, buf: bufio.NewWriterSize(file, 1<<20), ops: map[string]uint16{}}
+	if format == "csv" {
+		s.csv = csv.NewWriter(s.buf)
+		err = s.csv.Write([]string{"start_ns", "latency_ns", "op", "worker", "ok"})
+	} else {
+		_, err = s.buf.WriteString(latencyMagic)
+	}
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// Record - İşlemin örneğini yazar (ok = false: başarısız işlem)
+func (s *LatencySamples) Record(op string, worker int, start time.Time, latency time.Duration, ok bool) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.failed != nil {
+		return
+	}
+	if s.csv != nil {
+		s.failed = s.csv.Write([]string{
+			strconv.FormatInt(start.UnixNano(), 10), strconv.FormatInt(int64(latency), 10),
+			op, strconv.Itoa(worker), strconv.FormatBool(ok),
+		})
+	} else {
+		s.failed = s.writeBinary(op, worker, start, latency, ok)
+	}
+	if s.failed == nil {
+		s.count++
+	}
+}
+
+func (s *LatencySamples) writeBinary(op string, worker int, start time.Time, latency time.Duration, ok bool) error {
+	id, known := s.ops[op]
+	if !known {
+		if len(s.ops) == 1<<16-1 {
+			return fmt.Errorf("en fazla %d işlem türü", 1<<16-1)
+		}
+		id = uint16(len(s.ops))
+		s.ops[op] = id
+		name := op[:min(len(op), 255)]
+		def := []byte{'o', 0, 0, byte(len(name))}
+		binary.LittleEndian.PutUint16(def[1:], id)
+		if _, err := s.buf.Write(append(def, name...)); err != nil {
+			return err
+		}
+	}
+	var rec [22]byte
+	rec[0] = 's'
+	binary.LittleEndian.PutUint64(rec[1:], uint64(start.UnixNano()))
+	binary.LittleEndian.PutUint64(rec[9:], uint64(latency))
+	binary.LittleEndian.PutUint16(rec[17:], id)
+	binary.LittleEndian.PutUint16(rec[19:], uint16(worker))
+	if ok {
+		rec[21] = 1
+	}
+	_, err := s.buf.Write(rec[:])
+	return err
+}
+
+// Close - Tamponu boşaltır ve dosyayı kapatır
+// Döndürür: yazılan örnek sayısı ve ilk hata
+func (s *LatencySamples) Close() (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.csv != nil {
+		s.csv.Flush()
+		if err := s.csv.Error(); s.failed == nil {
+			s.failed = err
+		}
+	}
+	if err := s.buf.Flush(); s.failed == nil {
+		s.failed = err
+	}
+	if err := s.file.Close(); s.failed == nil {
+		s.failed = err
+	}
+	return s.count, s.failed
+}
@@ -0,0 +1,617 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"benchkit"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// sink.go - Sonuçların yazılacağı hedefler (result sink)
+// Benchmark'lar sonuçları nereye yazacaklarını bilmez: Logger'a yazarlar, Logger da
+// PERFLAB_SINKS ile seçilen tüm hedeflere aynı anda dağıtır (fan-out).
+// Böylece tek bir çalıştırma hem text log'u, hem JSON artifact'i, hem de uzak bir depoyu doldurabilir.
+//
+// İki tür çıktı vardır:
+// - Text: Logger.Printf ile yazılan insan-okunabilir log (Write)
+// - Kayıt: PrintMetrics'in ürettiği MetricsRecord (WriteRecord)
+// Her sink ilgilendiği türü yazar, diğerini yok sayar.
+//
+// PERFLAB_SINKS formatı - virgülle ayrılmış liste (varsayılan "stdout,file"):
+//   stdout                        Text → terminal
+//   file[=yol]                    Text → dosya (varsayılan: script'in _results.txt dosyası)
+//   json=yol                      Kayıtlar → JSON Lines dosyası (append)
+//   csv=yol                       Kayıtlar → CSV dosyası (append, kayıt başına bir satır; tablo/grafik için)
+//   events=yol                    Text → JSON Lines olay günlüğü (append, satır başına seviye ve script; bkz. LogEvent)
+//   mongo=veritabanı.koleksiyon   Kayıtlar → MongoDB koleksiyonu
+//   http=URL                      Kayıtlar → her biri JSON olarak POST edilir
+//   s3=bucket[/prefix]            Text + kayıtlar → çalıştırma sonunda S3'e yüklenir (PutObject)
+//
+// Örnek:
+//   PERFLAB_SINKS=stdout,file,mongo=perfdb.results go run main.go config.go ... read_v3.go
+//
+// PERFLAB_METRICS_FILE tanımlıysa (perflab altında) json sink'i, PERFLAB_EVENTS_FILE tanımlıysa
+// events sink'i otomatik eklenir (perflab logs ile aranır, bkz. runlog.go).
+// PERFLAB_CSV veya script'in -csv parametresi csv sink'ini ekler:
+//   go run main.go config.go ... read_v3.go -csv results.csv
+//   perflab run -f experiments/read_versions.yaml -csv results.csv
+
+// ResultSink - Sonuç hedefi
+type ResultSink interface {
+	// Write - Text çıktı (io.Writer ile uyumlu)
+	Write(p []byte) (int, error)
+	// WriteRecord - Makine-okunabilir metrik kaydı
+	WriteRecord(record MetricsRecord) error
+	// Close - Bekleyen veriyi gönderir ve kaynakları bırakır
+	Close() error
+}
+
+// SinkSpec - PERFLAB_SINKS içindeki tek bir hedef tanımı
+type SinkSpec struct {
+	Kind   string // stdout, file, json, csv, events, mongo, http, s3
+	Target string // "=" sonrası kısım (ör: dosya yolu, URL)
+}
+
+func (s SinkSpec) String() string {
+	if s.Target == "" {
+		return s.Kind
+	}
+	return s.Kind + "=" + s.Target
+}
+
+// defaultSinks - PERFLAB_SINKS tanımlı değilse kullanılan hedefler (eski Logger davranışı)
+const defaultSinks = "stdout,file"
+
+// ParseSinkSpecs - "stdout,file,json=metrics.jsonl" formatındaki listeyi ayrıştırır ve doğrular
+// Bağlantı kurmaz - manifest doğrulamasında da kullanılır
+func ParseSinkSpecs(s string) ([]SinkSpec, error) {
+	var specs []SinkSpec
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		kind, target, _ := strings.Cut(part, "=")
+		spec := SinkSpec{Kind: strings.TrimSpace(kind), Target: strings.TrimSpace(target)}
+
+		switch spec.Kind {
+		case "stdout":
+			if spec.Target != "" {
+				return nil, fmt.Errorf("sink %q: stdout hedef almaz", part)
+			}
+		case "file":
+		case "json", "csv", "events", "http", "s3":
+			if spec.Target == "" {
+				return nil, fmt.Errorf("sink %q: hedef gerekli (%s=...)", part, spec.Kind)
+			}
+		case "mongo":
+			if db, coll, ok := strings.Cut(spec.Target, "."); !ok || db == "" || coll == "" {
+				return nil, fmt.Errorf("sink %q: mongo=veritabanı.koleksiyon formatında olmalı", part)
+			}
+		default:
+			return nil, fmt.Errorf("bilinmeyen sink: %q (stdout, file, json, csv, events, mongo, http, s3)", spec.Kind)
+		}
+		specs = append(specs, spec)
+	}
+	if len(specs) == 0 {
+		return nil, fmt.Errorf("sink listesi boş: %q", s)
+	}
+	return specs, nil
+}
+
+// SinkSpecsFromEnv - PERFLAB_SINKS, PERFLAB_METRICS_FILE, PERFLAB_EVENTS_FILE ve PERFLAB_CSV'den hedef listesini oluşturur
+func SinkSpecsFromEnv() ([]SinkSpec, error) {
+	value := os.Getenv("PERFLAB_SINKS")
+	if value == "" {
+		value = defaultSinks
+	}
+	specs, err := ParseSinkSpecs(value)
+	if err != nil {
+		return nil, fmt.Errorf("PERFLAB_SINKS: %v", err)
+	}
+
+	// perflab metrik ve olay dosyaları ve CSV - zaten listede yoksa eklenir
+	if path := os.Getenv("PERFLAB_METRICS_FILE"); path != "" {
+		specs = appendSinkSpec(specs, SinkSpec{Kind: "json", Target: path})
+	}
+	if path := os.Getenv("PERFLAB_EVENTS_FILE"); path != "" {
+		specs = appendSinkSpec(specs, SinkSpec{Kind: "events", Target: path})
+	}
+	if path := os.Getenv("PERFLAB_CSV"); path != "" {
+		specs = appendSinkSpec(specs, SinkSpec{Kind: "csv", Target: path})
+	}
+	return specs, nil
+}
+
+// appendSinkSpec - Hedef listede yoksa ekler (aynı dosyaya iki kez yazılmasın)
+func appendSinkSpec(specs []SinkSpec, spec SinkSpec) []SinkSpec {
+	for _, s := range specs {
+		if s == spec {
+			return specs
+		}
+	}
+	return append(specs, spec)
+}
+
+// OpenSinks - Hedefleri açar ve tek bir MultiSink'te toplar
+// Parametreler:
+//   - specs: Açılacak hedefler
+//   - name: Çalıştırma adı (ör: "read_v3_results.txt") - file sink'inin varsayılan yolu ve S3 nesne adı
+//
+// Bir hedef açılamazsa o ana kadar açılanlar kapatılır ve hata döner
+func OpenSinks(specs []SinkSpec, name string) (*MultiSink, error) {
+	multi := &MultiSink{}
+	for _, spec := range specs {
+		sink, err := openSink(spec, name)
+		if err != nil {
+			multi.Close()
+			return nil, fmt.Errorf("sink %s açılamadı: %s", Redact(spec.String()), Redact(err.Error()))
+		}
+		multi.sinks = append(multi.sinks, sink)
+	}
+	return multi, nil
+}
+
+func openSink(spec SinkSpec, name string) (ResultSink, error) {
+	switch spec.Kind {
+	case "stdout":
+		return stdoutSink{}, nil
+	case "file":
+		path := spec.Target
+		if path == "" {
+			path = name
+		}
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+		if err != nil {
+			return nil, err
+		}
+		return &fileSink{file: file}, nil
+	case "json":
+		return &jsonFileSink{path: spec.Target}, nil
+	case "csv":
+		return &csvFileSink{path: spec.Target}, nil
+	case "events":
+		return newEventSink(spec.Target, name)
+	case "mongo":
+		db, coll, _ := strings.Cut(spec.Target, ".")
+		return newMongoSink(db, coll)
+	case "http":
+		return &httpSink{url: spec.Target, client: &http.Client{Timeout: 10 * time.Second}}, nil
+	case "s3":
+		return newS3Sink(spec.Target, name)
+	}
+	return nil, fmt.Errorf("bilinmeyen sink: %s", spec.Kind)
+}
+
+// MultiSink - Yazılanları tüm hedeflere dağıtır
+// io.MultiWriter'dan farkı: Bir hedef hata verse de diğerlerine yazmaya devam eder
+// (uzak depo erişilemiyor diye terminal ve dosya çıktısı kesilmemeli)
+type MultiSink struct {
+	sinks []ResultSink
+}
+
+func (m *MultiSink) Write(p []byte) (int, error) {
+	var errs []error
+	for _, s := range m.sinks {
+		if _, err := s.Write(p); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return len(p), errors.Join(errs...)
+}
+
+func (m *MultiSink) WriteRecord(record MetricsRecord) error {
+	var errs []error
+	for _, s := range m.sinks {
+		if err := s.WriteRecord(record); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (m *MultiSink) Close() error {
+	var errs []error
+	for _, s := range m.sinks {
+		if err := s.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// stdoutSink - Text → terminal
+type stdoutSink struct{}
+
+func (stdoutSink) Write(p []byte) (int, error)            { return os.Stdout.Write(p) }
+func (stdoutSink) WriteRecord(record MetricsRecord) error { return nil }
+func (stdoutSink) Close() error                           { return nil }
+
+// fileSink - Text → dosya (her çalıştırmada baştan yazılır)
+type fileSink struct {
+	file *os.File
+}
+
+func (s *fileSink) Write(p []byte) (int, error)            { return s.file.Write(p) }
+func (s *fileSink) WriteRecord(record MetricsRecord) error { return nil }
+func (s *fileSink) Close() error                           { return s.file.Close() }
+
+// jsonFileSink - Kayıtlar → JSON Lines dosyası
+// Dosya her kayıtta append modunda açılır: perflab tekrarları aynı dosyaya ekler
+type jsonFileSink struct {
+	path string
+}
+
+func (s *jsonFileSink) Write(p []byte) (int, error) { return len(p), nil }
+
+func (s *jsonFileSink) WriteRecord(record MetricsRecord) error {
+	return benchkit.AppendJSONL(s.path, record)
+}
+
+func (s *jsonFileSink) Close() error { return nil }
+
+// csvFileSink - Kayıtlar → CSV dosyası
+// json sink'i gibi her kayıtta append modunda açılır: Farklı script'lerin ve çalıştırmaların
+// satırları aynı dosyada birikir. Dosya boşsa önce başlık satırı yazılır.
+type csvFileSink struct {
+	path string
+}
+
+// csvColumns - CSV başlığı (csvRow ile aynı sırada)
+var csvColumns = []string{
+	"timestamp", "scenario", "environment", "repetition",
+	"duration_ms", "records", "memory_mb", "docs_examined", "keys_examined", "n_returned", "efficiency",
+}
+
+// csvRow - Kaydın CSV satırı; senaryo benchmark/varyant adıdır (MetricsRecord.Name)
+func csvRow(record MetricsRecord) []string {
+	f := func(v float64) string { return strconv.FormatFloat(v, 'f', -1, 64) }
+	i := func(v int64) string { return strconv.FormatInt(v, 10) }
+	return []string{
+		time.Now().Format(time.RFC3339), record.Name(), record.Environment, strconv.Itoa(record.Repetition),
+		f(record.DurationMs), strconv.Itoa(record.RecordsRead), f(record.MemoryMB),
+		i(record.DocsExamined), i(record.KeysExamined), i(record.NReturned), f(record.Efficiency),
+	}
+}
+
+func (s *csvFileSink) Write(p []byte) (int, error) { return len(p), nil }
+
+func (s *csvFileSink) WriteRecord(record MetricsRecord) error {
+	file, err := os.OpenFile(s.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	w := csv.NewWriter(file)
+	if info.Size() == 0 {
+		w.Write(csvColumns)
+	}
+	w.Write(csvRow(record))
+	w.Flush()
+	return w.Error()
+}
+
+func (s *csvFileSink) Close() error { return nil }
+
+// LogEvent - Olay günlüğünün tek satırı: Text log'un bir satırı, seviyesi ve kaynağıyla
+type LogEvent struct {
+	Time       time.Time `json:"time"`
+	Level      string    `json:"level"` // info, warn, error (bkz. lineLevel)
+	Script     string    `json:"script"`
+	Repetition int       `json:"repetition,omitempty"` // perflab altında PERFLAB_REPETITION
+	Line       string    `json:"line"`
+}
+
+// Olay seviyeleri, önem sırasıyla (perflab logs -level en düşük seviyeyi seçer)
+var logLevels = []string{"info", "warn", "error"}
+
+// lineLevel - Satırın seviyesi, script'lerin kullandığı işaretlerden çıkarılır:
+// ❌ hata, ⚠️ / 🚨 uyarı, diğerleri bilgi
+func lineLevel(line string) string {
+	switch {
+	case strings.HasPrefix(line, "❌"):
+		return "error"
+	case strings.HasPrefix(line, "⚠️"), strings.HasPrefix(line, "🚨"):
+		return "warn"
+	}
+	return "info"
+}
+
+// eventSink - Text → JSON Lines olay günlüğü
+// Logger'a parça parça yazılan text satırlara bölünür; her dolu satır ayrı bir olaydır.
+// Dosya append modunda açılır: perflab'ın tüm script ve tekrarları aynı events.jsonl'a yazar.
+type eventSink struct {
+	file       *os.File
+	enc        *json.Encoder
+	script     string
+	repetition int
+	pending    []byte // Henüz satır sonu gelmemiş kısım
+}
+
+func newEventSink(path, name string) (*eventSink, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	rep, _ := strconv.Atoi(os.Getenv("PERFLAB_REPETITION"))
+	return &eventSink{
+		file:       file,
+		enc:        json.NewEncoder(file),
+		script:     strings.TrimSuffix(filepath.Base(name), "_results.txt"),
+		repetition: rep,
+	}, nil
+}
+
+func (s *eventSink) Write(p []byte) (int, error) {
+	s.pending = append(s.pending, p...)
+	for {
+		i := bytes.IndexByte(s.pending, '\n')
+		if i < 0 {
+			return len(p), nil
+		}
+		line := string(s.pending[:i])
+		s.pending = s.pending[i+1:]
+		if err := s.emit(line); err != nil {
+			return len(p), err
+		}
+	}
+}
+
+// emit - Boş satırlar (ve WriteHeader'ın ayraçları) olay sayılmaz
+func (s *eventSink) emit(line string) error {
+	line = strings.Trim(line, " \t\r\x00")
+	if strings.Trim(line, "=") == "" {
+		return nil
+	}
+	return s.enc.Encode(LogEvent{Time: time.Now(), Level: lineLevel(line), Script: s.script, Repetition: s.repetition, Line: line})
+}
+
+func (s *eventSink) WriteRecord(record MetricsRecord) error { return nil }
+
+func (s *eventSink) Close() error {
+	err := s.emit(string(s.pending))
+	s.pending = nil
+	return errors.Join(err, s.file.Close())
+}
+
+// mongoSink - Kayıtlar → MongoDB koleksiyonu
+// Ölçülen client'tan ayrı bir client kullanır: Kayıt yazmak cursor aşama sürelerine
+// ve havuz istatistiklerine karışmamalı (MongoClientOptions'ın izleyicileri burada yok)
+type mongoSink struct {
+	client *mongo.Client
+	col    *mongo.Collection
+	host   string
+}
+
+func newMongoSink(db, coll string) (*mongoSink, error) {
+	opts, err := CurrentEnvironment().ClientOptions()
+	if err != nil {
+		return nil, err
+	}
+	client, err := mongo.Connect(context.Background(), opts)
+	if err != nil {
+		return nil, err
+	}
+	host, _ := os.Hostname()
+	return &mongoSink{client: client, col: client.Database(db).Collection(coll), host: host}, nil
+}
+
+func (s *mongoSink) Write(p []byte) (int, error) { return len(p), nil }
+
+func (s *mongoSink) WriteRecord(record MetricsRecord) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// Kayıt, JSON alan adlarıyla saklanır: Dosyadaki ve koleksiyondaki sorgular aynı adları kullanır
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+	doc["recordedAt"] = time.Now()
+	doc["host"] = s.host
+	_, err = s.col.InsertOne(ctx, doc)
+	return err
+}
+
+func (s *mongoSink) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return s.client.Disconnect(ctx)
+}
+
+// httpSink - Kayıtlar → her biri JSON olarak POST edilir
+type httpSink struct {
+	url    string
+	client *http.Client
+}
+
+func (s *httpSink) Write(p []byte) (int, error) { return len(p), nil }
+
+func (s *httpSink) WriteRecord(record MetricsRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	resp, err := s.client.Post(s.url, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("http sink: %s yanıtı %s", Redact(s.url), resp.Status)
+	}
+	return nil
+}
+
+func (s *httpSink) Close() error { return nil }
+
+// s3Sink - Text ve kayıtlar bellekte biriktirilir, Close'da S3'e yüklenir
+// Nesne adları: <prefix>/<script>-<zaman>.txt ve .jsonl
+// Kimlik bilgileri AWS ortam değişkenlerinden okunur:
+//   AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY, AWS_SESSION_TOKEN (opsiyonel),
+//   AWS_REGION (varsayılan us-east-1), AWS_ENDPOINT_URL (opsiyonel, ör: MinIO)
+//
+// AWS SDK bağımlılığı eklememek için PutObject isteği SigV4 ile elle imzalanır
+type s3Sink struct {
+	bucket  string
+	key     string // Uzantısız nesne adı
+	text    bytes.Buffer
+	records bytes.Buffer
+
+	region    string
+	endpoint  string
+	accessKey string
+	secretKey string
+	token     string
+	client    *http.Client
+}
+
+func newS3Sink(target, name string) (*s3Sink, error) {
+	bucket, prefix, _ := strings.Cut(target, "/")
+	s := &s3Sink{
+		bucket:    bucket,
+		region:    os.Getenv("AWS_REGION"),
+		endpoint:  strings.TrimSuffix(os.Getenv("AWS_ENDPOINT_URL"), "/"),
+		accessKey: os.Getenv("AWS_ACCESS_KEY_ID"),
+		secretKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		token:     os.Getenv("AWS_SESSION_TOKEN"),
+		client:    &http.Client{Timeout: 30 * time.Second},
+	}
+	if s.accessKey == "" || s.secretKey == "" {
+		return nil, fmt.Errorf("AWS_ACCESS_KEY_ID ve AWS_SECRET_ACCESS_KEY tanımlı olmalı")
+	}
+	RegisterSecret(s.secretKey)
+	RegisterSecret(s.token)
+	if s.region == "" {
+		s.region = "us-east-1"
+	}
+
+	base := strings.TrimSuffix(filepath.Base(name), filepath.Ext(name))
+	s.key = fmt.Sprintf("%s-%s", base, time.Now().UTC().Format("20060102T150405Z"))
+	if prefix = strings.Trim(prefix, "/"); prefix != "" {
+		s.key = prefix + "/" + s.key
+	}
+	return s, nil
+}
+
+func (s *s3Sink) Write(p []byte) (int, error) { return s.text.Write(p) }
+
+func (s *s3Sink) WriteRecord(record MetricsRecord) error {
+	return json.NewEncoder(&s.records).Encode(record)
+}
+
+func (s *s3Sink) Close() error {
+	if err := s.put(s.key+".txt", "text/plain; charset=utf-8", s.text.Bytes()); err != nil {
+		return err
+	}
+	if s.records.Len() > 0 {
+		return s.put(s.key+".jsonl", "application/x-ndjson", s.records.Bytes())
+	}
+	return nil
+}
+
+// put - Tek bir PutObject isteği (AWS Signature Version 4)
+func (s *s3Sink) put(key, contentType string, body []byte) error {
+	// Endpoint verilmişse path-style (MinIO vb.), yoksa virtual-hosted style
+	escapedKey := strings.ReplaceAll(url.PathEscape(key), "%2F", "/")
+	rawURL := fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s", s.bucket, s.region, escapedKey)
+	if s.endpoint != "" {
+		rawURL = fmt.Sprintf("%s/%s/%s", s.endpoint, s.bucket, escapedKey)
+	}
+	req, err := http.NewRequest(http.MethodPut, rawURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	day := now.Format("20060102")
+	payloadHash := sha256Hex(body)
+
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if s.token != "" {
+		req.Header.Set("X-Amz-Security-Token", s.token)
+	}
+
+	// İmzalanan header'lar alfabetik sırada olmalı
+	headers := []string{"content-type", "host", "x-amz-content-sha256", "x-amz-date"}
+	if s.token != "" {
+		headers = append(headers, "x-amz-security-token")
+	}
+	var canonicalHeaders strings.Builder
+	for _, h := range headers {
+		value := req.Header.Get(h)
+		if h == "host" {
+			value = req.URL.Host
+		}
+		canonicalHeaders.WriteString(h + ":" + strings.TrimSpace(value) + "\n")
+	}
+	signedHeaders := strings.Join(headers, ";")
+
+	canonicalRequest := strings.Join([]string{
+		http.MethodPut,
+		req.URL.EscapedPath(),
+		"", // Query string yok
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := day + "/" + s.region + "/s3/aws4_request"
+	stringToSign := strings.Join([]string{"AWS4-HMAC-SHA256", amzDate, scope, sha256Hex([]byte(canonicalRequest))}, "\n")
+
+	signingKey := hmacSHA256([]byte("AWS4"+s.secretKey), day)
+	signingKey = hmacSHA256(signingKey, s.region)
+	signingKey = hmacSHA256(signingKey, "s3")
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.accessKey, scope, signedHeaders, signature))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("s3 sink: s3://%s/%s yüklenemedi: %s", s.bucket, key, resp.Status)
+	}
+	return nil
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"benchkit"
+)
+
+// summary.go - runs/<deney>/summary.json formatı
+// perflab run yazar; writeup gibi komutlar geçmiş çalıştırmaları buradan okur.
+
+// AssertionResult - Bir assertion'ın değerlendirme sonucu
+type AssertionResult struct {
+	AssertionSpec
+	Value  float64 `json:"value"`
+	Passed bool    `json:"passed"`
+	Reason string  `json:"reason,omitempty"`
+}
+
+// RunSummary - runs/<deney>/summary.json içeriği
+type RunSummary struct {
+	Manifest    *Manifest         `json:"manifest"` // Ortamın veri seti ve eşikleri uygulanmış hali
+	Environment string            `json:"environment"`
+	Topology    *TopologyInfo     `json:"topology,omitempty"` // Tek düğüm, replica set veya sharded (bkz. topology.go)
+	Host        benchkit.HostInfo `json:"host"`               // Farklı makinelerdeki çalıştırmalar karşılaştırılırken gerekli
+	StartedAt   time.Time         `json:"startedAt"`
+	FinishedAt  time.Time         `json:"finishedAt"`
+	Records     []MetricsRecord   `json:"records"`
+	Assertions  []AssertionResult `json:"assertions"`
+
+	Costs []BenchmarkCost `json:"costs,omitempty"` // manifest'te cost tanımlıysa
+
+	Coordination *CoordinationInfo `json:"coordination,omitempty"` // -coord ile çalıştırıldıysa
+	Provenance   *Provenance       `json:"provenance"`             // Manifest, veri seti ve kaynak hash'leri (bkz. provenance.go)
+}
+
+// BenchmarkCost - Bir benchmark'ın tekrar ortalamasından hesaplanan maliyet tahmini
+type BenchmarkCost struct {
+	Benchmark string `json:"benchmark"`
+	benchkit.CostEstimate
+}
+
+// LoadRunSummary - Çalıştırma klasöründeki (veya doğrudan verilen) summary.json'ı okur
+// Sıkıştırılmışsa (summary.json.zst) akış olarak çözülür (bkz. artifacts.go)
+func LoadRunSummary(path string) (*RunSummary, error) {
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		path = filepath.Join(path, "summary.json")
+	}
+	file, err := OpenArtifact(path)
+	if err != nil {
+		return nil, fmt.Errorf("%v (summary.json için manifest'te outputs: [json] gerekli)", err)
+	}
+	defer file.Close()
+	var s RunSummary
+	if err := json.NewDecoder(file).Decode(&s); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	if s.Manifest == nil {
+		return nil, fmt.Errorf("%s: manifest yok", path)
+	}
+	return &s, nil
+}
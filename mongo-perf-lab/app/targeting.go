@@ -0,0 +1,180 @@
+package main
+
+import (
+	"context"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// targeting.go - Çalıştırma boyunca query targeting oranı takibi
+// Explain tek bir sorgunun oranını verir; workload'daki binlerce işlemin toplamı için
+// serverStatus sayaçları periyodik olarak okunur ve aralık farkları alınır:
+//   - metrics.queryExecutor.scannedObjects: İncelenen doküman
+//   - metrics.queryExecutor.scanned: İncelenen index key
+//   - metrics.document.returned: Döndürülen doküman
+//
+// Oran = incelenen doküman / dönen doküman. Atlas'taki "Query Targeting: Scanned Objects / Returned"
+// alarmı aynı sayaçlara bakar ve varsayılan olarak 1000'i geçince tetiklenir.
+// Sayaçlar sunucu geneli olduğu için aynı anda çalışan başka işlemler de orana karışır.
+
+// targetingAlertRatio - Atlas'ın varsayılan query targeting alarm eşiği
+const targetingAlertRatio = 1000
+
+// TargetingSample - Bir örnekleme aralığındaki sayaç farkları
+type TargetingSample struct {
+	At           time.Duration // Sampler başladıktan ne kadar sonra (aralığın sonu)
+	DocsExamined int64
+	KeysExamined int64
+	Returned     int64
+}
+
+// Ratio - İncelenen doküman / dönen doküman
+// Hiç doküman dönmediyse incelenen sayısı döner (her inceleme boşa gitti)
+func (s TargetingSample) Ratio() float64 {
+	if s.Returned == 0 {
+		return float64(s.DocsExamined)
+	}
+	return float64(s.DocsExamined) / float64(s.Returned)
+}
+
+// targetingCounters - serverStatus'tan okunan sayaçlar
+type targetingCounters struct {
+	Metrics struct {
+		QueryExecutor struct {
+			Scanned        int64 `bson:"scanned"`
+			ScannedObjects int64 `bson:"scannedObjects"`
+		} `bson:"queryExecutor"`
+		Document struct {
+			Returned int64 `bson:"returned"`
+		} `bson:"document"`
+	} `bson:"metrics"`
+}
+
+func readTargetingCounters(db *mongo.Database) (targetingCounters, error) {
+	var c targetingCounters
+	// Sadece metrics bölümü lazım - diğer bölümleri kapatmak komutu hafifletir
+	err := db.RunCommand(context.Background(), bson.D{
+		{Key: "serverStatus", Value: 1},
+		{Key: "repl", Value: 0},
+		{Key: "locks", Value: 0},
+		{Key: "wiredTiger", Value: 0},
+	}).Decode(&c)
+	return c, err
+}
+
+// TargetingSampler - serverStatus sayaçlarını arka planda örnekleyen sampler
+type TargetingSampler struct {
+	db      *mongo.Database
+	start   time.Time
+	stop    chan struct{}
+	done    chan struct{}
+	mu      sync.Mutex
+	samples []TargetingSample
+	errors  int
+}
+
+// StartTargetingSampler - Sampler'ı başlatır
+// interval: Örnekleme aralığı (workload zaman çizelgesiyle hizalı olması için genelde 1 sn)
+func StartTargetingSampler(db *mongo.Database, interval time.Duration) *TargetingSampler {
+	s := &TargetingSampler{
+		db:    db,
+		start: time.Now(),
+		stop:  make(chan struct{}),
+		done:  make(chan struct{}),
+	}
+	prev, err := readTargetingCounters(db)
+	go func() {
+		defer close(s.done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		ok := err == nil
+		for {
+			select {
+			case <-s.stop:
+				s.sample(&prev, &ok) // Son kısmi aralık
+				return
+			case <-ticker.C:
+				s.sample(&prev, &ok)
+			}
+		}
+	}()
+	return s
+}
+
+// sample - Sayaçları okur, önceki okumayla farkını kaydeder
+// Önceki okuma başarısızsa bu okuma sadece yeni başlangıç noktası olur
+func (s *TargetingSampler) sample(prev *targetingCounters, ok *bool) {
+	cur, err := readTargetingCounters(s.db)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err != nil {
+		s.errors++
+		*ok = false
+		return
+	}
+	if *ok {
+		s.samples = append(s.samples, TargetingSample{
+			At:           time.Since(s.start),
+			DocsExamined: cur.Metrics.QueryExecutor.ScannedObjects - prev.Metrics.QueryExecutor.ScannedObjects,
+			KeysExamined: cur.Metrics.QueryExecutor.Scanned - prev.Metrics.QueryExecutor.Scanned,
+			Returned:     cur.Metrics.Document.Returned - prev.Metrics.Document.Returned,
+		})
+	}
+	*prev, *ok = cur, true
+}
+
+// Stop - Sampler'ı durdurur ve örnekleri döndürür
+func (s *TargetingSampler) Stop() []TargetingSample {
+	close(s.stop)
+	<-s.done
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.samples
+}
+
+// TotalTargeting - Tüm örneklerin toplamı (At = son örneğin zamanı)
+func TotalTargeting(samples []TargetingSample) TargetingSample {
+	var total TargetingSample
+	for _, s := range samples {
+		total.At = s.At
+		total.DocsExamined += s.DocsExamined
+		total.KeysExamined += s.KeysExamined
+		total.Returned += s.Returned
+	}
+	return total
+}
+
+// PrintTargetingTimeline - Oranın çalıştırma boyunca değişimini çizer
+// Çubuk logaritmik ölçeklidir (1x → boş, 10x → 10 karakter, 1000x → 30 karakter):
+// oran sıçramaları (ör: index silinmesi) tek satırda göze çarpar
+func PrintTargetingTimeline(samples []TargetingSample, logger *Logger) {
+	total := TotalTargeting(samples)
+	logger.Println("\n=== QUERY TARGETING (incelenen doküman / dönen) ===")
+	logger.Printf("📊 Toplam: %d doküman + %d key incelendi, %d döndü → oran %.1f\n",
+		total.DocsExamined, total.KeysExamined, total.Returned, total.Ratio())
+	logger.Printf("  %8s %12s %12s %10s %9s\n", "zaman", "incelenen", "dönen", "key", "oran")
+
+	alerts := 0
+	for _, s := range samples {
+		bar := ""
+		if ratio := s.Ratio(); ratio > 1 {
+			bar = strings.Repeat("█", min(40, int(math.Log10(ratio)*10)))
+		}
+		marker := ""
+		if s.Ratio() > targetingAlertRatio {
+			marker = " 🚨"
+			alerts++
+		}
+		logger.Printf("  %8v %12d %12d %10d %9.1f %s%s\n",
+			s.At.Round(time.Second), s.DocsExamined, s.Returned, s.KeysExamined, s.Ratio(), bar, marker)
+	}
+	if alerts > 0 {
+		logger.Printf("🚨 %d aralıkta oran %d'i geçti - Atlas bu durumda query targeting alarmı üretir\n", alerts, targetingAlertRatio)
+	}
+	logger.Println("ℹ️  serverStatus sayaçları sunucu geneli: Aynı anda çalışan diğer işlemler de dahildir")
+}
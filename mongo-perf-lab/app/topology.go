@@ -0,0 +1,252 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// topology.go - Aynı deneyin tek düğüm, replica set ve sharded kümede karşılaştırması (perflab topology)
+// Okuma/yazma yolunun topolojiye göre maliyeti (replikasyon, mongos yönlendirmesi, scatter-gather)
+// aynı iş yükü ve aynı veri setiyle ölçülür. Kümeler docker-compose.yml'daki topology profilidir:
+//
+//	docker compose --profile topology up -d --wait      (mongo, mongo-replset, mongo-sharded)
+//	perflab topology -f experiments/read_versions.yaml
+//	perflab topology -up -envs topo-single,topo-sharded  (container'ları da başlatır)
+//
+// Her ortam için perflab run ayrı process olarak çalışır (kendi runs/ klasörü, özet ve
+// assertion'ları); sonunda runs/topology_<deney>_<zaman>/TOPOLOGY.md'ye benchmark başına süre
+// karşılaştırması ve topoloji maliyeti (toplam süre × veri taşıyan düğüm) yazılır.
+//
+// Sharded ortamda koleksiyon perflab.yaml'daki shardKey ile veri seti üretilmeden önce shard'lanır
+// (boş koleksiyonda hashed key'in chunk'ları baştan shard'lara dağıtılır, balancer beklenmez).
+
+// topologyEnvironments - perflab topology'nin varsayılan ortamları (perflab.yaml)
+const topologyEnvironments = "topo-single,topo-replset,topo-sharded"
+
+// TopologyInfo - Bağlanılan dağıtımın yapısı (hello ve listShards'tan)
+type TopologyInfo struct {
+	Kind    string `json:"kind"`              // standalone, replset, sharded
+	SetName string `json:"setName,omitempty"` // Replica set adı
+	Members int    `json:"members,omitempty"` // Replica set üyeleri (hello.hosts: gizli/arbiter hariç)
+	Shards  int    `json:"shards,omitempty"`
+}
+
+func (t TopologyInfo) String() string {
+	switch t.Kind {
+	case "replset":
+		return fmt.Sprintf("replica set %s (%d üye)", t.SetName, t.Members)
+	case "sharded":
+		return fmt.Sprintf("sharded (%d shard)", t.Shards)
+	}
+	return "tek düğüm"
+}
+
+// DataNodes - Veriyi tutan düğüm sayısı (maliyet karşılaştırmasında çarpan)
+// Sharded kümede shard başına bir düğüm sayılır; config server ve mongos hariçtir
+func (t TopologyInfo) DataNodes() int {
+	switch t.Kind {
+	case "replset":
+		return max(t.Members, 1)
+	case "sharded":
+		return max(t.Shards, 1)
+	}
+	return 1
+}
+
+// DetectTopology - Client'ın bağlandığı dağıtımın türünü bulur
+func DetectTopology(client *mongo.Client) (*TopologyInfo, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	admin := client.Database("admin")
+
+	var hello struct {
+		Msg     string   `bson:"msg"`
+		SetName string   `bson:"setName"`
+		Hosts   []string `bson:"hosts"`
+	}
+	if err := admin.RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&hello); err != nil {
+		return nil, err
+	}
+	switch {
+	case hello.Msg == "isdbgrid":
+		var shards struct {
+			Shards []bson.M `bson:"shards"`
+		}
+		if err := admin.RunCommand(ctx, bson.D{{Key: "listShards", Value: 1}}).Decode(&shards); err != nil {
+			return nil, err
+		}
+		return &TopologyInfo{Kind: "sharded", Shards: len(shards.Shards)}, nil
+	case hello.SetName != "":
+		return &TopologyInfo{Kind: "replset", SetName: hello.SetName, Members: len(hello.Hosts)}, nil
+	}
+	return &TopologyInfo{Kind: "standalone"}, nil
+}
+
+// ParseShardKey - "_id:hashed" veya "userId:1,createdAt:1" formatındaki shard key'i ayrıştırır
+func ParseShardKey(s string) (bson.D, error) {
+	var key bson.D
+	for _, part := range strings.Split(s, ",") {
+		field, kind, found := strings.Cut(strings.TrimSpace(part), ":")
+		if field == "" {
+			return nil, fmt.Errorf("boş alan: %q", s)
+		}
+		switch {
+		case !found || kind == "1":
+			key = append(key, bson.E{Key: field, Value: 1})
+		case kind == "hashed":
+			key = append(key, bson.E{Key: field, Value: "hashed"})
+		default:
+			return nil, fmt.Errorf("%q: yön 1 veya hashed olmalı", part)
+		}
+	}
+	return key, nil
+}
+
+// ShardCollection - Koleksiyonu key ile shard'lar (zaten aynı key'le shard'lıysa bir şey yapmaz)
+// Dolu koleksiyonda shardCollection key'in index'ini ister: Önce index oluşturulur
+func ShardCollection(col *mongo.Collection, key string) error {
+	keys, err := ParseShardKey(key)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	if _, err := col.Indexes().CreateOne(ctx, mongo.IndexModel{Keys: keys}); err != nil {
+		return fmt.Errorf("shard key index'i: %v", err)
+	}
+	ns := col.Database().Name() + "." + col.Name()
+	cmd := bson.D{{Key: "shardCollection", Value: ns}, {Key: "key", Value: keys}}
+	if err := col.Database().Client().Database("admin").RunCommand(ctx, cmd).Err(); err != nil {
+		return fmt.Errorf("shardCollection %s: %v", ns, err)
+	}
+	return nil
+}
+
+// TopologyRun - perflab topology'de bir ortamın çalıştırması
+type TopologyRun struct {
+	Environment string        `json:"environment"`
+	Dir         string        `json:"dir,omitempty"`      // runs/<deney>_<zaman>
+	Topology    *TopologyInfo `json:"topology,omitempty"` // Özetteki tespit (bkz. RunSummary.Topology)
+	Summary     *RunSummary   `json:"-"`
+	Error       string        `json:"error,omitempty"` // Çalıştırma başarısızsa (özet yoksa)
+}
+
+// topology - Çalıştırmanın tespit edilen topolojisi (tespit edilemediyse tek düğüm varsayılır)
+func (r TopologyRun) topology() TopologyInfo {
+	if r.Topology != nil {
+		return *r.Topology
+	}
+	return TopologyInfo{Kind: "standalone"}
+}
+
+// totalSeconds - Tüm kayıtların süre toplamı (aynı iş yükünün toplam ölçüm süresi)
+func (r TopologyRun) totalSeconds() float64 {
+	total := 0.0
+	for _, rec := range r.Summary.Records {
+		total += rec.DurationMs / 1000
+	}
+	return total
+}
+
+// WriteTopologyReport - Ortamların karşılaştırmasını Markdown olarak yazar
+// Oranlar (x) özeti olan ilk ortama göredir: >1 daha yavaş / daha pahalı
+func WriteTopologyReport(w io.Writer, name string, runs []TopologyRun) error {
+	p := func(format string, args ...any) { fmt.Fprintf(w, format, args...) }
+
+	var ok []TopologyRun
+	for _, r := range runs {
+		if r.Summary != nil {
+			ok = append(ok, r)
+		}
+	}
+
+	p("# %s - Topoloji Karşılaştırması\n\n", name)
+	p("| ortam | topoloji | veri düğümü | çalıştırma |\n|---|---|---:|---|\n")
+	for _, r := range runs {
+		if r.Summary == nil {
+			p("| %s | - | - | ❌ %s |\n", r.Environment, r.Error)
+			continue
+		}
+		t := r.topology()
+		p("| %s | %s | %d | %s |\n", r.Environment, t, t.DataNodes(), r.Dir)
+	}
+	if len(ok) == 0 {
+		return nil
+	}
+	base := ok[0]
+
+	// Benchmark başına tekrar ortalaması; sıra ilk görülme sırasıdır (önce ilk ortamın kayıtları)
+	stats := make([]map[string]benchmarkStats, len(ok))
+	var order []string
+	for i, r := range ok {
+		stats[i] = map[string]benchmarkStats{}
+		for _, s := range summarizeBenchmarks(r.Summary.Records) {
+			if !slices.Contains(order, s.Name) {
+				order = append(order, s.Name)
+			}
+			stats[i][s.Name] = s
+		}
+	}
+
+	p("\n## Süre (tekrar ortalaması, ms; x: %s'e göre)\n\n| benchmark |", base.Environment)
+	for _, r := range ok {
+		p(" %s | x |", r.Environment)
+	}
+	p("\n|---|")
+	for range ok {
+		p("---:|---:|")
+	}
+	p("\n")
+	for _, name := range order {
+		p("| %s |", name)
+		b, hasBase := stats[0][name]
+		for i := range ok {
+			s, has := stats[i][name]
+			if !has {
+				p(" - | - |")
+				continue
+			}
+			ratio := "-"
+			if hasBase && b.MeanMs > 0 {
+				ratio = fmt.Sprintf("%.2fx", s.MeanMs/b.MeanMs)
+			}
+			p(" %.1f | %s |", s.MeanMs, ratio)
+		}
+		p("\n")
+	}
+
+	// Aynı iş yükü için düğüm-saniye: Her düğüm aynı kaynağı aldığında altyapı maliyetiyle orantılı
+	p("\n## Topoloji Maliyeti\n\n")
+	p("Düğüm-saniye = toplam ölçüm süresi × veri taşıyan düğüm (her düğüm aynı kaynakla çalışır; config server ve mongos hariç).\n\n")
+	p("| ortam | topoloji | toplam süre (s) | x | düğüm-saniye | x | hata |\n|---|---|---:|---:|---:|---:|---:|\n")
+	baseSeconds := base.totalSeconds()
+	baseNodeSeconds := baseSeconds * float64(base.topology().DataNodes())
+	for _, r := range ok {
+		t := r.topology()
+		seconds := r.totalSeconds()
+		nodeSeconds := seconds * float64(t.DataNodes())
+		errs := 0
+		for _, rec := range r.Summary.Records {
+			errs += rec.Errors
+		}
+		p("| %s | %s | %.1f | %s | %.1f | %s | %d |\n", r.Environment, t,
+			seconds, topologyRatio(seconds, baseSeconds), nodeSeconds, topologyRatio(nodeSeconds, baseNodeSeconds), errs)
+	}
+	return nil
+}
+
+// topologyRatio - v / base ("2.50x"); base sıfırsa "-"
+func topologyRatio(v, base float64) string {
+	if base <= 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.2fx", v/base)
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// update_bad.go - KÖTÜ GÜNCELLEME ÖRNEĞİ: Okuyup her doküman için ayrı UpdateOne
+// Senaryo: -older-than'dan eski bekleyen (PENDING) siparişler EXPIRED olarak işaretlenir.
+// Bu versiyon işi uygulamada yapar:
+// 1. Eşleşen dokümanlar index'siz bir find ile okunur (COLLSCAN)
+// 2. Her doküman için _id ile ayrı bir UpdateOne gönderilir: N doküman = N round trip
+// 3. Filtre sunucuda tek seferde çalışabilecekken tüm _id'ler client'a taşınır
+// Her UpdateOne _id index'iyle doğrudan dokümanı bulur (IDHACK) - tek güncelleme ucuzdur,
+// pahalı olan sayısıdır. İyileştirmeler: update_v1 (UpdateMany), update_v2 (BulkWrite)
+//
+// Her turdan önce orders'ın ilk -n dokümanı -collection'a kopyalanır (ölçüme dahil değil).
+//
+// KULLANIM:
+//   go run main.go config.go analyzer.go antipattern.go errors.go logger.go samples.go sink.go dataset.go orders.go iterations.go writes.go update_bad.go
+//   go run main.go config.go analyzer.go antipattern.go errors.go logger.go samples.go sink.go dataset.go orders.go iterations.go writes.go update_bad.go -n 100000 -older-than 360h
+func main() {
+	n := flag.Int64("n", 200000, "Çalışma kopyasına alınan doküman sayısı")
+	olderThan := flag.Duration("older-than", 30*24*time.Hour, "Bu süreden eski bekleyen siparişler güncellenir (createdAt)")
+	collection := flag.String("collection", "orders_updates", "Çalışma kopyası (her turda yeniden oluşturulur)")
+	flag.Parse()
+
+	logger, err := NewLogger("update_bad_results.txt")
+	if err != nil {
+		fmt.Printf("Logger oluşturulamadı: %v\n", err)
+		return
+	}
+	defer CloseLogger(logger)
+
+	logger.WriteHeader("update_bad - KÖTÜ GÜNCELLEME ÖRNEĞİ (Doküman Başına UpdateOne)")
+
+	col, err := WriteTarget(*collection)
+	if err != nil {
+		logger.Printf("❌ %v\n", err)
+		return
+	}
+	ctx := context.Background()
+	reset := CopyOrders(GetMongo(), col, *n, nil) // KÖTÜ: Filtrenin index'i yok
+	if HandleError(logger, "reset", reset(ctx)) {
+		PrintErrorSummary(logger)
+		return
+	}
+
+	filter := ExpireFilter(*olderThan)
+	findOpts := options.Find().SetProjection(bson.M{"_id": 1})
+	logger.Printf("📋 %d doküman, filtre: status=PENDING, createdAt < %s\n", *n, time.Now().Add(-*olderThan).Format(time.RFC3339))
+
+	// Explain: Eşleşenleri bulan find (asıl maliyet) ve tek bir _id güncellemesi
+	logger.Println("🔍 Filtre analizi yapılıyor (explain)...")
+	explainResult, err := ExplainQuery(col, filter, findOpts)
+	if err != nil {
+		logger.Printf("⚠️  Explain hatası: %v\n", err)
+	} else {
+		PrintExplainResults(explainResult, "update_bad (find + UpdateOne)", logger)
+	}
+	var sample struct {
+		ID interface{} `bson:"_id"`
+	}
+	if err := col.FindOne(ctx, filter).Decode(&sample); err == nil {
+		if explain, err := ExplainUpdate(col, bson.M{"_id": sample.ID}, expireUpdate(), false); err == nil {
+			plan := SummarizeExplain(explain)
+			logger.Printf("🧭 Tek UpdateOne planı: %s (%d key, %d doküman) - bu plan eşleşen her doküman için tekrarlanır\n",
+				plan.StageChain(), plan.KeysExamined, plan.DocsExamined)
+		}
+	}
+
+	metrics := MeasureWrite(logger, reset, "", func(ctx context.Context) int {
+		cursor, err := col.Find(ctx, filter, findOpts)
+		if HandleError(logger, "find", err) {
+			return 0
+		}
+		defer cursor.Close(ctx)
+
+		modified := 0
+		for cursor.Next(ctx) {
+			var doc struct {
+				ID interface{} `bson:"_id"`
+			}
+			if HandleError(logger, "decode", cursor.Decode(&doc)) {
+				continue
+			}
+			// KÖTÜ: Her doküman için ayrı bir update komutu ve round trip
+			res, err := col.UpdateOne(ctx, bson.M{"_id": doc.ID}, expireUpdate())
+			if HandleError(logger, "updateOne", err) {
+				continue
+			}
+			modified += int(res.ModifiedCount)
+		}
+		HandleError(logger, "cursor", cursor.Err())
+		return modified
+	})
+	if explainResult != nil {
+		plan := SummarizeExplain(explainResult)
+		metrics.ExecutionStats = &ExecutionStats{
+			ExecutionTimeMillis: plan.ExecutionTimeMs,
+			TotalDocsExamined:   plan.DocsExamined,
+			TotalKeysExamined:   plan.KeysExamined,
+			NReturned:           plan.NReturned,
+		}
+	}
+	col.Drop(ctx)
+
+	logger.Printf("\n⚠️  KÖTÜ GÜNCELLEME SONUÇLARI (find + UpdateOne):\n")
+	PrintMetrics(metrics, "update_bad", logger)
+
+	PrintAntiPatterns(logger)
+	PrintErrorSummary(logger)
+
+	logger.Println("\n✅ Test tamamlandı! Sonuçlar 'update_bad_results.txt' dosyasına kaydedildi.")
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// update_v1.go - GÜNCELLEME İYİLEŞTİRME 1: Index'li filtreyle tek UpdateMany
+// update_bad'deki "oku, sonra her dokümanı ayrı güncelle" yerine filtre sunucuya verilir:
+// 1. Tek komut, tek round trip: Eşleşen dokümanlar sunucuda bulunur ve yerinde güncellenir
+// 2. {status, createdAt} index'i: Eşitlik (status) + aralık (createdAt) → IXSCAN, sadece
+//    eşleşen dokümanlar okunur (ESR kuralı; status tek başına aralığı daraltmaz)
+// 3. Hiçbir _id client'a taşınmaz
+// Dikkat: Index'teki bir alan (status) güncellendiği için her doküman index girdisini de değiştirir.
+//
+// -index=false ile aynı UpdateMany index'siz çalışır (UPDATE → COLLSCAN): Explain farkı gösterir.
+//
+// KULLANIM:
+//   go run main.go config.go analyzer.go antipattern.go errors.go logger.go samples.go sink.go dataset.go orders.go iterations.go writes.go update_v1.go
+//   go run main.go config.go analyzer.go antipattern.go errors.go logger.go samples.go sink.go dataset.go orders.go iterations.go writes.go update_v1.go -index=false -iterations 3
+func main() {
+	n := flag.Int64("n", 200000, "Çalışma kopyasına alınan doküman sayısı")
+	olderThan := flag.Duration("older-than", 30*24*time.Hour, "Bu süreden eski bekleyen siparişler güncellenir (createdAt)")
+	collection := flag.String("collection", "orders_updates", "Çalışma kopyası (her turda yeniden oluşturulur)")
+	withIndex := flag.Bool("index", true, "Filtre için {status, createdAt} index'ini oluştur")
+	flag.Parse()
+
+	logger, err := NewLogger("update_v1_results.txt")
+	if err != nil {
+		fmt.Printf("Logger oluşturulamadı: %v\n", err)
+		return
+	}
+	defer CloseLogger(logger)
+
+	logger.WriteHeader("update_v1 - GÜNCELLEME İYİLEŞTİRME 1 (Index'li UpdateMany)")
+
+	col, err := WriteTarget(*collection)
+	if err != nil {
+		logger.Printf("❌ %v\n", err)
+		return
+	}
+	ctx := context.Background()
+	var indexes []mongo.IndexModel
+	variant := "noindex"
+	if *withIndex {
+		indexes = []mongo.IndexModel{expireIndex}
+		variant = ""
+	}
+	reset := CopyOrders(GetMongo(), col, *n, indexes)
+	if HandleError(logger, "reset", reset(ctx)) {
+		PrintErrorSummary(logger)
+		return
+	}
+
+	filter := ExpireFilter(*olderThan)
+	logger.Printf("📋 %d doküman, filtre: status=PENDING, createdAt < %s, index: %v\n",
+		*n, time.Now().Add(-*olderThan).Format(time.RFC3339), *withIndex)
+
+	// Explain: UpdateMany'nin kendisi (multi: true) - dokümanlar değiştirilmez
+	logger.Println("🔍 Update filtresi analizi yapılıyor (explain)...")
+	explainResult, err := ExplainUpdate(col, filter, expireUpdate(), true)
+	if err != nil {
+		logger.Printf("⚠️  Explain hatası: %v\n", err)
+	} else {
+		PrintExplainResults(explainResult, "update_v1 (UpdateMany)", logger)
+		logger.Printf("🧭 Plan: %s\n", SummarizeExplain(explainResult).StageChain())
+	}
+
+	metrics := MeasureWrite(logger, reset, variant, func(ctx context.Context) int {
+		// İYİLEŞTİRME: Tek komut - filtre ve güncelleme sunucuda
+		res, err := col.UpdateMany(ctx, filter, expireUpdate())
+		if HandleError(logger, "updateMany", err) {
+			return 0
+		}
+		return int(res.ModifiedCount)
+	})
+	if explainResult != nil {
+		metrics.ExecutionStats = UpdateExecutionStats(explainResult)
+	}
+	col.Drop(ctx)
+
+	logger.Printf("\n✅ GÜNCELLEME İYİLEŞTİRME 1 SONUÇLARI (UpdateMany):\n")
+	PrintMetrics(metrics, "update_v1", logger)
+
+	PrintAntiPatterns(logger)
+	PrintErrorSummary(logger)
+
+	logger.Println("\n✅ Test tamamlandı! Sonuçlar 'update_v1_results.txt' dosyasına kaydedildi.")
+}
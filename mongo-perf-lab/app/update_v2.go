@@ -0,0 +1,151 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// update_v2.go - GÜNCELLEME İYİLEŞTİRME 2: Doküman başına farklı değer, BulkWrite ile batch'li
+// UpdateMany her dokümana aynı güncellemeyi uygular. Değer dokümana göre uygulamada hesaplanıyorsa
+// (ör: ödeme sağlayıcısından gelen iade tutarı) tek komut yetmez; update_bad'deki gibi doküman başına
+// UpdateOne yerine güncellemeler BulkWrite ile -batch'lik gruplar halinde gönderilir:
+// 1. Eşleşen dokümanlar {status, createdAt} index'iyle okunur (sadece _id ve total)
+// 2. Her doküman için iade tutarı client'ta hesaplanır ve bir UpdateOne modeli oluşturulur
+// 3. Modeller unordered BulkWrite ile gönderilir: N doküman = N/batch round trip
+// Bu script'te iade tutarı total'a göre kademeli bir kuraldır; gerçek hayatta sunucuda
+// hesaplanamayan (dış kaynaktan gelen) değerler için aynı desen kullanılır.
+//
+// KULLANIM:
+//   go run main.go config.go analyzer.go antipattern.go errors.go logger.go samples.go sink.go dataset.go orders.go iterations.go writes.go update_v2.go
+//   go run main.go config.go analyzer.go antipattern.go errors.go logger.go samples.go sink.go dataset.go orders.go iterations.go writes.go update_v2.go -batch 500 -iterations 3
+func main() {
+	n := flag.Int64("n", 200000, "Çalışma kopyasına alınan doküman sayısı")
+	olderThan := flag.Duration("older-than", 30*24*time.Hour, "Bu süreden eski bekleyen siparişler güncellenir (createdAt)")
+	collection := flag.String("collection", "orders_updates", "Çalışma kopyası (her turda yeniden oluşturulur)")
+	batchSize := flag.Int("batch", 1000, "BulkWrite başına UpdateOne modeli")
+	flag.Parse()
+
+	logger, err := NewLogger("update_v2_results.txt")
+	if err != nil {
+		fmt.Printf("Logger oluşturulamadı: %v\n", err)
+		return
+	}
+	defer CloseLogger(logger)
+
+	logger.WriteHeader("update_v2 - GÜNCELLEME İYİLEŞTİRME 2 (BulkWrite UpdateOne Modelleri)")
+
+	if *batchSize < 1 {
+		logger.Println("❌ -batch pozitif olmalı")
+		return
+	}
+	col, err := WriteTarget(*collection)
+	if err != nil {
+		logger.Printf("❌ %v\n", err)
+		return
+	}
+	ctx := context.Background()
+	reset := CopyOrders(GetMongo(), col, *n, []mongo.IndexModel{expireIndex})
+	if HandleError(logger, "reset", reset(ctx)) {
+		PrintErrorSummary(logger)
+		return
+	}
+
+	filter := ExpireFilter(*olderThan)
+	findOpts := options.Find().SetProjection(bson.M{"_id": 1, "total": 1})
+	logger.Printf("📋 %d doküman, filtre: status=PENDING, createdAt < %s, batch %d\n",
+		*n, time.Now().Add(-*olderThan).Format(time.RFC3339), *batchSize)
+
+	// Explain: Eşleşenleri okuyan find (index'li) ve batch'teki tek bir model
+	logger.Println("🔍 Filtre analizi yapılıyor (explain)...")
+	explainResult, err := ExplainQuery(col, filter, findOpts)
+	if err != nil {
+		logger.Printf("⚠️  Explain hatası: %v\n", err)
+	} else {
+		PrintExplainResults(explainResult, "update_v2 (find + BulkWrite)", logger)
+	}
+	var sample struct {
+		ID interface{} `bson:"_id"`
+	}
+	if err := col.FindOne(ctx, filter).Decode(&sample); err == nil {
+		if explain, err := ExplainUpdate(col, bson.M{"_id": sample.ID}, expireUpdate(), false); err == nil {
+			plan := SummarizeExplain(explain)
+			logger.Printf("🧭 Model başına plan: %s (%d key, %d doküman)\n", plan.StageChain(), plan.KeysExamined, plan.DocsExamined)
+		}
+	}
+
+	var readTime, writeTime time.Duration
+	metrics := MeasureWrite(logger, reset, "", func(ctx context.Context) int {
+		readStart := time.Now()
+		cursor, err := col.Find(ctx, filter, findOpts)
+		if HandleError(logger, "find", err) {
+			return 0
+		}
+		var docs []struct {
+			ID    interface{} `bson:"_id"`
+			Total int         `bson:"total"`
+		}
+		err = cursor.All(ctx, &docs)
+		readTime = time.Since(readStart)
+		if HandleError(logger, "cursor", err) {
+			return 0
+		}
+
+		writeStart := time.Now()
+		modified := 0
+		now := time.Now()
+		opts := options.BulkWrite().SetOrdered(false)
+		for first := 0; first < len(docs); first += *batchSize {
+			batch := docs[first:min(first+*batchSize, len(docs))]
+			models := make([]mongo.WriteModel, len(batch))
+			for i, doc := range batch {
+				// İYİLEŞTİRME: Model başına farklı değer, ama batch başına tek round trip
+				models[i] = mongo.NewUpdateOneModel().
+					SetFilter(bson.M{"_id": doc.ID}).
+					SetUpdate(bson.M{"$set": bson.M{"status": "EXPIRED", "expiredAt": now, "refund": refundFor(doc.Total)}})
+			}
+			res, err := col.BulkWrite(ctx, models, opts)
+			if res != nil {
+				modified += int(res.ModifiedCount)
+			}
+			HandleError(logger, "bulkWrite", err)
+		}
+		writeTime = time.Since(writeStart)
+		return modified
+	})
+	if explainResult != nil {
+		plan := SummarizeExplain(explainResult)
+		metrics.ExecutionStats = &ExecutionStats{
+			ExecutionTimeMillis: plan.ExecutionTimeMs,
+			TotalDocsExamined:   plan.DocsExamined,
+			TotalKeysExamined:   plan.KeysExamined,
+			NReturned:           plan.NReturned,
+		}
+	}
+	col.Drop(ctx)
+
+	logger.Printf("\n✅ GÜNCELLEME İYİLEŞTİRME 2 SONUÇLARI (BulkWrite):\n")
+	logger.Printf("⏱️  Son tur: okuma %v, güncelleme %v\n", readTime.Round(time.Millisecond), writeTime.Round(time.Millisecond))
+	PrintMetrics(metrics, "update_v2", logger)
+
+	PrintAntiPatterns(logger)
+	PrintErrorSummary(logger)
+
+	logger.Println("\n✅ Test tamamlandı! Sonuçlar 'update_v2_results.txt' dosyasına kaydedildi.")
+}
+
+// refundFor - Siparişin iade tutarı: Büyük siparişlerde daha yüksek oran (client tarafı kural)
+func refundFor(total int) int {
+	switch {
+	case total >= 4000:
+		return total * 15 / 100
+	case total >= 1000:
+		return total * 10 / 100
+	}
+	return total * 5 / 100
+}
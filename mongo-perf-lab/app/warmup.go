@@ -0,0 +1,176 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// warmup.go - Bağlantı havuzu ısınma (warm-up) ölçümü
+// mongo.Connect bağlantı açmaz; ilk sorgular bağlantıyı kendisi kurar:
+// TCP + (TLS) + hello handshake + auth. Bu yüzden yeni oluşturulmuş bir client'ın
+// ilk K işlemi, kararlı durumdaki (steady-state) işlemlerden belirgin şekilde yavaştır.
+// Kısa benchmark'larda bu maliyet sonuçları çarpıtır.
+//
+// Bu script her denemede yeni bir client oluşturur ve:
+// 1. İlk K işlemin gecikmesini, sonraki işlemlerin (steady-state) gecikmesiyle karşılaştırır
+// 2. Ölçüm sırasında kaç bağlantı açıldığını ve ortalama kurulma süresini raporlar (pool monitor)
+// 3. -prewarm ile ölçümden önce havuzu ısıtır (WarmUpPool) ve farkı gösterir
+//
+// Diğer benchmark'larda ısıtma için: PERFLAB_PREWARM=N ortam değişkeni (GetMongo okur)
+// veya manifest'te benchmark'a `prewarm: N`.
+//
+// KULLANIM:
+//   go run main.go config.go analyzer.go antipattern.go errors.go logger.go samples.go sink.go warmup.go
+//   go run main.go config.go analyzer.go antipattern.go errors.go logger.go samples.go sink.go warmup.go -k 20 -steady 500 -concurrency 8
+//   go run main.go config.go analyzer.go antipattern.go errors.go logger.go samples.go sink.go warmup.go -concurrency 8 -prewarm
+
+// warmupTrial - Tek bir denemenin sonucu
+type warmupTrial struct {
+	firstOp  time.Duration
+	firstK   []time.Duration // Sıralı
+	steady   []time.Duration // Sıralı
+	pool     PoolStats       // Ölçülen bölüm sırasındaki havuz olayları
+	prewarm  time.Duration
+	prewarmN int
+}
+
+func main() {
+	k := flag.Int("k", 10, "Isınma sayılan ilk işlem sayısı")
+	steadyOps := flag.Int("steady", 200, "Kararlı durum için ölçülen işlem sayısı")
+	concurrency := flag.Int("concurrency", 1, "Eşzamanlı işlem yapan worker sayısı")
+	trials := flag.Int("trials", 3, "Deneme sayısı (her denemede yeni client)")
+	prewarm := flag.Bool("prewarm", false, "Ölçümden önce havuzda -concurrency kadar bağlantı aç")
+	flag.Parse()
+
+	if *k < 1 || *steadyOps < 1 || *concurrency < 1 || *trials < 1 {
+		fmt.Println("❌ -k, -steady, -concurrency ve -trials pozitif olmalı")
+		return
+	}
+
+	logger, err := NewLogger("warmup_results.txt")
+	if err != nil {
+		fmt.Printf("Logger oluşturulamadı: %v\n", err)
+		return
+	}
+	defer CloseLogger(logger)
+
+	logger.WriteHeader("warmup - Bağlantı Havuzu Isınma Ölçümü")
+	logger.Printf("📋 Ayarlar: k=%d steady=%d concurrency=%d trials=%d prewarm=%v\n",
+		*k, *steadyOps, *concurrency, *trials, *prewarm)
+
+	var results []warmupTrial
+	for t := 1; t <= *trials; t++ {
+		logger.Printf("\n▶️  Deneme %d/%d\n", t, *trials)
+		res, err := runWarmupTrial(logger, *k, *steadyOps, *concurrency, *prewarm)
+		if HandleError(logger, "connect", err) {
+			PrintErrorSummary(logger)
+			return
+		}
+		results = append(results, res)
+
+		if *prewarm {
+			logger.Printf("  🔥 Isıtma: %d bağlantı, %v\n", res.prewarmN, res.prewarm.Round(time.Millisecond))
+		}
+		logger.Printf("  ⏱️  İlk işlem: %v\n", res.firstOp)
+		logger.Printf("  ⏱️  İlk %d işlem: p50 %v, max %v\n", len(res.firstK), Percentile(res.firstK, 50), Percentile(res.firstK, 100))
+		logger.Printf("  ⏱️  Kararlı durum: p50 %v, p99 %v\n", Percentile(res.steady, 50), Percentile(res.steady, 99))
+		logger.Printf("  🔌 Ölçüm sırasında açılan bağlantı: %d", res.pool.Ready)
+		if res.pool.Ready > 0 {
+			logger.Printf(" (ortalama kurulma %v)", res.pool.EstablishTime/time.Duration(res.pool.Ready))
+		}
+		logger.Printf("\n")
+	}
+
+	// Özet: Her denemede ilk K işlem ile kararlı durumun karşılaştırması
+	logger.Println("\n=== ÖZET ===")
+	logger.Printf("  %-8s %12s %14s %14s %8s %12s\n", "deneme", "ilk işlem", "ilk K p50", "steady p50", "oran", "bağlantı")
+	for i, r := range results {
+		steadyP50 := Percentile(r.steady, 50)
+		ratio := 0.0
+		if steadyP50 > 0 {
+			ratio = float64(Percentile(r.firstK, 50)) / float64(steadyP50)
+		}
+		logger.Printf("  %-8d %12v %14v %14v %7.1fx %12d\n",
+			i+1, r.firstOp.Round(time.Microsecond), Percentile(r.firstK, 50).Round(time.Microsecond),
+			steadyP50.Round(time.Microsecond), ratio, r.pool.Ready)
+	}
+
+	if !*prewarm {
+		logger.Println("\n💡 İlk işlemler bağlantı kurma maliyetini taşıyor. -prewarm ile tekrar çalıştırıp farkı görün;")
+		logger.Println("   benchmark'larda PERFLAB_PREWARM=N ile aynı ısıtma ölçümden önce yapılır.")
+	} else {
+		logger.Println("\n💡 Havuz ısıtıldığı için ilk K işlem kararlı duruma yakın olmalı - fark kalıyorsa")
+		logger.Println("   sebep bağlantı değil sunucu/cache ısınmasıdır.")
+	}
+	PrintErrorSummary(logger)
+
+	logger.Println("\n✅ Test tamamlandı! Sonuçlar 'warmup_results.txt' dosyasına kaydedildi.")
+}
+
+// runWarmupTrial - Yeni bir client oluşturup ilk K + steady işlemi ölçer
+// İşlemler global bir sıra numarası alır: İlk k işlem "ısınma", kalanlar "kararlı durum" sayılır
+// Sorgu hataları HandleError ile sayılır; sadece client oluşturulamazsa hata döner
+func runWarmupTrial(logger *Logger, k, steadyOps, concurrency int, prewarm bool) (warmupTrial, error) {
+	ctx := context.Background()
+	client, err := mongo.Connect(ctx, MongoClientOptions())
+	if err != nil {
+		return warmupTrial{}, err
+	}
+	defer client.Disconnect(ctx)
+	env := CurrentEnvironment()
+	col := client.Database(env.Database).Collection(env.Collection)
+
+	var res warmupTrial
+	if prewarm {
+		res.prewarmN, res.prewarm = WarmUpPool(col, concurrency)
+	}
+
+	total := k + steadyOps
+	latencies := make([]time.Duration, total)
+	var next int64 = -1
+	var wg sync.WaitGroup
+
+	// Ham örneklerde ilk k işlem "findOne/first", kalanlar "findOne/steady" olarak ayrılır
+	samples := logger.LatencySamples()
+	ResetPoolStats()
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for {
+				i := int(atomic.AddInt64(&next, 1))
+				if i >= total {
+					return
+				}
+				start := time.Now()
+				err := col.FindOne(ctx, bson.M{}).Err()
+				latencies[i] = time.Since(start)
+				op := "findOne/steady"
+				if i < k {
+					op = "findOne/first"
+				}
+				samples.Record(op, w, start, latencies[i], err == nil || err == mongo.ErrNoDocuments)
+				if err != mongo.ErrNoDocuments {
+					HandleError(logger, "findOne", err)
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+	res.pool = SnapshotPoolStats()
+
+	res.firstOp = latencies[0]
+	res.firstK = append([]time.Duration(nil), latencies[:k]...)
+	res.steady = append([]time.Duration(nil), latencies[k:]...)
+	sort.Slice(res.firstK, func(i, j int) bool { return res.firstK[i] < res.firstK[j] })
+	sort.Slice(res.steady, func(i, j int) bool { return res.steady[i] < res.steady[j] })
+	return res, nil
+}
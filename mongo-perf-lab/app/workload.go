@@ -0,0 +1,249 @@
+package main
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// workload.go - Yük profiline göre işlem çalıştıran workload runner
+// Bu runner, LoadProfile'da tanımlanan hıza (ops/sn) göre işlem "token"ları üretir
+// ve bu token'ları sabit sayıda worker goroutine'e dağıtır.
+//
+// Neden sabit hız (open-loop)?
+// - Closed-loop testlerde (her worker bir işlem bitince diğerine geçer) sistem yavaşladıkça
+//   yük de otomatik azalır ve gecikme artışı gizlenir
+// - Sabit hızda, worker'lar yetişemezse bu "atlanan işlem" olarak raporlanır
+//
+// İstek bütçesi verilirse her işlem kendi BudgetRun'ı ile çalışır (bkz. budget.go): İşlem
+// Mongo çağrılarını BudgetFrom(ctx).Phase ile yapar, aşım raporu sonuca eklenir.
+
+// WorkloadOp - Runner'ın her token için çalıştırdığı tek işlem
+// workerID: İşlemi çalıştıran worker'ın numarası (0..workers-1)
+type WorkloadOp func(ctx context.Context, workerID int) error
+
+// TimelinePoint - Zaman çizelgesindeki 1 saniyelik dilim
+// Hedef hız ile gerçekleşen hız yan yana tutulur, böylece rapor
+// istenen profil ile gerçek davranışı karşılaştırabilir
+type TimelinePoint struct {
+	Second     int           // Çalışmanın kaçıncı saniyesi
+	Phase      string        // O saniyede aktif olan profil aşaması
+	TargetRate float64       // Hedeflenen hız (ops/sn, saniye içi ortalama)
+	Completed  int           // Bu saniyede tamamlanan işlem sayısı
+	Errors     int           // Bu saniyede hata veren işlem sayısı
+	Dropped    int           // Worker'lar yetişemediği için atlanan işlem sayısı
+	P50        time.Duration // Medyan gecikme
+	P99        time.Duration // %99'luk gecikme
+}
+
+// WorkloadResult - Workload çalışmasının toplam sonucu
+type WorkloadResult struct {
+	Profile      *LoadProfile
+	Workers      int
+	Duration     time.Duration
+	TotalOps     int
+	TotalErrors  int
+	TotalDropped int
+	Latencies    []time.Duration // Tüm işlemlerin gecikmeleri (sıralı)
+	Timeline     []TimelinePoint
+	Budget       *BudgetStats // İstek bütçesi kullanımı (nil = bütçesiz çalıştı)
+}
+
+// timelineBucket - Saniye bazlı ham veri (runner içinde kullanılır)
+type timelineBucket struct {
+	mu            sync.Mutex
+	phase         string
+	targetSum     float64
+	targetSamples int
+	latencies     []time.Duration
+	errors        int
+	dropped       int
+}
+
+// RunWorkload - Profil bitene kadar op'u hedef hızda çalıştırır
+// Parametreler:
+//   - ctx: İptal için context
+//   - profile: Hedef hızı zamana bağlı veren yük profili
+//   - workers: Paralel çalışan worker sayısı (maksimum eşzamanlılık)
+//   - budget: İşlem başına istek bütçesi (nil = bütçesiz)
+//   - samples: Ham gecikme örnekleri (nil = yazılmaz); işlem türü "workload/<aşama>" olarak yazılır
+//   - op: Her token için çalıştırılacak işlem
+//
+// Döndürür:
+//   - *WorkloadResult: Toplam metrikler ve saniye bazlı zaman çizelgesi
+func RunWorkload(ctx context.Context, profile *LoadProfile, workers int, budget *RequestBudget, samples *LatencySamples, op WorkloadOp) *WorkloadResult {
+	totalSeconds := int(profile.TotalDuration()/time.Second) + 1
+	buckets := make([]*timelineBucket, totalSeconds)
+	for i := range buckets {
+		buckets[i] = &timelineBucket{}
+	}
+	bucketAt := func(elapsed time.Duration) *timelineBucket {
+		sec := int(elapsed / time.Second)
+		if sec >= len(buckets) {
+			sec = len(buckets) - 1
+		}
+		return buckets[sec]
+	}
+
+	// Token kanalı: Buffer, kısa süreli gecikmeleri tolere eder
+	// Buffer dolarsa worker'lar yetişemiyor demektir → işlem atlanır
+	tokens := make(chan struct{}, workers*2)
+	start := time.Now()
+	var budgetStats *BudgetStats
+	if budget != nil {
+		budgetStats = NewBudgetStats(budget)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(workerID int) {
+			defer wg.Done()
+			for range tokens {
+				opCtx := ctx
+				var run *BudgetRun
+				if budget != nil {
+					run = budget.Start()
+					opCtx = WithBudgetRun(ctx, run)
+				}
+				opStart := time.Now()
+				err := op(opCtx, workerID)
+				latency := time.Since(opStart)
+				if run != nil {
+					budgetStats.Record(run)
+				}
+
+				b := bucketAt(time.Since(start))
+				b.mu.Lock()
+				if err != nil {
+					b.errors++
+				} else {
+					b.latencies = append(b.latencies, latency)
+				}
+				phase := b.phase
+				b.mu.Unlock()
+				samples.Record("workload/"+phase, workerID, opStart, latency, err == nil)
+			}
+		}(i)
+	}
+
+	// Pacer: Her tick'te profile göre kaç token üretilmesi gerektiğini hesaplar
+	// due: Henüz üretilmemiş (kesirli) işlem miktarı
+	ticker := time.NewTicker(5 * time.Millisecond)
+	last := start
+	due := 0.0
+pacer:
+	for {
+		select {
+		case <-ctx.Done():
+			break pacer
+		case now := <-ticker.C:
+			elapsed := now.Sub(start)
+			rate, phase := profile.RateAt(elapsed)
+			if phase == "" {
+				break pacer // Profil bitti
+			}
+
+			b := bucketAt(elapsed)
+			b.mu.Lock()
+			b.phase = phase
+			b.targetSum += rate
+			b.targetSamples++
+			b.mu.Unlock()
+
+			due += rate * now.Sub(last).Seconds()
+			last = now
+			for due >= 1 {
+				select {
+				case tokens <- struct{}{}:
+				default:
+					b.mu.Lock()
+					b.dropped++
+					b.mu.Unlock()
+				}
+				due--
+			}
+		}
+	}
+	ticker.Stop()
+	close(tokens)
+	wg.Wait()
+
+	result := &WorkloadResult{
+		Profile:  profile,
+		Workers:  workers,
+		Duration: time.Since(start),
+		Budget:   budgetStats,
+	}
+	for sec, b := range buckets {
+		if b.targetSamples == 0 && len(b.latencies) == 0 && b.errors == 0 {
+			continue
+		}
+		sort.Slice(b.latencies, func(i, j int) bool { return b.latencies[i] < b.latencies[j] })
+
+		point := TimelinePoint{
+			Second:    sec,
+			Phase:     b.phase,
+			Completed: len(b.latencies),
+			Errors:    b.errors,
+			Dropped:   b.dropped,
+			P50:       Percentile(b.latencies, 50),
+			P99:       Percentile(b.latencies, 99),
+		}
+		if b.targetSamples > 0 {
+			point.TargetRate = b.targetSum / float64(b.targetSamples)
+		}
+		result.Timeline = append(result.Timeline, point)
+
+		result.TotalOps += len(b.latencies)
+		result.TotalErrors += b.errors
+		result.TotalDropped += b.dropped
+		result.Latencies = append(result.Latencies, b.latencies...)
+	}
+	sort.Slice(result.Latencies, func(i, j int) bool { return result.Latencies[i] < result.Latencies[j] })
+
+	return result
+}
+
+// PrintWorkloadReport - Workload sonucunu ve zaman çizelgesini yazdırır
+// Zaman çizelgesinde her saniye için hedef hız ile gerçekleşen hız yan yana gösterilir,
+// böylece sistemin profili ne zaman takip edemediği (ör: spike sırasında) görülebilir
+func PrintWorkloadReport(result *WorkloadResult, logger *Logger) {
+	logger.Printf("\n=== WORKLOAD SONUÇLARI - profil: %s ===\n", result.Profile.Name)
+	logger.Printf("📋 Aşamalar: %s\n", result.Profile.String())
+	logger.Printf("👥 Worker sayısı: %d\n", result.Workers)
+	logger.Printf("⏱️  Toplam Süre: %v\n", result.Duration)
+	logger.Printf("✅ Tamamlanan İşlem: %d (%.1f ops/sn)\n",
+		result.TotalOps, float64(result.TotalOps)/result.Duration.Seconds())
+	logger.Printf("❌ Hatalı İşlem: %d\n", result.TotalErrors)
+	logger.Printf("⏭️  Atlanan İşlem (worker yetişemedi): %d\n", result.TotalDropped)
+	logger.Printf("📈 Gecikme: p50=%v p95=%v p99=%v max=%v\n",
+		Percentile(result.Latencies, 50),
+		Percentile(result.Latencies, 95),
+		Percentile(result.Latencies, 99),
+		Percentile(result.Latencies, 100))
+	if result.Budget != nil {
+		PrintBudgetReport(result.Budget, logger)
+	}
+
+	logger.Println("\n📊 Zaman Çizelgesi (hedef profil vs gerçekleşen):")
+	logger.Printf("  %4s  %-7s %10s %10s %7s %6s %7s %12s %12s\n",
+		"sn", "aşama", "hedef/sn", "gerçek/sn", "oran", "hata", "atlanan", "p50", "p99")
+	for _, point := range result.Timeline {
+		ratio := 0.0
+		if point.TargetRate > 0 {
+			ratio = float64(point.Completed) / point.TargetRate * 100
+		}
+		marker := ""
+		// Hedefin %90'ının altında kalındıysa işaretle - sistem profili takip edemiyor
+		if point.TargetRate > 0 && ratio < 90 {
+			marker = " ⚠️"
+		}
+		logger.Printf("  %4d  %-7s %10.1f %10d %6.1f%% %6d %7d %12v %12v%s\n",
+			point.Second, point.Phase, point.TargetRate, point.Completed, ratio,
+			point.Errors, point.Dropped, point.P50, point.P99, marker)
+	}
+	logger.Println(strings.Repeat("=", 50) + "\n")
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// workload_profile.go - Yük profili ile (ramp / steady / spike) sorgu çalıştırma
+// Bu script, sabit bir sorguyu LoadProfile'da tanımlanan hızda çalıştırır ve
+// gerçekleşen throughput/gecikme zaman çizelgesini hedef profil ile yan yana raporlar.
+//
+// KULLANIM:
+//   go run main.go config.go analyzer.go antipattern.go errors.go logger.go samples.go sink.go loadprofile.go budget.go workload.go targeting.go workload_profile.go -profile spike
+//   go run main.go config.go analyzer.go antipattern.go errors.go logger.go samples.go sink.go loadprofile.go budget.go workload.go targeting.go workload_profile.go \
+//       -profile "ramp:0-1000:30s,steady:1000:60s,spike:3000:10s" -workers 50
+//   go run main.go config.go analyzer.go antipattern.go errors.go logger.go samples.go sink.go loadprofile.go budget.go workload.go targeting.go workload_profile.go \
+//       -budget 50ms -budget-split count=10,explain=10,find=30,getMore=50 -page 1000
+//
+// Sorgu: Rastgele bir status ve tutar aralığı için tek doküman (FindOne)
+// status_1 index'i varsa her işlem IXSCAN ile hızlıca biter
+//
+// -budget verilirse işlem sayfalı bir liste isteğidir (toplam sayı + plan + sayfa):
+// count (en fazla -count-limit), explain (queryPlanner), find (ilk batch) ve getMore (sayfanın
+// kalanı) aşamaları tek bir istek bütçesini paylaşır (bkz. budget.go). Rapor, deadline'ı aşılan
+// isteklerin hangi aşamada kesildiğini ve bütçeyi hangi aşamanın tükettiğini gösterir.
+func main() {
+	profileSpec := flag.String("profile", "steady", "Yük profili (hazır: ramp, steady, spike) veya tip:hız:süre listesi")
+	workers := flag.Int("workers", 20, "Paralel worker sayısı (maksimum eşzamanlı işlem)")
+	budgetTotal := flag.Duration("budget", 0, "İşlem başına istek bütçesi (0 = bütçesiz FindOne; verilirse sayfalı liste isteği)")
+	budgetSplit := flag.String("budget-split", defaultBudgetSplit, "Bütçenin aşamalara yüzde paylaşımı")
+	page := flag.Int64("page", 500, "-budget ile liste isteğinin sayfa boyutu (find limit)")
+	batch := flag.Int("batch", 100, "-budget ile cursor batch boyutu (sayfanın kalanı getMore ile gelir)")
+	countLimit := flag.Int64("count-limit", 10000, "-budget ile count'un en fazla sayacağı doküman")
+	flag.Parse()
+
+	profile, err := ParseLoadProfile(*profileSpec)
+	if err != nil {
+		fmt.Printf("Profil hatası: %v\n", err)
+		return
+	}
+
+	var budget *RequestBudget
+	if *budgetTotal > 0 {
+		if budget, err = ParseRequestBudget(*budgetTotal, *budgetSplit); err != nil {
+			fmt.Printf("-budget-split: %v\n", err)
+			return
+		}
+	}
+
+	logger, err := NewLogger("workload_profile_results.txt")
+	if err != nil {
+		fmt.Printf("Logger oluşturulamadı: %v\n", err)
+		return
+	}
+	defer CloseLogger(logger)
+
+	logger.WriteHeader("workload_profile - Yük Profili (" + profile.Name + ")")
+
+	col := GetMongo()
+	ctx := context.Background()
+
+	statuses := []string{"PAID", "CANCELLED", "PENDING"}
+
+	logger.Printf("🚀 Profil çalıştırılıyor: %s (toplam %v)\n", profile.String(), profile.TotalDuration())
+	if budget != nil {
+		logger.Printf("⏳ İstek bütçesi: %s, sayfa %d (batch %d)\n", budget, *page, *batch)
+	}
+
+	// Workload boyunca tüm işlemlerin toplam query targeting oranı (sadece tek explain değil)
+	targeting := StartTargetingSampler(col.Database(), time.Second)
+
+	result := RunWorkload(ctx, profile, *workers, budget, logger.LatencySamples(), func(ctx context.Context, workerID int) error {
+		// math/rand global kaynağı goroutine-safe, burada yeterli
+		filter := bson.M{
+			"status": statuses[rand.Intn(len(statuses))],
+			"total":  bson.M{"$gte": rand.Intn(5000)},
+		}
+		if budget == nil {
+			var order bson.M
+			err := col.FindOne(ctx, filter).Decode(&order)
+			if err == mongo.ErrNoDocuments {
+				return nil // Eşleşme olmaması hata değil
+			}
+			return err
+		}
+		return listRequest(ctx, col, filter, *page, int32(*batch), *countLimit)
+	})
+
+	samples := targeting.Stop()
+
+	PrintWorkloadReport(result, logger)
+	PrintTargetingTimeline(samples, logger)
+
+	record := newMetricsRecord("workload_profile")
+	record.DurationMs = float64(result.Duration) / float64(time.Millisecond)
+	record.RecordsRead = result.TotalOps
+	record.Targeting = TotalTargeting(samples).Ratio()
+	if result.Budget != nil {
+		record.BudgetExceeded = result.Budget.Exceeded()
+	}
+	logger.WriteRecord(record)
+
+	logger.Println("\n✅ Test tamamlandı! Sonuçlar 'workload_profile_results.txt' dosyasına kaydedildi.")
+}
+
+// listRequest - Sayfalı liste isteği: toplam sayı, plan ve bir sayfa doküman
+// Her Mongo çağrısı isteğin bütçesindeki kendi aşamasında çalışır; find ilk batch'i,
+// getMore sayfanın kalanını okur
+func listRequest(ctx context.Context, col *mongo.Collection, filter bson.M, page int64, batch int32, countLimit int64) error {
+	run := BudgetFrom(ctx)
+
+	err := run.Phase(ctx, "count", func(ctx context.Context) error {
+		_, err := col.CountDocuments(ctx, filter, options.Count().SetLimit(countLimit))
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	err = run.Phase(ctx, "explain", func(ctx context.Context) error {
+		cmd := bson.D{
+			{Key: "explain", Value: bson.D{{Key: "find", Value: col.Name()}, {Key: "filter", Value: filter}, {Key: "limit", Value: page}}},
+			{Key: "verbosity", Value: "queryPlanner"},
+		}
+		return col.Database().RunCommand(ctx, cmd).Err()
+	})
+	if err != nil {
+		return err
+	}
+
+	var cursor *mongo.Cursor
+	err = run.Phase(ctx, "find", func(ctx context.Context) error {
+		var err error
+		if cursor, err = col.Find(ctx, filter, options.Find().SetLimit(page).SetBatchSize(batch)); err != nil {
+			return err
+		}
+		// İlk batch find yanıtıyla geldi: Next getMore göndermeden okur
+		for cursor.RemainingBatchLength() > 0 && cursor.Next(ctx) {
+		}
+		return cursor.Err()
+	})
+	if cursor != nil {
+		defer cursor.Close(context.Background())
+	}
+	if err != nil {
+		return err
+	}
+
+	return run.Phase(ctx, "getMore", func(ctx context.Context) error {
+		for cursor.Next(ctx) {
+		}
+		return cursor.Err()
+	})
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"time"
+)
+
+// write_bad.go - KÖTÜ YAZMA ÖRNEĞİ: Her doküman için ayrı InsertOne
+// Yazma serisinin başlangıç noktası (read_bad'in karşılığı). Dokümanlar döngüde tek tek yazılır:
+// 1. Her doküman bir round trip: Süre, ağ gecikmesi × doküman sayısı kadar büyür
+// 2. Her doküman için ayrı onay: Write concern beklemesi de dokümana bölünmez
+// 3. Sunucu tarafında her insert ayrı bir komut: Komut ayrıştırma ve kilit maliyeti her dokümanda yeniden ödenir
+// İyileştirmeler: write_v1 (InsertMany, batch boyutu), write_v2 (BulkWrite, ordered ↔ unordered)
+//
+// KULLANIM:
+//   go run main.go config.go analyzer.go antipattern.go errors.go logger.go samples.go sink.go dataset.go orders.go iterations.go writes.go write_bad.go
+//   go run main.go config.go analyzer.go antipattern.go errors.go logger.go samples.go sink.go dataset.go orders.go iterations.go writes.go write_bad.go -n 20000 -iterations 3
+func main() {
+	n := flag.Int("n", 50000, "Yazılacak doküman sayısı")
+	collection := flag.String("collection", "orders_writes", "Yazılacak (her turda silinen) koleksiyon")
+	seed := flag.Int64("seed", 42, "Doküman üretimi için seed")
+	flag.Parse()
+
+	logger, err := NewLogger("write_bad_results.txt")
+	if err != nil {
+		fmt.Printf("Logger oluşturulamadı: %v\n", err)
+		return
+	}
+	defer CloseLogger(logger)
+
+	logger.WriteHeader("write_bad - KÖTÜ YAZMA ÖRNEĞİ (Doküman Başına InsertOne)")
+
+	col, err := WriteTarget(*collection)
+	if err != nil {
+		logger.Printf("❌ %v\n", err)
+		return
+	}
+	logger.Printf("📦 %d doküman üretiliyor (seed %d)...\n", *n, *seed)
+	docs := NewWriteDocs(*n, *seed)
+
+	metrics := MeasureWrite(logger, col.Drop, "", func(ctx context.Context) int {
+		written := 0
+		for _, doc := range docs {
+			// KÖTÜ: Her doküman ayrı bir insert komutu ve ayrı bir round trip
+			if _, err := col.InsertOne(ctx, doc); HandleError(logger, "insertOne", err) {
+				continue
+			}
+			written++
+		}
+		return written
+	})
+	col.Drop(context.Background())
+
+	logger.Printf("\n⚠️  KÖTÜ YAZMA SONUÇLARI (InsertOne):\n")
+	PrintMetrics(metrics, "write_bad", logger)
+	if metrics.RecordsWritten > 0 {
+		logger.Printf("\n💡 Doküman başına %v: Bu sürenin çoğu round trip'tir, batch'lemek (write_v1) onu dokümanlara böler\n",
+			(metrics.Duration / time.Duration(metrics.RecordsWritten)).Round(time.Microsecond))
+	}
+
+	PrintErrorSummary(logger)
+
+	logger.Println("\n✅ Test tamamlandı! Sonuçlar 'write_bad_results.txt' dosyasına kaydedildi.")
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// write_v1.go - YAZMA İYİLEŞTİRME 1: InsertMany ile batch'li yazma
+// write_bad'deki doküman başına InsertOne yerine dokümanlar batch'ler halinde tek komutla yazılır:
+// Round trip ve onay beklemesi batch'e bölünür. Batch boyutu -batches ile taranır:
+// - Küçük batch: Round trip maliyeti hâlâ baskın
+// - Büyük batch: Driver komutu 48 MB / 100000 doküman sınırında kendisi böler, client bellek
+//   kullanımı (tüm batch'in BSON'u) büyür, hata durumunda tekrar edilecek iş büyür
+// Her batch boyutu ayrı bir QueryMetrics/metrik kaydıdır (variant: b<boyut>), sonunda eğri yazılır.
+//
+// KULLANIM:
+//   go run main.go config.go analyzer.go antipattern.go errors.go logger.go samples.go sink.go dataset.go orders.go iterations.go flags.go writes.go write_v1.go
+//   go run main.go config.go analyzer.go antipattern.go errors.go logger.go samples.go sink.go dataset.go orders.go iterations.go flags.go writes.go write_v1.go -n 500000 -batches 100,1000,10000 -iterations 3
+func main() {
+	n := flag.Int("n", 200000, "Her batch boyutunda yazılacak doküman sayısı")
+	batchList := flag.String("batches", "10,100,1000,10000", "InsertMany batch boyutları (tarama)")
+	collection := flag.String("collection", "orders_writes", "Yazılacak (her turda silinen) koleksiyon")
+	seed := flag.Int64("seed", 42, "Doküman üretimi için seed")
+	flag.Parse()
+
+	logger, err := NewLogger("write_v1_results.txt")
+	if err != nil {
+		fmt.Printf("Logger oluşturulamadı: %v\n", err)
+		return
+	}
+	defer CloseLogger(logger)
+
+	logger.WriteHeader("write_v1 - YAZMA İYİLEŞTİRME 1 (InsertMany Batch Boyutu)")
+
+	sizes, err := parseIntList(*batchList)
+	if err != nil {
+		logger.Printf("❌ -batches: %v\n", err)
+		return
+	}
+	col, err := WriteTarget(*collection)
+	if err != nil {
+		logger.Printf("❌ %v\n", err)
+		return
+	}
+	logger.Printf("📦 %d doküman üretiliyor (seed %d)...\n", *n, *seed)
+	docs := NewWriteDocs(*n, *seed)
+
+	var results []QueryMetrics
+	for _, size := range sizes {
+		logger.Printf("\n▶️  InsertMany, batch %d\n", size)
+		batches := writeBatches(docs, size)
+		metrics := MeasureWrite(logger, col.Drop, fmt.Sprintf("b%d", size), func(ctx context.Context) int {
+			written := 0
+			for _, batch := range batches {
+				res, err := col.InsertMany(ctx, batch)
+				if res != nil {
+					written += len(res.InsertedIDs)
+				}
+				HandleError(logger, "insertMany", err)
+			}
+			return written
+		})
+		PrintMetrics(metrics, "write_v1", logger)
+		results = append(results, metrics)
+	}
+	col.Drop(context.Background())
+
+	// Batch boyutu eğrisi
+	logger.Println("\n=== BATCH BOYUTU EĞRİSİ ===")
+	best := results[0]
+	for _, m := range results {
+		if m.WriteRate() > best.WriteRate() {
+			best = m
+		}
+	}
+	logger.Printf("  %-8s %12s %14s %12s\n", "batch", "süre", "doküman/sn", "alloc MB")
+	for _, m := range results {
+		bar := ""
+		if best.WriteRate() > 0 {
+			bar = strings.Repeat("█", int(m.WriteRate()/best.WriteRate()*40))
+		}
+		logger.Printf("  %-8s %12v %14.0f %12.1f %s\n", m.Variant, m.Duration.Round(time.Millisecond),
+			m.WriteRate(), float64(m.TotalAllocated)/(1024*1024), bar)
+	}
+	logger.Printf("\n🏆 En yüksek throughput: %s (%.0f doküman/sn)\n", best.Variant, best.WriteRate())
+	logger.Println("💡 Eğri düzleştikten sonra batch'i büyütmek hızı artırmaz, sadece bellek ve hata başına kaybı büyütür:")
+	logger.Println("   Düzleşmenin başladığı en küçük boyut genelde doğru seçimdir.")
+
+	PrintErrorSummary(logger)
+
+	logger.Println("\n✅ Test tamamlandı! Sonuçlar 'write_v1_results.txt' dosyasına kaydedildi.")
+}
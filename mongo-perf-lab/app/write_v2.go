@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// write_v2.go - YAZMA İYİLEŞTİRME 2: BulkWrite, ordered ↔ unordered
+// BulkWrite, InsertMany gibi batch'li yazar; fark sıralama garantisidir:
+// - ordered (varsayılan): Modeller sırayla uygulanır, ilk hatada batch'in geri kalanı YAZILMAZ
+// - unordered: Sunucu sırayı korumak zorunda değildir, hatalı dokümanı atlayıp devam eder;
+//   sharded kümede mongos alt batch'leri shard'lara paralel gönderebilir
+//
+// Hatasız veride iki yöntem tek sunucuda benzer hızdadır. -dup-rate ile dokümanların bir kısmı
+// önceki bir dokümanın _id'sini taşır (tekrar gelen olay, yeniden denenen istek): ordered batch ilk
+// duplicate key'de durur ve sonraki dokümanlar kaybolur, unordered sadece tekrarları atlar.
+// Duplicate key hataları beklenen sonuçtur, hata özetine değil tabloya yazılır.
+//
+// KULLANIM:
+//   go run main.go config.go analyzer.go antipattern.go errors.go logger.go samples.go sink.go dataset.go orders.go iterations.go writes.go write_v2.go
+//   go run main.go config.go analyzer.go antipattern.go errors.go logger.go samples.go sink.go dataset.go orders.go iterations.go writes.go write_v2.go -batch 500 -dup-rate 0.01 -iterations 3
+
+// bulkResult - Bir yöntemin metrikleri ve son turdaki duplicate key sayısı
+type bulkResult struct {
+	metrics    QueryMetrics
+	duplicates int
+}
+
+func main() {
+	n := flag.Int("n", 200000, "Her yöntemde yazılacak doküman sayısı")
+	batchSize := flag.Int("batch", 1000, "BulkWrite başına model sayısı")
+	dupRate := flag.Float64("dup-rate", 0, "Önceki bir dokümanın _id'sini taşıyan doküman oranı (0-1)")
+	collection := flag.String("collection", "orders_writes", "Yazılacak (her turda silinen) koleksiyon")
+	seed := flag.Int64("seed", 42, "Doküman üretimi için seed")
+	flag.Parse()
+
+	logger, err := NewLogger("write_v2_results.txt")
+	if err != nil {
+		fmt.Printf("Logger oluşturulamadı: %v\n", err)
+		return
+	}
+	defer CloseLogger(logger)
+
+	logger.WriteHeader("write_v2 - YAZMA İYİLEŞTİRME 2 (BulkWrite Ordered vs Unordered)")
+
+	if *batchSize < 1 || *dupRate < 0 || *dupRate >= 1 {
+		logger.Println("❌ -batch pozitif, -dup-rate 0 ile 1 arasında olmalı")
+		return
+	}
+	col, err := WriteTarget(*collection)
+	if err != nil {
+		logger.Printf("❌ %v\n", err)
+		return
+	}
+	logger.Printf("📦 %d doküman üretiliyor (seed %d, tekrar oranı %.2f%%)...\n", *n, *seed, *dupRate*100)
+	docs := NewWriteDocs(*n, *seed)
+
+	// Tekrarlar: Belirli aralıklarla bir doküman, kendinden bir önceki dokümanın _id'sini alır
+	expected := len(docs)
+	if *dupRate > 0 {
+		every := max(int(1 / *dupRate), 2)
+		for i := every - 1; i < len(docs); i += every {
+			docs[i].(bson.M)["_id"] = docs[i-1].(bson.M)["_id"]
+			expected--
+		}
+	}
+
+	var models [][]mongo.WriteModel
+	for _, batch := range writeBatches(docs, *batchSize) {
+		ms := make([]mongo.WriteModel, len(batch))
+		for i, doc := range batch {
+			ms[i] = mongo.NewInsertOneModel().SetDocument(doc)
+		}
+		models = append(models, ms)
+	}
+
+	var results []bulkResult
+	for _, ordered := range []bool{true, false} {
+		variant := "unordered"
+		if ordered {
+			variant = "ordered"
+		}
+		logger.Printf("\n▶️  BulkWrite %s, batch %d\n", variant, *batchSize)
+		opts := options.BulkWrite().SetOrdered(ordered)
+		res := bulkResult{}
+		res.metrics = MeasureWrite(logger, col.Drop, variant, func(ctx context.Context) int {
+			written, duplicates := 0, 0
+			for _, batch := range models {
+				r, err := col.BulkWrite(ctx, batch, opts)
+				if r != nil {
+					written += int(r.InsertedCount)
+				}
+				var bulkErr mongo.BulkWriteException
+				if errors.As(err, &bulkErr) && bulkErr.WriteConcernError == nil && onlyDuplicateKeys(bulkErr) {
+					duplicates += len(bulkErr.WriteErrors)
+					continue
+				}
+				HandleError(logger, "bulkWrite", err)
+			}
+			res.duplicates = duplicates
+			return written
+		})
+		PrintMetrics(res.metrics, "write_v2", logger)
+		results = append(results, res)
+	}
+	col.Drop(context.Background())
+
+	logger.Println("\n=== KARŞILAŞTIRMA ===")
+	logger.Printf("  %-10s %12s %14s %10s %10s %10s\n", "yöntem", "süre", "doküman/sn", "yazılan", "tekrar", "kayıp")
+	for _, r := range results {
+		m := r.metrics
+		logger.Printf("  %-10s %12v %14.0f %10d %10d %10d\n", m.Variant, m.Duration.Round(time.Millisecond),
+			m.WriteRate(), m.RecordsWritten, r.duplicates, expected-m.RecordsWritten)
+	}
+
+	if lost := expected - results[0].metrics.RecordsWritten; lost > 0 {
+		logger.Printf("\n🚨 ordered: %d geçerli doküman yazılmadı - her batch ilk duplicate key'de durdu.\n", lost)
+		logger.Println("💡 Tekrar gelebilen veride unordered kullanın (ya da tekrarları upsert ile yazın);")
+		logger.Println("   ordered sadece sonraki yazmalar öncekilere bağlıysa gerekir.")
+	} else {
+		logger.Println("\n💡 Hatasız veride fark küçüktür; unordered'ın kazancı sharded kümede ve hata durumunda görünür (-dup-rate).")
+	}
+
+	PrintErrorSummary(logger)
+
+	logger.Println("\n✅ Test tamamlandı! Sonuçlar 'write_v2_results.txt' dosyasına kaydedildi.")
+}
+
+// onlyDuplicateKeys - Toplu yazma hatalarının hepsi duplicate key (11000) mi
+func onlyDuplicateKeys(err mongo.BulkWriteException) bool {
+	for _, we := range err.WriteErrors {
+		if we.Code != 11000 {
+			return false
+		}
+	}
+	return len(err.WriteErrors) > 0
+}
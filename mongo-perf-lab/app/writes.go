@@ -0,0 +1,162 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"benchkit"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// writes.go - Yazma (write_*) ve güncelleme (update_*) serileri için ortak yardımcılar
+// Okuma serisindeki gibi her script aynı işi farklı yöntemle yapar ve sonucu aynı QueryMetrics /
+// PrintMetrics raporuyla yazar (okunan kayıt yerine yazılan kayıt ve doküman/sn):
+//   write_bad - Her doküman için ayrı InsertOne (bir round trip ve bir onay / doküman)
+//   write_v1  - InsertMany, batch boyutu taraması
+//   write_v2  - BulkWrite, ordered ↔ unordered
+//
+// Dokümanlar bir kez üretilir ve her turda aynen yazılır; her turdan önce hedef koleksiyon silinir
+// (silme ölçüme dahil değildir). Hedef perfdb.orders olamaz.
+//
+// Güncelleme serisi aynı raporu kullanır (yazılan kayıt = değiştirilen doküman):
+//   update_bad - Eşleşen dokümanları okuyup her biri için ayrı UpdateOne
+//   update_v1  - Index'li filtreyle tek UpdateMany
+//   update_v2  - Doküman başına farklı değerler: BulkWrite ile batch'li UpdateOne modelleri
+// Her turdan önce orders'ın ilk dokümanları çalışma kopyasına alınır (CopyOrders), filtreler
+// ExplainUpdate ile explain edilir (update explain'i dokümanları değiştirmez).
+
+// NewWriteDocs - n sipariş dokümanı üretir (_id'ler burada atanır: her tur ve yöntem aynı dokümanları yazar)
+func NewWriteDocs(n int, seed int64) []interface{} {
+	rng := workerRand(seed, 0)
+	now := time.Now()
+	docs := make([]interface{}, n)
+	for i := range docs {
+		order := newOrder(rng, now, ShapeProfile{})
+		order["_id"] = primitive.NewObjectID()
+		docs[i] = order
+	}
+	return docs
+}
+
+// WriteTarget - Yazma serisinin hedef koleksiyonu (her turda silinir)
+func WriteTarget(name string) (*mongo.Collection, error) {
+	if name == "orders" {
+		return nil, fmt.Errorf("-collection orders olamaz: koleksiyon her turda silinir")
+	}
+	return GetMongo().Database().Collection(name), nil
+}
+
+// MeasureWrite - write'ı -iterations turu boyunca ölçer ve QueryMetrics döndürür
+// reset her turdan önce (ölçüm dışında) hedefi hazırlar: yazma serisinde col.Drop, güncelleme
+// serisinde CopyOrders. write, yazdığı/değiştirdiği doküman sayısını döner; hatalar write içinde
+// HandleError ile bildirilir
+func MeasureWrite(logger *Logger, reset func(ctx context.Context) error, variant string, write func(ctx context.Context) int) QueryMetrics {
+	ctx := context.Background()
+	metrics := QueryMetrics{Variant: variant}
+	var mem benchkit.MemUsage
+	iterations := NewIterations(logger)
+	for iterations.Next() {
+		if err := reset(ctx); HandleError(logger, "reset", err) {
+			break
+		}
+
+		memProbe := benchkit.StartMemProbe()
+		start := time.Now()
+		metrics.RecordsWritten = write(ctx)
+		metrics.Duration = time.Since(start)
+		mem = memProbe.Stop()
+
+		iterations.Record(metrics.Duration, mem.PeakGrowth)
+		logger.Printf("  ✍️  %d doküman, %v (%.0f doküman/sn)\n",
+			metrics.RecordsWritten, metrics.Duration.Round(time.Millisecond), metrics.WriteRate())
+	}
+	metrics.SetMemory(mem)
+	metrics.Iterations = iterations.Stats()
+	return metrics
+}
+
+// writeBatches - docs'u size'lık parçalara böler (son parça kısa olabilir)
+func writeBatches(docs []interface{}, size int) [][]interface{} {
+	var batches [][]interface{}
+	for first := 0; first < len(docs); first += size {
+		batches = append(batches, docs[first:min(first+size, len(docs))])
+	}
+	return batches
+}
+
+// ExpireFilter - Güncelleme serisinin senaryosu: olderThan'dan eski bekleyen siparişler
+// Filtre eşitlik (status) + aralık (createdAt) olduğu için ESR'ye uygun index expireIndex'tir
+func ExpireFilter(olderThan time.Duration) bson.M {
+	return bson.M{"status": "PENDING", "createdAt": bson.M{"$lt": time.Now().Add(-olderThan)}}
+}
+
+// expireUpdate - Eşleşen siparişi süresi dolmuş olarak işaretler
+func expireUpdate() bson.M {
+	return bson.M{"$set": bson.M{"status": "EXPIRED", "expiredAt": time.Now()}}
+}
+
+// expireIndex - ExpireFilter'ın index'i (önce eşitlik, sonra aralık)
+var expireIndex = mongo.IndexModel{
+	Keys:    bson.D{{Key: "status", Value: 1}, {Key: "createdAt", Value: 1}},
+	Options: options.Index().SetName("status_1_createdAt_1"),
+}
+
+// CopyOrders - Güncelleme serisinin turlarını hazırlayan reset: dst'yi silip src'nin ilk n dokümanını
+// $out ile kopyalar ve indexes'i oluşturur (index bakım maliyeti ölçülen güncellemeye dahil olur)
+func CopyOrders(src, dst *mongo.Collection, n int64, indexes []mongo.IndexModel) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		if err := dst.Drop(ctx); err != nil {
+			return err
+		}
+		cursor, err := src.Aggregate(ctx, mongo.Pipeline{
+			{{Key: "$limit", Value: n}},
+			{{Key: "$out", Value: dst.Name()}},
+		})
+		if err != nil {
+			return err
+		}
+		cursor.Close(ctx)
+		if len(indexes) == 0 {
+			return nil
+		}
+		_, err = dst.Indexes().CreateMany(ctx, indexes)
+		return err
+	}
+}
+
+// ExplainUpdate - update komutunu executionStats ile explain eder (dokümanlar değiştirilmez)
+// Sonuç ExplainQuery ile aynı biçimdedir: PrintExplainResults ve SummarizeExplain doğrudan kullanılır,
+// kazanan planın kökünde UPDATE stage'i vardır (ör: UPDATE → FETCH → IXSCAN)
+func ExplainUpdate(col *mongo.Collection, filter bson.M, update interface{}, multi bool) (map[string]interface{}, error) {
+	var result map[string]interface{}
+	err := col.Database().RunCommand(context.Background(), bson.D{
+		{Key: "explain", Value: bson.D{
+			{Key: "update", Value: col.Name()},
+			{Key: "updates", Value: bson.A{bson.D{{Key: "q", Value: filter}, {Key: "u", Value: update}, {Key: "multi", Value: multi}}}},
+		}},
+		{Key: "verbosity", Value: "executionStats"},
+	}).Decode(&result)
+	return result, err
+}
+
+// UpdateExecutionStats - Update explain'inin istatistikleri; update hiç doküman döndürmediği için
+// NReturned yerine UPDATE stage'inin eşleştirdiği doküman sayısı (nMatched) yazılır
+func UpdateExecutionStats(explain map[string]interface{}) *ExecutionStats {
+	execStats, ok := explain["executionStats"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	stats := &ExecutionStats{
+		ExecutionTimeMillis: toInt64(execStats["executionTimeMillis"]),
+		TotalDocsExamined:   toInt64(execStats["totalDocsExamined"]),
+		TotalKeysExamined:   toInt64(execStats["totalKeysExamined"]),
+	}
+	if stages, ok := execStats["executionStages"].(map[string]interface{}); ok {
+		stats.NReturned = toInt64(stages["nMatched"])
+	}
+	return stats
+}
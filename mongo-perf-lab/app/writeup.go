@@ -0,0 +1,317 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// writeup.go - Çalıştırma sonucundan deney raporu (Markdown)
+// Repodaki karşılaştırmalar (read_v1..v5 sonuç dosyaları) hep aynı soruyu cevaplıyor: "Neyi
+// denedik, hangi koşullarda, ne çıktı, neden?" Rapor bu iskeleti summary.json'dan doldurur:
+// hipotez, kurulum, versiyon başına sonuç tablosu (tekrar ortalaması, sapma, baseline'a göre
+// hız), analyzer bulguları, assertion'lar ve maliyet. Yorum gerektiren kısımlar (neden, sonuç)
+// "_TODO_" olarak bırakılır - rakamlar otomatik, yorum insanın işi.
+
+// highVariation - Tekrarlar arası değişim katsayısı bu orandan büyükse sonuç gürültülü sayılır
+const highVariation = 0.10
+
+// benchmarkStats - Bir benchmark'ın (varyant dahil) tekrarlarının özeti
+type benchmarkStats struct {
+	Name       string
+	Reps       int
+	MeanMs     float64
+	StdDevMs   float64
+	MinMs      float64
+	MaxMs      float64
+	Records    float64 // Ortalama
+	MemoryMB   float64
+	Examined   float64
+	Efficiency float64
+	Targeting  float64
+	Errors     int
+	Findings   []string
+}
+
+// CV - Değişim katsayısı (stddev / ortalama)
+func (b benchmarkStats) CV() float64 {
+	if b.MeanMs == 0 {
+		return 0
+	}
+	return b.StdDevMs / b.MeanMs
+}
+
+// summarizeBenchmarks - Kayıtları ad bazında (ilk görülme sırasıyla) toplar
+func summarizeBenchmarks(records []MetricsRecord) []benchmarkStats {
+	var order []string
+	byName := map[string][]MetricsRecord{}
+	for _, r := range records {
+		if _, ok := byName[r.Name()]; !ok {
+			order = append(order, r.Name())
+		}
+		byName[r.Name()] = append(byName[r.Name()], r)
+	}
+
+	stats := make([]benchmarkStats, 0, len(order))
+	for _, name := range order {
+		rs := byName[name]
+		n := float64(len(rs))
+		s := benchmarkStats{Name: name, Reps: len(rs), MinMs: math.Inf(1)}
+		for _, r := range rs {
+			s.MeanMs += r.DurationMs / n
+			s.MinMs = min(s.MinMs, r.DurationMs)
+			s.MaxMs = max(s.MaxMs, r.DurationMs)
+			s.Records += float64(r.RecordsRead) / n
+			s.MemoryMB += r.MemoryMB / n
+			s.Examined += float64(r.DocsExamined) / n
+			s.Efficiency += r.Efficiency / n
+			s.Targeting += r.Targeting / n
+			s.Errors += r.Errors
+			for _, code := range r.Findings {
+				if !slices.Contains(s.Findings, code) {
+					s.Findings = append(s.Findings, code)
+				}
+			}
+		}
+		if len(rs) > 1 {
+			var sq float64
+			for _, r := range rs {
+				sq += (r.DurationMs - s.MeanMs) * (r.DurationMs - s.MeanMs)
+			}
+			s.StdDevMs = math.Sqrt(sq / (n - 1))
+		}
+		sort.Strings(s.Findings)
+		stats = append(stats, s)
+	}
+	return stats
+}
+
+// WriteExperimentWriteup - Raporu w'ye yazar
+// baseline: Hız oranlarının referansı (boşsa ilk benchmark); runDir: Ek dosyaların listesi için
+func WriteExperimentWriteup(w io.Writer, s *RunSummary, runDir, baseline string) error {
+	m := s.Manifest
+	stats := summarizeBenchmarks(s.Records)
+	if baseline == "" && len(stats) > 0 {
+		baseline = stats[0].Name
+	}
+	var base *benchmarkStats
+	for i := range stats {
+		if stats[i].Name == baseline {
+			base = &stats[i]
+		}
+	}
+	if base == nil && len(stats) > 0 {
+		return fmt.Errorf("baseline %q kayıtlarda yok", baseline)
+	}
+
+	p := func(format string, args ...any) { fmt.Fprintf(w, format, args...) }
+
+	p("# %s - Deney Raporu\n\n", m.Name)
+	p("| | |\n|---|---|\n")
+	p("| Tarih | %s |\n", s.StartedAt.Format("2006-01-02 15:04"))
+	p("| Süre | %v |\n", s.FinishedAt.Sub(s.StartedAt).Round(time.Second))
+	p("| Ortam | %s |\n", s.Environment)
+	p("| Makine | %s |\n", s.Host)
+	if c := s.Coordination; c != nil {
+		p("| Koordinasyon | %s: %d client (bu rapor: %s) |\n", c.ID, c.Clients, c.Client)
+	}
+	if pv := s.Provenance; pv != nil {
+		p("| Manifest | `%s` |\n", shortHash(pv.ManifestSHA256))
+		if pv.SourceRevision != "" {
+			p("| Kaynak | `%s` |\n", pv.SourceRevision)
+		}
+		if pv.DatasetChecksum != "" {
+			p("| Veri seti | `%s` (%d doküman) |\n", pv.DatasetChecksum, pv.DatasetCount)
+		}
+		p("| Sürümler | %s, mongo-go-driver %s |\n", pv.GoVersion, pv.DriverVersion)
+	}
+
+	p("\n## Hipotez\n\n")
+	if m.Hypothesis != "" {
+		p("%s\n\n", strings.TrimSpace(m.Hypothesis))
+	} else {
+		p("_TODO: Ne bekleniyordu? (manifest'e `hypothesis:` eklenirse buraya yazılır)_\n\n")
+	}
+	if m.Description != "" {
+		p("> %s\n", m.Description)
+	}
+
+	p("\n## Kurulum\n\n")
+	if d := m.Dataset; d.Documents > 0 {
+		p("- **Veri seti:** %d doküman, seed %d, şekil %s", d.Documents, d.Seed, valueOrDefault(d.Shape.Mode, "uniform"))
+		if d.DateFormat != "" {
+			p(", createdAt %s", d.DateFormat)
+		}
+		p("\n")
+	} else {
+		p("- **Veri seti:** Mevcut koleksiyon (generator çalıştırılmadı)\n")
+	}
+	if len(m.Indexes) > 0 {
+		var idx []string
+		for _, i := range m.Indexes {
+			idx = append(idx, fmt.Sprintf("`%s` (%s)", valueOrDefault(i.Name, "-"), strings.Join(i.Keys, ", ")))
+		}
+		p("- **Index'ler:** %s\n", strings.Join(idx, ", "))
+	}
+	if m.Ingest != nil {
+		p("- **Arka plan yazma yükü:** %.0f doküman/sn (batch %d)\n", m.Ingest.Rate, m.Ingest.BatchSize)
+	}
+	p("- **Benchmark'lar:**\n")
+	for _, b := range m.Benchmarks {
+		p("  - `%s` × %d", b.Name, b.Repetitions)
+		if len(b.Args) > 0 {
+			p(" `%s`", strings.Join(b.Args, " "))
+		}
+		if b.Prewarm > 0 {
+			p(" (havuz ısıtma: %d bağlantı)", b.Prewarm)
+		}
+		p("\n")
+	}
+
+	p("\n## Sonuçlar\n\n")
+	if len(stats) == 0 {
+		p("_Metrik kaydı yok._\n")
+	} else {
+		p("Tekrar ortalaması; hız, `%s`'e göre (>1 daha hızlı).\n\n", baseline)
+		p("| benchmark | tekrar | süre ms (ort ± sapma) | min–max ms | hız | kayıt | bellek MB | incelenen | verim %% | hata |\n")
+		p("|---|---:|---:|---:|---:|---:|---:|---:|---:|---:|\n")
+		for _, b := range stats {
+			speed := "-"
+			if b.MeanMs > 0 {
+				speed = fmt.Sprintf("%.2fx", base.MeanMs/b.MeanMs)
+			}
+			noisy := ""
+			if b.CV() > highVariation {
+				noisy = " ⚠️"
+			}
+			p("| `%s` | %d | %.1f ± %.1f%s | %.1f–%.1f | %s | %.0f | %.2f | %.0f | %.2f | %d |\n",
+				b.Name, b.Reps, b.MeanMs, b.StdDevMs, noisy, b.MinMs, b.MaxMs, speed, b.Records, b.MemoryMB, b.Examined, b.Efficiency, b.Errors)
+		}
+		for _, b := range stats {
+			if b.CV() > highVariation {
+				p("\n⚠️ Tekrarlar arası değişimi %%%.0f'dan büyük olan sonuçlar gürültülüdür; tekrar sayısını artırmayı düşünün.\n", highVariation*100)
+				break
+			}
+		}
+	}
+
+	p("\n## Analyzer Bulguları\n\n")
+	findings := 0
+	for _, b := range stats {
+		if len(b.Findings) == 0 {
+			continue
+		}
+		findings++
+		p("- **`%s`**", b.Name)
+		if b.Targeting > 0 {
+			p(" (incelenen/dönen: %.1f)", b.Targeting)
+		}
+		p("\n")
+		for _, code := range b.Findings {
+			p("  - %s\n", describeFinding(code))
+		}
+	}
+	if findings == 0 {
+		p("Anti-pattern bulgusu yok.\n")
+	}
+
+	if len(s.Assertions) > 0 {
+		p("\n## Assertion'lar\n\n| benchmark | metrik | değer | sınır | sonuç |\n|---|---|---:|---|---|\n")
+		for _, a := range s.Assertions {
+			var bounds []string
+			if a.Min != nil {
+				bounds = append(bounds, fmt.Sprintf("≥ %g", *a.Min))
+			}
+			if a.Max != nil {
+				bounds = append(bounds, fmt.Sprintf("≤ %g", *a.Max))
+			}
+			result := "✅"
+			if !a.Passed {
+				result = "❌ " + a.Reason
+			}
+			p("| `%s` | %s | %.2f | %s | %s |\n", a.Name(), a.Metric, a.Value, strings.Join(bounds, ", "), result)
+		}
+	}
+
+	if len(s.Costs) > 0 {
+		p("\n## Maliyet Tahmini (%s)\n\n| benchmark | $/1M işlem | Wh/1M işlem |\n|---|---:|---:|\n", s.Costs[0].Model)
+		for _, c := range s.Costs {
+			p("| `%s` | %.4f | %.4f |\n", c.Benchmark, c.PerMillionOpsUSD, c.EnergyPerMillionOpsWh)
+		}
+	}
+
+	p("\n## Sonuç\n\n")
+	if len(stats) > 1 {
+		fastest := stats[0]
+		for _, b := range stats[1:] {
+			if b.MeanMs > 0 && b.MeanMs < fastest.MeanMs {
+				fastest = b
+			}
+		}
+		p("- En hızlı: `%s` (%.1f ms, `%s`'e göre %.2fx)\n", fastest.Name, fastest.MeanMs, baseline, base.MeanMs/fastest.MeanMs)
+	}
+	if len(s.Assertions) > 0 {
+		passed := 0
+		for _, a := range s.Assertions {
+			if a.Passed {
+				passed++
+			}
+		}
+		p("- Assertion'lar: %d/%d geçti\n", passed, len(s.Assertions))
+	}
+	p("- _TODO: Hipotez doğrulandı mı? Farkın nedeni (plan, veri transferi, decode)?_\n")
+	p("- _TODO: Öneri / sonraki adım_\n")
+
+	if runDir != "" {
+		if files, _ := GlobArtifacts(filepath.Join(runDir, "*_rep*.txt")); len(files) > 0 {
+			p("\n## Ekler\n\n")
+			for _, f := range files {
+				p("- [%s](%s)\n", filepath.Base(f), filepath.Base(f))
+			}
+		}
+	}
+	return nil
+}
+
+// describeFinding - Bulgu kodunun başlığı ve dokümantasyon linki (antipattern.go kuralları)
+func describeFinding(code string) string {
+	for _, rule := range antiPatternRules {
+		if rule.code == code {
+			return fmt.Sprintf("[%s] %s - %s ([doküman](%s))", code, rule.severity, rule.title, rule.docURL)
+		}
+	}
+	return code
+}
+
+func shortHash(h string) string {
+	if len(h) > 12 {
+		return h[:12]
+	}
+	return h
+}
+
+func valueOrDefault(v, def string) string {
+	if v == "" {
+		return def
+	}
+	return v
+}
+
+// WriteExperimentWriteupFile - Raporu path'e yazar
+func WriteExperimentWriteupFile(path string, s *RunSummary, runDir, baseline string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := WriteExperimentWriteup(f, s, runDir, baseline); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,27 @@
+#!/bin/sh
+# replset.sh - 3 üyeli replica set (rs0), tek container'da
+# Üyeler localhost:27021-27023'te dinler ve replica set config'ine de bu adreslerle yazılır:
+# Portlar host'a aynı numarayla açıldığı için driver üyeleri hem container içinden hem
+# host'tan aynı adreslerle bulur (ayrı container'larda /etc/hosts ayarı gerekirdi).
+# Her üye tek düğümlü mongo servisiyle aynı cache boyutunu kullanır (mongod.conf).
+set -e
+
+for port in 27021 27022 27023; do
+  mkdir -p /data/rs/$port
+  mongod --replSet rs0 --port $port --bind_ip_all --dbpath /data/rs/$port \
+    --wiredTigerCacheSizeGB 1 --fork --logpath /data/rs/$port.log
+done
+
+# Container yeniden başlarsa config zaten vardır
+mongosh --port 27021 --quiet --eval '
+try {
+  rs.status()
+} catch (e) {
+  rs.initiate({_id: "rs0", members: [
+    {_id: 0, host: "localhost:27021"},
+    {_id: 1, host: "localhost:27022"},
+    {_id: 2, host: "localhost:27023"}
+  ]})
+}'
+
+exec tail -F /data/rs/27021.log
@@ -0,0 +1,45 @@
+#!/bin/sh
+# sharded.sh - 2 shard'lı küme, tek container'da
+# config server (cfg, 27039), iki shard (sh1 27041, sh2 27042 - varsayılan olarak tek üyeli replica set)
+# ve mongos (27030). Client'lar yalnızca mongos'a bağlanır; portu host'a açılan tek süreç odur.
+# Her shard tek düğümlü mongo servisiyle aynı cache boyutunu kullanır (mongod.conf).
+#
+# SHARD_MEMBERS=2 ile her shard iki üyeli olur (ikinci üyeler 27051, 27052): Hedged read
+# okumayı shard'ın ikinci üyesine de gönderebilsin diye (bkz. read_point.go -hedge). Shard başına
+# toplam cache aynı kalır, üye başına yarıya iner.
+set -e
+
+SHARD_MEMBERS=${SHARD_MEMBERS:-1}
+
+# start <rol parametresi> <replica set> <port> [üye sayısı]
+# Üye i'nin portu port + 10*i'dir
+start() {
+  members=${4:-1}
+  cache=1
+  [ "$members" -gt 1 ] && cache=0.5
+  hosts=""
+  i=0
+  while [ $i -lt $members ]; do
+    port=$(($3 + 10 * i))
+    dir=/data/$2
+    [ $i -gt 0 ] && dir=/data/$2_$i
+    mkdir -p $dir
+    mongod $1 --replSet $2 --port $port --bind_ip_all --dbpath $dir \
+      --wiredTigerCacheSizeGB $cache --fork --logpath $dir.log
+    hosts="$hosts{_id: $i, host: 'localhost:$port'},"
+    i=$((i + 1))
+  done
+  mongosh --port $3 --quiet --eval "try { rs.status() } catch (e) { rs.initiate({_id: '$2', members: [$hosts]}) }"
+  until mongosh --port $3 --quiet --eval 'quit(db.hello().isWritablePrimary ? 0 : 1)'; do sleep 1; done
+}
+
+start --configsvr cfg 27039
+start --shardsvr sh1 27041 $SHARD_MEMBERS
+start --shardsvr sh2 27042 $SHARD_MEMBERS
+
+mongos --configdb cfg/localhost:27039 --port 27030 --bind_ip_all --fork --logpath /data/mongos.log
+mongosh --port 27030 --quiet --eval '
+sh.addShard("sh1/localhost:27041");
+sh.addShard("sh2/localhost:27042");'
+
+exec tail -F /data/mongos.log
@@ -0,0 +1,11 @@
+module scenario
+
+go 1.22
+
+require (
+	benchkit v0.0.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+// Tüm lab'ların ortak ölçüm kütüphanesi (repo kökündeki benchkit modülü)
+replace benchkit => ../benchkit
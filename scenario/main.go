@@ -0,0 +1,164 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"benchkit"
+)
+
+// scenario - Lab'lar arası çok adımlı deneylerin YAML ile tanımlanması
+// "1M doküman üret, index oluştur, server-go'yu başlat, 5 dakika yük bas, sonuçları topla"
+// gibi deneyler elle sırayla çalıştırıldığında adımlar atlanıyor, süreler kayboluyor ve
+// yarım kalan deneyin arka planda sunucusu açık kalıyordu. Senaryo dosyası (bkz. spec.go)
+// adımları, sürelerini ve hata politikasını tanımlar; runner her adımı zamanlar, çıktısını
+// RUN_DIR/NN-adım.log'a yazar ve sonunda RUN_DIR/summary.json'ı üretir.
+//
+// Adımlar RUN_DIR ortam değişkenini görür: Lab'ların -json çıktıları oraya yazılırsa bir
+// çalıştırmanın tüm sonuçları tek klasörde toplanır (aggregate ile birleştirilebilir).
+//
+// KULLANIM (scenario klasöründe):
+//
+//	go run . run -f examples/index-and-load.yaml
+//	go run . run -f examples/index-and-load.yaml -dry-run
+//	go run . run -f deney.yaml -out /tmp/runs
+//
+// Çıkış kodu: 0 başarılı, 1 adım başarısız, 2 kullanım/senaryo hatası, 130 Ctrl-C
+func main() {
+	if len(os.Args) < 2 || os.Args[1] != "run" {
+		fmt.Println("Kullanım: scenario run -f senaryo.yaml [-out runs/scenarios] [-dry-run]")
+		os.Exit(2)
+	}
+	fs := flag.NewFlagSet("run", flag.ExitOnError)
+	file := fs.String("f", "", "Senaryo dosyası (YAML)")
+	out := fs.String("out", "runs/scenarios", "Çalıştırma klasörlerinin oluşturulacağı dizin")
+	dryRun := fs.Bool("dry-run", false, "Adımları yalnızca listeler, çalıştırmaz")
+	fs.Parse(os.Args[2:])
+	if *file == "" {
+		fmt.Println("❌ -f gerekli")
+		os.Exit(2)
+	}
+
+	s, err := LoadScenario(*file)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(2)
+	}
+	if *dryRun {
+		printPlan(s)
+		return
+	}
+
+	started := time.Now()
+	runDir, err := filepath.Abs(filepath.Join(*out, s.Name+"-"+started.Format("20060102-150405")))
+	if err == nil {
+		err = os.MkdirAll(runDir, 0o755)
+	}
+	if err != nil {
+		fmt.Printf("❌ Çalıştırma klasörü: %v\n", err)
+		os.Exit(2)
+	}
+
+	host := benchkit.CollectHostInfo()
+	fmt.Printf("🖥️  %s\n", host)
+	fmt.Printf("🎬 %s: %d adım, RUN_DIR=%s\n", s.Name, len(s.Steps), runDir)
+
+	// Ctrl-C çalışan adımı keser; always adımları da atlanır, arka plan adımları kapatılır
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	r := &Runner{scenario: s, runDir: runDir, background: map[string]*process{}}
+	runErr := r.Run(ctx)
+	finished := time.Now()
+
+	printSummary(s, r.results, finished.Sub(started))
+	summary := map[string]any{
+		"scenario":   s,
+		"file":       *file,
+		"startedAt":  started.UTC(),
+		"finishedAt": finished.UTC(),
+		"success":    !r.Failed(),
+		"host":       host,
+		"steps":      r.results,
+	}
+	if err := writeJSON(filepath.Join(runDir, "summary.json"), summary); err != nil {
+		fmt.Printf("⚠️  summary.json yazılamadı: %v\n", err)
+	}
+	fmt.Printf("📁 %s\n", runDir)
+
+	switch {
+	case ctx.Err() != nil:
+		fmt.Println("⛔ Kesildi")
+		os.Exit(130)
+	case runErr != nil:
+		fmt.Printf("❌ Başarısız: %v\n", runErr)
+		os.Exit(1)
+	}
+	fmt.Println("✅ Tamamlandı")
+}
+
+// printPlan - -dry-run: Adımlar ve çözülmüş çalışma dizinleri
+func printPlan(s *Scenario) {
+	fmt.Printf("🎬 %s (%d adım, kök %s)\n", s.Name, len(s.Steps), s.rootDir())
+	if s.Description != "" {
+		fmt.Printf("   %s\n", s.Description)
+	}
+	for i, st := range s.Steps {
+		if st.Stop != "" {
+			fmt.Printf("\n%2d. %s: %s'i kapat\n", i+1, st.Name, st.Stop)
+			continue
+		}
+		var flags []string
+		if st.Background {
+			flags = append(flags, "arka plan")
+		}
+		if st.Ready != "" {
+			flags = append(flags, "hazır: "+st.Ready)
+		}
+		if st.Timeout > 0 {
+			flags = append(flags, "zaman aşımı "+time.Duration(st.Timeout).String())
+		}
+		if st.Retries > 0 {
+			flags = append(flags, fmt.Sprintf("%d tekrar", st.Retries))
+		}
+		if st.ContinueOnError {
+			flags = append(flags, "hatada devam")
+		}
+		if st.Always {
+			flags = append(flags, "her zaman")
+		}
+		fmt.Printf("\n%2d. %s", i+1, st.Name)
+		if len(flags) > 0 {
+			fmt.Printf(" (%s)", strings.Join(flags, ", "))
+		}
+		fmt.Printf("\n    📂 %s\n    $ %s\n", s.stepDir(st), st.Run)
+	}
+}
+
+func printSummary(s *Scenario, results []StepResult, total time.Duration) {
+	fmt.Printf("\n=== ÖZET: %s (%v) ===\n", s.Name, total.Round(time.Second))
+	fmt.Printf("  %3s %-24s %-14s %12s %7s\n", "#", "adım", "durum", "süre", "deneme")
+	for i, res := range results {
+		duration := "-"
+		if !res.Started.IsZero() {
+			duration = res.Duration.Round(time.Millisecond).String()
+		}
+		fmt.Printf("  %3d %-24s %-14s %12s %7d\n", i+1, res.Name, res.Status, duration, res.Attempts)
+	}
+}
+
+func writeJSON(path string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
@@ -0,0 +1,21 @@
+//go:build !unix
+
+package main
+
+import "os/exec"
+
+// shellCommand - Komutu cmd /C ile çalıştırır
+// Process grubu yoktur: Komut alt process başlatıyorsa (ör: go run) kapanışta geride kalabilir
+func shellCommand(command string) *exec.Cmd {
+	return exec.Command("cmd", "/C", command)
+}
+
+// terminate - Bu platformda sinyal yok, process öldürülür
+func terminate(cmd *exec.Cmd) {
+	cmd.Process.Kill()
+}
+
+// kill - Process'i öldürür
+func kill(cmd *exec.Cmd) {
+	cmd.Process.Kill()
+}
@@ -0,0 +1,27 @@
+//go:build unix
+
+package main
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// shellCommand - Komutu sh -c ile kendi process grubunda çalıştırır
+// Grup, komutun başlattığı alt process'lerin de (go run'ın derlediği program gibi) birlikte
+// kapatılmasını sağlar
+func shellCommand(command string) *exec.Cmd {
+	cmd := exec.Command("sh", "-c", command)
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	return cmd
+}
+
+// terminate - Gruba SIGTERM
+func terminate(cmd *exec.Cmd) {
+	syscall.Kill(-cmd.Process.Pid, syscall.SIGTERM)
+}
+
+// kill - Gruba SIGKILL
+func kill(cmd *exec.Cmd) {
+	syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+}
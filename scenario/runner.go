@@ -0,0 +1,364 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// runner.go - Adımların sırayla çalıştırılması
+// Hata politikası: Başarısız adım (çıkış kodu ≠ 0, zaman aşımı, hazır olmama) retries kadar
+// tekrarlanır. Yine başarısızsa continueOnError verilmemişse senaryo durur; kalan adımlar
+// atlanır, yalnızca always: true olanlar (rapor toplama, temizlik) yine çalışır. Arka plan
+// adımları stop ile veya senaryo sonunda (hata ve Ctrl-C dahil) kapatılır.
+
+// Adım durumları
+const (
+	statusOK          = "ok"
+	statusFailed      = "hata"
+	statusFailedCont  = "hata (devam)" // continueOnError
+	statusSkipped     = "atlandı"
+	statusInterrupted = "kesildi"
+	statusStopped     = "durduruldu" // stop adımıyla kapatılan arka plan adımı
+)
+
+// StepResult - Adımın sonucu (summary.json)
+type StepResult struct {
+	Name     string        `json:"name"`
+	Status   string        `json:"status"`
+	Started  time.Time     `json:"started,omitempty"`
+	Duration time.Duration `json:"durationNs"` // Arka plan adımında hazır olana kadar geçen süre
+	Attempts int           `json:"attempts,omitempty"`
+	Error    string        `json:"error,omitempty"`
+	Log      string        `json:"log,omitempty"` // runDir'e göre
+}
+
+// Runner - Tek senaryo çalıştırması
+type Runner struct {
+	scenario   *Scenario
+	runDir     string
+	background map[string]*process
+	results    []StepResult
+}
+
+// process - Arka planda çalışan adım
+type process struct {
+	cmd  *exec.Cmd
+	exit chan error
+	log  *os.File
+}
+
+// stop - Process grubunu sonlandırır; 5 saniyede çıkmazsa öldürür
+func (p *process) stop() {
+	select {
+	case <-p.exit:
+	default:
+		terminate(p.cmd)
+		select {
+		case <-p.exit:
+		case <-time.After(5 * time.Second):
+			kill(p.cmd)
+			<-p.exit
+		}
+	}
+	p.log.Close()
+}
+
+// Run - Tüm adımları çalıştırır; ctx iptal edilirse (Ctrl-C) çalışan adım kesilir ve kalanlar
+// atlanır. Dönen hata ilk başarısız adımınkidir
+func (r *Runner) Run(ctx context.Context) error {
+	defer r.stopAll()
+	var firstErr error
+	for i, st := range r.scenario.Steps {
+		result := StepResult{Name: st.Name}
+		switch {
+		case ctx.Err() != nil:
+			result.Status = statusInterrupted
+		case firstErr != nil && !st.Always:
+			result.Status = statusSkipped
+		default:
+			fmt.Printf("\n▶️  [%d/%d] %s\n", i+1, len(r.scenario.Steps), st.Name)
+			result = r.runStep(ctx, i, st)
+			icon := "✅"
+			if result.Status != statusOK && result.Status != statusStopped {
+				icon = "❌"
+			}
+			fmt.Printf("   %s %s (%v)", icon, result.Status, result.Duration.Round(time.Millisecond))
+			if result.Error != "" {
+				fmt.Printf(": %s", result.Error)
+			}
+			fmt.Println()
+			if result.Status == statusFailed && firstErr == nil {
+				firstErr = fmt.Errorf("%s: %s", st.Name, result.Error)
+			}
+		}
+		r.results = append(r.results, result)
+	}
+	if firstErr == nil && ctx.Err() != nil {
+		firstErr = ctx.Err()
+	}
+	return firstErr
+}
+
+// runStep - Adımı (gerekirse tekrar ederek) çalıştırır
+func (r *Runner) runStep(ctx context.Context, index int, st Step) StepResult {
+	result := StepResult{Name: st.Name, Started: time.Now()}
+	if st.Stop != "" {
+		if p, ok := r.background[st.Stop]; ok {
+			fmt.Printf("   ⏹️  %s kapatılıyor\n", st.Stop)
+			p.stop()
+			delete(r.background, st.Stop)
+		}
+		result.Status = statusStopped
+		result.Duration = time.Since(result.Started)
+		return result
+	}
+
+	logName := fmt.Sprintf("%02d-%s.log", index+1, st.Name)
+	result.Log = logName
+	// Ölen arka plan process'i (ör: sunucu çöktü) bu adımın sonucunu anlamsız kılar; adım
+	// çalıştırılmadan başarısız sayılır
+	err := r.checkBackground()
+	for attempt := 1; err == nil; attempt++ {
+		result.Attempts = attempt
+		if st.Background {
+			err = r.startBackground(ctx, st, logName)
+		} else {
+			err = r.runForeground(ctx, st, logName)
+		}
+		if err == nil || ctx.Err() != nil || attempt > st.Retries {
+			break
+		}
+		fmt.Printf("   🔁 Deneme %d/%d (%v)\n", attempt+1, st.Retries+1, err)
+		err = nil
+	}
+	result.Duration = time.Since(result.Started)
+	switch {
+	case err == nil:
+		result.Status = statusOK
+	case ctx.Err() != nil:
+		result.Status = statusInterrupted
+		result.Error = "kullanıcı tarafından kesildi"
+	case st.ContinueOnError:
+		result.Status = statusFailedCont
+		result.Error = err.Error()
+	default:
+		result.Status = statusFailed
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// command - Adımın komutu: Senaryo ve adım ortam değişkenleri ile RUN_DIR, SCENARIO_ROOT, SCENARIO_STEP
+func (r *Runner) command(st Step) *exec.Cmd {
+	cmd := shellCommand(st.Run)
+	cmd.Dir = r.scenario.stepDir(st)
+	cmd.Env = os.Environ()
+	for k, v := range r.scenario.Env {
+		cmd.Env = append(cmd.Env, k+"="+v)
+	}
+	for k, v := range st.Env {
+		cmd.Env = append(cmd.Env, k+"="+v)
+	}
+	root, _ := filepath.Abs(r.scenario.rootDir())
+	cmd.Env = append(cmd.Env, "RUN_DIR="+r.runDir, "SCENARIO_ROOT="+root, "SCENARIO_STEP="+st.Name)
+	return cmd
+}
+
+// openLog - Adımın log dosyası (tekrarlarda sona eklenir)
+func (r *Runner) openLog(name string, st Step) (*os.File, error) {
+	f, err := os.OpenFile(filepath.Join(r.runDir, name), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Fprintf(f, "=== %s $ %s (%s)\n", time.Now().Format(time.RFC3339), st.Run, r.scenario.stepDir(st))
+	return f, nil
+}
+
+// runForeground - Komut bitene kadar bekler; çıktı hem log'a hem konsola (girintili) yazılır
+func (r *Runner) runForeground(ctx context.Context, st Step, logName string) error {
+	logFile, err := r.openLog(logName, st)
+	if err != nil {
+		return err
+	}
+	defer logFile.Close()
+	console := &prefixWriter{w: os.Stdout, prefix: "   │ "}
+	defer console.Flush()
+
+	cmd := r.command(st)
+	out := io.MultiWriter(logFile, console)
+	cmd.Stdout, cmd.Stderr = out, out
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	done := make(chan error, 1)
+	go func() { done <- cmd.Wait() }()
+
+	var timeout <-chan time.Time
+	if st.Timeout > 0 {
+		timer := time.NewTimer(time.Duration(st.Timeout))
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	select {
+	case err := <-done:
+		return err
+	case <-timeout:
+		stopCommand(cmd, done)
+		return fmt.Errorf("%v içinde bitmedi", time.Duration(st.Timeout))
+	case <-ctx.Done():
+		stopCommand(cmd, done)
+		return ctx.Err()
+	}
+}
+
+// stopCommand - Çalışan ön plan komutunu sonlandırır ve çıkmasını bekler
+func stopCommand(cmd *exec.Cmd, done <-chan error) {
+	terminate(cmd)
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		kill(cmd)
+		<-done
+	}
+}
+
+// startBackground - Komutu başlatır ve (ready verildiyse) URL 200 dönene kadar bekler
+// Arka plan çıktısı yalnızca log'a yazılır: Sonraki adımların konsol çıktısıyla karışmasın
+func (r *Runner) startBackground(ctx context.Context, st Step, logName string) error {
+	logFile, err := r.openLog(logName, st)
+	if err != nil {
+		return err
+	}
+	cmd := r.command(st)
+	cmd.Stdout, cmd.Stderr = logFile, logFile
+	if err := cmd.Start(); err != nil {
+		logFile.Close()
+		return err
+	}
+	p := &process{cmd: cmd, exit: make(chan error, 1), log: logFile}
+	go func() { p.exit <- cmd.Wait() }()
+
+	readyTimeout := time.Duration(st.ReadyTimeout)
+	if readyTimeout <= 0 {
+		readyTimeout = defaultReadyTimeout
+	}
+	if err := waitReady(ctx, p, st.Ready, readyTimeout); err != nil {
+		p.stop()
+		return fmt.Errorf("%v (çıktı: %s)", err, logName)
+	}
+	r.background[st.Name] = p
+	return nil
+}
+
+// waitReady - url 200 dönene kadar bekler; url boşsa process'in 1 saniye ayakta kalması yeterlidir
+func waitReady(ctx context.Context, p *process, url string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	if url == "" {
+		deadline = time.Now().Add(time.Second)
+	}
+	client := &http.Client{Timeout: time.Second}
+	for {
+		select {
+		case err := <-p.exit:
+			p.exit <- err // stop() tekrar okuyabilsin
+			return fmt.Errorf("başlarken çıktı: %v", err)
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+		if url != "" {
+			if resp, err := client.Get(url); err == nil {
+				resp.Body.Close()
+				if resp.StatusCode == http.StatusOK {
+					return nil
+				}
+			}
+		}
+		if time.Now().After(deadline) {
+			if url == "" {
+				return nil
+			}
+			return fmt.Errorf("%v içinde hazır olmadı (%s)", timeout, url)
+		}
+		time.Sleep(200 * time.Millisecond)
+	}
+}
+
+// checkBackground - Arka plan adımlarından beklenmedik şekilde çıkan var mı
+// Çıkan adım listeden çıkarılır: Hata bir kez raporlanır, sonraki always adımları çalışabilir
+func (r *Runner) checkBackground() error {
+	for name, p := range r.background {
+		select {
+		case err := <-p.exit:
+			p.log.Close()
+			delete(r.background, name)
+			return fmt.Errorf("arka plan adımı %q beklenmedik şekilde çıktı: %v", name, err)
+		default:
+		}
+	}
+	return nil
+}
+
+// stopAll - Kalan arka plan adımlarını kapatır
+func (r *Runner) stopAll() {
+	for name, p := range r.background {
+		fmt.Printf("   ⏹️  %s kapatılıyor\n", name)
+		p.stop()
+		delete(r.background, name)
+	}
+}
+
+// Failed - Senaryo başarısız mı (continueOnError'lı hatalar sayılmaz)
+func (r *Runner) Failed() bool {
+	for _, res := range r.results {
+		if res.Status == statusFailed || res.Status == statusInterrupted {
+			return true
+		}
+	}
+	return false
+}
+
+// prefixWriter - Her satırın başına prefix ekler (adım çıktısı senaryo çıktısından ayrışsın)
+// Satır sonu gelmeyen kısım tamponda bekler; Flush kalanı yazar
+type prefixWriter struct {
+	mu     sync.Mutex
+	w      io.Writer
+	prefix string
+	buf    bytes.Buffer
+}
+
+func (p *prefixWriter) Write(b []byte) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.buf.Write(b)
+	for {
+		line, err := p.buf.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			p.buf.Write(line) // Tamamlanmamış satır geri konur
+			return len(b), nil
+		}
+		if _, err := io.WriteString(p.w, p.prefix+strings.TrimRight(string(line), "\r\n")+"\n"); err != nil {
+			return len(b), err
+		}
+	}
+}
+
+// Flush - Tampondaki tamamlanmamış satırı yazar
+func (p *prefixWriter) Flush() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.buf.Len() > 0 {
+		io.WriteString(p.w, p.prefix+p.buf.String()+"\n")
+		p.buf.Reset()
+	}
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// spec.go - Senaryo dosyası formatı
+// Bir senaryo, lab'lar arasında sırayla çalışan adımlardan oluşur. Her adım bir kabuk
+// komutudur; lab'ların kendi araçları (generator, perflab, server-go, loadgen-go,
+// orchestrator...) olduğu gibi çağrılır, senaryo yalnızca sıralamayı, süreleri ve
+// hataları yönetir. Örnek: examples/index-and-load.yaml
+//
+//	name: index-and-load
+//	root: ../..                 # Adımların dir'i buna göre (varsayılan: YAML'ın klasörü)
+//	env: {PERFLAB_ENV: local}
+//	steps:
+//	  - name: generate
+//	    dir: mongo-perf-lab/app
+//	    run: go run main.go ... generator.go -n 1000000 -drop
+//	    timeout: 30m
+//	  - name: server
+//	    dir: io-vs-cpu-demo/server-go
+//	    run: go run .
+//	    background: true
+//	    ready: http://localhost:4000/metrics
+//	  - name: loadgen
+//	    dir: io-vs-cpu-demo/loadgen-go
+//	    run: go run . -d 5m -json $RUN_DIR/loadgen.jsonl
+//	  - name: stop-server
+//	    stop: server
+//	  - name: report
+//	    run: ls -l $RUN_DIR
+//	    always: true
+
+// Scenario - Senaryo dosyasının tamamı
+type Scenario struct {
+	Name        string            `yaml:"name" json:"name"`
+	Description string            `yaml:"description" json:"description,omitempty"`
+	Root        string            `yaml:"root" json:"root,omitempty"` // Göreli dir'lerin kökü (YAML'ın klasörüne göre)
+	Env         map[string]string `yaml:"env" json:"env,omitempty"`   // Tüm adımlara eklenen ortam değişkenleri
+	Steps       []Step            `yaml:"steps" json:"steps"`
+
+	path string // Okunan dosya (göreli yollar buna göre çözülür)
+}
+
+// Step - Tek adım
+// run veya stop'tan biri verilir: run komut çalıştırır, stop önceki bir arka plan adımını kapatır
+type Step struct {
+	Name            string            `yaml:"name" json:"name"`
+	Dir             string            `yaml:"dir" json:"dir,omitempty"` // root'a göre (boş: root)
+	Run             string            `yaml:"run" json:"run,omitempty"` // sh -c ile çalıştırılır
+	Env             map[string]string `yaml:"env" json:"env,omitempty"`
+	Timeout         Duration          `yaml:"timeout" json:"timeout,omitempty"`       // 0: sınırsız (arka planda: hazır olma dahil değil)
+	Background      bool              `yaml:"background" json:"background,omitempty"` // Beklenmez; senaryo sonunda (veya stop ile) kapatılır
+	Ready           string            `yaml:"ready" json:"ready,omitempty"`           // Arka plan adımı bu URL 200 dönünce hazır sayılır
+	ReadyTimeout    Duration          `yaml:"readyTimeout" json:"readyTimeout,omitempty"`
+	Retries         int               `yaml:"retries" json:"retries,omitempty"`                 // Başarısızlıkta tekrar sayısı
+	ContinueOnError bool              `yaml:"continueOnError" json:"continueOnError,omitempty"` // Başarısız olsa da sonraki adımlara geçilir
+	Always          bool              `yaml:"always" json:"always,omitempty"`                   // Önceki bir adım başarısız olsa da çalışır (rapor toplama, temizlik)
+	Stop            string            `yaml:"stop" json:"stop,omitempty"`                       // Kapatılacak arka plan adımının adı
+}
+
+// Duration - YAML'da "30s", "5m" gibi yazılan süre
+type Duration time.Duration
+
+func (d *Duration) UnmarshalYAML(node *yaml.Node) error {
+	v, err := time.ParseDuration(node.Value)
+	if err != nil {
+		return fmt.Errorf("satır %d: süre bekleniyor (ör: 30s, 5m): %q", node.Line, node.Value)
+	}
+	*d = Duration(v)
+	return nil
+}
+
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+// defaultReadyTimeout - readyTimeout verilmeyen arka plan adımlarının hazır olma süresi
+// (go run ile derleme dahil)
+const defaultReadyTimeout = 2 * time.Minute
+
+// LoadScenario - Senaryo dosyasını okur ve doğrular; bilinmeyen alanlar hatadır (yazım hatası
+// sessizce yok sayılmasın)
+func LoadScenario(path string) (*Scenario, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var s Scenario
+	dec := yaml.NewDecoder(f)
+	dec.KnownFields(true)
+	if err := dec.Decode(&s); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	s.path = path
+	if err := s.validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &s, nil
+}
+
+func (s *Scenario) validate() error {
+	if s.Name == "" {
+		return fmt.Errorf("name gerekli")
+	}
+	if len(s.Steps) == 0 {
+		return fmt.Errorf("en az bir adım gerekli")
+	}
+	seen := map[string]bool{}
+	background := map[string]bool{}
+	for i, st := range s.Steps {
+		switch {
+		case st.Name == "":
+			return fmt.Errorf("adım %d: name gerekli", i+1)
+		case seen[st.Name]:
+			return fmt.Errorf("adım %q: ad tekrar ediyor", st.Name)
+		case (st.Run == "") == (st.Stop == ""):
+			return fmt.Errorf("adım %q: run veya stop'tan yalnızca biri verilmeli", st.Name)
+		case st.Stop != "" && !background[st.Stop]:
+			return fmt.Errorf("adım %q: stop: %q önceki bir arka plan adımı değil", st.Name, st.Stop)
+		case st.Ready != "" && !st.Background:
+			return fmt.Errorf("adım %q: ready yalnızca arka plan adımlarında kullanılır", st.Name)
+		case st.Retries < 0:
+			return fmt.Errorf("adım %q: retries negatif olamaz", st.Name)
+		}
+		seen[st.Name] = true
+		if st.Background {
+			background[st.Name] = true
+		}
+	}
+	return nil
+}
+
+// rootDir - Adımların göreli dir'lerinin kökü
+func (s *Scenario) rootDir() string {
+	base := filepath.Dir(s.path)
+	if s.Root == "" {
+		return base
+	}
+	if filepath.IsAbs(s.Root) {
+		return s.Root
+	}
+	return filepath.Join(base, s.Root)
+}
+
+// stepDir - Adımın çalışma dizini
+func (s *Scenario) stepDir(st Step) string {
+	if filepath.IsAbs(st.Dir) {
+		return st.Dir
+	}
+	return filepath.Join(s.rootDir(), st.Dir)
+}